@@ -34,6 +34,14 @@ const (
 	// ShardGroupDeletedExpiration is the amount of time before a shard group info will be removed from cached
 	// data after it has been marked deleted (2 weeks).
 	ShardGroupDeletedExpiration = -2 * 7 * 24 * time.Hour
+
+	// TokenLastUsedInterval is how often the last use of a token is recorded,
+	// so authenticating with a token doesn't change the metadata on every request.
+	TokenLastUsedInterval = time.Minute
+
+	// UserLastLoginInterval is how often the last login of a user is
+	// recorded, so authenticating doesn't change the metadata on every request.
+	UserLastLoginInterval = time.Hour
 )
 
 var (
@@ -61,6 +69,7 @@ type MetaClient interface {
 	DataNodeByHTTPHost(httpAddr string) (*NodeInfo, error)
 	DataNodeByTCPHost(tcpAddr string) (*NodeInfo, error)
 	DeleteDataNode(id uint64) error
+	SetDataNodeTags(id uint64, tags map[string]string) error
 
 	MetaNodes() ([]NodeInfo, error)
 	MetaNodeByAddr(addr string) *NodeInfo
@@ -78,6 +87,7 @@ type MetaClient interface {
 	DropRetentionPolicy(database, name string) error
 	SetDefaultRetentionPolicy(database, name string) error
 	UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error
+	UpdateDatabase(name string, du *DatabaseUpdate) error
 
 	Users() []UserInfo
 	UserCount() int
@@ -88,10 +98,31 @@ type MetaClient interface {
 
 	SetPrivilege(username, database string, p cnosql.Privilege) error
 	SetAdminPrivilege(username string, admin bool) error
+	SetUserLocked(username string, locked bool) error
+	UpdateUserQueryLimits(username string, u *UserQueryLimitsUpdate) error
+	SetUserDefaultDatabase(username, database, rp string) error
+	SetMeasurementPrivilege(username string, mp MeasurementPrivilege) error
+	SetPrivilegeOnRP(username, database, rp string, p cnosql.Privilege) error
 	UserPrivileges(username string) (map[string]cnosql.Privilege, error)
+	UserMeasurementPrivileges(username string) ([]MeasurementPrivilege, error)
+	UserPrivilegeOnRP(username, database, rp string) (*cnosql.Privilege, error)
 	UserPrivilege(username, database string) (*cnosql.Privilege, error)
 	AdminUserExists() bool
 	Authenticate(username, password string) (User, error)
+	AuthenticateExternal(username string, admin *bool, create bool) (User, error)
+
+	Roles() []RoleInfo
+	CreateRole(name string) error
+	DropRole(name string) error
+	SetRolePrivilege(role, database string, p cnosql.Privilege) error
+	RolePrivileges(role string) (map[string]cnosql.Privilege, error)
+	GrantRole(username, role string) error
+	RevokeRole(username, role string) error
+
+	Tokens() []TokenInfo
+	CreateToken(username, database string, p cnosql.Privilege, expiry time.Time) (string, *TokenInfo, error)
+	DropToken(id string) error
+	AuthenticateToken(token string) (User, error)
 
 	ShardIDs() []uint64
 	ShardGroupsByTimeRange(database, rp string, min, max time.Time) (a []ShardGroupInfo, err error)
@@ -101,13 +132,16 @@ type MetaClient interface {
 	PruneShardGroups() error
 	CreateShardGroup(database, rp string, timestamp time.Time) (*ShardGroupInfo, error)
 	DeleteShardGroup(database, rp string, id uint64) error
+	PurgeShardGroup(database, rp string, id uint64) error
+	UndeleteShardGroup(database, rp string, id uint64) error
 	PrecreateShardGroups(from, to time.Time) error
 	ShardOwner(shardID uint64) (database, rp string, sgi *ShardGroupInfo)
 
-	CreateContinuousQuery(database, name, query string) error
+	CreateContinuousQuery(database, name, query, owner string) error
+	AlterContinuousQuery(database, name, query string) error
 	DropContinuousQuery(database, name string) error
 
-	CreateSubscription(database, rp, name, mode string, destinations []string) error
+	CreateSubscription(database, rp, name, mode string, destinations []string, weights []int, filter string, tls SubscriptionTLSInfo) error
 	DropSubscription(database, rp, name string) error
 
 	SetData(data *Data) error
@@ -135,6 +169,9 @@ type Client struct {
 	// Authentication cache.
 	authCache map[string]authUser
 
+	// Hashes new passwords and tells which ones to re-hash.
+	passwordHasher *PasswordHasher
+
 	path string
 
 	retentionPolicyAutoCreate bool
@@ -157,11 +194,19 @@ func NewClient(config *Config) *Client {
 		changed:                   make(chan struct{}),
 		logger:                    zap.NewNop(),
 		authCache:                 make(map[string]authUser),
+		passwordHasher:            NewPasswordHasher(config),
 		path:                      config.Dir,
 		retentionPolicyAutoCreate: config.RetentionAutoCreate,
 	}
 }
 
+// WithPasswordHasher sets the hasher used for new passwords.
+func (c *Client) WithPasswordHasher(h *PasswordHasher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.passwordHasher = h
+}
+
 // Open a connection to a meta service cluster.
 func (c *Client) Open() error {
 	c.mu.Lock()
@@ -233,6 +278,7 @@ func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) { r
 func (c *Client) DataNodeByHTTPHost(httpAddr string) (*NodeInfo, error)      { return nil, nil }
 func (c *Client) DataNodeByTCPHost(tcpAddr string) (*NodeInfo, error)        { return nil, nil }
 func (c *Client) DeleteDataNode(id uint64) error                             { return nil }
+func (c *Client) SetDataNodeTags(id uint64, tags map[string]string) error    { return nil }
 func (c *Client) MetaNodes() ([]NodeInfo, error)                             { return nil, nil }
 func (c *Client) MetaNodeByAddr(addr string) *NodeInfo                       { return nil }
 func (c *Client) CreateMetaNode(httpAddr, tcpAddr string) (*NodeInfo, error) { return nil, nil }
@@ -453,6 +499,24 @@ func (c *Client) SetDefaultRetentionPolicy(database, name string) error {
 	return nil
 }
 
+// UpdateDatabase updates a database.
+func (c *Client) UpdateDatabase(name string, du *DatabaseUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.UpdateDatabase(name, du); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateRetentionPolicy updates a retention policy.
 func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	c.mu.Lock()
@@ -489,16 +553,14 @@ func (c *Client) User(name string) (User, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	for _, u := range c.cacheData.Users {
-		if u.Name == name {
-			return &u, nil
-		}
+	if u := c.cacheData.authUser(name); u != nil {
+		return u, nil
 	}
 
 	return nil, ErrUserNotFound
 }
 
-// bcryptCost is the cost associated with generating password with bcrypt.
+// bcryptCost is the cost of bcrypt hashes when the config doesn't set one.
 // This setting is lowered during testing to improve test suite performance.
 var bcryptCost = bcrypt.DefaultCost
 
@@ -529,19 +591,19 @@ func (c *Client) CreateUser(name, password string, admin bool) (User, error) {
 
 	// See if the user already exists.
 	if u := data.user(name); u != nil {
-		if err := bcrypt.CompareHashAndPassword([]byte(u.Hash), []byte(password)); err != nil || u.Admin != admin {
+		if err := comparePassword(u.Hash, password); err != nil || u.Admin != admin {
 			return nil, ErrUserExists
 		}
 		return u, nil
 	}
 
 	// Hash the password before serializing it.
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	hash, err := c.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := data.CreateUser(name, string(hash), admin); err != nil {
+	if err := data.CreateUser(name, hash, admin, time.Now().UTC()); err != nil {
 		return nil, err
 	}
 
@@ -562,12 +624,12 @@ func (c *Client) UpdateUser(name, password string) error {
 	data := c.cacheData.Clone()
 
 	// Hash the password before serializing it.
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	hash, err := c.passwordHasher.Hash(password)
 	if err != nil {
 		return err
 	}
 
-	if err := data.UpdateUser(name, string(hash)); err != nil {
+	if err := data.UpdateUser(name, hash); err != nil {
 		return err
 	}
 
@@ -612,6 +674,36 @@ func (c *Client) SetPrivilege(username, database string, p cnosql.Privilege) err
 	return nil
 }
 
+// SetPrivilegeOnRP sets a privilege for the given user on the given
+// retention policy of the given database.
+func (c *Client) SetPrivilegeOnRP(username, database, rp string, p cnosql.Privilege) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetPrivilegeOnRP(username, database, rp, p); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetMeasurementPrivilege sets a privilege for the given user on the
+// measurements selected by mp.
+func (c *Client) SetMeasurementPrivilege(username string, mp MeasurementPrivilege) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetMeasurementPrivilege(username, mp); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
 // SetAdminPrivilege sets or unsets admin privilege to the given username.
 func (c *Client) SetAdminPrivilege(username string, admin bool) error {
 	c.mu.Lock()
@@ -630,6 +722,49 @@ func (c *Client) SetAdminPrivilege(username string, admin bool) error {
 	return nil
 }
 
+// SetUserLocked locks or unlocks the given username.
+func (c *Client) SetUserLocked(username string, locked bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetUserLocked(username, locked); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// UpdateUserQueryLimits updates the query limits of the given username.
+func (c *Client) UpdateUserQueryLimits(username string, u *UserQueryLimitsUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.UpdateUserQueryLimits(username, u); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetUserDefaultDatabase sets the default database and retention policy of
+// the given username.
+func (c *Client) SetUserDefaultDatabase(username, database, rp string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetUserDefaultDatabase(username, database, rp); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
 // UserPrivileges returns the privileges for a user mapped by database name.
 func (c *Client) UserPrivileges(username string) (map[string]cnosql.Privilege, error) {
 	c.mu.RLock()
@@ -642,6 +777,23 @@ func (c *Client) UserPrivileges(username string) (map[string]cnosql.Privilege, e
 	return p, nil
 }
 
+// UserPrivilegeOnRP returns the privilege for the given user on the given
+// retention policy of the given database.
+func (c *Client) UserPrivilegeOnRP(username, database, rp string) (*cnosql.Privilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.UserPrivilegeOnRP(username, database, rp)
+}
+
+// UserMeasurementPrivileges returns the privileges of a user granted on measurements.
+func (c *Client) UserMeasurementPrivileges(username string) ([]MeasurementPrivilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.UserMeasurementPrivileges(username)
+}
+
 // UserPrivilege returns the privilege for the given user on the given database.
 func (c *Client) UserPrivilege(username, database string) (*cnosql.Privilege, error) {
 	c.mu.RLock()
@@ -665,7 +817,7 @@ func (c *Client) AdminUserExists() bool {
 func (c *Client) Authenticate(username, password string) (User, error) {
 	// Find user.
 	c.mu.RLock()
-	userInfo := c.cacheData.user(username)
+	userInfo := c.cacheData.authUser(username)
 	c.mu.RUnlock()
 	if userInfo == nil {
 		return nil, ErrUserNotFound
@@ -678,6 +830,10 @@ func (c *Client) Authenticate(username, password string) (User, error) {
 	if ok {
 		// verify the password using the cached salt and hash
 		if bytes.Equal(c.hashWithSalt(au.salt, password), au.hash) {
+			if userInfo.Locked {
+				return nil, ErrUserLocked
+			}
+			c.recordLogin(userInfo)
 			return userInfo, nil
 		}
 
@@ -685,18 +841,110 @@ func (c *Client) Authenticate(username, password string) (User, error) {
 	}
 
 	// Compare password with user hash.
-	if err := bcrypt.CompareHashAndPassword([]byte(userInfo.Hash), []byte(password)); err != nil {
+	if err := comparePassword(userInfo.Hash, password); err != nil {
 		return nil, ErrAuthenticate
 	}
 
+	// Only tell a locked user apart once the password is verified.
+	if userInfo.Locked {
+		return nil, ErrUserLocked
+	}
+
+	// Upgrade hashes created with an older setting now that the password is known.
+	bhash := userInfo.Hash
+	if c.passwordHasher.NeedsRehash(bhash) {
+		if hash, err := c.rehashUser(username, bhash, password); err != nil {
+			c.logger.Warn("Failed to upgrade password hash", zap.String("user", username), zap.Error(err))
+		} else {
+			bhash = hash
+		}
+	}
+
 	// generate a salt and hash of the password for the cache
 	salt, hashed, err := c.saltedHash(password)
 	if err != nil {
 		return nil, err
 	}
 	c.mu.Lock()
-	c.authCache[username] = authUser{salt: salt, hash: hashed, bhash: userInfo.Hash}
+	c.authCache[username] = authUser{salt: salt, hash: hashed, bhash: bhash}
 	c.mu.Unlock()
+
+	c.recordLogin(userInfo)
+	return userInfo, nil
+}
+
+// recordLogin records that a user authenticated, at most once per
+// UserLastLoginInterval. Recording it is best effort, failing to record it
+// doesn't fail the authentication.
+func (c *Client) recordLogin(ui *UserInfo) {
+	now := time.Now().UTC()
+	if now.Sub(ui.LastLogin) < UserLastLoginInterval {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ui := c.cacheData.user(ui.Name); ui != nil && now.Sub(ui.LastLogin) >= UserLastLoginInterval {
+		data := c.cacheData.Clone()
+		if err := data.SetUserLastLogin(ui.Name, now); err == nil {
+			_ = c.commit(data)
+		}
+	}
+}
+
+// rehashUser replaces the password hash of a user with one created with the
+// current settings, unless the password changed since old was read.
+func (c *Client) rehashUser(username, old, password string) (string, error) {
+	hash, err := c.passwordHasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+	if !data.RehashUser(username, old, hash) {
+		return old, nil
+	}
+	if err := c.commit(data); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// AuthenticateExternal returns the user authenticated by an external
+// provider, creating it without a password if it is unknown and create is
+// set. The admin privilege of externally managed users follows admin unless
+// it is nil.
+func (c *Client) AuthenticateExternal(username string, admin *bool, create bool) (User, error) {
+	userInfo, err := c.authenticateExternal(username, admin, create)
+	if err != nil {
+		return nil, err
+	}
+	c.recordLogin(userInfo)
+	return userInfo, nil
+}
+
+func (c *Client) authenticateExternal(username string, admin *bool, create bool) (*UserInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.cacheData.externalUserInSync(username, admin) {
+		data := c.cacheData.Clone()
+		if err := data.SetExternalUser(username, admin, create); err != nil {
+			return nil, err
+		} else if err := c.commit(data); err != nil {
+			return nil, err
+		}
+	}
+
+	userInfo := c.cacheData.authUser(username)
+	if userInfo == nil {
+		return nil, ErrUserNotFound
+	} else if userInfo.Locked {
+		return nil, ErrUserLocked
+	}
 	return userInfo, nil
 }
 
@@ -708,6 +956,177 @@ func (c *Client) UserCount() int {
 	return len(c.cacheData.Users)
 }
 
+// Roles returns a slice of RoleInfo representing the currently known roles.
+func (c *Client) Roles() []RoleInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	roles := c.cacheData.Roles
+
+	if roles == nil {
+		return []RoleInfo{}
+	}
+	return roles
+}
+
+// CreateRole adds a role with the given name.
+func (c *Client) CreateRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// DropRole removes the role with the given name.
+func (c *Client) DropRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetRolePrivilege sets a privilege for the given role on the given database.
+func (c *Client) SetRolePrivilege(role, database string, p cnosql.Privilege) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetRolePrivilege(role, database, p); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// RolePrivileges returns the privileges for a role mapped by database name.
+func (c *Client) RolePrivileges(role string) (map[string]cnosql.Privilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.RolePrivileges(role)
+}
+
+// GrantRole grants the given role to the given user.
+func (c *Client) GrantRole(username, role string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.GrantRole(username, role); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// RevokeRole revokes the given role from the given user.
+func (c *Client) RevokeRole(username, role string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.RevokeRole(username, role); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// Tokens returns a slice of TokenInfo representing the currently known tokens.
+func (c *Client) Tokens() []TokenInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tokens := c.cacheData.Tokens
+
+	if tokens == nil {
+		return []TokenInfo{}
+	}
+	return tokens
+}
+
+// CreateToken adds a token authenticating as the given user, restricted to
+// the privilege p on database unless p is NoPrivileges. It returns the token
+// to authenticate with, which is not stored.
+func (c *Client) CreateToken(username, database string, p cnosql.Privilege, expiry time.Time) (string, *TokenInfo, error) {
+	ti, token, err := NewToken(username, database, p, expiry)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateToken(ti); err != nil {
+		return "", nil, err
+	}
+
+	if err := c.commit(data); err != nil {
+		return "", nil, err
+	}
+
+	return token, &ti, nil
+}
+
+// DropToken removes the token with the given id.
+func (c *Client) DropToken(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropToken(id); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// AuthenticateToken returns the user an API token authenticates as,
+// restricted to the privileges of the token.
+func (c *Client) AuthenticateToken(token string) (User, error) {
+	now := time.Now().UTC()
+
+	c.mu.RLock()
+	userInfo, ti, err := c.cacheData.authToken(token, now)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Recording the last use is best effort, failing to record it doesn't
+	// fail the authentication.
+	if now.Sub(ti.LastUsed) >= TokenLastUsedInterval {
+		c.mu.Lock()
+		if ti := c.cacheData.Token(ti.ID); ti != nil && now.Sub(ti.LastUsed) >= TokenLastUsedInterval {
+			data := c.cacheData.Clone()
+			if err := data.SetTokenLastUsed(ti.ID, now); err == nil {
+				_ = c.commit(data)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return userInfo, nil
+}
+
 // ShardIDs returns a list of all shard ids.
 func (c *Client) ShardIDs() []uint64 {
 	c.mu.RLock()
@@ -804,7 +1223,7 @@ func (c *Client) PruneShardGroups() error {
 		for j, rp := range d.RetentionPolicies {
 			var remainingShardGroups []ShardGroupInfo
 			for _, sgi := range rp.ShardGroups {
-				if sgi.DeletedAt.IsZero() || !expiration.After(sgi.DeletedAt) {
+				if sgi.DeletedAt.IsZero() || !expiration.After(sgi.PurgeAt(rp.DeletionDelay)) {
 					remainingShardGroups = append(remainingShardGroups, sgi)
 					continue
 				}
@@ -889,6 +1308,36 @@ func (c *Client) DeleteShardGroup(database, rp string, id uint64) error {
 	return nil
 }
 
+// PurgeShardGroup marks a shard group whose shards were deleted right away as
+// deleted, so it can't be restored.
+func (c *Client) PurgeShardGroup(database, rp string, id uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.PurgeShardGroup(database, rp, id); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// UndeleteShardGroup restores a deleted shard group within the deletion delay
+// of its retention policy.
+func (c *Client) UndeleteShardGroup(database, rp string, id uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.UndeleteShardGroup(database, rp, id); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
 // PrecreateShardGroups creates shard groups whose endtime is before the 'to' time passed in, but
 // is yet to expire before 'from'. This is to avoid the need for these shards to be created when data
 // for the corresponding time range arrives. Shard creation involves Raft consensus, and precreation
@@ -972,13 +1421,14 @@ func (c *Client) ShardOwner(shardID uint64) (database, rp string, sgi *ShardGrou
 }
 
 // CreateContinuousQuery saves a continuous query with the given name for the given database.
-func (c *Client) CreateContinuousQuery(database, name, query string) error {
+// owner is the user creating it, or blank if authentication is disabled.
+func (c *Client) CreateContinuousQuery(database, name, query, owner string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	data := c.cacheData.Clone()
 
-	if err := data.CreateContinuousQuery(database, name, query); err != nil {
+	if err := data.CreateContinuousQuery(database, name, query, owner); err != nil {
 		return err
 	}
 
@@ -989,6 +1439,20 @@ func (c *Client) CreateContinuousQuery(database, name, query string) error {
 	return nil
 }
 
+// AlterContinuousQuery replaces the query of the continuous query with the given name on the given database.
+func (c *Client) AlterContinuousQuery(database, name, query string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.AlterContinuousQuery(database, name, query); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
 // DropContinuousQuery removes the continuous query with the given name on the given database.
 func (c *Client) DropContinuousQuery(database, name string) error {
 	c.mu.Lock()
@@ -1008,13 +1472,13 @@ func (c *Client) DropContinuousQuery(database, name string) error {
 }
 
 // CreateSubscription creates a subscription against the given database and retention policy.
-func (c *Client) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+func (c *Client) CreateSubscription(database, rp, name, mode string, destinations []string, weights []int, filter string, tls SubscriptionTLSInfo) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	data := c.cacheData.Clone()
 
-	if err := data.CreateSubscription(database, rp, name, mode, destinations); err != nil {
+	if err := data.CreateSubscription(database, rp, name, mode, destinations, weights, filter, tls); err != nil {
 		return err
 	}
 
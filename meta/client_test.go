@@ -0,0 +1,234 @@
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestClient_Authenticate_RecordsLogin(t *testing.T) {
+	c := NewClient(&Config{Dir: t.TempDir()})
+	c.WithPasswordHasher(&PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	user := func() UserInfo {
+		data := c.Data()
+		return *data.user("alice")
+	}
+
+	if _, err := c.CreateUser("alice", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	if ui := user(); ui.CreatedAt.IsZero() {
+		t.Fatal("created time not recorded")
+	} else if !ui.LastLogin.IsZero() {
+		t.Fatal("last login recorded before authenticating")
+	}
+
+	if _, err := c.Authenticate("alice", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	first := user().LastLogin
+	if first.IsZero() {
+		t.Fatal("last login not recorded")
+	}
+
+	// Logins within the interval don't change the metadata.
+	index := c.Data().Index
+	if _, err := c.Authenticate("alice", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if ui := user(); !ui.LastLogin.Equal(first) {
+		t.Fatal("last login recorded twice within the interval")
+	} else if c.Data().Index != index {
+		t.Fatal("metadata changed by throttled login")
+	}
+
+	// The times survive serialization.
+	data := c.Data()
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	ui := other.user("alice")
+	if !ui.CreatedAt.Equal(user().CreatedAt) || !ui.LastLogin.Equal(first) {
+		t.Fatalf("unexpected times after unmarshal: created=%s last_login=%s", ui.CreatedAt, ui.LastLogin)
+	}
+	if ui.LastLogin.Location() != time.UTC {
+		t.Fatal("expected UTC times")
+	}
+}
+
+func TestClient_UndeleteShardGroup_Purged(t *testing.T) {
+	c := NewClient(&Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	delay := time.Hour
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &RetentionPolicySpec{Name: "rp0", DeletionDelay: &delay}); err != nil {
+		t.Fatal(err)
+	}
+	group := func(ts time.Time) *ShardGroupInfo {
+		sgi, err := c.CreateShardGroup("db0", "rp0", ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sgi
+	}
+	now := time.Now().UTC()
+
+	// A deleted shard group is restored within the deletion delay.
+	sgi := group(now)
+	if err := c.DeleteShardGroup("db0", "rp0", sgi.ID); err != nil {
+		t.Fatal(err)
+	} else if err := c.UndeleteShardGroup("db0", "rp0", sgi.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not once its shards were deleted by PURGE DATA.
+	if err := c.PurgeShardGroup("db0", "rp0", sgi.ID); err != nil {
+		t.Fatal(err)
+	} else if err := c.UndeleteShardGroup("db0", "rp0", sgi.ID); err != ErrShardGroupShardsDeleted {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Nor once its last shard was dropped.
+	sgi = group(now.Add(-30 * 24 * time.Hour))
+	for _, si := range sgi.Shards {
+		if err := c.DropShard(si.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.UndeleteShardGroup("db0", "rp0", sgi.ID); err != ErrShardGroupShardsDeleted {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The mark survives serialization.
+	data := c.Data()
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.UndeleteShardGroup("db0", "rp0", sgi.ID); err != ErrShardGroupShardsDeleted {
+		t.Fatalf("unexpected error after unmarshal: %v", err)
+	}
+}
+
+func TestClient_AuthenticateExternal(t *testing.T) {
+	c := NewClient(&Config{Dir: t.TempDir()})
+	c.WithPasswordHasher(&PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	user := func(name string) UserInfo {
+		data := c.Data()
+		return *data.user(name)
+	}
+	admin, notAdmin := true, false
+
+	// Local users are not taken over by the external provider.
+	if _, err := c.CreateUser("admin", "secret", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AuthenticateExternal("admin", &admin, true); err != ErrUserNotExternal {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.AuthenticateExternal("admin", nil, false); err != ErrUserNotExternal {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ui := user("admin"); ui.External || !ui.Admin {
+		t.Fatalf("local user changed: %+v", ui)
+	}
+
+	// Unknown users are only created if requested.
+	if _, err := c.AuthenticateExternal("bob", &admin, false); err != ErrUserNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, err := c.AuthenticateExternal("bob", &admin, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if u.ID() != "bob" {
+		t.Fatalf("unexpected user: %s", u.ID())
+	}
+	if ui := user("bob"); !ui.External || !ui.Admin || ui.Hash != "" {
+		t.Fatalf("unexpected external user: %+v", ui)
+	}
+
+	// The admin privilege follows the provider unless it doesn't manage it.
+	if _, err := c.AuthenticateExternal("bob", nil, true); err != nil {
+		t.Fatal(err)
+	} else if ui := user("bob"); !ui.Admin {
+		t.Fatal("admin privilege revoked without the provider managing it")
+	}
+	if _, err := c.AuthenticateExternal("bob", &notAdmin, true); err != nil {
+		t.Fatal(err)
+	} else if ui := user("bob"); ui.Admin {
+		t.Fatal("admin privilege not revoked")
+	}
+
+	// Externally managed users have no password.
+	if err := c.UpdateUser("bob", "secret"); err != ErrUserExternal {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := c.Authenticate("bob", ""); err != ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Locked users can't log in through the provider either.
+	if err := c.SetUserLocked("bob", true); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.AuthenticateExternal("bob", nil, true); err != ErrUserLocked {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_CreateContinuousQuery_Owner(t *testing.T) {
+	c := NewClient(&Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, cq := range []struct{ name, owner string }{{"cq0", "alice"}, {"cq1", ""}} {
+		q := `CREATE CONTINUOUS QUERY ` + cq.name + ` ON db0 BEGIN SELECT mean(value) INTO cpu_1h FROM cpu GROUP BY time(1h) END`
+		if err := c.CreateContinuousQuery("db0", cq.name, q, cq.owner); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Queries created without authentication have no owner, and the owner
+	// survives serialization.
+	data := c.Data()
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []*Data{&data, &other} {
+		cqs := d.Database("db0").ContinuousQueries
+		if len(cqs) != 2 || cqs[0].Owner != "alice" || cqs[1].Owner != "" {
+			t.Fatalf("unexpected continuous queries: %+v", cqs)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/cnosdb/cnosdb/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/text/encoding/unicode"
 	"golang.org/x/text/transform"
 )
@@ -20,6 +21,15 @@ import (
 const (
 	// DefaultLoggingEnabled determines if log messages are printed for the meta service.
 	DefaultLoggingEnabled = true
+
+	// DefaultPasswordHash is the algorithm new password hashes are created with.
+	DefaultPasswordHash = PasswordHashBcrypt
+
+	// DefaultArgon2Time, DefaultArgon2Memory and DefaultArgon2Threads are the
+	// iterations, memory in KiB and parallelism of argon2id password hashes.
+	DefaultArgon2Time    = 1
+	DefaultArgon2Memory  = 64 * 1024
+	DefaultArgon2Threads = 4
 )
 
 // Config represents the meta configuration.
@@ -29,12 +39,22 @@ type Config struct {
 	Hostname            string `toml:"hostname"`
 	HTTPD               *ServerConfig
 	Log                 *logger.Config
+
+	// Algorithm and cost new password hashes are created with. Passwords
+	// hashed with another algorithm or a lower cost are re-hashed the next
+	// time the user authenticates.
+	PasswordHash  string `toml:"password-hash"`
+	BcryptCost    int    `toml:"bcrypt-cost"`
+	Argon2Time    uint32 `toml:"argon2-time"`
+	Argon2Memory  uint32 `toml:"argon2-memory"`
+	Argon2Threads uint8  `toml:"argon2-threads"`
 }
 
 // NewConfig builds a new configuration with default values.
 func NewConfig() *Config {
 	return &Config{
 		RetentionAutoCreate: true,
+		PasswordHash:        DefaultPasswordHash,
 	}
 }
 
@@ -109,5 +129,18 @@ func (c *Config) Validate() error {
 	if c.Dir == "" {
 		return errors.New("Meta.Dir must be specified")
 	}
+	return c.ValidatePasswordHash()
+}
+
+// ValidatePasswordHash returns an error if the password hash settings are invalid.
+func (c *Config) ValidatePasswordHash() error {
+	switch c.PasswordHash {
+	case "", PasswordHashBcrypt, PasswordHashArgon2id:
+	default:
+		return fmt.Errorf("Meta.PasswordHash must be %q or %q", PasswordHashBcrypt, PasswordHashArgon2id)
+	}
+	if c.BcryptCost != 0 && (c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost) {
+		return fmt.Errorf("Meta.BcryptCost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
 	return nil
 }
@@ -1,10 +1,15 @@
 package meta
 
 import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -12,6 +17,8 @@ import (
 	"unicode"
 
 	"github.com/cnosdb/cnosdb"
+	"github.com/cnosdb/cnosdb/pkg/kafka"
+	"github.com/cnosdb/cnosdb/pkg/mqtt"
 	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/models"
 	"github.com/cnosdb/cnosdb/vend/db/query"
@@ -48,6 +55,8 @@ type Data struct {
 	DataNodes []NodeInfo
 	Databases []DatabaseInfo
 	Users     []UserInfo
+	Roles     []RoleInfo
+	Tokens    []TokenInfo
 
 	// adminUserExists provides a constant time mechanism for determining
 	// if there is at least one admin user.
@@ -156,6 +165,23 @@ func (data *Data) DataNode(id uint64) *NodeInfo {
 	return nil
 }
 
+// SetDataNodeTags replaces the tags of a data node. Tags are matched against
+// the placement of retention policies when shard groups are created.
+func (data *Data) SetDataNodeTags(id uint64, tags map[string]string) error {
+	n := data.DataNode(id)
+	if n == nil {
+		return ErrNodeNotFound
+	}
+	n.Tags = nil
+	for k, v := range tags {
+		if n.Tags == nil {
+			n.Tags = make(map[string]string, len(tags))
+		}
+		n.Tags[k] = v
+	}
+	return nil
+}
+
 // CreateDataNode adds a node to the metadata.
 func (data *Data) CreateDataNode(host, tcpHost string) error {
 	// Ensure a node with the same host doesn't already exist.
@@ -274,6 +300,7 @@ func (data *Data) DeleteDataNode(id uint64) error {
 				// or all of its shards are orphaned.
 				if len(rg.Shards) == 0 || len(orphanedShards) == len(rg.Shards) {
 					data.Databases[di].RetentionPolicies[ti].ShardGroups[ri].DeletedAt = time.Now().UTC()
+					data.Databases[di].RetentionPolicies[ti].ShardGroups[ri].Purged = true
 					continue
 				}
 
@@ -362,10 +389,26 @@ func (data *Data) DropDatabase(name string) error {
 		if data.Databases[i].Name == name {
 			data.Databases = append(data.Databases[:i], data.Databases[i+1:]...)
 
-			// Remove all user privileges associated with this database.
+			// Remove all user and role privileges associated with this database.
 			for i := range data.Users {
 				delete(data.Users[i].Privileges, name)
 			}
+			for i := range data.Roles {
+				delete(data.Roles[i].Privileges, name)
+			}
+			for i := range data.Users {
+				data.Users[i].MeasurementPrivileges = removeMeasurementPrivileges(data.Users[i].MeasurementPrivileges, func(mp *MeasurementPrivilege) bool {
+					return mp.Database == name
+				})
+				data.Users[i].RetentionPolicyPrivileges = removeRetentionPolicyPrivilege(data.Users[i].RetentionPolicyPrivileges, name, "")
+
+				// Clear default databases so they don't refer to a
+				// database that may be recreated later.
+				if data.Users[i].DefaultDatabase == name {
+					data.Users[i].DefaultDatabase = ""
+					data.Users[i].DefaultRetentionPolicy = ""
+				}
+			}
 			break
 		}
 	}
@@ -409,6 +452,9 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 	if rpi.Duration > 0 && rpi.Duration < rpi.ShardGroupDuration {
 		return ErrIncompatibleDurations
 	}
+	if _, err := ParsePlacement(rpi.Placement); err != nil {
+		return err
+	}
 
 	// Find database.
 	di := data.Database(database)
@@ -416,7 +462,11 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 		return cnosdb.ErrDatabaseNotFound(database)
 	} else if rp := di.RetentionPolicy(rpi.Name); rp != nil {
 		// Retention policy with that name already exists. Make sure they're the same.
-		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration {
+		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration ||
+			rp.FutureWriteLimit != rpi.FutureWriteLimit || rp.PastWriteLimit != rpi.PastWriteLimit ||
+			rp.ColdAfter != rpi.ColdAfter || !rp.Downsample.Equal(rpi.Downsample) ||
+			rp.Placement != rpi.Placement || rp.DeletionDelay != rpi.DeletionDelay ||
+			rp.MaxQueryRange != rpi.MaxQueryRange {
 			return ErrRetentionPolicyExists
 		}
 		// if they want to make it default, and it's not the default, it's not an identical command so it's an error
@@ -450,6 +500,14 @@ func (data *Data) DropRetentionPolicy(database, name string) error {
 	for i := range di.RetentionPolicies {
 		if di.RetentionPolicies[i].Name == name {
 			di.RetentionPolicies = append(di.RetentionPolicies[:i], di.RetentionPolicies[i+1:]...)
+
+			// Remove all user privileges granted on this retention policy.
+			for j := range data.Users {
+				data.Users[j].RetentionPolicyPrivileges = removeRetentionPolicyPrivilege(data.Users[j].RetentionPolicyPrivileges, database, name)
+				if data.Users[j].DefaultDatabase == database && data.Users[j].DefaultRetentionPolicy == name {
+					data.Users[j].DefaultRetentionPolicy = ""
+				}
+			}
 			break
 		}
 	}
@@ -463,6 +521,12 @@ type RetentionPolicyUpdate struct {
 	Duration           *time.Duration
 	ReplicaN           *int
 	ShardGroupDuration *time.Duration
+	FutureWriteLimit   *time.Duration
+	PastWriteLimit     *time.Duration
+	ColdAfter          *time.Duration
+	Placement          *string
+	DeletionDelay      *time.Duration
+	MaxQueryRange      *time.Duration
 }
 
 // SetName sets the RetentionPolicyUpdate.Name.
@@ -477,6 +541,24 @@ func (rpu *RetentionPolicyUpdate) SetReplicaN(v int) { rpu.ReplicaN = &v }
 // SetShardGroupDuration sets the RetentionPolicyUpdate.ShardGroupDuration.
 func (rpu *RetentionPolicyUpdate) SetShardGroupDuration(v time.Duration) { rpu.ShardGroupDuration = &v }
 
+// SetFutureWriteLimit sets the RetentionPolicyUpdate.FutureWriteLimit.
+func (rpu *RetentionPolicyUpdate) SetFutureWriteLimit(v time.Duration) { rpu.FutureWriteLimit = &v }
+
+// SetPastWriteLimit sets the RetentionPolicyUpdate.PastWriteLimit.
+func (rpu *RetentionPolicyUpdate) SetPastWriteLimit(v time.Duration) { rpu.PastWriteLimit = &v }
+
+// SetColdAfter sets the RetentionPolicyUpdate.ColdAfter.
+func (rpu *RetentionPolicyUpdate) SetColdAfter(v time.Duration) { rpu.ColdAfter = &v }
+
+// SetPlacement sets the RetentionPolicyUpdate.Placement.
+func (rpu *RetentionPolicyUpdate) SetPlacement(v string) { rpu.Placement = &v }
+
+// SetDeletionDelay sets the RetentionPolicyUpdate.DeletionDelay.
+func (rpu *RetentionPolicyUpdate) SetDeletionDelay(v time.Duration) { rpu.DeletionDelay = &v }
+
+// SetMaxQueryRange sets the RetentionPolicyUpdate.MaxQueryRange.
+func (rpu *RetentionPolicyUpdate) SetMaxQueryRange(v time.Duration) { rpu.MaxQueryRange = &v }
+
 // UpdateRetentionPolicy updates an existing retention policy.
 func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	// Find database.
@@ -510,8 +592,22 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 		return ErrIncompatibleDurations
 	}
 
+	if rpu.Placement != nil {
+		if _, err := ParsePlacement(*rpu.Placement); err != nil {
+			return err
+		}
+	}
+
 	// Update fields.
 	if rpu.Name != nil {
+		// Privileges granted on the retention policy follow it.
+		for i := range data.Users {
+			for j := range data.Users[i].RetentionPolicyPrivileges {
+				if p := &data.Users[i].RetentionPolicyPrivileges[j]; p.Database == database && p.RetentionPolicy == rpi.Name {
+					p.RetentionPolicy = *rpu.Name
+				}
+			}
+		}
 		rpi.Name = *rpu.Name
 	}
 	if rpu.Duration != nil {
@@ -523,6 +619,24 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	if rpu.ShardGroupDuration != nil {
 		rpi.ShardGroupDuration = normalisedShardDuration(*rpu.ShardGroupDuration, rpi.Duration)
 	}
+	if rpu.FutureWriteLimit != nil {
+		rpi.FutureWriteLimit = *rpu.FutureWriteLimit
+	}
+	if rpu.PastWriteLimit != nil {
+		rpi.PastWriteLimit = *rpu.PastWriteLimit
+	}
+	if rpu.ColdAfter != nil {
+		rpi.ColdAfter = *rpu.ColdAfter
+	}
+	if rpu.Placement != nil {
+		rpi.Placement = *rpu.Placement
+	}
+	if rpu.DeletionDelay != nil {
+		rpi.DeletionDelay = *rpu.DeletionDelay
+	}
+	if rpu.MaxQueryRange != nil {
+		rpi.MaxQueryRange = *rpu.MaxQueryRange
+	}
 
 	if di.DefaultRetentionPolicy != rpi.Name && makeDefault {
 		di.DefaultRetentionPolicy = rpi.Name
@@ -547,6 +661,47 @@ func (data *Data) SetDefaultRetentionPolicy(database, name string) error {
 	return nil
 }
 
+// DatabaseUpdate represents database fields to be updated.
+type DatabaseUpdate struct {
+	MaxSeriesN      *int64
+	MaxValuesPerTag *int64
+	IndexType       *string
+}
+
+// SetMaxSeriesN sets the DatabaseUpdate.MaxSeriesN.
+func (du *DatabaseUpdate) SetMaxSeriesN(v int64) { du.MaxSeriesN = &v }
+
+// SetMaxValuesPerTag sets the DatabaseUpdate.MaxValuesPerTag.
+func (du *DatabaseUpdate) SetMaxValuesPerTag(v int64) { du.MaxValuesPerTag = &v }
+
+// SetIndexType sets the DatabaseUpdate.IndexType.
+func (du *DatabaseUpdate) SetIndexType(v string) { du.IndexType = &v }
+
+// UpdateDatabase updates an existing database. A zero limit removes it.
+func (data *Data) UpdateDatabase(name string, du *DatabaseUpdate) error {
+	if (du.MaxSeriesN != nil && *du.MaxSeriesN < 0) ||
+		(du.MaxValuesPerTag != nil && *du.MaxValuesPerTag < 0) {
+		return ErrDatabaseLimitNegative
+	}
+
+	di := data.Database(name)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(name)
+	}
+
+	if du.MaxSeriesN != nil {
+		di.MaxSeriesN = *du.MaxSeriesN
+	}
+	if du.MaxValuesPerTag != nil {
+		di.MaxValuesPerTag = *du.MaxValuesPerTag
+	}
+	if du.IndexType != nil {
+		di.IndexType = *du.IndexType
+	}
+
+	return nil
+}
+
 // DropShard removes a shard by ID.
 //
 // DropShard won't return an error if the shard can't be found, which
@@ -569,8 +724,10 @@ func (data *Data) DropShard(id uint64) {
 					data.Databases[dbidx].RetentionPolicies[rpidx].ShardGroups[sgidx].Shards = append(shards[:found], shards[found+1:]...)
 
 					if len(shards) == 1 {
-						// We just deleted the last shard in the shard group.
+						// We just deleted the last shard in the shard group,
+						// which can't be restored without it.
 						data.Databases[dbidx].RetentionPolicies[rpidx].ShardGroups[sgidx].DeletedAt = time.Now()
+						data.Databases[dbidx].RetentionPolicies[rpidx].ShardGroups[sgidx].Purged = true
 					}
 					return
 				}
@@ -673,13 +830,6 @@ func (data *Data) CreateShardGroupDeprecated(database, rp string, timestamp time
 
 // CreateShardGroup creates a shard group on a database and retention policy for a given timestamp.
 func (data *Data) CreateShardGroup(database, rp string, timestamp time.Time) error {
-	singleMode := false
-	dataNodeCount := len(data.DataNodes)
-	if dataNodeCount == 0 {
-		dataNodeCount = 1
-		singleMode = true
-	}
-
 	// Find retention policy.
 	rpi, err := data.RetentionPolicy(database, rp)
 	if err != nil {
@@ -693,6 +843,33 @@ func (data *Data) CreateShardGroup(database, rp string, timestamp time.Time) err
 		return nil
 	}
 
+	// Owners are chosen from the data nodes matching the placement of the
+	// retention policy. A policy with a placement never falls back to
+	// other nodes.
+	nodes := data.DataNodes
+	if rpi.Placement != "" {
+		selector, err := ParsePlacement(rpi.Placement)
+		if err != nil {
+			return err
+		}
+		nodes = nil
+		for _, n := range data.DataNodes {
+			if n.MatchesPlacement(selector) {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) == 0 {
+			return ErrNoPlacementNodes(rpi.Name, rpi.Placement)
+		}
+	}
+
+	singleMode := false
+	dataNodeCount := len(nodes)
+	if dataNodeCount == 0 {
+		dataNodeCount = 1
+		singleMode = true
+	}
+
 	// Require at least one replica but no more replicas than nodes.
 	replicaN := rpi.ReplicaN
 	if replicaN == 0 {
@@ -743,7 +920,7 @@ func (data *Data) CreateShardGroup(database, rp string, timestamp time.Time) err
 		for i := range sgi.Shards {
 			si := &sgi.Shards[i]
 			for j := 0; j < replicaN; j++ {
-				nodeID := data.DataNodes[nodeIndex%dataNodeCount].ID
+				nodeID := nodes[nodeIndex%dataNodeCount].ID
 				si.Owners = append(si.Owners, ShardOwner{NodeID: nodeID})
 				nodeIndex++
 			}
@@ -780,8 +957,64 @@ func (data *Data) DeleteShardGroup(database, rp string, id uint64) error {
 	return ErrShardGroupNotFound
 }
 
-// CreateContinuousQuery adds a named continuous query to a database.
-func (data *Data) CreateContinuousQuery(database, name, query string) error {
+// PurgeShardGroup marks a shard group as deleted along with its shards, so it
+// can't be restored. It is used when the shards are deleted right away rather
+// than after the deletion delay of the retention policy.
+func (data *Data) PurgeShardGroup(database, rp string, id uint64) error {
+	// Find retention policy.
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return cnosdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.ShardGroups {
+		sgi := &rpi.ShardGroups[i]
+		if sgi.ID == id {
+			if !sgi.Deleted() {
+				sgi.DeletedAt = time.Now().UTC()
+			}
+			sgi.Purged = true
+			return nil
+		}
+	}
+
+	return ErrShardGroupNotFound
+}
+
+// UndeleteShardGroup restores a deleted shard group whose retention policy
+// deletion delay has not elapsed yet, so its shards still exist.
+func (data *Data) UndeleteShardGroup(database, rp string, id uint64) error {
+	// Find retention policy.
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return cnosdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.ShardGroups {
+		sgi := &rpi.ShardGroups[i]
+		if sgi.ID != id {
+			continue
+		}
+		if !sgi.Deleted() {
+			return ErrShardGroupNotDeleted
+		} else if sgi.Purged {
+			return ErrShardGroupShardsDeleted
+		} else if purgeAt := sgi.PurgeAt(rpi.DeletionDelay); !time.Now().Before(purgeAt) {
+			return ErrShardGroupPurged
+		}
+		sgi.DeletedAt = time.Time{}
+		return nil
+	}
+
+	return ErrShardGroupNotFound
+}
+
+// CreateContinuousQuery adds a named continuous query owned by owner to a database.
+func (data *Data) CreateContinuousQuery(database, name, query, owner string) error {
 	di := data.Database(database)
 	if di == nil {
 		return cnosdb.ErrDatabaseNotFound(database)
@@ -801,14 +1034,34 @@ func (data *Data) CreateContinuousQuery(database, name, query string) error {
 	}
 
 	// Append new query.
-	di.ContinuousQueries = append(di.ContinuousQueries, ContinuousQueryInfo{
+	cqi := ContinuousQueryInfo{
 		Name:  name,
 		Query: query,
-	})
+		Owner: owner,
+	}
+	cqi.parseOptions()
+	di.ContinuousQueries = append(di.ContinuousQueries, cqi)
 
 	return nil
 }
 
+// AlterContinuousQuery replaces the query of an existing continuous query.
+func (data *Data) AlterContinuousQuery(database, name, query string) error {
+	di := data.Database(database)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		if cqi := &di.ContinuousQueries[i]; cqi.Name == name {
+			cqi.Query = query
+			cqi.parseOptions()
+			return nil
+		}
+	}
+	return ErrContinuousQueryNotFound
+}
+
 // DropContinuousQuery removes a continuous query.
 func (data *Data) DropContinuousQuery(database, name string) error {
 	di := data.Database(database)
@@ -825,13 +1078,27 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
-// validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
+// validateURL returns an error if the URL does not have a port or uses a scheme other than UDP, HTTP, Kafka or MQTT.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
 	if err != nil {
 		return ErrInvalidSubscriptionURL(input)
 	}
 
+	if u.Scheme == kafka.Scheme {
+		if _, _, err := kafka.ParseURL(u); err != nil {
+			return ErrInvalidSubscriptionURL(input)
+		}
+		return nil
+	}
+
+	if u.Scheme == mqtt.Scheme || u.Scheme == mqtt.SchemeTLS {
+		if _, err := mqtt.ParseURL(u); err != nil {
+			return ErrInvalidSubscriptionURL(input)
+		}
+		return nil
+	}
+
 	if u.Scheme != "udp" && u.Scheme != "http" && u.Scheme != "https" {
 		return ErrInvalidSubscriptionURL(input)
 	}
@@ -844,13 +1111,52 @@ func validateURL(input string) error {
 	return nil
 }
 
+// validateWeights returns an error unless a BALANCE subscription has one
+// weight per destination, none negative and at least one positive.
+func validateWeights(mode string, destinations []string, weights []int) error {
+	if mode != "BALANCE" {
+		if len(weights) > 0 {
+			return ErrInvalidSubscriptionWeights("only BALANCE mode supports weights")
+		}
+		return nil
+	}
+
+	if len(weights) != len(destinations) {
+		return ErrInvalidSubscriptionWeights(fmt.Sprintf("got %d weights for %d destinations", len(weights), len(destinations)))
+	}
+	active := false
+	for _, w := range weights {
+		if w < 0 {
+			return ErrInvalidSubscriptionWeights("weights must not be negative")
+		} else if w > 0 {
+			active = true
+		}
+	}
+	if !active {
+		return ErrInvalidSubscriptionWeights("at least one destination must have a positive weight")
+	}
+	return nil
+}
+
 // CreateSubscription adds a named subscription to a database and retention policy.
-func (data *Data) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+// weights holds the weight of each destination in BALANCE mode and must be
+// nil in the other modes.
+// An empty filter forwards every point written to the retention policy.
+// The TLS files are checked when the subscription is created.
+func (data *Data) CreateSubscription(database, rp, name, mode string, destinations []string, weights []int, filter string, tls SubscriptionTLSInfo) error {
 	for _, d := range destinations {
 		if err := validateURL(d); err != nil {
 			return err
 		}
 	}
+	if err := validateWeights(mode, destinations, weights); err != nil {
+		return err
+	}
+	if filter != "" {
+		if _, err := cnosql.ParseSubscriptionFilter(filter); err != nil {
+			return ErrInvalidSubscriptionFilter(filter, err)
+		}
+	}
 
 	rpi, err := data.RetentionPolicy(database, rp)
 	if err != nil {
@@ -871,6 +1177,9 @@ func (data *Data) CreateSubscription(database, rp, name, mode string, destinatio
 		Name:         name,
 		Mode:         mode,
 		Destinations: destinations,
+		Weights:      weights,
+		Filter:       filter,
+		TLS:          tls,
 	})
 
 	return nil
@@ -903,6 +1212,29 @@ func (data *Data) user(username string) *UserInfo {
 	return nil
 }
 
+// authUser returns the user with the privileges of its roles resolved for
+// authorization, or nil if the user doesn't exist. Users with roles are
+// returned as a copy so the stored user is left untouched.
+func (data *Data) authUser(username string) *UserInfo {
+	ui := data.user(username)
+	if ui == nil || len(ui.Roles) == 0 {
+		return ui
+	}
+
+	other := ui.clone()
+	other.rolePrivileges = make(map[string]cnosql.Privilege)
+	for _, name := range ui.Roles {
+		ri := data.role(name)
+		if ri == nil {
+			continue
+		}
+		for db, p := range ri.Privileges {
+			other.rolePrivileges[db] = unionPrivileges(other.rolePrivileges[db], p)
+		}
+	}
+	return &other
+}
+
 // User returns a user by username.
 func (data *Data) User(username string) User {
 	u := data.user(username)
@@ -913,8 +1245,8 @@ func (data *Data) User(username string) User {
 	return u
 }
 
-// CreateUser creates a new user.
-func (data *Data) CreateUser(name, hash string, admin bool) error {
+// CreateUser creates a new user created at created.
+func (data *Data) CreateUser(name, hash string, admin bool, created time.Time) error {
 	// Ensure the user doesn't already exist.
 	if name == "" {
 		return ErrUsernameRequired
@@ -924,9 +1256,10 @@ func (data *Data) CreateUser(name, hash string, admin bool) error {
 
 	// Append new user.
 	data.Users = append(data.Users, UserInfo{
-		Name:  name,
-		Hash:  hash,
-		Admin: admin,
+		Name:      name,
+		Hash:      hash,
+		Admin:     admin,
+		CreatedAt: created,
 	})
 
 	// We know there is now at least one admin user.
@@ -944,6 +1277,15 @@ func (data *Data) DropUser(name string) error {
 			wasAdmin := data.Users[i].Admin
 			data.Users = append(data.Users[:i], data.Users[i+1:]...)
 
+			// The tokens of the user go away with it.
+			tokens := data.Tokens[:0]
+			for _, ti := range data.Tokens {
+				if ti.User != name {
+					tokens = append(tokens, ti)
+				}
+			}
+			data.Tokens = tokens
+
 			// Maybe we dropped the only admin user?
 			if wasAdmin {
 				data.adminUserExists = data.hasAdminUser()
@@ -959,6 +1301,9 @@ func (data *Data) DropUser(name string) error {
 func (data *Data) UpdateUser(name, hash string) error {
 	for i := range data.Users {
 		if data.Users[i].Name == name {
+			if data.Users[i].External {
+				return ErrUserExternal
+			}
 			data.Users[i].Hash = hash
 			return nil
 		}
@@ -966,6 +1311,27 @@ func (data *Data) UpdateUser(name, hash string) error {
 	return ErrUserNotFound
 }
 
+// SetUserLastLogin records the last time a user authenticated.
+func (data *Data) SetUserLastLogin(name string, t time.Time) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+	ui.LastLogin = t
+	return nil
+}
+
+// RehashUser replaces the password hash of a user with hash if it is still
+// old. It returns false if the user is gone or its password changed.
+func (data *Data) RehashUser(name, old, hash string) bool {
+	ui := data.user(name)
+	if ui == nil || ui.Hash != old {
+		return false
+	}
+	ui.Hash = hash
+	return true
+}
+
 // SetPrivilege sets a privilege for a user on a database.
 func (data *Data) SetPrivilege(name, database string, p cnosql.Privilege) error {
 	ui := data.user(name)
@@ -985,104 +1351,529 @@ func (data *Data) SetPrivilege(name, database string, p cnosql.Privilege) error
 	return nil
 }
 
-// SetAdminPrivilege sets the admin privilege for a user.
-func (data *Data) SetAdminPrivilege(name string, admin bool) error {
+// SetPrivilegeOnRP sets a privilege for a user on a retention policy of a
+// database. Setting NoPrivileges removes it.
+func (data *Data) SetPrivilegeOnRP(name, database, rp string, p cnosql.Privilege) error {
 	ui := data.user(name)
 	if ui == nil {
 		return ErrUserNotFound
 	}
 
-	ui.Admin = admin
-
-	// We could have promoted or revoked the only admin. Check if an admin
-	// user exists.
-	data.adminUserExists = data.hasAdminUser()
-	return nil
-}
-
-// AdminUserExists returns true if an admin user exists.
-func (data Data) AdminUserExists() bool {
-	return data.adminUserExists
-}
+	di := data.Database(database)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	} else if di.RetentionPolicy(rp) == nil {
+		return cnosdb.ErrRetentionPolicyNotFound(rp)
+	}
 
-// UserPrivileges gets the privileges for a user.
-func (data *Data) UserPrivileges(name string) (map[string]cnosql.Privilege, error) {
-	ui := data.user(name)
-	if ui == nil {
-		return nil, ErrUserNotFound
+	ui.RetentionPolicyPrivileges = removeRetentionPolicyPrivilege(ui.RetentionPolicyPrivileges, database, rp)
+	if p != cnosql.NoPrivileges {
+		ui.RetentionPolicyPrivileges = append(ui.RetentionPolicyPrivileges, RetentionPolicyPrivilege{
+			Database:        database,
+			RetentionPolicy: rp,
+			Privilege:       p,
+		})
 	}
 
-	return ui.Privileges, nil
+	return nil
 }
 
-// UserPrivilege gets the privilege for a user on a database.
-func (data *Data) UserPrivilege(name, database string) (*cnosql.Privilege, error) {
+// UserPrivilegeOnRP gets the privilege for a user on a retention policy of a database.
+func (data *Data) UserPrivilegeOnRP(name, database, rp string) (*cnosql.Privilege, error) {
 	ui := data.user(name)
 	if ui == nil {
 		return nil, ErrUserNotFound
 	}
 
-	for db, p := range ui.Privileges {
-		if db == database {
-			return &p, nil
+	for _, p := range ui.RetentionPolicyPrivileges {
+		if p.Database == database && p.RetentionPolicy == rp {
+			return &p.Privilege, nil
 		}
 	}
 
 	return cnosql.NewPrivilege(cnosql.NoPrivileges), nil
 }
 
-// Clone returns a copy of data with a new version.
-func (data *Data) Clone() *Data {
-	other := *data
-
-	// Copy nodes.
-	if data.DataNodes != nil {
-		other.DataNodes = make([]NodeInfo, len(data.DataNodes))
-		for i := range data.DataNodes {
-			other.DataNodes[i] = data.DataNodes[i].clone()
+// removeRetentionPolicyPrivilege returns a without the privilege on the
+// retention policy rp of database, or on all of its retention policies if rp
+// is empty.
+func removeRetentionPolicyPrivilege(a []RetentionPolicyPrivilege, database, rp string) []RetentionPolicyPrivilege {
+	var other []RetentionPolicyPrivilege
+	for _, p := range a {
+		if p.Database != database || (rp != "" && p.RetentionPolicy != rp) {
+			other = append(other, p)
 		}
 	}
+	return other
+}
 
-	if data.MetaNodes != nil {
-		other.MetaNodes = make([]NodeInfo, len(data.MetaNodes))
-		for i := range data.MetaNodes {
-			other.MetaNodes[i] = data.MetaNodes[i].clone()
-		}
+// SetMeasurementPrivilege sets a privilege for a user on the measurements of a
+// database selected by mp, replacing the privilege previously set on the same
+// measurements. Setting NoPrivileges removes it.
+func (data *Data) SetMeasurementPrivilege(name string, mp MeasurementPrivilege) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
 	}
 
-	if data.Databases != nil {
-		other.Databases = make([]DatabaseInfo, len(data.Databases))
-		for i := range data.Databases {
-			other.Databases[i] = data.Databases[i].clone()
-		}
+	if data.Database(mp.Database) == nil {
+		return cnosdb.ErrDatabaseNotFound(mp.Database)
 	}
 
-	if data.Users != nil {
-		other.Users = make([]UserInfo, len(data.Users))
-		for i := range data.Users {
-			other.Users[i] = data.Users[i].clone()
-		}
+	if err := mp.compile(); err != nil {
+		return err
 	}
 
-	return &other
-}
+	ui.MeasurementPrivileges = removeMeasurementPrivileges(ui.MeasurementPrivileges, mp.sameMeasurements)
+	if mp.Privilege != cnosql.NoPrivileges {
+		ui.MeasurementPrivileges = append(ui.MeasurementPrivileges, mp)
+	}
 
-// marshal serializes data to a protobuf representation.
-func (data *Data) marshal() *internal.Data {
-	pb := &internal.Data{
-		Term:      proto.Uint64(data.Term),
-		Index:     proto.Uint64(data.Index),
-		ClusterID: proto.Uint64(data.ClusterID),
+	return nil
+}
 
-		MaxNodeID:       proto.Uint64(data.MaxNodeID),
-		MaxShardGroupID: proto.Uint64(data.MaxShardGroupID),
-		MaxShardID:      proto.Uint64(data.MaxShardID),
+// UserMeasurementPrivileges gets the measurement privileges for a user.
+func (data *Data) UserMeasurementPrivileges(name string) ([]MeasurementPrivilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
 	}
 
-	pb.DataNodes = make([]*internal.NodeInfo, len(data.DataNodes))
-	for i := range data.DataNodes {
-		pb.DataNodes[i] = data.DataNodes[i].marshal()
-	}
+	return ui.MeasurementPrivileges, nil
+}
+
+// removeMeasurementPrivileges returns a without the privileges fn returns true for.
+func removeMeasurementPrivileges(a []MeasurementPrivilege, fn func(mp *MeasurementPrivilege) bool) []MeasurementPrivilege {
+	var other []MeasurementPrivilege
+	for i := range a {
+		if !fn(&a[i]) {
+			other = append(other, a[i])
+		}
+	}
+	return other
+}
+
+// SetAdminPrivilege sets the admin privilege for a user.
+func (data *Data) SetAdminPrivilege(name string, admin bool) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	ui.Admin = admin
+
+	// We could have promoted or revoked the only admin. Check if an admin
+	// user exists.
+	data.adminUserExists = data.hasAdminUser()
+	return nil
+}
+
+// SetExternalUser ensures a user authenticated by an external provider
+// exists, creating it without a password if create is set. The admin
+// privilege of externally managed users is set to admin unless it is nil.
+// Local users are never taken over by the provider: ErrUserNotExternal is
+// returned if the user exists but isn't externally managed.
+func (data *Data) SetExternalUser(name string, admin *bool, create bool) error {
+	ui := data.user(name)
+	if ui == nil {
+		if !create {
+			return ErrUserNotFound
+		}
+		if err := data.CreateUser(name, "", admin != nil && *admin, time.Now().UTC()); err != nil {
+			return err
+		}
+		data.user(name).External = true
+		return nil
+	} else if !ui.External {
+		return ErrUserNotExternal
+	}
+
+	if admin != nil && ui.Admin != *admin {
+		return data.SetAdminPrivilege(name, *admin)
+	}
+	return nil
+}
+
+// externalUserInSync returns true if SetExternalUser wouldn't change the user.
+func (data *Data) externalUserInSync(name string, admin *bool) bool {
+	ui := data.user(name)
+	return ui != nil && ui.External && (admin == nil || ui.Admin == *admin)
+}
+
+// SetUserLocked locks or unlocks a user. Locking the last unlocked admin
+// user is refused since nobody could administer the instance anymore.
+func (data *Data) SetUserLocked(name string, locked bool) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	if locked && ui.Admin && !ui.Locked {
+		n := 0
+		for i := range data.Users {
+			if data.Users[i].Admin && !data.Users[i].Locked {
+				n++
+			}
+		}
+		if n == 1 {
+			return ErrLastAdminLocked
+		}
+	}
+
+	ui.Locked = locked
+	return nil
+}
+
+// UserQueryLimitsUpdate represents the query limits to change on a user.
+type UserQueryLimitsUpdate struct {
+	MaxConcurrentQueries *int64
+	MaxQueriesPerMinute  *int64
+	Priority             *query.Priority
+}
+
+// UpdateUserQueryLimits updates the query limits of a user.
+func (data *Data) UpdateUserQueryLimits(name string, u *UserQueryLimitsUpdate) error {
+	if (u.MaxConcurrentQueries != nil && *u.MaxConcurrentQueries < 0) ||
+		(u.MaxQueriesPerMinute != nil && *u.MaxQueriesPerMinute < 0) {
+		return ErrUserQueryLimitNegative
+	}
+
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	if u.MaxConcurrentQueries != nil {
+		ui.MaxConcurrentQueries = *u.MaxConcurrentQueries
+	}
+	if u.MaxQueriesPerMinute != nil {
+		ui.MaxQueriesPerMinute = *u.MaxQueriesPerMinute
+	}
+	if u.Priority != nil {
+		ui.QueryPriority = *u.Priority
+	}
+	return nil
+}
+
+// SetUserDefaultDatabase sets the database and retention policy used for
+// the requests of a user that don't specify any. An empty database clears
+// the defaults; an empty retention policy uses the default retention policy
+// of the database.
+func (data *Data) SetUserDefaultDatabase(name, database, rp string) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	if database == "" {
+		if rp != "" {
+			return ErrRetentionPolicyRequiresDatabase
+		}
+	} else if di := data.Database(database); di == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	} else if rp != "" && di.RetentionPolicy(rp) == nil {
+		return cnosdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	ui.DefaultDatabase, ui.DefaultRetentionPolicy = database, rp
+	return nil
+}
+
+// AdminUserExists returns true if an admin user exists.
+func (data Data) AdminUserExists() bool {
+	return data.adminUserExists
+}
+
+// UserPrivileges gets the privileges for a user.
+func (data *Data) UserPrivileges(name string) (map[string]cnosql.Privilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return ui.Privileges, nil
+}
+
+// UserPrivilege gets the privilege for a user on a database.
+func (data *Data) UserPrivilege(name, database string) (*cnosql.Privilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	for db, p := range ui.Privileges {
+		if db == database {
+			return &p, nil
+		}
+	}
+
+	return cnosql.NewPrivilege(cnosql.NoPrivileges), nil
+}
+
+func (data *Data) role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	return data.role(name)
+}
+
+// CreateRole creates a new role.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.role(name) != nil {
+		return ErrRoleExists
+	}
+
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	return nil
+}
+
+// DropRole removes an existing role by name and revokes it from all users.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+
+			for j := range data.Users {
+				data.Users[j].Roles = removeRole(data.Users[j].Roles, name)
+			}
+			return nil
+		}
+	}
+	return ErrRoleNotFound
+}
+
+// SetRolePrivilege sets a privilege for a role on a database.
+func (data *Data) SetRolePrivilege(name, database string, p cnosql.Privilege) error {
+	ri := data.role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	if data.Database(database) == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	if ri.Privileges == nil {
+		ri.Privileges = make(map[string]cnosql.Privilege)
+	}
+	ri.Privileges[database] = p
+
+	return nil
+}
+
+// RolePrivileges gets the privileges for a role.
+func (data *Data) RolePrivileges(name string) (map[string]cnosql.Privilege, error) {
+	ri := data.role(name)
+	if ri == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	return ri.Privileges, nil
+}
+
+// GrantRole grants a role to a user. Granting a role the user already has
+// is a no-op.
+func (data *Data) GrantRole(username, role string) error {
+	ui := data.user(username)
+	if ui == nil {
+		return ErrUserNotFound
+	} else if data.role(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	for _, name := range ui.Roles {
+		if name == role {
+			return nil
+		}
+	}
+	ui.Roles = append(ui.Roles, role)
+	return nil
+}
+
+// RevokeRole revokes a role from a user. Revoking a role the user doesn't
+// have is a no-op.
+func (data *Data) RevokeRole(username, role string) error {
+	ui := data.user(username)
+	if ui == nil {
+		return ErrUserNotFound
+	} else if data.role(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	ui.Roles = removeRole(ui.Roles, role)
+	return nil
+}
+
+// removeRole returns roles without name.
+func removeRole(roles []string, name string) []string {
+	for i, r := range roles {
+		if r == name {
+			return append(roles[:i:i], roles[i+1:]...)
+		}
+	}
+	return roles
+}
+
+func (data *Data) token(id string) *TokenInfo {
+	for i := range data.Tokens {
+		if data.Tokens[i].ID == id {
+			return &data.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// Token returns a token by id.
+func (data *Data) Token(id string) *TokenInfo {
+	return data.token(id)
+}
+
+// CreateToken adds a token for an existing user.
+func (data *Data) CreateToken(ti TokenInfo) error {
+	if data.user(ti.User) == nil {
+		return ErrUserNotFound
+	} else if ti.Privilege != cnosql.NoPrivileges && data.Database(ti.Database) == nil {
+		return cnosdb.ErrDatabaseNotFound(ti.Database)
+	} else if data.token(ti.ID) != nil {
+		return ErrTokenExists
+	}
+
+	data.Tokens = append(data.Tokens, ti)
+	return nil
+}
+
+// DropToken removes a token by id.
+func (data *Data) DropToken(id string) error {
+	for i := range data.Tokens {
+		if data.Tokens[i].ID == id {
+			data.Tokens = append(data.Tokens[:i], data.Tokens[i+1:]...)
+			return nil
+		}
+	}
+	return ErrTokenNotFound
+}
+
+// SetTokenLastUsed records the last time a token was used.
+func (data *Data) SetTokenLastUsed(id string, t time.Time) error {
+	ti := data.token(id)
+	if ti == nil {
+		return ErrTokenNotFound
+	}
+	ti.LastUsed = t
+	return nil
+}
+
+// authToken returns the token matching the secret of token and the user it
+// authenticates, restricted to the privileges of the token.
+func (data *Data) authToken(token string, now time.Time) (*UserInfo, *TokenInfo, error) {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return nil, nil, ErrAuthenticate
+	}
+	ti := data.token(token[:i])
+	if ti == nil || !ti.matches(token[i+1:]) {
+		return nil, nil, ErrAuthenticate
+	} else if !ti.Expiry.IsZero() && !now.Before(ti.Expiry) {
+		return nil, nil, ErrTokenExpired
+	}
+
+	ui := data.authUser(ti.User)
+	if ui == nil {
+		return nil, nil, ErrAuthenticate
+	} else if ui.Locked {
+		return nil, nil, ErrUserLocked
+	}
+	other := ui.clone()
+	other.token = ti.ID
+	if ti.Privilege != cnosql.NoPrivileges {
+		other.restrict(ti.Database, ti.Privilege)
+	}
+	return &other, ti, nil
+}
+
+// unionPrivileges returns the privilege granting everything a and b grant.
+func unionPrivileges(a, b cnosql.Privilege) cnosql.Privilege {
+	if a == b || b == cnosql.NoPrivileges {
+		return a
+	} else if a == cnosql.NoPrivileges {
+		return b
+	}
+	return cnosql.AllPrivileges
+}
+
+// Clone returns a copy of data with a new version.
+func (data *Data) Clone() *Data {
+	other := *data
+
+	// Copy nodes.
+	if data.DataNodes != nil {
+		other.DataNodes = make([]NodeInfo, len(data.DataNodes))
+		for i := range data.DataNodes {
+			other.DataNodes[i] = data.DataNodes[i].clone()
+		}
+	}
+
+	if data.MetaNodes != nil {
+		other.MetaNodes = make([]NodeInfo, len(data.MetaNodes))
+		for i := range data.MetaNodes {
+			other.MetaNodes[i] = data.MetaNodes[i].clone()
+		}
+	}
+
+	if data.Databases != nil {
+		other.Databases = make([]DatabaseInfo, len(data.Databases))
+		for i := range data.Databases {
+			other.Databases[i] = data.Databases[i].clone()
+		}
+	}
+
+	if data.Users != nil {
+		other.Users = make([]UserInfo, len(data.Users))
+		for i := range data.Users {
+			other.Users[i] = data.Users[i].clone()
+		}
+	}
+
+	if data.Roles != nil {
+		other.Roles = make([]RoleInfo, len(data.Roles))
+		for i := range data.Roles {
+			other.Roles[i] = data.Roles[i].clone()
+		}
+	}
+
+	if data.Tokens != nil {
+		other.Tokens = make([]TokenInfo, len(data.Tokens))
+		copy(other.Tokens, data.Tokens)
+	}
+
+	return &other
+}
+
+// marshal serializes data to a protobuf representation.
+func (data *Data) marshal() *internal.Data {
+	pb := &internal.Data{
+		Term:      proto.Uint64(data.Term),
+		Index:     proto.Uint64(data.Index),
+		ClusterID: proto.Uint64(data.ClusterID),
+
+		MaxNodeID:       proto.Uint64(data.MaxNodeID),
+		MaxShardGroupID: proto.Uint64(data.MaxShardGroupID),
+		MaxShardID:      proto.Uint64(data.MaxShardID),
+	}
+
+	pb.DataNodes = make([]*internal.NodeInfo, len(data.DataNodes))
+	for i := range data.DataNodes {
+		pb.DataNodes[i] = data.DataNodes[i].marshal()
+	}
 
 	pb.MetaNodes = make([]*internal.NodeInfo, len(data.MetaNodes))
 	for i := range data.MetaNodes {
@@ -1099,6 +1890,16 @@ func (data *Data) marshal() *internal.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Roles = make([]*internal.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
+	pb.Tokens = make([]*internal.TokenInfo, len(data.Tokens))
+	for i := range data.Tokens {
+		pb.Tokens[i] = data.Tokens[i].marshal()
+	}
+
 	return pb
 }
 
@@ -1132,6 +1933,16 @@ func (data *Data) unmarshal(pb *internal.Data) {
 		data.Users[i].unmarshal(x)
 	}
 
+	data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+	for i, x := range pb.GetRoles() {
+		data.Roles[i].unmarshal(x)
+	}
+
+	data.Tokens = make([]TokenInfo, len(pb.GetTokens()))
+	for i, x := range pb.GetTokens() {
+		data.Tokens[i].unmarshal(x)
+	}
+
 	// Exhaustively determine if there is an admin user. The marshalled cache
 	// value may not be correct.
 	data.adminUserExists = data.hasAdminUser()
@@ -1294,13 +2105,35 @@ type NodeInfo struct {
 	ID      uint64
 	Host    string
 	TCPHost string
+
+	// Tags are the node-tags the data node was configured with.
+	Tags map[string]string
+}
+
+// MatchesPlacement returns true if the node has every tag of the selector.
+func (n NodeInfo) MatchesPlacement(selector map[string]string) bool {
+	for k, v := range selector {
+		if tv, ok := n.Tags[k]; !ok || tv != v {
+			return false
+		}
+	}
+	return true
 }
 
 // NodeInfos is a slice of NodeInfo used for sorting
 type NodeInfos []NodeInfo
 
 // clone returns a deep copy of NodeInfo.
-func (n NodeInfo) clone() NodeInfo { return n }
+func (n NodeInfo) clone() NodeInfo {
+	if n.Tags != nil {
+		tags := make(map[string]string, len(n.Tags))
+		for k, v := range n.Tags {
+			tags[k] = v
+		}
+		n.Tags = tags
+	}
+	return n
+}
 
 // Len implements sort.Interface.
 func (n NodeInfos) Len() int { return len(n) }
@@ -1317,6 +2150,10 @@ func (n NodeInfo) marshal() *internal.NodeInfo {
 	pb.ID = proto.Uint64(n.ID)
 	pb.Host = proto.String(n.Host)
 	pb.TCPHost = proto.String(n.TCPHost)
+	for k, v := range n.Tags {
+		pb.Tags = append(pb.Tags, k+"="+v)
+	}
+	sort.Strings(pb.Tags)
 	return pb
 }
 
@@ -1325,6 +2162,16 @@ func (n *NodeInfo) unmarshal(pb *internal.NodeInfo) {
 	n.ID = pb.GetID()
 	n.Host = pb.GetHost()
 	n.TCPHost = pb.GetTCPHost()
+	for _, tag := range pb.GetTags() {
+		if n.Tags == nil {
+			n.Tags = make(map[string]string)
+		}
+		i := strings.Index(tag, "=")
+		if i < 0 {
+			continue
+		}
+		n.Tags[tag[:i]] = tag[i+1:]
+	}
 }
 
 // DatabaseInfo represents information about a database in the system.
@@ -1333,6 +2180,18 @@ type DatabaseInfo struct {
 	DefaultRetentionPolicy string
 	RetentionPolicies      []RetentionPolicyInfo
 	ContinuousQueries      []ContinuousQueryInfo
+
+	// MaxSeriesN is the maximum number of series the database may hold on
+	// a node. Zero means unlimited.
+	MaxSeriesN int64
+
+	// MaxValuesPerTag is the maximum number of values a tag key may have
+	// within a measurement. Zero means unlimited.
+	MaxValuesPerTag int64
+
+	// IndexType is the index new shards of the database are created with.
+	// Empty means the index configured on the server.
+	IndexType string
 }
 
 // RetentionPolicy returns a retention policy by name.
@@ -1414,6 +2273,16 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	for i := range di.ContinuousQueries {
 		pb.ContinuousQueries[i] = di.ContinuousQueries[i].marshal()
 	}
+
+	if di.MaxSeriesN > 0 {
+		pb.MaxSeriesN = proto.Int64(di.MaxSeriesN)
+	}
+	if di.MaxValuesPerTag > 0 {
+		pb.MaxValuesPerTag = proto.Int64(di.MaxValuesPerTag)
+	}
+	if di.IndexType != "" {
+		pb.IndexType = proto.String(di.IndexType)
+	}
 	return pb
 }
 
@@ -1435,6 +2304,10 @@ func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 			di.ContinuousQueries[i].unmarshal(x)
 		}
 	}
+
+	di.MaxSeriesN = pb.GetMaxSeriesN()
+	di.MaxValuesPerTag = pb.GetMaxValuesPerTag()
+	di.IndexType = pb.GetIndexType()
 }
 
 // RetentionPolicySpec represents the specification for a new retention policy.
@@ -1443,6 +2316,13 @@ type RetentionPolicySpec struct {
 	ReplicaN           *int
 	Duration           *time.Duration
 	ShardGroupDuration time.Duration
+	FutureWriteLimit   *time.Duration
+	PastWriteLimit     *time.Duration
+	ColdAfter          *time.Duration
+	Downsample         *DownsampleInfo
+	Placement          *string
+	DeletionDelay      *time.Duration
+	MaxQueryRange      *time.Duration
 }
 
 // NewRetentionPolicyInfo creates a new retention policy info from the specification.
@@ -1461,6 +2341,20 @@ func (s *RetentionPolicySpec) Matches(rpi *RetentionPolicyInfo) bool {
 		return false
 	} else if s.ReplicaN != nil && *s.ReplicaN != rpi.ReplicaN {
 		return false
+	} else if s.FutureWriteLimit != nil && *s.FutureWriteLimit != rpi.FutureWriteLimit {
+		return false
+	} else if s.PastWriteLimit != nil && *s.PastWriteLimit != rpi.PastWriteLimit {
+		return false
+	} else if s.ColdAfter != nil && *s.ColdAfter != rpi.ColdAfter {
+		return false
+	} else if s.Downsample != nil && !s.Downsample.Equal(rpi.Downsample) {
+		return false
+	} else if s.Placement != nil && *s.Placement != rpi.Placement {
+		return false
+	} else if s.DeletionDelay != nil && *s.DeletionDelay != rpi.DeletionDelay {
+		return false
+	} else if s.MaxQueryRange != nil && *s.MaxQueryRange != rpi.MaxQueryRange {
+		return false
 	}
 
 	// Normalise ShardDuration before comparing to any existing retention policy.
@@ -1485,6 +2379,29 @@ func (s *RetentionPolicySpec) marshal() *internal.RetentionPolicySpec {
 	if s.ReplicaN != nil {
 		pb.ReplicaN = proto.Uint32(uint32(*s.ReplicaN))
 	}
+	if s.FutureWriteLimit != nil {
+		pb.FutureWriteLimit = proto.Int64(int64(*s.FutureWriteLimit))
+	}
+	if s.PastWriteLimit != nil {
+		pb.PastWriteLimit = proto.Int64(int64(*s.PastWriteLimit))
+	}
+	if s.ColdAfter != nil {
+		pb.ColdAfter = proto.Int64(int64(*s.ColdAfter))
+	}
+	if s.Downsample != nil {
+		pb.DownsampleCalls = s.Downsample.Calls
+		pb.DownsampleEvery = proto.Int64(int64(s.Downsample.Every))
+		pb.DownsampleInto = proto.String(s.Downsample.RetentionPolicy)
+	}
+	if s.Placement != nil {
+		pb.Placement = proto.String(*s.Placement)
+	}
+	if s.DeletionDelay != nil {
+		pb.DeletionDelay = proto.Int64(int64(*s.DeletionDelay))
+	}
+	if s.MaxQueryRange != nil {
+		pb.MaxQueryRange = proto.Int64(int64(*s.MaxQueryRange))
+	}
 	return pb
 }
 
@@ -1504,6 +2421,37 @@ func (s *RetentionPolicySpec) unmarshal(pb *internal.RetentionPolicySpec) {
 		replicaN := int(pb.GetReplicaN())
 		s.ReplicaN = &replicaN
 	}
+	if pb.FutureWriteLimit != nil {
+		limit := time.Duration(pb.GetFutureWriteLimit())
+		s.FutureWriteLimit = &limit
+	}
+	if pb.PastWriteLimit != nil {
+		limit := time.Duration(pb.GetPastWriteLimit())
+		s.PastWriteLimit = &limit
+	}
+	if pb.ColdAfter != nil {
+		coldAfter := time.Duration(pb.GetColdAfter())
+		s.ColdAfter = &coldAfter
+	}
+	if pb.DownsampleEvery != nil {
+		s.Downsample = &DownsampleInfo{
+			Calls:           pb.GetDownsampleCalls(),
+			Every:           time.Duration(pb.GetDownsampleEvery()),
+			RetentionPolicy: pb.GetDownsampleInto(),
+		}
+	}
+	if pb.Placement != nil {
+		placement := pb.GetPlacement()
+		s.Placement = &placement
+	}
+	if pb.DeletionDelay != nil {
+		delay := time.Duration(pb.GetDeletionDelay())
+		s.DeletionDelay = &delay
+	}
+	if pb.MaxQueryRange != nil {
+		queryRange := time.Duration(pb.GetMaxQueryRange())
+		s.MaxQueryRange = &queryRange
+	}
 }
 
 // MarshalBinary encodes RetentionPolicySpec to a binary format.
@@ -1529,6 +2477,33 @@ type RetentionPolicyInfo struct {
 	ShardGroupDuration time.Duration
 	ShardGroups        []ShardGroupInfo
 	Subscriptions      []SubscriptionInfo
+
+	// FutureWriteLimit and PastWriteLimit bound how far ahead of or behind
+	// the current time a point may be written. Zero means unlimited.
+	FutureWriteLimit time.Duration
+	PastWriteLimit   time.Duration
+
+	// ColdAfter is how long after a shard group ends its shards are moved
+	// to the cold directory. Zero disables the cold tier.
+	ColdAfter time.Duration
+
+	// Downsample, when set, periodically aggregates the retention policy's
+	// data into another retention policy.
+	Downsample *DownsampleInfo
+
+	// Placement restricts shard owners to data nodes with matching tags.
+	// See ParsePlacement for its format.
+	Placement string
+
+	// DeletionDelay is how long the shards of a deleted shard group are kept
+	// before they are removed. Until then the group can be restored with
+	// UndeleteShardGroup.
+	DeletionDelay time.Duration
+
+	// MaxQueryRange is the longest time range a SELECT against the policy
+	// may span. Zero is unlimited. Admin users are not limited, and neither
+	// are the SHOW statements answered from the series index.
+	MaxQueryRange time.Duration
 }
 
 // NewRetentionPolicyInfo returns a new instance of RetentionPolicyInfo
@@ -1554,6 +2529,13 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 		ReplicaN:           rpi.ReplicaN,
 		Duration:           rpi.Duration,
 		ShardGroupDuration: rpi.ShardGroupDuration,
+		FutureWriteLimit:   rpi.FutureWriteLimit,
+		PastWriteLimit:     rpi.PastWriteLimit,
+		ColdAfter:          rpi.ColdAfter,
+		Downsample:         rpi.Downsample,
+		Placement:          rpi.Placement,
+		DeletionDelay:      rpi.DeletionDelay,
+		MaxQueryRange:      rpi.MaxQueryRange,
 	}
 	if spec.Name != "" {
 		rp.Name = spec.Name
@@ -1564,6 +2546,27 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 	if spec.Duration != nil {
 		rp.Duration = *spec.Duration
 	}
+	if spec.FutureWriteLimit != nil {
+		rp.FutureWriteLimit = *spec.FutureWriteLimit
+	}
+	if spec.PastWriteLimit != nil {
+		rp.PastWriteLimit = *spec.PastWriteLimit
+	}
+	if spec.ColdAfter != nil {
+		rp.ColdAfter = *spec.ColdAfter
+	}
+	if spec.Downsample != nil {
+		rp.Downsample = spec.Downsample.clone()
+	}
+	if spec.Placement != nil {
+		rp.Placement = *spec.Placement
+	}
+	if spec.DeletionDelay != nil {
+		rp.DeletionDelay = *spec.DeletionDelay
+	}
+	if spec.MaxQueryRange != nil {
+		rp.MaxQueryRange = *spec.MaxQueryRange
+	}
 	rp.ShardGroupDuration = normalisedShardDuration(spec.ShardGroupDuration, rp.Duration)
 	return rp
 }
@@ -1595,12 +2598,43 @@ func (rpi *RetentionPolicyInfo) ExpiredShardGroups(t time.Time) []*ShardGroupInf
 	return groups
 }
 
+// ColdShardGroups returns the ShardGroups which ended more than ColdAfter
+// before the given time and should be moved to the cold tier.
+func (rpi *RetentionPolicyInfo) ColdShardGroups(t time.Time) []*ShardGroupInfo {
+	var groups = make([]*ShardGroupInfo, 0)
+	if rpi.ColdAfter == 0 {
+		return groups
+	}
+	for i := range rpi.ShardGroups {
+		if rpi.ShardGroups[i].Deleted() {
+			continue
+		}
+		if rpi.ShardGroups[i].EndTime.Add(rpi.ColdAfter).Before(t) {
+			groups = append(groups, &rpi.ShardGroups[i])
+		}
+	}
+	return groups
+}
+
 // DeletedShardGroups returns the ShardGroups which are marked as deleted.
 func (rpi *RetentionPolicyInfo) DeletedShardGroups() []*ShardGroupInfo {
 	var groups = make([]*ShardGroupInfo, 0)
 	for i := range rpi.ShardGroups {
-		if rpi.ShardGroups[i].Deleted() {
-			groups = append(groups, &rpi.ShardGroups[i])
+		if rpi.ShardGroups[i].Deleted() {
+			groups = append(groups, &rpi.ShardGroups[i])
+		}
+	}
+	return groups
+}
+
+// PurgeableShardGroups returns the deleted ShardGroups whose deletion delay
+// has elapsed at the given time, so their shards can be removed.
+func (rpi *RetentionPolicyInfo) PurgeableShardGroups(t time.Time) []*ShardGroupInfo {
+	var groups = make([]*ShardGroupInfo, 0)
+	for i := range rpi.ShardGroups {
+		sgi := &rpi.ShardGroups[i]
+		if sgi.Deleted() && !sgi.PurgeAt(rpi.DeletionDelay).After(t) {
+			groups = append(groups, sgi)
 		}
 	}
 	return groups
@@ -1614,6 +2648,29 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		Duration:           proto.Int64(int64(rpi.Duration)),
 		ShardGroupDuration: proto.Int64(int64(rpi.ShardGroupDuration)),
 	}
+	if rpi.FutureWriteLimit > 0 {
+		pb.FutureWriteLimit = proto.Int64(int64(rpi.FutureWriteLimit))
+	}
+	if rpi.PastWriteLimit > 0 {
+		pb.PastWriteLimit = proto.Int64(int64(rpi.PastWriteLimit))
+	}
+	if rpi.ColdAfter > 0 {
+		pb.ColdAfter = proto.Int64(int64(rpi.ColdAfter))
+	}
+	if rpi.Downsample != nil {
+		pb.DownsampleCalls = rpi.Downsample.Calls
+		pb.DownsampleEvery = proto.Int64(int64(rpi.Downsample.Every))
+		pb.DownsampleInto = proto.String(rpi.Downsample.RetentionPolicy)
+	}
+	if rpi.Placement != "" {
+		pb.Placement = proto.String(rpi.Placement)
+	}
+	if rpi.DeletionDelay > 0 {
+		pb.DeletionDelay = proto.Int64(int64(rpi.DeletionDelay))
+	}
+	if rpi.MaxQueryRange > 0 {
+		pb.MaxQueryRange = proto.Int64(int64(rpi.MaxQueryRange))
+	}
 
 	pb.ShardGroups = make([]*internal.ShardGroupInfo, len(rpi.ShardGroups))
 	for i, sgi := range rpi.ShardGroups {
@@ -1634,6 +2691,19 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 	rpi.ReplicaN = int(pb.GetReplicaN())
 	rpi.Duration = time.Duration(pb.GetDuration())
 	rpi.ShardGroupDuration = time.Duration(pb.GetShardGroupDuration())
+	rpi.FutureWriteLimit = time.Duration(pb.GetFutureWriteLimit())
+	rpi.PastWriteLimit = time.Duration(pb.GetPastWriteLimit())
+	rpi.ColdAfter = time.Duration(pb.GetColdAfter())
+	if pb.DownsampleEvery != nil {
+		rpi.Downsample = &DownsampleInfo{
+			Calls:           pb.GetDownsampleCalls(),
+			Every:           time.Duration(pb.GetDownsampleEvery()),
+			RetentionPolicy: pb.GetDownsampleInto(),
+		}
+	}
+	rpi.Placement = pb.GetPlacement()
+	rpi.DeletionDelay = time.Duration(pb.GetDeletionDelay())
+	rpi.MaxQueryRange = time.Duration(pb.GetMaxQueryRange())
 
 	if len(pb.GetShardGroups()) > 0 {
 		rpi.ShardGroups = make([]ShardGroupInfo, len(pb.GetShardGroups()))
@@ -1659,10 +2729,70 @@ func (rpi RetentionPolicyInfo) clone() RetentionPolicyInfo {
 			other.ShardGroups[i] = rpi.ShardGroups[i].clone()
 		}
 	}
+	other.Downsample = rpi.Downsample.clone()
 
 	return other
 }
 
+// ParsePlacement parses a retention policy placement, a comma-separated list
+// of key=value node tags such as "disk=ssd,zone=a", into a selector. An empty
+// placement returns a nil selector, which matches every node.
+func ParsePlacement(placement string) (map[string]string, error) {
+	if placement == "" {
+		return nil, nil
+	}
+	selector := make(map[string]string)
+	for _, tag := range strings.Split(placement, ",") {
+		i := strings.Index(tag, "=")
+		if i <= 0 {
+			return nil, ErrInvalidPlacement(placement)
+		}
+		k, v := strings.TrimSpace(tag[:i]), strings.TrimSpace(tag[i+1:])
+		if k == "" || v == "" {
+			return nil, ErrInvalidPlacement(placement)
+		}
+		selector[k] = v
+	}
+	return selector, nil
+}
+
+// DownsampleInfo describes how a retention policy's data is periodically
+// aggregated into another retention policy.
+type DownsampleInfo struct {
+	// Calls are the aggregate function calls, e.g. "mean(*)".
+	Calls []string
+	// Every is both the schedule interval and the GROUP BY time interval.
+	Every time.Duration
+	// RetentionPolicy is the target retention policy.
+	RetentionPolicy string
+}
+
+// Equal returns true if d and other describe the same downsampling.
+func (d *DownsampleInfo) Equal(other *DownsampleInfo) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	if d.Every != other.Every || d.RetentionPolicy != other.RetentionPolicy || len(d.Calls) != len(other.Calls) {
+		return false
+	}
+	for i := range d.Calls {
+		if d.Calls[i] != other.Calls[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of d.
+func (d *DownsampleInfo) clone() *DownsampleInfo {
+	if d == nil {
+		return nil
+	}
+	other := *d
+	other.Calls = append([]string(nil), d.Calls...)
+	return &other
+}
+
 // MarshalBinary encodes rpi to a binary format.
 func (rpi *RetentionPolicyInfo) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(rpi.marshal())
@@ -1713,6 +2843,10 @@ type ShardGroupInfo struct {
 	DeletedAt   time.Time
 	Shards      []ShardInfo
 	TruncatedAt time.Time
+
+	// Set if the shards of the deleted shard group were deleted right away
+	// rather than after the deletion delay, so it can't be restored.
+	Purged bool
 }
 
 // ShardGroupInfos implements sort.Interface on []ShardGroupInfo, based
@@ -1759,6 +2893,15 @@ func (sgi *ShardGroupInfo) Deleted() bool {
 	return !sgi.DeletedAt.IsZero()
 }
 
+// PurgeAt returns when the shards of a deleted shard group are removed, given
+// the deletion delay of its retention policy.
+func (sgi *ShardGroupInfo) PurgeAt(delay time.Duration) time.Time {
+	if sgi.Purged {
+		return sgi.DeletedAt
+	}
+	return sgi.DeletedAt.Add(delay)
+}
+
 // Truncated returns true if this ShardGroup has been truncated (no new writes).
 func (sgi *ShardGroupInfo) Truncated() bool {
 	return !sgi.TruncatedAt.IsZero()
@@ -1802,6 +2945,9 @@ func (sgi *ShardGroupInfo) marshal() *internal.ShardGroupInfo {
 	if !sgi.TruncatedAt.IsZero() {
 		pb.TruncatedAt = proto.Int64(MarshalTime(sgi.TruncatedAt))
 	}
+	if sgi.Purged {
+		pb.Purged = proto.Bool(true)
+	}
 
 	pb.Shards = make([]*internal.ShardInfo, len(sgi.Shards))
 	for i := range sgi.Shards {
@@ -1829,6 +2975,7 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 	if pb != nil && pb.TruncatedAt != nil {
 		sgi.TruncatedAt = UnmarshalTime(pb.GetTruncatedAt())
 	}
+	sgi.Purged = pb.GetPurged()
 
 	if len(pb.GetShards()) > 0 {
 		sgi.Shards = make([]ShardInfo, len(pb.GetShards()))
@@ -1917,19 +3064,41 @@ type SubscriptionInfo struct {
 	Name         string
 	Mode         string
 	Destinations []string
+	Weights      []int  // weight of each destination in BALANCE mode, 0 for standbys
+	Filter       string // empty if every point is forwarded
+	TLS          SubscriptionTLSInfo
+}
+
+// SubscriptionTLSInfo holds the files of the TLS settings of a subscription's
+// HTTPS destinations. Empty files use the subscriber service's settings.
+type SubscriptionTLSInfo struct {
+	CA   string // CA certificates verifying the destinations
+	Cert string // client certificate presented to the destinations
+	Key  string // key of the client certificate
 }
 
 // marshal serializes to a protobuf representation.
 func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 	pb := &internal.SubscriptionInfo{
-		Name: proto.String(si.Name),
-		Mode: proto.String(si.Mode),
+		Name:   proto.String(si.Name),
+		Mode:   proto.String(si.Mode),
+		Filter: proto.String(si.Filter),
+		CA:     proto.String(si.TLS.CA),
+		Cert:   proto.String(si.TLS.Cert),
+		Key:    proto.String(si.TLS.Key),
 	}
 
 	pb.Destinations = make([]string, len(si.Destinations))
 	for i := range si.Destinations {
 		pb.Destinations[i] = si.Destinations[i]
 	}
+
+	if len(si.Weights) > 0 {
+		pb.Weights = make([]int64, len(si.Weights))
+		for i, w := range si.Weights {
+			pb.Weights[i] = int64(w)
+		}
+	}
 	return pb
 }
 
@@ -1937,11 +3106,20 @@ func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 func (si *SubscriptionInfo) unmarshal(pb *internal.SubscriptionInfo) {
 	si.Name = pb.GetName()
 	si.Mode = pb.GetMode()
+	si.Filter = pb.GetFilter()
+	si.TLS = SubscriptionTLSInfo{CA: pb.GetCA(), Cert: pb.GetCert(), Key: pb.GetKey()}
 
 	if len(pb.GetDestinations()) > 0 {
 		si.Destinations = make([]string, len(pb.GetDestinations()))
 		copy(si.Destinations, pb.GetDestinations())
 	}
+
+	if len(pb.GetWeights()) > 0 {
+		si.Weights = make([]int, len(pb.GetWeights()))
+		for i, w := range pb.GetWeights() {
+			si.Weights[i] = int(w)
+		}
+	}
 }
 
 // ShardOwner represents a node that owns a shard.
@@ -1970,6 +3148,30 @@ func (so *ShardOwner) unmarshal(pb *internal.ShardOwner) {
 type ContinuousQueryInfo struct {
 	Name  string
 	Query string
+
+	// ResampleEvery and ResampleFor hold the RESAMPLE clause of the query.
+	// Zero means the clause did not set them.
+	ResampleEvery time.Duration
+	ResampleFor   time.Duration
+
+	// Offset delays every execution of the query after its scheduled time.
+	Offset time.Duration
+
+	// Owner is the user who created the query. Blank if authentication
+	// was disabled.
+	Owner string
+}
+
+// parseOptions sets the resample parameters and offset from the query text.
+func (cqi *ContinuousQueryInfo) parseOptions() {
+	stmt, err := cnosql.ParseStatement(cqi.Query)
+	if err != nil {
+		return
+	}
+	if cq, ok := stmt.(*cnosql.CreateContinuousQueryStatement); ok {
+		cqi.ResampleEvery, cqi.ResampleFor = cq.ResampleEvery, cq.ResampleFor
+		cqi.Offset = cq.Offset
+	}
 }
 
 // clone returns a deep copy of cqi.
@@ -1978,8 +3180,12 @@ func (cqi ContinuousQueryInfo) clone() ContinuousQueryInfo { return cqi }
 // marshal serializes to a protobuf representation.
 func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 	return &internal.ContinuousQueryInfo{
-		Name:  proto.String(cqi.Name),
-		Query: proto.String(cqi.Query),
+		Name:          proto.String(cqi.Name),
+		Query:         proto.String(cqi.Query),
+		ResampleEvery: proto.Int64(int64(cqi.ResampleEvery)),
+		ResampleFor:   proto.Int64(int64(cqi.ResampleFor)),
+		Offset:        proto.Int64(int64(cqi.Offset)),
+		Owner:         proto.String(cqi.Owner),
 	}
 }
 
@@ -1987,6 +3193,17 @@ func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Name = pb.GetName()
 	cqi.Query = pb.GetQuery()
+	cqi.Owner = pb.GetOwner()
+
+	// Metadata written before the query options were stored only has
+	// the query text.
+	if pb.ResampleEvery == nil && pb.ResampleFor == nil {
+		cqi.parseOptions()
+		return
+	}
+	cqi.ResampleEvery = time.Duration(pb.GetResampleEvery())
+	cqi.ResampleFor = time.Duration(pb.GetResampleFor())
+	cqi.Offset = time.Duration(pb.GetOffset())
 }
 
 var _ query.FineAuthorizer = (*UserInfo)(nil)
@@ -2004,6 +3221,49 @@ type UserInfo struct {
 
 	// Map of database name to granted privilege.
 	Privileges map[string]cnosql.Privilege
+
+	// Names of the roles granted to the user.
+	Roles []string
+
+	// Privileges granted on measurements rather than whole databases.
+	MeasurementPrivileges []MeasurementPrivilege
+
+	// Privileges granted on retention policies rather than whole databases.
+	RetentionPolicyPrivileges []RetentionPolicyPrivilege
+
+	// Whether the user is locked, i.e. kept but unable to authenticate.
+	Locked bool
+
+	// Maximum number of queries the user may run at once. Zero means unlimited.
+	MaxConcurrentQueries int64
+
+	// Maximum number of queries the user may start per minute. Zero means
+	// unlimited.
+	MaxQueriesPerMinute int64
+
+	// Priority of the queries of the user that don't request one.
+	QueryPriority query.Priority
+
+	// Whether the user is authenticated by an external provider, such as
+	// LDAP, and has no password.
+	External bool
+
+	// Database and retention policy used for requests of the user that
+	// don't specify any.
+	DefaultDatabase        string
+	DefaultRetentionPolicy string
+
+	// When the user was created and last authenticated. Users created
+	// before these were recorded have zero times.
+	CreatedAt time.Time
+	LastLogin time.Time
+
+	// Map of database name to privilege granted through roles. It is only
+	// set on users returned for authorization.
+	rolePrivileges map[string]cnosql.Privilege
+
+	// ID of the API token the user authenticated with, if any.
+	token string
 }
 
 type User interface {
@@ -2016,28 +3276,188 @@ func (u *UserInfo) ID() string {
 	return u.Name
 }
 
+// restrict limits the privileges of the user to at most privilege on database.
+func (ui *UserInfo) restrict(database string, privilege cnosql.Privilege) {
+	p := cnosql.AllPrivileges
+	if !ui.Admin {
+		p = unionPrivileges(ui.Privileges[database], ui.rolePrivileges[database])
+	}
+
+	ui.Admin = false
+	ui.Privileges = nil
+	ui.rolePrivileges = nil
+	if p&privilege != cnosql.NoPrivileges {
+		ui.Privileges = map[string]cnosql.Privilege{database: p & privilege}
+	}
+
+	var mps []MeasurementPrivilege
+	for _, mp := range ui.MeasurementPrivileges {
+		if mp.Database == database && mp.Privilege&privilege != cnosql.NoPrivileges {
+			mp.Privilege &= privilege
+			mps = append(mps, mp)
+		}
+	}
+	ui.MeasurementPrivileges = mps
+
+	var rpps []RetentionPolicyPrivilege
+	for _, rpp := range ui.RetentionPolicyPrivileges {
+		if rpp.Database == database && rpp.Privilege&privilege != cnosql.NoPrivileges {
+			rpp.Privilege &= privilege
+			rpps = append(rpps, rpp)
+		}
+	}
+	ui.RetentionPolicyPrivileges = rpps
+}
+
 // AuthorizeDatabase returns true if the user is authorized for the given privilege on the given database.
 func (ui *UserInfo) AuthorizeDatabase(privilege cnosql.Privilege, database string) bool {
 	if ui.Admin || privilege == cnosql.NoPrivileges {
 		return true
 	}
-	p, ok := ui.Privileges[database]
+	if p, ok := ui.Privileges[database]; ok && (p == privilege || p == cnosql.AllPrivileges) {
+		return true
+	}
+	p, ok := ui.rolePrivileges[database]
 	return ok && (p == privilege || p == cnosql.AllPrivileges)
 }
 
-// AuthorizeSeriesRead is used to limit access per-series (enterprise only)
+// AuthorizeMeasurements returns true if the user is authorized for the given
+// privilege on some retention policies or measurements of the given
+// database. Access to each measurement must then be checked with
+// AuthorizeSeriesRead or AuthorizeSeriesWrite.
+func (ui *UserInfo) AuthorizeMeasurements(privilege cnosql.Privilege, database string) bool {
+	for _, p := range ui.RetentionPolicyPrivileges {
+		if p.Database == database && (p.Privilege == privilege || p.Privilege == cnosql.AllPrivileges) {
+			return true
+		}
+	}
+	for i := range ui.MeasurementPrivileges {
+		mp := &ui.MeasurementPrivileges[i]
+		if mp.Database == database && (mp.Privilege == privilege || mp.Privilege == cnosql.AllPrivileges) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeRetentionPolicyRead returns true if the user is authorized to
+// read some measurements of the retention policy of the given database.
+func (ui *UserInfo) AuthorizeRetentionPolicyRead(database, rp string) bool {
+	if ui.AuthorizeDatabase(cnosql.ReadPrivilege, database) {
+		return true
+	}
+	for _, p := range ui.RetentionPolicyPrivileges {
+		if p.Database == database && p.RetentionPolicy == rp && (p.Privilege == cnosql.ReadPrivilege || p.Privilege == cnosql.AllPrivileges) {
+			return true
+		}
+	}
+	for i := range ui.MeasurementPrivileges {
+		mp := &ui.MeasurementPrivileges[i]
+		if mp.Database == database && (mp.RetentionPolicy == "" || mp.RetentionPolicy == rp) &&
+			(mp.Privilege == cnosql.ReadPrivilege || mp.Privilege == cnosql.AllPrivileges) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeMeasurement returns true if the user is authorized for the given
+// privilege on the measurement in one of the retention policies rps of the
+// given database. Without rps only the privileges that apply to every
+// retention policy are considered.
+func (ui *UserInfo) authorizeMeasurement(privilege cnosql.Privilege, database string, rps []string, measurement []byte) bool {
+	if ui.AuthorizeDatabase(privilege, database) {
+		return true
+	}
+	for _, p := range ui.RetentionPolicyPrivileges {
+		if p.Database == database && containsString(rps, p.RetentionPolicy) && (p.Privilege == privilege || p.Privilege == cnosql.AllPrivileges) {
+			return true
+		}
+	}
+	for i := range ui.MeasurementPrivileges {
+		mp := &ui.MeasurementPrivileges[i]
+		if (mp.RetentionPolicy == "" || containsString(rps, mp.RetentionPolicy)) &&
+			(mp.Privilege == privilege || mp.Privilege == cnosql.AllPrivileges) && mp.Matches(database, measurement) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString returns true if a contains s.
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeSeriesRead returns true if the user is authorized to read the
+// measurement of the given database in every retention policy. Privileges
+// granted on some retention policies only apply through ForRetentionPolicy.
 func (u *UserInfo) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
-	return true
+	return u.authorizeMeasurement(cnosql.ReadPrivilege, database, nil, measurement)
 }
 
-// AuthorizeSeriesWrite is used to limit access per-series (enterprise only)
+// AuthorizeSeriesWrite returns true if the user is authorized to write to the
+// measurement of the given database in every retention policy. Privileges
+// granted on some retention policies only apply through ForRetentionPolicy.
 func (u *UserInfo) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
-	return true
+	return u.authorizeMeasurement(cnosql.WritePrivilege, database, nil, measurement)
 }
 
 // IsOpen is a method on FineAuthorizer to indicate all fine auth is permitted and short circuit some checks.
+// It is false for users restricted to some retention policies or measurements of a database.
 func (u *UserInfo) IsOpen() bool {
-	return true
+	return u.Admin || (len(u.MeasurementPrivileges) == 0 && len(u.RetentionPolicyPrivileges) == 0)
+}
+
+// ForRetentionPolicy returns a fine authorizer checking the series of the
+// retention policy rp with the privileges of the user.
+func (u *UserInfo) ForRetentionPolicy(rp string) query.FineAuthorizer {
+	return u.ForRetentionPolicies([]string{rp})
+}
+
+// ForRetentionPolicies returns a fine authorizer checking series that may
+// belong to any of the retention policies rps, such as those of an index
+// shared by the retention policies. A series is authorized if the user may
+// access it in one of them.
+func (u *UserInfo) ForRetentionPolicies(rps []string) query.FineAuthorizer {
+	if u.IsOpen() {
+		return u
+	}
+	return &retentionPolicyAuthorizer{user: u, rps: rps}
+}
+
+// retentionPolicyAuthorizer authorizes access to the series of some
+// retention policies.
+type retentionPolicyAuthorizer struct {
+	user *UserInfo
+	rps  []string
+}
+
+// AuthorizeSeriesRead returns true if the user is authorized to read the
+// measurement of the retention policies of the given database.
+func (a *retentionPolicyAuthorizer) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
+	return a.user.authorizeMeasurement(cnosql.ReadPrivilege, database, a.rps, measurement)
+}
+
+// AuthorizeSeriesWrite returns true if the user is authorized to write to the
+// measurement of the retention policies of the given database.
+func (a *retentionPolicyAuthorizer) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
+	return a.user.authorizeMeasurement(cnosql.WritePrivilege, database, a.rps, measurement)
+}
+
+// IsOpen returns false, the user is restricted to some retention policies or measurements.
+func (a *retentionPolicyAuthorizer) IsOpen() bool {
+	return false
+}
+
+// ID returns the name of the user.
+func (a *retentionPolicyAuthorizer) ID() string {
+	return a.user.ID()
 }
 
 // AuthorizeUnrestricted allows admins to shortcut access checks.
@@ -2056,6 +3476,21 @@ func (ui UserInfo) clone() UserInfo {
 		}
 	}
 
+	if ui.Roles != nil {
+		other.Roles = make([]string, len(ui.Roles))
+		copy(other.Roles, ui.Roles)
+	}
+
+	if ui.MeasurementPrivileges != nil {
+		other.MeasurementPrivileges = make([]MeasurementPrivilege, len(ui.MeasurementPrivileges))
+		copy(other.MeasurementPrivileges, ui.MeasurementPrivileges)
+	}
+
+	if ui.RetentionPolicyPrivileges != nil {
+		other.RetentionPolicyPrivileges = make([]RetentionPolicyPrivilege, len(ui.RetentionPolicyPrivileges))
+		copy(other.RetentionPolicyPrivileges, ui.RetentionPolicyPrivileges)
+	}
+
 	return other
 }
 
@@ -2066,6 +3501,33 @@ func (ui UserInfo) marshal() *internal.UserInfo {
 		Hash:  proto.String(ui.Hash),
 		Admin: proto.Bool(ui.Admin),
 	}
+	if ui.Locked {
+		pb.Locked = proto.Bool(true)
+	}
+	if ui.MaxConcurrentQueries != 0 {
+		pb.MaxConcurrentQueries = proto.Int64(ui.MaxConcurrentQueries)
+	}
+	if ui.MaxQueriesPerMinute != 0 {
+		pb.MaxQueriesPerMinute = proto.Int64(ui.MaxQueriesPerMinute)
+	}
+	if ui.QueryPriority != query.NormalPriority {
+		pb.QueryPriority = proto.Int32(int32(ui.QueryPriority))
+	}
+	if ui.External {
+		pb.External = proto.Bool(true)
+	}
+	if ui.DefaultDatabase != "" {
+		pb.DefaultDatabase = proto.String(ui.DefaultDatabase)
+	}
+	if ui.DefaultRetentionPolicy != "" {
+		pb.DefaultRetentionPolicy = proto.String(ui.DefaultRetentionPolicy)
+	}
+	if !ui.CreatedAt.IsZero() {
+		pb.CreatedAt = proto.Int64(ui.CreatedAt.UnixNano())
+	}
+	if !ui.LastLogin.IsZero() {
+		pb.LastLogin = proto.Int64(ui.LastLogin.UnixNano())
+	}
 
 	for database, privilege := range ui.Privileges {
 		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
@@ -2074,6 +3536,20 @@ func (ui UserInfo) marshal() *internal.UserInfo {
 		})
 	}
 
+	pb.Roles = ui.Roles
+
+	for i := range ui.MeasurementPrivileges {
+		pb.MeasurementPrivileges = append(pb.MeasurementPrivileges, ui.MeasurementPrivileges[i].marshal())
+	}
+
+	for _, p := range ui.RetentionPolicyPrivileges {
+		pb.RetentionPolicyPrivileges = append(pb.RetentionPolicyPrivileges, &internal.RetentionPolicyPrivilege{
+			Database:        proto.String(p.Database),
+			RetentionPolicy: proto.String(p.RetentionPolicy),
+			Privilege:       proto.Int32(int32(p.Privilege)),
+		})
+	}
+
 	return pb
 }
 
@@ -2082,11 +3558,273 @@ func (ui *UserInfo) unmarshal(pb *internal.UserInfo) {
 	ui.Name = pb.GetName()
 	ui.Hash = pb.GetHash()
 	ui.Admin = pb.GetAdmin()
+	ui.Locked = pb.GetLocked()
+	ui.MaxConcurrentQueries = pb.GetMaxConcurrentQueries()
+	ui.MaxQueriesPerMinute = pb.GetMaxQueriesPerMinute()
+	ui.QueryPriority = query.Priority(pb.GetQueryPriority())
+	ui.External = pb.GetExternal()
+	ui.DefaultDatabase = pb.GetDefaultDatabase()
+	ui.DefaultRetentionPolicy = pb.GetDefaultRetentionPolicy()
+	ui.CreatedAt, ui.LastLogin = time.Time{}, time.Time{}
+	if n := pb.GetCreatedAt(); n != 0 {
+		ui.CreatedAt = time.Unix(0, n).UTC()
+	}
+	if n := pb.GetLastLogin(); n != 0 {
+		ui.LastLogin = time.Unix(0, n).UTC()
+	}
 
 	ui.Privileges = make(map[string]cnosql.Privilege)
 	for _, p := range pb.GetPrivileges() {
 		ui.Privileges[p.GetDatabase()] = cnosql.Privilege(p.GetPrivilege())
 	}
+
+	ui.Roles = pb.GetRoles()
+
+	ui.MeasurementPrivileges = nil
+	for _, x := range pb.GetMeasurementPrivileges() {
+		var mp MeasurementPrivilege
+		mp.unmarshal(x)
+		ui.MeasurementPrivileges = append(ui.MeasurementPrivileges, mp)
+	}
+
+	ui.RetentionPolicyPrivileges = nil
+	for _, x := range pb.GetRetentionPolicyPrivileges() {
+		ui.RetentionPolicyPrivileges = append(ui.RetentionPolicyPrivileges, RetentionPolicyPrivilege{
+			Database:        x.GetDatabase(),
+			RetentionPolicy: x.GetRetentionPolicy(),
+			Privilege:       cnosql.Privilege(x.GetPrivilege()),
+		})
+	}
+}
+
+// RetentionPolicyPrivilege represents a privilege granted on a retention
+// policy of a database.
+type RetentionPolicyPrivilege struct {
+	Database        string
+	RetentionPolicy string
+	Privilege       cnosql.Privilege
+}
+
+// MeasurementPrivilege represents a privilege granted on the measurements of
+// a database, selected by name or by regular expression.
+type MeasurementPrivilege struct {
+	Database string
+
+	// Retention policy of the measurements, or empty for all retention
+	// policies.
+	RetentionPolicy string
+
+	// Name of the measurement, or the regular expression if Regex is set.
+	Measurement string
+	Regex       bool
+
+	Privilege cnosql.Privilege
+
+	re *regexp.Regexp
+}
+
+// Matches returns true if the privilege applies to the measurement of the
+// given database.
+func (mp *MeasurementPrivilege) Matches(database string, measurement []byte) bool {
+	if mp.Database != database {
+		return false
+	} else if mp.Regex {
+		return mp.re != nil && mp.re.Match(measurement)
+	}
+	return mp.Measurement == string(measurement)
+}
+
+// String returns the name of the measurement, or the regular expression in
+// the form /regex/.
+func (mp *MeasurementPrivilege) String() string {
+	if mp.Regex {
+		return (&cnosql.RegexLiteral{Val: mp.re}).String()
+	}
+	return mp.Measurement
+}
+
+// sameMeasurements returns true if other is granted on the same measurements as mp.
+func (mp *MeasurementPrivilege) sameMeasurements(other *MeasurementPrivilege) bool {
+	return mp.Database == other.Database && mp.RetentionPolicy == other.RetentionPolicy &&
+		mp.Measurement == other.Measurement && mp.Regex == other.Regex
+}
+
+// compile compiles the regular expression of the privilege.
+func (mp *MeasurementPrivilege) compile() error {
+	if !mp.Regex {
+		mp.re = nil
+		return nil
+	}
+	re, err := regexp.Compile(mp.Measurement)
+	if err != nil {
+		return err
+	}
+	mp.re = re
+	return nil
+}
+
+// marshal serializes to a protobuf representation.
+func (mp MeasurementPrivilege) marshal() *internal.MeasurementPrivilege {
+	return &internal.MeasurementPrivilege{
+		Database:        proto.String(mp.Database),
+		RetentionPolicy: proto.String(mp.RetentionPolicy),
+		Measurement:     proto.String(mp.Measurement),
+		Regex:           proto.Bool(mp.Regex),
+		Privilege:       proto.Int32(int32(mp.Privilege)),
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (mp *MeasurementPrivilege) unmarshal(pb *internal.MeasurementPrivilege) {
+	mp.Database = pb.GetDatabase()
+	mp.RetentionPolicy = pb.GetRetentionPolicy()
+	mp.Measurement = pb.GetMeasurement()
+	mp.Regex = pb.GetRegex()
+	mp.Privilege = cnosql.Privilege(pb.GetPrivilege())
+
+	// The regex was valid when the privilege was set. An invalid one
+	// matches nothing.
+	_ = mp.compile()
+}
+
+// RoleInfo represents metadata about a role. Users granted the role get its
+// privileges in addition to their own.
+type RoleInfo struct {
+	// Role's name.
+	Name string
+
+	// Map of database name to granted privilege.
+	Privileges map[string]cnosql.Privilege
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+
+	if ri.Privileges != nil {
+		other.Privileges = make(map[string]cnosql.Privilege)
+		for k, v := range ri.Privileges {
+			other.Privileges[k] = v
+		}
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *internal.RoleInfo {
+	pb := &internal.RoleInfo{
+		Name: proto.String(ri.Name),
+	}
+
+	for database, privilege := range ri.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(privilege)),
+		})
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *internal.RoleInfo) {
+	ri.Name = pb.GetName()
+
+	ri.Privileges = make(map[string]cnosql.Privilege)
+	for _, p := range pb.GetPrivileges() {
+		ri.Privileges[p.GetDatabase()] = cnosql.Privilege(p.GetPrivilege())
+	}
+}
+
+// TokenInfo represents metadata about an API token authenticating as a user.
+// Only a hash of the secret of the token is stored.
+type TokenInfo struct {
+	ID   string
+	User string
+	Hash []byte
+
+	// Privilege the token is restricted to on Database. NoPrivileges keeps
+	// all the privileges of the user.
+	Database  string
+	Privilege cnosql.Privilege
+
+	CreatedAt time.Time
+	Expiry    time.Time // zero for tokens that never expire
+	LastUsed  time.Time
+}
+
+// NewToken returns a new token for the user, expiring at expiry unless it is
+// zero, along with the token to authenticate with. The token is
+// "<id>.<secret>" and can't be recovered from the returned TokenInfo.
+func NewToken(username, database string, p cnosql.Privilege, expiry time.Time) (TokenInfo, string, error) {
+	id := make([]byte, 8)
+	if _, err := crand.Read(id); err != nil {
+		return TokenInfo{}, "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := crand.Read(secret); err != nil {
+		return TokenInfo{}, "", err
+	}
+
+	ti := TokenInfo{
+		ID:        hex.EncodeToString(id),
+		User:      username,
+		Database:  database,
+		Privilege: p,
+		CreatedAt: time.Now().UTC(),
+		Expiry:    expiry,
+	}
+	s := hex.EncodeToString(secret)
+	ti.Hash = hashTokenSecret(s)
+	return ti, ti.ID + "." + s, nil
+}
+
+// hashTokenSecret returns the hash of the secret of a token. The secret is
+// random, so unlike passwords it needs neither a salt nor a slow hash.
+func hashTokenSecret(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+// matches returns true if secret is the secret of the token.
+func (ti *TokenInfo) matches(secret string) bool {
+	return subtle.ConstantTimeCompare(hashTokenSecret(secret), ti.Hash) == 1
+}
+
+// marshal serializes to a protobuf representation.
+func (ti TokenInfo) marshal() *internal.TokenInfo {
+	pb := &internal.TokenInfo{
+		ID:        proto.String(ti.ID),
+		User:      proto.String(ti.User),
+		Hash:      ti.Hash,
+		Database:  proto.String(ti.Database),
+		Privilege: proto.Int32(int32(ti.Privilege)),
+		CreatedAt: proto.Int64(ti.CreatedAt.UnixNano()),
+	}
+	if !ti.Expiry.IsZero() {
+		pb.Expiry = proto.Int64(ti.Expiry.UnixNano())
+	}
+	if !ti.LastUsed.IsZero() {
+		pb.LastUsed = proto.Int64(ti.LastUsed.UnixNano())
+	}
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ti *TokenInfo) unmarshal(pb *internal.TokenInfo) {
+	ti.ID = pb.GetID()
+	ti.User = pb.GetUser()
+	ti.Hash = pb.GetHash()
+	ti.Database = pb.GetDatabase()
+	ti.Privilege = cnosql.Privilege(pb.GetPrivilege())
+	ti.CreatedAt = time.Unix(0, pb.GetCreatedAt()).UTC()
+	if n := pb.GetExpiry(); n != 0 {
+		ti.Expiry = time.Unix(0, n).UTC()
+	}
+	if n := pb.GetLastUsed(); n != 0 {
+		ti.LastUsed = time.Unix(0, n).UTC()
+	}
 }
 
 // Lease represents a lease held on a resource.
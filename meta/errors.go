@@ -3,6 +3,8 @@ package meta
 import (
 	"errors"
 	"fmt"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
 )
 
 var (
@@ -48,6 +50,12 @@ var (
 
 	// ErrInvalidName is returned when attempting to create a database or retention policy with an invalid name
 	ErrInvalidName = errors.New("invalid name")
+
+	// ErrDatabaseLimitNegative is returned when setting a negative limit on a database.
+	ErrDatabaseLimitNegative = errors.New("database limits must not be negative")
+
+	// ErrUserQueryLimitNegative is returned when setting a negative query limit on a user.
+	ErrUserQueryLimitNegative = errors.New("user query limits must not be negative")
 )
 
 var (
@@ -90,6 +98,18 @@ var (
 	ErrReplicationFactorTooLow = errors.New("replication factor must be greater than 0")
 )
 
+// ErrInvalidPlacement is returned when a retention policy placement is not a
+// comma-separated list of key=value node tags.
+func ErrInvalidPlacement(placement string) error {
+	return fmt.Errorf("invalid placement %q: expected comma-separated key=value node tags", placement)
+}
+
+// ErrNoPlacementNodes is returned when no data node matches the placement of
+// the retention policy a shard group is being created for.
+func ErrNoPlacementNodes(rp, placement string) error {
+	return fmt.Errorf("no data nodes match placement %q of retention policy %s", placement, rp)
+}
+
 var (
 	// ErrShardGroupExists is returned when creating an already existing shard group.
 	ErrShardGroupExists = errors.New("shard group already exists")
@@ -97,6 +117,19 @@ var (
 	// ErrShardGroupNotFound is returned when mutating a shard group that doesn't exist.
 	ErrShardGroupNotFound = errors.New("shard group not found")
 
+	// ErrShardGroupNotDeleted is returned when restoring a shard group that
+	// isn't deleted.
+	ErrShardGroupNotDeleted = errors.New("shard group is not deleted")
+
+	// ErrShardGroupPurged is returned when restoring a deleted shard group
+	// whose shards have been, or are being, removed.
+	ErrShardGroupPurged = errors.New("shard group deletion delay has elapsed")
+
+	// ErrShardGroupShardsDeleted is returned when restoring a deleted shard
+	// group whose shards were deleted right away, e.g. by PURGE DATA or
+	// DROP SHARD.
+	ErrShardGroupShardsDeleted = errors.New("shards of the shard group have been deleted")
+
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
@@ -120,7 +153,17 @@ var (
 
 // ErrInvalidSubscriptionURL is returned when the subscription's destination URL is invalid.
 func ErrInvalidSubscriptionURL(url string) error {
-	return fmt.Errorf("invalid subscription URL: %s", url)
+	return fmt.Errorf("invalid subscription URL: %s", cnosql.SanitizeURL(url))
+}
+
+// ErrInvalidSubscriptionWeights is returned when the weights of a subscription's destinations are invalid.
+func ErrInvalidSubscriptionWeights(reason string) error {
+	return fmt.Errorf("invalid subscription weights: %s", reason)
+}
+
+// ErrInvalidSubscriptionFilter is returned when the subscription's filter is invalid.
+func ErrInvalidSubscriptionFilter(filter string, err error) error {
+	return fmt.Errorf("invalid subscription filter %s: %s", filter, err)
 }
 
 var (
@@ -133,6 +176,46 @@ var (
 	// ErrUsernameRequired is returned when creating a user without a username.
 	ErrUsernameRequired = errors.New("username required")
 
+	// ErrUserLocked is returned when authenticating as a locked user.
+	ErrUserLocked = errors.New("user is locked")
+
+	// ErrUserExternal is returned when setting the password of a user
+	// managed by an external authentication provider.
+	ErrUserExternal = errors.New("cannot set the password of an externally managed user")
+
+	// ErrUserNotExternal is returned when a user authenticated by an
+	// external provider has the name of a user managed locally.
+	ErrUserNotExternal = errors.New("user is not externally managed")
+
+	// ErrRetentionPolicyRequiresDatabase is returned when setting a default
+	// retention policy on a user without a default database.
+	ErrRetentionPolicyRequiresDatabase = errors.New("default retention policy requires a default database")
+
+	// ErrLastAdminLocked is returned when locking the last unlocked admin user.
+	ErrLastAdminLocked = errors.New("cannot lock the last unlocked admin user")
+
 	// ErrAuthenticate is returned when authentication fails.
 	ErrAuthenticate = errors.New("authentication failed")
 )
+
+var (
+	// ErrRoleExists is returned when creating an already existing role.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleNotFound is returned when mutating a role that doesn't exist.
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrRoleNameRequired is returned when creating a role without a name.
+	ErrRoleNameRequired = errors.New("role name required")
+)
+
+var (
+	// ErrTokenExists is returned when creating a token with the id of an existing one.
+	ErrTokenExists = errors.New("token already exists")
+
+	// ErrTokenNotFound is returned when mutating a token that doesn't exist.
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenExpired is returned when authenticating with an expired token.
+	ErrTokenExpired = errors.New("token expired")
+)
@@ -138,7 +138,7 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 }
 
 func (Command_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{12, 0}
+	return fileDescriptor_59b0956366e72083, []int{16, 0}
 }
 
 type Data struct {
@@ -152,11 +152,13 @@ type Data struct {
 	MaxShardGroupID *uint64         `protobuf:"varint,8,req,name=MaxShardGroupID" json:"MaxShardGroupID,omitempty"`
 	MaxShardID      *uint64         `protobuf:"varint,9,req,name=MaxShardID" json:"MaxShardID,omitempty"`
 	// added for 0.10.0
-	DataNodes            []*NodeInfo `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
-	MetaNodes            []*NodeInfo `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	DataNodes            []*NodeInfo  `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
+	MetaNodes            []*NodeInfo  `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Roles                []*RoleInfo  `protobuf:"bytes,12,rep,name=Roles" json:"Roles,omitempty"`
+	Tokens               []*TokenInfo `protobuf:"bytes,13,rep,name=Tokens" json:"Tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *Data) Reset()         { *m = Data{} }
@@ -260,10 +262,25 @@ func (m *Data) GetMetaNodes() []*NodeInfo {
 	return nil
 }
 
+func (m *Data) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Data) GetTokens() []*TokenInfo {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
 type NodeInfo struct {
 	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
 	TCPHost              *string  `protobuf:"bytes,3,opt,name=TCPHost" json:"TCPHost,omitempty"`
+	Tags                 []string `protobuf:"bytes,4,rep,name=Tags" json:"Tags,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -314,11 +331,21 @@ func (m *NodeInfo) GetTCPHost() string {
 	return ""
 }
 
+func (m *NodeInfo) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
 type DatabaseInfo struct {
 	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
 	RetentionPolicies      []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
 	ContinuousQueries      []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	MaxSeriesN             *int64                 `protobuf:"varint,5,opt,name=MaxSeriesN" json:"MaxSeriesN,omitempty"`
+	MaxValuesPerTag        *int64                 `protobuf:"varint,6,opt,name=MaxValuesPerTag" json:"MaxValuesPerTag,omitempty"`
+	IndexType              *string                `protobuf:"bytes,7,opt,name=IndexType" json:"IndexType,omitempty"`
 	XXX_NoUnkeyedLiteral   struct{}               `json:"-"`
 	XXX_unrecognized       []byte                 `json:"-"`
 	XXX_sizecache          int32                  `json:"-"`
@@ -376,11 +403,41 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetMaxSeriesN() int64 {
+	if m != nil && m.MaxSeriesN != nil {
+		return *m.MaxSeriesN
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetMaxValuesPerTag() int64 {
+	if m != nil && m.MaxValuesPerTag != nil {
+		return *m.MaxValuesPerTag
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetIndexType() string {
+	if m != nil && m.IndexType != nil {
+		return *m.IndexType
+	}
+	return ""
+}
+
 type RetentionPolicySpec struct {
 	Name                 *string  `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration             *int64   `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
 	ShardGroupDuration   *int64   `protobuf:"varint,3,opt,name=ShardGroupDuration" json:"ShardGroupDuration,omitempty"`
 	ReplicaN             *uint32  `protobuf:"varint,4,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
+	FutureWriteLimit     *int64   `protobuf:"varint,5,opt,name=FutureWriteLimit" json:"FutureWriteLimit,omitempty"`
+	PastWriteLimit       *int64   `protobuf:"varint,6,opt,name=PastWriteLimit" json:"PastWriteLimit,omitempty"`
+	ColdAfter            *int64   `protobuf:"varint,7,opt,name=ColdAfter" json:"ColdAfter,omitempty"`
+	DownsampleCalls      []string `protobuf:"bytes,8,rep,name=DownsampleCalls" json:"DownsampleCalls,omitempty"`
+	DownsampleEvery      *int64   `protobuf:"varint,9,opt,name=DownsampleEvery" json:"DownsampleEvery,omitempty"`
+	DownsampleInto       *string  `protobuf:"bytes,10,opt,name=DownsampleInto" json:"DownsampleInto,omitempty"`
+	Placement            *string  `protobuf:"bytes,11,opt,name=Placement" json:"Placement,omitempty"`
+	DeletionDelay        *int64   `protobuf:"varint,12,opt,name=DeletionDelay" json:"DeletionDelay,omitempty"`
+	MaxQueryRange        *int64   `protobuf:"varint,13,opt,name=MaxQueryRange" json:"MaxQueryRange,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -438,6 +495,69 @@ func (m *RetentionPolicySpec) GetReplicaN() uint32 {
 	return 0
 }
 
+func (m *RetentionPolicySpec) GetFutureWriteLimit() int64 {
+	if m != nil && m.FutureWriteLimit != nil {
+		return *m.FutureWriteLimit
+	}
+	return 0
+}
+
+func (m *RetentionPolicySpec) GetPastWriteLimit() int64 {
+	if m != nil && m.PastWriteLimit != nil {
+		return *m.PastWriteLimit
+	}
+	return 0
+}
+
+func (m *RetentionPolicySpec) GetColdAfter() int64 {
+	if m != nil && m.ColdAfter != nil {
+		return *m.ColdAfter
+	}
+	return 0
+}
+
+func (m *RetentionPolicySpec) GetDownsampleCalls() []string {
+	if m != nil {
+		return m.DownsampleCalls
+	}
+	return nil
+}
+
+func (m *RetentionPolicySpec) GetDownsampleEvery() int64 {
+	if m != nil && m.DownsampleEvery != nil {
+		return *m.DownsampleEvery
+	}
+	return 0
+}
+
+func (m *RetentionPolicySpec) GetDownsampleInto() string {
+	if m != nil && m.DownsampleInto != nil {
+		return *m.DownsampleInto
+	}
+	return ""
+}
+
+func (m *RetentionPolicySpec) GetPlacement() string {
+	if m != nil && m.Placement != nil {
+		return *m.Placement
+	}
+	return ""
+}
+
+func (m *RetentionPolicySpec) GetDeletionDelay() int64 {
+	if m != nil && m.DeletionDelay != nil {
+		return *m.DeletionDelay
+	}
+	return 0
+}
+
+func (m *RetentionPolicySpec) GetMaxQueryRange() int64 {
+	if m != nil && m.MaxQueryRange != nil {
+		return *m.MaxQueryRange
+	}
+	return 0
+}
+
 type RetentionPolicyInfo struct {
 	Name                 *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Duration             *int64              `protobuf:"varint,2,req,name=Duration" json:"Duration,omitempty"`
@@ -445,6 +565,15 @@ type RetentionPolicyInfo struct {
 	ReplicaN             *uint32             `protobuf:"varint,4,req,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroups          []*ShardGroupInfo   `protobuf:"bytes,5,rep,name=ShardGroups" json:"ShardGroups,omitempty"`
 	Subscriptions        []*SubscriptionInfo `protobuf:"bytes,6,rep,name=Subscriptions" json:"Subscriptions,omitempty"`
+	FutureWriteLimit     *int64              `protobuf:"varint,7,opt,name=FutureWriteLimit" json:"FutureWriteLimit,omitempty"`
+	PastWriteLimit       *int64              `protobuf:"varint,8,opt,name=PastWriteLimit" json:"PastWriteLimit,omitempty"`
+	ColdAfter            *int64              `protobuf:"varint,9,opt,name=ColdAfter" json:"ColdAfter,omitempty"`
+	DownsampleCalls      []string            `protobuf:"bytes,10,rep,name=DownsampleCalls" json:"DownsampleCalls,omitempty"`
+	DownsampleEvery      *int64              `protobuf:"varint,11,opt,name=DownsampleEvery" json:"DownsampleEvery,omitempty"`
+	DownsampleInto       *string             `protobuf:"bytes,12,opt,name=DownsampleInto" json:"DownsampleInto,omitempty"`
+	Placement            *string             `protobuf:"bytes,13,opt,name=Placement" json:"Placement,omitempty"`
+	DeletionDelay        *int64              `protobuf:"varint,14,opt,name=DeletionDelay" json:"DeletionDelay,omitempty"`
+	MaxQueryRange        *int64              `protobuf:"varint,15,opt,name=MaxQueryRange" json:"MaxQueryRange,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
 	XXX_sizecache        int32               `json:"-"`
@@ -516,6 +645,69 @@ func (m *RetentionPolicyInfo) GetSubscriptions() []*SubscriptionInfo {
 	return nil
 }
 
+func (m *RetentionPolicyInfo) GetFutureWriteLimit() int64 {
+	if m != nil && m.FutureWriteLimit != nil {
+		return *m.FutureWriteLimit
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetPastWriteLimit() int64 {
+	if m != nil && m.PastWriteLimit != nil {
+		return *m.PastWriteLimit
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetColdAfter() int64 {
+	if m != nil && m.ColdAfter != nil {
+		return *m.ColdAfter
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetDownsampleCalls() []string {
+	if m != nil {
+		return m.DownsampleCalls
+	}
+	return nil
+}
+
+func (m *RetentionPolicyInfo) GetDownsampleEvery() int64 {
+	if m != nil && m.DownsampleEvery != nil {
+		return *m.DownsampleEvery
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetDownsampleInto() string {
+	if m != nil && m.DownsampleInto != nil {
+		return *m.DownsampleInto
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetPlacement() string {
+	if m != nil && m.Placement != nil {
+		return *m.Placement
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetDeletionDelay() int64 {
+	if m != nil && m.DeletionDelay != nil {
+		return *m.DeletionDelay
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetMaxQueryRange() int64 {
+	if m != nil && m.MaxQueryRange != nil {
+		return *m.MaxQueryRange
+	}
+	return 0
+}
+
 type ShardGroupInfo struct {
 	ID                   *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	StartTime            *int64       `protobuf:"varint,2,req,name=StartTime" json:"StartTime,omitempty"`
@@ -523,6 +715,7 @@ type ShardGroupInfo struct {
 	DeletedAt            *int64       `protobuf:"varint,4,req,name=DeletedAt" json:"DeletedAt,omitempty"`
 	Shards               []*ShardInfo `protobuf:"bytes,5,rep,name=Shards" json:"Shards,omitempty"`
 	TruncatedAt          *int64       `protobuf:"varint,6,opt,name=TruncatedAt" json:"TruncatedAt,omitempty"`
+	Purged               *bool        `protobuf:"varint,7,opt,name=Purged" json:"Purged,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -594,6 +787,13 @@ func (m *ShardGroupInfo) GetTruncatedAt() int64 {
 	return 0
 }
 
+func (m *ShardGroupInfo) GetPurged() bool {
+	if m != nil && m.Purged != nil {
+		return *m.Purged
+	}
+	return false
+}
+
 type ShardInfo struct {
 	ID                   *uint64       `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	OwnerIDs             []uint64      `protobuf:"varint,2,rep,name=OwnerIDs" json:"OwnerIDs,omitempty"` // Deprecated: Do not use.
@@ -653,6 +853,11 @@ type SubscriptionInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode                 *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
 	Destinations         []string `protobuf:"bytes,3,rep,name=Destinations" json:"Destinations,omitempty"`
+	Filter               *string  `protobuf:"bytes,4,opt,name=Filter" json:"Filter,omitempty"`
+	CA                   *string  `protobuf:"bytes,5,opt,name=CA" json:"CA,omitempty"`
+	Cert                 *string  `protobuf:"bytes,6,opt,name=Cert" json:"Cert,omitempty"`
+	Key                  *string  `protobuf:"bytes,7,opt,name=Key" json:"Key,omitempty"`
+	Weights              []int64  `protobuf:"varint,8,rep,name=Weights" json:"Weights,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -703,6 +908,41 @@ func (m *SubscriptionInfo) GetDestinations() []string {
 	return nil
 }
 
+func (m *SubscriptionInfo) GetFilter() string {
+	if m != nil && m.Filter != nil {
+		return *m.Filter
+	}
+	return ""
+}
+
+func (m *SubscriptionInfo) GetCA() string {
+	if m != nil && m.CA != nil {
+		return *m.CA
+	}
+	return ""
+}
+
+func (m *SubscriptionInfo) GetCert() string {
+	if m != nil && m.Cert != nil {
+		return *m.Cert
+	}
+	return ""
+}
+
+func (m *SubscriptionInfo) GetKey() string {
+	if m != nil && m.Key != nil {
+		return *m.Key
+	}
+	return ""
+}
+
+func (m *SubscriptionInfo) GetWeights() []int64 {
+	if m != nil {
+		return m.Weights
+	}
+	return nil
+}
+
 type ShardOwner struct {
 	NodeID               *uint64  `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -744,6 +984,10 @@ func (m *ShardOwner) GetNodeID() uint64 {
 type ContinuousQueryInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Query                *string  `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	ResampleEvery        *int64   `protobuf:"varint,3,opt,name=ResampleEvery" json:"ResampleEvery,omitempty"`
+	ResampleFor          *int64   `protobuf:"varint,4,opt,name=ResampleFor" json:"ResampleFor,omitempty"`
+	Offset               *int64   `protobuf:"varint,5,opt,name=Offset" json:"Offset,omitempty"`
+	Owner                *string  `protobuf:"bytes,6,opt,name=Owner" json:"Owner,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -787,14 +1031,54 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+func (m *ContinuousQueryInfo) GetResampleEvery() int64 {
+	if m != nil && m.ResampleEvery != nil {
+		return *m.ResampleEvery
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetResampleFor() int64 {
+	if m != nil && m.ResampleFor != nil {
+		return *m.ResampleFor
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetOffset() int64 {
+	if m != nil && m.Offset != nil {
+		return *m.Offset
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetOwner() string {
+	if m != nil && m.Owner != nil {
+		return *m.Owner
+	}
+	return ""
+}
+
 type UserInfo struct {
-	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Hash                 *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
-	Admin                *bool            `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
-	Privileges           []*UserPrivilege `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Name                      *string                     `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Hash                      *string                     `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
+	Admin                     *bool                       `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
+	Privileges                []*UserPrivilege            `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
+	Roles                     []string                    `protobuf:"bytes,5,rep,name=Roles" json:"Roles,omitempty"`
+	MeasurementPrivileges     []*MeasurementPrivilege     `protobuf:"bytes,6,rep,name=MeasurementPrivileges" json:"MeasurementPrivileges,omitempty"`
+	RetentionPolicyPrivileges []*RetentionPolicyPrivilege `protobuf:"bytes,7,rep,name=RetentionPolicyPrivileges" json:"RetentionPolicyPrivileges,omitempty"`
+	Locked                    *bool                       `protobuf:"varint,8,opt,name=Locked" json:"Locked,omitempty"`
+	MaxConcurrentQueries      *int64                      `protobuf:"varint,9,opt,name=MaxConcurrentQueries" json:"MaxConcurrentQueries,omitempty"`
+	MaxQueriesPerMinute       *int64                      `protobuf:"varint,10,opt,name=MaxQueriesPerMinute" json:"MaxQueriesPerMinute,omitempty"`
+	External                  *bool                       `protobuf:"varint,11,opt,name=External" json:"External,omitempty"`
+	DefaultDatabase           *string                     `protobuf:"bytes,12,opt,name=DefaultDatabase" json:"DefaultDatabase,omitempty"`
+	DefaultRetentionPolicy    *string                     `protobuf:"bytes,13,opt,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
+	CreatedAt                 *int64                      `protobuf:"varint,14,opt,name=CreatedAt" json:"CreatedAt,omitempty"`
+	LastLogin                 *int64                      `protobuf:"varint,15,opt,name=LastLogin" json:"LastLogin,omitempty"`
+	QueryPriority             *int32                      `protobuf:"varint,16,opt,name=QueryPriority" json:"QueryPriority,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{}                    `json:"-"`
+	XXX_unrecognized          []byte                      `json:"-"`
+	XXX_sizecache             int32                       `json:"-"`
 }
 
 func (m *UserInfo) Reset()         { *m = UserInfo{} }
@@ -849,6 +1133,214 @@ func (m *UserInfo) GetPrivileges() []*UserPrivilege {
 	return nil
 }
 
+func (m *UserInfo) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *UserInfo) GetMeasurementPrivileges() []*MeasurementPrivilege {
+	if m != nil {
+		return m.MeasurementPrivileges
+	}
+	return nil
+}
+
+func (m *UserInfo) GetRetentionPolicyPrivileges() []*RetentionPolicyPrivilege {
+	if m != nil {
+		return m.RetentionPolicyPrivileges
+	}
+	return nil
+}
+
+func (m *UserInfo) GetLocked() bool {
+	if m != nil && m.Locked != nil {
+		return *m.Locked
+	}
+	return false
+}
+
+func (m *UserInfo) GetMaxConcurrentQueries() int64 {
+	if m != nil && m.MaxConcurrentQueries != nil {
+		return *m.MaxConcurrentQueries
+	}
+	return 0
+}
+
+func (m *UserInfo) GetMaxQueriesPerMinute() int64 {
+	if m != nil && m.MaxQueriesPerMinute != nil {
+		return *m.MaxQueriesPerMinute
+	}
+	return 0
+}
+
+func (m *UserInfo) GetExternal() bool {
+	if m != nil && m.External != nil {
+		return *m.External
+	}
+	return false
+}
+
+func (m *UserInfo) GetDefaultDatabase() string {
+	if m != nil && m.DefaultDatabase != nil {
+		return *m.DefaultDatabase
+	}
+	return ""
+}
+
+func (m *UserInfo) GetDefaultRetentionPolicy() string {
+	if m != nil && m.DefaultRetentionPolicy != nil {
+		return *m.DefaultRetentionPolicy
+	}
+	return ""
+}
+
+func (m *UserInfo) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
+func (m *UserInfo) GetLastLogin() int64 {
+	if m != nil && m.LastLogin != nil {
+		return *m.LastLogin
+	}
+	return 0
+}
+
+func (m *UserInfo) GetQueryPriority() int32 {
+	if m != nil && m.QueryPriority != nil {
+		return *m.QueryPriority
+	}
+	return 0
+}
+
+type RetentionPolicyPrivilege struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy      *string  `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Privilege            *int32   `protobuf:"varint,3,req,name=Privilege" json:"Privilege,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetentionPolicyPrivilege) Reset()         { *m = RetentionPolicyPrivilege{} }
+func (m *RetentionPolicyPrivilege) String() string { return proto.CompactTextString(m) }
+func (*RetentionPolicyPrivilege) ProtoMessage()    {}
+func (*RetentionPolicyPrivilege) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{11}
+}
+func (m *RetentionPolicyPrivilege) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionPolicyPrivilege.Unmarshal(m, b)
+}
+func (m *RetentionPolicyPrivilege) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionPolicyPrivilege.Marshal(b, m, deterministic)
+}
+func (m *RetentionPolicyPrivilege) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionPolicyPrivilege.Merge(m, src)
+}
+func (m *RetentionPolicyPrivilege) XXX_Size() int {
+	return xxx_messageInfo_RetentionPolicyPrivilege.Size(m)
+}
+func (m *RetentionPolicyPrivilege) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionPolicyPrivilege.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionPolicyPrivilege proto.InternalMessageInfo
+
+func (m *RetentionPolicyPrivilege) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *RetentionPolicyPrivilege) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *RetentionPolicyPrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+type MeasurementPrivilege struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy      *string  `protobuf:"bytes,2,opt,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Measurement          *string  `protobuf:"bytes,3,req,name=Measurement" json:"Measurement,omitempty"`
+	Regex                *bool    `protobuf:"varint,4,opt,name=Regex" json:"Regex,omitempty"`
+	Privilege            *int32   `protobuf:"varint,5,req,name=Privilege" json:"Privilege,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MeasurementPrivilege) Reset()         { *m = MeasurementPrivilege{} }
+func (m *MeasurementPrivilege) String() string { return proto.CompactTextString(m) }
+func (*MeasurementPrivilege) ProtoMessage()    {}
+func (*MeasurementPrivilege) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{12}
+}
+func (m *MeasurementPrivilege) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MeasurementPrivilege.Unmarshal(m, b)
+}
+func (m *MeasurementPrivilege) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MeasurementPrivilege.Marshal(b, m, deterministic)
+}
+func (m *MeasurementPrivilege) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MeasurementPrivilege.Merge(m, src)
+}
+func (m *MeasurementPrivilege) XXX_Size() int {
+	return xxx_messageInfo_MeasurementPrivilege.Size(m)
+}
+func (m *MeasurementPrivilege) XXX_DiscardUnknown() {
+	xxx_messageInfo_MeasurementPrivilege.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MeasurementPrivilege proto.InternalMessageInfo
+
+func (m *MeasurementPrivilege) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *MeasurementPrivilege) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *MeasurementPrivilege) GetMeasurement() string {
+	if m != nil && m.Measurement != nil {
+		return *m.Measurement
+	}
+	return ""
+}
+
+func (m *MeasurementPrivilege) GetRegex() bool {
+	if m != nil && m.Regex != nil {
+		return *m.Regex
+	}
+	return false
+}
+
+func (m *MeasurementPrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
 type UserPrivilege struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Privilege            *int32   `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
@@ -861,7 +1353,7 @@ func (m *UserPrivilege) Reset()         { *m = UserPrivilege{} }
 func (m *UserPrivilege) String() string { return proto.CompactTextString(m) }
 func (*UserPrivilege) ProtoMessage()    {}
 func (*UserPrivilege) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{11}
+	return fileDescriptor_59b0956366e72083, []int{13}
 }
 func (m *UserPrivilege) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserPrivilege.Unmarshal(m, b)
@@ -895,6 +1387,146 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 	return 0
 }
 
+type RoleInfo struct {
+	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Privileges           []*UserPrivilege `protobuf:"bytes,2,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RoleInfo) Reset()         { *m = RoleInfo{} }
+func (m *RoleInfo) String() string { return proto.CompactTextString(m) }
+func (*RoleInfo) ProtoMessage()    {}
+func (*RoleInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{14}
+}
+func (m *RoleInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleInfo.Unmarshal(m, b)
+}
+func (m *RoleInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleInfo.Marshal(b, m, deterministic)
+}
+func (m *RoleInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleInfo.Merge(m, src)
+}
+func (m *RoleInfo) XXX_Size() int {
+	return xxx_messageInfo_RoleInfo.Size(m)
+}
+func (m *RoleInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleInfo proto.InternalMessageInfo
+
+func (m *RoleInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RoleInfo) GetPrivileges() []*UserPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+type TokenInfo struct {
+	ID                   *string  `protobuf:"bytes,1,req,name=ID" json:"ID,omitempty"`
+	User                 *string  `protobuf:"bytes,2,req,name=User" json:"User,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,3,req,name=Hash" json:"Hash,omitempty"`
+	Database             *string  `protobuf:"bytes,4,opt,name=Database" json:"Database,omitempty"`
+	Privilege            *int32   `protobuf:"varint,5,opt,name=Privilege" json:"Privilege,omitempty"`
+	CreatedAt            *int64   `protobuf:"varint,6,opt,name=CreatedAt" json:"CreatedAt,omitempty"`
+	Expiry               *int64   `protobuf:"varint,7,opt,name=Expiry" json:"Expiry,omitempty"`
+	LastUsed             *int64   `protobuf:"varint,8,opt,name=LastUsed" json:"LastUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
+func (m *TokenInfo) String() string { return proto.CompactTextString(m) }
+func (*TokenInfo) ProtoMessage()    {}
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59b0956366e72083, []int{15}
+}
+func (m *TokenInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenInfo.Unmarshal(m, b)
+}
+func (m *TokenInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenInfo.Marshal(b, m, deterministic)
+}
+func (m *TokenInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenInfo.Merge(m, src)
+}
+func (m *TokenInfo) XXX_Size() int {
+	return xxx_messageInfo_TokenInfo.Size(m)
+}
+func (m *TokenInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenInfo proto.InternalMessageInfo
+
+func (m *TokenInfo) GetID() string {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *TokenInfo) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetExpiry() int64 {
+	if m != nil && m.Expiry != nil {
+		return *m.Expiry
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetLastUsed() int64 {
+	if m != nil && m.LastUsed != nil {
+		return *m.LastUsed
+	}
+	return 0
+}
+
 type Command struct {
 	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=meta.Command_Type" json:"type,omitempty"`
 	XXX_NoUnkeyedLiteral         struct{}      `json:"-"`
@@ -907,7 +1539,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{12}
+	return fileDescriptor_59b0956366e72083, []int{16}
 }
 
 var extRange_Command = []proto.ExtensionRange{
@@ -957,7 +1589,7 @@ func (m *CreateNodeCommand) Reset()         { *m = CreateNodeCommand{} }
 func (m *CreateNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateNodeCommand) ProtoMessage()    {}
 func (*CreateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{13}
+	return fileDescriptor_59b0956366e72083, []int{17}
 }
 func (m *CreateNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateNodeCommand.Unmarshal(m, b)
@@ -1012,7 +1644,7 @@ func (m *DeleteNodeCommand) Reset()         { *m = DeleteNodeCommand{} }
 func (m *DeleteNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteNodeCommand) ProtoMessage()    {}
 func (*DeleteNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{14}
+	return fileDescriptor_59b0956366e72083, []int{18}
 }
 func (m *DeleteNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteNodeCommand.Unmarshal(m, b)
@@ -1067,7 +1699,7 @@ func (m *CreateDatabaseCommand) Reset()         { *m = CreateDatabaseCommand{} }
 func (m *CreateDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()    {}
 func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{15}
+	return fileDescriptor_59b0956366e72083, []int{19}
 }
 func (m *CreateDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDatabaseCommand.Unmarshal(m, b)
@@ -1121,7 +1753,7 @@ func (m *DropDatabaseCommand) Reset()         { *m = DropDatabaseCommand{} }
 func (m *DropDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*DropDatabaseCommand) ProtoMessage()    {}
 func (*DropDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{16}
+	return fileDescriptor_59b0956366e72083, []int{20}
 }
 func (m *DropDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropDatabaseCommand.Unmarshal(m, b)
@@ -1170,7 +1802,7 @@ func (m *CreateRetentionPolicyCommand) Reset()         { *m = CreateRetentionPol
 func (m *CreateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateRetentionPolicyCommand) ProtoMessage()    {}
 func (*CreateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{17}
+	return fileDescriptor_59b0956366e72083, []int{21}
 }
 func (m *CreateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1232,7 +1864,7 @@ func (m *DropRetentionPolicyCommand) Reset()         { *m = DropRetentionPolicyC
 func (m *DropRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*DropRetentionPolicyCommand) ProtoMessage()    {}
 func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{18}
+	return fileDescriptor_59b0956366e72083, []int{22}
 }
 func (m *DropRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropRetentionPolicyCommand.Unmarshal(m, b)
@@ -1287,7 +1919,7 @@ func (m *SetDefaultRetentionPolicyCommand) Reset()         { *m = SetDefaultRete
 func (m *SetDefaultRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDefaultRetentionPolicyCommand) ProtoMessage()    {}
 func (*SetDefaultRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{19}
+	return fileDescriptor_59b0956366e72083, []int{23}
 }
 func (m *SetDefaultRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDefaultRetentionPolicyCommand.Unmarshal(m, b)
@@ -1337,6 +1969,9 @@ type UpdateRetentionPolicyCommand struct {
 	Duration             *int64   `protobuf:"varint,4,opt,name=Duration" json:"Duration,omitempty"`
 	ReplicaN             *uint32  `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
 	Default              *bool    `protobuf:"varint,6,req,name=Default" json:"Default,omitempty"`
+	Placement            *string  `protobuf:"bytes,7,opt,name=Placement" json:"Placement,omitempty"`
+	DeletionDelay        *int64   `protobuf:"varint,8,opt,name=DeletionDelay" json:"DeletionDelay,omitempty"`
+	MaxQueryRange        *int64   `protobuf:"varint,9,opt,name=MaxQueryRange" json:"MaxQueryRange,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1346,7 +1981,7 @@ func (m *UpdateRetentionPolicyCommand) Reset()         { *m = UpdateRetentionPol
 func (m *UpdateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateRetentionPolicyCommand) ProtoMessage()    {}
 func (*UpdateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{20}
+	return fileDescriptor_59b0956366e72083, []int{24}
 }
 func (m *UpdateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1408,6 +2043,27 @@ func (m *UpdateRetentionPolicyCommand) GetDefault() bool {
 	return false
 }
 
+func (m *UpdateRetentionPolicyCommand) GetPlacement() string {
+	if m != nil && m.Placement != nil {
+		return *m.Placement
+	}
+	return ""
+}
+
+func (m *UpdateRetentionPolicyCommand) GetDeletionDelay() int64 {
+	if m != nil && m.DeletionDelay != nil {
+		return *m.DeletionDelay
+	}
+	return 0
+}
+
+func (m *UpdateRetentionPolicyCommand) GetMaxQueryRange() int64 {
+	if m != nil && m.MaxQueryRange != nil {
+		return *m.MaxQueryRange
+	}
+	return 0
+}
+
 var E_UpdateRetentionPolicyCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*UpdateRetentionPolicyCommand)(nil),
@@ -1430,7 +2086,7 @@ func (m *CreateShardGroupCommand) Reset()         { *m = CreateShardGroupCommand
 func (m *CreateShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateShardGroupCommand) ProtoMessage()    {}
 func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{21}
+	return fileDescriptor_59b0956366e72083, []int{25}
 }
 func (m *CreateShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateShardGroupCommand.Unmarshal(m, b)
@@ -1493,7 +2149,7 @@ func (m *DeleteShardGroupCommand) Reset()         { *m = DeleteShardGroupCommand
 func (m *DeleteShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteShardGroupCommand) ProtoMessage()    {}
 func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{22}
+	return fileDescriptor_59b0956366e72083, []int{26}
 }
 func (m *DeleteShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteShardGroupCommand.Unmarshal(m, b)
@@ -1547,6 +2203,7 @@ type CreateContinuousQueryCommand struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
 	Query                *string  `protobuf:"bytes,3,req,name=Query" json:"Query,omitempty"`
+	Owner                *string  `protobuf:"bytes,4,opt,name=Owner" json:"Owner,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1556,7 +2213,7 @@ func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQu
 func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateContinuousQueryCommand) ProtoMessage()    {}
 func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{23}
+	return fileDescriptor_59b0956366e72083, []int{27}
 }
 func (m *CreateContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateContinuousQueryCommand.Unmarshal(m, b)
@@ -1597,6 +2254,13 @@ func (m *CreateContinuousQueryCommand) GetQuery() string {
 	return ""
 }
 
+func (m *CreateContinuousQueryCommand) GetOwner() string {
+	if m != nil && m.Owner != nil {
+		return *m.Owner
+	}
+	return ""
+}
+
 var E_CreateContinuousQueryCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateContinuousQueryCommand)(nil),
@@ -1618,7 +2282,7 @@ func (m *DropContinuousQueryCommand) Reset()         { *m = DropContinuousQueryC
 func (m *DropContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*DropContinuousQueryCommand) ProtoMessage()    {}
 func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{24}
+	return fileDescriptor_59b0956366e72083, []int{28}
 }
 func (m *DropContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropContinuousQueryCommand.Unmarshal(m, b)
@@ -1665,6 +2329,7 @@ type CreateUserCommand struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Hash                 *string  `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
 	Admin                *bool    `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
+	CreatedAt            *int64   `protobuf:"varint,4,opt,name=CreatedAt" json:"CreatedAt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1674,7 +2339,7 @@ func (m *CreateUserCommand) Reset()         { *m = CreateUserCommand{} }
 func (m *CreateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateUserCommand) ProtoMessage()    {}
 func (*CreateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{25}
+	return fileDescriptor_59b0956366e72083, []int{29}
 }
 func (m *CreateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateUserCommand.Unmarshal(m, b)
@@ -1715,6 +2380,13 @@ func (m *CreateUserCommand) GetAdmin() bool {
 	return false
 }
 
+func (m *CreateUserCommand) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
 var E_CreateUserCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateUserCommand)(nil),
@@ -1735,7 +2407,7 @@ func (m *DropUserCommand) Reset()         { *m = DropUserCommand{} }
 func (m *DropUserCommand) String() string { return proto.CompactTextString(m) }
 func (*DropUserCommand) ProtoMessage()    {}
 func (*DropUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{26}
+	return fileDescriptor_59b0956366e72083, []int{30}
 }
 func (m *DropUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropUserCommand.Unmarshal(m, b)
@@ -1783,7 +2455,7 @@ func (m *UpdateUserCommand) Reset()         { *m = UpdateUserCommand{} }
 func (m *UpdateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateUserCommand) ProtoMessage()    {}
 func (*UpdateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{27}
+	return fileDescriptor_59b0956366e72083, []int{31}
 }
 func (m *UpdateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateUserCommand.Unmarshal(m, b)
@@ -1839,7 +2511,7 @@ func (m *SetPrivilegeCommand) Reset()         { *m = SetPrivilegeCommand{} }
 func (m *SetPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetPrivilegeCommand) ProtoMessage()    {}
 func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{28}
+	return fileDescriptor_59b0956366e72083, []int{32}
 }
 func (m *SetPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetPrivilegeCommand.Unmarshal(m, b)
@@ -1900,7 +2572,7 @@ func (m *SetDataCommand) Reset()         { *m = SetDataCommand{} }
 func (m *SetDataCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDataCommand) ProtoMessage()    {}
 func (*SetDataCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{29}
+	return fileDescriptor_59b0956366e72083, []int{33}
 }
 func (m *SetDataCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDataCommand.Unmarshal(m, b)
@@ -1948,7 +2620,7 @@ func (m *SetAdminPrivilegeCommand) Reset()         { *m = SetAdminPrivilegeComma
 func (m *SetAdminPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetAdminPrivilegeCommand) ProtoMessage()    {}
 func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{30}
+	return fileDescriptor_59b0956366e72083, []int{34}
 }
 func (m *SetAdminPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetAdminPrivilegeCommand.Unmarshal(m, b)
@@ -2003,7 +2675,7 @@ func (m *UpdateNodeCommand) Reset()         { *m = UpdateNodeCommand{} }
 func (m *UpdateNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateNodeCommand) ProtoMessage()    {}
 func (*UpdateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{31}
+	return fileDescriptor_59b0956366e72083, []int{35}
 }
 func (m *UpdateNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateNodeCommand.Unmarshal(m, b)
@@ -2052,6 +2724,11 @@ type CreateSubscriptionCommand struct {
 	RetentionPolicy      *string  `protobuf:"bytes,3,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
 	Mode                 *string  `protobuf:"bytes,4,req,name=Mode" json:"Mode,omitempty"`
 	Destinations         []string `protobuf:"bytes,5,rep,name=Destinations" json:"Destinations,omitempty"`
+	Filter               *string  `protobuf:"bytes,6,opt,name=Filter" json:"Filter,omitempty"`
+	CA                   *string  `protobuf:"bytes,7,opt,name=CA" json:"CA,omitempty"`
+	Cert                 *string  `protobuf:"bytes,8,opt,name=Cert" json:"Cert,omitempty"`
+	Key                  *string  `protobuf:"bytes,9,opt,name=Key" json:"Key,omitempty"`
+	Weights              []int64  `protobuf:"varint,10,rep,name=Weights" json:"Weights,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2061,7 +2738,7 @@ func (m *CreateSubscriptionCommand) Reset()         { *m = CreateSubscriptionCom
 func (m *CreateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateSubscriptionCommand) ProtoMessage()    {}
 func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{32}
+	return fileDescriptor_59b0956366e72083, []int{36}
 }
 func (m *CreateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateSubscriptionCommand.Unmarshal(m, b)
@@ -2116,6 +2793,41 @@ func (m *CreateSubscriptionCommand) GetDestinations() []string {
 	return nil
 }
 
+func (m *CreateSubscriptionCommand) GetFilter() string {
+	if m != nil && m.Filter != nil {
+		return *m.Filter
+	}
+	return ""
+}
+
+func (m *CreateSubscriptionCommand) GetCA() string {
+	if m != nil && m.CA != nil {
+		return *m.CA
+	}
+	return ""
+}
+
+func (m *CreateSubscriptionCommand) GetCert() string {
+	if m != nil && m.Cert != nil {
+		return *m.Cert
+	}
+	return ""
+}
+
+func (m *CreateSubscriptionCommand) GetKey() string {
+	if m != nil && m.Key != nil {
+		return *m.Key
+	}
+	return ""
+}
+
+func (m *CreateSubscriptionCommand) GetWeights() []int64 {
+	if m != nil {
+		return m.Weights
+	}
+	return nil
+}
+
 var E_CreateSubscriptionCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateSubscriptionCommand)(nil),
@@ -2138,7 +2850,7 @@ func (m *DropSubscriptionCommand) Reset()         { *m = DropSubscriptionCommand
 func (m *DropSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*DropSubscriptionCommand) ProtoMessage()    {}
 func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{33}
+	return fileDescriptor_59b0956366e72083, []int{37}
 }
 func (m *DropSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropSubscriptionCommand.Unmarshal(m, b)
@@ -2200,7 +2912,7 @@ func (m *RemovePeerCommand) Reset()         { *m = RemovePeerCommand{} }
 func (m *RemovePeerCommand) String() string { return proto.CompactTextString(m) }
 func (*RemovePeerCommand) ProtoMessage()    {}
 func (*RemovePeerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{34}
+	return fileDescriptor_59b0956366e72083, []int{38}
 }
 func (m *RemovePeerCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemovePeerCommand.Unmarshal(m, b)
@@ -2256,7 +2968,7 @@ func (m *CreateMetaNodeCommand) Reset()         { *m = CreateMetaNodeCommand{} }
 func (m *CreateMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateMetaNodeCommand) ProtoMessage()    {}
 func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{35}
+	return fileDescriptor_59b0956366e72083, []int{39}
 }
 func (m *CreateMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMetaNodeCommand.Unmarshal(m, b)
@@ -2318,7 +3030,7 @@ func (m *CreateDataNodeCommand) Reset()         { *m = CreateDataNodeCommand{} }
 func (m *CreateDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDataNodeCommand) ProtoMessage()    {}
 func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{36}
+	return fileDescriptor_59b0956366e72083, []int{40}
 }
 func (m *CreateDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDataNodeCommand.Unmarshal(m, b)
@@ -2374,7 +3086,7 @@ func (m *UpdateDataNodeCommand) Reset()         { *m = UpdateDataNodeCommand{} }
 func (m *UpdateDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateDataNodeCommand) ProtoMessage()    {}
 func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{37}
+	return fileDescriptor_59b0956366e72083, []int{41}
 }
 func (m *UpdateDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateDataNodeCommand.Unmarshal(m, b)
@@ -2435,7 +3147,7 @@ func (m *DeleteMetaNodeCommand) Reset()         { *m = DeleteMetaNodeCommand{} }
 func (m *DeleteMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteMetaNodeCommand) ProtoMessage()    {}
 func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{38}
+	return fileDescriptor_59b0956366e72083, []int{42}
 }
 func (m *DeleteMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteMetaNodeCommand.Unmarshal(m, b)
@@ -2482,7 +3194,7 @@ func (m *DeleteDataNodeCommand) Reset()         { *m = DeleteDataNodeCommand{} }
 func (m *DeleteDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteDataNodeCommand) ProtoMessage()    {}
 func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{39}
+	return fileDescriptor_59b0956366e72083, []int{43}
 }
 func (m *DeleteDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteDataNodeCommand.Unmarshal(m, b)
@@ -2531,7 +3243,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{40}
+	return fileDescriptor_59b0956366e72083, []int{44}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -2587,7 +3299,7 @@ func (m *SetMetaNodeCommand) Reset()         { *m = SetMetaNodeCommand{} }
 func (m *SetMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetMetaNodeCommand) ProtoMessage()    {}
 func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{41}
+	return fileDescriptor_59b0956366e72083, []int{45}
 }
 func (m *SetMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetMetaNodeCommand.Unmarshal(m, b)
@@ -2648,7 +3360,7 @@ func (m *DropShardCommand) Reset()         { *m = DropShardCommand{} }
 func (m *DropShardCommand) String() string { return proto.CompactTextString(m) }
 func (*DropShardCommand) ProtoMessage()    {}
 func (*DropShardCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_59b0956366e72083, []int{42}
+	return fileDescriptor_59b0956366e72083, []int{46}
 }
 func (m *DropShardCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropShardCommand.Unmarshal(m, b)
@@ -2697,7 +3409,11 @@ func init() {
 	proto.RegisterType((*ShardOwner)(nil), "meta.ShardOwner")
 	proto.RegisterType((*ContinuousQueryInfo)(nil), "meta.ContinuousQueryInfo")
 	proto.RegisterType((*UserInfo)(nil), "meta.UserInfo")
+	proto.RegisterType((*RetentionPolicyPrivilege)(nil), "meta.RetentionPolicyPrivilege")
+	proto.RegisterType((*MeasurementPrivilege)(nil), "meta.MeasurementPrivilege")
 	proto.RegisterType((*UserPrivilege)(nil), "meta.UserPrivilege")
+	proto.RegisterType((*RoleInfo)(nil), "meta.RoleInfo")
+	proto.RegisterType((*TokenInfo)(nil), "meta.TokenInfo")
 	proto.RegisterType((*Command)(nil), "meta.Command")
 	proto.RegisterExtension(E_CreateNodeCommand_Command)
 	proto.RegisterType((*CreateNodeCommand)(nil), "meta.CreateNodeCommand")
@@ -2763,119 +3479,164 @@ func init() {
 func init() { proto.RegisterFile("internal/meta.proto", fileDescriptor_59b0956366e72083) }
 
 var fileDescriptor_59b0956366e72083 = []byte{
-	// 1819 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0xcb, 0x73, 0xdc, 0x44,
-	0x13, 0x2f, 0x49, 0xbb, 0xeb, 0xdd, 0xf6, 0x33, 0xe3, 0x97, 0x9c, 0x38, 0xfe, 0xb6, 0x54, 0xa9,
-	0x7c, 0x5b, 0x5f, 0x7d, 0x65, 0xa8, 0xa5, 0x2a, 0x27, 0x5e, 0x89, 0x37, 0x89, 0xb7, 0x52, 0x7e,
-	0xa0, 0x75, 0xae, 0x54, 0x29, 0xde, 0x49, 0xbc, 0xb0, 0x2b, 0x2d, 0x92, 0x36, 0x89, 0x09, 0x06,
-	0xc3, 0x25, 0x57, 0x28, 0x8a, 0xe2, 0x90, 0x1b, 0x1c, 0x38, 0x52, 0x5c, 0xb8, 0x70, 0xe2, 0xc0,
-	0x89, 0xbf, 0x80, 0x7f, 0x80, 0x13, 0x77, 0xae, 0xd4, 0xcc, 0x68, 0x34, 0x23, 0x69, 0x46, 0xb6,
-	0x21, 0xdc, 0x34, 0xdd, 0x3d, 0xd3, 0xbf, 0xee, 0xe9, 0xe9, 0xe9, 0x1e, 0xc1, 0xe2, 0xc0, 0x8f,
-	0x71, 0xe8, 0x7b, 0xc3, 0x57, 0x46, 0x38, 0xf6, 0x36, 0xc7, 0x61, 0x10, 0x07, 0xa8, 0x42, 0xbe,
-	0x9d, 0xcf, 0x2d, 0xa8, 0x74, 0xbc, 0xd8, 0x43, 0x08, 0x2a, 0x07, 0x38, 0x1c, 0xd9, 0x46, 0xd3,
-	0x6c, 0x55, 0x5c, 0xfa, 0x8d, 0x96, 0xa0, 0xda, 0xf5, 0xfb, 0xf8, 0xa9, 0x6d, 0x52, 0x22, 0x1b,
-	0xa0, 0x75, 0x68, 0x6c, 0x0d, 0x27, 0x51, 0x8c, 0xc3, 0x6e, 0xc7, 0xb6, 0x28, 0x47, 0x10, 0xd0,
-	0x35, 0xa8, 0xee, 0x06, 0x7d, 0x1c, 0xd9, 0x95, 0xa6, 0xd5, 0x9a, 0x6e, 0xcf, 0x6d, 0x52, 0x95,
-	0x84, 0xd4, 0xf5, 0x1f, 0x06, 0x2e, 0x63, 0xa2, 0x57, 0xa1, 0x41, 0xb4, 0x3e, 0xf0, 0x22, 0x1c,
-	0xd9, 0x55, 0x2a, 0x89, 0x98, 0x24, 0x27, 0x53, 0x69, 0x21, 0x44, 0xd6, 0xbd, 0x1f, 0xe1, 0x30,
-	0xb2, 0x6b, 0xf2, 0xba, 0x84, 0xc4, 0xd6, 0xa5, 0x4c, 0x82, 0x6d, 0xc7, 0x7b, 0x4a, 0xb5, 0x75,
-	0xec, 0x29, 0x86, 0x2d, 0x25, 0xa0, 0x16, 0xcc, 0xef, 0x78, 0x4f, 0x7b, 0x47, 0x5e, 0xd8, 0xbf,
-	0x1b, 0x06, 0x93, 0x71, 0xb7, 0x63, 0xd7, 0xa9, 0x4c, 0x9e, 0x8c, 0x36, 0x00, 0x38, 0xa9, 0xdb,
-	0xb1, 0x1b, 0x54, 0x48, 0xa2, 0xa0, 0xff, 0x33, 0xfc, 0xcc, 0x52, 0x50, 0x5a, 0x2a, 0x04, 0x88,
-	0xf4, 0x0e, 0xe6, 0xd2, 0xd3, 0x6a, 0xe9, 0x54, 0xc0, 0xd9, 0x86, 0x3a, 0x27, 0xa3, 0x39, 0x30,
-	0xbb, 0x9d, 0x64, 0x4f, 0xcc, 0x6e, 0x87, 0xec, 0xd2, 0x76, 0x10, 0xc5, 0x74, 0x43, 0x1a, 0x2e,
-	0xfd, 0x46, 0x36, 0x4c, 0x1d, 0x6c, 0xed, 0x53, 0xb2, 0xd5, 0x34, 0x5a, 0x0d, 0x97, 0x0f, 0x9d,
-	0x3f, 0x0c, 0x98, 0x91, 0xfd, 0x49, 0xa6, 0xef, 0x7a, 0x23, 0x4c, 0x17, 0x6c, 0xb8, 0xf4, 0x1b,
-	0xdd, 0x80, 0x95, 0x0e, 0x7e, 0xe8, 0x4d, 0x86, 0xb1, 0x8b, 0x63, 0xec, 0xc7, 0x83, 0xc0, 0xdf,
-	0x0f, 0x86, 0x83, 0xc3, 0xe3, 0x44, 0x89, 0x86, 0x8b, 0xee, 0xc2, 0xa5, 0x2c, 0x69, 0x80, 0x23,
-	0xdb, 0xa2, 0xc6, 0xad, 0x31, 0xe3, 0x72, 0x33, 0xa8, 0x9d, 0xc5, 0x39, 0x64, 0xa1, 0xad, 0xc0,
-	0x8f, 0x07, 0xfe, 0x24, 0x98, 0x44, 0xef, 0x4c, 0x70, 0x38, 0x48, 0xa3, 0x27, 0x59, 0x28, 0xcb,
-	0x4e, 0x16, 0x2a, 0xcc, 0x71, 0xbe, 0x30, 0x60, 0x31, 0xa7, 0xb3, 0x37, 0xc6, 0x87, 0x92, 0xd5,
-	0x46, 0x6a, 0xf5, 0x65, 0xa8, 0x77, 0x26, 0xa1, 0x47, 0x24, 0x6d, 0xb3, 0x69, 0xb4, 0x2c, 0x37,
-	0x1d, 0xa3, 0x4d, 0x40, 0x22, 0x18, 0x52, 0x29, 0x8b, 0x4a, 0x29, 0x38, 0x64, 0x2d, 0x17, 0x8f,
-	0x87, 0x83, 0x43, 0x6f, 0xd7, 0xae, 0x34, 0x8d, 0xd6, 0xac, 0x9b, 0x8e, 0x9d, 0xe7, 0x66, 0x01,
-	0x93, 0x76, 0x27, 0xb2, 0x98, 0xcc, 0x73, 0x61, 0x32, 0xcf, 0x85, 0xc9, 0x94, 0x31, 0xa1, 0x1b,
-	0x30, 0x2d, 0x66, 0xf0, 0xe3, 0xb7, 0xc4, 0x5c, 0x2d, 0x9d, 0x02, 0xe2, 0x65, 0x59, 0x10, 0xbd,
-	0x0e, 0xb3, 0xbd, 0xc9, 0x83, 0xe8, 0x30, 0x1c, 0x8c, 0x89, 0x0e, 0x7e, 0x14, 0x57, 0x92, 0x99,
-	0x12, 0x8b, 0xce, 0xcd, 0x0a, 0x3b, 0x3f, 0x1b, 0x30, 0x97, 0x5d, 0xbd, 0x10, 0xdd, 0xeb, 0xd0,
-	0xe8, 0xc5, 0x5e, 0x18, 0x1f, 0x0c, 0x46, 0x38, 0xf1, 0x80, 0x20, 0x90, 0x38, 0xbf, 0xed, 0xf7,
-	0x29, 0x8f, 0xd9, 0xcd, 0x87, 0x64, 0x5e, 0x07, 0x0f, 0x71, 0x8c, 0xfb, 0x37, 0x63, 0x6a, 0xad,
-	0xe5, 0x0a, 0x02, 0xfa, 0x2f, 0xd4, 0xa8, 0x5e, 0x6e, 0xe9, 0xbc, 0x64, 0x29, 0x05, 0x9a, 0xb0,
-	0x51, 0x13, 0xa6, 0x0f, 0xc2, 0x89, 0x7f, 0xe8, 0xb1, 0x85, 0x6a, 0x74, 0xc3, 0x65, 0x92, 0x83,
-	0xa1, 0x91, 0x4e, 0x2b, 0xa0, 0xdf, 0x80, 0xfa, 0xde, 0x13, 0x9f, 0x24, 0xc1, 0xc8, 0x36, 0x9b,
-	0x56, 0xab, 0x72, 0xcb, 0xb4, 0x0d, 0x37, 0xa5, 0xa1, 0x16, 0xd4, 0xe8, 0x37, 0x3f, 0x25, 0x0b,
-	0x12, 0x0e, 0xca, 0x70, 0x13, 0xbe, 0xf3, 0x2e, 0x2c, 0xe4, 0xbd, 0xa9, 0x0c, 0x18, 0x04, 0x95,
-	0x9d, 0xa0, 0x8f, 0x79, 0x36, 0x20, 0xdf, 0xc8, 0x81, 0x99, 0x0e, 0x8e, 0xe2, 0x81, 0xef, 0xb1,
-	0x3d, 0x22, 0xba, 0x1a, 0x6e, 0x86, 0xe6, 0x5c, 0x03, 0x10, 0x5a, 0xd1, 0x0a, 0xd4, 0x92, 0x84,
-	0xc9, 0x6c, 0x49, 0x46, 0xce, 0x5b, 0xb0, 0xa8, 0x38, 0x78, 0x4a, 0x20, 0x4b, 0x50, 0xa5, 0x02,
-	0x09, 0x12, 0x36, 0x70, 0x4e, 0xa0, 0xce, 0xf3, 0xb3, 0x0e, 0xfe, 0xb6, 0x17, 0x1d, 0xa5, 0xc9,
-	0xcc, 0x8b, 0x8e, 0xc8, 0x4a, 0x37, 0xfb, 0xa3, 0x01, 0x0b, 0xed, 0xba, 0xcb, 0x06, 0xe8, 0x35,
-	0x80, 0xfd, 0x70, 0xf0, 0x78, 0x30, 0xc4, 0x8f, 0xd2, 0xdc, 0xb0, 0x28, 0x6e, 0x80, 0x94, 0xe7,
-	0x4a, 0x62, 0x4e, 0x17, 0x66, 0x33, 0x4c, 0x7a, 0xbe, 0x92, 0x6c, 0x98, 0xe0, 0x48, 0xc7, 0x24,
-	0x84, 0x52, 0x41, 0x0a, 0xa8, 0xea, 0x0a, 0x82, 0xf3, 0x5b, 0x0d, 0xa6, 0xb6, 0x82, 0xd1, 0xc8,
-	0xf3, 0xfb, 0xe8, 0x3a, 0x54, 0xe2, 0xe3, 0x31, 0x5b, 0x61, 0x8e, 0xdf, 0x5a, 0x09, 0x73, 0xf3,
-	0xe0, 0x78, 0x8c, 0x5d, 0xca, 0x77, 0x5e, 0xd4, 0xa0, 0x42, 0x86, 0x68, 0x19, 0x2e, 0x6d, 0x85,
-	0xd8, 0x8b, 0x31, 0xf1, 0x6b, 0x22, 0xb8, 0x60, 0x10, 0x32, 0x8b, 0x51, 0x99, 0x6c, 0xa2, 0x35,
-	0x58, 0x66, 0xd2, 0x1c, 0x1a, 0x67, 0x59, 0x68, 0x15, 0x16, 0x3b, 0x61, 0x30, 0xce, 0x33, 0x2a,
-	0xa8, 0x09, 0xeb, 0x6c, 0x4e, 0x2e, 0xd3, 0x70, 0x89, 0x2a, 0xda, 0x80, 0xcb, 0x64, 0xaa, 0x86,
-	0x5f, 0x43, 0xd7, 0xa0, 0xd9, 0xc3, 0xb1, 0x3a, 0xd3, 0x73, 0xa9, 0x29, 0xa2, 0xe7, 0xfe, 0xb8,
-	0xaf, 0xd7, 0x53, 0x47, 0x57, 0x60, 0x95, 0x21, 0x11, 0x27, 0x9d, 0x33, 0x1b, 0x84, 0xc9, 0x2c,
-	0x2e, 0x32, 0x41, 0xd8, 0x90, 0x8b, 0x39, 0x2e, 0x31, 0xcd, 0x6d, 0xd0, 0xf0, 0x67, 0x84, 0x9f,
-	0xc9, 0xae, 0x73, 0xf2, 0x2c, 0x5a, 0x84, 0x79, 0x32, 0x4d, 0x26, 0xce, 0x11, 0x59, 0x66, 0x89,
-	0x4c, 0x9e, 0x27, 0x1e, 0xee, 0xe1, 0x38, 0xdd, 0x77, 0xce, 0x58, 0x40, 0x08, 0xe6, 0x88, 0x7f,
-	0xbc, 0xd8, 0xe3, 0xb4, 0x4b, 0x68, 0x1d, 0xec, 0x1e, 0x8e, 0x69, 0x80, 0x16, 0x66, 0x20, 0xa1,
-	0x41, 0xde, 0xde, 0x45, 0x74, 0x15, 0xd6, 0x12, 0x07, 0x49, 0x07, 0x9c, 0xb3, 0x97, 0xa9, 0x8b,
-	0xc2, 0x60, 0xac, 0x62, 0xae, 0x90, 0x25, 0x5d, 0x3c, 0x0a, 0x1e, 0xe3, 0x7d, 0x2c, 0x40, 0xaf,
-	0x8a, 0x88, 0xe1, 0x25, 0x04, 0x67, 0xd9, 0xd9, 0x60, 0x92, 0x59, 0x6b, 0x84, 0xc5, 0xf0, 0xe5,
-	0x59, 0x97, 0x09, 0x8b, 0xed, 0x53, 0x7e, 0xc1, 0x2b, 0x82, 0x95, 0x9f, 0xb5, 0x8e, 0x56, 0x00,
-	0xf5, 0x70, 0x9c, 0x9f, 0x72, 0x15, 0x2d, 0xc1, 0x02, 0x35, 0x89, 0xec, 0x39, 0xa7, 0x6e, 0xfc,
-	0xaf, 0x5e, 0xef, 0x2f, 0x9c, 0x9e, 0x9e, 0x9e, 0x9a, 0xce, 0x89, 0xe2, 0x78, 0xa4, 0x75, 0x8e,
-	0x21, 0xd5, 0x39, 0x08, 0x2a, 0xae, 0xe7, 0xf7, 0x93, 0x62, 0x94, 0x7e, 0xb7, 0xdf, 0x86, 0xa9,
-	0xc3, 0x64, 0xca, 0x6c, 0xe6, 0x24, 0xda, 0xb8, 0x69, 0xb4, 0xa6, 0xdb, 0xab, 0x09, 0x31, 0xaf,
-	0xc0, 0xe5, 0xd3, 0x9c, 0x67, 0x8a, 0x63, 0x58, 0x48, 0xed, 0x4b, 0x50, 0xbd, 0x13, 0x84, 0x87,
-	0x2c, 0x33, 0xd4, 0x5d, 0x36, 0x28, 0x51, 0xfe, 0x50, 0x56, 0x5e, 0x58, 0x5e, 0x28, 0xff, 0xd1,
-	0xd0, 0x9c, 0x76, 0x65, 0xbe, 0xdc, 0x82, 0xf9, 0x62, 0x89, 0x66, 0x94, 0xd7, 0x5b, 0xf9, 0x19,
-	0xed, 0x8e, 0x16, 0xf4, 0x23, 0xba, 0xd6, 0x15, 0xd9, 0x63, 0x39, 0x54, 0x02, 0xf8, 0x48, 0x99,
-	0x8a, 0x54, 0xa8, 0xdb, 0xb7, 0xb4, 0x0a, 0x8f, 0x64, 0xf0, 0x8a, 0xe5, 0x84, 0xba, 0xdf, 0x8d,
-	0xf2, 0x0c, 0x57, 0x9a, 0xda, 0x95, 0x6e, 0x33, 0x2f, 0xe6, 0x36, 0x52, 0x7c, 0x24, 0xd9, 0x31,
-	0xb9, 0x99, 0xf8, 0xb0, 0x7d, 0x4f, 0x6b, 0xdf, 0x80, 0xda, 0xe7, 0xc8, 0x0e, 0x55, 0xc3, 0x17,
-	0x86, 0x7e, 0x6d, 0x94, 0x25, 0xea, 0x52, 0x33, 0xb9, 0xef, 0x4d, 0xc9, 0xf7, 0x5d, 0x2d, 0xb6,
-	0xf7, 0x28, 0xb6, 0xa6, 0xf0, 0xfd, 0x59, 0xc8, 0xbe, 0x35, 0xce, 0xbe, 0x22, 0x2e, 0x8c, 0x6f,
-	0x4f, 0x8b, 0xef, 0x7d, 0x8a, 0xef, 0x7a, 0x52, 0x22, 0x9d, 0xa1, 0x57, 0xa0, 0x7c, 0x6e, 0x96,
-	0x5f, 0x51, 0x17, 0x45, 0x48, 0xf6, 0x7d, 0x17, 0x3f, 0xa1, 0xe4, 0xa4, 0xb9, 0x4a, 0x86, 0x99,
-	0x6a, 0xbd, 0x92, 0xeb, 0x20, 0xe4, 0xea, 0xbb, 0x9a, 0xed, 0x08, 0xe4, 0x48, 0xaa, 0x9d, 0x37,
-	0x92, 0x86, 0x72, 0x24, 0x95, 0xd9, 0x27, 0x3c, 0xf1, 0x8b, 0xa1, 0xbd, 0x8a, 0x4b, 0x9d, 0xd0,
-	0x52, 0x9f, 0x96, 0x46, 0xf1, 0x48, 0xac, 0x43, 0x83, 0x54, 0xdf, 0x51, 0xec, 0x8d, 0xc6, 0x49,
-	0x45, 0x2e, 0x08, 0xed, 0x3b, 0x5a, 0x63, 0x46, 0xd4, 0x98, 0xab, 0xf2, 0xb1, 0x28, 0x40, 0x14,
-	0x76, 0xfc, 0x6a, 0x68, 0xab, 0x86, 0x97, 0x64, 0x87, 0x03, 0x33, 0x99, 0x27, 0x01, 0xf6, 0xa4,
-	0x91, 0xa1, 0x95, 0x58, 0xe3, 0xcb, 0xd6, 0x68, 0x80, 0x0a, 0x6b, 0x7e, 0x30, 0xca, 0xcb, 0x9c,
-	0x0b, 0xc7, 0x67, 0x5a, 0x79, 0x5b, 0x52, 0xe5, 0x5d, 0x12, 0x49, 0x41, 0x31, 0x27, 0xa9, 0x91,
-	0x14, 0x73, 0xd2, 0xcb, 0x41, 0x5c, 0x92, 0x93, 0xc6, 0xf9, 0x9c, 0x74, 0x16, 0xb2, 0x2f, 0x0d,
-	0x45, 0xc9, 0xf7, 0xcf, 0x5a, 0x8d, 0x92, 0x4b, 0xfd, 0x83, 0x62, 0x45, 0x21, 0xa9, 0x15, 0xa8,
-	0x70, 0xa1, 0xe0, 0x54, 0xde, 0x8b, 0x6f, 0x6a, 0x15, 0x85, 0x54, 0xd1, 0xb2, 0xf0, 0x83, 0x52,
-	0xcd, 0x89, 0xa2, 0x84, 0x3d, 0xaf, 0xed, 0x25, 0x56, 0x46, 0xb2, 0x95, 0x05, 0x05, 0x42, 0xfd,
-	0xf7, 0x86, 0xb2, 0x56, 0x26, 0xe1, 0x40, 0xe4, 0x7d, 0x81, 0x22, 0x1d, 0x67, 0x42, 0xc5, 0x2c,
-	0x6b, 0xc0, 0xac, 0x5c, 0x03, 0x56, 0x52, 0x44, 0xc4, 0x72, 0x11, 0xa1, 0x00, 0x24, 0x10, 0x07,
-	0xf9, 0x1a, 0x1e, 0x6d, 0xb0, 0xb7, 0x4f, 0x8a, 0x73, 0xba, 0x0d, 0xe2, 0x01, 0xd2, 0xa5, 0xf4,
-	0xf6, 0x1b, 0x5a, 0xad, 0x13, 0xaa, 0x75, 0x49, 0x5c, 0x4f, 0x62, 0x55, 0xa1, 0xf0, 0x2b, 0x43,
-	0xdf, 0x21, 0x94, 0xfa, 0x29, 0x8d, 0x4c, 0x53, 0x8e, 0xcc, 0xbb, 0x5a, 0x34, 0x8f, 0x29, 0x9a,
-	0x8d, 0x14, 0x8d, 0x52, 0xa3, 0xc0, 0x75, 0xac, 0x68, 0x4d, 0xce, 0xf3, 0xd2, 0x58, 0x12, 0x35,
-	0x4f, 0x8a, 0x51, 0xa3, 0x2c, 0x78, 0xff, 0x34, 0x4a, 0xfa, 0x1f, 0xed, 0xa3, 0x98, 0x2e, 0x66,
-	0x14, 0x39, 0xde, 0x52, 0xe7, 0x78, 0xfe, 0x52, 0x52, 0x29, 0x79, 0x29, 0xa9, 0x16, 0x5f, 0x4a,
-	0xda, 0xdb, 0x5a, 0x8b, 0x8f, 0xa9, 0xc5, 0xff, 0xc9, 0xdc, 0x62, 0x45, 0x93, 0x84, 0xe5, 0x3f,
-	0x19, 0xda, 0xd6, 0xee, 0xdf, 0xb3, 0xbb, 0xe4, 0xde, 0xfa, 0x30, 0x73, 0x6f, 0xa9, 0x81, 0x65,
-	0x42, 0xa6, 0xd0, 0x7a, 0xa6, 0x21, 0x63, 0x88, 0x90, 0xb9, 0xd9, 0xef, 0x87, 0x3c, 0x64, 0xc8,
-	0x77, 0x49, 0xc8, 0x3c, 0x93, 0x43, 0xa6, 0xb0, 0xb8, 0x50, 0xfd, 0x9d, 0xa1, 0xe9, 0x6f, 0x89,
-	0x8b, 0xb6, 0x0f, 0x0e, 0xf6, 0xa9, 0xce, 0xe4, 0x08, 0xf1, 0x71, 0xf2, 0x28, 0x2e, 0xc1, 0xe1,
-	0xc3, 0xb4, 0x8d, 0xb4, 0xa4, 0x36, 0x52, 0xdf, 0x14, 0x7d, 0x54, 0x6c, 0x8a, 0x72, 0x30, 0x32,
-	0xd7, 0x91, 0xba, 0xdd, 0xfe, 0x7b, 0x48, 0x4b, 0x50, 0x9d, 0xa8, 0x5b, 0x35, 0x25, 0xaa, 0x17,
-	0x86, 0xa6, 0xd3, 0xbf, 0xf8, 0xcf, 0x05, 0x53, 0xfa, 0xb9, 0x50, 0x82, 0xee, 0x63, 0x19, 0x9d,
-	0x52, 0xb5, 0xdc, 0x48, 0xaa, 0xdf, 0x1a, 0xf2, 0xe0, 0x4a, 0xd4, 0x7d, 0x22, 0xab, 0x53, 0x2e,
-	0x26, 0xd4, 0xf9, 0x9a, 0xf7, 0x8b, 0x82, 0xba, 0xdb, 0x5a, 0x75, 0xa7, 0x46, 0x51, 0x9f, 0xd6,
-	0xbc, 0x3b, 0xa4, 0x11, 0x88, 0xc6, 0x81, 0x1f, 0x61, 0xa2, 0x62, 0xef, 0x1e, 0x55, 0x51, 0x77,
-	0xcd, 0xbd, 0x7b, 0x24, 0xcb, 0xdf, 0x0e, 0xc3, 0x20, 0xa4, 0x4d, 0x7c, 0xc3, 0x65, 0x03, 0xf1,
-	0xcf, 0xcd, 0xa2, 0xe7, 0x8a, 0x0d, 0x9c, 0x6f, 0x0c, 0xd5, 0xeb, 0xca, 0x4b, 0x3c, 0x01, 0xfa,
-	0x0b, 0xf6, 0x53, 0x66, 0xaf, 0x9d, 0xde, 0x2e, 0x5a, 0xe7, 0xf6, 0x8b, 0x2f, 0x3d, 0x05, 0xbf,
-	0xea, 0xf3, 0xc1, 0x67, 0x4c, 0xcf, 0x8a, 0x94, 0x91, 0xa4, 0x85, 0x52, 0x2d, 0x7f, 0x05, 0x00,
-	0x00, 0xff, 0xff, 0x0e, 0x26, 0x9f, 0x1d, 0xcd, 0x1c, 0x00, 0x00,
+	// 2532 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x1a, 0x4d, 0x6f, 0x1c, 0x49,
+	0x55, 0xdd, 0x3d, 0x33, 0x9e, 0x29, 0x7f, 0x6e, 0xd9, 0x71, 0x3a, 0x5f, 0x66, 0xd4, 0x8a, 0x82,
+	0xb5, 0x42, 0x61, 0x35, 0x48, 0x7b, 0x02, 0x84, 0x77, 0xc6, 0x4e, 0xac, 0xc4, 0xc9, 0x50, 0x76,
+	0xd8, 0xcb, 0x5e, 0x7a, 0x67, 0xca, 0x4e, 0x93, 0x99, 0xee, 0xa1, 0xbb, 0x27, 0xb1, 0x59, 0xb2,
+	0x04, 0xfe, 0x02, 0x42, 0x1c, 0xf6, 0x04, 0x1c, 0x38, 0x22, 0x90, 0xe0, 0xc2, 0x19, 0x38, 0x70,
+	0xe0, 0xc2, 0x65, 0x4f, 0xb9, 0x21, 0x71, 0xe4, 0x1f, 0xa0, 0x7a, 0x55, 0xd5, 0x55, 0xdd, 0x5d,
+	0xdd, 0x1e, 0x43, 0xb8, 0x75, 0xbd, 0xf7, 0xea, 0x7d, 0xd5, 0xab, 0xf7, 0x5e, 0xbd, 0x19, 0xb4,
+	0x19, 0x84, 0x29, 0x8d, 0x43, 0x7f, 0xf2, 0xf5, 0x29, 0x4d, 0xfd, 0xfb, 0xb3, 0x38, 0x4a, 0x23,
+	0xdc, 0x60, 0xdf, 0xde, 0x5b, 0x07, 0x35, 0x06, 0x7e, 0xea, 0x63, 0x8c, 0x1a, 0x27, 0x34, 0x9e,
+	0xba, 0x56, 0xd7, 0xde, 0x6d, 0x10, 0xf8, 0xc6, 0x5b, 0xa8, 0x79, 0x18, 0x8e, 0xe9, 0xb9, 0x6b,
+	0x03, 0x90, 0x2f, 0xf0, 0x6d, 0xd4, 0xe9, 0x4f, 0xe6, 0x49, 0x4a, 0xe3, 0xc3, 0x81, 0xeb, 0x00,
+	0x46, 0x01, 0xf0, 0x5d, 0xd4, 0x7c, 0x12, 0x8d, 0x69, 0xe2, 0x36, 0xba, 0xce, 0xee, 0x72, 0x6f,
+	0xed, 0x3e, 0x88, 0x64, 0xa0, 0xc3, 0xf0, 0x34, 0x22, 0x1c, 0x89, 0x3f, 0x40, 0x1d, 0x26, 0xf5,
+	0x53, 0x3f, 0xa1, 0x89, 0xdb, 0x04, 0x4a, 0xcc, 0x29, 0x25, 0x18, 0xa8, 0x15, 0x11, 0xe3, 0xfb,
+	0x2c, 0xa1, 0x71, 0xe2, 0xb6, 0x74, 0xbe, 0x0c, 0xc4, 0xf9, 0x02, 0x92, 0xe9, 0x76, 0xe4, 0x9f,
+	0x83, 0xb4, 0x81, 0xbb, 0xc4, 0x75, 0xcb, 0x00, 0x78, 0x17, 0xad, 0x1f, 0xf9, 0xe7, 0xc7, 0xcf,
+	0xfd, 0x78, 0xfc, 0x20, 0x8e, 0xe6, 0xb3, 0xc3, 0x81, 0xdb, 0x06, 0x9a, 0x22, 0x18, 0xef, 0x20,
+	0x24, 0x41, 0x87, 0x03, 0xb7, 0x03, 0x44, 0x1a, 0x04, 0x7f, 0x8d, 0xeb, 0xcf, 0x2d, 0x45, 0x46,
+	0x4b, 0x15, 0x01, 0xa3, 0x3e, 0xa2, 0x92, 0x7a, 0xd9, 0x4c, 0x9d, 0x11, 0x30, 0x4b, 0x49, 0x34,
+	0xa1, 0x89, 0xbb, 0xa2, 0x53, 0x32, 0x10, 0xb7, 0x14, 0x90, 0xf8, 0xab, 0xa8, 0x75, 0x12, 0xbd,
+	0xa0, 0x61, 0xe2, 0xae, 0x02, 0xd9, 0x3a, 0x27, 0x03, 0x18, 0xd0, 0x09, 0xb4, 0xf7, 0x09, 0x6a,
+	0x4b, 0x29, 0x78, 0x0d, 0xd9, 0x87, 0x03, 0x71, 0xc4, 0xf6, 0xe1, 0x80, 0x1d, 0xfa, 0xc3, 0x28,
+	0x49, 0xe1, 0x7c, 0x3b, 0x04, 0xbe, 0xb1, 0x8b, 0x96, 0x4e, 0xfa, 0x43, 0x00, 0x3b, 0x5d, 0x6b,
+	0xb7, 0x43, 0xe4, 0x12, 0x42, 0xc4, 0x3f, 0xe3, 0x27, 0xdb, 0x21, 0xf0, 0xed, 0xfd, 0xc3, 0x46,
+	0x2b, 0xfa, 0x91, 0x31, 0xa2, 0x27, 0xfe, 0x94, 0x82, 0x90, 0x0e, 0x81, 0x6f, 0xfc, 0x21, 0xda,
+	0x1e, 0xd0, 0x53, 0x7f, 0x3e, 0x49, 0x09, 0x4d, 0x69, 0x98, 0x06, 0x51, 0x38, 0x8c, 0x26, 0xc1,
+	0xe8, 0x42, 0x08, 0xae, 0xc0, 0xe2, 0x07, 0xe8, 0xbd, 0x3c, 0x28, 0xa0, 0x89, 0xeb, 0x80, 0xb9,
+	0x37, 0x84, 0x57, 0xf2, 0x3b, 0xc0, 0xf0, 0xf2, 0x1e, 0xc6, 0xa8, 0x1f, 0x85, 0x69, 0x10, 0xce,
+	0xa3, 0x79, 0xf2, 0xdd, 0x39, 0x8d, 0x83, 0x2c, 0x40, 0x05, 0xa3, 0x3c, 0x5a, 0x30, 0x2a, 0xed,
+	0x91, 0x71, 0x01, 0x8b, 0x27, 0x6e, 0xb3, 0x6b, 0xed, 0x3a, 0x44, 0x83, 0x88, 0x08, 0xfb, 0x9e,
+	0x3f, 0x99, 0xd3, 0x64, 0x48, 0xe3, 0x13, 0xff, 0xcc, 0x6d, 0x01, 0x51, 0x11, 0xcc, 0x22, 0x15,
+	0xae, 0xd3, 0xc9, 0xc5, 0x8c, 0xba, 0x4b, 0xe0, 0x68, 0x05, 0xf0, 0xfe, 0xe5, 0xa0, 0xcd, 0x82,
+	0x6d, 0xc7, 0x33, 0x3a, 0xd2, 0xbc, 0x6b, 0x65, 0xde, 0xbd, 0x89, 0xda, 0x83, 0x79, 0xec, 0x33,
+	0x4a, 0xd7, 0x06, 0x61, 0xd9, 0x1a, 0xdf, 0x47, 0x58, 0xc5, 0x75, 0x46, 0xe5, 0x00, 0x95, 0x01,
+	0xc3, 0x78, 0x11, 0x3a, 0x9b, 0x04, 0x23, 0xff, 0x89, 0xdb, 0xe8, 0x5a, 0xbb, 0xab, 0x24, 0x5b,
+	0xe3, 0xf7, 0xd1, 0xc6, 0xc1, 0x3c, 0x9d, 0xc7, 0xf4, 0xe3, 0x38, 0x48, 0xe9, 0xe3, 0x60, 0x1a,
+	0xa4, 0xc2, 0x03, 0x25, 0x38, 0xbe, 0x87, 0xd6, 0x86, 0x7e, 0x92, 0x6a, 0x94, 0xdc, 0x0d, 0x05,
+	0x28, 0xe4, 0x92, 0x68, 0x32, 0xde, 0x3b, 0x4d, 0x69, 0x0c, 0x5e, 0x70, 0x88, 0x02, 0x30, 0x6f,
+	0x0e, 0xa2, 0x57, 0x61, 0xe2, 0x4f, 0x67, 0x13, 0xda, 0xf7, 0x27, 0x93, 0xc4, 0x6d, 0x43, 0xec,
+	0x15, 0xc1, 0x79, 0xca, 0xfd, 0x97, 0x34, 0xbe, 0x70, 0x3b, 0xdc, 0xef, 0x05, 0x30, 0xd3, 0x4c,
+	0x81, 0x0e, 0xc3, 0x34, 0x72, 0x11, 0xf8, 0xb2, 0x00, 0x65, 0x9a, 0x0d, 0x27, 0xfe, 0x88, 0x4e,
+	0x69, 0x98, 0xba, 0xcb, 0xfc, 0x7c, 0x32, 0x00, 0xbe, 0x8b, 0x56, 0x07, 0x74, 0x42, 0x99, 0xcf,
+	0x06, 0x74, 0xe2, 0x5f, 0xb8, 0x2b, 0x20, 0x2d, 0x0f, 0x64, 0x54, 0x47, 0xfe, 0x39, 0x04, 0x14,
+	0xf1, 0xc3, 0x33, 0xea, 0xae, 0x72, 0xaa, 0x1c, 0xd0, 0x7b, 0xdb, 0x28, 0x9d, 0x75, 0xe5, 0x4d,
+	0xca, 0x9f, 0xb5, 0xbd, 0xd0, 0x59, 0xdb, 0x0b, 0x9d, 0xb5, 0x9d, 0x3b, 0xeb, 0x0f, 0xd1, 0xb2,
+	0xda, 0x21, 0x33, 0xf4, 0x16, 0xbf, 0x2a, 0x0a, 0x01, 0xb7, 0x44, 0x27, 0xc4, 0xdf, 0x44, 0xab,
+	0xc7, 0xf3, 0x4f, 0x93, 0x51, 0x1c, 0xcc, 0x98, 0x0c, 0x99, 0xad, 0xb7, 0xc5, 0x4e, 0x0d, 0x05,
+	0x7b, 0xf3, 0xc4, 0xc6, 0x08, 0x5b, 0x5a, 0x38, 0xc2, 0xda, 0x97, 0x47, 0x58, 0x67, 0x81, 0x08,
+	0x43, 0x0b, 0x47, 0xd8, 0xf2, 0xa2, 0x11, 0xb6, 0x72, 0x79, 0x84, 0xad, 0x5e, 0x1a, 0x61, 0x6b,
+	0x0b, 0x45, 0xd8, 0xba, 0x29, 0xc2, 0xbe, 0xb4, 0xd0, 0x5a, 0xfe, 0xd4, 0x4a, 0x95, 0xe0, 0x36,
+	0xea, 0x1c, 0xa7, 0x7e, 0x9c, 0x9e, 0x04, 0x53, 0x2a, 0x22, 0x4b, 0x01, 0x58, 0x4d, 0xd8, 0x0f,
+	0xc7, 0x80, 0xe3, 0xf1, 0x24, 0x97, 0x6c, 0x1f, 0x68, 0x44, 0xc7, 0x7b, 0x29, 0x44, 0x91, 0x43,
+	0x14, 0x80, 0x15, 0x29, 0x90, 0x2b, 0x23, 0x68, 0x5d, 0x8b, 0x20, 0x5e, 0xa4, 0x38, 0x1a, 0x77,
+	0xd1, 0xf2, 0x49, 0x3c, 0x0f, 0x47, 0x3e, 0x67, 0xc4, 0x93, 0x85, 0x0e, 0xc2, 0xdb, 0xa8, 0x35,
+	0x9c, 0xc7, 0x67, 0x74, 0x0c, 0x11, 0xd1, 0x26, 0x62, 0xe5, 0x51, 0xd4, 0xc9, 0xd8, 0x95, 0xac,
+	0xda, 0x41, 0xed, 0xa7, 0xaf, 0x42, 0xd6, 0x97, 0x24, 0xae, 0xdd, 0x75, 0x76, 0x1b, 0x1f, 0xd9,
+	0xae, 0x45, 0x32, 0x18, 0xde, 0x45, 0x2d, 0xf8, 0x96, 0x55, 0x65, 0x43, 0xd3, 0x0f, 0x10, 0x44,
+	0xe0, 0xbd, 0xbf, 0x58, 0x68, 0xa3, 0x18, 0xbe, 0xc6, 0x1b, 0x8a, 0x51, 0xe3, 0x28, 0x1a, 0x53,
+	0x59, 0x52, 0xd9, 0x37, 0xf6, 0xd0, 0xca, 0x80, 0x26, 0x69, 0x10, 0xfa, 0xfc, 0x52, 0x38, 0x10,
+	0x62, 0x39, 0x18, 0xb3, 0xef, 0x20, 0x98, 0xb0, 0x20, 0x6d, 0x40, 0x28, 0x88, 0x15, 0x33, 0xa9,
+	0xbf, 0x07, 0x79, 0xb6, 0x43, 0xec, 0xfe, 0x1e, 0xe3, 0xdf, 0xa7, 0x31, 0x77, 0x51, 0x87, 0xc0,
+	0x37, 0xde, 0x40, 0xce, 0x23, 0x7a, 0x21, 0xaa, 0x08, 0xfb, 0x64, 0x07, 0xf6, 0x31, 0x0d, 0xce,
+	0x9e, 0xa7, 0x3c, 0x63, 0x3a, 0x44, 0x2e, 0xbd, 0xbb, 0x08, 0x29, 0xf3, 0x98, 0x54, 0xd1, 0x2c,
+	0x71, 0xa7, 0x89, 0x95, 0xf7, 0x07, 0x0b, 0x6d, 0x1a, 0x4a, 0xa2, 0xd1, 0xe2, 0x2d, 0xd4, 0x04,
+	0x02, 0x61, 0x32, 0x5f, 0xb0, 0xc8, 0x24, 0x54, 0xbf, 0x2d, 0xbc, 0xe8, 0xe4, 0x81, 0xec, 0xdc,
+	0x25, 0xe0, 0x20, 0xe2, 0xa6, 0x3b, 0x44, 0x07, 0x31, 0x0d, 0x9f, 0x9e, 0x9e, 0x26, 0x54, 0xd6,
+	0x1a, 0xb1, 0x62, 0x52, 0xc1, 0x04, 0xe1, 0x08, 0xbe, 0xf0, 0x7e, 0xd9, 0x44, 0x6d, 0xd9, 0x13,
+	0x56, 0x1d, 0xcf, 0x43, 0x3f, 0x79, 0x9e, 0x75, 0x3c, 0x7e, 0xf2, 0x9c, 0xb1, 0xda, 0x1b, 0x4f,
+	0x03, 0x9e, 0x2b, 0xdb, 0x84, 0x2f, 0xf0, 0x37, 0x10, 0x1a, 0xc6, 0xc1, 0xcb, 0x60, 0x42, 0xcf,
+	0xb2, 0x66, 0x61, 0x53, 0x75, 0x9d, 0x19, 0x8e, 0x68, 0x64, 0x8c, 0x15, 0xef, 0xdd, 0x9a, 0x70,
+	0xc4, 0x7c, 0x81, 0x87, 0xe8, 0xda, 0x11, 0xf5, 0x93, 0x79, 0x0c, 0x57, 0x5b, 0xe3, 0xca, 0xb3,
+	0xe3, 0x4d, 0xce, 0xd5, 0x44, 0x42, 0xcc, 0x1b, 0xf1, 0x27, 0xe8, 0x46, 0xa1, 0x64, 0x68, 0x5c,
+	0x97, 0x80, 0xeb, 0x8e, 0xb1, 0x43, 0x52, 0x9c, 0xab, 0x19, 0x30, 0x9f, 0x3f, 0x8e, 0x46, 0x2f,
+	0xe8, 0x18, 0x72, 0x6a, 0x9b, 0x88, 0x15, 0xee, 0xa1, 0xad, 0x23, 0xff, 0xbc, 0x1f, 0x85, 0xa3,
+	0x79, 0x1c, 0xd3, 0x30, 0x95, 0x9d, 0x14, 0x4f, 0xab, 0x46, 0x1c, 0xfe, 0x00, 0x6d, 0x8a, 0x64,
+	0x14, 0x40, 0xef, 0x73, 0x14, 0x84, 0xf3, 0x94, 0x42, 0xd1, 0x75, 0x88, 0x09, 0xc5, 0xea, 0xd2,
+	0xfe, 0x39, 0x7f, 0xaf, 0x40, 0x8a, 0x6d, 0x93, 0x6c, 0x0d, 0x59, 0x98, 0xf7, 0x8a, 0xb2, 0xe9,
+	0x14, 0xc9, 0xb5, 0x08, 0xae, 0xe9, 0x39, 0x79, 0xaa, 0xad, 0xc0, 0x42, 0xbd, 0x88, 0xa9, 0xc8,
+	0x43, 0x6b, 0xa2, 0x5e, 0x48, 0x00, 0xc3, 0x3e, 0xf6, 0x93, 0xf4, 0x71, 0x74, 0x16, 0x84, 0x22,
+	0xd7, 0x2a, 0x00, 0x8b, 0x79, 0x08, 0xfe, 0x61, 0x1c, 0x44, 0x71, 0x90, 0x5e, 0xb8, 0x1b, 0x5d,
+	0x6b, 0xb7, 0x49, 0xf2, 0x40, 0xef, 0x73, 0xe4, 0x56, 0xb9, 0x1e, 0xea, 0xbb, 0x34, 0x8c, 0x87,
+	0x6d, 0xb6, 0x66, 0xb6, 0x9b, 0xdb, 0xe7, 0x75, 0x83, 0x0d, 0x19, 0x4b, 0x08, 0xea, 0x26, 0x51,
+	0x00, 0xef, 0xf7, 0x16, 0xda, 0x32, 0x45, 0xd5, 0xd5, 0x85, 0x5b, 0x26, 0xe1, 0x5d, 0xb4, 0xac,
+	0x71, 0x07, 0xf1, 0x1d, 0xa2, 0x83, 0xe0, 0x92, 0xd0, 0x33, 0x7a, 0x0e, 0xd7, 0xbd, 0x4d, 0xf8,
+	0x22, 0xaf, 0x74, 0xb3, 0xa8, 0xf4, 0x21, 0x5a, 0xcd, 0xdd, 0xba, 0x5a, 0x65, 0x73, 0xac, 0xec,
+	0x22, 0xab, 0x63, 0xd4, 0x96, 0x8f, 0x29, 0x63, 0x8a, 0xc8, 0x5f, 0x7c, 0x7b, 0xa1, 0x8b, 0xef,
+	0xfd, 0xdd, 0x42, 0x9d, 0xec, 0xed, 0xa5, 0xd5, 0xa1, 0x8e, 0x7c, 0x67, 0xb1, 0xad, 0x32, 0xeb,
+	0xb0, 0xef, 0x2c, 0x13, 0x31, 0x07, 0xad, 0x88, 0x4c, 0xa4, 0x1b, 0xc5, 0xcb, 0x40, 0x85, 0x51,
+	0x4d, 0x08, 0x2c, 0x05, 0xc8, 0x87, 0x6d, 0xab, 0x18, 0xb6, 0xdb, 0xa8, 0xb5, 0x7f, 0x3e, 0x0b,
+	0xe2, 0x0b, 0xd1, 0x4e, 0x89, 0x15, 0x93, 0xc7, 0xa2, 0xf7, 0x59, 0x22, 0xae, 0xba, 0x43, 0xb2,
+	0xb5, 0xf7, 0x65, 0x0b, 0x2d, 0xf5, 0xa3, 0xe9, 0xd4, 0x0f, 0xc7, 0xf8, 0x1e, 0x6a, 0xa4, 0xec,
+	0x9d, 0xc2, 0x2c, 0x5a, 0x93, 0x2f, 0x75, 0x81, 0xbc, 0xcf, 0x1e, 0x2c, 0x04, 0xf0, 0xde, 0x17,
+	0x2d, 0xd4, 0x60, 0x4b, 0x7c, 0x0d, 0xbd, 0xc7, 0xa5, 0xb3, 0x7a, 0x22, 0x08, 0x37, 0x2c, 0x06,
+	0xe6, 0xcd, 0x81, 0x0e, 0xb6, 0xf1, 0x0d, 0x74, 0x8d, 0x53, 0x4b, 0x63, 0x25, 0xca, 0xc1, 0xd7,
+	0xd1, 0xe6, 0x20, 0x8e, 0x66, 0x45, 0x44, 0x03, 0x77, 0xd1, 0x6d, 0xbe, 0xa7, 0x10, 0x7f, 0x92,
+	0xa2, 0x89, 0x77, 0xd0, 0x4d, 0xb6, 0xb5, 0x02, 0xdf, 0xc2, 0x77, 0x51, 0xf7, 0x98, 0xa6, 0xe6,
+	0x34, 0x20, 0xa9, 0x96, 0x98, 0x9c, 0x67, 0xb3, 0x71, 0xb5, 0x9c, 0x36, 0xbe, 0x85, 0xae, 0x73,
+	0x4d, 0x54, 0x8b, 0x25, 0x91, 0x1d, 0x86, 0xe4, 0x16, 0x97, 0x91, 0x48, 0xd9, 0x50, 0x28, 0xb5,
+	0x92, 0x62, 0x59, 0xda, 0x50, 0x81, 0x5f, 0x51, 0x7e, 0x66, 0x21, 0x25, 0xc1, 0xab, 0x78, 0x13,
+	0xad, 0xb3, 0x6d, 0x3a, 0x70, 0x8d, 0xd1, 0x72, 0x4b, 0x74, 0xf0, 0x3a, 0xf3, 0xf0, 0x31, 0x55,
+	0x59, 0x40, 0x22, 0x36, 0x30, 0x46, 0x6b, 0xcc, 0x3f, 0x7e, 0xea, 0x4b, 0xd8, 0x7b, 0xf8, 0x36,
+	0x72, 0x8f, 0x69, 0x0a, 0x05, 0xb2, 0xb4, 0x03, 0x2b, 0x09, 0xfa, 0xf1, 0x6e, 0xe2, 0x3b, 0xe8,
+	0x86, 0x70, 0x90, 0xd6, 0x40, 0x49, 0xf4, 0x35, 0x70, 0x51, 0x1c, 0xcd, 0x4c, 0xc8, 0x6d, 0xc6,
+	0x92, 0xd0, 0x69, 0xf4, 0x92, 0x0e, 0xa9, 0x52, 0xfa, 0xba, 0x8a, 0x18, 0x39, 0x36, 0x91, 0x28,
+	0x37, 0x1f, 0x4c, 0x3a, 0xea, 0x06, 0x43, 0x71, 0xfd, 0x8a, 0xa8, 0x9b, 0x0c, 0xc5, 0xcf, 0xa9,
+	0xc8, 0xf0, 0x96, 0x42, 0x15, 0x77, 0xdd, 0xc6, 0xdb, 0x08, 0x1f, 0xd3, 0xb4, 0xb8, 0xe5, 0x0e,
+	0xde, 0x42, 0x1b, 0x60, 0x12, 0x3b, 0x73, 0x09, 0xdd, 0x79, 0xbf, 0xdd, 0x1e, 0x6f, 0xbc, 0x79,
+	0xf3, 0xe6, 0x8d, 0xed, 0xbd, 0x36, 0x5c, 0x8f, 0x6c, 0x18, 0x63, 0x69, 0xc3, 0x18, 0x8c, 0x1a,
+	0xc4, 0x0f, 0xc7, 0x62, 0x00, 0x07, 0xdf, 0xbd, 0xef, 0xa0, 0xa5, 0x91, 0xd8, 0xb2, 0x9a, 0xbb,
+	0x89, 0x2e, 0xed, 0x5a, 0xbb, 0xcb, 0xbd, 0xeb, 0x02, 0x58, 0x14, 0x40, 0xe4, 0x36, 0xef, 0x33,
+	0xc3, 0x35, 0x2c, 0xf5, 0xce, 0x5b, 0xa8, 0x79, 0x10, 0xc5, 0x23, 0x9e, 0x40, 0xdb, 0x84, 0x2f,
+	0x6a, 0x84, 0x9f, 0xea, 0xc2, 0x4b, 0xec, 0x95, 0xf0, 0x3f, 0x5a, 0x15, 0xb7, 0xdd, 0x98, 0x8c,
+	0xfb, 0xe6, 0xba, 0x53, 0x3b, 0x00, 0x2a, 0xee, 0xe8, 0x0d, 0x2a, 0x95, 0x3e, 0x03, 0x5e, 0xb7,
+	0x74, 0x8f, 0x15, 0xb4, 0x52, 0x8a, 0x4f, 0x8d, 0xa9, 0xc8, 0xa4, 0x75, 0xef, 0xa3, 0x4a, 0x81,
+	0xcf, 0x75, 0xe5, 0x0d, 0xec, 0x94, 0xb8, 0x7f, 0x5a, 0xf5, 0x19, 0xae, 0xb6, 0x02, 0xf6, 0xcd,
+	0xbd, 0xc2, 0x95, 0xdc, 0xc6, 0x1e, 0x11, 0x22, 0x3b, 0x8a, 0xce, 0x58, 0x2e, 0x7b, 0x8f, 0x2a,
+	0xed, 0x0b, 0xc0, 0x3e, 0x4f, 0x77, 0xa8, 0x59, 0x7d, 0x65, 0xe8, 0x2f, 0xac, 0xba, 0x44, 0x5d,
+	0x6b, 0xa6, 0xf4, 0xbd, 0xad, 0xf9, 0xfe, 0xb0, 0x52, 0xb7, 0xef, 0x83, 0x6e, 0x5d, 0xe5, 0xfb,
+	0xcb, 0x34, 0xfb, 0xb5, 0x75, 0x79, 0x89, 0xb8, 0xb2, 0x7e, 0x4f, 0x2b, 0xf5, 0x7b, 0x01, 0xfa,
+	0xdd, 0xe3, 0xc0, 0xcb, 0xe4, 0x2a, 0x2d, 0xff, 0x6d, 0xd7, 0x97, 0xa8, 0xab, 0x6a, 0xc8, 0xce,
+	0xfd, 0x09, 0x7d, 0x05, 0x60, 0x31, 0x01, 0x16, 0xcb, 0xdc, 0xf8, 0xa9, 0x51, 0x18, 0x35, 0xea,
+	0xe3, 0xa4, 0x66, 0x61, 0x74, 0xa8, 0x45, 0x52, 0x2b, 0x17, 0x49, 0xf9, 0x21, 0xc8, 0xd2, 0xa5,
+	0x43, 0x90, 0xf6, 0x42, 0x43, 0x90, 0x8e, 0x61, 0x08, 0x52, 0x13, 0xb3, 0x13, 0x3d, 0x66, 0xeb,
+	0x3c, 0xa9, 0x7c, 0xfe, 0x67, 0xab, 0xb2, 0xe8, 0xbf, 0xbb, 0x1e, 0x9e, 0x0d, 0x58, 0x92, 0xd4,
+	0x9f, 0xce, 0xc4, 0xd0, 0x45, 0x01, 0x7a, 0x07, 0x95, 0xc6, 0x4c, 0xc1, 0x98, 0x3b, 0xfa, 0x05,
+	0x2c, 0xa9, 0xa8, 0xec, 0xf8, 0x9b, 0x55, 0xd9, 0x9f, 0xbc, 0x23, 0x3b, 0x3c, 0xb4, 0x92, 0xfb,
+	0xc1, 0x85, 0xff, 0x60, 0x94, 0x83, 0xd5, 0x58, 0x13, 0xea, 0xd6, 0x54, 0x28, 0xaa, 0xac, 0xf9,
+	0xab, 0x55, 0xdf, 0x50, 0x5d, 0xf9, 0x26, 0x64, 0xa3, 0x0d, 0x47, 0x1f, 0x6d, 0x64, 0xa3, 0x87,
+	0x86, 0x36, 0x7a, 0xa8, 0x89, 0xaf, 0xa8, 0x9c, 0x13, 0xcd, 0xfa, 0x95, 0x73, 0xe2, 0xbb, 0xb1,
+	0xa3, 0x26, 0x27, 0xce, 0x8a, 0x39, 0xf1, 0x32, 0xcd, 0x7e, 0x67, 0x19, 0x5a, 0xce, 0xff, 0x71,
+	0xd4, 0x92, 0x7b, 0xbc, 0x34, 0x0a, 0x8f, 0x97, 0x9a, 0x96, 0xe3, 0x07, 0xe5, 0x7e, 0x47, 0x53,
+	0x4a, 0xe9, 0x4c, 0x4b, 0xed, 0xb0, 0xb1, 0x6a, 0x7f, 0xbb, 0x52, 0x50, 0x0c, 0x82, 0xae, 0x29,
+	0x2f, 0x19, 0xc5, 0xbc, 0x36, 0x34, 0xd8, 0x8b, 0x7a, 0xa6, 0xc6, 0xca, 0x44, 0xb7, 0xb2, 0x24,
+	0x40, 0x89, 0xff, 0xad, 0x65, 0xec, 0xe4, 0x59, 0xb0, 0x30, 0xfa, 0x50, 0x69, 0x91, 0xad, 0x73,
+	0x81, 0x64, 0xd7, 0xbd, 0xa2, 0x8b, 0x53, 0x84, 0x9a, 0x16, 0x27, 0xd5, 0x5b, 0x1c, 0x83, 0x42,
+	0x4a, 0xe3, 0xa8, 0xf8, 0xc2, 0xc0, 0x3b, 0xfc, 0xd7, 0x68, 0xd0, 0x73, 0xb9, 0x87, 0xd4, 0x4f,
+	0xc2, 0x04, 0xe0, 0xbd, 0x6f, 0x55, 0x4a, 0x9d, 0x77, 0x2d, 0xed, 0x27, 0x8a, 0x1c, 0x57, 0x25,
+	0xf0, 0xe7, 0x56, 0xf5, 0xfb, 0xa5, 0xd6, 0x4f, 0x59, 0xdc, 0xda, 0x5a, 0xdc, 0xf6, 0x1e, 0x54,
+	0x6a, 0xf3, 0xb2, 0x6b, 0xa9, 0x11, 0x5c, 0x95, 0x44, 0xa5, 0xd7, 0x85, 0xe1, 0xe1, 0xb4, 0xc8,
+	0x8f, 0xb5, 0x35, 0x51, 0xf3, 0xaa, 0x1c, 0x35, 0xc6, 0x76, 0xfc, 0xad, 0x5d, 0xf3, 0x3a, 0xab,
+	0xfc, 0x0d, 0xaa, 0x2a, 0x66, 0x0c, 0x75, 0xc1, 0x31, 0xd7, 0x05, 0x39, 0x27, 0x6f, 0xd4, 0xcc,
+	0xc9, 0x9b, 0xb5, 0x73, 0xf2, 0x96, 0x61, 0x4e, 0xbe, 0x54, 0x9a, 0x93, 0xb7, 0xcb, 0x73, 0xf2,
+	0x8e, 0x71, 0x4e, 0x8e, 0x72, 0x73, 0xf2, 0xde, 0xc3, 0x4a, 0xcf, 0x5e, 0x80, 0x67, 0xbf, 0x92,
+	0xab, 0xb0, 0x65, 0xd7, 0x29, 0x0f, 0xff, 0xc9, 0xaa, 0x7c, 0xe0, 0xfe, 0xff, 0xfc, 0x5b, 0x53,
+	0x53, 0x7f, 0x98, 0xab, 0xa9, 0x66, 0xc5, 0x72, 0xa1, 0x59, 0x7a, 0x80, 0x67, 0xa1, 0x69, 0xa9,
+	0xd0, 0xdc, 0x1b, 0x8f, 0xb3, 0xf9, 0x16, 0xfb, 0xae, 0x09, 0xcd, 0xcf, 0xf4, 0xd0, 0x2c, 0x31,
+	0x57, 0xa2, 0x7f, 0x63, 0x55, 0xbc, 0xf2, 0x99, 0x8b, 0x1e, 0x9e, 0x9c, 0x0c, 0x41, 0xa6, 0xb8,
+	0xaa, 0x72, 0x2d, 0xfe, 0xbf, 0xa0, 0xa9, 0x23, 0x97, 0xd9, 0x63, 0xda, 0xd1, 0x1e, 0xd3, 0xd5,
+	0x4f, 0xc3, 0x1f, 0x95, 0x9f, 0x86, 0x05, 0x35, 0x94, 0xa6, 0x3f, 0xb3, 0x2a, 0x86, 0x0e, 0xff,
+	0x9d, 0xa6, 0x35, 0x5a, 0xbd, 0x36, 0x3f, 0x58, 0x8d, 0x5a, 0x7d, 0x61, 0x55, 0xcc, 0x3b, 0xae,
+	0xfe, 0x3f, 0x10, 0x5b, 0xfb, 0x1f, 0x48, 0x8d, 0x76, 0x9f, 0xeb, 0xda, 0x19, 0x45, 0xeb, 0xcf,
+	0x69, 0xf3, 0xc4, 0xa5, 0xa8, 0x5c, 0x8d, 0xb8, 0x1f, 0xeb, 0xe2, 0x8c, 0xcc, 0x94, 0xb8, 0xb0,
+	0x62, 0x8a, 0x53, 0x12, 0xb7, 0x5f, 0x29, 0xee, 0x8d, 0x55, 0x96, 0x57, 0x69, 0xde, 0x01, 0x7b,
+	0x0e, 0x25, 0xb3, 0x28, 0x4c, 0x28, 0x13, 0xf1, 0xf4, 0x11, 0x88, 0x68, 0x13, 0xfb, 0xe9, 0x23,
+	0x56, 0x4d, 0xf6, 0xe3, 0x38, 0x8a, 0xc5, 0x08, 0x9d, 0x2f, 0xd4, 0xbf, 0xad, 0x1c, 0xb8, 0x57,
+	0x7c, 0xe1, 0xfd, 0xca, 0x32, 0xcd, 0x98, 0xde, 0xe1, 0x0d, 0xa8, 0x2e, 0xe4, 0x3f, 0xe1, 0xf6,
+	0xba, 0x59, 0x15, 0xab, 0x74, 0xee, 0xb8, 0x3c, 0xef, 0x2a, 0xf9, 0xb5, 0x3a, 0x1f, 0xfc, 0x94,
+	0xcb, 0xd9, 0xd6, 0x32, 0x92, 0xc6, 0x28, 0x93, 0xf2, 0x9f, 0x01, 0x00, 0x1f, 0x02, 0xdf, 0x41,
+	0xc7, 0x26, 0x00, 0x00,
 }
@@ -0,0 +1,198 @@
+package meta
+
+import (
+	crand "crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cnosdb/cnosdb/vend/common/monitor/diagnostics"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// PasswordHashBcrypt hashes passwords with bcrypt.
+	PasswordHashBcrypt = "bcrypt"
+
+	// PasswordHashArgon2id hashes passwords with argon2id.
+	PasswordHashArgon2id = "argon2id"
+
+	// argon2SaltBytes and argon2KeyBytes are the salt and key lengths of
+	// argon2id hashes.
+	argon2SaltBytes = 16
+	argon2KeyBytes  = 32
+)
+
+// ErrPasswordHashFormat is returned when a stored password hash can't be parsed.
+var ErrPasswordHashFormat = errors.New("unrecognized password hash format")
+
+// PasswordHasher hashes new passwords with the configured algorithm and
+// tells which stored hashes use an older or weaker setting.
+type PasswordHasher struct {
+	// Algorithm is either PasswordHashBcrypt or PasswordHashArgon2id.
+	Algorithm string
+
+	// Cost of bcrypt hashes.
+	BcryptCost int
+
+	// Iterations, memory in KiB and parallelism of argon2id hashes.
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// NewPasswordHasher returns a hasher using the password settings of c.
+func NewPasswordHasher(c *Config) *PasswordHasher {
+	h := &PasswordHasher{
+		Algorithm:     c.PasswordHash,
+		BcryptCost:    c.BcryptCost,
+		Argon2Time:    c.Argon2Time,
+		Argon2Memory:  c.Argon2Memory,
+		Argon2Threads: c.Argon2Threads,
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = DefaultPasswordHash
+	}
+	if h.BcryptCost == 0 {
+		h.BcryptCost = bcryptCost
+	}
+	if h.Argon2Time == 0 {
+		h.Argon2Time = DefaultArgon2Time
+	}
+	if h.Argon2Memory == 0 {
+		h.Argon2Memory = DefaultArgon2Memory
+	}
+	if h.Argon2Threads == 0 {
+		h.Argon2Threads = DefaultArgon2Threads
+	}
+	return h
+}
+
+// defaultPasswordHasher is used by clients that weren't given a hasher.
+func defaultPasswordHasher() *PasswordHasher {
+	return NewPasswordHasher(NewConfig())
+}
+
+// Hash returns the hash of password using the configured algorithm.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	switch h.Algorithm {
+	case PasswordHashArgon2id:
+		salt := make([]byte, argon2SaltBytes)
+		if _, err := io.ReadFull(crand.Reader, salt); err != nil {
+			return "", err
+		}
+		p := argon2Params{time: h.Argon2Time, memory: h.Argon2Memory, threads: h.Argon2Threads}
+		key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyBytes)
+		return p.encode(salt, key), nil
+	case PasswordHashBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	default:
+		return "", fmt.Errorf("unknown password hash algorithm: %q", h.Algorithm)
+	}
+}
+
+// NeedsRehash returns true if hash was created with another algorithm or
+// with a lower cost than the configured one.
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		if h.Algorithm != PasswordHashArgon2id {
+			return true
+		}
+		p, _, _, err := decodeArgon2(hash)
+		if err != nil {
+			return false
+		}
+		return p.time < h.Argon2Time || p.memory < h.Argon2Memory || p.threads < h.Argon2Threads
+	}
+
+	if h.Algorithm != PasswordHashBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return cost < h.BcryptCost
+}
+
+// Diagnostics returns the settings new password hashes are created with.
+func (h *PasswordHasher) Diagnostics() (*diagnostics.Diagnostics, error) {
+	m := map[string]interface{}{
+		"algorithm": h.Algorithm,
+	}
+	switch h.Algorithm {
+	case PasswordHashArgon2id:
+		m["argon2-time"] = h.Argon2Time
+		m["argon2-memory"] = h.Argon2Memory
+		m["argon2-threads"] = h.Argon2Threads
+	case PasswordHashBcrypt:
+		m["bcrypt-cost"] = h.BcryptCost
+	}
+	return diagnostics.RowFromMap(m), nil
+}
+
+// comparePassword returns nil if password matches hash, which may have been
+// created with any supported algorithm.
+func comparePassword(hash, password string) error {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	p, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return err
+	}
+	other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrAuthenticate
+	}
+	return nil
+}
+
+// argon2Params are the parameters an argon2id hash was created with.
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// encode returns the hash in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>.
+func (p argon2Params) encode(salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+// decodeArgon2 parses an argon2id hash in the PHC string format.
+func decodeArgon2(hash string) (p argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrPasswordHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrPasswordHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, ErrPasswordHashFormat
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, ErrPasswordHashFormat
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return p, nil, nil, ErrPasswordHashFormat
+	}
+	return p, salt, key, nil
+}
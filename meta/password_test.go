@@ -0,0 +1,115 @@
+package meta
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher_Hash(t *testing.T) {
+	for _, h := range []*PasswordHasher{
+		{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost},
+		{Algorithm: PasswordHashArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1},
+	} {
+		t.Run(h.Algorithm, func(t *testing.T) {
+			hash, err := h.Hash("secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := comparePassword(hash, "secret"); err != nil {
+				t.Fatalf("unexpected error comparing correct password: %s", err)
+			}
+			if err := comparePassword(hash, "wrong"); err == nil {
+				t.Fatal("expected error comparing wrong password")
+			}
+			if h.NeedsRehash(hash) {
+				t.Fatal("hash created with the current settings needs rehash")
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	bcryptLow := &PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost}
+	bcryptHigh := &PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost + 1}
+	argonLow := &PasswordHasher{Algorithm: PasswordHashArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1}
+	argonHigh := &PasswordHasher{Algorithm: PasswordHashArgon2id, Argon2Time: 2, Argon2Memory: 1024, Argon2Threads: 1}
+
+	hash := func(h *PasswordHasher) string {
+		s, err := h.Hash("secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	for _, tt := range []struct {
+		name   string
+		hasher *PasswordHasher
+		hash   string
+		exp    bool
+	}{
+		{name: "bcrypt lower cost", hasher: bcryptHigh, hash: hash(bcryptLow), exp: true},
+		{name: "bcrypt higher cost", hasher: bcryptLow, hash: hash(bcryptHigh), exp: false},
+		{name: "bcrypt to argon2id", hasher: argonLow, hash: hash(bcryptLow), exp: true},
+		{name: "argon2id to bcrypt", hasher: bcryptLow, hash: hash(argonLow), exp: true},
+		{name: "argon2id fewer iterations", hasher: argonHigh, hash: hash(argonLow), exp: true},
+		{name: "argon2id more iterations", hasher: argonLow, hash: hash(argonHigh), exp: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hash); got != tt.exp {
+				t.Fatalf("NeedsRehash() = %v, expected %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestClient_Authenticate_UpgradesHash(t *testing.T) {
+	c := NewClient(&Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hashOf := func(name string) string {
+		data := c.Data()
+		return data.user(name).Hash
+	}
+
+	// Create the user with the old setting.
+	c.WithPasswordHasher(&PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost})
+	if _, err := c.CreateUser("alice", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	old := hashOf("alice")
+
+	c.WithPasswordHasher(&PasswordHasher{Algorithm: PasswordHashArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1})
+
+	// A wrong password must not change the hash.
+	if _, err := c.Authenticate("alice", "wrong"); err != ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	} else if hash := hashOf("alice"); hash != old {
+		t.Fatal("hash changed by failed authentication")
+	}
+
+	// The old hash keeps verifying and is upgraded.
+	if _, err := c.Authenticate("alice", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	upgraded := hashOf("alice")
+	if !strings.HasPrefix(upgraded, "$argon2id$") {
+		t.Fatalf("hash not upgraded: %s", upgraded)
+	}
+
+	// Later authentications, cached or not, keep the upgraded hash.
+	for i := 0; i < 2; i++ {
+		if _, err := c.Authenticate("alice", "secret"); err != nil {
+			t.Fatal(err)
+		}
+		delete(c.authCache, "alice")
+	}
+	if hash := hashOf("alice"); hash != upgraded {
+		t.Fatal("hash upgraded more than once")
+	}
+}
@@ -54,6 +54,22 @@ func (a *QueryAuthorizer) AuthorizeQuery(u User, q *cnosql.Query, database strin
 	// Enterprise UserInfo in closed-source code.
 	switch user := u.(type) {
 	case *UserInfo:
+		// Tokens can't manage tokens, which would let them get around their
+		// own restrictions and expiry.
+		if user.token != "" {
+			for _, stmt := range q.Statements {
+				switch stmt.(type) {
+				case *cnosql.CreateTokenStatement, *cnosql.RevokeTokenStatement:
+					return nil, &ErrAuthorize{
+						Query:    q,
+						User:     user.Name,
+						Database: database,
+						Message:  fmt.Sprintf("statement '%s', can't be executed with an API token", stmt),
+					}
+				}
+			}
+		}
+
 		// Admin privilege allows the user to execute all statements.
 		if user.Admin {
 			return query.OpenAuthorizer, nil
@@ -88,7 +104,11 @@ func (a *QueryAuthorizer) AuthorizeQuery(u User, q *cnosql.Query, database strin
 				if db == "" {
 					db = database
 				}
-				if !user.AuthorizeDatabase(p.Privilege, db) {
+				// Reading some measurements of a database is enough
+				// to run the statement. The returned fine authorizer
+				// restricts it to those measurements.
+				if !user.AuthorizeDatabase(p.Privilege, db) &&
+					!(p.Privilege == cnosql.ReadPrivilege && user.AuthorizeMeasurements(p.Privilege, db)) {
 					return nil, &ErrAuthorize{
 						Query:    q,
 						User:     user.Name,
@@ -98,6 +118,9 @@ func (a *QueryAuthorizer) AuthorizeQuery(u User, q *cnosql.Query, database strin
 				}
 			}
 		}
+		if !user.IsOpen() {
+			return user, nil
+		}
 		return query.OpenAuthorizer, nil
 	default:
 	}
@@ -150,3 +173,8 @@ func (e ErrAuthorize) Error() string {
 	}
 	return fmt.Sprintf("%s not authorized to execute %s", e.User, e.Message)
 }
+
+// AuthorizationFailed returns true to mark the error as an authorization failure.
+func (e ErrAuthorize) AuthorizationFailed() bool {
+	return true
+}
@@ -22,7 +22,6 @@ import (
 	internal "github.com/cnosdb/cnosdb/meta/internal"
 	"github.com/gogo/protobuf/proto"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -50,17 +49,28 @@ type RemoteClient struct {
 
 	// Authentication cache.
 	authCache map[string]authUser
+
+	// Hashes new passwords and tells which ones to re-hash.
+	passwordHasher *PasswordHasher
 }
 
 // NewRemoteClient returns a new *Remote
 func NewRemoteClient() *RemoteClient {
 	return &RemoteClient{
-		cacheData: &Data{},
-		logger:    zap.NewNop(),
-		authCache: make(map[string]authUser, 0),
+		cacheData:      &Data{},
+		logger:         zap.NewNop(),
+		authCache:      make(map[string]authUser, 0),
+		passwordHasher: defaultPasswordHasher(),
 	}
 }
 
+// WithPasswordHasher sets the hasher used for new passwords.
+func (c *RemoteClient) WithPasswordHasher(h *PasswordHasher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.passwordHasher = h
+}
+
 // Open a connection to a meta service cluster.
 func (c *RemoteClient) Open() error {
 	c.changed = make(chan struct{})
@@ -339,6 +349,15 @@ func (c *RemoteClient) DeleteDataNode(id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteDataNodeCommand, internal.E_DeleteDataNodeCommand_Command, cmd)
 }
 
+// SetDataNodeTags replaces the tags of a data node.
+func (c *RemoteClient) SetDataNodeTags(id uint64, tags map[string]string) error {
+	data := c.Data()
+	if err := data.SetDataNodeTags(id, tags); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
 // MetaNodes returns the meta nodes' info.
 func (c *RemoteClient) MetaNodes() ([]NodeInfo, error) {
 	return c.data().MetaNodes, nil
@@ -533,6 +552,16 @@ func (c *RemoteClient) SetDefaultRetentionPolicy(database, name string) error {
 	return c.retryUntilExec(internal.Command_SetDefaultRetentionPolicyCommand, internal.E_SetDefaultRetentionPolicyCommand_Command, cmd)
 }
 
+// UpdateDatabase updates a database. There is no dedicated command for it,
+// so the updated data is sent whole.
+func (c *RemoteClient) UpdateDatabase(name string, du *DatabaseUpdate) error {
+	data := c.Data()
+	if err := data.UpdateDatabase(name, du); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
 // UpdateRetentionPolicy updates a retention policy.
 func (c *RemoteClient) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	var newName *string
@@ -553,12 +582,19 @@ func (c *RemoteClient) UpdateRetentionPolicy(database, name string, rpu *Retenti
 	}
 
 	cmd := &internal.UpdateRetentionPolicyCommand{
-		Database: proto.String(database),
-		Name:     proto.String(name),
-		NewName:  newName,
-		Duration: duration,
-		ReplicaN: replicaN,
-		Default:  proto.Bool(makeDefault),
+		Database:  proto.String(database),
+		Name:      proto.String(name),
+		NewName:   newName,
+		Duration:  duration,
+		ReplicaN:  replicaN,
+		Default:   proto.Bool(makeDefault),
+		Placement: rpu.Placement,
+	}
+	if rpu.DeletionDelay != nil {
+		cmd.DeletionDelay = proto.Int64(int64(*rpu.DeletionDelay))
+	}
+	if rpu.MaxQueryRange != nil {
+		cmd.MaxQueryRange = proto.Int64(int64(*rpu.MaxQueryRange))
 	}
 
 	return c.retryUntilExec(internal.Command_UpdateRetentionPolicyCommand, internal.E_UpdateRetentionPolicyCommand_Command, cmd)
@@ -578,10 +614,8 @@ func (c *RemoteClient) UserCount() int {
 }
 
 func (c *RemoteClient) User(name string) (User, error) {
-	for _, u := range c.data().Users {
-		if u.Name == name {
-			return &u, nil
-		}
+	if u := c.data().authUser(name); u != nil {
+		return u, nil
 	}
 
 	return nil, ErrUserNotFound
@@ -610,23 +644,24 @@ func (c *RemoteClient) CreateUser(name, password string, admin bool) (User, erro
 
 	// See if the user already exists.
 	if u := data.user(name); u != nil {
-		if err := bcrypt.CompareHashAndPassword([]byte(u.Hash), []byte(password)); err != nil || u.Admin != admin {
+		if err := comparePassword(u.Hash, password); err != nil || u.Admin != admin {
 			return nil, ErrUserExists
 		}
 		return u, nil
 	}
 
 	// Hash the password before serializing it.
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	hash, err := c.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := c.retryUntilExec(internal.Command_CreateUserCommand, internal.E_CreateUserCommand_Command,
 		&internal.CreateUserCommand{
-			Name:  proto.String(name),
-			Hash:  proto.String(string(hash)),
-			Admin: proto.Bool(admin),
+			Name:      proto.String(name),
+			Hash:      proto.String(hash),
+			Admin:     proto.Bool(admin),
+			CreatedAt: proto.Int64(time.Now().UTC().UnixNano()),
 		},
 	); err != nil {
 		return nil, err
@@ -635,8 +670,12 @@ func (c *RemoteClient) CreateUser(name, password string, admin bool) (User, erro
 }
 
 func (c *RemoteClient) UpdateUser(name, password string) error {
+	if ui := c.data().user(name); ui != nil && ui.External {
+		return ErrUserExternal
+	}
+
 	// Hash the password before serializing it.
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	hash, err := c.passwordHasher.Hash(password)
 	if err != nil {
 		return err
 	}
@@ -644,7 +683,7 @@ func (c *RemoteClient) UpdateUser(name, password string) error {
 	return c.retryUntilExec(internal.Command_UpdateUserCommand, internal.E_UpdateUserCommand_Command,
 		&internal.UpdateUserCommand{
 			Name: proto.String(name),
-			Hash: proto.String(string(hash)),
+			Hash: proto.String(hash),
 		},
 	)
 }
@@ -667,6 +706,54 @@ func (c *RemoteClient) SetPrivilege(username, database string, p cnosql.Privileg
 	)
 }
 
+func (c *RemoteClient) SetUserLocked(username string, locked bool) error {
+	data := c.Data()
+	if err := data.SetUserLocked(username, locked); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) UpdateUserQueryLimits(username string, u *UserQueryLimitsUpdate) error {
+	data := c.Data()
+	if err := data.UpdateUserQueryLimits(username, u); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) SetUserDefaultDatabase(username, database, rp string) error {
+	data := c.Data()
+	if err := data.SetUserDefaultDatabase(username, database, rp); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) SetPrivilegeOnRP(username, database, rp string, p cnosql.Privilege) error {
+	data := c.Data()
+	if err := data.SetPrivilegeOnRP(username, database, rp, p); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) UserPrivilegeOnRP(username, database, rp string) (*cnosql.Privilege, error) {
+	return c.data().UserPrivilegeOnRP(username, database, rp)
+}
+
+func (c *RemoteClient) SetMeasurementPrivilege(username string, mp MeasurementPrivilege) error {
+	data := c.Data()
+	if err := data.SetMeasurementPrivilege(username, mp); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) UserMeasurementPrivileges(username string) ([]MeasurementPrivilege, error) {
+	return c.data().UserMeasurementPrivileges(username)
+}
+
 func (c *RemoteClient) SetAdminPrivilege(username string, admin bool) error {
 	return c.retryUntilExec(internal.Command_SetAdminPrivilegeCommand, internal.E_SetAdminPrivilegeCommand_Command,
 		&internal.SetAdminPrivilegeCommand{
@@ -702,37 +789,225 @@ func (c *RemoteClient) AdminUserExists() bool {
 }
 
 func (c *RemoteClient) Authenticate(username, password string) (User, error) {
+	userInfo, rehash, err := c.authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upgrade hashes created with an older setting now that the password is
+	// known. The meta update drops the cached entry of the old hash.
+	if rehash {
+		if err := c.rehashUser(username, userInfo.Hash, password); err != nil {
+			c.logger.Warn("Failed to upgrade password hash", zap.String("user", username), zap.Error(err))
+		}
+	}
+
+	c.recordLogin(userInfo)
+	return userInfo, nil
+}
+
+// recordLogin records that a user authenticated, at most once per
+// UserLastLoginInterval. Recording it is best effort, failing to record it
+// doesn't fail the authentication.
+func (c *RemoteClient) recordLogin(ui *UserInfo) {
+	now := time.Now().UTC()
+	if now.Sub(ui.LastLogin) < UserLastLoginInterval {
+		return
+	}
+
+	data := c.Data()
+	if err := data.SetUserLastLogin(ui.Name, now); err == nil {
+		_ = c.SetData(&data)
+	}
+}
+
+// authenticate verifies the password of a user and returns whether its
+// hash should be upgraded to the current settings.
+func (c *RemoteClient) authenticate(username, password string) (*UserInfo, bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Find user.
-	userInfo := c.cacheData.user(username)
+	userInfo := c.cacheData.authUser(username)
 	if userInfo == nil {
-		return nil, ErrUserNotFound
+		return nil, false, ErrUserNotFound
 	}
 
 	// Check the local auth cache first.
 	if au, ok := c.authCache[username]; ok {
 		// verify the password using the cached salt and hash
 		if bytes.Equal(c.hashWithSalt(au.salt, password), au.hash) {
-			return userInfo, nil
+			if userInfo.Locked {
+				return nil, false, ErrUserLocked
+			}
+			return userInfo, false, nil
 		}
 
 		// fall through to requiring a full bcrypt hash for invalid passwords
 	}
 
 	// Compare password with user hash.
-	if err := bcrypt.CompareHashAndPassword([]byte(userInfo.Hash), []byte(password)); err != nil {
-		return nil, ErrAuthenticate
+	if err := comparePassword(userInfo.Hash, password); err != nil {
+		return nil, false, ErrAuthenticate
+	}
+
+	// Only tell a locked user apart once the password is verified.
+	if userInfo.Locked {
+		return nil, false, ErrUserLocked
 	}
 
 	// generate a salt and hash of the password for the cache
 	salt, hashed, err := c.saltedHash(password)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	c.authCache[username] = authUser{salt: salt, hash: hashed, bhash: userInfo.Hash}
 
+	return userInfo, c.passwordHasher.NeedsRehash(userInfo.Hash), nil
+}
+
+// rehashUser replaces the password hash of a user with one created with the
+// current settings, unless the password changed since old was read.
+func (c *RemoteClient) rehashUser(username, old, password string) error {
+	if ui := c.data().user(username); ui == nil || ui.Hash != old {
+		return nil
+	}
+
+	hash, err := c.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return c.retryUntilExec(internal.Command_UpdateUserCommand, internal.E_UpdateUserCommand_Command,
+		&internal.UpdateUserCommand{
+			Name: proto.String(username),
+			Hash: proto.String(hash),
+		},
+	)
+}
+
+func (c *RemoteClient) AuthenticateExternal(username string, admin *bool, create bool) (User, error) {
+	if !c.data().externalUserInSync(username, admin) {
+		data := c.Data()
+		if err := data.SetExternalUser(username, admin, create); err != nil {
+			return nil, err
+		} else if err := c.SetData(&data); err != nil {
+			return nil, err
+		}
+	}
+
+	userInfo := c.data().authUser(username)
+	if userInfo == nil {
+		return nil, ErrUserNotFound
+	} else if userInfo.Locked {
+		return nil, ErrUserLocked
+	}
+
+	c.recordLogin(userInfo)
+	return userInfo, nil
+}
+
+func (c *RemoteClient) Roles() []RoleInfo {
+	roles := c.data().Roles
+	if roles == nil {
+		return []RoleInfo{}
+	}
+	return roles
+}
+
+func (c *RemoteClient) CreateRole(name string) error {
+	data := c.Data()
+	if err := data.CreateRole(name); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) DropRole(name string) error {
+	data := c.Data()
+	if err := data.DropRole(name); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) SetRolePrivilege(role, database string, p cnosql.Privilege) error {
+	data := c.Data()
+	if err := data.SetRolePrivilege(role, database, p); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) RolePrivileges(role string) (map[string]cnosql.Privilege, error) {
+	return c.data().RolePrivileges(role)
+}
+
+func (c *RemoteClient) GrantRole(username, role string) error {
+	data := c.Data()
+	if err := data.GrantRole(username, role); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) RevokeRole(username, role string) error {
+	data := c.Data()
+	if err := data.RevokeRole(username, role); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) Tokens() []TokenInfo {
+	tokens := c.data().Tokens
+	if tokens == nil {
+		return []TokenInfo{}
+	}
+	return tokens
+}
+
+func (c *RemoteClient) CreateToken(username, database string, p cnosql.Privilege, expiry time.Time) (string, *TokenInfo, error) {
+	ti, token, err := NewToken(username, database, p, expiry)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data := c.Data()
+	if err := data.CreateToken(ti); err != nil {
+		return "", nil, err
+	}
+	if err := c.SetData(&data); err != nil {
+		return "", nil, err
+	}
+	return token, &ti, nil
+}
+
+func (c *RemoteClient) DropToken(id string) error {
+	data := c.Data()
+	if err := data.DropToken(id); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+func (c *RemoteClient) AuthenticateToken(token string) (User, error) {
+	now := time.Now().UTC()
+
+	userInfo, ti, err := c.data().authToken(token, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recording the last use is best effort, failing to record it doesn't
+	// fail the authentication.
+	if now.Sub(ti.LastUsed) >= TokenLastUsedInterval {
+		data := c.Data()
+		if err := data.SetTokenLastUsed(ti.ID, now); err == nil {
+			_ = c.SetData(&data)
+		}
+	}
+
 	return userInfo, nil
 }
 
@@ -856,6 +1131,26 @@ func (c *RemoteClient) DeleteShardGroup(database, rp string, id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteShardGroupCommand, internal.E_DeleteShardGroupCommand_Command, cmd)
 }
 
+// PurgeShardGroup marks a shard group whose shards were deleted right away as
+// deleted, so it can't be restored.
+func (c *RemoteClient) PurgeShardGroup(database, rp string, id uint64) error {
+	data := c.Data()
+	if err := data.PurgeShardGroup(database, rp, id); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
+// UndeleteShardGroup restores a deleted shard group within the deletion delay
+// of its retention policy.
+func (c *RemoteClient) UndeleteShardGroup(database, rp string, id uint64) error {
+	data := c.Data()
+	if err := data.UndeleteShardGroup(database, rp, id); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
 // PrecreateShardGroups creates shard groups whose endtime is before the 'to' time passed in, but
 // is yet to expire before 'from'. This is to avoid the need for these shards to be created when data
 // for the corresponding time range arrives. Shard creation involves Raft consensus, and precreation
@@ -915,16 +1210,26 @@ func (c *RemoteClient) ShardOwner(shardID uint64) (database, rp string, sgi *Sha
 	return
 }
 
-func (c *RemoteClient) CreateContinuousQuery(database, name, query string) error {
+func (c *RemoteClient) CreateContinuousQuery(database, name, query, owner string) error {
 	return c.retryUntilExec(internal.Command_CreateContinuousQueryCommand, internal.E_CreateContinuousQueryCommand_Command,
 		&internal.CreateContinuousQueryCommand{
 			Database: proto.String(database),
 			Name:     proto.String(name),
 			Query:    proto.String(query),
+			Owner:    proto.String(owner),
 		},
 	)
 }
 
+// AlterContinuousQuery replaces the query of an existing continuous query.
+func (c *RemoteClient) AlterContinuousQuery(database, name, query string) error {
+	data := c.Data()
+	if err := data.AlterContinuousQuery(database, name, query); err != nil {
+		return err
+	}
+	return c.SetData(&data)
+}
+
 func (c *RemoteClient) DropContinuousQuery(database, name string) error {
 	return c.retryUntilExec(internal.Command_DropContinuousQueryCommand, internal.E_DropContinuousQueryCommand_Command,
 		&internal.DropContinuousQueryCommand{
@@ -934,7 +1239,11 @@ func (c *RemoteClient) DropContinuousQuery(database, name string) error {
 	)
 }
 
-func (c *RemoteClient) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+func (c *RemoteClient) CreateSubscription(database, rp, name, mode string, destinations []string, weights []int, filter string, tls SubscriptionTLSInfo) error {
+	var pbWeights []int64
+	for _, w := range weights {
+		pbWeights = append(pbWeights, int64(w))
+	}
 	return c.retryUntilExec(internal.Command_CreateSubscriptionCommand, internal.E_CreateSubscriptionCommand_Command,
 		&internal.CreateSubscriptionCommand{
 			Database:        proto.String(database),
@@ -942,6 +1251,11 @@ func (c *RemoteClient) CreateSubscription(database, rp, name, mode string, desti
 			Name:            proto.String(name),
 			Mode:            proto.String(mode),
 			Destinations:    destinations,
+			Weights:         pbWeights,
+			Filter:          proto.String(filter),
+			CA:              proto.String(tls.CA),
+			Cert:            proto.String(tls.Cert),
+			Key:             proto.String(tls.Key),
 		},
 	)
 }
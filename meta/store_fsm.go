@@ -322,6 +322,18 @@ func (fsm *storeFSM) applyUpdateRetentionPolicyCommand(cmd *internal.Command) in
 		value := int(v.GetReplicaN())
 		rpu.ReplicaN = &value
 	}
+	if v.Placement != nil {
+		value := v.GetPlacement()
+		rpu.Placement = &value
+	}
+	if v.DeletionDelay != nil {
+		value := time.Duration(v.GetDeletionDelay())
+		rpu.DeletionDelay = &value
+	}
+	if v.MaxQueryRange != nil {
+		value := time.Duration(v.GetMaxQueryRange())
+		rpu.MaxQueryRange = &value
+	}
 
 	// Copy data and update.
 	other := fsm.data.Clone()
@@ -367,7 +379,7 @@ func (fsm *storeFSM) applyCreateContinuousQueryCommand(cmd *internal.Command) in
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateContinuousQuery(v.GetDatabase(), v.GetName(), v.GetQuery()); err != nil {
+	if err := other.CreateContinuousQuery(v.GetDatabase(), v.GetName(), v.GetQuery(), v.GetOwner()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -395,7 +407,12 @@ func (fsm *storeFSM) applyCreateSubscriptionCommand(cmd *internal.Command) inter
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateSubscription(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName(), v.GetMode(), v.GetDestinations()); err != nil {
+	tls := SubscriptionTLSInfo{CA: v.GetCA(), Cert: v.GetCert(), Key: v.GetKey()}
+	var weights []int
+	for _, w := range v.GetWeights() {
+		weights = append(weights, int(w))
+	}
+	if err := other.CreateSubscription(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName(), v.GetMode(), v.GetDestinations(), weights, v.GetFilter(), tls); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -423,7 +440,11 @@ func (fsm *storeFSM) applyCreateUserCommand(cmd *internal.Command) interface{} {
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateUser(v.GetName(), v.GetHash(), v.GetAdmin()); err != nil {
+	var created time.Time
+	if n := v.GetCreatedAt(); n != 0 {
+		created = time.Unix(0, n).UTC()
+	}
+	if err := other.CreateUser(v.GetName(), v.GetHash(), v.GetAdmin(), created); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -30,7 +30,9 @@ func (a WriteAuthorizer) AuthorizeWrite(username, database string) error {
 	// Enterprise UserInfo in closed-source code.
 	switch user := u.(type) {
 	case *UserInfo:
-		if !user.AuthorizeDatabase(cnosql.WritePrivilege, database) {
+		// Writes of users restricted to some measurements are checked
+		// point by point by the points writer.
+		if !user.AuthorizeDatabase(cnosql.WritePrivilege, database) && !user.AuthorizeMeasurements(cnosql.WritePrivilege, database) {
 			return &ErrAuthorize{
 				Database: database,
 				Message:  fmt.Sprintf("%s not authorized to write to %s", username, database),
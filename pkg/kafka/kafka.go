@@ -0,0 +1,331 @@
+// Package kafka implements a minimal Kafka producer.
+//
+// It speaks just enough of the Kafka protocol to write uncompressed record
+// batches to the partitions of a single topic: Metadata requests to find the
+// partition leaders and Produce requests to write to them.
+package kafka
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheme is the URL scheme of Kafka destinations.
+const Scheme = "kafka"
+
+// DefaultTimeout is the default timeout of requests to the brokers.
+const DefaultTimeout = 30 * time.Second
+
+// clientID identifies the producer to the brokers.
+const clientID = "cnosdb"
+
+// maxResponseSize bounds the size of a response read from a broker.
+const maxResponseSize = 64 * 1024 * 1024
+
+var (
+	// ErrClosed is returned when producing with a closed producer.
+	ErrClosed = errors.New("kafka: producer closed")
+
+	errUnknownTopic = errors.New("kafka: topic not found")
+	errNoPartitions = errors.New("kafka: topic has no partitions")
+)
+
+// Error is an error code returned by a broker.
+type Error int16
+
+// Error returns the string representation of the error code.
+func (e Error) Error() string {
+	switch e {
+	case 2:
+		return "kafka: corrupt message"
+	case 3:
+		return "kafka: unknown topic or partition"
+	case 5:
+		return "kafka: leader not available"
+	case 6:
+		return "kafka: not leader for partition"
+	case 7:
+		return "kafka: request timed out"
+	case 10:
+		return "kafka: message too large"
+	case 19, 20:
+		return "kafka: not enough replicas"
+	case 29:
+		return "kafka: topic authorization failed"
+	}
+	return fmt.Sprintf("kafka: error code %d", int16(e))
+}
+
+// ParseURL returns the brokers and the topic of a Kafka destination of the
+// form kafka://host1:port1[,host2:port2...]/topic.
+func ParseURL(u *url.URL) (brokers []string, topic string, err error) {
+	if u.Scheme != Scheme {
+		return nil, "", fmt.Errorf("invalid scheme %q, expected %q", u.Scheme, Scheme)
+	} else if u.Host == "" {
+		return nil, "", errors.New("no brokers")
+	}
+
+	for _, addr := range strings.Split(u.Host, ",") {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid broker %q: %s", addr, err)
+		} else if host == "" {
+			return nil, "", fmt.Errorf("invalid broker %q: missing host", addr)
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, "", fmt.Errorf("invalid broker %q: invalid port", addr)
+		}
+		brokers = append(brokers, addr)
+	}
+
+	topic = strings.TrimPrefix(u.Path, "/")
+	if topic == "" {
+		return nil, "", errors.New("no topic")
+	} else if len(topic) > 249 {
+		return nil, "", errors.New("topic longer than 249 characters")
+	}
+	for _, c := range topic {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return nil, "", fmt.Errorf("invalid topic %q", topic)
+		}
+	}
+	return brokers, topic, nil
+}
+
+// Message is a message written to the topic.
+type Message struct {
+	Key   []byte
+	Value []byte
+}
+
+// Producer writes messages to a topic. Messages with the same key are
+// written to the same partition.
+type Producer struct {
+	mu      sync.Mutex
+	brokers []string
+	topic   string
+	timeout time.Duration
+	closed  bool
+
+	correlationID int32
+
+	// Partition leaders, loaded on first use and reset on errors.
+	leaders    map[int32]string
+	partitions []int32
+	conns      map[string]*conn
+}
+
+// NewProducer returns a new producer writing to topic through the given
+// bootstrap brokers. No connection is made until messages are produced.
+func NewProducer(brokers []string, topic string, timeout time.Duration) *Producer {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Producer{
+		brokers: brokers,
+		topic:   topic,
+		timeout: timeout,
+		conns:   make(map[string]*conn),
+	}
+}
+
+// Produce writes msgs to the topic and waits for the partition leaders to
+// acknowledge them.
+func (p *Producer) Produce(msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	if err := p.produce(msgs); err != nil {
+		// The leaders may have moved or a connection may be broken, so
+		// start over on the next call.
+		p.reset()
+		return err
+	}
+	return nil
+}
+
+func (p *Producer) produce(msgs []Message) error {
+	if p.leaders == nil {
+		if err := p.loadMetadata(); err != nil {
+			return err
+		}
+	}
+
+	// Group the messages by partition, then the partitions by leader.
+	byPartition := make(map[int32][]Message)
+	for _, m := range msgs {
+		id := p.partition(m.Key)
+		byPartition[id] = append(byPartition[id], m)
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	byLeader := make(map[string]map[int32][]byte)
+	for id, msgs := range byPartition {
+		addr, ok := p.leaders[id]
+		if !ok {
+			return Error(5) // leader not available
+		}
+		if byLeader[addr] == nil {
+			byLeader[addr] = make(map[int32][]byte)
+		}
+		byLeader[addr][id] = recordBatch(msgs, now)
+	}
+
+	timeoutMs := int32(p.timeout / time.Millisecond)
+	for addr, batches := range byLeader {
+		resp, err := p.roundTrip(addr, apiKeyProduce, produceVersion, produceRequest(p.topic, -1, timeoutMs, batches))
+		if err != nil {
+			return err
+		} else if err := decodeProduceResponse(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partition returns the partition written to for key.
+func (p *Producer) partition(key []byte) int32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return p.partitions[h.Sum32()%uint32(len(p.partitions))]
+}
+
+// loadMetadata loads the partition leaders of the topic from the first
+// bootstrap broker that answers.
+func (p *Producer) loadMetadata() error {
+	err := errors.New("kafka: no brokers")
+	for _, addr := range p.brokers {
+		var resp []byte
+		if resp, err = p.roundTrip(addr, apiKeyMetadata, metadataVersion, metadataRequest(p.topic)); err != nil {
+			continue
+		}
+
+		brokers, partitions, err := decodeMetadataResponse(resp, p.topic)
+		if err != nil {
+			return err
+		} else if len(partitions) == 0 {
+			return errNoPartitions
+		}
+
+		addrs := make(map[int32]string, len(brokers))
+		for _, b := range brokers {
+			addrs[b.id] = b.addr
+		}
+		p.leaders = make(map[int32]string, len(partitions))
+		p.partitions = p.partitions[:0]
+		for _, pt := range partitions {
+			p.partitions = append(p.partitions, pt.id)
+			if addr, ok := addrs[pt.leader]; ok {
+				p.leaders[pt.id] = addr
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+// roundTrip sends a request to the broker at addr and returns the body of
+// its response. The connection is closed if the request fails.
+func (p *Producer) roundTrip(addr string, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	c, err := p.conn(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.exchange(c, apiKey, apiVersion, body)
+	if err != nil {
+		c.Close()
+		delete(p.conns, addr)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *Producer) exchange(c *conn, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+
+	p.correlationID++
+	id := p.correlationID
+
+	if err := c.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		return nil, err
+	} else if _, err := c.Write(request(apiKey, apiVersion, id, clientID, body)); err != nil {
+		return nil, err
+	}
+
+	var hdr [8]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.BigEndian.Uint32(hdr[:4]))
+	if size < 4 || size > maxResponseSize {
+		return nil, fmt.Errorf("kafka: invalid response size %d", size)
+	} else if got := int32(binary.BigEndian.Uint32(hdr[4:])); got != id {
+		return nil, fmt.Errorf("kafka: unexpected correlation id %d, expected %d", got, id)
+	}
+
+	resp := make([]byte, size-4)
+	if _, err := io.ReadFull(c.r, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// conn returns the connection to the broker at addr, dialing it if needed.
+func (p *Producer) conn(addr string) (*conn, error) {
+	if c := p.conns[addr]; c != nil {
+		return c, nil
+	}
+	nc, err := net.DialTimeout("tcp", addr, p.timeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	p.conns[addr] = c
+	return c, nil
+}
+
+// reset closes the connections and forgets the partition leaders.
+func (p *Producer) reset() {
+	for addr, c := range p.conns {
+		c.Close()
+		delete(p.conns, addr)
+	}
+	p.leaders = nil
+}
+
+// Close closes the connections to the brokers. Produce returns ErrClosed
+// after Close is called.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	p.reset()
+	return nil
+}
+
+// conn is a connection to a broker.
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func joinHostPort(host string, port int32) string {
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
@@ -0,0 +1,438 @@
+package kafka
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBroker is a fake Kafka broker answering Metadata and Produce requests.
+type testBroker struct {
+	ln net.Listener
+	id int32
+
+	mu sync.Mutex
+
+	// metadata returns the body of the responses to Metadata requests.
+	metadata func() []byte
+
+	// errCode returns the error code of writes to a partition.
+	errCode func(partition int32) int16
+
+	// Offset added to the correlation IDs of the responses.
+	correlationOffset int32
+
+	// Number of requests by API key.
+	requests map[int16]int
+
+	// Number of records written by partition.
+	records map[int32]int
+}
+
+// newTestBroker returns a broker listening on a local port. It is closed
+// when the test finishes.
+func newTestBroker(t *testing.T, id int32) *testBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBroker{
+		ln:       ln,
+		id:       id,
+		errCode:  func(int32) int16 { return 0 },
+		requests: make(map[int16]int),
+		records:  make(map[int32]int),
+	}
+	go b.serve(t)
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+func (b *testBroker) Addr() string { return b.ln.Addr().String() }
+
+// Requests returns the number of requests received with the API key.
+func (b *testBroker) Requests(apiKey int16) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests[apiKey]
+}
+
+// Records returns the number of records written to each partition.
+func (b *testBroker) Records() map[int32]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m := make(map[int32]int, len(b.records))
+	for k, v := range b.records {
+		m[k] = v
+	}
+	return m
+}
+
+func (b *testBroker) serve(t *testing.T) {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		go b.serveConn(t, conn)
+	}
+}
+
+func (b *testBroker) serveConn(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, req); err != nil {
+			return
+		}
+
+		d := decoder{buf: req}
+		apiKey, _, correlationID := d.int16(), d.int16(), d.int32()
+		if clientID := d.string(); clientID != "cnosdb" {
+			t.Errorf("unexpected client id: %q", clientID)
+			return
+		}
+
+		b.mu.Lock()
+		b.requests[apiKey]++
+		var body []byte
+		switch apiKey {
+		case apiKeyMetadata:
+			body = b.metadata()
+		case apiKeyProduce:
+			body = b.produce(t, d.buf)
+		}
+		correlationID += b.correlationOffset
+		b.mu.Unlock()
+
+		var e encoder
+		e.int32(int32(4 + len(body)))
+		e.int32(correlationID)
+		e.buf = append(e.buf, body...)
+		if _, err := conn.Write(e.buf); err != nil {
+			return
+		}
+	}
+}
+
+// produce records the records of a Produce request and returns the body of
+// its response.
+func (b *testBroker) produce(t *testing.T, req []byte) []byte {
+	d := decoder{buf: req}
+	d.string() // transactional id
+	if acks := d.int16(); acks != -1 {
+		t.Errorf("unexpected acks: %d", acks)
+	}
+	d.int32() // timeout
+
+	var e encoder
+	n := d.arrayLen()
+	e.int32(int32(n))
+	for i := 0; i < n; i++ {
+		e.string(d.string())
+
+		m := d.arrayLen()
+		e.int32(int32(m))
+		for j := 0; j < m; j++ {
+			id := d.int32()
+			batch := d.next(int(d.int32()))
+			if len(batch) < 61 || crc32.Checksum(batch[21:], castagnoli) != binary.BigEndian.Uint32(batch[17:]) {
+				t.Errorf("corrupt record batch for partition %d", id)
+				return nil
+			}
+
+			code := b.errCode(id)
+			if code == 0 {
+				b.records[id] += int(binary.BigEndian.Uint32(batch[57:]))
+			}
+			e.int32(id)
+			e.int16(code)
+			e.int64(0)  // base offset
+			e.int64(-1) // log append time
+		}
+	}
+	e.int32(0) // throttle time
+	if d.err != nil {
+		t.Errorf("malformed produce request: %v", d.err)
+	}
+	return e.buf
+}
+
+// testMetadata encodes a Metadata response listing the brokers and the
+// partitions of topic, with the ID of their leader by partition ID.
+func testMetadata(brokers []*testBroker, topic string, topicErr int16, leaders map[int32]int32) []byte {
+	var e encoder
+	e.int32(int32(len(brokers)))
+	for _, b := range brokers {
+		host, port, _ := net.SplitHostPort(b.Addr())
+		n, _ := strconv.Atoi(port)
+		e.int32(b.id)
+		e.string(host)
+		e.int32(int32(n))
+		e.nullString() // rack
+	}
+	e.int32(brokers[0].id) // controller id
+
+	e.int32(1)
+	e.int16(topicErr)
+	e.string(topic)
+	e.int8(0) // is internal
+	e.int32(int32(len(leaders)))
+	for id := int32(0); id < int32(len(leaders)); id++ {
+		e.int16(0)
+		e.int32(id)
+		e.int32(leaders[id])
+		e.int32(1) // replicas
+		e.int32(leaders[id])
+		e.int32(1) // in-sync replicas
+		e.int32(leaders[id])
+	}
+	return e.buf
+}
+
+// testMessages returns n messages with distinct keys.
+func testMessages(n int) []Message {
+	msgs := make([]Message, n)
+	for i := range msgs {
+		msgs[i] = Message{Key: []byte("key" + strconv.Itoa(i)), Value: []byte("value")}
+	}
+	return msgs
+}
+
+func TestProducer_Produce(t *testing.T) {
+	b1, b2 := newTestBroker(t, 1), newTestBroker(t, 2)
+	brokers := []*testBroker{b1, b2}
+	metadata := func() []byte { return testMetadata(brokers, "t", 0, map[int32]int32{0: 1, 1: 2}) }
+	b1.metadata, b2.metadata = metadata, metadata
+
+	p := NewProducer([]string{b1.Addr()}, "t", time.Second)
+	defer p.Close()
+
+	if err := p.Produce(testMessages(20)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The records are written to the leader of each partition.
+	r1, r2 := b1.Records(), b2.Records()
+	if len(r1) != 1 || r1[0] == 0 {
+		t.Fatalf("unexpected records on broker 1: %v", r1)
+	} else if len(r2) != 1 || r2[1] == 0 {
+		t.Fatalf("unexpected records on broker 2: %v", r2)
+	} else if r1[0]+r2[1] != 20 {
+		t.Fatalf("unexpected records: %v, %v", r1, r2)
+	}
+
+	// Messages with the same key go to the same partition, and the
+	// metadata is only loaded once.
+	if err := p.Produce([]Message{{Key: []byte("key0")}, {Key: []byte("key0")}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := b1.Records()[0] + b2.Records()[1] - r1[0] - r2[1]; n != 2 {
+		t.Fatalf("unexpected records: %d", n)
+	} else if b1.Records()[0] != r1[0] && b2.Records()[1] != r2[1] {
+		t.Fatal("messages with the same key written to different partitions")
+	}
+	if n := b1.Requests(apiKeyMetadata) + b2.Requests(apiKeyMetadata); n != 1 {
+		t.Fatalf("unexpected metadata requests: %d", n)
+	}
+}
+
+func TestProducer_LeaderChange(t *testing.T) {
+	b1, b2 := newTestBroker(t, 1), newTestBroker(t, 2)
+	brokers := []*testBroker{b1, b2}
+
+	var mu sync.Mutex
+	leader := int32(1)
+	metadata := func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return testMetadata(brokers, "t", 0, map[int32]int32{0: leader})
+	}
+	notLeader := func(id int32) func(int32) int16 {
+		return func(int32) int16 {
+			mu.Lock()
+			defer mu.Unlock()
+			if leader != id {
+				return 6 // not leader for partition
+			}
+			return 0
+		}
+	}
+	b1.metadata, b2.metadata = metadata, metadata
+	b1.errCode, b2.errCode = notLeader(1), notLeader(2)
+
+	p := NewProducer([]string{b1.Addr(), b2.Addr()}, "t", time.Second)
+	defer p.Close()
+
+	if err := p.Produce(testMessages(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The leadership moves to broker 2. The write to the former leader
+	// fails and the next one reloads the metadata.
+	mu.Lock()
+	leader = 2
+	mu.Unlock()
+	if err := p.Produce(testMessages(3)); err != Error(6) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Produce(testMessages(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := b1.Records(); r[0] != 3 {
+		t.Fatalf("unexpected records on broker 1: %v", r)
+	} else if r := b2.Records(); r[0] != 3 {
+		t.Fatalf("unexpected records on broker 2: %v", r)
+	} else if n := b1.Requests(apiKeyMetadata); n != 2 {
+		t.Fatalf("unexpected metadata requests: %d", n)
+	}
+}
+
+func TestProducer_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		metadata func(b *testBroker) []byte
+		errCode  int16
+		offset   int32
+		err      string
+	}{
+		{
+			name:     "topic error",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "t", 3, nil) },
+			err:      "kafka: unknown topic or partition",
+		},
+		{
+			name:     "unknown topic",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "other", 0, map[int32]int32{0: 1}) },
+			err:      "kafka: topic not found",
+		},
+		{
+			name:     "no partitions",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "t", 0, nil) },
+			err:      "kafka: topic has no partitions",
+		},
+		{
+			name:     "leader not available",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "t", 0, map[int32]int32{0: 9}) },
+			err:      "kafka: leader not available",
+		},
+		{
+			name:     "produce error",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "t", 0, map[int32]int32{0: 1}) },
+			errCode:  10,
+			err:      "kafka: message too large",
+		},
+		{
+			name:     "unexpected correlation id",
+			metadata: func(b *testBroker) []byte { return testMetadata([]*testBroker{b}, "t", 0, map[int32]int32{0: 1}) },
+			offset:   1,
+			err:      "kafka: unexpected correlation id 2, expected 1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBroker(t, 1)
+			b.metadata = func() []byte { return tt.metadata(b) }
+			b.errCode = func(int32) int16 { return tt.errCode }
+			b.correlationOffset = tt.offset
+
+			p := NewProducer([]string{b.Addr()}, "t", time.Second)
+			defer p.Close()
+
+			if err := p.Produce(testMessages(1)); err == nil || err.Error() != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestProducer_Bootstrap(t *testing.T) {
+	// The first bootstrap broker is down.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := ln.Addr().String()
+	ln.Close()
+
+	b := newTestBroker(t, 1)
+	b.metadata = func() []byte { return testMetadata([]*testBroker{b}, "t", 0, map[int32]int32{0: 1}) }
+
+	p := NewProducer([]string{down, b.Addr()}, "t", time.Second)
+	defer p.Close()
+	if err := p.Produce(testMessages(2)); err != nil {
+		t.Fatal(err)
+	} else if r := b.Records(); r[0] != 2 {
+		t.Fatalf("unexpected records: %v", r)
+	}
+
+	// Produce fails if no bootstrap broker answers.
+	p2 := NewProducer([]string{down}, "t", time.Second)
+	defer p2.Close()
+	if err := p2.Produce(testMessages(1)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestProducer_Closed(t *testing.T) {
+	p := NewProducer([]string{"127.0.0.1:1"}, "t", time.Second)
+	if err := p.Produce(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := p.Produce(testMessages(1)); err != ErrClosed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	for _, tt := range []struct {
+		s       string
+		brokers []string
+		topic   string
+		err     string
+	}{
+		{s: "kafka://h1:9092,h2:9093/metrics", brokers: []string{"h1:9092", "h2:9093"}, topic: "metrics"},
+		{s: "kafka://[::1]:9092/a.b_c-d", brokers: []string{"[::1]:9092"}, topic: "a.b_c-d"},
+		{s: "http://h1:9092/metrics", err: `invalid scheme "http", expected "kafka"`},
+		{s: "kafka:///metrics", err: "no brokers"},
+		{s: "kafka://h1/metrics", err: `invalid broker "h1": address h1: missing port in address`},
+		{s: "kafka://:9092/metrics", err: `invalid broker ":9092": missing host`},
+		{s: "kafka://h1:99999/metrics", err: `invalid broker "h1:99999": invalid port`},
+		{s: "kafka://h1:9092", err: "no topic"},
+		{s: "kafka://h1:9092/a/b", err: `invalid topic "a/b"`},
+		{s: "kafka://h1:9092/" + strings.Repeat("a", 250), err: "topic longer than 249 characters"},
+	} {
+		u, err := url.Parse(tt.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		brokers, topic, err := ParseURL(u)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.s, err)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		}
+		if strings.Join(brokers, ",") != strings.Join(tt.brokers, ",") || topic != tt.topic {
+			t.Fatalf("%s: unexpected brokers and topic: %v, %s", tt.s, brokers, topic)
+		}
+	}
+}
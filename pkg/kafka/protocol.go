@@ -0,0 +1,308 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// API keys and versions of the requests sent by the producer.
+const (
+	apiKeyProduce  = 0
+	apiKeyMetadata = 3
+
+	produceVersion  = 3
+	metadataVersion = 1
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+var errShortResponse = errors.New("kafka: short response")
+
+// encoder appends big endian protocol primitives to a buffer.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(v int8) { e.buf = append(e.buf, byte(v)) }
+
+func (e *encoder) int16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) int32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) int64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+// varint encodes v as a zigzag varint.
+func (e *encoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	e.buf = append(e.buf, b[:n]...)
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// nullString encodes a null string.
+func (e *encoder) nullString() { e.int16(-1) }
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// varbytes encodes b with a varint length as used inside records. A nil
+// slice is encoded as null.
+func (e *encoder) varbytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder reads big endian protocol primitives from a response. The first
+// error is sticky so a response can be decoded without checking every read.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = errShortResponse
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string decodes a nullable string. Null decodes as an empty string.
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+// arrayLen decodes the length of an array. Null decodes as empty.
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	// Every element takes at least one byte, which bounds the length of
+	// a corrupt response.
+	if int(n) > len(d.buf) {
+		d.err = errShortResponse
+		return 0
+	}
+	return int(n)
+}
+
+// request frames a request body with its size and request header.
+func request(apiKey, apiVersion int16, correlationID int32, clientID string, body []byte) []byte {
+	var e encoder
+	e.int32(0) // size, set below
+	e.int16(apiKey)
+	e.int16(apiVersion)
+	e.int32(correlationID)
+	e.string(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	return e.buf
+}
+
+// metadataRequest encodes a Metadata v1 request for a single topic.
+func metadataRequest(topic string) []byte {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	return e.buf
+}
+
+// broker is a broker listed in a Metadata response.
+type broker struct {
+	id   int32
+	addr string
+}
+
+// partition is a partition of the topic listed in a Metadata response.
+type partition struct {
+	id     int32
+	leader int32
+}
+
+// decodeMetadataResponse decodes a Metadata v1 response and returns the
+// brokers and the partitions of topic.
+func decodeMetadataResponse(b []byte, topic string) ([]broker, []partition, error) {
+	d := decoder{buf: b}
+
+	brokers := make([]broker, d.arrayLen())
+	for i := range brokers {
+		brokers[i].id = d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[i].addr = joinHostPort(host, port)
+	}
+	d.int32() // controller id
+
+	var partitions []partition
+	var topicErr int16
+	found := false
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		errCode := d.int16()
+		name := d.string()
+		d.int8() // is internal
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int16() // partition error code
+			p := partition{id: d.int32(), leader: d.int32()}
+			for k, r := 0, d.arrayLen(); k < r; k++ {
+				d.int32() // replica
+			}
+			for k, r := 0, d.arrayLen(); k < r; k++ {
+				d.int32() // in-sync replica
+			}
+			if name == topic {
+				partitions = append(partitions, p)
+			}
+		}
+		if name == topic {
+			found, topicErr = true, errCode
+		}
+	}
+
+	if d.err != nil {
+		return nil, nil, d.err
+	} else if !found {
+		return nil, nil, errUnknownTopic
+	} else if topicErr != 0 {
+		return nil, nil, Error(topicErr)
+	}
+	return brokers, partitions, nil
+}
+
+// recordBatch encodes messages as a v2 record batch without compression.
+func recordBatch(msgs []Message, timestamp int64) []byte {
+	var e encoder
+	e.int64(0)  // base offset
+	e.int32(0)  // batch length, set below
+	e.int32(-1) // partition leader epoch
+	e.int8(2)   // magic
+	e.int32(0)  // crc, set below
+	crcStart := len(e.buf)
+	e.int16(0) // attributes
+	e.int32(int32(len(msgs) - 1))
+	e.int64(timestamp) // base timestamp
+	e.int64(timestamp) // max timestamp
+	e.int64(-1)        // producer id
+	e.int16(-1)        // producer epoch
+	e.int32(-1)        // base sequence
+	e.int32(int32(len(msgs)))
+
+	var r encoder
+	for i, m := range msgs {
+		r.buf = r.buf[:0]
+		r.int8(0)   // attributes
+		r.varint(0) // timestamp delta
+		r.varint(int64(i))
+		r.varbytes(m.Key)
+		r.varbytes(m.Value)
+		r.varint(0) // headers
+
+		e.varint(int64(len(r.buf)))
+		e.buf = append(e.buf, r.buf...)
+	}
+
+	binary.BigEndian.PutUint32(e.buf[8:], uint32(len(e.buf)-12))
+	binary.BigEndian.PutUint32(e.buf[crcStart-4:], crc32.Checksum(e.buf[crcStart:], castagnoli))
+	return e.buf
+}
+
+// produceRequest encodes a Produce v3 request writing a record batch to each
+// of the given partitions of topic.
+func produceRequest(topic string, acks int16, timeoutMs int32, batches map[int32][]byte) []byte {
+	var e encoder
+	e.nullString() // transactional id
+	e.int16(acks)
+	e.int32(timeoutMs)
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(batches)))
+	for id, batch := range batches {
+		e.int32(id)
+		e.bytes(batch)
+	}
+	return e.buf
+}
+
+// decodeProduceResponse decodes a Produce v3 response and returns the first
+// partition error in it.
+func decodeProduceResponse(b []byte) error {
+	d := decoder{buf: b}
+	var err error
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		d.string() // topic
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int32() // partition
+			if code := d.int16(); code != 0 && err == nil {
+				err = Error(code)
+			}
+			d.int64() // base offset
+			d.int64() // log append time
+		}
+	}
+	d.int32() // throttle time
+	if d.err != nil {
+		return d.err
+	}
+	return err
+}
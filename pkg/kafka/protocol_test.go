@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"encoding/hex"
+	"hash/crc32"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mustDecodeHex decodes the hex string s, ignoring spaces.
+func mustDecodeHex(tb testing.TB, s string) []byte {
+	tb.Helper()
+	b, err := hex.DecodeString(strings.Replace(s, " ", "", -1))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return b
+}
+
+func TestRequest(t *testing.T) {
+	b := request(apiKeyMetadata, metadataVersion, 7, "cnosdb", metadataRequest("t"))
+	exp := mustDecodeHex(t, ""+
+		"00000017"+ // size
+		"0003 0001"+ // api key and version
+		"00000007"+ // correlation id
+		"0006 636e6f736462"+ // client id
+		"00000001 0001 74") // topics
+	if !reflect.DeepEqual(b, exp) {
+		t.Fatalf("unexpected request: got %x, exp %x", b, exp)
+	}
+}
+
+func TestDecodeMetadataResponse(t *testing.T) {
+	b := mustDecodeHex(t, ""+
+		// brokers
+		"00000002"+
+		"00000001 0002 6831 00002384 ffff"+
+		"00000002 0002 6832 00002385 0001 72"+
+		// controller id
+		"00000001"+
+		// topics
+		"00000002"+
+		"0000 0005 6f74686572 00"+
+		"00000001"+
+		"0000 00000000 00000002 00000001 00000002 00000001 00000002"+
+		"0000 0001 74 00"+
+		"00000002"+
+		"0000 00000000 00000001 00000002 00000001 00000002 00000001 00000001"+
+		"0000 00000001 00000002 00000002 00000001 00000002 00000001 00000002")
+
+	brokers, partitions, err := decodeMetadataResponse(b, "t")
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []broker{{id: 1, addr: "h1:9092"}, {id: 2, addr: "h2:9093"}}; !reflect.DeepEqual(brokers, exp) {
+		t.Fatalf("unexpected brokers: %+v", brokers)
+	} else if exp := []partition{{id: 0, leader: 1}, {id: 1, leader: 2}}; !reflect.DeepEqual(partitions, exp) {
+		t.Fatalf("unexpected partitions: %+v", partitions)
+	}
+
+	// Other topics are not found.
+	if _, _, err := decodeMetadataResponse(b, "missing"); err != errUnknownTopic {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Truncated responses fail.
+	for _, n := range []int{0, 3, 20, len(b) - 1} {
+		if _, _, err := decodeMetadataResponse(b[:n], "t"); err != errShortResponse {
+			t.Fatalf("%d: unexpected error: %v", n, err)
+		}
+	}
+}
+
+func TestDecodeMetadataResponse_Error(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		b    string
+		err  error
+	}{
+		{
+			name: "topic error",
+			b:    "00000000 00000001 00000001 0003 0001 74 00 00000000",
+			err:  Error(3),
+		},
+		{
+			name: "corrupt array length",
+			b:    "7fffffff",
+			err:  errShortResponse,
+		},
+		{
+			name: "corrupt string length",
+			b:    "00000001 00000001 7fff",
+			err:  errShortResponse,
+		},
+	} {
+		if _, _, err := decodeMetadataResponse(mustDecodeHex(t, tt.b), "t"); err != tt.err {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	b := recordBatch([]Message{
+		{Key: []byte("k"), Value: []byte("v")},
+		{Value: []byte("w")},
+	}, 1000)
+
+	exp := mustDecodeHex(t, ""+
+		"0000000000000000"+ // base offset
+		"00000042"+ // batch length
+		"ffffffff"+ // partition leader epoch
+		"02"+ // magic
+		"54c66521"+ // crc
+		"0000"+ // attributes
+		"00000001"+ // last offset delta
+		"00000000000003e8 00000000000003e8"+ // base and max timestamps
+		"ffffffffffffffff ffff ffffffff"+ // producer id, epoch and base sequence
+		"00000002"+ // records
+		"10 00 00 00 02 6b 02 76 00"+ // key k, value v
+		"0e 00 00 02 01 02 77 00") // null key, value w
+	if !reflect.DeepEqual(b, exp) {
+		t.Fatalf("unexpected record batch:\ngot %x\nexp %x", b, exp)
+	}
+
+	// The CRC covers everything from the attributes.
+	if crc := crc32.Checksum(b[21:], castagnoli); crc != 0x54c66521 {
+		t.Fatalf("unexpected crc: %x", crc)
+	}
+}
+
+func TestProduceRequest(t *testing.T) {
+	b := produceRequest("t", -1, 1000, map[int32][]byte{3: {0xaa, 0xbb}})
+	exp := mustDecodeHex(t, ""+
+		"ffff"+ // transactional id
+		"ffff"+ // acks
+		"000003e8"+ // timeout
+		"00000001 0001 74"+ // topic
+		"00000001 00000003 00000002 aabb") // partition and record batch
+	if !reflect.DeepEqual(b, exp) {
+		t.Fatalf("unexpected request: got %x, exp %x", b, exp)
+	}
+}
+
+func TestDecodeProduceResponse(t *testing.T) {
+	ok := mustDecodeHex(t, ""+
+		"00000001 0001 74 00000002"+
+		"00000000 0000 0000000000000010 ffffffffffffffff"+
+		"00000001 0000 0000000000000004 ffffffffffffffff"+
+		"00000000")
+	if err := decodeProduceResponse(ok); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first partition error is returned.
+	failed := mustDecodeHex(t, ""+
+		"00000001 0001 74 00000002"+
+		"00000000 0006 0000000000000000 ffffffffffffffff"+
+		"00000001 0007 0000000000000000 ffffffffffffffff"+
+		"00000000")
+	if err := decodeProduceResponse(failed); err != Error(6) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err.Error() != "kafka: not leader for partition" {
+		t.Fatalf("unexpected message: %s", err)
+	}
+
+	if err := decodeProduceResponse(ok[:len(ok)-1]); err != errShortResponse {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package ldap
+
+import (
+	"bufio"
+	"io"
+)
+
+// Identifier octets of the BER elements used by LDAP (RFC 4511, section 5.1).
+const (
+	classApplication = 0x40
+	classContext     = 0x80
+	constructed      = 0x20
+
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+)
+
+// element is a decoded BER element.
+type element struct {
+	tag      byte
+	contents []byte
+}
+
+// appendLength appends the definite form of the length n to b.
+func appendLength(b []byte, n int) []byte {
+	if n < 0x80 {
+		return append(b, byte(n))
+	}
+
+	var tmp [8]byte
+	i := len(tmp)
+	for ; n > 0; n >>= 8 {
+		i--
+		tmp[i] = byte(n)
+	}
+	b = append(b, 0x80|byte(len(tmp)-i))
+	return append(b, tmp[i:]...)
+}
+
+// tlv encodes the element with the given identifier and contents.
+func tlv(tag byte, contents ...[]byte) []byte {
+	n := 0
+	for _, c := range contents {
+		n += len(c)
+	}
+
+	b := make([]byte, 0, n+6)
+	b = append(b, tag)
+	b = appendLength(b, n)
+	for _, c := range contents {
+		b = append(b, c...)
+	}
+	return b
+}
+
+// berInteger encodes v in the minimal two's complement form.
+func berInteger(tag byte, v int64) []byte {
+	var b []byte
+	for {
+		b = append([]byte{byte(v)}, b...)
+		v >>= 8
+		if (v == 0 && b[0]&0x80 == 0) || (v == -1 && b[0]&0x80 != 0) {
+			return tlv(tag, b)
+		}
+	}
+}
+
+// berString encodes s as an octet string.
+func berString(tag byte, s string) []byte {
+	return tlv(tag, []byte(s))
+}
+
+// berBoolean encodes v as a boolean.
+func berBoolean(v bool) []byte {
+	if v {
+		return tlv(tagBoolean, []byte{0xff})
+	}
+	return tlv(tagBoolean, []byte{0x00})
+}
+
+// parseElement decodes the element at the start of b and returns the rest of b.
+func parseElement(b []byte) (element, []byte, error) {
+	if len(b) < 2 || b[0]&0x1f == 0x1f {
+		// High tag numbers are not used by LDAP.
+		return element{}, nil, errMalformed
+	}
+
+	n, hdr := int(b[1]), 2
+	if n&0x80 != 0 {
+		k := n & 0x7f
+		if k == 0 || k > 4 || len(b) < hdr+k {
+			return element{}, nil, errMalformed
+		}
+		n = 0
+		for _, c := range b[hdr : hdr+k] {
+			n = n<<8 | int(c)
+		}
+		hdr += k
+	}
+	if n < 0 || len(b)-hdr < n {
+		return element{}, nil, errMalformed
+	}
+	return element{tag: b[0], contents: b[hdr : hdr+n]}, b[hdr+n:], nil
+}
+
+// children decodes the elements making up a constructed element.
+func (e element) children() ([]element, error) {
+	var a []element
+	for b := e.contents; len(b) > 0; {
+		c, rest, err := parseElement(b)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, c)
+		b = rest
+	}
+	return a, nil
+}
+
+// integer decodes an integer or enumerated element.
+func (e element) integer() (int64, error) {
+	if len(e.contents) == 0 || len(e.contents) > 8 {
+		return 0, errMalformed
+	}
+	v := int64(int8(e.contents[0]))
+	for _, c := range e.contents[1:] {
+		v = v<<8 | int64(c)
+	}
+	return v, nil
+}
+
+// readElement reads a whole encoded element from r.
+func readElement(r *bufio.Reader) ([]byte, error) {
+	hdr := make([]byte, 2, 6)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+
+	n := int(hdr[1])
+	if n&0x80 != 0 {
+		k := n & 0x7f
+		if k == 0 || k > 4 {
+			return nil, errMalformed
+		}
+		hdr = hdr[:2+k]
+		if _, err := io.ReadFull(r, hdr[2:]); err != nil {
+			return nil, err
+		}
+		n = 0
+		for _, c := range hdr[2:] {
+			n = n<<8 | int(c)
+		}
+	}
+	if n < 0 || n > maxMessageSize {
+		return nil, errMalformed
+	}
+
+	b := make([]byte, len(hdr)+n)
+	copy(b, hdr)
+	if _, err := io.ReadFull(r, b[len(hdr):]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,148 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBERInteger(t *testing.T) {
+	for _, tt := range []struct {
+		v   int64
+		exp string
+	}{
+		{v: 0, exp: "020100"},
+		{v: 1, exp: "020101"},
+		{v: 127, exp: "02017f"},
+		{v: 128, exp: "02020080"},
+		{v: 256, exp: "02020100"},
+		{v: -1, exp: "0201ff"},
+		{v: -128, exp: "020180"},
+		{v: -129, exp: "0202ff7f"},
+		{v: 1 << 31, exp: "02050080000000"},
+	} {
+		b := berInteger(tagInteger, tt.v)
+		if got := hex.EncodeToString(b); got != tt.exp {
+			t.Fatalf("%d: unexpected encoding: got %s, exp %s", tt.v, got, tt.exp)
+		}
+
+		e, rest, err := parseElement(b)
+		if err != nil {
+			t.Fatalf("%d: %v", tt.v, err)
+		} else if len(rest) != 0 {
+			t.Fatalf("%d: unexpected rest: %x", tt.v, rest)
+		}
+		if v, err := e.integer(); err != nil {
+			t.Fatalf("%d: %v", tt.v, err)
+		} else if v != tt.v {
+			t.Fatalf("unexpected decoded integer: got %d, exp %d", v, tt.v)
+		}
+	}
+}
+
+func TestBERLength(t *testing.T) {
+	for _, tt := range []struct {
+		n   int
+		exp string
+	}{
+		{n: 0, exp: "00"},
+		{n: 127, exp: "7f"},
+		{n: 128, exp: "8180"},
+		{n: 255, exp: "81ff"},
+		{n: 256, exp: "820100"},
+		{n: 70000, exp: "83011170"},
+	} {
+		if got := hex.EncodeToString(appendLength(nil, tt.n)); got != tt.exp {
+			t.Fatalf("%d: unexpected encoding: got %s, exp %s", tt.n, got, tt.exp)
+		}
+
+		// The long form decodes back to the same contents.
+		s := strings.Repeat("x", tt.n)
+		e, rest, err := parseElement(berString(tagOctetString, s))
+		if err != nil {
+			t.Fatalf("%d: %v", tt.n, err)
+		} else if string(e.contents) != s || len(rest) != 0 {
+			t.Fatalf("%d: unexpected element", tt.n)
+		}
+	}
+}
+
+func TestBERSequence(t *testing.T) {
+	b := tlv(tagSequence, berInteger(tagInteger, 5), berString(tagOctetString, "cn"), berBoolean(true), berBoolean(false))
+	if got, exp := hex.EncodeToString(b), "300d0201050402636e0101ff010100"; got != exp {
+		t.Fatalf("unexpected encoding: got %s, exp %s", got, exp)
+	}
+
+	e, _, err := parseElement(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children, err := e.children()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(children) != 4 {
+		t.Fatalf("unexpected children: %d", len(children))
+	}
+	for i, tag := range []byte{tagInteger, tagOctetString, tagBoolean, tagBoolean} {
+		if children[i].tag != tag {
+			t.Fatalf("child %d: unexpected tag: %x", i, children[i].tag)
+		}
+	}
+	if string(children[1].contents) != "cn" {
+		t.Fatalf("unexpected string: %q", children[1].contents)
+	}
+}
+
+func TestParseElement_Malformed(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"04",
+		// High tag numbers.
+		"1f0100",
+		// Contents shorter than the length.
+		"040361",
+		// Indefinite and oversized lengths.
+		"0480",
+		"0485010000000000",
+		"048201",
+	} {
+		b, _ := hex.DecodeString(s)
+		if _, _, err := parseElement(b); err != errMalformed {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+	}
+
+	// Integers must have one to eight octets.
+	for _, s := range []string{"0200", "0209010203040506070809"} {
+		b, _ := hex.DecodeString(s)
+		e, _, err := parseElement(b)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := e.integer(); err != errMalformed {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+	}
+}
+
+func TestReadElement(t *testing.T) {
+	a := berString(tagOctetString, strings.Repeat("x", 300))
+	b := berInteger(tagInteger, 7)
+	r := bufio.NewReader(bytes.NewReader(append(append([]byte{}, a...), b...)))
+
+	for _, exp := range [][]byte{a, b} {
+		got, err := readElement(r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(got, exp) {
+			t.Fatalf("unexpected element: got %x, exp %x", got, exp)
+		}
+	}
+
+	// Messages beyond the maximum size are refused before being read.
+	big := append([]byte{tagSequence}, appendLength(nil, maxMessageSize+1)...)
+	if _, err := readElement(bufio.NewReader(bytes.NewReader(big))); err != errMalformed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
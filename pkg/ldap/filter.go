@@ -0,0 +1,153 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Context-specific tags of the filter choices.
+const (
+	filterAnd      = classContext | constructed | 0
+	filterOr       = classContext | constructed | 1
+	filterNot      = classContext | constructed | 2
+	filterEquality = classContext | constructed | 3
+	filterPresent  = classContext | 7
+)
+
+// EscapeFilter escapes s for use as a value in a search filter.
+func EscapeFilter(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&buf, `\%02x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// EscapeDN escapes s for use as an attribute value in a distinguished name.
+func EscapeDN(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 0:
+			buf.WriteString(`\00`)
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			(c == ' ' || c == '#') && i == 0,
+			c == ' ' && i == len(s)-1:
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// compileFilter encodes a filter given in the string representation of
+// RFC 4515. Only the and, or, not, equality and presence filters are
+// supported.
+func compileFilter(s string) ([]byte, error) {
+	b, rest, err := parseFilter(s)
+	if err != nil {
+		return nil, err
+	} else if rest != "" {
+		return nil, fmt.Errorf("ldap: unexpected %q after filter", rest)
+	}
+	return b, nil
+}
+
+// parseFilter encodes the filter at the start of s and returns the rest of s.
+func parseFilter(s string) ([]byte, string, error) {
+	if len(s) < 2 || s[0] != '(' {
+		return nil, "", fmt.Errorf("ldap: filter must be enclosed in parentheses: %q", s)
+	}
+	s = s[1:]
+
+	switch s[0] {
+	case '&', '|':
+		tag := byte(filterAnd)
+		if s[0] == '|' {
+			tag = filterOr
+		}
+		s = s[1:]
+
+		var filters [][]byte
+		for len(s) > 0 && s[0] == '(' {
+			b, rest, err := parseFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			filters = append(filters, b)
+			s = rest
+		}
+		if len(filters) == 0 || s == "" || s[0] != ')' {
+			return nil, "", fmt.Errorf("ldap: malformed filter list")
+		}
+		return tlv(tag, filters...), s[1:], nil
+	case '!':
+		b, rest, err := parseFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		} else if rest == "" || rest[0] != ')' {
+			return nil, "", fmt.Errorf("ldap: malformed not filter")
+		}
+		return tlv(filterNot, b), rest[1:], nil
+	}
+
+	i := strings.IndexByte(s, ')')
+	if i < 0 {
+		return nil, "", fmt.Errorf("ldap: unterminated filter")
+	}
+	item, rest := s[:i], s[i+1:]
+
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, "", fmt.Errorf("ldap: malformed filter item %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+	if strings.ContainsAny(attr, "<>~:") {
+		return nil, "", fmt.Errorf("ldap: unsupported filter item %q", item)
+	} else if value == "*" {
+		return tlv(filterPresent, []byte(attr)), rest, nil
+	} else if strings.IndexByte(value, '*') >= 0 {
+		return nil, "", fmt.Errorf("ldap: substring filters are not supported: %q", item)
+	}
+
+	v, err := unescapeFilterValue(value)
+	if err != nil {
+		return nil, "", err
+	}
+	return tlv(filterEquality, berString(tagOctetString, attr), berString(tagOctetString, v)), rest, nil
+}
+
+// unescapeFilterValue replaces the \XX escapes of a filter value by the
+// bytes they stand for.
+func unescapeFilterValue(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", fmt.Errorf("ldap: malformed escape in filter value %q", s)
+		}
+		b, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("ldap: malformed escape in filter value %q", s)
+		}
+		buf.Write(b)
+		i += 2
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,101 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		exp string
+	}{
+		{s: "alice", exp: "alice"},
+		{s: "*", exp: `\2a`},
+		{s: "a*)(uid=*", exp: `a\2a\29\28uid=\2a`},
+		{s: `back\slash`, exp: `back\5cslash`},
+		{s: "nul\x00", exp: `nul\00`},
+		{s: "cn=Doe\\, John", exp: `cn=Doe\5c, John`},
+	} {
+		if got := EscapeFilter(tt.s); got != tt.exp {
+			t.Fatalf("%q: unexpected escape: got %q, exp %q", tt.s, got, tt.exp)
+		}
+
+		// The escaped value matches the original value exactly.
+		got, err := compileFilter("(uid=" + EscapeFilter(tt.s) + ")")
+		if err != nil {
+			t.Fatalf("%q: %v", tt.s, err)
+		}
+		exp := tlv(filterEquality, berString(tagOctetString, "uid"), berString(tagOctetString, tt.s))
+		if hex.EncodeToString(got) != hex.EncodeToString(exp) {
+			t.Fatalf("%q: unexpected filter: got %x, exp %x", tt.s, got, exp)
+		}
+	}
+}
+
+func TestEscapeDN(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		exp string
+	}{
+		{s: "alice", exp: "alice"},
+		{s: "Doe, John", exp: `Doe\, John`},
+		{s: `a+b"c\d<e>f;g=h`, exp: `a\+b\"c\\d\<e\>f\;g\=h`},
+		{s: "#admin", exp: `\#admin`},
+		{s: "a#b", exp: "a#b"},
+		{s: " lead", exp: `\ lead`},
+		{s: "trail ", exp: `trail\ `},
+		{s: "in side", exp: "in side"},
+		{s: "nul\x00", exp: `nul\00`},
+		{s: "admin,ou=people", exp: `admin\,ou\=people`},
+	} {
+		if got := EscapeDN(tt.s); got != tt.exp {
+			t.Fatalf("%q: unexpected escape: got %q, exp %q", tt.s, got, tt.exp)
+		}
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		exp string
+	}{
+		{s: "(cn=a)", exp: "a3070402636e040161"},
+		{s: "(objectClass=*)", exp: "870b6f626a656374436c617373"},
+		{s: "(!(cn=a))", exp: "a209a3070402636e040161"},
+		{s: "(&(cn=a)(cn=b))", exp: "a012a3070402636e040161a3070402636e040162"},
+		{s: "(|(cn=a)(!(cn=b)))", exp: "a114a3070402636e040161a209a3070402636e040162"},
+		{s: `(cn=\28x\29)`, exp: "a3090402636e0403287829"},
+	} {
+		b, err := compileFilter(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		} else if got := hex.EncodeToString(b); got != tt.exp {
+			t.Fatalf("%s: unexpected encoding: got %s, exp %s", tt.s, got, tt.exp)
+		}
+	}
+}
+
+func TestCompileFilter_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"cn=a",
+		"(cn=a",
+		"(cn=a)(cn=b)",
+		"(=a)",
+		"(cn)",
+		"(&)",
+		"(&(cn=a)",
+		"(!(cn=a)",
+		"(cn=a*)",
+		"(cn>=a)",
+		"(cn~=a)",
+		"(cn:dn:=a)",
+		`(cn=\2)`,
+		`(cn=\zz)`,
+	} {
+		if _, err := compileFilter(s); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
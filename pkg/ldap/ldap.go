@@ -0,0 +1,473 @@
+// Package ldap implements a minimal LDAPv3 client.
+//
+// It speaks just enough of the protocol to authenticate users against a
+// directory: simple binds and searches with equality and presence filters,
+// over plain TCP, LDAPS or StartTLS.
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL schemes of LDAP servers.
+const (
+	Scheme    = "ldap"
+	SchemeTLS = "ldaps"
+)
+
+// Default ports of the servers.
+const (
+	DefaultPort    = "389"
+	DefaultTLSPort = "636"
+)
+
+// DefaultTimeout is the default timeout of connecting and of operations.
+const DefaultTimeout = 10 * time.Second
+
+// maxMessageSize bounds the size of a message read from a server.
+const maxMessageSize = 16 * 1024 * 1024
+
+// startTLSOID is the name of the StartTLS extended operation.
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Result codes of operations.
+const (
+	ResultSuccess            = 0
+	ResultInvalidCredentials = 49
+)
+
+// Scopes of a search.
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// Application tags of the protocol operations.
+const (
+	opBindRequest       = classApplication | constructed | 0
+	opBindResponse      = classApplication | constructed | 1
+	opUnbindRequest     = classApplication | 2
+	opSearchRequest     = classApplication | constructed | 3
+	opSearchEntry       = classApplication | constructed | 4
+	opSearchDone        = classApplication | constructed | 5
+	opSearchReference   = classApplication | constructed | 19
+	opExtendedRequest   = classApplication | constructed | 23
+	opExtendedResponse  = classApplication | constructed | 24
+	contextSimpleAuth   = classContext | 0
+	contextExtendedName = classContext | 0
+)
+
+var (
+	// ErrEmptyPassword is returned when binding without a password. Servers
+	// accept such binds as unauthenticated, so they prove nothing.
+	ErrEmptyPassword = errors.New("ldap: empty password")
+
+	// ErrPoolClosed is returned when getting a connection from a closed pool.
+	ErrPoolClosed = errors.New("ldap: pool closed")
+
+	errMalformed    = errors.New("ldap: malformed message")
+	errDisconnected = errors.New("ldap: connection closed by server")
+)
+
+// Error is a result other than success returned by the server.
+type Error struct {
+	Code    int
+	Message string
+}
+
+// Error returns the string representation of the error.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldap: result code %d", e.Code)
+	}
+	return fmt.Sprintf("ldap: result code %d: %s", e.Code, e.Message)
+}
+
+// Dialer connects to an LDAP server.
+type Dialer struct {
+	// URL of the server, ldap://host[:port] or ldaps://host[:port].
+	URL string
+
+	// TLS configuration of ldaps and StartTLS connections.
+	TLSConfig *tls.Config
+
+	// Whether ldap connections are upgraded with StartTLS.
+	StartTLS bool
+
+	// Timeout of connecting and of each operation.
+	Timeout time.Duration
+}
+
+// Dial connects to the server.
+func (d *Dialer) Dial() (*Conn, error) {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	port := DefaultPort
+	switch u.Scheme {
+	case Scheme:
+	case SchemeTLS:
+		port = DefaultTLSPort
+	default:
+		return nil, fmt.Errorf("ldap: invalid scheme %q, expected %q or %q", u.Scheme, Scheme, SchemeTLS)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("ldap: no host")
+	} else if u.Port() != "" {
+		port = u.Port()
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	tlsConfig := &tls.Config{}
+	if d.TLSConfig != nil {
+		tlsConfig = d.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	nd := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if u.Scheme == SchemeTLS {
+		conn, err = tls.DialWithDialer(nd, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = nd.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Conn{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+	if u.Scheme == Scheme && d.StartTLS {
+		if err := c.startTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Conn is a connection to an LDAP server. It runs one operation at a time
+// and is not safe for concurrent use.
+type Conn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	msgID   int64
+
+	// Error that broke the connection. A broken connection can't be reused.
+	err error
+}
+
+// fail marks the connection as broken by err.
+func (c *Conn) fail(err error) error {
+	c.err = err
+	return err
+}
+
+// send sends a request with the protocol operation op and returns its message ID.
+func (c *Conn) send(op []byte) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	c.msgID++
+	msg := tlv(tagSequence, berInteger(tagInteger, c.msgID), op)
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, c.fail(err)
+	} else if _, err := c.conn.Write(msg); err != nil {
+		return 0, c.fail(err)
+	}
+	return c.msgID, nil
+}
+
+// receive returns the protocol operation of the next response to the
+// request with the message ID id.
+func (c *Conn) receive(id int64) (element, error) {
+	for {
+		b, err := readElement(c.r)
+		if err != nil {
+			return element{}, c.fail(err)
+		}
+
+		msg, _, err := parseElement(b)
+		if err != nil {
+			return element{}, c.fail(err)
+		}
+		parts, err := msg.children()
+		if err != nil || len(parts) < 2 || parts[0].tag != tagInteger {
+			return element{}, c.fail(errMalformed)
+		}
+		mid, err := parts[0].integer()
+		if err != nil {
+			return element{}, c.fail(err)
+		}
+
+		switch mid {
+		case id:
+			return parts[1], nil
+		case 0:
+			// Unsolicited notifications, such as a notice of
+			// disconnection, end the connection.
+			return element{}, c.fail(errDisconnected)
+		}
+	}
+}
+
+// parseResult returns the error of an LDAPResult, or nil on success.
+func parseResult(op element) error {
+	parts, err := op.children()
+	if err != nil || len(parts) < 3 || parts[0].tag != tagEnumerated {
+		return errMalformed
+	}
+	code, err := parts[0].integer()
+	if err != nil {
+		return err
+	} else if code == ResultSuccess {
+		return nil
+	}
+	return &Error{Code: int(code), Message: string(parts[2].contents)}
+}
+
+// startTLS upgrades the connection with the StartTLS extended operation.
+func (c *Conn) startTLS(config *tls.Config) error {
+	id, err := c.send(tlv(opExtendedRequest, berString(contextExtendedName, startTLSOID)))
+	if err != nil {
+		return err
+	}
+	resp, err := c.receive(id)
+	if err != nil {
+		return err
+	} else if resp.tag != opExtendedResponse {
+		return c.fail(errMalformed)
+	} else if err := parseResult(resp); err != nil {
+		return c.fail(err)
+	}
+
+	tc := tls.Client(c.conn, config)
+	if err := tc.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return c.fail(err)
+	} else if err := tc.Handshake(); err != nil {
+		return c.fail(err)
+	}
+	c.conn, c.r = tc, bufio.NewReader(tc)
+	return nil
+}
+
+// Bind authenticates the connection as dn with a simple bind. An *Error
+// with the code ResultInvalidCredentials is returned for a wrong password.
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+
+	id, err := c.send(tlv(opBindRequest,
+		berInteger(tagInteger, 3),
+		berString(tagOctetString, dn),
+		berString(contextSimpleAuth, password)))
+	if err != nil {
+		return err
+	}
+	resp, err := c.receive(id)
+	if err != nil {
+		return err
+	} else if resp.tag != opBindResponse {
+		return c.fail(errMalformed)
+	}
+	return parseResult(resp)
+}
+
+// SearchRequest is a search of the entries of a directory.
+type SearchRequest struct {
+	// Entry the search starts from.
+	BaseDN string
+
+	// One of ScopeBaseObject, ScopeSingleLevel or ScopeWholeSubtree.
+	Scope int
+
+	// Filter in the string representation of RFC 4515, e.g. (member=...).
+	Filter string
+
+	// Attributes returned with the entries.
+	Attributes []string
+
+	// Maximum number of entries returned. Zero means no limit.
+	SizeLimit int
+}
+
+// Entry is an entry returned by a search.
+type Entry struct {
+	DN string
+
+	// Values of the attributes by lower-cased attribute name.
+	Attributes map[string][]string
+}
+
+// Values returns the values of the attribute name of the entry.
+func (e *Entry) Values(name string) []string {
+	return e.Attributes[strings.ToLower(name)]
+}
+
+// Search returns the entries matching the search request. References to
+// other servers are not followed.
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filter, err := compileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([][]byte, 0, len(req.Attributes))
+	for _, a := range req.Attributes {
+		attrs = append(attrs, berString(tagOctetString, a))
+	}
+
+	id, err := c.send(tlv(opSearchRequest,
+		berString(tagOctetString, req.BaseDN),
+		berInteger(tagEnumerated, int64(req.Scope)),
+		berInteger(tagEnumerated, 0), // never dereference aliases
+		berInteger(tagInteger, int64(req.SizeLimit)),
+		berInteger(tagInteger, int64(c.timeout/time.Second)),
+		berBoolean(false),
+		filter,
+		tlv(tagSequence, attrs...)))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for {
+		resp, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.tag {
+		case opSearchEntry:
+			e, err := parseEntry(resp)
+			if err != nil {
+				return nil, c.fail(err)
+			}
+			entries = append(entries, e)
+		case opSearchReference:
+		case opSearchDone:
+			if err := parseResult(resp); err != nil {
+				return nil, err
+			}
+			return entries, nil
+		default:
+			return nil, c.fail(errMalformed)
+		}
+	}
+}
+
+// parseEntry decodes a SearchResultEntry.
+func parseEntry(op element) (*Entry, error) {
+	parts, err := op.children()
+	if err != nil || len(parts) < 2 {
+		return nil, errMalformed
+	}
+	attrs, err := parts[1].children()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entry{DN: string(parts[0].contents), Attributes: make(map[string][]string)}
+	for _, a := range attrs {
+		kv, err := a.children()
+		if err != nil || len(kv) < 2 {
+			return nil, errMalformed
+		}
+		vals, err := kv[1].children()
+		if err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(string(kv[0].contents))
+		for _, v := range vals {
+			e.Attributes[name] = append(e.Attributes[name], string(v.contents))
+		}
+	}
+	return e, nil
+}
+
+// Close unbinds and closes the connection.
+func (c *Conn) Close() error {
+	if c.err == nil {
+		c.msgID++
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+		_, _ = c.conn.Write(tlv(tagSequence, berInteger(tagInteger, c.msgID), []byte{opUnbindRequest, 0}))
+	}
+	return c.conn.Close()
+}
+
+// Pool keeps idle connections to a server for reuse.
+type Pool struct {
+	Dialer Dialer
+
+	// Maximum number of idle connections kept.
+	MaxIdle int
+
+	mu     sync.Mutex
+	idle   []*Conn
+	closed bool
+}
+
+// Get returns an idle connection or a new one. Idle connections may have
+// been closed by the server in the meantime.
+func (p *Pool) Get() (*Conn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	return p.Dialer.Dial()
+}
+
+// Put returns c to the pool. Broken connections and connections beyond
+// MaxIdle are closed.
+func (p *Pool) Put(c *Conn) {
+	p.mu.Lock()
+	if c.err == nil && !p.closed && len(p.idle) < p.MaxIdle {
+		p.idle = append(p.idle, c)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	_ = c.Close()
+}
+
+// Close closes the idle connections of the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle, p.closed = nil, true
+	p.mu.Unlock()
+
+	for _, c := range idle {
+		_ = c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package ldap
+
+import (
+	"bufio"
+	"encoding/hex"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// tagSet is the universal tag of the sets of attribute values.
+const tagSet = constructed | 0x11
+
+// testServer is a fake LDAP server answering requests with a handler.
+type testServer struct {
+	ln net.Listener
+
+	// handle returns the messages answering a request with the message ID
+	// id and the protocol operation op.
+	handle func(id int64, op element) [][]byte
+
+	mu       sync.Mutex
+	requests [][]byte
+	accepted int
+}
+
+// newTestServer returns a server listening on a local port. It is closed
+// when the test finishes.
+func newTestServer(t *testing.T, handle func(id int64, op element) [][]byte) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{ln: ln, handle: handle}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+// URL returns the URL of the server.
+func (s *testServer) URL() string {
+	return Scheme + "://" + s.ln.Addr().String()
+}
+
+// Requests returns the requests received so far.
+func (s *testServer) Requests() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.requests...)
+}
+
+// Accepted returns the number of connections accepted so far.
+func (s *testServer) Accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.accepted++
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+func (s *testServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		b, err := readElement(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, b)
+		s.mu.Unlock()
+
+		msg, _, err := parseElement(b)
+		if err != nil {
+			return
+		}
+		parts, err := msg.children()
+		if err != nil || len(parts) < 2 {
+			return
+		}
+		id, err := parts[0].integer()
+		if err != nil || parts[1].tag == opUnbindRequest {
+			return
+		}
+		for _, m := range s.handle(id, parts[1]) {
+			if _, err := conn.Write(m); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// testMessage encodes a message with the message ID id and the protocol
+// operation op.
+func testMessage(id int64, op []byte) []byte {
+	return tlv(tagSequence, berInteger(tagInteger, id), op)
+}
+
+// testResult encodes an LDAPResult with the application tag tag.
+func testResult(tag byte, code int64, message string) []byte {
+	return tlv(tag, berInteger(tagEnumerated, code), berString(tagOctetString, ""), berString(tagOctetString, message))
+}
+
+// testEntry encodes a SearchResultEntry.
+func testEntry(dn string, attrs map[string][]string) []byte {
+	var a [][]byte
+	for name, values := range attrs {
+		var vals [][]byte
+		for _, v := range values {
+			vals = append(vals, berString(tagOctetString, v))
+		}
+		a = append(a, tlv(tagSequence, berString(tagOctetString, name), tlv(tagSet, vals...)))
+	}
+	return tlv(opSearchEntry, berString(tagOctetString, dn), tlv(tagSequence, a...))
+}
+
+// testDirectory returns a handler binding as the DNs with the given
+// passwords and answering every search with entries.
+func testDirectory(passwords map[string]string, entries ...[]byte) func(id int64, op element) [][]byte {
+	return func(id int64, op element) [][]byte {
+		switch op.tag {
+		case opBindRequest:
+			parts, _ := op.children()
+			if pw, ok := passwords[string(parts[1].contents)]; ok && pw == string(parts[2].contents) {
+				return [][]byte{testMessage(id, testResult(opBindResponse, ResultSuccess, ""))}
+			}
+			return [][]byte{testMessage(id, testResult(opBindResponse, ResultInvalidCredentials, "invalid credentials"))}
+		case opSearchRequest:
+			var msgs [][]byte
+			for _, e := range entries {
+				msgs = append(msgs, testMessage(id, e))
+			}
+			return append(msgs, testMessage(id, testResult(opSearchDone, ResultSuccess, "")))
+		}
+		return [][]byte{testMessage(id, testResult(opExtendedResponse, 2, "unsupported"))}
+	}
+}
+
+func TestConn_Bind(t *testing.T) {
+	s := newTestServer(t, testDirectory(map[string]string{"cn=a": "pw"}))
+	d := &Dialer{URL: s.URL()}
+
+	conn, err := d.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind("cn=a", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	reqs := s.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("unexpected requests: %d", len(reqs))
+	} else if got, exp := hex.EncodeToString(reqs[0]), "3012020101600d0201030404636e3d6180027077"; got != exp {
+		t.Fatalf("unexpected bind request: got %s, exp %s", got, exp)
+	}
+
+	// A wrong password fails with the result code of the server, and the
+	// connection remains usable.
+	err = conn.Bind("cn=a", "wrong")
+	if e, ok := err.(*Error); !ok || e.Code != ResultInvalidCredentials || e.Message != "invalid credentials" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if conn.err != nil {
+		t.Fatalf("connection broken by a failed bind: %v", conn.err)
+	}
+
+	// Empty passwords are refused without asking the server.
+	if err := conn.Bind("cn=a", ""); err != ErrEmptyPassword {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n := len(s.Requests()); n != 2 {
+		t.Fatalf("unexpected requests: %d", n)
+	}
+}
+
+func TestConn_Search(t *testing.T) {
+	s := newTestServer(t, testDirectory(nil,
+		testEntry("cn=admins,dc=x", map[string][]string{"CN": {"admins", "Administrators"}}),
+		tlv(opSearchReference, berString(tagOctetString, "ldap://other/dc=x")),
+		testEntry("cn=users,dc=x", map[string][]string{"cn": {"users"}}),
+	))
+	d := &Dialer{URL: s.URL()}
+
+	conn, err := d.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Use the second message ID, as after a bind.
+	conn.msgID = 1
+	entries, err := conn.Search(&SearchRequest{
+		BaseDN:     "dc=x",
+		Scope:      ScopeWholeSubtree,
+		Filter:     "(cn=a)",
+		Attributes: []string{"cn"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "30290201026324" + "040464633d78" + "0a0102" + "0a0100" + "020100" + "02010a" + "010100" + "a3070402636e040161" + "30040402636e"
+	if reqs := s.Requests(); len(reqs) != 1 {
+		t.Fatalf("unexpected requests: %d", len(reqs))
+	} else if got := hex.EncodeToString(reqs[0]); got != exp {
+		t.Fatalf("unexpected search request: got %s, exp %s", got, exp)
+	}
+
+	// References are skipped and attribute names are case insensitive.
+	if len(entries) != 2 {
+		t.Fatalf("unexpected entries: %d", len(entries))
+	} else if entries[0].DN != "cn=admins,dc=x" || entries[1].DN != "cn=users,dc=x" {
+		t.Fatalf("unexpected entries: %s, %s", entries[0].DN, entries[1].DN)
+	} else if got := entries[0].Values("cn"); !reflect.DeepEqual(got, []string{"admins", "Administrators"}) {
+		t.Fatalf("unexpected values: %v", got)
+	} else if got := entries[1].Values("CN"); !reflect.DeepEqual(got, []string{"users"}) {
+		t.Fatalf("unexpected values: %v", got)
+	}
+
+	// Invalid filters are refused before sending anything.
+	if _, err := conn.Search(&SearchRequest{Filter: "(cn=a*)"}); err == nil {
+		t.Fatal("expected error")
+	} else if n := len(s.Requests()); n != 1 {
+		t.Fatalf("unexpected requests: %d", n)
+	}
+}
+
+func TestConn_SearchError(t *testing.T) {
+	s := newTestServer(t, func(id int64, op element) [][]byte {
+		return [][]byte{testMessage(id, testResult(opSearchDone, 32, "no such object"))}
+	})
+	d := &Dialer{URL: s.URL()}
+
+	conn, err := d.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Search(&SearchRequest{BaseDN: "dc=missing", Filter: "(objectClass=*)"})
+	if e, ok := err.(*Error); !ok || e.Code != 32 || e.Error() != "ldap: result code 32: no such object" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConn_Broken(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		response func(id int64) [][]byte
+		err      error
+	}{
+		{
+			name: "notice of disconnection",
+			response: func(id int64) [][]byte {
+				return [][]byte{testMessage(0, tlv(opExtendedResponse, berInteger(tagEnumerated, 52)))}
+			},
+			err: errDisconnected,
+		},
+		{
+			name: "malformed response",
+			response: func(id int64) [][]byte {
+				return [][]byte{testMessage(id, testResult(opSearchDone, ResultSuccess, ""))}
+			},
+			err: errMalformed,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, func(id int64, op element) [][]byte { return tt.response(id) })
+			p := &Pool{Dialer: Dialer{URL: s.URL()}, MaxIdle: 1}
+			defer p.Close()
+
+			conn, err := p.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := conn.Bind("cn=a", "pw"); err != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The broken connection is not reused.
+			if err := conn.Bind("cn=a", "pw"); err != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			p.Put(conn)
+			if len(p.idle) != 0 {
+				t.Fatal("broken connection pooled")
+			}
+		})
+	}
+}
+
+func TestPool(t *testing.T) {
+	s := newTestServer(t, testDirectory(map[string]string{"cn=a": "pw"}))
+	p := &Pool{Dialer: Dialer{URL: s.URL()}, MaxIdle: 1}
+
+	a, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Bind("cn=a", "pw"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only MaxIdle connections are kept.
+	p.Put(a)
+	p.Put(b)
+	if len(p.idle) != 1 || p.idle[0] != a {
+		t.Fatalf("unexpected idle connections: %d", len(p.idle))
+	}
+
+	c, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	} else if c != a {
+		t.Fatal("idle connection not reused")
+	} else if n := s.Accepted(); n != 2 {
+		t.Fatalf("unexpected connections: %d", n)
+	}
+	p.Put(c)
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	} else if _, err := p.Get(); err != ErrPoolClosed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDialer_InvalidURL(t *testing.T) {
+	for _, u := range []string{"http://localhost", "ldap://", "ldap:///dc=x"} {
+		d := &Dialer{URL: u}
+		if _, err := d.Dial(); err == nil {
+			t.Fatalf("%s: expected error", u)
+		}
+	}
+}
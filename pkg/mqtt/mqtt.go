@@ -0,0 +1,415 @@
+// Package mqtt implements a minimal MQTT 3.1.1 publisher.
+//
+// It supports publishing to a single topic with QoS 0 or 1 over TCP or TLS,
+// reconnecting with exponential backoff when the connection is lost.
+package mqtt
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL schemes of MQTT destinations.
+const (
+	Scheme    = "mqtt"
+	SchemeTLS = "mqtts"
+)
+
+// Default ports of the brokers.
+const (
+	DefaultPort    = "1883"
+	DefaultTLSPort = "8883"
+)
+
+// DefaultTimeout is the default timeout of connecting and publishing.
+const DefaultTimeout = 30 * time.Second
+
+// Bounds of the delay between reconnects.
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Control packet types.
+const (
+	packetConnect    = 1
+	packetConnAck    = 2
+	packetPublish    = 3
+	packetPubAck     = 4
+	packetDisconnect = 14
+)
+
+var (
+	// ErrClosed is returned when publishing with a closed publisher.
+	ErrClosed = errors.New("mqtt: publisher closed")
+
+	errMalformedPacket = errors.New("mqtt: malformed packet")
+)
+
+// ConnectError is the return code of a refused connection.
+type ConnectError byte
+
+// Error returns the string representation of the return code.
+func (e ConnectError) Error() string {
+	switch e {
+	case 1:
+		return "mqtt: connection refused, unacceptable protocol version"
+	case 2:
+		return "mqtt: connection refused, identifier rejected"
+	case 3:
+		return "mqtt: connection refused, server unavailable"
+	case 4:
+		return "mqtt: connection refused, bad user name or password"
+	case 5:
+		return "mqtt: connection refused, not authorized"
+	}
+	return fmt.Sprintf("mqtt: connection refused, return code %d", byte(e))
+}
+
+// Options are the options of a publisher, parsed from a destination URL.
+type Options struct {
+	Addr     string // host:port of the broker
+	TLS      bool
+	Topic    string
+	QoS      byte
+	ClientID string
+	Username string
+	Password string
+}
+
+// ParseURL returns the options of an MQTT destination of the form
+// mqtt[s]://[user:password@]host[:port]/topic[?qos=0|1][&client_id=id].
+func ParseURL(u *url.URL) (Options, error) {
+	var opt Options
+	switch u.Scheme {
+	case Scheme:
+	case SchemeTLS:
+		opt.TLS = true
+	default:
+		return opt, fmt.Errorf("invalid scheme %q, expected %q or %q", u.Scheme, Scheme, SchemeTLS)
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if host == "" {
+		return opt, errors.New("no broker")
+	} else if port == "" {
+		port = DefaultPort
+		if opt.TLS {
+			port = DefaultTLSPort
+		}
+	} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return opt, fmt.Errorf("invalid port %q", port)
+	}
+	opt.Addr = net.JoinHostPort(host, port)
+
+	opt.Topic = strings.TrimPrefix(u.Path, "/")
+	if opt.Topic == "" {
+		return opt, errors.New("no topic")
+	} else if strings.ContainsAny(opt.Topic, "+#") {
+		return opt, fmt.Errorf("invalid topic %q: wildcards can't be published to", opt.Topic)
+	}
+
+	q := u.Query()
+	for k := range q {
+		if k != "qos" && k != "client_id" {
+			return opt, fmt.Errorf("unknown parameter %q", k)
+		}
+	}
+	switch v := q.Get("qos"); v {
+	case "", "0":
+	case "1":
+		opt.QoS = 1
+	default:
+		return opt, fmt.Errorf("invalid qos %q: must be 0 or 1", v)
+	}
+	opt.ClientID = q.Get("client_id")
+	if len(opt.ClientID) > 23 {
+		return opt, fmt.Errorf("invalid client_id %q: longer than 23 characters", opt.ClientID)
+	}
+
+	if u.User != nil {
+		opt.Username = u.User.Username()
+		opt.Password, _ = u.User.Password()
+	}
+	return opt, nil
+}
+
+// Publisher publishes messages to a topic.
+type Publisher struct {
+	mu        sync.Mutex
+	opt       Options
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	closed    bool
+
+	conn     net.Conn
+	r        *bufio.Reader
+	packetID uint16
+
+	// Reconnects are delayed after failures, doubling the delay each time.
+	delay     time.Duration
+	nextRetry time.Time
+}
+
+// NewPublisher returns a new publisher. tlsConfig is used when opt.TLS is set.
+// No connection is made until a message is published.
+func NewPublisher(opt Options, tlsConfig *tls.Config, timeout time.Duration) *Publisher {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if opt.ClientID == "" {
+		opt.ClientID = randomClientID()
+	}
+	return &Publisher{opt: opt, tlsConfig: tlsConfig, timeout: timeout}
+}
+
+// Publish publishes payload to the topic. With QoS 1 it waits for the broker
+// to acknowledge it.
+func (p *Publisher) Publish(payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	// An idle connection may have been dropped without us noticing, so a
+	// failure on an existing connection is retried once on a new one.
+	reused := p.conn != nil
+	err := p.publish(payload)
+	if err != nil && reused {
+		err = p.publish(payload)
+	}
+	return err
+}
+
+func (p *Publisher) publish(payload []byte) error {
+	if err := p.connect(); err != nil {
+		return err
+	}
+	if err := p.send(payload); err != nil {
+		p.disconnect()
+		return err
+	}
+	return nil
+}
+
+// connect connects to the broker if not connected, unless the previous
+// attempt failed less than the reconnect delay ago.
+func (p *Publisher) connect() error {
+	if p.conn != nil {
+		return nil
+	} else if wait := time.Until(p.nextRetry); wait > 0 {
+		return fmt.Errorf("mqtt: not connected to %s, reconnecting in %s", p.opt.Addr, wait.Round(time.Millisecond))
+	}
+
+	if err := p.dial(); err != nil {
+		if p.delay == 0 {
+			p.delay = minReconnectDelay
+		} else if p.delay *= 2; p.delay > maxReconnectDelay {
+			p.delay = maxReconnectDelay
+		}
+		p.nextRetry = time.Now().Add(p.delay)
+		return err
+	}
+	p.delay, p.nextRetry = 0, time.Time{}
+	return nil
+}
+
+func (p *Publisher) dial() error {
+	dialer := &net.Dialer{Timeout: p.timeout}
+	var conn net.Conn
+	var err error
+	if p.opt.TLS {
+		tlsConfig := p.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = new(tls.Config)
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(p.opt.Addr)
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", p.opt.Addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", p.opt.Addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	if err := handshake(conn, r, p.opt, p.timeout); err != nil {
+		conn.Close()
+		return err
+	}
+	p.conn, p.r = conn, r
+	return nil
+}
+
+// handshake sends CONNECT and waits for the CONNACK of the broker.
+func handshake(conn net.Conn, r *bufio.Reader, opt Options, timeout time.Duration) error {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4) // protocol level 3.1.1
+	flags := byte(0x02)    // clean session
+	if opt.Username != "" {
+		flags |= 0x80
+		if opt.Password != "" {
+			flags |= 0x40
+		}
+	}
+	body = append(body, flags, 0, 0) // keep alive disabled
+	body = appendString(body, opt.ClientID)
+	if opt.Username != "" {
+		body = appendString(body, opt.Username)
+		if opt.Password != "" {
+			body = appendString(body, opt.Password)
+		}
+	}
+	if _, err := conn.Write(packet(packetConnect<<4, body)); err != nil {
+		return err
+	}
+
+	typ, body, err := readPacket(r)
+	if err != nil {
+		return err
+	} else if typ>>4 != packetConnAck || len(body) != 2 {
+		return errMalformedPacket
+	} else if body[1] != 0 {
+		return ConnectError(body[1])
+	}
+	return nil
+}
+
+// send publishes payload on the current connection.
+func (p *Publisher) send(payload []byte) error {
+	if err := p.conn.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		return err
+	}
+
+	body := appendString(nil, p.opt.Topic)
+	var id uint16
+	if p.opt.QoS > 0 {
+		if p.packetID++; p.packetID == 0 {
+			p.packetID = 1
+		}
+		id = p.packetID
+		body = append(body, byte(id>>8), byte(id))
+	}
+	body = append(body, payload...)
+	if _, err := p.conn.Write(packet(packetPublish<<4|p.opt.QoS<<1, body)); err != nil {
+		return err
+	}
+	if p.opt.QoS == 0 {
+		return nil
+	}
+
+	typ, ack, err := readPacket(p.r)
+	if err != nil {
+		return err
+	} else if typ>>4 != packetPubAck || len(ack) != 2 || binary.BigEndian.Uint16(ack) != id {
+		return errMalformedPacket
+	}
+	return nil
+}
+
+// disconnect closes the current connection.
+func (p *Publisher) disconnect() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.r = nil, nil
+	}
+}
+
+// Close disconnects from the broker. Publish returns ErrClosed after Close
+// is called.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if p.conn != nil {
+		p.conn.SetWriteDeadline(time.Now().Add(p.timeout))
+		p.conn.Write([]byte{packetDisconnect << 4, 0})
+	}
+	p.disconnect()
+	return nil
+}
+
+// packet frames body with a fixed header.
+func packet(header byte, body []byte) []byte {
+	b := make([]byte, 0, len(body)+5)
+	b = append(b, header)
+	n := len(body)
+	for {
+		c := byte(n % 128)
+		if n /= 128; n > 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if n == 0 {
+			break
+		}
+	}
+	return append(b, body...)
+}
+
+// readPacket reads a packet and returns its fixed header byte and its body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, shift int
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errMalformedPacket
+		}
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(c&0x7f) << shift
+		if c&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// randomClientID returns a client identifier unique to this publisher.
+func randomClientID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "cnosdb"
+	}
+	return "cnosdb-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,343 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPacket_RemainingLength(t *testing.T) {
+	for _, tt := range []struct {
+		n   int
+		exp string
+	}{
+		{n: 0, exp: "00"},
+		{n: 127, exp: "7f"},
+		{n: 128, exp: "8001"},
+		{n: 16383, exp: "ff7f"},
+		{n: 16384, exp: "808001"},
+		{n: 2097151, exp: "ffff7f"},
+		{n: 2097152, exp: "80808001"},
+	} {
+		body := make([]byte, tt.n)
+		b := packet(packetPublish<<4, body)
+		if got := hex.EncodeToString(b[1 : len(b)-tt.n]); got != tt.exp {
+			t.Fatalf("%d: unexpected remaining length: got %s, exp %s", tt.n, got, tt.exp)
+		} else if b[0] != 0x30 {
+			t.Fatalf("%d: unexpected header: %x", tt.n, b[0])
+		}
+
+		header, got, err := readPacket(bufio.NewReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Fatalf("%d: %v", tt.n, err)
+		} else if header != 0x30 || len(got) != tt.n {
+			t.Fatalf("%d: unexpected packet: %x, %d bytes", tt.n, header, len(got))
+		}
+	}
+
+	// The remaining length has at most four bytes.
+	b, _ := hex.DecodeString("30ffffffff7f")
+	if _, _, err := readPacket(bufio.NewReader(bytes.NewReader(b))); err != errMalformedPacket {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The remaining length is read before the body.
+	b, _ = hex.DecodeString("30ffff7f00")
+	if _, _, err := readPacket(bufio.NewReader(bytes.NewReader(b))); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// testBroker is a fake MQTT broker acknowledging connections and publishes.
+type testBroker struct {
+	ln net.Listener
+
+	mu sync.Mutex
+
+	// Return code of the CONNACK packets.
+	returnCode byte
+
+	// Offset added to the packet IDs of the PUBACK packets.
+	ackOffset uint16
+
+	// Whether connections are closed after each PUBLISH.
+	closeAfterPublish bool
+
+	connects  [][]byte
+	publishes [][]byte
+}
+
+// newTestBroker returns a broker listening on a local port. It is closed
+// when the test finishes.
+func newTestBroker(t *testing.T) *testBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBroker{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		raw := packet(header, body)
+
+		b.mu.Lock()
+		var resp []byte
+		closeConn := false
+		switch header >> 4 {
+		case packetConnect:
+			b.connects = append(b.connects, raw)
+			resp = []byte{packetConnAck << 4, 2, 0, b.returnCode}
+			closeConn = b.returnCode != 0
+		case packetPublish:
+			b.publishes = append(b.publishes, raw)
+			if qos := header >> 1 & 3; qos > 0 {
+				n := binary.BigEndian.Uint16(body) + 2
+				id := binary.BigEndian.Uint16(body[n:]) + b.ackOffset
+				resp = []byte{packetPubAck << 4, 2, byte(id >> 8), byte(id)}
+			}
+			closeConn = b.closeAfterPublish
+		case packetDisconnect:
+			closeConn = true
+		}
+		b.mu.Unlock()
+
+		if resp != nil {
+			if _, err := conn.Write(resp); err != nil {
+				return
+			}
+		}
+		if closeConn {
+			return
+		}
+	}
+}
+
+// Packets returns the CONNECT and PUBLISH packets received.
+func (b *testBroker) Packets() (connects, publishes []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, p := range b.connects {
+		connects = append(connects, hex.EncodeToString(p))
+	}
+	for _, p := range b.publishes {
+		publishes = append(publishes, hex.EncodeToString(p))
+	}
+	return connects, publishes
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opt     Options
+		connect string
+		publish []string
+	}{
+		{
+			name:    "qos 0",
+			opt:     Options{Topic: "t", ClientID: "c1"},
+			connect: "100e" + "00044d515454" + "04" + "02" + "0000" + "00026331",
+			publish: []string{"3005000174" + "6869", "3005000174" + "6869"},
+		},
+		{
+			name:    "qos 1",
+			opt:     Options{Topic: "t", ClientID: "c1", QoS: 1, Username: "u", Password: "p"},
+			connect: "1014" + "00044d515454" + "04" + "c2" + "0000" + "00026331" + "000175" + "000170",
+			publish: []string{"3207000174" + "0001" + "6869", "3207000174" + "0002" + "6869"},
+		},
+		{
+			name:    "user without password",
+			opt:     Options{Topic: "t", ClientID: "c1", Username: "u"},
+			connect: "1011" + "00044d515454" + "04" + "82" + "0000" + "00026331" + "000175",
+			publish: []string{"3005000174" + "6869", "3005000174" + "6869"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBroker(t)
+			tt.opt.Addr = b.ln.Addr().String()
+			p := NewPublisher(tt.opt, nil, time.Second)
+
+			for i := 0; i < 2; i++ {
+				if err := p.Publish([]byte("hi")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := p.Close(); err != nil {
+				t.Fatal(err)
+			} else if err := p.Publish([]byte("hi")); err != ErrClosed {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Wait for QoS 0 publishes to arrive.
+			deadline := time.Now().Add(5 * time.Second)
+			connects, publishes := b.Packets()
+			for len(publishes) < 2 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+				connects, publishes = b.Packets()
+			}
+
+			if len(connects) != 1 || connects[0] != tt.connect {
+				t.Fatalf("unexpected connects: %v, exp %s", connects, tt.connect)
+			} else if strings.Join(publishes, ",") != strings.Join(tt.publish, ",") {
+				t.Fatalf("unexpected publishes: %v, exp %v", publishes, tt.publish)
+			}
+		})
+	}
+}
+
+func TestPublisher_Errors(t *testing.T) {
+	b := newTestBroker(t)
+	opt := Options{Addr: b.ln.Addr().String(), Topic: "t", ClientID: "c1", QoS: 1}
+
+	// Acknowledgements of other packets are refused.
+	b.mu.Lock()
+	b.ackOffset = 1
+	b.mu.Unlock()
+	p := NewPublisher(opt, nil, time.Second)
+	defer p.Close()
+	if err := p.Publish([]byte("hi")); err != errMalformedPacket {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Refused connections return the return code of the broker.
+	b.mu.Lock()
+	b.returnCode = 5
+	b.mu.Unlock()
+	p2 := NewPublisher(opt, nil, time.Second)
+	defer p2.Close()
+	if err := p2.Publish([]byte("hi")); err != ConnectError(5) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err.Error() != "mqtt: connection refused, not authorized" {
+		t.Fatalf("unexpected message: %s", err)
+	}
+}
+
+func TestPublisher_DroppedConnection(t *testing.T) {
+	b := newTestBroker(t)
+	b.mu.Lock()
+	b.closeAfterPublish = true
+	b.mu.Unlock()
+
+	p := NewPublisher(Options{Addr: b.ln.Addr().String(), Topic: "t", ClientID: "c1", QoS: 1}, nil, time.Second)
+	defer p.Close()
+
+	// The broker drops the connection after each publish, so each publish
+	// after the first one fails on the idle connection and is retried once
+	// on a new one.
+	for i := 0; i < 3; i++ {
+		if err := p.Publish([]byte("hi")); err != nil {
+			t.Fatalf("publish %d: %v", i, err)
+		}
+	}
+	if connects, publishes := b.Packets(); len(connects) != 3 || len(publishes) != 3 {
+		t.Fatalf("unexpected packets: %d connects, %d publishes", len(connects), len(publishes))
+	}
+}
+
+func TestPublisher_ReconnectBackoff(t *testing.T) {
+	b := newTestBroker(t)
+	b.mu.Lock()
+	b.returnCode = 3
+	b.mu.Unlock()
+
+	p := NewPublisher(Options{Addr: b.ln.Addr().String(), Topic: "t", ClientID: "c1", QoS: 1}, nil, time.Second)
+	defer p.Close()
+
+	// retry makes the reconnect delay elapse.
+	retry := func() {
+		p.mu.Lock()
+		p.nextRetry = time.Now().Add(-time.Millisecond)
+		p.mu.Unlock()
+	}
+
+	// The delay doubles after each failed attempt, up to the maximum.
+	for _, exp := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute} {
+		if err := p.Publish([]byte("hi")); err != ConnectError(3) {
+			t.Fatalf("unexpected error: %v", err)
+		} else if p.delay != exp {
+			t.Fatalf("unexpected delay: got %s, exp %s", p.delay, exp)
+		}
+
+		// No connection is attempted before the delay elapses.
+		if err := p.Publish([]byte("hi")); err == nil || !strings.Contains(err.Error(), "reconnecting in") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		retry()
+	}
+	if connects, _ := b.Packets(); len(connects) != 8 {
+		t.Fatalf("unexpected connects: %d", len(connects))
+	}
+
+	// The delay is reset once connected.
+	b.mu.Lock()
+	b.returnCode = 0
+	b.mu.Unlock()
+	if err := p.Publish([]byte("hi")); err != nil {
+		t.Fatal(err)
+	} else if p.delay != 0 || !p.nextRetry.IsZero() {
+		t.Fatalf("reconnect delay not reset: %s", p.delay)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		opt Options
+		err string
+	}{
+		{s: "mqtt://broker/metrics", opt: Options{Addr: "broker:1883", Topic: "metrics"}},
+		{s: "mqtts://u:p@broker:8884/a/b?qos=1&client_id=c1", opt: Options{Addr: "broker:8884", TLS: true, Topic: "a/b", QoS: 1, ClientID: "c1", Username: "u", Password: "p"}},
+		{s: "mqtts://broker/metrics", opt: Options{Addr: "broker:8883", TLS: true, Topic: "metrics"}},
+		{s: "http://broker/metrics", err: `invalid scheme "http", expected "mqtt" or "mqtts"`},
+		{s: "mqtt:///metrics", err: "no broker"},
+		{s: "mqtt://broker:99999/metrics", err: `invalid port "99999"`},
+		{s: "mqtt://broker", err: "no topic"},
+		{s: "mqtt://broker/a/%23", err: `invalid topic "a/#": wildcards can't be published to`},
+		{s: "mqtt://broker/metrics?qos=2", err: `invalid qos "2": must be 0 or 1`},
+		{s: "mqtt://broker/metrics?retain=1", err: `unknown parameter "retain"`},
+		{s: "mqtt://broker/metrics?client_id=" + strings.Repeat("c", 24), err: `invalid client_id "` + strings.Repeat("c", 24) + `": longer than 23 characters`},
+	} {
+		u, err := url.Parse(tt.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opt, err := ParseURL(u)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.s, err)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		}
+		if opt != tt.opt {
+			t.Fatalf("%s: unexpected options: %+v", tt.s, opt)
+		}
+	}
+}
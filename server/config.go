@@ -40,6 +40,10 @@ type Config struct {
 	Cluster     bool   `toml:"cluster"`
 	Hostname    string `toml:"hostname"`
 
+	// NodeTags label this data node. Retention policies with a PLACEMENT
+	// only place shards on nodes whose tags match.
+	NodeTags map[string]string `toml:"node-tags"`
+
 	Meta            *meta.Config
 	Data            tsdb.Config
 	Coordinator     coordinator.Config
@@ -133,6 +137,10 @@ func (c *Config) FromToml(input string) error {
 
 // Validate returns an error if the config is invalid.
 func (c *Config) Validate() error {
+	if err := c.Meta.ValidatePasswordHash(); err != nil {
+		return err
+	}
+
 	if err := c.Data.Validate(); err != nil {
 		return err
 	}
@@ -165,6 +173,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.HTTPD.LDAP.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -33,11 +33,12 @@ type Config struct {
 	RunInterval toml.Duration `toml:"run-interval"`
 }
 
-// NewConfig returns a new instance of Config with defaults.
+// NewConfig returns a new instance of Config with defaults. The service is
+// disabled by default since it writes data on its own.
 func NewConfig() Config {
 	return Config{
 		LogEnabled:        true,
-		Enabled:           true,
+		Enabled:           false,
 		QueryStatsEnabled: false,
 		RunInterval:       toml.Duration(DefaultRunInterval),
 	}
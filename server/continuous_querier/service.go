@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/cnosdb/cnosdb/server/coordinator"
 	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/logger"
 	"github.com/cnosdb/cnosdb/vend/db/models"
@@ -34,6 +35,15 @@ const (
 	statQueryFail = "queryFail"
 )
 
+// Statistics for each continuous query.
+const (
+	statCQRuns           = "runs"           // Executions of the query.
+	statCQFailures       = "failures"       // Executions that returned an error.
+	statCQPointsWritten  = "pointsWritten"  // Points written into the target.
+	statCQLastDurationNs = "lastDurationNs" // Duration of the last execution.
+	statCQLastLagNs      = "lastLagNs"      // Delay of the last execution after its scheduled time.
+)
+
 // ContinuousQuerier represents a service that executes continuous queries.
 type ContinuousQuerier interface {
 	// Run executes the named query in the named database.  Blank database or name matches all.
@@ -95,8 +105,16 @@ type Service struct {
 	// lastRuns maps CQ name to last time it was run.
 	mu       sync.RWMutex
 	lastRuns map[string]time.Time
-	stop     chan struct{}
-	wg       *sync.WaitGroup
+
+	// runs maps CQ name to its last execution and queryStats to its
+	// counters. They have their own lock since mu is held while a CQ
+	// executes.
+	runsMu     sync.RWMutex
+	runs       map[string]coordinator.ContinuousQueryRun
+	queryStats map[string]*queryStatistics
+
+	stop chan struct{}
+	wg   *sync.WaitGroup
 }
 
 // NewService returns a new instance of Service.
@@ -111,6 +129,8 @@ func NewService(c Config) *Service {
 		Logger:            zap.NewNop(),
 		stats:             &Statistics{},
 		lastRuns:          map[string]time.Time{},
+		runs:              map[string]coordinator.ContinuousQueryRun{},
+		queryStats:        map[string]*queryStatistics{},
 	}
 
 	return s
@@ -157,9 +177,21 @@ type Statistics struct {
 	QueryFail int64
 }
 
-// Statistics returns statistics for periodic monitoring.
+// queryStatistics maintains the statistics of a single continuous query.
+type queryStatistics struct {
+	database, name string
+
+	runs          int64
+	failures      int64
+	pointsWritten int64
+	lastDuration  time.Duration
+	lastLag       time.Duration
+}
+
+// Statistics returns statistics for periodic monitoring: the service totals
+// followed by one row per continuous query executed since startup.
 func (s *Service) Statistics(tags map[string]string) []models.Statistic {
-	return []models.Statistic{{
+	statistics := []models.Statistic{{
 		Name: "cq",
 		Tags: tags,
 		Values: map[string]interface{}{
@@ -167,6 +199,68 @@ func (s *Service) Statistics(tags map[string]string) []models.Statistic {
 			statQueryFail: atomic.LoadInt64(&s.stats.QueryFail),
 		},
 	}}
+
+	s.runsMu.RLock()
+	defer s.runsMu.RUnlock()
+	for _, qs := range s.queryStats {
+		statistics = append(statistics, models.Statistic{
+			Name: "cq",
+			Tags: models.StatisticTags{"database": qs.database, "cq": qs.name}.Merge(tags),
+			Values: map[string]interface{}{
+				statCQRuns:           qs.runs,
+				statCQFailures:       qs.failures,
+				statCQPointsWritten:  qs.pointsWritten,
+				statCQLastDurationNs: int64(qs.lastDuration),
+				statCQLastLagNs:      int64(qs.lastLag),
+			},
+		})
+	}
+	return statistics
+}
+
+// recordRun saves an execution of the CQ with the given id. lag is how long
+// after its scheduled time the execution started and written the number of
+// points it wrote, or negative if unknown.
+func (s *Service) recordRun(id, database, name string, run coordinator.ContinuousQueryRun, lag time.Duration, written int64) {
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+
+	s.runs[id] = run
+
+	qs := s.queryStats[id]
+	if qs == nil {
+		qs = &queryStatistics{database: database, name: name}
+		s.queryStats[id] = qs
+	}
+	qs.runs++
+	if run.Err != nil {
+		qs.failures++
+	}
+	if written > 0 {
+		qs.pointsWritten += written
+	}
+	qs.lastDuration = run.Duration
+
+	// A run requested for a time in the future starts before it is due.
+	if lag < 0 {
+		lag = 0
+	}
+	qs.lastLag = lag
+}
+
+// recordError saves the error the CQ with the given id failed with. Errors
+// of an execution are already saved by recordRun, so this only covers CQs
+// failing before they execute, such as those whose query no longer parses.
+func (s *Service) recordError(id string, err error) {
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+
+	run := s.runs[id]
+	if run.Err == err {
+		return
+	}
+	run.Err, run.ErrTime = err, time.Now()
+	s.runs[id] = run
 }
 
 // Run runs the specified continuous query, or all CQs if none is specified.
@@ -205,6 +299,25 @@ func (s *Service) Run(database, name string, t time.Time) error {
 	return nil
 }
 
+// LastContinuousQueryRun returns the last execution of the named CQ by this
+// service. ok is false if it has not run since the service started.
+func (s *Service) LastContinuousQueryRun(database, name string) (run coordinator.ContinuousQueryRun, ok bool) {
+	s.runsMu.RLock()
+	defer s.runsMu.RUnlock()
+	run, ok = s.runs[fmt.Sprintf("%s%s%s", database, idDelimiter, name)]
+	return run, ok
+}
+
+// ResetContinuousQuery forgets the executions and statistics of the named CQ
+// so a CQ recreated with the same name starts afresh.
+func (s *Service) ResetContinuousQuery(database, name string) {
+	id := fmt.Sprintf("%s%s%s", database, idDelimiter, name)
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+	delete(s.runs, id)
+	delete(s.queryStats, id)
+}
+
 // backgroundLoop runs on a go routine and periodically executes CQs.
 func (s *Service) backgroundLoop() {
 	leaseName := "continuous_querier"
@@ -246,6 +359,11 @@ func (s *Service) hasContinuousQueries() bool {
 		if len(db.ContinuousQueries) > 0 {
 			return true
 		}
+		for _, rp := range db.RetentionPolicies {
+			if rp.Downsample != nil {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -254,21 +372,89 @@ func (s *Service) hasContinuousQueries() bool {
 func (s *Service) runContinuousQueries(req *RunRequest) {
 	// Get list of all databases.
 	dbs := s.MetaClient.Databases()
+	// Downsampling schedules that are still attached to a retention policy.
+	downsamples := make(map[string]struct{})
+	// All continuous queries that still exist.
+	ids := make(map[string]struct{})
 	// Loop through all databases executing CQs.
 	for _, db := range dbs {
+		cqs := db.ContinuousQueries
+		for _, cq := range downsampleQueries(&db) {
+			downsamples[fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Name)] = struct{}{}
+			cqs = append(cqs, cq)
+		}
+		for _, cq := range cqs {
+			ids[fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Name)] = struct{}{}
+		}
+
 		// TODO: distribute across nodes
-		for _, cq := range db.ContinuousQueries {
+		for _, cq := range cqs {
 			if !req.matches(&cq) {
 				continue
 			}
 			if ok, err := s.ExecuteContinuousQuery(&db, &cq, req.Now); err != nil {
 				s.Logger.Info("Error executing query", zap.String("query", cq.Query), zap.Error(err))
+				s.recordError(fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Name), err)
 				atomic.AddInt64(&s.stats.QueryFail, 1)
 			} else if ok {
 				atomic.AddInt64(&s.stats.QueryOK, 1)
 			}
 		}
 	}
+
+	// Forget the schedule of retention policies that were dropped or no
+	// longer downsample, so a policy recreated later starts afresh.
+	s.mu.Lock()
+	for id := range s.lastRuns {
+		if _, ok := downsamples[id]; !ok && strings.Contains(id, idDelimiter+downsamplePrefix) {
+			delete(s.lastRuns, id)
+		}
+	}
+	s.mu.Unlock()
+
+	// Stop reporting executions and statistics of continuous queries that
+	// were dropped, including those dropped on other nodes.
+	s.runsMu.Lock()
+	for id := range s.runs {
+		if _, ok := ids[id]; !ok {
+			delete(s.runs, id)
+		}
+	}
+	for id := range s.queryStats {
+		if _, ok := ids[id]; !ok {
+			delete(s.queryStats, id)
+		}
+	}
+	s.runsMu.Unlock()
+}
+
+// downsamplePrefix prefixes the names of the continuous queries generated for
+// retention policies with a DOWNSAMPLE clause. It contains idDelimiter so it
+// cannot clash with the name of a user-defined continuous query.
+const downsamplePrefix = "downsample" + idDelimiter
+
+// downsampleQueries returns a continuous query for each retention policy of
+// dbi that downsamples into another retention policy. Each query aggregates
+// every measurement of the source policy into the same measurement of the
+// target policy, grouped by all tags.
+func downsampleQueries(dbi *meta.DatabaseInfo) []meta.ContinuousQueryInfo {
+	var cqs []meta.ContinuousQueryInfo
+	for _, rpi := range dbi.RetentionPolicies {
+		ds := rpi.Downsample
+		if ds == nil {
+			continue
+		}
+		name := downsamplePrefix + rpi.Name
+		cqs = append(cqs, meta.ContinuousQueryInfo{
+			Name: name,
+			Query: fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s BEGIN SELECT %s INTO %s.%s.:MEASUREMENT FROM %s.%s./.*/ GROUP BY time(%s), * END",
+				cnosql.QuoteIdent(name), cnosql.QuoteIdent(dbi.Name), strings.Join(ds.Calls, ", "),
+				cnosql.QuoteIdent(dbi.Name), cnosql.QuoteIdent(ds.RetentionPolicy),
+				cnosql.QuoteIdent(dbi.Name), cnosql.QuoteIdent(rpi.Name),
+				cnosql.FormatDuration(ds.Every)),
+		})
+	}
+	return cqs
 }
 
 // ExecuteContinuousQuery may execute a single CQ. This will return false if there were no errors and the CQ was not run.
@@ -282,6 +468,10 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		return false, err
 	}
 
+	// Run the CQ as if it were its offset earlier, which delays every
+	// execution without changing the intervals it computes.
+	now = now.Add(-cq.Offset)
+
 	// Set the time zone on the now time if the CQ has one. Otherwise, force UTC.
 	now = now.UTC()
 	if cq.q.Location != nil {
@@ -381,7 +571,24 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	}
 
 	// Do the actual processing of the query & writing of results.
+	runStart := time.Now()
 	res := s.runContinuousQueryAndWriteResult(cq)
+	execution := coordinator.ContinuousQueryRun{Time: runStart, Duration: time.Since(runStart), Err: res.Err}
+	if res.Err != nil {
+		execution.ErrTime = time.Now()
+	}
+
+	// extract number of points written and dropped from SELECT ... INTO result
+	var written, dropped int64 = -1, -1
+	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
+		s := res.Series[0]
+		written = s.Values[0][1].(int64)
+		if len(s.Values[0]) > 2 {
+			dropped = s.Values[0][2].(int64)
+		}
+	}
+
+	s.recordRun(id, dbi.Name, cqi.Name, execution, runStart.Sub(nextRun.Add(cq.Offset)), written)
 	if res.Err != nil {
 		return false, res.Err
 	}
@@ -391,18 +598,12 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		execDuration = time.Since(start)
 	}
 
-	// extract number of points written from SELECT ... INTO result
-	var written int64 = -1
-	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
-		s := res.Series[0]
-		written = s.Values[0][1].(int64)
-	}
-
 	if s.loggingEnabled {
 		log.Info("Finished continuous query",
 			zap.String("name", cq.Info.Name),
 			logger.Database(cq.Database),
 			zap.Int64("written", written),
+			zap.Int64("dropped", dropped),
 			zap.Time("start", startTime),
 			zap.Time("end", endTime),
 			logger.DurationLiteral("duration", execDuration))
@@ -410,7 +611,7 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 
 	if s.queryStatsEnabled && s.Monitor.Enabled() {
 		tags := map[string]string{"db": dbi.Name, "cq": cq.Info.Name}
-		fields := map[string]interface{}{"durationNs": int64(execDuration), "pointsWrittenOK": written, "startTime": startTime.UnixNano(), "endTime": endTime.UnixNano()}
+		fields := map[string]interface{}{"durationNs": int64(execDuration), "pointsWrittenOK": written, "pointsDropped": dropped, "startTime": startTime.UnixNano(), "endTime": endTime.UnixNano()}
 		p, _ := models.NewPoint("cq_query", models.NewTags(tags), fields, time.Now())
 		s.Monitor.WritePoints(models.Points{p})
 	}
@@ -448,6 +649,7 @@ type ContinuousQuery struct {
 	HasRun   bool
 	LastRun  time.Time
 	Resample ResampleOptions
+	Offset   time.Duration
 	q        *cnosql.SelectStatement
 }
 
@@ -488,7 +690,8 @@ func NewContinuousQuery(database string, cqi *meta.ContinuousQueryInfo) (*Contin
 			Every: q.ResampleEvery,
 			For:   q.ResampleFor,
 		},
-		q: q.Source,
+		Offset: q.Offset,
+		q:      q.Source,
 	}
 
 	return cquery, nil
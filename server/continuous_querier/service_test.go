@@ -0,0 +1,184 @@
+package continuous_querier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/server/coordinator"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// testMetaClient returns the databases in dbs.
+type testMetaClient struct {
+	dbs []meta.DatabaseInfo
+}
+
+func (c *testMetaClient) AcquireLease(name string) (*meta.Lease, error) { return nil, nil }
+func (c *testMetaClient) Databases() []meta.DatabaseInfo                { return c.dbs }
+
+func (c *testMetaClient) Database(name string) *meta.DatabaseInfo {
+	for i := range c.dbs {
+		if c.dbs[i].Name == name {
+			return &c.dbs[i]
+		}
+	}
+	return nil
+}
+
+// testStatementExecutor calls ExecuteStatementFn with the SELECT INTO
+// statement of a continuous query.
+type testStatementExecutor struct {
+	ExecuteStatementFn func(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error
+}
+
+func (e *testStatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt cnosql.Statement) error {
+	return e.ExecuteStatementFn(ctx, stmt.(*cnosql.SelectStatement))
+}
+
+// sendWritten sends the result of a SELECT INTO writing n points.
+func sendWritten(ctx *query.ExecutionContext, n int64) error {
+	return ctx.Send(&query.Result{Series: models.Rows{{
+		Name:    "result",
+		Columns: []string{"time", "written", "dropped"},
+		Values:  [][]interface{}{{time.Unix(0, 0).UTC(), n, int64(0)}},
+	}}})
+}
+
+// newTestService returns a service running the continuous queries of db0 with
+// the statement executor e.
+func newTestService(e *testStatementExecutor, cqs ...meta.ContinuousQueryInfo) (*Service, *testMetaClient) {
+	mc := &testMetaClient{dbs: []meta.DatabaseInfo{{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "rp0",
+		ContinuousQueries:      cqs,
+	}}}
+
+	s := NewService(NewConfig())
+	s.MetaClient = mc
+	s.QueryExecutor = query.NewExecutor()
+	s.QueryExecutor.StatementExecutor = e
+	return s, mc
+}
+
+// cqStatistics returns the statistics of each continuous query by name.
+func cqStatistics(s *Service) map[string]map[string]interface{} {
+	stats := make(map[string]map[string]interface{})
+	for _, stat := range s.Statistics(nil)[1:] {
+		stats[stat.Tags["cq"]] = stat.Values
+	}
+	return stats
+}
+
+func TestService_Statistics(t *testing.T) {
+	errFailed := errors.New("query failed")
+	s, mc := newTestService(&testStatementExecutor{
+		ExecuteStatementFn: func(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error {
+			if stmt.Target.Measurement.Name == "cpu_failed" {
+				return errFailed
+			}
+			return sendWritten(ctx, 5)
+		},
+	},
+		meta.ContinuousQueryInfo{Name: "cq0", Query: `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO cpu_count FROM cpu GROUP BY time(1m) END`},
+		meta.ContinuousQueryInfo{Name: "cq1", Query: `CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT count(value) INTO cpu_failed FROM cpu GROUP BY time(1m) END`},
+	)
+
+	// Only the totals are reported before any query runs.
+	if stats := s.Statistics(map[string]string{"hostname": "h0"}); len(stats) != 1 || stats[0].Name != "cq" {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+
+	// The queries run an hour late, on an interval boundary.
+	now := time.Now().UTC().Truncate(time.Minute).Add(-time.Hour)
+	s.runContinuousQueries(&RunRequest{Now: now})
+	s.runContinuousQueries(&RunRequest{Now: now.Add(time.Minute)})
+
+	for _, stat := range s.Statistics(map[string]string{"hostname": "h0"})[1:] {
+		if stat.Name != "cq" || stat.Tags["database"] != "db0" || stat.Tags["hostname"] != "h0" {
+			t.Fatalf("unexpected statistic: %+v", stat)
+		}
+	}
+	stats := cqStatistics(s)
+	if v := stats["cq0"]; v[statCQRuns] != int64(2) || v[statCQFailures] != int64(0) || v[statCQPointsWritten] != int64(10) {
+		t.Fatalf("unexpected statistics: %v", v)
+	} else if v[statCQLastDurationNs].(int64) < 0 || v[statCQLastLagNs].(int64) < int64(50*time.Minute) {
+		t.Fatalf("unexpected timings: %v", v)
+	}
+	if v := stats["cq1"]; v[statCQRuns] != int64(2) || v[statCQFailures] != int64(2) || v[statCQPointsWritten] != int64(0) {
+		t.Fatalf("unexpected statistics: %v", v)
+	}
+	if v := s.Statistics(nil)[0].Values; v[statQueryOK] != int64(2) || v[statQueryFail] != int64(2) {
+		t.Fatalf("unexpected totals: %v", v)
+	}
+
+	// Runs requested ahead of their time have no lag.
+	s.recordRun("db0"+idDelimiter+"cq0", "db0", "cq0", coordinator.ContinuousQueryRun{Time: time.Now()}, -time.Minute, -1)
+	if v := cqStatistics(s)["cq0"]; v[statCQRuns] != int64(3) || v[statCQPointsWritten] != int64(10) || v[statCQLastLagNs] != int64(0) {
+		t.Fatalf("unexpected statistics: %v", v)
+	}
+
+	// Dropped queries are no longer reported.
+	mc.dbs[0].ContinuousQueries = mc.dbs[0].ContinuousQueries[:1]
+	s.runContinuousQueries(&RunRequest{Now: now.Add(2 * time.Minute)})
+	if stats := cqStatistics(s); len(stats) != 1 || stats["cq0"] == nil {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+}
+
+func TestService_LastContinuousQueryRun(t *testing.T) {
+	errFailed := errors.New("query failed")
+	var failed bool
+	s, mc := newTestService(&testStatementExecutor{
+		ExecuteStatementFn: func(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error {
+			if failed {
+				return errFailed
+			}
+			return sendWritten(ctx, 1)
+		},
+	},
+		meta.ContinuousQueryInfo{Name: "cq0", Query: `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO cpu_count FROM cpu GROUP BY time(1m) END`},
+		meta.ContinuousQueryInfo{Name: "cq1", Query: `CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT value INTO cpu_copy FROM cpu GROUP BY time(1m) END`},
+	)
+	now := time.Now().UTC().Truncate(time.Minute).Add(-time.Hour)
+
+	// A failed execution records when it failed.
+	failed = true
+	start := time.Now()
+	s.runContinuousQueries(&RunRequest{Now: now})
+	run, ok := s.LastContinuousQueryRun("db0", "cq0")
+	if !ok || run.Err != errFailed || run.Time.Before(start) || run.ErrTime.Before(run.Time) {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+
+	// A query failing before it executes has an error but no execution.
+	if run, ok := s.LastContinuousQueryRun("db0", "cq1"); !ok || !run.Time.IsZero() || run.Err == nil || run.ErrTime.Before(start) {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+
+	// A successful execution clears the error.
+	failed = false
+	s.runContinuousQueries(&RunRequest{Now: now.Add(time.Minute)})
+	if run, ok := s.LastContinuousQueryRun("db0", "cq0"); !ok || run.Err != nil || !run.ErrTime.IsZero() {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+
+	// A dropped query is forgotten, so one recreated with its name starts
+	// afresh.
+	s.ResetContinuousQuery("db0", "cq0")
+	if _, ok := s.LastContinuousQueryRun("db0", "cq0"); ok {
+		t.Fatal("unexpected run")
+	} else if _, ok := cqStatistics(s)["cq0"]; ok {
+		t.Fatal("unexpected statistics")
+	}
+
+	// So is one dropped on another node.
+	mc.dbs[0].ContinuousQueries = nil
+	s.runContinuousQueries(&RunRequest{Now: now.Add(2 * time.Minute)})
+	if _, ok := s.LastContinuousQueryRun("db0", "cq1"); ok {
+		t.Fatal("unexpected run")
+	}
+}
@@ -0,0 +1,197 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// The keys for statistics generated by the "coordinator_select_admission" module.
+const (
+	statSelectsRunning        = "selectsRunning"   // Number of SELECT statements admitted and running.
+	statSelectsQueued         = "selectsQueued"    // Number of SELECT statements waiting for a slot.
+	statSelectsRejected       = "selectsRejected"  // Number of SELECT statements rejected because the server was busy.
+	statSelectsPreempted      = "selectsPreempted" // Number of low priority SELECT statements killed for high priority ones.
+	selectAdmissionStatistics = "coordinator_select_admission"
+)
+
+// selectAdmission limits the number of SELECT statements running at once.
+// Statements beyond the limit wait in a bounded queue, ordered by priority
+// and then by arrival.
+type selectAdmission struct {
+	mu        sync.Mutex
+	running   []*admissionSlot
+	queue     []*admissionWaiter
+	rejected  int64
+	preempted int64
+}
+
+// admissionRequest describes a statement asking for a slot.
+type admissionRequest struct {
+	priority query.Priority
+
+	// Kills the statement if it is preempted. May be nil.
+	kill func()
+}
+
+// admissionSlot is a slot held by a running statement.
+type admissionSlot struct {
+	admissionRequest
+	preempted bool
+}
+
+// admissionWaiter is a statement waiting for a slot. ready is closed once
+// the slot is given to it.
+type admissionWaiter struct {
+	slot  *admissionSlot
+	ready chan struct{}
+}
+
+// acquire admits a statement if fewer than maxRunning statements run, or
+// waits for one of them to finish. It returns a *query.ServerBusyError if
+// maxQueued statements already wait or no slot frees up within maxWait,
+// and the error of ctx if it is done first. Zero maxRunning is unlimited,
+// zero maxQueued doesn't queue and zero maxWait waits as long as ctx. If
+// preempt is set, a high priority statement that must wait kills the
+// oldest running low priority statement. The returned function must be
+// called once an admitted statement finishes.
+func (a *selectAdmission) acquire(ctx context.Context, r admissionRequest, maxRunning, maxQueued int, maxWait time.Duration, preempt bool) (func(), error) {
+	slot := &admissionSlot{admissionRequest: r}
+	release := func() { a.release(slot, maxRunning) }
+
+	a.mu.Lock()
+	if maxRunning <= 0 || (len(a.running) < maxRunning && len(a.queue) == 0) {
+		a.running = append(a.running, slot)
+		a.mu.Unlock()
+		return release, nil
+	} else if len(a.queue) >= maxQueued {
+		a.rejected++
+		a.mu.Unlock()
+		return nil, &query.ServerBusyError{Limit: int64(maxRunning), Reason: "the select queue is full"}
+	}
+	w := &admissionWaiter{slot: slot, ready: make(chan struct{})}
+	a.enqueue(w)
+	var victim *admissionSlot
+	if preempt && r.priority == query.HighPriority {
+		victim = a.preemptable()
+	}
+	a.mu.Unlock()
+
+	// The victim releases its slot once it stopped, which is given to the
+	// statement queued first.
+	if victim != nil && victim.kill != nil {
+		victim.kill()
+	}
+
+	var timerCh <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timerCh:
+		err = &query.ServerBusyError{Limit: int64(maxRunning), Reason: "queued for longer than " + maxWait.String()}
+	}
+
+	// The slot may have been given to the statement meanwhile.
+	if !a.dequeue(w) {
+		release()
+		return nil, err
+	}
+	if _, ok := err.(*query.ServerBusyError); ok {
+		a.mu.Lock()
+		a.rejected++
+		a.mu.Unlock()
+	}
+	return nil, err
+}
+
+// enqueue queues w after the statements of the same or a higher priority.
+func (a *selectAdmission) enqueue(w *admissionWaiter) {
+	i := len(a.queue)
+	for i > 0 && a.queue[i-1].slot.priority < w.slot.priority {
+		i--
+	}
+	a.queue = append(a.queue, nil)
+	copy(a.queue[i+1:], a.queue[i:])
+	a.queue[i] = w
+}
+
+// preemptable marks the oldest running low priority statement as preempted
+// and returns it, or returns nil if there is none.
+func (a *selectAdmission) preemptable() *admissionSlot {
+	for _, slot := range a.running {
+		if slot.priority == query.LowPriority && !slot.preempted {
+			slot.preempted = true
+			a.preempted++
+			return slot
+		}
+	}
+	return nil
+}
+
+// dequeue removes w from the queue and returns whether it was still queued.
+func (a *selectAdmission) dequeue(w *admissionWaiter) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, other := range a.queue {
+		if other == w {
+			a.queue = append(a.queue[:i], a.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// release frees the slot of a finished statement and gives the freed slots
+// to the statements queued first.
+func (a *selectAdmission) release(slot *admissionSlot, maxRunning int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, other := range a.running {
+		if other == slot {
+			a.running = append(a.running[:i], a.running[i+1:]...)
+			break
+		}
+	}
+	for len(a.queue) > 0 && len(a.running) < maxRunning {
+		w := a.queue[0]
+		a.queue = a.queue[1:]
+		a.running = append(a.running, w.slot)
+		close(w.ready)
+	}
+}
+
+// counts returns the number of running and queued statements.
+func (a *selectAdmission) counts() (running, queued int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.running), len(a.queue)
+}
+
+// Statistics returns the running, queued, rejected and preempted SELECT
+// statements.
+func (a *selectAdmission) Statistics(tags map[string]string) []models.Statistic {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return []models.Statistic{{
+		Name: selectAdmissionStatistics,
+		Tags: tags,
+		Values: map[string]interface{}{
+			statSelectsRunning:   int64(len(a.running)),
+			statSelectsQueued:    int64(len(a.queue)),
+			statSelectsRejected:  a.rejected,
+			statSelectsPreempted: a.preempted,
+		},
+	}}
+}
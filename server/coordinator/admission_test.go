@@ -0,0 +1,167 @@
+package coordinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+func TestSelectAdmission_Acquire(t *testing.T) {
+	var a selectAdmission
+
+	release, err := a.acquire(context.Background(), admissionRequest{}, 1, 1, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second statement waits for the first one.
+	admitted := make(chan func())
+	go func() {
+		release, err := a.acquire(context.Background(), admissionRequest{}, 1, 1, 0, false)
+		if err != nil {
+			t.Error(err)
+		}
+		admitted <- release
+	}()
+	waitQueued(t, &a, 1)
+
+	// The queue is full.
+	if _, err := a.acquire(context.Background(), admissionRequest{}, 1, 1, 0, false); err == nil {
+		t.Fatal("expected error")
+	} else if e, ok := err.(*query.ServerBusyError); !ok || e.Reason != "the select queue is full" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	release()
+	(<-admitted)()
+	if running, queued := a.counts(); running != 0 || queued != 0 {
+		t.Fatalf("unexpected counts: running=%d queued=%d", running, queued)
+	}
+}
+
+func TestSelectAdmission_Acquire_LeaveQueue(t *testing.T) {
+	var a selectAdmission
+	release, err := a.acquire(context.Background(), admissionRequest{}, 1, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// Waiting longer than the queue time is rejected.
+	if _, err := a.acquire(context.Background(), admissionRequest{}, 1, 10, 10*time.Millisecond, false); err == nil {
+		t.Fatal("expected error")
+	} else if e, ok := err.(*query.ServerBusyError); !ok || e.Reason != "queued for longer than 10ms" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A killed query leaves the queue without counting as rejected.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitQueued(t, &a, 1)
+		cancel()
+	}()
+	if _, err := a.acquire(ctx, admissionRequest{}, 1, 10, 0, false); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := a.Statistics(nil)[0].Values
+	if stats[statSelectsRunning] != int64(1) || stats[statSelectsQueued] != int64(0) || stats[statSelectsRejected] != int64(1) {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+}
+
+func TestSelectAdmission_Acquire_Priority(t *testing.T) {
+	var a selectAdmission
+	release, err := a.acquire(context.Background(), admissionRequest{}, 1, 10, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each statement releases its slot as soon as it is admitted.
+	admitted := make(chan query.Priority, 3)
+	for i, priority := range []query.Priority{query.LowPriority, query.NormalPriority, query.HighPriority} {
+		priority := priority
+		go func() {
+			release, err := a.acquire(context.Background(), admissionRequest{priority: priority}, 1, 10, 0, false)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			admitted <- priority
+			release()
+		}()
+		waitQueued(t, &a, i+1)
+	}
+
+	release()
+	for _, exp := range []query.Priority{query.HighPriority, query.NormalPriority, query.LowPriority} {
+		if priority := <-admitted; priority != exp {
+			t.Fatalf("unexpected priority admitted: got %s, exp %s", priority, exp)
+		}
+	}
+}
+
+func TestSelectAdmission_Acquire_Preempt(t *testing.T) {
+	var a selectAdmission
+
+	// The oldest running low priority statement is killed, not the normal
+	// priority one or the newer low priority one.
+	killed := make(chan int, 3)
+	var releases []func()
+	for i, priority := range []query.Priority{query.NormalPriority, query.LowPriority, query.LowPriority} {
+		i := i
+		release, err := a.acquire(context.Background(), admissionRequest{
+			priority: priority,
+			kill:     func() { killed <- i },
+		}, 3, 10, 0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+
+	// Low priority statements never preempt.
+	go func() {
+		waitQueued(t, &a, 1)
+		if _, err := a.acquire(context.Background(), admissionRequest{priority: query.HighPriority}, 3, 10, 0, true); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := a.acquire(ctx, admissionRequest{priority: query.LowPriority}, 3, 10, 0, true); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if i := <-killed; i != 1 {
+		t.Fatalf("unexpected statement killed: %d", i)
+	}
+	releases[1]()
+	waitQueued(t, &a, 0)
+
+	select {
+	case i := <-killed:
+		t.Fatalf("unexpected statement killed: %d", i)
+	default:
+	}
+	stats := a.Statistics(nil)[0].Values
+	if stats[statSelectsRunning] != int64(3) || stats[statSelectsPreempted] != int64(1) {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+}
+
+// waitQueued waits until n statements are queued in a.
+func waitQueued(t *testing.T, a *selectAdmission, n int) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, queued := a.counts(); queued == n {
+			return
+		} else if time.Now().After(deadline) {
+			t.Errorf("%d statements queued, expected %d", queued, n)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,211 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// AuditMeasurement is the measurement audit records are written to.
+	AuditMeasurement = "audit"
+
+	// AuditStatusOK is the status of a statement that executed successfully.
+	AuditStatusOK = "ok"
+
+	// AuditStatusError is the status of a statement that returned an error.
+	AuditStatusError = "error"
+)
+
+// AuditRecord is the record of an audited statement.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Database  string    `json:"database,omitempty"`
+	Type      string    `json:"type"`
+	Statement string    `json:"statement"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// newAuditRecord returns the record of stmt executed in ctx with the result err.
+//
+// The statement text is taken from stmt.String(), which writes passwords of
+// user statements as [REDACTED] and strips credentials from subscription
+// destinations, so secrets never reach the audit sinks.
+func newAuditRecord(ctx *query.ExecutionContext, stmt cnosql.Statement, err error) *AuditRecord {
+	r := &AuditRecord{
+		Time:      time.Now().UTC(),
+		User:      ctx.UserID,
+		Database:  ctx.Database,
+		Type:      statementTypeName(stmt),
+		Statement: stmt.String(),
+		Status:    AuditStatusOK,
+	}
+	if err != nil {
+		r.Status, r.Error = AuditStatusError, err.Error()
+	}
+	return r
+}
+
+// isAuditedStatement returns true if stmt is a DDL, user management or other
+// security-sensitive statement. SELECT ... INTO is not audited since it is
+// issued continuously by continuous queries.
+func isAuditedStatement(stmt cnosql.Statement) bool {
+	if _, ok := stmt.(*cnosql.SelectStatement); ok {
+		return false
+	}
+	return cnosql.IsMutatingStatement(stmt)
+}
+
+// AuditSink is a destination for audit records.
+type AuditSink interface {
+	WriteAuditRecord(r *AuditRecord) error
+}
+
+// AuditLog writes records of audited statements to a set of sinks.
+type AuditLog struct {
+	Sinks  []AuditSink
+	Logger *zap.Logger
+}
+
+// NewAuditLog returns a new instance of AuditLog writing to sinks.
+func NewAuditLog(sinks ...AuditSink) *AuditLog {
+	return &AuditLog{
+		Sinks:  sinks,
+		Logger: zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the audit log.
+func (l *AuditLog) WithLogger(log *zap.Logger) {
+	l.Logger = log.With(zap.String("service", "audit"))
+}
+
+// Record writes r to every sink. A failing sink is logged and does not
+// prevent the record from reaching the others.
+func (l *AuditLog) Record(r *AuditRecord) {
+	for _, s := range l.Sinks {
+		if err := s.WriteAuditRecord(r); err != nil {
+			l.Logger.Warn("Failed to write audit record",
+				zap.String("type", r.Type),
+				zap.Error(err))
+		}
+	}
+}
+
+// Close closes the sinks of the audit log that hold resources.
+func (l *AuditLog) Close() error {
+	var err error
+	for _, s := range l.Sinks {
+		if c, ok := s.(io.Closer); ok {
+			if e := c.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// AuditFile writes audit records as JSON lines to a file rotated by size.
+type AuditFile struct {
+	f *jsonLinesFile
+}
+
+// NewAuditFile returns an AuditFile writing to path. The file is rotated
+// when it reaches maxSize megabytes; at most maxBackups rotated files are
+// kept for at most maxAge days. Zero values keep the lumberjack defaults.
+func NewAuditFile(path string, maxSize, maxBackups, maxAge int) *AuditFile {
+	return &AuditFile{f: newJSONLinesFile(path, maxSize, maxBackups, maxAge)}
+}
+
+// WriteAuditRecord appends r to the file.
+func (f *AuditFile) WriteAuditRecord(r *AuditRecord) error {
+	return f.f.write(r)
+}
+
+// Close closes the file.
+func (f *AuditFile) Close() error {
+	return f.f.Close()
+}
+
+// jsonLinesFile writes values as JSON lines to a file rotated by size.
+type jsonLinesFile struct {
+	mu sync.Mutex
+	w  *lumberjack.Logger
+}
+
+func newJSONLinesFile(path string, maxSize, maxBackups, maxAge int) *jsonLinesFile {
+	return &jsonLinesFile{
+		w: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAge,
+		},
+	}
+}
+
+// write appends v to the file.
+func (f *jsonLinesFile) write(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.w.Write(b)
+	return err
+}
+
+// Close closes the file.
+func (f *jsonLinesFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.w.Close()
+}
+
+// AuditPoints writes audit records as points of the audit measurement.
+type AuditPoints struct {
+	PointsWriter interface {
+		WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+	}
+	Database        string
+	RetentionPolicy string
+}
+
+// WriteAuditRecord writes r as a single point.
+func (p *AuditPoints) WriteAuditRecord(r *AuditRecord) error {
+	tags := map[string]string{
+		"type":   r.Type,
+		"status": r.Status,
+	}
+	// Tag values cannot be empty, e.g. when authentication is disabled.
+	if r.User != "" {
+		tags["user"] = r.User
+	}
+	fields := map[string]interface{}{
+		"statement": r.Statement,
+	}
+	if r.Database != "" {
+		fields["database"] = r.Database
+	}
+	if r.Error != "" {
+		fields["error"] = r.Error
+	}
+
+	pt, err := models.NewPoint(AuditMeasurement, models.NewTags(tags), fields, r.Time)
+	if err != nil {
+		return err
+	}
+	return p.PointsWriter.WritePointsPrivileged(p.Database, p.RetentionPolicy, models.ConsistencyLevelAny, []models.Point{pt})
+}
@@ -4,6 +4,7 @@ package coordinator
 
 import (
 	"github.com/cnosdb/cnosdb/vend/common/monitor/diagnostics"
+	"strings"
 	"time"
 
 	"github.com/cnosdb/cnosdb/vend/common/pkg/toml"
@@ -28,6 +29,18 @@ const (
 	// A value of zero will make the maximum query limit unlimited.
 	DefaultMaxConcurrentQueries = 0
 
+	// DefaultMaxConcurrentSelects is the maximum number of running SELECT
+	// statements before new ones are queued. A value of zero is unlimited.
+	DefaultMaxConcurrentSelects = 0
+
+	// DefaultMaxQueuedSelects is the maximum number of SELECT statements
+	// waiting to run before new ones are rejected.
+	DefaultMaxQueuedSelects = 100
+
+	// DefaultMaxSelectQueueTime is the maximum time a SELECT statement waits
+	// to run before it is rejected.
+	DefaultMaxSelectQueueTime = 10 * time.Second
+
 	// DefaultMaxSelectPointN is the maximum number of points a SELECT can process.
 	// A value of zero will make the maximum point count unlimited.
 	DefaultMaxSelectPointN = 0
@@ -35,6 +48,33 @@ const (
 	// DefaultMaxSelectSeriesN is the maximum number of series a SELECT can run.
 	// A value of zero will make the maximum series count unlimited.
 	DefaultMaxSelectSeriesN = 0
+
+	// DefaultMaxSelectMemory is the maximum number of bytes a SELECT can hold
+	// in results and SELECT INTO buffers. A value of zero makes it unlimited.
+	DefaultMaxSelectMemory = 0
+
+	// DefaultIntoWriteBatchSize is the number of points a SELECT INTO buffers
+	// before writing them to the target.
+	DefaultIntoWriteBatchSize = 10000
+
+	// DefaultIntoWriteConcurrency is the number of batches a SELECT INTO
+	// writes concurrently.
+	DefaultIntoWriteConcurrency = 1
+
+	// DefaultRuntimeStatsThreshold is the minimum duration of a statement before
+	// GC and heap activity is attributed to it in SHOW STATS.
+	DefaultRuntimeStatsThreshold = time.Second
+
+	// DefaultAuditLogMaxSize is the size in megabytes at which the audit log
+	// file is rotated.
+	DefaultAuditLogMaxSize = 100
+
+	// DefaultSlowQuerySampleN records every slow statement.
+	DefaultSlowQuerySampleN = 1
+
+	// DefaultSlowQueryLogMaxSize is the size in megabytes at which the slow
+	// query log file is rotated.
+	DefaultSlowQueryLogMaxSize = 100
 )
 
 // Config represents the configuration for the coordinator service.
@@ -48,9 +88,40 @@ type Config struct {
 	MaxConcurrentQueries int           `toml:"max-concurrent-queries"`
 	QueryTimeout         toml.Duration `toml:"query-timeout"`
 	LogQueriesAfter      toml.Duration `toml:"log-queries-after"`
+	MaxConcurrentSelects int           `toml:"max-concurrent-selects"`
+	MaxQueuedSelects     int           `toml:"max-queued-selects"`
+	MaxSelectQueueTime   toml.Duration `toml:"max-select-queue-time"`
+	PreemptLowPriority   bool          `toml:"preempt-low-priority"`
 	MaxSelectPointN      int           `toml:"max-select-point"`
 	MaxSelectSeriesN     int           `toml:"max-select-series"`
 	MaxSelectBucketsN    int           `toml:"max-select-buckets"`
+	MaxSelectMemory      toml.Size     `toml:"max-select-memory"`
+	IntoWriteBatchSize   int           `toml:"into-write-batch-size"`
+	IntoFlushInterval    toml.Duration `toml:"into-flush-interval"`
+	IntoWriteConcurrency int           `toml:"into-write-concurrency"`
+	IntoWriteRateLimit   int           `toml:"into-write-rate-limit"`
+
+	RuntimeStatsThreshold toml.Duration `toml:"runtime-stats-threshold"`
+
+	EscalateNotices []string `toml:"escalate-notices"`
+
+	OperationLockTimeout toml.Duration `toml:"operation-lock-timeout"`
+
+	AuditLogPath         string `toml:"audit-log-path"`
+	AuditLogMaxSize      int    `toml:"audit-log-max-size"`
+	AuditLogMaxBackups   int    `toml:"audit-log-max-backups"`
+	AuditLogMaxAge       int    `toml:"audit-log-max-age"`
+	AuditDatabase        string `toml:"audit-database"`
+	AuditRetentionPolicy string `toml:"audit-retention-policy"`
+
+	SlowQueryThreshold       toml.Duration `toml:"slow-query-threshold"`
+	SlowQuerySampleN         int           `toml:"slow-query-sample-n"`
+	SlowQueryLogPath         string        `toml:"slow-query-log-path"`
+	SlowQueryLogMaxSize      int           `toml:"slow-query-log-max-size"`
+	SlowQueryLogMaxBackups   int           `toml:"slow-query-log-max-backups"`
+	SlowQueryLogMaxAge       int           `toml:"slow-query-log-max-age"`
+	SlowQueryDatabase        string        `toml:"slow-query-database"`
+	SlowQueryRetentionPolicy string        `toml:"slow-query-retention-policy"`
 }
 
 // NewConfig returns an instance of Config with defaults.
@@ -63,20 +134,52 @@ func NewConfig() Config {
 
 		QueryTimeout:         toml.Duration(query.DefaultQueryTimeout),
 		MaxConcurrentQueries: DefaultMaxConcurrentQueries,
+		MaxConcurrentSelects: DefaultMaxConcurrentSelects,
+		MaxQueuedSelects:     DefaultMaxQueuedSelects,
+		MaxSelectQueueTime:   toml.Duration(DefaultMaxSelectQueueTime),
 		MaxSelectPointN:      DefaultMaxSelectPointN,
 		MaxSelectSeriesN:     DefaultMaxSelectSeriesN,
+		MaxSelectMemory:      toml.Size(DefaultMaxSelectMemory),
+		IntoWriteBatchSize:   DefaultIntoWriteBatchSize,
+		IntoWriteConcurrency: DefaultIntoWriteConcurrency,
+
+		RuntimeStatsThreshold: toml.Duration(DefaultRuntimeStatsThreshold),
+		OperationLockTimeout:  toml.Duration(DefaultOperationLockTimeout),
+
+		AuditLogMaxSize: DefaultAuditLogMaxSize,
+
+		SlowQuerySampleN:    DefaultSlowQuerySampleN,
+		SlowQueryLogMaxSize: DefaultSlowQueryLogMaxSize,
 	}
 }
 
 // Diagnostics returns a diagnostics representation of a subset of the Config.
 func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	return diagnostics.RowFromMap(map[string]interface{}{
-		"write-timeout":          c.WriteTimeout,
-		"max-concurrent-queries": c.MaxConcurrentQueries,
-		"query-timeout":          c.QueryTimeout,
-		"log-queries-after":      c.LogQueriesAfter,
-		"max-select-point":       c.MaxSelectPointN,
-		"max-select-series":      c.MaxSelectSeriesN,
-		"max-select-buckets":     c.MaxSelectBucketsN,
+		"write-timeout":           c.WriteTimeout,
+		"max-concurrent-queries":  c.MaxConcurrentQueries,
+		"query-timeout":           c.QueryTimeout,
+		"log-queries-after":       c.LogQueriesAfter,
+		"max-concurrent-selects":  c.MaxConcurrentSelects,
+		"max-queued-selects":      c.MaxQueuedSelects,
+		"max-select-queue-time":   c.MaxSelectQueueTime,
+		"preempt-low-priority":    c.PreemptLowPriority,
+		"max-select-point":        c.MaxSelectPointN,
+		"max-select-series":       c.MaxSelectSeriesN,
+		"max-select-buckets":      c.MaxSelectBucketsN,
+		"max-select-memory":       c.MaxSelectMemory,
+		"into-write-batch-size":   c.IntoWriteBatchSize,
+		"into-flush-interval":     c.IntoFlushInterval,
+		"into-write-concurrency":  c.IntoWriteConcurrency,
+		"into-write-rate-limit":   c.IntoWriteRateLimit,
+		"runtime-stats-threshold": c.RuntimeStatsThreshold,
+		"escalate-notices":        strings.Join(c.EscalateNotices, ","),
+		"operation-lock-timeout":  c.OperationLockTimeout,
+		"audit-log-path":          c.AuditLogPath,
+		"audit-database":          c.AuditDatabase,
+		"slow-query-threshold":    c.SlowQueryThreshold,
+		"slow-query-sample-n":     c.SlowQuerySampleN,
+		"slow-query-log-path":     c.SlowQueryLogPath,
+		"slow-query-database":     c.SlowQueryDatabase,
 	}), nil
 }
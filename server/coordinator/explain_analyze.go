@@ -0,0 +1,157 @@
+package coordinator
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing/fields"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing/labels"
+)
+
+// explainAnalyzeMaxShards is the number of shards EXPLAIN ANALYZE breaks
+// down, slowest first. The remaining shards are summarized in one node.
+const explainAnalyzeMaxShards = 20
+
+// shardSpans collects the spans of the iterators created on one shard.
+type shardSpans struct {
+	id        string
+	iterators []*tracing.TreeNode
+
+	pointN       int64
+	blockN       int64
+	planningTime time.Duration
+	readTime     time.Duration
+}
+
+// add adds the span of an iterator created on the shard.
+func (s *shardSpans) add(n *tracing.TreeNode) {
+	s.iterators = append(s.iterators, n)
+	s.merge(n.Raw.Fields)
+}
+
+// merge adds the points, blocks and times in f to the totals of the shard.
+func (s *shardSpans) merge(f fields.Fields) {
+	for _, f := range f {
+		switch v := f.Value().(type) {
+		case int64:
+			if f.Key() == "points_read" {
+				s.pointN += v
+			} else if strings.HasSuffix(f.Key(), "_blocks_decoded") {
+				s.blockN += v
+			}
+		case time.Duration:
+			switch f.Key() {
+			case "planning_time":
+				s.planningTime += v
+			case "read_time":
+				s.readTime += v
+			}
+		}
+	}
+}
+
+// duration returns the time spent creating and reading the iterators.
+func (s *shardSpans) duration() time.Duration {
+	return s.planningTime + s.readTime
+}
+
+func (s *shardSpans) fields() fields.Fields {
+	return fields.New(
+		fields.Int64("iterators", int64(len(s.iterators))),
+		fields.Int64("points_read", s.pointN),
+		fields.Int64("blocks_decoded", s.blockN),
+		fields.Duration("planning_time", s.planningTime),
+		fields.Duration("read_time", s.readTime),
+		fields.Duration("total_time", s.duration()),
+	)
+}
+
+// explainAnalyzeTree moves the spans of the iterators created on each shard
+// from where they were created to a "shards" node under root, grouped by
+// shard. Only the slowest explainAnalyzeMaxShards shards are broken down,
+// so queries over many shards don't return thousands of rows.
+func explainAnalyzeTree(root *tracing.TreeNode) *tracing.TreeNode {
+	shards := make(map[string]*shardSpans)
+	removeIteratorSpans(root, shards)
+	if len(shards) == 0 {
+		return root
+	}
+
+	list := make([]*shardSpans, 0, len(shards))
+	for _, s := range shards {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if d1, d2 := list[i].duration(), list[j].duration(); d1 != d2 {
+			return d1 > d2
+		}
+		return list[i].id < list[j].id
+	})
+
+	node := &tracing.TreeNode{Raw: tracing.RawSpan{Name: "shards"}}
+	for i, s := range list {
+		if i == explainAnalyzeMaxShards {
+			node.Children = append(node.Children, otherShardsNode(list[i:]))
+			break
+		}
+		node.Children = append(node.Children, &tracing.TreeNode{
+			Raw: tracing.RawSpan{
+				Name:   "shard",
+				Labels: labels.New("shard_id", s.id),
+				Fields: s.fields(),
+			},
+			Children: s.iterators,
+		})
+	}
+	root.Children = append(root.Children, node)
+	return root
+}
+
+// otherShardsNode returns a node summarizing shards that aren't broken down.
+func otherShardsNode(list []*shardSpans) *tracing.TreeNode {
+	var other shardSpans
+	for _, s := range list {
+		other.iterators = append(other.iterators, s.iterators...)
+		for _, n := range s.iterators {
+			other.merge(n.Raw.Fields)
+		}
+	}
+
+	f := other.fields()
+	f.Merge(fields.New(fields.Int64("shards", int64(len(list)))))
+	return &tracing.TreeNode{Raw: tracing.RawSpan{Name: "other shards", Fields: f}}
+}
+
+// removeIteratorSpans removes the create_iterator spans of shards below n
+// and adds them to shards.
+func removeIteratorSpans(n *tracing.TreeNode, shards map[string]*shardSpans) {
+	children := n.Children[:0]
+	for _, c := range n.Children {
+		if c.Raw.Name == "create_iterator" {
+			if id := spanLabel(c, "shard_id"); id != "" {
+				s := shards[id]
+				if s == nil {
+					s = &shardSpans{id: id}
+					shards[id] = s
+				}
+				s.add(c)
+				continue
+			}
+		}
+		removeIteratorSpans(c, shards)
+		children = append(children, c)
+	}
+	n.Children = children
+}
+
+// spanLabel returns the value of the label key of the span of n.
+func spanLabel(n *tracing.TreeNode, key string) string {
+	for _, l := range n.Raw.Labels {
+		if l.Key == key {
+			return l.Value
+		}
+	}
+	return ""
+}
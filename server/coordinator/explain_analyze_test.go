@@ -0,0 +1,80 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing/fields"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing/labels"
+)
+
+func TestExplainAnalyzeTree(t *testing.T) {
+	iterator := func(shardID int, d time.Duration) *tracing.TreeNode {
+		return &tracing.TreeNode{Raw: tracing.RawSpan{
+			Name:   "create_iterator",
+			Labels: labels.New("shard_id", fmt.Sprint(shardID), "measurement", "cpu"),
+			Fields: fields.New(
+				fields.Int64("points_read", 10),
+				fields.Int64("float_blocks_decoded", 2),
+				fields.Duration("planning_time", d),
+				fields.Duration("read_time", d),
+			),
+		}}
+	}
+
+	cursor := &tracing.TreeNode{Raw: tracing.RawSpan{Name: "build_cursor"}}
+	for id := 1; id <= explainAnalyzeMaxShards+5; id++ {
+		cursor.Children = append(cursor.Children, iterator(id, time.Duration(id)*time.Millisecond))
+	}
+	// A second iterator on the fastest shard.
+	cursor.Children = append(cursor.Children, iterator(1, time.Millisecond))
+	root := &tracing.TreeNode{Raw: tracing.RawSpan{Name: "select"}, Children: []*tracing.TreeNode{cursor}}
+
+	tree := explainAnalyzeTree(root)
+	if len(cursor.Children) != 0 {
+		t.Fatalf("iterator spans left under build_cursor: %d", len(cursor.Children))
+	}
+	if len(tree.Children) != 2 || tree.Children[1].Raw.Name != "shards" {
+		t.Fatalf("unexpected root children: %d", len(tree.Children))
+	}
+
+	shards := tree.Children[1].Children
+	if len(shards) != explainAnalyzeMaxShards+1 {
+		t.Fatalf("unexpected shard nodes: %d", len(shards))
+	}
+	if id := spanLabel(shards[0], "shard_id"); id != fmt.Sprint(explainAnalyzeMaxShards+5) {
+		t.Fatalf("slowest shard not first: %s", id)
+	}
+
+	// The five fastest shards, including both iterators of shard 1, are
+	// summarized in the last node.
+	other := shards[explainAnalyzeMaxShards]
+	if other.Raw.Name != "other shards" || len(other.Children) != 0 {
+		t.Fatalf("unexpected summary node: %s", other.Raw.Name)
+	}
+	exp := map[string]interface{}{
+		"shards":         int64(5),
+		"iterators":      int64(6),
+		"points_read":    int64(60),
+		"blocks_decoded": int64(12),
+		"planning_time":  16 * time.Millisecond,
+		"read_time":      16 * time.Millisecond,
+		"total_time":     32 * time.Millisecond,
+	}
+	for _, f := range other.Raw.Fields {
+		if v, ok := exp[f.Key()]; !ok || v != f.Value() {
+			t.Errorf("unexpected field %s", f)
+		}
+		delete(exp, f.Key())
+	}
+	if len(exp) != 0 {
+		t.Errorf("missing fields: %v", exp)
+	}
+
+	if s := tree.String(); !strings.Contains(s, "shard_id: 25") || strings.Contains(s, "shard_id: 1\n") {
+		t.Fatalf("unexpected rendering:\n%s", s)
+	}
+}
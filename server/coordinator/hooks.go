@@ -0,0 +1,43 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// StatementHook is called around the execution of every statement, e.g. to
+// enforce a site policy or to rewrite deprecated statements.
+type StatementHook interface {
+	// Before is called before stmt is executed. It returns the statement to
+	// execute instead, or stmt itself. An error aborts the execution and is
+	// returned as the error of the statement.
+	Before(ctx *query.ExecutionContext, stmt cnosql.Statement) (cnosql.Statement, error)
+
+	// After is called once the statement returned by Before was executed,
+	// with the error it returned and how long it ran.
+	After(ctx *query.ExecutionContext, stmt cnosql.Statement, err error, d time.Duration)
+}
+
+// beforeHooks calls Before on the statement hooks in order, each with the
+// statement returned by the previous one. It returns the statement to
+// execute and the hooks After must be called on.
+func (e *StatementExecutor) beforeHooks(ctx *query.ExecutionContext, stmt cnosql.Statement) (cnosql.Statement, []StatementHook, error) {
+	for i, h := range e.StatementHooks {
+		s, err := h.Before(ctx, stmt)
+		if err != nil {
+			return stmt, e.StatementHooks[:i], err
+		} else if s != nil {
+			stmt = s
+		}
+	}
+	return stmt, e.StatementHooks, nil
+}
+
+// afterHooks calls After on hooks in reverse order.
+func afterHooks(ctx *query.ExecutionContext, hooks []StatementHook, stmt cnosql.Statement, err error, d time.Duration) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].After(ctx, stmt, err, d)
+	}
+}
@@ -0,0 +1,93 @@
+package coordinator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+func TestStatementExecutor_ExecuteStatement_Hooks(t *testing.T) {
+	var after []string
+	e := &StatementExecutor{
+		ShardMapper: &endlessShardMapper{cost: query.IteratorCost{NumShards: 1, NumSeries: 1}},
+		StatementHooks: []StatementHook{
+			&blockStatementHook{typ: reflect.TypeOf(&cnosql.DropDatabaseStatement{})},
+			&renameMeasurementHook{from: "old_cpu", to: "cpu"},
+			&recordingHook{after: &after},
+		},
+	}
+	opt := query.ExecutionOptions{Database: "db0", UserAdmin: true}
+
+	// The statement is refused before reaching the meta client, which
+	// would panic, and the later hooks are not called.
+	if _, err := executeStatement(e, `DROP DATABASE db0`, opt); err == nil || err.Error() != "DropDatabaseStatement is not allowed" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(after) != 0 {
+		t.Fatalf("unexpected hooks called: %q", after)
+	}
+
+	// The rewritten statement is executed instead.
+	results, err := executeStatement(e, `EXPLAIN SELECT value FROM db0.rp0.old_cpu`, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan := queryPlan(results); indexOf(plan, "SERIES OF cpu: 1") < 0 {
+		t.Fatalf("unexpected plan: %q", plan)
+	}
+	if exp := "EXPLAIN SELECT value FROM db0.rp0.cpu: <nil>"; len(after) != 1 || after[0] != exp {
+		t.Fatalf("unexpected hooks called: %q", after)
+	}
+}
+
+// blockStatementHook refuses statements of type typ.
+type blockStatementHook struct {
+	typ reflect.Type
+}
+
+func (h *blockStatementHook) Before(ctx *query.ExecutionContext, stmt cnosql.Statement) (cnosql.Statement, error) {
+	if reflect.TypeOf(stmt) == h.typ {
+		return nil, fmt.Errorf("%s is not allowed", h.typ.Elem().Name())
+	}
+	return stmt, nil
+}
+
+func (*blockStatementHook) After(*query.ExecutionContext, cnosql.Statement, error, time.Duration) {}
+
+// renameMeasurementHook rewrites the measurement from to to in statements.
+type renameMeasurementHook struct {
+	from, to string
+}
+
+func (h *renameMeasurementHook) Before(ctx *query.ExecutionContext, stmt cnosql.Statement) (cnosql.Statement, error) {
+	// Parse the statement again so the original is left untouched.
+	stmt, err := cnosql.ParseStatement(stmt.String())
+	if err != nil {
+		return nil, err
+	}
+	cnosql.WalkFunc(stmt, func(n cnosql.Node) {
+		if m, ok := n.(*cnosql.Measurement); ok && m.Name == h.from {
+			m.Name = h.to
+		}
+	})
+	return stmt, nil
+}
+
+func (*renameMeasurementHook) After(*query.ExecutionContext, cnosql.Statement, error, time.Duration) {
+}
+
+// recordingHook records the statements it is called after.
+type recordingHook struct {
+	after *[]string
+}
+
+func (h *recordingHook) Before(ctx *query.ExecutionContext, stmt cnosql.Statement) (cnosql.Statement, error) {
+	return stmt, nil
+}
+
+func (h *recordingHook) After(ctx *query.ExecutionContext, stmt cnosql.Statement, err error, d time.Duration) {
+	*h.after = append(*h.after, fmt.Sprintf("%s: %v", stmt, err))
+}
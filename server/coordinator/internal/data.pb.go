@@ -226,6 +226,9 @@ type CreateIteratorRequest struct {
 	Database             []byte   `protobuf:"bytes,3,req,name=Database" json:"Database,omitempty"`
 	RetentionPolicy      []byte   `protobuf:"bytes,4,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
 	MeasurementName      []byte   `protobuf:"bytes,5,req,name=MeasurementName" json:"MeasurementName,omitempty"`
+	NodeID               *uint64  `protobuf:"varint,6,opt,name=NodeID" json:"NodeID,omitempty"`
+	QueryID              *uint64  `protobuf:"varint,7,opt,name=QueryID" json:"QueryID,omitempty"`
+	User                 *string  `protobuf:"bytes,8,opt,name=User" json:"User,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -290,6 +293,27 @@ func (m *CreateIteratorRequest) GetMeasurementName() []byte {
 	return nil
 }
 
+func (m *CreateIteratorRequest) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *CreateIteratorRequest) GetQueryID() uint64 {
+	if m != nil && m.QueryID != nil {
+		return *m.QueryID
+	}
+	return 0
+}
+
+func (m *CreateIteratorRequest) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
 type CreateIteratorResponse struct {
 	Err                  *string  `protobuf:"bytes,1,opt,name=Err" json:"Err,omitempty"`
 	DataType             *int32   `protobuf:"varint,2,opt,name=DataType" json:"DataType,omitempty"`
@@ -452,6 +476,90 @@ func (m *FieldDimensionsResponse) GetErr() string {
 	return ""
 }
 
+type KillQueryRequest struct {
+	NodeID               *uint64  `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
+	QueryID              *uint64  `protobuf:"varint,2,req,name=QueryID" json:"QueryID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillQueryRequest) Reset()         { *m = KillQueryRequest{} }
+func (m *KillQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*KillQueryRequest) ProtoMessage()    {}
+func (*KillQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7438786364df21e1, []int{8}
+}
+func (m *KillQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillQueryRequest.Unmarshal(m, b)
+}
+func (m *KillQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *KillQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillQueryRequest.Merge(m, src)
+}
+func (m *KillQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_KillQueryRequest.Size(m)
+}
+func (m *KillQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillQueryRequest proto.InternalMessageInfo
+
+func (m *KillQueryRequest) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *KillQueryRequest) GetQueryID() uint64 {
+	if m != nil && m.QueryID != nil {
+		return *m.QueryID
+	}
+	return 0
+}
+
+type KillQueryResponse struct {
+	Err                  *string  `protobuf:"bytes,1,opt,name=Err" json:"Err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillQueryResponse) Reset()         { *m = KillQueryResponse{} }
+func (m *KillQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*KillQueryResponse) ProtoMessage()    {}
+func (*KillQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7438786364df21e1, []int{9}
+}
+func (m *KillQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillQueryResponse.Unmarshal(m, b)
+}
+func (m *KillQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *KillQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillQueryResponse.Merge(m, src)
+}
+func (m *KillQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_KillQueryResponse.Size(m)
+}
+func (m *KillQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillQueryResponse proto.InternalMessageInfo
+
+func (m *KillQueryResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*WriteShardRequest)(nil), "internal.WriteShardRequest")
 	proto.RegisterType((*WriteShardResponse)(nil), "internal.WriteShardResponse")
@@ -461,36 +569,42 @@ func init() {
 	proto.RegisterType((*CreateIteratorResponse)(nil), "internal.CreateIteratorResponse")
 	proto.RegisterType((*FieldDimensionsRequest)(nil), "internal.FieldDimensionsRequest")
 	proto.RegisterType((*FieldDimensionsResponse)(nil), "internal.FieldDimensionsResponse")
+	proto.RegisterType((*KillQueryRequest)(nil), "internal.KillQueryRequest")
+	proto.RegisterType((*KillQueryResponse)(nil), "internal.KillQueryResponse")
 }
 
 func init() { proto.RegisterFile("internal/data.proto", fileDescriptor_7438786364df21e1) }
 
 var fileDescriptor_7438786364df21e1 = []byte{
-	// 406 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xc1, 0x6e, 0xd4, 0x30,
-	0x10, 0x55, 0x36, 0x9b, 0xb2, 0x19, 0x56, 0xa2, 0x18, 0x91, 0x5a, 0x15, 0x42, 0x51, 0x4e, 0x39,
-	0xc1, 0x3f, 0xd0, 0x2d, 0xa2, 0x87, 0x86, 0xca, 0x8b, 0xe0, 0x6c, 0x36, 0x23, 0xb0, 0xb4, 0x6b,
-	0x07, 0x7b, 0x56, 0x6a, 0xbf, 0x81, 0xef, 0xe1, 0xff, 0x50, 0xa6, 0x71, 0x1a, 0xd2, 0x03, 0x88,
-	0xdb, 0xbc, 0xe7, 0xd1, 0xe8, 0xbd, 0x97, 0x17, 0x78, 0x61, 0x2c, 0xa1, 0xb7, 0x7a, 0xff, 0xb6,
-	0xd5, 0xa4, 0xdf, 0x74, 0xde, 0x91, 0x13, 0xab, 0x48, 0x56, 0x3f, 0x13, 0x78, 0xfe, 0xc5, 0x1b,
-	0xc2, 0xed, 0x77, 0xed, 0x5b, 0x85, 0x3f, 0x8e, 0x18, 0x48, 0x48, 0x78, 0xc2, 0xf8, 0x6a, 0x23,
-	0x93, 0x72, 0x51, 0x2f, 0x55, 0x84, 0xa2, 0x80, 0x93, 0x1b, 0x67, 0x2c, 0x05, 0xb9, 0x28, 0xd3,
-	0x7a, 0xad, 0x06, 0x24, 0xce, 0x61, 0xb5, 0xd1, 0xa4, 0xbf, 0xea, 0x80, 0x32, 0x2d, 0x93, 0x3a,
-	0x57, 0x23, 0x16, 0x35, 0x3c, 0x53, 0x48, 0x68, 0xc9, 0x38, 0x7b, 0xe3, 0xf6, 0x66, 0x77, 0x27,
-	0x97, 0xbc, 0x32, 0xa7, 0xab, 0x77, 0x20, 0xa6, 0x62, 0x42, 0xe7, 0x6c, 0x40, 0x21, 0x60, 0x79,
-	0xe1, 0x5a, 0x64, 0x29, 0x99, 0xe2, 0xb9, 0x57, 0x78, 0x8d, 0x21, 0xe8, 0x6f, 0x28, 0x17, 0x7c,
-	0x2b, 0xc2, 0x6a, 0x0b, 0x67, 0x97, 0xb7, 0xb8, 0x3b, 0x12, 0x6e, 0x49, 0x13, 0x1e, 0xd0, 0x52,
-	0xb4, 0xf5, 0x0a, 0xf2, 0x91, 0xe3, 0x6b, 0xb9, 0x7a, 0x20, 0xfe, 0xb0, 0xb0, 0xe0, 0xc7, 0x11,
-	0x57, 0x1f, 0x40, 0x3e, 0x3e, 0xfa, 0x5f, 0xf2, 0x7e, 0x25, 0xf0, 0xf2, 0xc2, 0xa3, 0x26, 0xbc,
-	0x22, 0xf4, 0x9a, 0x9c, 0x8f, 0xea, 0xce, 0x61, 0x35, 0xa4, 0x1c, 0x64, 0x52, 0xa6, 0xf5, 0x52,
-	0x8d, 0x58, 0x9c, 0x42, 0xfa, 0xb1, 0x23, 0x96, 0xb5, 0x56, 0xfd, 0x38, 0x0b, 0xbc, 0xa7, 0xff,
-	0x12, 0x78, 0xbf, 0x32, 0xa7, 0xfb, 0xcd, 0x6b, 0xd4, 0xe1, 0xe8, 0xd9, 0x52, 0xa3, 0x0f, 0x28,
-	0xb3, 0xfb, 0xcd, 0x19, 0x5d, 0xdd, 0x42, 0x31, 0x97, 0x3d, 0xf8, 0x3f, 0x85, 0xf4, 0xd2, 0x7b,
-	0x99, 0xb0, 0xcf, 0x7e, 0x8c, 0xda, 0x3e, 0xdd, 0x75, 0xf7, 0xf6, 0x33, 0x35, 0x62, 0xae, 0x16,
-	0x7a, 0x83, 0xa1, 0xe1, 0x9e, 0x64, 0x2a, 0xc2, 0xb1, 0x5a, 0x0d, 0xb7, 0x23, 0x1b, 0xaa, 0xd5,
-	0x54, 0x9f, 0xa1, 0x78, 0x6f, 0x70, 0xdf, 0x6e, 0xcc, 0x01, 0x6d, 0x30, 0xce, 0x86, 0x7f, 0x49,
-	0xac, 0x84, 0xa7, 0x13, 0x0b, 0x43, 0x72, 0x53, 0xaa, 0xda, 0xc1, 0xd9, 0xa3, 0xbb, 0x83, 0xa5,
-	0x02, 0x4e, 0xf8, 0x29, 0xf0, 0x47, 0x5d, 0xab, 0x01, 0x89, 0xd7, 0x00, 0x0f, 0xdb, 0xfc, 0x07,
-	0xe4, 0x6a, 0xc2, 0xc4, 0x28, 0xd2, 0x31, 0x8a, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe5, 0x27,
-	0x44, 0xac, 0x7f, 0x03, 0x00, 0x00,
+	// 469 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0xdd, 0x8e, 0xd3, 0x30,
+	0x10, 0x85, 0x95, 0xbf, 0x6e, 0x3b, 0x54, 0xa2, 0x6b, 0x44, 0xd7, 0x5a, 0x21, 0x14, 0x45, 0x42,
+	0xca, 0x15, 0xbc, 0x03, 0x9b, 0x45, 0x54, 0x68, 0xc3, 0xe2, 0xf2, 0x73, 0x6d, 0x9a, 0x11, 0x58,
+	0x4a, 0xe3, 0x62, 0xbb, 0xd2, 0xf6, 0x19, 0x78, 0x64, 0x6e, 0x50, 0xa6, 0x71, 0x1a, 0xb2, 0x20,
+	0x10, 0x77, 0x3e, 0xc7, 0xa3, 0xd1, 0x9c, 0xcf, 0x63, 0x78, 0xa4, 0x1a, 0x87, 0xa6, 0x91, 0xf5,
+	0x8b, 0x4a, 0x3a, 0xf9, 0x7c, 0x67, 0xb4, 0xd3, 0x6c, 0xea, 0xcd, 0xec, 0x7b, 0x00, 0xe7, 0x9f,
+	0x8c, 0x72, 0xb8, 0xfe, 0x2a, 0x4d, 0x25, 0xf0, 0xdb, 0x1e, 0xad, 0x63, 0x1c, 0xce, 0x48, 0xaf,
+	0x0a, 0x1e, 0xa4, 0x61, 0x1e, 0x0b, 0x2f, 0xd9, 0x12, 0x26, 0xb7, 0x5a, 0x35, 0xce, 0xf2, 0x30,
+	0x8d, 0xf2, 0xb9, 0xe8, 0x14, 0xbb, 0x84, 0x69, 0x21, 0x9d, 0xfc, 0x2c, 0x2d, 0xf2, 0x28, 0x0d,
+	0xf2, 0x99, 0xe8, 0x35, 0xcb, 0xe1, 0xa1, 0x40, 0x87, 0x8d, 0x53, 0xba, 0xb9, 0xd5, 0xb5, 0xda,
+	0x1c, 0x78, 0x4c, 0x25, 0x63, 0x3b, 0x7b, 0x09, 0x6c, 0x38, 0x8c, 0xdd, 0xe9, 0xc6, 0x22, 0x63,
+	0x10, 0x5f, 0xe9, 0x0a, 0x69, 0x94, 0x44, 0xd0, 0xb9, 0x9d, 0xf0, 0x06, 0xad, 0x95, 0x5f, 0x90,
+	0x87, 0xd4, 0xcb, 0xcb, 0x6c, 0x0d, 0x17, 0xd7, 0x77, 0xb8, 0xd9, 0x3b, 0x5c, 0x3b, 0xe9, 0x70,
+	0x8b, 0x8d, 0xf3, 0xb1, 0x9e, 0xc0, 0xac, 0xf7, 0xa8, 0xdb, 0x4c, 0x9c, 0x8c, 0x5f, 0x22, 0x84,
+	0x74, 0xd9, 0xeb, 0xec, 0x35, 0xf0, 0xfb, 0x4d, 0xff, 0x6b, 0xbc, 0x1f, 0x01, 0x3c, 0xbe, 0x32,
+	0x28, 0x1d, 0xae, 0x1c, 0x1a, 0xe9, 0xb4, 0xf1, 0xd3, 0x5d, 0xc2, 0xb4, 0xa3, 0x6c, 0x79, 0x90,
+	0x46, 0x79, 0x2c, 0x7a, 0xcd, 0x16, 0x10, 0xbd, 0xdd, 0x39, 0x1a, 0x6b, 0x2e, 0xda, 0xe3, 0x08,
+	0x78, 0x6b, 0xff, 0x05, 0x78, 0x5b, 0x32, 0xb6, 0xdb, 0xca, 0x1b, 0x94, 0x76, 0x6f, 0x28, 0x52,
+	0x29, 0xb7, 0xc8, 0x93, 0x63, 0xe5, 0xc8, 0x6e, 0x1f, 0xbe, 0xd4, 0x15, 0xae, 0x0a, 0x3e, 0x49,
+	0x83, 0x3c, 0x16, 0x9d, 0x6a, 0x93, 0xbe, 0xdb, 0xa3, 0x39, 0xac, 0x0a, 0x7e, 0x46, 0x17, 0x5e,
+	0xb6, 0x5c, 0x3e, 0x58, 0x34, 0x7c, 0x4a, 0x00, 0xe8, 0x9c, 0xdd, 0xc1, 0x72, 0x1c, 0xbe, 0xa3,
+	0xb8, 0x80, 0xe8, 0xda, 0x18, 0x1e, 0x50, 0x71, 0x7b, 0xf4, 0x09, 0xdf, 0x1f, 0x76, 0x47, 0x88,
+	0x89, 0xe8, 0x35, 0x2d, 0x28, 0x1a, 0x85, 0xb6, 0xa4, 0x6d, 0x4b, 0x84, 0x97, 0xfd, 0x82, 0x96,
+	0xb4, 0x63, 0x49, 0xb7, 0xa0, 0x65, 0xf6, 0x11, 0x96, 0xaf, 0x14, 0xd6, 0x55, 0xa1, 0xb6, 0xd8,
+	0x58, 0xa5, 0x1b, 0xfb, 0x2f, 0xdc, 0x53, 0x78, 0x30, 0x00, 0xd1, 0xf1, 0x1f, 0x5a, 0xd9, 0x06,
+	0x2e, 0xee, 0xf5, 0xed, 0x22, 0x2d, 0x61, 0x42, 0x57, 0x96, 0x56, 0x63, 0x2e, 0x3a, 0xc5, 0x9e,
+	0x02, 0x9c, 0xaa, 0xe9, 0x1f, 0xcd, 0xc4, 0xc0, 0xf1, 0x28, 0xa2, 0x1e, 0x45, 0x56, 0xc0, 0xe2,
+	0x8d, 0xaa, 0x6b, 0x22, 0xeb, 0xc7, 0x3e, 0x3d, 0xc8, 0xf1, 0x8b, 0xfe, 0xe6, 0x41, 0xc2, 0xe3,
+	0xdf, 0xed, 0x64, 0xf6, 0x0c, 0xce, 0x07, 0x5d, 0xfe, 0xc4, 0xfd, 0xe7, 0x00, 0xe3, 0x1d, 0x13,
+	0x7f, 0x32, 0x04, 0x00, 0x00,
 }
@@ -9,35 +9,60 @@ import (
 
 // MetaClient is an interface for accessing meta data.
 type MetaClient interface {
-	CreateContinuousQuery(database, name, query string) error
+	AlterContinuousQuery(database, name, query string) error
+	CreateContinuousQuery(database, name, query, owner string) error
 	CreateDatabase(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicy(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateRole(name string) error
 	CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
-	CreateSubscription(database, rp, name, mode string, destinations []string) error
+	CreateShardGroup(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
+	CreateSubscription(database, rp, name, mode string, destinations []string, weights []int, filter string, tls meta.SubscriptionTLSInfo) error
+	CreateToken(username, database string, p cnosql.Privilege, expiry time.Time) (string, *meta.TokenInfo, error)
 	CreateUser(name, password string, admin bool) (meta.User, error)
 	Database(name string) *meta.DatabaseInfo
 	Databases() []meta.DatabaseInfo
 	DataNode(id uint64) (*meta.NodeInfo, error)
 	DataNodes() ([]meta.NodeInfo, error)
 	DeleteDataNode(id uint64) error
+	DeleteShardGroup(database, policy string, id uint64) error
+	PurgeShardGroup(database, policy string, id uint64) error
 	MetaNodes() ([]meta.NodeInfo, error)
+	PrecreateShardGroups(from, to time.Time) error
 	DeleteMetaNode(id uint64) error
 	DropShard(id uint64) error
 	DropContinuousQuery(database, name string) error
 	DropDatabase(name string) error
 	DropRetentionPolicy(database, name string) error
+	DropRole(name string) error
 	DropSubscription(database, rp, name string) error
+	DropToken(id string) error
 	DropUser(name string) error
 	ShardGroupsByTimeRange(database, rp string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
 	SetAdminPrivilege(username string, admin bool) error
 	SetDefaultRetentionPolicy(database, name string) error
+	SetMeasurementPrivilege(username string, mp meta.MeasurementPrivilege) error
 	SetPrivilege(username, database string, p cnosql.Privilege) error
+	SetPrivilegeOnRP(username, database, rp string, p cnosql.Privilege) error
+	SetUserDefaultDatabase(username, database, rp string) error
+	SetUserLocked(username string, locked bool) error
 	ShardsByTimeRange(sources cnosql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error)
+	GrantRole(username, role string) error
 	RetentionPolicy(database, name string) (rp *meta.RetentionPolicyInfo, err error)
+	RevokeRole(username, role string) error
+	RolePrivileges(role string) (map[string]cnosql.Privilege, error)
+	Roles() []meta.RoleInfo
+	SetRolePrivilege(role, database string, p cnosql.Privilege) error
+	Tokens() []meta.TokenInfo
 	TruncateShardGroups(t time.Time) error
+	UndeleteShardGroup(database, policy string, id uint64) error
+	UpdateDatabase(name string, du *meta.DatabaseUpdate) error
 	UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUser(name, password string) error
+	UpdateUserQueryLimits(username string, u *meta.UserQueryLimitsUpdate) error
+	User(name string) (meta.User, error)
+	UserMeasurementPrivileges(username string) ([]meta.MeasurementPrivilege, error)
 	UserPrivilege(username, database string) (*cnosql.Privilege, error)
+	UserPrivilegeOnRP(username, database, rp string) (*cnosql.Privilege, error)
 	UserPrivileges(username string) (map[string]cnosql.Privilege, error)
 	Users() []meta.UserInfo
 }
@@ -0,0 +1,77 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// Names of the deprecation notices raised by the StatementExecutor. Any of
+// them can be turned into a hard error with the escalate-notices setting to
+// stage the removal of a deprecated form.
+const (
+	// NoticeDropSeriesTime is raised for DROP SERIES with a time condition,
+	// which is executed as the equivalent DELETE.
+	NoticeDropSeriesTime = "drop-series-time"
+)
+
+// ErrDeprecated is returned for a deprecated form whose notice has been
+// escalated to an error.
+type ErrDeprecated struct {
+	Notice      string
+	Text        string
+	Replacement string
+}
+
+func (e ErrDeprecated) Error() string {
+	return fmt.Sprintf("%s: use %s instead (notice %s is escalated to an error)", e.Text, e.Replacement, e.Notice)
+}
+
+// deprecated returns a deprecation message for the named notice, or an
+// ErrDeprecated if the notice has been escalated. A session only receives
+// the message once and nil afterwards, statements outside of a tracked
+// session receive it every time.
+func (e *StatementExecutor) deprecated(ctx *query.ExecutionContext, notice, text, replacement string) (*query.Message, error) {
+	for _, name := range e.EscalatedNotices {
+		if name == notice {
+			return nil, ErrDeprecated{Notice: notice, Text: text, Replacement: replacement}
+		}
+	}
+	if ctx.SessionID != 0 && !e.notices.first(notice, ctx.SessionID) {
+		return nil, nil
+	}
+	return query.DeprecationWarning(text, replacement), nil
+}
+
+// maxSentNotices bounds the number of notices remembered as sent.
+const maxSentNotices = 10000
+
+// sentNotices remembers the notices sent to each session.
+type sentNotices struct {
+	mu   sync.Mutex
+	sent map[sentNotice]struct{}
+}
+
+type sentNotice struct {
+	notice  string
+	session uint64
+}
+
+// first records notice as sent to session and returns whether it wasn't
+// before. Past maxSentNotices everything is forgotten, so sessions may
+// receive a notice again.
+func (n *sentNotices) first(notice string, session uint64) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	k := sentNotice{notice: notice, session: session}
+	if _, ok := n.sent[k]; ok {
+		return false
+	}
+	if n.sent == nil || len(n.sent) >= maxSentNotices {
+		n.sent = make(map[sentNotice]struct{})
+	}
+	n.sent[k] = struct{}{}
+	return true
+}
@@ -0,0 +1,227 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/common/monitor/diagnostics"
+)
+
+// DefaultOperationLockTimeout is how long an operation waits for a
+// conflicting operation to finish before giving up.
+const DefaultOperationLockTimeout = 10 * time.Second
+
+// ErrConflictingOperation is returned when an operation could not acquire its
+// lock because a conflicting operation is still in progress.
+type ErrConflictingOperation struct {
+	Desc string
+}
+
+func (e ErrConflictingOperation) Error() string {
+	return fmt.Sprintf("conflicting operation in progress: %s", e.Desc)
+}
+
+// OperationScope identifies the objects a destructive operation works on. An
+// empty RetentionPolicy covers every retention policy of Database and a zero
+// ShardID covers every shard of the retention policy.
+type OperationScope struct {
+	Database        string
+	RetentionPolicy string
+	ShardID         uint64
+}
+
+// overlaps returns whether the two scopes share any shard.
+func (s OperationScope) overlaps(other OperationScope) bool {
+	if s.ShardID != 0 && other.ShardID != 0 {
+		return s.ShardID == other.ShardID
+	}
+	if s.Database != other.Database {
+		return false
+	}
+	return s.RetentionPolicy == "" || other.RetentionPolicy == "" || s.RetentionPolicy == other.RetentionPolicy
+}
+
+type operation struct {
+	scope OperationScope
+	desc  string
+	start time.Time
+	done  chan struct{}
+}
+
+// OperationInfo describes an operation holding or waiting for a lock.
+type OperationInfo struct {
+	OperationScope
+	Desc string
+
+	// When the operation acquired its lock, or started waiting for it.
+	Since time.Time
+
+	// Whether the operation waits for a conflicting operation to finish.
+	Waiting bool
+}
+
+// OperationLocks serializes destructive operations, such as drops, deletes,
+// shard removal and backup or restore, that work on overlapping objects.
+type OperationLocks struct {
+	mu      sync.Mutex
+	nextID  uint64
+	ops     map[uint64]*operation
+	waiting map[uint64]*operation
+
+	// Timeout is how long Acquire waits for conflicting operations.
+	// Zero fails immediately on conflict.
+	Timeout time.Duration
+
+	// MetaClient resolves the database and retention policy of a shard.
+	MetaClient interface {
+		Databases() []meta.DatabaseInfo
+	}
+}
+
+// NewOperationLocks returns a new instance of OperationLocks.
+func NewOperationLocks() *OperationLocks {
+	return &OperationLocks{
+		ops:     make(map[uint64]*operation),
+		waiting: make(map[uint64]*operation),
+		Timeout: DefaultOperationLockTimeout,
+	}
+}
+
+// Acquire locks scope for the operation described by desc, waiting up to
+// Timeout for conflicting operations to finish. The returned function
+// releases the lock and must be called, typically deferred, exactly once.
+func (l *OperationLocks) Acquire(scope OperationScope, desc string) (release func(), err error) {
+	var (
+		timeout <-chan time.Time
+		waitID  uint64
+		waiting bool
+	)
+	for {
+		l.mu.Lock()
+		conflict := l.conflict(scope)
+		if conflict == nil {
+			if waiting {
+				delete(l.waiting, waitID)
+			}
+			id := l.nextID
+			l.nextID++
+			op := &operation{scope: scope, desc: desc, start: time.Now(), done: make(chan struct{})}
+			l.ops[id] = op
+			l.mu.Unlock()
+
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					l.mu.Lock()
+					delete(l.ops, id)
+					l.mu.Unlock()
+					close(op.done)
+				})
+			}, nil
+		}
+		if l.Timeout <= 0 {
+			l.mu.Unlock()
+			return nil, ErrConflictingOperation{Desc: conflict.desc}
+		}
+		if !waiting {
+			waitID, waiting = l.nextID, true
+			l.nextID++
+			l.waiting[waitID] = &operation{scope: scope, desc: desc, start: time.Now()}
+		}
+		l.mu.Unlock()
+
+		if timeout == nil {
+			timer := time.NewTimer(l.Timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case <-conflict.done:
+		case <-timeout:
+			l.mu.Lock()
+			delete(l.waiting, waitID)
+			l.mu.Unlock()
+			return nil, ErrConflictingOperation{Desc: conflict.desc}
+		}
+	}
+}
+
+// AcquireShard locks a single shard, including its database and retention
+// policy if they are known to the meta store. See Acquire.
+func (l *OperationLocks) AcquireShard(shardID uint64, desc string) (release func(), err error) {
+	scope := OperationScope{ShardID: shardID}
+	if l.MetaClient != nil {
+		scope.Database, scope.RetentionPolicy = shardOwner(l.MetaClient.Databases(), shardID)
+	}
+	return l.Acquire(scope, desc)
+}
+
+// conflict returns an operation in progress overlapping scope, if any.
+func (l *OperationLocks) conflict(scope OperationScope) *operation {
+	for _, op := range l.ops {
+		if op.scope.overlaps(scope) {
+			return op
+		}
+	}
+	return nil
+}
+
+// Operations returns the operations holding a lock, oldest first, followed
+// by the operations waiting for one.
+func (l *OperationLocks) Operations() []OperationInfo {
+	l.mu.Lock()
+	a := make([]OperationInfo, 0, len(l.ops)+len(l.waiting))
+	for _, op := range l.ops {
+		a = append(a, OperationInfo{OperationScope: op.scope, Desc: op.desc, Since: op.start})
+	}
+	for _, op := range l.waiting {
+		a = append(a, OperationInfo{OperationScope: op.scope, Desc: op.desc, Since: op.start, Waiting: true})
+	}
+	l.mu.Unlock()
+
+	sort.Slice(a, func(i, j int) bool {
+		if a[i].Waiting != a[j].Waiting {
+			return !a[i].Waiting
+		}
+		return a[i].Since.Before(a[j].Since)
+	})
+	return a
+}
+
+// Diagnostics returns the operations holding or waiting for a lock.
+func (l *OperationLocks) Diagnostics() (*diagnostics.Diagnostics, error) {
+	d := diagnostics.NewDiagnostics([]string{"database", "rp", "shard", "operation", "state", "since"})
+	for _, op := range l.Operations() {
+		d.AddRow([]interface{}{op.Database, op.RetentionPolicy, op.ShardID, op.Desc, op.State(), op.Since.UTC().Format(time.RFC3339)})
+	}
+	return d, nil
+}
+
+// State returns "waiting" if the operation waits for its lock, or "running".
+func (op OperationInfo) State() string {
+	if op.Waiting {
+		return "waiting"
+	}
+	return "running"
+}
+
+// shardOwner returns the database and retention policy of a shard, or empty
+// strings if the shard is unknown.
+func shardOwner(dis []meta.DatabaseInfo, shardID uint64) (database, rp string) {
+	for _, di := range dis {
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					if si.ID == shardID {
+						return di.Name, rpi.Name
+					}
+				}
+			}
+		}
+	}
+	return "", ""
+}
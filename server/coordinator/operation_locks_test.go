@@ -0,0 +1,273 @@
+package coordinator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+func TestOperationScope_Overlaps(t *testing.T) {
+	db0 := OperationScope{Database: "db0"}
+	rp0 := OperationScope{Database: "db0", RetentionPolicy: "rp0"}
+	rp1 := OperationScope{Database: "db0", RetentionPolicy: "rp1"}
+	shard1 := OperationScope{Database: "db0", RetentionPolicy: "rp0", ShardID: 1}
+	shard2 := OperationScope{Database: "db0", RetentionPolicy: "rp0", ShardID: 2}
+	unknown := OperationScope{ShardID: 3}
+
+	for _, tt := range []struct {
+		a, b OperationScope
+		exp  bool
+	}{
+		{a: db0, b: db0, exp: true},
+		{a: db0, b: OperationScope{Database: "db1"}},
+		{a: db0, b: rp0, exp: true},
+		{a: rp0, b: rp1},
+		{a: rp0, b: shard1, exp: true},
+		{a: rp1, b: shard1},
+		{a: db0, b: shard1, exp: true},
+		{a: shard1, b: shard2},
+		{a: shard1, b: shard1, exp: true},
+		// Shards unknown to the meta store only conflict with themselves.
+		{a: db0, b: unknown},
+		{a: unknown, b: OperationScope{ShardID: 3}, exp: true},
+	} {
+		if got := tt.a.overlaps(tt.b); got != tt.exp {
+			t.Fatalf("%+v overlaps %+v: got %v, exp %v", tt.a, tt.b, got, tt.exp)
+		} else if got := tt.b.overlaps(tt.a); got != tt.exp {
+			t.Fatalf("%+v overlaps %+v: got %v, exp %v", tt.b, tt.a, got, tt.exp)
+		}
+	}
+}
+
+func TestOperationLocks_Acquire(t *testing.T) {
+	l := NewOperationLocks()
+	l.Timeout = 0
+
+	release, err := l.Acquire(OperationScope{Database: "db0"}, "DROP DATABASE db0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overlapping operations fail at once without a timeout.
+	if _, err := l.Acquire(OperationScope{Database: "db0", RetentionPolicy: "rp0"}, "DROP RETENTION POLICY rp0 ON db0"); err != (ErrConflictingOperation{Desc: "DROP DATABASE db0"}) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err.Error() != "conflicting operation in progress: DROP DATABASE db0" {
+		t.Fatalf("unexpected message: %s", err)
+	}
+
+	// Other objects can be locked.
+	release1, err := l.Acquire(OperationScope{Database: "db1"}, "DROP DATABASE db1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release1()
+
+	// Releasing twice is harmless.
+	release()
+	release()
+	release2, err := l.Acquire(OperationScope{Database: "db0", RetentionPolicy: "rp0"}, "DROP RETENTION POLICY rp0 ON db0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release2()
+	if ops := l.Operations(); len(ops) != 2 {
+		t.Fatalf("unexpected operations: %+v", ops)
+	}
+}
+
+func TestOperationLocks_AcquireShard(t *testing.T) {
+	l := NewOperationLocks()
+	l.Timeout = 0
+	l.MetaClient = &testMetaClient{
+		DatabasesFn: func() []meta.DatabaseInfo {
+			return []meta.DatabaseInfo{{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{{
+					Name:        "rp0",
+					ShardGroups: []meta.ShardGroupInfo{{ID: 1, Shards: []meta.ShardInfo{{ID: 1}, {ID: 2}}}},
+				}},
+			}}
+		},
+	}
+
+	release, err := l.AcquireShard(1, "backup shard 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The shard's database and retention policy are locked with it.
+	if _, err := l.Acquire(OperationScope{Database: "db0"}, "DROP DATABASE db0"); err != (ErrConflictingOperation{Desc: "backup shard 1"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release2, err := l.AcquireShard(2, "backup shard 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release2()
+
+	exp := []OperationScope{
+		{Database: "db0", RetentionPolicy: "rp0", ShardID: 1},
+		{Database: "db0", RetentionPolicy: "rp0", ShardID: 2},
+	}
+	var scopes []OperationScope
+	for _, op := range l.Operations() {
+		scopes = append(scopes, op.OperationScope)
+	}
+	if !reflect.DeepEqual(scopes, exp) {
+		t.Fatalf("unexpected scopes: %+v", scopes)
+	}
+}
+
+func TestOperationLocks_Wait(t *testing.T) {
+	l := NewOperationLocks()
+	l.Timeout = 10 * time.Second
+	e := &StatementExecutor{OperationLocks: l}
+
+	release, err := l.Acquire(OperationScope{Database: "db0"}, "DROP DATABASE db0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		release func()
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		release, err := l.Acquire(OperationScope{Database: "db0", ShardID: 1}, "DROP SHARD 1")
+		done <- result{release, err}
+	}()
+
+	// The conflicting operation waits, and is shown as such.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(l.Operations()) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the operation to wait")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	results, err := executeStatement(e, `SHOW OPERATIONS`, query.ExecutionOptions{UserAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := results[0].Series[0]
+	if exp := []string{"database", "retention_policy", "shard", "operation", "state", "since"}; !reflect.DeepEqual(rows.Columns, exp) {
+		t.Fatalf("unexpected columns: %v", rows.Columns)
+	} else if len(rows.Values) != 2 {
+		t.Fatalf("unexpected rows: %v", rows.Values)
+	}
+	for i, exp := range [][]interface{}{
+		{"db0", "", uint64(0), "DROP DATABASE db0", "running"},
+		{"db0", "", uint64(1), "DROP SHARD 1", "waiting"},
+	} {
+		if got := rows.Values[i][:5]; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("unexpected row %d: %v", i, got)
+		}
+	}
+
+	// It proceeds once the lock is released.
+	select {
+	case <-done:
+		t.Fatal("conflicting operation did not wait")
+	default:
+	}
+	release()
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	defer r.release()
+	if ops := l.Operations(); len(ops) != 1 || ops[0].Desc != "DROP SHARD 1" || ops[0].Waiting {
+		t.Fatalf("unexpected operations: %+v", ops)
+	}
+}
+
+func TestOperationLocks_Timeout(t *testing.T) {
+	l := NewOperationLocks()
+	l.Timeout = 20 * time.Millisecond
+
+	release, err := l.Acquire(OperationScope{Database: "db0", RetentionPolicy: "rp0"}, "DROP RETENTION POLICY rp0 ON db0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The wait is bounded, and the operation gives up waiting.
+	start := time.Now()
+	if _, err := l.Acquire(OperationScope{Database: "db0"}, "DROP DATABASE db0"); err != (ErrConflictingOperation{Desc: "DROP RETENTION POLICY rp0 ON db0"}) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if d := time.Since(start); d < l.Timeout || d > 5*time.Second {
+		t.Fatalf("unexpected wait: %s", d)
+	}
+	if ops := l.Operations(); len(ops) != 1 || ops[0].Waiting {
+		t.Fatalf("unexpected operations: %+v", ops)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_OperationLocks(t *testing.T) {
+	l := NewOperationLocks()
+	l.Timeout = 0
+
+	errDelete := errors.New("delete failed")
+	var deleteSeries func() error
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name}
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			DeleteSeriesFn: func(database string, sources []cnosql.Source, condition cnosql.Expr) error {
+				return deleteSeries()
+			},
+			DeleteShardFn: func(id uint64) error {
+				return errDelete
+			},
+		},
+		OperationLocks: l,
+	}
+	opt := query.ExecutionOptions{Database: "db0", UserAdmin: true}
+
+	// Conflicting statements fail.
+	release, err := l.Acquire(OperationScope{Database: "db0"}, "DROP DATABASE db0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleteSeries = func() error { return nil }
+	if _, err := executeStatement(e, `DELETE FROM cpu`, opt); err != (ErrConflictingOperation{Desc: "DROP DATABASE db0"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+
+	// Locks are released when the statement fails or panics.
+	deleteSeries = func() error { return errDelete }
+	if _, err := executeStatement(e, `DELETE FROM cpu`, opt); err != errDelete {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := executeStatement(e, `DROP SHARD 1`, opt); err != errDelete {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deleteSeries = func() error { panic("delete panicked") }
+	func() {
+		defer func() {
+			if r := recover(); r != "delete panicked" {
+				t.Fatalf("unexpected panic: %v", r)
+			}
+		}()
+		executeStatement(e, `DELETE FROM cpu`, opt)
+	}()
+	if ops := l.Operations(); len(ops) != 0 {
+		t.Fatalf("locks not released: %+v", ops)
+	}
+
+	deleteSeries = func() error { return nil }
+	if _, err := executeStatement(e, `DELETE FROM cpu`, opt); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cnosdb/cnosdb"
 	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/bytesutil"
 	"github.com/cnosdb/cnosdb/vend/db/tsdb"
 	"github.com/cnosdb/cnosdb/meta"
 	"go.uber.org/zap"
@@ -80,7 +81,8 @@ type PointsWriter struct {
 	}
 	subPoints []chan<- *WritePointsRequest
 
-	stats *WriteStatistics
+	stats   *WriteStatistics
+	rpStats rpWriteStatistics
 }
 
 // NewPointsWriter returns a new instance of PointsWriter for a node.
@@ -95,10 +97,11 @@ func NewPointsWriter() *PointsWriter {
 
 // ShardMapping contains a mapping of shards to points.
 type ShardMapping struct {
-	n       int
-	Points  map[uint64][]models.Point  // The points associated with a shard ID
-	Shards  map[uint64]*meta.ShardInfo // The shards that have been mapped, keyed by shard ID
-	Dropped []models.Point             // Points that were dropped
+	n           int
+	Points      map[uint64][]models.Point  // The points associated with a shard ID
+	Shards      map[uint64]*meta.ShardInfo // The shards that have been mapped, keyed by shard ID
+	Dropped     []models.Point             // Points that were dropped
+	OutOfBounds []models.Point             // Points outside the retention policy's write limits
 }
 
 // NewShardMapping creates an empty ShardMapping.
@@ -170,7 +173,7 @@ type WriteStatistics struct {
 
 // Statistics returns statistics for periodic monitoring.
 func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
-	return []models.Statistic{{
+	statistics := []models.Statistic{{
 		Name: "write",
 		Tags: tags,
 		Values: map[string]interface{}{
@@ -188,6 +191,13 @@ func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
 			statSubWriteDrop:        atomic.LoadInt64(&w.stats.SubWriteDrop),
 		},
 	}}
+	return append(statistics, w.rpStats.Statistics(tags)...)
+}
+
+// RetentionPolicyWriteStats returns the write statistics of a retention policy
+// since startup. ok is false if nothing has been written to it.
+func (w *PointsWriter) RetentionPolicyWriteStats(database, rp string) (RetentionPolicyWriteStats, bool) {
+	return w.rpStats.get(database, rp)
 }
 
 // MapShards maps the points contained in wp to a ShardMapping.  If a point
@@ -203,15 +213,23 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 
 	// Holds all the shard groups and shards that are required for writes.
 	list := sgList{items: make(meta.ShardGroupInfos, 0, 8)}
+	now := time.Now()
 	min := time.Unix(0, models.MinNanoTime)
 	if rp.Duration > 0 {
-		min = time.Now().Add(-rp.Duration)
+		min = now.Add(-rp.Duration)
+	}
+
+	// Points outside the retention policy's write limits are rejected before
+	// they can create shard groups far from the current time.
+	outOfBounds := func(t time.Time) bool {
+		return (rp.FutureWriteLimit > 0 && t.After(now.Add(rp.FutureWriteLimit))) ||
+			(rp.PastWriteLimit > 0 && t.Before(now.Add(-rp.PastWriteLimit)))
 	}
 
 	for _, p := range wp.Points {
 		// Either the point is outside the scope of the retention policy, or we already have
 		// a suitable shard group for the point.
-		if p.Time().Before(min) || list.Covers(p.Time()) {
+		if p.Time().Before(min) || outOfBounds(p.Time()) || list.Covers(p.Time()) {
 			continue
 		}
 
@@ -230,6 +248,12 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 
 	mapping := NewShardMapping(len(wp.Points))
 	for _, p := range wp.Points {
+		if outOfBounds(p.Time()) {
+			mapping.OutOfBounds = append(mapping.OutOfBounds, p)
+			atomic.AddInt64(&w.stats.WriteDropped, 1)
+			continue
+		}
+
 		rg := list.ShardGroupAt(p.Time())
 		if rg == nil {
 			// We didn't create a shard group because the point was outside the
@@ -342,6 +366,29 @@ func (w *PointsWriter) WritePointsInto(p *IntoWriteRequest) error {
 
 // WritePoints writes data to the underlying storage. consistencyLevel and user are only used for clustered scenarios.
 func (w *PointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	// Users restricted to some retention policies or measurements may only
+	// write to those.
+	if user != nil && !user.IsOpen() {
+		rp := retentionPolicy
+		if di := w.MetaClient.Database(database); rp == "" && di != nil {
+			rp = di.DefaultRetentionPolicy
+		}
+		var auth interface {
+			AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool
+		} = user
+		if ui, ok := user.(*meta.UserInfo); ok {
+			auth = ui.ForRetentionPolicy(rp)
+		}
+		for _, p := range points {
+			if !auth.AuthorizeSeriesWrite(database, p.Name(), p.Tags()) {
+				return meta.ErrAuthorize{
+					User:     user.ID(),
+					Database: database,
+					Message:  fmt.Sprintf("write to measurement %q of %s.%s", p.Name(), database, rp),
+				}
+			}
+		}
+	}
 	return w.WritePointsPrivileged(database, retentionPolicy, consistencyLevel, points)
 }
 
@@ -371,6 +418,8 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 			err := w.writeToShard(shard, database, retentionPolicy, consistencyLevel, points)
 			if err == tsdb.ErrShardDeletion {
 				err = tsdb.PartialWriteError{Reason: fmt.Sprintf("shard %d is pending deletion", shard.ID), Dropped: len(points)}
+			} else if err == tsdb.ErrShardCold {
+				err = tsdb.PartialWriteError{Reason: fmt.Sprintf("shard %d is in the cold tier and read-only", shard.ID), Dropped: len(points)}
 			}
 			ch <- err
 		}(shardMappings.Shards[shardID], database, retentionPolicy, points)
@@ -399,7 +448,9 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 		atomic.AddInt64(&w.stats.SubWriteDrop, dropped)
 	}
 
-	if err == nil && len(shardMappings.Dropped) > 0 {
+	if err == nil && len(shardMappings.OutOfBounds) > 0 {
+		err = outOfBoundsError(retentionPolicy, shardMappings)
+	} else if err == nil && len(shardMappings.Dropped) > 0 {
 		err = tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: len(shardMappings.Dropped)}
 
 	}
@@ -419,9 +470,29 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 			}
 		}
 	}
+	w.rpStats.record(database, retentionPolicy, int64(len(points)-len(shardMappings.Dropped)-len(shardMappings.OutOfBounds)))
 	return err
 }
 
+// outOfBoundsError returns a partial write error for the points of m that
+// fell outside the write limits of the retention policy. The reason names
+// the first offending point and DroppedKeys holds the series keys of all of
+// them.
+func outOfBoundsError(retentionPolicy string, m *ShardMapping) error {
+	keys := make([][]byte, 0, len(m.OutOfBounds))
+	for _, p := range m.OutOfBounds {
+		keys = append(keys, p.Key())
+	}
+
+	first := m.OutOfBounds[0]
+	return tsdb.PartialWriteError{
+		Reason: fmt.Sprintf("points outside write limits of retention policy %q: first %s at %s",
+			retentionPolicy, first.Key(), first.Time().UTC().Format(time.RFC3339Nano)),
+		Dropped:     len(m.Dropped) + len(m.OutOfBounds),
+		DroppedKeys: bytesutil.SortDedup(keys),
+	}
+}
+
 // writeToShard writes points to a shard and ensures a write consistency level has been met.  If the write
 // partially succeeds, ErrPartialWrite is returned.
 func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPolicy string, consistency models.ConsistencyLevel, points []models.Point) error {
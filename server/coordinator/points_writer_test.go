@@ -0,0 +1,64 @@
+package coordinator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+)
+
+func TestPointsWriter_WritePoints_RetentionPolicyPrivileges(t *testing.T) {
+	// The user may only write to rp1, rp0 is the default.
+	user := &meta.UserInfo{
+		Name: "writer",
+		RetentionPolicyPrivileges: []meta.RetentionPolicyPrivilege{
+			{Database: "db0", RetentionPolicy: "rp1", Privilege: cnosql.WritePrivilege},
+		},
+	}
+	w := NewPointsWriter()
+	w.MetaClient = &writerMetaClient{defaultRP: "rp0"}
+	points := []models.Point{models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, time.Unix(0, 0))}
+
+	for _, tt := range []struct {
+		rp         string
+		authorized bool
+	}{
+		{rp: "rp0"},
+		{rp: ""},
+		{rp: "rp1", authorized: true},
+	} {
+		err := w.WritePoints("db0", tt.rp, models.ConsistencyLevelOne, user, points)
+		if tt.authorized {
+			// The authorized write reaches the retention policy lookup.
+			if err != errRetentionPolicyLookup {
+				t.Errorf("rp %q: unexpected error: %v", tt.rp, err)
+			}
+		} else if _, ok := err.(meta.ErrAuthorize); !ok {
+			t.Errorf("rp %q: unexpected error: %v", tt.rp, err)
+		}
+	}
+}
+
+// errRetentionPolicyLookup is returned by writerMetaClient when a retention
+// policy is looked up.
+var errRetentionPolicyLookup = errors.New("retention policy looked up")
+
+// writerMetaClient has a database db0 without shards.
+type writerMetaClient struct {
+	defaultRP string
+}
+
+func (c *writerMetaClient) Database(name string) *meta.DatabaseInfo {
+	return &meta.DatabaseInfo{Name: name, DefaultRetentionPolicy: c.defaultRP}
+}
+
+func (c *writerMetaClient) RetentionPolicy(database, rp string) (*meta.RetentionPolicyInfo, error) {
+	return nil, errRetentionPolicyLookup
+}
+
+func (c *writerMetaClient) CreateShardGroup(database, rp string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+	return nil, errRetentionPolicyLookup
+}
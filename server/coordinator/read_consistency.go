@@ -0,0 +1,84 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// readOwners returns the owners to read shard si from, in order of preference,
+// for the quorum-read consistency. The local node comes first if it owns the
+// shard, followed by the owners with no hinted handoff pending from this node
+// and finally the owners that are known to be missing writes. stale reports
+// whether the first owner is known to be missing writes.
+func (e *LocalShardMapper) readOwners(si meta.ShardInfo, localNodeID uint64) (nodeIDs []uint64, stale bool) {
+	lagging := func(nodeID uint64) bool {
+		return nodeID != localNodeID && e.HintedHandoff != nil && e.HintedHandoff.Pending(nodeID)
+	}
+
+	nodeIDs = make([]uint64, 0, len(si.Owners))
+	for _, i := range rand.Perm(len(si.Owners)) {
+		nodeIDs = append(nodeIDs, si.Owners[i].NodeID)
+	}
+	sort.SliceStable(nodeIDs, func(i, j int) bool {
+		if (nodeIDs[i] == localNodeID) != (nodeIDs[j] == localNodeID) {
+			return nodeIDs[i] == localNodeID
+		}
+		return !lagging(nodeIDs[i]) && lagging(nodeIDs[j])
+	})
+
+	if len(nodeIDs) > 0 {
+		stale = lagging(nodeIDs[0])
+	}
+	return nodeIDs, stale
+}
+
+type readWarningsContextKey struct{}
+
+// readWarnings collects the warnings raised while reading remote shards so
+// they can be returned to the user with the query results.
+type readWarnings struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+	msgs []*query.Message
+}
+
+// withReadWarnings returns a context that collects read warnings into w.
+func withReadWarnings(ctx context.Context, w *readWarnings) context.Context {
+	return context.WithValue(ctx, readWarningsContextKey{}, w)
+}
+
+// addReadWarning adds a warning to the collector in ctx, if any.
+func addReadWarning(ctx context.Context, format string, a ...interface{}) {
+	w, _ := ctx.Value(readWarningsContextKey{}).(*readWarnings)
+	if w == nil {
+		return
+	}
+
+	text := fmt.Sprintf(format, a...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.seen[text]; ok {
+		return
+	}
+	if w.seen == nil {
+		w.seen = make(map[string]struct{})
+	}
+	w.seen[text] = struct{}{}
+	w.msgs = append(w.msgs, &query.Message{Level: query.WarningLevel, Text: text})
+}
+
+// Messages returns the collected warnings and resets the collector.
+func (w *readWarnings) Messages() []*query.Message {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	msgs := w.msgs
+	w.msgs = nil
+	return msgs
+}
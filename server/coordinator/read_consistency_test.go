@@ -0,0 +1,205 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// pendingOwners is a hinted handoff with writes pending for some owners.
+type pendingOwners map[uint64]bool
+
+func (p pendingOwners) Pending(ownerID uint64) bool { return p[ownerID] }
+
+func TestLocalShardMapper_ReadOwners(t *testing.T) {
+	si := meta.ShardInfo{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}, {NodeID: 3}, {NodeID: 4}}}
+
+	for _, tt := range []struct {
+		name    string
+		local   uint64
+		pending pendingOwners
+		first   []uint64 // Expected owners in order, the others in any order.
+		last    []uint64
+		stale   bool
+	}{
+		{name: "local owner", local: 2, first: []uint64{2}},
+		// The local node has all the writes it queued for others.
+		{name: "local owner pending", local: 2, pending: pendingOwners{2: true, 3: true}, first: []uint64{2}, last: []uint64{3}},
+		{name: "remote", local: 5, pending: pendingOwners{1: true, 3: true}, last: []uint64{1, 3}},
+		{name: "all lagging", local: 5, pending: pendingOwners{1: true, 2: true, 3: true, 4: true}, stale: true},
+	} {
+		e := &LocalShardMapper{}
+		if tt.pending != nil {
+			e.HintedHandoff = tt.pending
+		}
+
+		// The owners are shuffled, so check the order holds every time.
+		for i := 0; i < 20; i++ {
+			nodeIDs, stale := e.readOwners(si, tt.local)
+			if len(nodeIDs) != 4 {
+				t.Fatalf("%s: unexpected owners: %v", tt.name, nodeIDs)
+			} else if stale != tt.stale {
+				t.Fatalf("%s: unexpected stale: %v", tt.name, stale)
+			}
+			if got := nodeIDs[:len(tt.first)]; len(tt.first) > 0 && !reflect.DeepEqual(got, tt.first) {
+				t.Fatalf("%s: unexpected owners: %v", tt.name, nodeIDs)
+			}
+			for _, id := range nodeIDs[:len(nodeIDs)-len(tt.last)] {
+				if tt.pending[id] && id != tt.local && !tt.stale {
+					t.Fatalf("%s: lagging owner read first: %v", tt.name, nodeIDs)
+				}
+			}
+		}
+	}
+
+	// Shards without owners have nothing to read.
+	if nodeIDs, stale := (&LocalShardMapper{}).readOwners(meta.ShardInfo{ID: 2}, 1); len(nodeIDs) != 0 || stale {
+		t.Fatalf("unexpected owners: %v", nodeIDs)
+	}
+}
+
+func TestReadWarnings(t *testing.T) {
+	// Warnings are dropped without a collector.
+	addReadWarning(context.Background(), "shard %d: dropped", 1)
+
+	var w readWarnings
+	ctx := withReadWarnings(context.Background(), &w)
+	addReadWarning(ctx, "shard %d: stale", 1)
+	addReadWarning(ctx, "shard %d: stale", 2)
+	addReadWarning(ctx, "shard %d: stale", 1)
+
+	var texts []string
+	for _, m := range w.Messages() {
+		if m.Level != query.WarningLevel {
+			t.Fatalf("unexpected level: %s", m.Level)
+		}
+		texts = append(texts, m.Text)
+	}
+	if exp := []string{"shard 1: stale", "shard 2: stale"}; !reflect.DeepEqual(texts, exp) {
+		t.Fatalf("unexpected warnings: %q", texts)
+	}
+
+	// The messages are returned once, and repeated warnings are not sent
+	// again.
+	addReadWarning(ctx, "shard %d: stale", 2)
+	if msgs := w.Messages(); len(msgs) != 0 {
+		t.Fatalf("unexpected warnings: %v", msgs)
+	}
+}
+
+// listenDataNode serves the iterator requests of a data node with empty
+// float iterators. It returns the address it listens on.
+func listenDataNode(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				header := make([]byte, len(MuxHeader))
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				var req CreateIteratorRequest
+				if _, err := DecodeTLV(conn, &req); err != nil {
+					return
+				}
+				EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{typ: cnosql.Float})
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRemoteIteratorCreator_Fallback(t *testing.T) {
+	addr := listenDataNode(t)
+	dialer := &NodeDialer{
+		MetaClient: &testMetaClient{
+			DataNodeFn: func(id uint64) (*meta.NodeInfo, error) {
+				if id == 2 {
+					return &meta.NodeInfo{ID: id, TCPHost: addr}, nil
+				}
+				return nil, fmt.Errorf("node %d unreachable", id)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}
+	m := &cnosql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"}
+
+	for _, tt := range []struct {
+		name      string
+		nodeID    uint64
+		fallbacks []uint64
+		stale     bool
+		err       string
+		warnings  []string
+	}{
+		{name: "preferred owner", nodeID: 2},
+		{name: "stale", nodeID: 2, stale: true, warnings: []string{"shard 10: every owner is missing writes queued in hinted handoff, results may be stale"}},
+		{name: "fallback", nodeID: 1, fallbacks: []uint64{3, 2}, warnings: []string{"shard 10: owner node 1 unreachable, read from node 2 instead, results may be stale"}},
+		{name: "no fallback", nodeID: 1, err: "node 1 unreachable"},
+		{name: "all unreachable", nodeID: 1, fallbacks: []uint64{3}, err: "node 3 unreachable", warnings: []string{"shard 10: no owner could be read, results are incomplete: node 3 unreachable"}},
+	} {
+		ic := newRemoteIteratorCreator(dialer, tt.nodeID, []uint64{10})
+		ic.fallbackNodeIDs, ic.stale = tt.fallbacks, tt.stale
+
+		var w readWarnings
+		itr, err := ic.CreateIterator(withReadWarnings(context.Background(), &w), m, query.IteratorOptions{})
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		} else {
+			itr.Close()
+		}
+
+		var texts []string
+		for _, m := range w.Messages() {
+			texts = append(texts, m.Text)
+		}
+		if !reflect.DeepEqual(texts, tt.warnings) {
+			t.Fatalf("%s: unexpected warnings: %q", tt.name, texts)
+		}
+	}
+}
+
+func TestParseReadConsistency(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		exp query.ReadConsistency
+		err error
+	}{
+		{s: "", exp: query.ReadConsistencyLocal},
+		{s: "local", exp: query.ReadConsistencyLocal},
+		{s: "Quorum-Read", exp: query.ReadConsistencyQuorum},
+		{s: "all", err: query.ErrInvalidReadConsistency},
+	} {
+		c, err := query.ParseReadConsistency(tt.s)
+		if err != tt.err {
+			t.Fatalf("%q: unexpected error: %v", tt.s, err)
+		} else if c != tt.exp {
+			t.Fatalf("%q: unexpected read consistency: %s", tt.s, c)
+		}
+	}
+	if s := query.ReadConsistencyQuorum.String(); s != "quorum-read" {
+		t.Fatalf("unexpected name: %s", s)
+	}
+}
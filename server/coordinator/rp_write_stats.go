@@ -0,0 +1,140 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/models"
+)
+
+// The keys for statistics generated by the "rpWrite" module.
+const (
+	statRPPointsWritten     = "pointsWritten"
+	statRPLastWrite         = "lastWrite"
+	statRPWritePointsPerMin = "writePointsPerMin"
+)
+
+// RetentionPolicyWriteStats describes the write traffic a retention policy
+// has received since the process started.
+type RetentionPolicyWriteStats struct {
+	// LastWrite is the time of the most recent successful write.
+	LastWrite time.Time
+
+	// PointsWritten is the number of points written since startup.
+	PointsWritten int64
+
+	// WritePointsPerMin is the number of points written during the last
+	// complete minute.
+	WritePointsPerMin int64
+}
+
+// RetentionPolicyWriteStatser returns write statistics for a retention policy.
+// ok is false if no write to the retention policy has been seen since startup.
+type RetentionPolicyWriteStatser interface {
+	RetentionPolicyWriteStats(database, rp string) (stats RetentionPolicyWriteStats, ok bool)
+}
+
+type rpKey struct {
+	database string
+	rp       string
+}
+
+type rpWriteStat struct {
+	lastWrite time.Time
+	points    int64
+
+	// Points written in the current and the previous minute.
+	minute     time.Time
+	minuteN    int64
+	prevMinute int64
+}
+
+// record adds n points written at now.
+func (s *rpWriteStat) record(n int64, now time.Time) {
+	if m := now.Truncate(time.Minute); !m.Equal(s.minute) {
+		if m.Sub(s.minute) == time.Minute {
+			s.prevMinute = s.minuteN
+		} else {
+			s.prevMinute = 0
+		}
+		s.minute, s.minuteN = m, 0
+	}
+	s.minuteN += n
+	s.points += n
+	s.lastWrite = now
+}
+
+// perMinute returns the number of points written in the last complete minute.
+func (s *rpWriteStat) perMinute(now time.Time) int64 {
+	switch now.Truncate(time.Minute).Sub(s.minute) {
+	case 0:
+		return s.prevMinute
+	case time.Minute:
+		return s.minuteN
+	default:
+		return 0
+	}
+}
+
+// rpWriteStatistics tracks write traffic per database and retention policy.
+// Nothing is persisted, so it only covers writes since startup.
+type rpWriteStatistics struct {
+	mu    sync.Mutex
+	stats map[rpKey]*rpWriteStat
+}
+
+func (r *rpWriteStatistics) record(database, rp string, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats == nil {
+		r.stats = make(map[rpKey]*rpWriteStat)
+	}
+	k := rpKey{database: database, rp: rp}
+	s := r.stats[k]
+	if s == nil {
+		s = &rpWriteStat{}
+		r.stats[k] = s
+	}
+	s.record(n, time.Now())
+}
+
+func (r *rpWriteStatistics) get(database, rp string) (RetentionPolicyWriteStats, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.stats[rpKey{database: database, rp: rp}]
+	if s == nil {
+		return RetentionPolicyWriteStats{}, false
+	}
+	return RetentionPolicyWriteStats{
+		LastWrite:         s.lastWrite,
+		PointsWritten:     s.points,
+		WritePointsPerMin: s.perMinute(time.Now()),
+	}, true
+}
+
+// Statistics returns one statistic per retention policy written to since startup.
+func (r *rpWriteStatistics) Statistics(tags map[string]string) []models.Statistic {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	statistics := make([]models.Statistic, 0, len(r.stats))
+	for k, s := range r.stats {
+		statistics = append(statistics, models.Statistic{
+			Name: "rpWrite",
+			Tags: models.StatisticTags{"database": k.database, "rp": k.rp}.Merge(tags),
+			Values: map[string]interface{}{
+				statRPPointsWritten:     s.points,
+				statRPLastWrite:         s.lastWrite.UnixNano(),
+				statRPWritePointsPerMin: s.perMinute(now),
+			},
+		})
+	}
+	return statistics
+}
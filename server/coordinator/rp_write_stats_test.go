@@ -0,0 +1,136 @@
+package coordinator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+func TestRPWriteStat_PerMinute(t *testing.T) {
+	t0 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	var s rpWriteStat
+
+	// The current minute is not complete, so it doesn't count yet.
+	s.record(10, t0.Add(10*time.Second))
+	s.record(5, t0.Add(50*time.Second))
+	if n := s.perMinute(t0.Add(55 * time.Second)); n != 0 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	} else if n := s.perMinute(t0.Add(90 * time.Second)); n != 15 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	}
+
+	// Writing in the next minute keeps the last complete one.
+	s.record(3, t0.Add(70*time.Second))
+	if n := s.perMinute(t0.Add(80 * time.Second)); n != 15 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	} else if n := s.perMinute(t0.Add(130 * time.Second)); n != 3 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	}
+
+	// Minutes without writes count as zero.
+	if n := s.perMinute(t0.Add(190 * time.Second)); n != 0 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	}
+	s.record(1, t0.Add(200*time.Second))
+	if n := s.perMinute(t0.Add(210 * time.Second)); n != 0 {
+		t.Fatalf("unexpected points per minute: %d", n)
+	}
+
+	if s.points != 19 || !s.lastWrite.Equal(t0.Add(200*time.Second)) {
+		t.Fatalf("unexpected totals: %d points, last write %s", s.points, s.lastWrite)
+	}
+}
+
+func TestRPWriteStatistics(t *testing.T) {
+	var r rpWriteStatistics
+
+	// Writes without points are not recorded.
+	r.record("db0", "rp0", 0)
+	if _, ok := r.get("db0", "rp0"); ok {
+		t.Fatal("unexpected write statistics")
+	}
+
+	r.record("db0", "rp0", 2)
+	r.record("db0", "rp0", 3)
+	r.record("db0", "rp1", 1)
+	if ws, ok := r.get("db0", "rp0"); !ok || ws.PointsWritten != 5 || ws.LastWrite.IsZero() {
+		t.Fatalf("unexpected write statistics: %+v", ws)
+	} else if _, ok := r.get("db1", "rp0"); ok {
+		t.Fatal("unexpected write statistics")
+	}
+
+	points := make(map[string]int64)
+	for _, stat := range r.Statistics(map[string]string{"hostname": "h0"}) {
+		if stat.Name != "rpWrite" || stat.Tags["database"] != "db0" || stat.Tags["hostname"] != "h0" {
+			t.Fatalf("unexpected statistic: %+v", stat)
+		}
+		points[stat.Tags["rp"]] = stat.Values[statRPPointsWritten].(int64)
+	}
+	if exp := map[string]int64{"rp0": 5, "rp1": 1}; !reflect.DeepEqual(points, exp) {
+		t.Fatalf("unexpected points written: %v", points)
+	}
+}
+
+// testWriteStats returns fixed write statistics per retention policy.
+type testWriteStats map[string]RetentionPolicyWriteStats
+
+func (s testWriteStats) RetentionPolicyWriteStats(database, rp string) (RetentionPolicyWriteStats, bool) {
+	ws, ok := s[database+"."+rp]
+	return ws, ok
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowRetentionPoliciesWriteStats(t *testing.T) {
+	di := &meta.DatabaseInfo{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "rp0",
+		RetentionPolicies:      []meta.RetentionPolicyInfo{{Name: "rp0", ReplicaN: 1}, {Name: "rp1", ReplicaN: 1}},
+	}
+	stats := testWriteStats{
+		"db0.rp0": {LastWrite: time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("", 3600)), PointsWritten: 100, WritePointsPerMin: 20},
+	}
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo { return di },
+		},
+		WriteStats: stats,
+	}
+
+	writeStats := func() [][]interface{} {
+		t.Helper()
+		results, err := executeStatement(e, `SHOW RETENTION POLICIES ON db0`, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		row := results[0].Series[0]
+		if !reflect.DeepEqual(row.Columns[5:7], []string{"lastWrite", "writePointsPerMin"}) {
+			t.Fatalf("unexpected columns: %q", row.Columns)
+		}
+		var values [][]interface{}
+		for _, v := range row.Values {
+			values = append(values, v[5:7])
+		}
+		return values
+	}
+
+	// Retention policies not written to since startup have unknown traffic.
+	if got, exp := writeStats(), [][]interface{}{{"1999-12-31T23:00:00Z", int64(20)}, {nil, nil}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected write statistics: %v", got)
+	}
+	results, _ := executeStatement(e, `SHOW RETENTION POLICIES ON db0`, query.ExecutionOptions{UserAdmin: true})
+	if msgs := results[0].Messages; len(msgs) != 1 || msgs[0].Level != query.WarningLevel {
+		t.Fatalf("unexpected messages: %v", msgs)
+	}
+
+	// No warning is sent once all of them are known.
+	stats["db0.rp1"] = RetentionPolicyWriteStats{LastWrite: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if got, exp := writeStats(), [][]interface{}{{"1999-12-31T23:00:00Z", int64(20)}, {"2000-01-01T00:00:00Z", int64(0)}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected write statistics: %v", got)
+	}
+	results, _ = executeStatement(e, `SHOW RETENTION POLICIES ON db0`, query.ExecutionOptions{UserAdmin: true})
+	if msgs := results[0].Messages; len(msgs) != 0 {
+		t.Fatalf("unexpected messages: %v", msgs)
+	}
+}
@@ -209,6 +209,16 @@ type CreateIteratorRequest struct {
 	ShardIDs    []uint64
 	Measurement cnosql.Measurement
 	Opt         query.IteratorOptions
+
+	// The node and the ID of the query the iterator is created for, used
+	// to kill it. Zero if the query can't be killed.
+	NodeID  uint64
+	QueryID uint64
+
+	// The user whose privileges restrict the series read, since the
+	// authorizer of the options isn't encoded. Empty if the query is not
+	// restricted to some retention policies or measurements.
+	User string
 }
 
 // MarshalBinary encodes r to a binary format.
@@ -217,13 +227,19 @@ func (r *CreateIteratorRequest) MarshalBinary() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return proto.Marshal(&internal.CreateIteratorRequest{
+	pb := &internal.CreateIteratorRequest{
 		ShardIDs:        r.ShardIDs,
 		Database:        []byte(r.Measurement.Database),
 		RetentionPolicy: []byte(r.Measurement.RetentionPolicy),
 		MeasurementName: []byte(r.Measurement.Name),
 		Opt:             buf,
-	})
+		NodeID:          proto.Uint64(r.NodeID),
+		QueryID:         proto.Uint64(r.QueryID),
+	}
+	if r.User != "" {
+		pb.User = proto.String(r.User)
+	}
+	return proto.Marshal(pb)
 }
 
 // UnmarshalBinary decodes data into r.
@@ -237,6 +253,9 @@ func (r *CreateIteratorRequest) UnmarshalBinary(data []byte) error {
 	r.Measurement.Database = string(pb.GetDatabase()[:])
 	r.Measurement.RetentionPolicy = string(pb.GetRetentionPolicy()[:])
 	r.Measurement.Name = string(pb.GetMeasurementName()[:])
+	r.NodeID = pb.GetNodeID()
+	r.QueryID = pb.GetQueryID()
+	r.User = pb.GetUser()
 	if err := r.Opt.UnmarshalBinary(pb.GetOpt()); err != nil {
 		return err
 	}
@@ -277,6 +296,59 @@ func (r *CreateIteratorResponse) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// KillQueryRequest represents a request to kill the iterators created for a
+// query of another node.
+type KillQueryRequest struct {
+	NodeID  uint64
+	QueryID uint64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.KillQueryRequest{
+		NodeID:  proto.Uint64(r.NodeID),
+		QueryID: proto.Uint64(r.QueryID),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.NodeID = pb.GetNodeID()
+	r.QueryID = pb.GetQueryID()
+	return nil
+}
+
+// KillQueryResponse represents a response to a KillQueryRequest, sent once
+// the iterators of the query stopped.
+type KillQueryResponse struct {
+	Err error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.KillQueryResponse
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
 // FieldDimensionsRequest represents a request to retrieve unique fields & dimensions.
 type FieldDimensionsRequest struct {
 	ShardIDs    []uint64
@@ -0,0 +1,151 @@
+package coordinator
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+)
+
+// The keys for statistics generated by the "coordinator_runtime" module.
+const (
+	statRuntimeStatements  = "statements"      // Number of sampled statements.
+	statRuntimeDuration    = "durationNs"      // Total wall time of sampled statements.
+	statRuntimeGCPause     = "gcPauseNs"       // GC pause time observed while sampled statements ran.
+	statRuntimeGCCycles    = "gcCycles"        // GC cycles completed while sampled statements ran.
+	statRuntimeAllocBytes  = "allocBytes"      // Bytes allocated while sampled statements ran.
+	statRuntimeHeapGrowth  = "heapGrowthBytes" // Net heap growth while sampled statements ran.
+	runtimeStatisticsName  = "coordinator_runtime"
+	memStatsCacheFreshness = time.Second
+)
+
+// memStatsCache caches the result of runtime.ReadMemStats so that callers
+// sampling at a high rate do not stop the world on every call.
+type memStatsCache struct {
+	mu   sync.Mutex
+	last time.Time
+	ms   runtime.MemStats
+}
+
+var cachedMemStats memStatsCache
+
+// get returns the cached memory statistics, refreshing them if they are older
+// than maxAge. A maxAge of zero always refreshes.
+func (c *memStatsCache) get(maxAge time.Duration) runtime.MemStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := time.Now(); maxAge == 0 || now.Sub(c.last) > maxAge {
+		runtime.ReadMemStats(&c.ms)
+		c.last = now
+	}
+	return c.ms
+}
+
+// runtimeSample is a snapshot of runtime state taken when a statement starts.
+type runtimeSample struct {
+	start        time.Time
+	pauseTotalNs uint64
+	numGC        uint32
+	totalAlloc   uint64
+	heapAlloc    uint64
+}
+
+type runtimeStatKey struct {
+	statement string
+	database  string
+}
+
+type runtimeStatValues struct {
+	statements      int64
+	durationNs      int64
+	gcPauseNs       int64
+	gcCycles        int64
+	allocBytes      int64
+	heapGrowthBytes int64
+}
+
+// runtimeStatistics attributes Go runtime activity (GC pauses and heap growth)
+// to the statements that were running while it happened. The attribution is
+// approximate: concurrent statements share the same runtime, and the starting
+// snapshot may be up to memStatsCacheFreshness old.
+type runtimeStatistics struct {
+	mu     sync.Mutex
+	values map[runtimeStatKey]*runtimeStatValues
+}
+
+// begin takes a cheap snapshot of the runtime state using cached memory statistics.
+func (s *runtimeStatistics) begin() runtimeSample {
+	ms := cachedMemStats.get(memStatsCacheFreshness)
+	return runtimeSample{
+		start:        time.Now(),
+		pauseTotalNs: ms.PauseTotalNs,
+		numGC:        ms.NumGC,
+		totalAlloc:   ms.TotalAlloc,
+		heapAlloc:    ms.HeapAlloc,
+	}
+}
+
+// end records the runtime deltas since sample if the statement ran for at
+// least threshold. Fast statements return without reading memory statistics.
+func (s *runtimeStatistics) end(sample runtimeSample, stmt cnosql.Statement, database string, threshold time.Duration) {
+	d := time.Since(sample.start)
+	if d < threshold {
+		return
+	}
+
+	ms := cachedMemStats.get(0)
+	key := runtimeStatKey{statement: statementTypeName(stmt), database: database}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values == nil {
+		s.values = make(map[runtimeStatKey]*runtimeStatValues)
+	}
+	v := s.values[key]
+	if v == nil {
+		v = &runtimeStatValues{}
+		s.values[key] = v
+	}
+
+	v.statements++
+	v.durationNs += d.Nanoseconds()
+	v.gcPauseNs += int64(ms.PauseTotalNs - sample.pauseTotalNs)
+	v.gcCycles += int64(ms.NumGC - sample.numGC)
+	v.allocBytes += int64(ms.TotalAlloc - sample.totalAlloc)
+	v.heapGrowthBytes += int64(ms.HeapAlloc) - int64(sample.heapAlloc)
+}
+
+// Statistics returns one statistic per statement type and database.
+func (s *runtimeStatistics) Statistics(tags map[string]string) []models.Statistic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statistics := make([]models.Statistic, 0, len(s.values))
+	for k, v := range s.values {
+		statistics = append(statistics, models.Statistic{
+			Name: runtimeStatisticsName,
+			Tags: models.StatisticTags{"statement": k.statement, "database": k.database}.Merge(tags),
+			Values: map[string]interface{}{
+				statRuntimeStatements: v.statements,
+				statRuntimeDuration:   v.durationNs,
+				statRuntimeGCPause:    v.gcPauseNs,
+				statRuntimeGCCycles:   v.gcCycles,
+				statRuntimeAllocBytes: v.allocBytes,
+				statRuntimeHeapGrowth: v.heapGrowthBytes,
+			},
+		})
+	}
+	return statistics
+}
+
+// statementTypeName returns the type name of a statement, e.g. "ShowTagValuesStatement".
+func statementTypeName(stmt cnosql.Statement) string {
+	name := fmt.Sprintf("%T", stmt)
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,78 @@
+package coordinator
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+var runtimeStatsSink [][]byte
+
+func TestRuntimeStatistics(t *testing.T) {
+	var s runtimeStatistics
+	stmt := cnosql.MustParseStatement(`SHOW DATABASES`)
+
+	// Fast statements are not recorded.
+	s.end(s.begin(), stmt, "db0", time.Hour)
+	if stats := s.Statistics(nil); len(stats) != 0 {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+
+	// The runtime activity of slow statements is attributed to their type
+	// and database.
+	for i := 0; i < 2; i++ {
+		sample := s.begin()
+		for j := 0; j < 100; j++ {
+			runtimeStatsSink = append(runtimeStatsSink, make([]byte, 1024))
+		}
+		runtime.GC()
+		s.end(sample, stmt, "db0", 0)
+	}
+	runtimeStatsSink = nil
+	s.end(s.begin(), stmt, "db1", 0)
+
+	stats := s.Statistics(map[string]string{"hostname": "h0"})
+	if len(stats) != 2 {
+		t.Fatalf("unexpected statistics: %v", stats)
+	}
+	for _, stat := range stats {
+		if stat.Name != "coordinator_runtime" || stat.Tags["statement"] != "ShowDatabasesStatement" || stat.Tags["hostname"] != "h0" {
+			t.Fatalf("unexpected statistic: %+v", stat)
+		}
+		if stat.Tags["database"] != "db0" {
+			continue
+		}
+		v := stat.Values
+		if v[statRuntimeStatements].(int64) != 2 || v[statRuntimeDuration].(int64) <= 0 {
+			t.Fatalf("unexpected statements: %v", v)
+		} else if v[statRuntimeGCCycles].(int64) < 2 || v[statRuntimeAllocBytes].(int64) < 200*1024 {
+			t.Fatalf("unexpected runtime activity: %v", v)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_RuntimeStats(t *testing.T) {
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabasesFn: func() []meta.DatabaseInfo { return nil },
+		},
+		RuntimeStatsThreshold: time.Nanosecond,
+	}
+
+	if _, err := executeStatement(e, `SHOW DATABASES`, query.ExecutionOptions{Database: "db0", UserAdmin: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, stat := range e.Statistics(nil) {
+		if stat.Name == "coordinator_runtime" {
+			if stat.Tags["statement"] != "ShowDatabasesStatement" || stat.Tags["database"] != "db0" || stat.Values[statRuntimeStatements].(int64) != 1 {
+				t.Fatalf("unexpected statistic: %+v", stat)
+			}
+			return
+		}
+	}
+	t.Fatal("no runtime statistics")
+}
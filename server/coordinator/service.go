@@ -40,6 +40,8 @@ const (
 
 	seriesKeysReq  = "seriesKeysReq"
 	seriesKeysResp = "seriesKeysResp"
+
+	killQueryReq = "killQueryReq"
 )
 
 // Service processes data received over raw TCP connections.
@@ -53,20 +55,37 @@ type Service struct {
 
 	MetaClient interface {
 		ShardOwner(shardID uint64) (string, string, *meta.ShardGroupInfo)
+		User(name string) (meta.User, error)
 	}
 
 	TSDBStore TSDBStore
 
+	// Iterators created for queries of other nodes, so they can be killed.
+	iterators map[remoteQuery]map[*remoteIterator]struct{}
+
 	Logger  *zap.Logger
 	statMap *expvar.Map
 }
 
+// remoteQuery identifies a query by the node it runs on and its ID there.
+type remoteQuery struct {
+	nodeID  uint64
+	queryID uint64
+}
+
+// remoteIterator is an iterator created for a query of another node.
+type remoteIterator struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
 // NewService returns a new instance of Service.
 func NewService(c Config) *Service {
 	return &Service{
-		closing: make(chan struct{}),
-		Logger:  zap.NewNop(),
-		statMap: common.NewStatistics("coordinator", "coordinator", nil),
+		closing:   make(chan struct{}),
+		iterators: make(map[remoteQuery]map[*remoteIterator]struct{}),
+		Logger:    zap.NewNop(),
+		statMap:   common.NewStatistics("coordinator", "coordinator", nil),
 	}
 }
 
@@ -190,6 +209,10 @@ func (s *Service) handleConn(conn net.Conn) {
 			s.statMap.Add(fieldDimensionsReq, 1)
 			s.processFieldDimensionsRequest(conn)
 			return
+		case killQueryRequestMessage:
+			s.statMap.Add(killQueryReq, 1)
+			s.processKillQueryRequest(conn)
+			return
 		default:
 			s.Logger.Info("coordinator service message type not found:", zap.Uint8("Type", uint8(typ)))
 		}
@@ -298,21 +321,48 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 	defer conn.Close()
 
 	var itr query.Iterator
+	unregister := func() {}
+	defer func() { unregister() }()
 	if err := func() error {
 		// Parse request.
 		var req CreateIteratorRequest
 		if err := DecodeLV(conn, &req); err != nil {
 			return err
 		}
+
+		// Stop the iterator if the query is killed on its node.
+		ctx := context.Background()
+		if req.QueryID != 0 {
+			ctx, unregister = s.registerIterator(remoteQuery{nodeID: req.NodeID, queryID: req.QueryID})
+			req.Opt.InterruptCh = ctx.Done()
+		}
+
+		// Read only the series the user of a restricted query may read.
+		if req.User != "" {
+			u, err := s.MetaClient.User(req.User)
+			if err != nil {
+				return err
+			}
+			req.Opt.Authorizer = u
+			if a, ok := u.(retentionPolicyAuthorizer); ok {
+				req.Opt.Authorizer = a.ForRetentionPolicy(req.Measurement.RetentionPolicy)
+			}
+		}
+
 		sg := s.TSDBStore.ShardGroup(req.ShardIDs)
-		ic, err := sg.CreateIterator(context.Background(), &req.Measurement, req.Opt)
+		ic, err := sg.CreateIterator(ctx, &req.Measurement, req.Opt)
 		if err != nil {
 			return err
 		}
+		if ic != nil && req.Opt.InterruptCh != nil {
+			ic = query.NewInterruptIterator(ic, req.Opt.InterruptCh)
+		}
 		itr = ic
 		return nil
 	}(); err != nil {
-		itr.Close()
+		if itr != nil {
+			itr.Close()
+		}
 		//s.Logger.Printf("error reading CreateIterator request: %s", err)
 		EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{Err: err})
 		return
@@ -355,6 +405,71 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 	}
 }
 
+// registerIterator registers an iterator created for the query q of
+// another node. It returns the context of the iterator, cancelled if the
+// query is killed, and a function to call once the iterator is done.
+func (s *Service) registerIterator(q remoteQuery) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ri := &remoteIterator{cancel: cancel, done: make(chan struct{})}
+
+	s.mu.Lock()
+	if s.iterators[q] == nil {
+		s.iterators[q] = make(map[*remoteIterator]struct{})
+	}
+	s.iterators[q][ri] = struct{}{}
+	s.mu.Unlock()
+
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.iterators[q], ri)
+		if len(s.iterators[q]) == 0 {
+			delete(s.iterators, q)
+		}
+		s.mu.Unlock()
+
+		cancel()
+		close(ri.done)
+	}
+}
+
+// killQuery cancels the iterators created for the query q of another node
+// and waits for them to stop.
+func (s *Service) killQuery(q remoteQuery) {
+	s.mu.RLock()
+	iterators := make([]*remoteIterator, 0, len(s.iterators[q]))
+	for ri := range s.iterators[q] {
+		iterators = append(iterators, ri)
+	}
+	s.mu.RUnlock()
+
+	for _, ri := range iterators {
+		ri.cancel()
+	}
+	for _, ri := range iterators {
+		select {
+		case <-ri.done:
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+func (s *Service) processKillQueryRequest(conn net.Conn) {
+	defer conn.Close()
+
+	var req KillQueryRequest
+	if err := DecodeLV(conn, &req); err != nil {
+		s.Logger.Info("error reading KillQuery request", zap.Error(err))
+		EncodeTLV(conn, killQueryResponseMessage, &KillQueryResponse{Err: err})
+		return
+	}
+
+	s.killQuery(remoteQuery{nodeID: req.NodeID, queryID: req.QueryID})
+	if err := EncodeTLV(conn, killQueryResponseMessage, &KillQueryResponse{}); err != nil {
+		s.Logger.Info("error writing KillQuery response", zap.Error(err))
+	}
+}
+
 func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 	var fields map[string]cnosql.DataType
 	var dimensions map[string]struct{}
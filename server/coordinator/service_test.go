@@ -0,0 +1,280 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+	"github.com/cnosdb/cnosdb/vend/db/tsdb"
+)
+
+func TestService_KillQuery(t *testing.T) {
+	s := NewService(Config{})
+	q := remoteQuery{nodeID: 1, queryID: 10}
+
+	// An iterator of another query must not be killed.
+	other, otherDone := s.registerIterator(remoteQuery{nodeID: 2, queryID: 10})
+	defer otherDone()
+
+	stopped := make(chan struct{})
+	ctx, done := s.registerIterator(q)
+	go func() {
+		<-ctx.Done()
+		close(stopped)
+		done()
+	}()
+
+	s.killQuery(q)
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("kill returned before the iterator stopped")
+	}
+	if other.Err() != nil {
+		t.Fatal("iterator of another query killed")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.iterators[q]; ok || len(s.iterators) != 1 {
+		t.Fatalf("unexpected registered queries: %v", s.iterators)
+	}
+}
+
+func TestService_CreateIterator_User(t *testing.T) {
+	user := &meta.UserInfo{
+		Name: "reader",
+		MeasurementPrivileges: []meta.MeasurementPrivilege{
+			{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+		},
+	}
+	sg := &authorizerShardGroup{}
+	s := NewService(Config{})
+	s.TSDBStore = &testTSDBStore{ShardGroupFn: func(ids []uint64) tsdb.ShardGroup { return sg }}
+	s.MetaClient = &serviceMetaClient{users: map[string]meta.User{user.Name: user}}
+
+	createIterator := func(user string) error {
+		client, server := net.Pipe()
+		defer client.Close()
+		go func() {
+			if _, err := ReadType(server); err != nil {
+				t.Error(err)
+			}
+			s.processCreateIteratorRequest(server)
+		}()
+
+		req := CreateIteratorRequest{
+			ShardIDs:    []uint64{1},
+			Measurement: cnosql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"},
+			User:        user,
+		}
+		if err := EncodeTLV(client, createIteratorRequestMessage, &req); err != nil {
+			return err
+		}
+		var resp CreateIteratorResponse
+		if _, err := DecodeTLV(client, &resp); err != nil {
+			return err
+		}
+		return resp.Err
+	}
+
+	// The iterator of a restricted user only reads the granted measurements.
+	if err := createIterator("reader"); err == nil || err.Error() != errIteratorCreated.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sg.auth == nil || !sg.auth.AuthorizeSeriesRead("db0", []byte("cpu"), nil) || sg.auth.AuthorizeSeriesRead("db0", []byte("mem"), nil) {
+		t.Fatalf("unexpected authorizer: %#v", sg.auth)
+	}
+
+	// An unknown user is refused.
+	sg.auth = nil
+	if err := createIterator("unknown"); err == nil || err.Error() != meta.ErrUserNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sg.auth != nil {
+		t.Fatal("iterator created for an unknown user")
+	}
+
+	// Queries which are not restricted send no user.
+	if err := createIterator(""); err == nil || err.Error() != errIteratorCreated.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sg.auth != nil {
+		t.Fatalf("unexpected authorizer: %#v", sg.auth)
+	}
+}
+
+func TestRestrictedUser(t *testing.T) {
+	restricted := &meta.UserInfo{
+		Name: "reader",
+		MeasurementPrivileges: []meta.MeasurementPrivilege{
+			{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+		},
+	}
+	for _, tt := range []struct {
+		auth query.FineAuthorizer
+		user string
+		err  bool
+	}{
+		{auth: nil},
+		{auth: query.OpenAuthorizer},
+		{auth: &meta.UserInfo{Name: "admin", Admin: true}},
+		{auth: restricted, user: "reader"},
+		{auth: restricted.ForRetentionPolicy("rp0"), user: "reader"},
+		{auth: closedAuthorizer{}, err: true},
+	} {
+		if user, err := restrictedUser(tt.auth); (err != nil) != tt.err || user != tt.user {
+			t.Errorf("%#v: unexpected user %q, error %v", tt.auth, user, err)
+		}
+	}
+}
+
+func TestForRetentionPolicies(t *testing.T) {
+	// The user may read every measurement of rp1 and cpu of every retention
+	// policy.
+	user := &meta.UserInfo{
+		Name: "reader",
+		RetentionPolicyPrivileges: []meta.RetentionPolicyPrivilege{
+			{Database: "db0", RetentionPolicy: "rp1", Privilege: cnosql.ReadPrivilege},
+		},
+		MeasurementPrivileges: []meta.MeasurementPrivilege{
+			{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+		},
+	}
+	di := &meta.DatabaseInfo{
+		Name:              "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}, {Name: "rp1"}},
+	}
+	for _, tt := range []struct {
+		name     string
+		auth     query.FineAuthorizer
+		cpu, mem bool
+	}{
+		{name: "user", auth: user, cpu: true},
+		{name: "rp0", auth: forRetentionPolicies(user, []string{"rp0"}), cpu: true},
+		{name: "rp1", auth: forRetentionPolicies(user, []string{"rp1"}), cpu: true, mem: true},
+		{name: "database", auth: databaseAuthorizer(user, di), cpu: true, mem: true},
+	} {
+		if cpu := tt.auth.AuthorizeSeriesRead("db0", []byte("cpu"), nil); cpu != tt.cpu {
+			t.Errorf("%s: unexpected cpu authorization: %v", tt.name, cpu)
+		}
+		if mem := tt.auth.AuthorizeSeriesRead("db0", []byte("mem"), nil); mem != tt.mem {
+			t.Errorf("%s: unexpected mem authorization: %v", tt.name, mem)
+		}
+	}
+}
+
+// errIteratorCreated is returned by authorizerShardGroup once it recorded
+// the authorizer of the iterator.
+var errIteratorCreated = errors.New("iterator created")
+
+// authorizerShardGroup records the authorizer its iterators are created with.
+type authorizerShardGroup struct {
+	tsdb.ShardGroup
+	auth query.FineAuthorizer
+}
+
+func (sg *authorizerShardGroup) CreateIterator(ctx context.Context, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	sg.auth = opt.Authorizer
+	return nil, errIteratorCreated
+}
+
+// serviceMetaClient looks up the users of a Service.
+type serviceMetaClient struct {
+	users map[string]meta.User
+}
+
+func (c *serviceMetaClient) ShardOwner(shardID uint64) (string, string, *meta.ShardGroupInfo) {
+	return "", "", nil
+}
+
+func (c *serviceMetaClient) User(name string) (meta.User, error) {
+	if u, ok := c.users[name]; ok {
+		return u, nil
+	}
+	return nil, meta.ErrUserNotFound
+}
+
+// closedAuthorizer is restricted without telling the user.
+type closedAuthorizer struct{}
+
+func (closedAuthorizer) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
+	return false
+}
+
+func (closedAuthorizer) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
+	return false
+}
+
+func (closedAuthorizer) IsOpen() bool { return false }
+
+func TestTaskManager_KillQuery_Draining(t *testing.T) {
+	killer := &blockingQueryKiller{
+		calls:   make(chan [3]uint64, 1),
+		release: make(chan struct{}),
+	}
+	tm := query.NewTaskManager()
+	tm.RemoteQueryKiller = killer
+	defer tm.Close()
+
+	stmt := cnosql.MustParseStatement(`SELECT value FROM db0.rp0.cpu`)
+	ctx, detach, err := tm.AttachQuery(&cnosql.Query{Statements: cnosql.Statements{stmt}}, query.ExecutionOptions{
+		Database: "db0",
+		NodeID:   1,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qid := query.AddQueryParticipant(ctx, 2); qid != ctx.QueryID {
+		t.Fatalf("unexpected query id: %d", qid)
+	}
+
+	status := func() query.TaskStatus {
+		for _, qi := range tm.Queries() {
+			if qi.ID == ctx.QueryID {
+				return qi.Status
+			}
+		}
+		return query.TaskStatus(0)
+	}
+
+	if err := tm.KillQuery(ctx.QueryID); err != nil {
+		t.Fatal(err)
+	}
+	if call := <-killer.calls; call != [3]uint64{2, 1, ctx.QueryID} {
+		t.Fatalf("unexpected kill: %v", call)
+	}
+	if s := status(); s != query.DrainingTask {
+		t.Fatalf("unexpected status: %s", s)
+	}
+
+	// The query stays listed until the participants acknowledged.
+	detach()
+	if s := status(); s != query.DrainingTask {
+		t.Fatalf("unexpected status after detach: %s", s)
+	}
+
+	close(killer.release)
+	deadline := time.Now().Add(time.Second)
+	for status() != query.TaskStatus(0) {
+		if time.Now().After(deadline) {
+			t.Fatalf("query still listed after draining: %s", status())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockingQueryKiller records the kills and acknowledges them once released.
+type blockingQueryKiller struct {
+	calls   chan [3]uint64
+	release chan struct{}
+}
+
+func (k *blockingQueryKiller) KillRemoteQuery(nodeID, origin, qid uint64) error {
+	k.calls <- [3]uint64{nodeID, origin, qid}
+	<-k.release
+	return nil
+}
@@ -2,11 +2,13 @@ package coordinator
 
 import (
 	"context"
+	"errors"
 	"io"
 	"math/rand"
 	"net"
 	"time"
 
+	"github.com/cnosdb/cnosdb/meta"
 	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/query"
 	"github.com/cnosdb/cnosdb/vend/db/tsdb"
@@ -31,6 +33,12 @@ type LocalShardMapper struct {
 		Shards(ids []uint64) []*tsdb.Shard
 		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 	}
+
+	// HintedHandoff reports owners missing writes made through this node.
+	// Used by the quorum-read consistency, optional.
+	HintedHandoff interface {
+		Pending(ownerID uint64) bool
+	}
 }
 
 // MapShards maps the sources to the appropriate shards into an IteratorCreator.
@@ -44,14 +52,14 @@ func (e *LocalShardMapper) MapShards(sources cnosql.Sources, t cnosql.TimeRange,
 	tmax := time.Unix(0, t.MaxTimeNano())
 	a.MinTime, a.MaxTime = tmin, tmax
 	a.LocalNodeID = opt.NodeID
-	if err := e.mapShards(a, sources, tmin, tmax); err != nil {
+	if err := e.mapShards(a, sources, tmin, tmax, opt); err != nil {
 		return nil, err
 	}
 
 	return a, nil
 }
 
-func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Sources, tmin, tmax time.Time) error {
+func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Sources, tmin, tmax time.Time, opt query.SelectOptions) error {
 	for _, s := range sources {
 		switch s := s.(type) {
 		case *cnosql.Measurement:
@@ -78,7 +86,15 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Source
 				for _, g := range groups {
 					for _, si := range g.Shards {
 						var nodeID uint64
-						if si.OwnedBy(a.LocalNodeID) {
+						var fallbackNodeIDs []uint64
+						var stale bool
+						if opt.ReadConsistency == query.ReadConsistencyQuorum {
+							nodeIDs, lagging := e.readOwners(si, a.LocalNodeID)
+							if len(nodeIDs) == 0 {
+								continue
+							}
+							nodeID, fallbackNodeIDs, stale = nodeIDs[0], nodeIDs[1:], lagging
+						} else if si.OwnedBy(a.LocalNodeID) {
 							nodeID = a.LocalNodeID
 						} else if len(si.Owners) > 0 {
 							nodeID = si.Owners[rand.Intn(len(si.Owners))].NodeID
@@ -97,6 +113,9 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Source
 							}
 							remoteShardIDs := []uint64{si.ID}
 							remoteIC := newRemoteIteratorCreator(dialer, nodeID, remoteShardIDs)
+							remoteIC.localNodeID = a.LocalNodeID
+							remoteIC.fallbackNodeIDs = fallbackNodeIDs
+							remoteIC.stale = stale
 							a.RemoteICs[source] = append(a.RemoteICs[source], remoteIC)
 
 						}
@@ -113,7 +132,7 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Source
 				a.ShardMap[source] = e.TSDBStore.ShardGroup(shardIDs)
 			}
 		case *cnosql.SubQuery:
-			if err := e.mapShards(a, s.Statement.Sources, tmin, tmax); err != nil {
+			if err := e.mapShards(a, s.Statement.Sources, tmin, tmax, opt); err != nil {
 				return err
 			}
 		}
@@ -236,6 +255,18 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *cnosql.Measur
 		return nil, nil
 	}
 
+	// Users restricted to some retention policies only see the data of those,
+	// the others are left out of the results with a warning.
+	if !query.AuthorizerIsOpen(opt.Authorizer) {
+		if !authorizeRetentionPolicyRead(opt.Authorizer, m.Database, m.RetentionPolicy) {
+			addReadWarning(ctx, "not authorized to read retention policy %s.%s, its data is left out of the results", m.Database, m.RetentionPolicy)
+			return nil, nil
+		}
+		if a, ok := opt.Authorizer.(retentionPolicyAuthorizer); ok {
+			opt.Authorizer = a.ForRetentionPolicy(m.RetentionPolicy)
+		}
+	}
+
 	// Override the time constraints if they don't match each other.
 	if !a.MinTime.IsZero() && opt.StartTime < a.MinTime.UnixNano() {
 		opt.StartTime = a.MinTime.UnixNano()
@@ -298,6 +329,16 @@ type remoteIteratorCreator struct {
 	dialer   *NodeDialer
 	nodeID   uint64
 	shardIDs []uint64
+
+	// Owners to read from, in order, if nodeID cannot be read.
+	fallbackNodeIDs []uint64
+
+	// Set if nodeID is known to be missing recent writes.
+	stale bool
+
+	// The node the query runs on, sent with the requests so that the
+	// iterators can be killed with the query.
+	localNodeID uint64
 }
 
 // newRemoteIteratorCreator returns a new instance of remoteIteratorCreator for a remote shard.
@@ -309,9 +350,37 @@ func newRemoteIteratorCreator(dialer *NodeDialer, nodeID uint64, shardIDs []uint
 	}
 }
 
-// CreateIterator creates a remote streaming iterator.
+// CreateIterator creates a remote streaming iterator. If the preferred owner
+// cannot be read, the fallback owners are tried in order and a warning is
+// added to ctx.
 func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
-	conn, err := ic.dialer.DialNode(ic.nodeID)
+	if ic.stale {
+		addReadWarning(ctx, "shard %s: every owner is missing writes queued in hinted handoff, results may be stale", joinUint64(ic.shardIDs))
+	}
+
+	itr, err := ic.createIterator(ctx, ic.nodeID, m, opt)
+	if err == nil || len(ic.fallbackNodeIDs) == 0 {
+		return itr, err
+	}
+
+	for _, nodeID := range ic.fallbackNodeIDs {
+		if itr, err = ic.createIterator(ctx, nodeID, m, opt); err == nil {
+			addReadWarning(ctx, "shard %s: owner node %d unreachable, read from node %d instead, results may be stale", joinUint64(ic.shardIDs), ic.nodeID, nodeID)
+			return itr, nil
+		}
+	}
+	addReadWarning(ctx, "shard %s: no owner could be read, results are incomplete: %s", joinUint64(ic.shardIDs), err)
+	return nil, err
+}
+
+// createIterator creates a remote streaming iterator on node nodeID.
+func (ic *remoteIteratorCreator) createIterator(ctx context.Context, nodeID uint64, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	user, err := restrictedUser(opt.Authorizer)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := ic.dialer.DialNode(nodeID)
 	if err != nil {
 		return nil, err
 	}
@@ -323,6 +392,9 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.M
 			ShardIDs:    ic.shardIDs,
 			Measurement: *(m.Clone()),
 			Opt:         opt,
+			NodeID:      ic.localNodeID,
+			QueryID:     query.AddQueryParticipant(ctx, nodeID),
+			User:        user,
 		}
 		if err := EncodeTLV(conn, createIteratorRequestMessage, &req); err != nil {
 			return err
@@ -332,7 +404,7 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.M
 		if _, err := DecodeTLV(conn, &resp); err != nil {
 			return err
 		} else if resp.Err != nil {
-			return err
+			return resp.Err
 		}
 
 		return nil
@@ -368,6 +440,35 @@ func (ic *remoteIteratorCreator) FieldDimensions(m *cnosql.Measurement) (fields
 	return resp.Fields, resp.Dimensions, resp.Err
 }
 
+// RemoteQueryKiller kills the iterators other nodes create for the queries
+// of this node.
+type RemoteQueryKiller struct {
+	Dialer *NodeDialer
+}
+
+// KillRemoteQuery kills the iterators node nodeID created for the query qid
+// of node origin and waits for them to stop.
+func (k *RemoteQueryKiller) KillRemoteQuery(nodeID, origin, qid uint64) error {
+	conn, err := k.Dialer.DialNode(nodeID)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := EncodeTLV(conn, killQueryRequestMessage, &KillQueryRequest{
+		NodeID:  origin,
+		QueryID: qid,
+	}); err != nil {
+		return err
+	}
+
+	var resp KillQueryResponse
+	if _, err := DecodeTLV(conn, &resp); err != nil {
+		return err
+	}
+	return resp.Err
+}
+
 // NodeDialer dials connections to a given node.
 type NodeDialer struct {
 	MetaClient MetaClient
@@ -441,3 +542,55 @@ type Source struct {
 	Database string
 	RetentionPolicy string
 }
+
+// retentionPolicyAuthorizer is implemented by authorizers of users whose
+// privileges may be restricted to some retention policies of a database.
+type retentionPolicyAuthorizer interface {
+	AuthorizeRetentionPolicyRead(database, rp string) bool
+	ForRetentionPolicy(rp string) query.FineAuthorizer
+	ForRetentionPolicies(rps []string) query.FineAuthorizer
+}
+
+// forRetentionPolicies returns the fine authorizer of auth for series of
+// the retention policies rps. Privileges granted on other retention policies
+// don't apply.
+func forRetentionPolicies(auth query.FineAuthorizer, rps []string) query.FineAuthorizer {
+	if a, ok := auth.(retentionPolicyAuthorizer); ok && !query.AuthorizerIsOpen(auth) {
+		return a.ForRetentionPolicies(rps)
+	}
+	return auth
+}
+
+// databaseAuthorizer returns the fine authorizer of auth for series of any
+// retention policy of the database di.
+func databaseAuthorizer(auth query.FineAuthorizer, di *meta.DatabaseInfo) query.FineAuthorizer {
+	rps := make([]string, 0, len(di.RetentionPolicies))
+	for _, rpi := range di.RetentionPolicies {
+		rps = append(rps, rpi.Name)
+	}
+	return forRetentionPolicies(auth, rps)
+}
+
+// authorizeRetentionPolicyRead returns true if auth may read some data of the
+// retention policy rp of the given database.
+func authorizeRetentionPolicyRead(auth query.FineAuthorizer, database, rp string) bool {
+	if query.AuthorizerIsOpen(auth) {
+		return true
+	}
+	a, ok := auth.(retentionPolicyAuthorizer)
+	return !ok || a.AuthorizeRetentionPolicyRead(database, rp)
+}
+
+// restrictedUser returns the name of the user of auth if its privileges are
+// restricted to some retention policies or measurements, so a remote node
+// applies them as well, or an empty string if auth is open.
+func restrictedUser(auth query.FineAuthorizer) (string, error) {
+	if query.AuthorizerIsOpen(auth) {
+		return "", nil
+	}
+	u, ok := auth.(interface{ ID() string })
+	if !ok {
+		return "", errors.New("cannot read remote shards with the privileges of an unknown user")
+	}
+	return u.ID(), nil
+}
@@ -22,6 +22,9 @@ const (
 
 	fieldDimensionsRequestMessage
 	fieldDimensionsResponseMessage
+
+	killQueryRequestMessage
+	killQueryResponseMessage
 )
 
 // ShardWriter writes a set of points to a shard.
@@ -0,0 +1,218 @@
+package coordinator
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+	"go.uber.org/zap"
+)
+
+// SlowQueryMeasurement is the measurement slow query records are written to.
+const SlowQueryMeasurement = "slow_queries"
+
+// SlowQueryRecord is the record of a statement that ran longer than the
+// slow query threshold.
+type SlowQueryRecord struct {
+	Time      time.Time     `json:"time"`
+	User      string        `json:"user"`
+	Database  string        `json:"database,omitempty"`
+	Type      string        `json:"type"`
+	Statement string        `json:"statement"`
+	Duration  time.Duration `json:"duration_ns"`
+
+	// Series and points read by the iterators of a SELECT.
+	SeriesN int `json:"series"`
+	PointN  int `json:"points"`
+
+	// Name of the limit the statement hit, e.g. "max-select-point".
+	Limit string `json:"limit,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// newSlowQueryRecord returns the record of stmt executed in ctx for d with
+// the result err.
+func newSlowQueryRecord(ctx *query.ExecutionContext, stmt cnosql.Statement, d time.Duration, err error) *SlowQueryRecord {
+	stats := ctx.IteratorStats()
+	r := &SlowQueryRecord{
+		Time:      time.Now().UTC(),
+		User:      ctx.UserID,
+		Database:  ctx.Database,
+		Type:      statementTypeName(stmt),
+		Statement: stmt.String(),
+		Duration:  d,
+		SeriesN:   stats.SeriesN,
+		PointN:    stats.PointN,
+	}
+	if err != nil {
+		r.Limit, r.Error = exceededLimit(err), err.Error()
+	}
+	return r
+}
+
+// exceededLimit returns the name of the limit err reports as exceeded, or
+// an empty string if err is not caused by a limit.
+func exceededLimit(err error) string {
+	switch err := err.(type) {
+	case *query.MemoryBudgetExceededError:
+		return "max-select-memory"
+	case *query.RateLimitError:
+		return err.Limit
+	case *query.ServerBusyError:
+		return "max-concurrent-selects"
+	}
+	if err == query.ErrQueryTimeoutLimitExceeded {
+		return "query-timeout"
+	}
+
+	// The SELECT limits are reported as "<limit> limit exceeded: (n/max)".
+	msg := err.Error()
+	if strings.HasPrefix(msg, "max-select-") {
+		if i := strings.Index(msg, " limit exce"); i > 0 {
+			return msg[:i]
+		}
+	} else if strings.HasPrefix(msg, "query timeout exceeded") {
+		return "timeout"
+	}
+	return ""
+}
+
+// SlowQuerySink is a destination for slow query records.
+type SlowQuerySink interface {
+	WriteSlowQueryRecord(r *SlowQueryRecord) error
+}
+
+// SlowQueryLog records statements running longer than a threshold.
+type SlowQueryLog struct {
+	// Counts the slow statements for sampling. Updated atomically.
+	slowN uint64
+
+	// Statements running at least this long are recorded.
+	Threshold time.Duration
+
+	// Only one in SampleN slow statements is recorded. Below 2 all are.
+	SampleN int
+
+	Sinks  []SlowQuerySink
+	Logger *zap.Logger
+}
+
+// NewSlowQueryLog returns a new instance of SlowQueryLog writing one in
+// sampleN statements running at least threshold to sinks.
+func NewSlowQueryLog(threshold time.Duration, sampleN int, sinks ...SlowQuerySink) *SlowQueryLog {
+	return &SlowQueryLog{
+		Threshold: threshold,
+		SampleN:   sampleN,
+		Sinks:     sinks,
+		Logger:    zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the slow query log.
+func (l *SlowQueryLog) WithLogger(log *zap.Logger) {
+	l.Logger = log.With(zap.String("service", "slow-queries"))
+}
+
+// Observe records stmt executed in ctx if it ran for at least the threshold
+// and is sampled.
+func (l *SlowQueryLog) Observe(ctx *query.ExecutionContext, stmt cnosql.Statement, d time.Duration, err error) {
+	if d < l.Threshold {
+		return
+	}
+	if n := atomic.AddUint64(&l.slowN, 1); l.SampleN > 1 && (n-1)%uint64(l.SampleN) != 0 {
+		return
+	}
+	l.Record(newSlowQueryRecord(ctx, stmt, d, err))
+}
+
+// Record writes r to every sink. A failing sink is logged and does not
+// prevent the record from reaching the others.
+func (l *SlowQueryLog) Record(r *SlowQueryRecord) {
+	for _, s := range l.Sinks {
+		if err := s.WriteSlowQueryRecord(r); err != nil {
+			l.Logger.Warn("Failed to write slow query record",
+				zap.String("type", r.Type),
+				zap.Error(err))
+		}
+	}
+}
+
+// Close closes the sinks of the slow query log that hold resources.
+func (l *SlowQueryLog) Close() error {
+	var err error
+	for _, s := range l.Sinks {
+		if c, ok := s.(io.Closer); ok {
+			if e := c.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// SlowQueryFile writes slow query records as JSON lines to a file rotated
+// by size.
+type SlowQueryFile struct {
+	f *jsonLinesFile
+}
+
+// NewSlowQueryFile returns a SlowQueryFile writing to path. The file is
+// rotated when it reaches maxSize megabytes; at most maxBackups rotated
+// files are kept for at most maxAge days.
+func NewSlowQueryFile(path string, maxSize, maxBackups, maxAge int) *SlowQueryFile {
+	return &SlowQueryFile{f: newJSONLinesFile(path, maxSize, maxBackups, maxAge)}
+}
+
+// WriteSlowQueryRecord appends r to the file.
+func (f *SlowQueryFile) WriteSlowQueryRecord(r *SlowQueryRecord) error {
+	return f.f.write(r)
+}
+
+// Close closes the file.
+func (f *SlowQueryFile) Close() error {
+	return f.f.Close()
+}
+
+// SlowQueryPoints writes slow query records as points of the slow_queries
+// measurement.
+type SlowQueryPoints struct {
+	PointsWriter interface {
+		WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+	}
+	Database        string
+	RetentionPolicy string
+}
+
+// WriteSlowQueryRecord writes r as a single point.
+func (p *SlowQueryPoints) WriteSlowQueryRecord(r *SlowQueryRecord) error {
+	tags := map[string]string{"type": r.Type}
+	// Tag values cannot be empty, e.g. when authentication is disabled.
+	if r.User != "" {
+		tags["user"] = r.User
+	}
+	if r.Limit != "" {
+		tags["limit"] = r.Limit
+	}
+	fields := map[string]interface{}{
+		"statement":   r.Statement,
+		"duration_ns": int64(r.Duration),
+		"series":      int64(r.SeriesN),
+		"points":      int64(r.PointN),
+	}
+	if r.Database != "" {
+		fields["database"] = r.Database
+	}
+	if r.Error != "" {
+		fields["error"] = r.Error
+	}
+
+	pt, err := models.NewPoint(SlowQueryMeasurement, models.NewTags(tags), fields, r.Time)
+	if err != nil {
+		return err
+	}
+	return p.PointsWriter.WritePointsPrivileged(p.Database, p.RetentionPolicy, models.ConsistencyLevelAny, []models.Point{pt})
+}
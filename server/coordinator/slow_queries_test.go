@@ -0,0 +1,94 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+func TestSlowQueryLog_Observe(t *testing.T) {
+	var sink recordingSlowQuerySink
+	l := NewSlowQueryLog(time.Second, 3, &sink)
+
+	ctx := &query.ExecutionContext{Context: context.Background()}
+	stmt := cnosql.MustParseStatement(`SHOW DATABASES`)
+
+	l.Observe(ctx, stmt, time.Millisecond, nil)
+	for i := 0; i < 7; i++ {
+		l.Observe(ctx, stmt, time.Duration(i+1)*time.Second, nil)
+	}
+
+	// Fast statements are ignored, one in three slow statements is recorded.
+	var durations []time.Duration
+	for _, r := range sink.records {
+		durations = append(durations, r.Duration)
+	}
+	if exp := []time.Duration{time.Second, 4 * time.Second, 7 * time.Second}; len(durations) != len(exp) ||
+		durations[0] != exp[0] || durations[1] != exp[1] || durations[2] != exp[2] {
+		t.Fatalf("unexpected recorded durations: %v", durations)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SlowQueryLog(t *testing.T) {
+	var sink recordingSlowQuerySink
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			UserFn: func(name string) (meta.User, error) { return nil, meta.ErrUserNotFound },
+		},
+		ShardMapper:          &endlessShardMapper{},
+		MaxSelectMemoryBytes: 1,
+		SlowQueryLog:         NewSlowQueryLog(time.Nanosecond, 1, &sink),
+	}
+
+	_, err := executeStatement(e, `SELECT value FROM db0.rp0.cpu`, query.ExecutionOptions{Database: "db0", UserID: "alice", UserAdmin: true, ChunkSize: 10})
+	if _, ok := err.(*query.MemoryBudgetExceededError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("unexpected records: %d", len(sink.records))
+	}
+	r := sink.records[0]
+	if r.User != "alice" || r.Database != "db0" || r.Type != "SelectStatement" || r.Statement != `SELECT value FROM db0.rp0.cpu` {
+		t.Fatalf("unexpected record: %+v", r)
+	} else if r.Duration <= 0 || r.SeriesN != 1 || r.PointN == 0 {
+		t.Fatalf("unexpected stats: %+v", r)
+	} else if r.Limit != "max-select-memory" || r.Error != err.Error() {
+		t.Fatalf("unexpected limit: %+v", r)
+	}
+}
+
+func TestExceededLimit(t *testing.T) {
+	for _, tt := range []struct {
+		err error
+		exp string
+	}{
+		{err: &query.MemoryBudgetExceededError{Bytes: 2, Limit: 1}, exp: "max-select-memory"},
+		{err: &query.RateLimitError{Limit: "max-queries-per-minute"}, exp: "max-queries-per-minute"},
+		{err: &query.ServerBusyError{Limit: 1}, exp: "max-concurrent-selects"},
+		{err: query.ErrMaxSelectPointsLimitExceeded(2, 1), exp: "max-select-point"},
+		{err: errors.New("max-select-series limit exceeded: (2/1)"), exp: "max-select-series"},
+		{err: query.ErrQueryTimeoutLimitExceeded, exp: "query-timeout"},
+		{err: query.ErrStatementTimeoutExceeded(time.Second), exp: "timeout"},
+		{err: errors.New("database not found: db0"), exp: ""},
+	} {
+		if got := exceededLimit(tt.err); got != tt.exp {
+			t.Errorf("exceededLimit(%q) = %q, expected %q", tt.err, got, tt.exp)
+		}
+	}
+}
+
+// recordingSlowQuerySink keeps the records written to it.
+type recordingSlowQuerySink struct {
+	records []*SlowQueryRecord
+}
+
+func (s *recordingSlowQuerySink) WriteSlowQueryRecord(r *SlowQueryRecord) error {
+	s.records = append(s.records, r)
+	return nil
+}
@@ -3,19 +3,29 @@ package coordinator
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cnosdb/cnosdb"
 	"github.com/cnosdb/cnosdb/meta"
 	"github.com/cnosdb/cnosdb/monitor"
+	"github.com/cnosdb/cnosdb/pkg/kafka"
+	"github.com/cnosdb/cnosdb/pkg/mqtt"
 	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/common/monitor/diagnostics"
 	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/limiter"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/tracing/fields"
 	"github.com/cnosdb/cnosdb/vend/db/query"
@@ -32,6 +42,16 @@ type pointsWriter interface {
 
 // StatementExecutor executes a statement in the query.
 type StatementExecutor struct {
+	// Live counters reported by Diagnostics. Updated atomically and kept
+	// first for 64-bit alignment.
+	runningSelects int64
+	bufferedPoints int64
+	throttledInto  int64
+
+	// Totals of SELECT INTO points reported by Statistics. Updated atomically.
+	intoPointsWritten int64
+	intoPointsDropped int64
+
 	MetaClient MetaClient
 
 	// TaskManager holds the StatementExecutor that handles task-related commands.
@@ -51,14 +71,151 @@ type StatementExecutor struct {
 		WritePointsInto(*IntoWriteRequest) error
 	}
 
+	// Reports per retention policy write traffic for SHOW RETENTION POLICIES.
+	WriteStats RetentionPolicyWriteStatser
+
+	// Reports the last execution of continuous queries for SHOW CONTINUOUS QUERIES.
+	ContinuousQueryRuns ContinuousQueryRunStatser
+
+	// Set if the retention service runs and removes the shards of deleted
+	// shard groups once the deletion delay of their retention policy has
+	// passed. Otherwise shards are removed along with their shard group.
+	DeferShardRemoval bool
+
+	// Maximum number of SELECT statements running at once, zero is
+	// unlimited. Up to MaxQueuedSelects more wait for at most
+	// MaxSelectQueueTime, others are rejected as the server is busy.
+	// Waiting statements run in order of priority, and a high priority
+	// one kills the oldest running low priority one if PreemptLowPriority
+	// is set.
+	MaxConcurrentSelects int
+	MaxQueuedSelects     int
+	MaxSelectQueueTime   time.Duration
+	PreemptLowPriority   bool
+
 	// Select statement limits
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
 	MaxSelectBucketsN int
+
+	// Maximum approximate bytes a SELECT holds in the row being emitted and
+	// its SELECT INTO buffers. Zero is unlimited.
+	MaxSelectMemoryBytes int64
+
+	// Number of points a SELECT INTO buffers before writing them. Zero uses
+	// DefaultIntoWriteBatchSize.
+	IntoWriteBatchSize int
+
+	// How often a SELECT INTO writes out its buffered points even if the
+	// buffer is not full. Zero only writes full buffers.
+	IntoFlushInterval time.Duration
+
+	// Number of batches a SELECT INTO writes concurrently. Below 2 batches
+	// are written synchronously.
+	IntoWriteConcurrency int
+
+	// Maximum points per second a SELECT INTO writes. Zero is unlimited.
+	IntoWriteRateLimit int
+
+	// Statements running at least this long have Go runtime activity
+	// attributed to them in SHOW STATS. Zero disables sampling.
+	RuntimeStatsThreshold time.Duration
+
+	// Names of deprecation notices that are returned as errors instead of warnings.
+	EscalatedNotices []string
+
+	// Serializes destructive operations on overlapping objects. Optional.
+	OperationLocks *OperationLocks
+
+	// Records DDL and user management statements. Optional.
+	AuditLog *AuditLog
+
+	// Records statements running longer than its threshold. Optional.
+	SlowQueryLog *SlowQueryLog
+
+	// Called around the execution of every statement, in order. Statements
+	// rewritten by a hook are executed without being authorized again.
+	StatementHooks []StatementHook
+
+	runtimeStats runtimeStatistics
+	userQueries  userQueryLimiter
+	admission    selectAdmission
+	notices      sentNotices
+}
+
+// The keys for statistics generated by the "select_into" module.
+const (
+	statIntoPointsWritten = "pointsWritten" // Points SELECT INTO statements passed to the target.
+	statIntoPointsDropped = "pointsDropped" // Points SELECT INTO statements could not convert and dropped.
+)
+
+// Statistics returns the SELECT INTO totals and the Go runtime statistics
+// attributed to slow statements.
+func (e *StatementExecutor) Statistics(tags map[string]string) []models.Statistic {
+	statistics := []models.Statistic{{
+		Name: "select_into",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statIntoPointsWritten: atomic.LoadInt64(&e.intoPointsWritten),
+			statIntoPointsDropped: atomic.LoadInt64(&e.intoPointsDropped),
+		},
+	}}
+	statistics = append(statistics, e.runtimeStats.Statistics(tags)...)
+	statistics = append(statistics, e.admission.Statistics(tags)...)
+	return append(statistics, e.userQueries.Statistics(tags)...)
+}
+
+// Diagnostics returns the SELECT limits and the live state of the executor.
+func (e *StatementExecutor) Diagnostics() (*diagnostics.Diagnostics, error) {
+	_, queued := e.admission.counts()
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"max-select-point":      e.MaxSelectPointN,
+		"max-select-series":     e.MaxSelectSeriesN,
+		"max-select-buckets":    e.MaxSelectBucketsN,
+		"max-select-memory":     e.MaxSelectMemoryBytes,
+		"into-write-batch-size": e.intoWriteBatchSize(0),
+		"into-write-rate-limit": e.IntoWriteRateLimit,
+		"running-selects":       atomic.LoadInt64(&e.runningSelects),
+		"queued-selects":        queued,
+		"buffered-points":       atomic.LoadInt64(&e.bufferedPoints),
+		"throttled-into":        atomic.LoadInt64(&e.throttledInto),
+	}), nil
 }
 
 // ExecuteStatement executes the given statement with the given execution context.
 func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt cnosql.Statement) error {
+	if e.RuntimeStatsThreshold > 0 {
+		sample := e.runtimeStats.begin()
+		defer e.runtimeStats.end(sample, stmt, ctx.Database, e.RuntimeStatsThreshold)
+	}
+
+	if e.SlowQueryLog != nil {
+		ctx.SetIteratorStats(query.IteratorStats{})
+	}
+	start := time.Now()
+
+	stmt, hooks, err := e.beforeHooks(ctx, stmt)
+	if err == nil {
+		err = e.executeStatement(ctx, stmt)
+	}
+	afterHooks(ctx, hooks, stmt, err, time.Since(start))
+
+	if e.AuditLog != nil && isAuditedStatement(stmt) {
+		e.AuditLog.Record(newAuditRecord(ctx, stmt, err))
+	}
+	if e.SlowQueryLog != nil {
+		e.SlowQueryLog.Observe(ctx, stmt, time.Since(start), err)
+	}
+	return err
+}
+
+func (e *StatementExecutor) executeStatement(ctx *query.ExecutionContext, stmt cnosql.Statement) error {
+	// In a strict read-only context, statements changing data or metadata
+	// are refused rather than executed with a warning.
+	if ctx.ReadOnly && ctx.StrictReadOnly && cnosql.IsMutatingStatement(stmt) {
+		return query.ReadOnlyError(stmt.String())
+	}
+
 	// Select statements are handled separately so that they can be streamed.
 	if stmt, ok := stmt.(*cnosql.SelectStatement); ok {
 		return e.executeSelectStatement(ctx, stmt)
@@ -68,16 +225,41 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 	var messages []*query.Message
 	var err error
 	switch stmt := stmt.(type) {
+	case *cnosql.AlterContinuousQueryStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterContinuousQueryStatement(stmt)
+	case *cnosql.AlterDatabaseStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterDatabaseStatement(stmt)
 	case *cnosql.AlterRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterRetentionPolicyStatement(stmt)
+	case *cnosql.AlterUserStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterUserStatement(stmt)
+	case *cnosql.AlterUserQueryLimitStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterUserQueryLimitStatement(stmt)
+	case *cnosql.AlterUserDefaultDatabaseStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterUserDefaultDatabaseStatement(stmt)
 	case *cnosql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeCreateContinuousQueryStatement(stmt)
+		err = e.executeCreateContinuousQueryStatement(ctx, stmt)
 	case *cnosql.CreateDatabaseStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -98,6 +280,16 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateUserStatement(stmt)
+	case *cnosql.CreateRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateRoleStatement(stmt)
+	case *cnosql.CreateTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executeCreateTokenStatement(ctx, stmt)
 	case *cnosql.DeleteSeriesStatement:
 		err = e.executeDeleteSeriesStatement(stmt, ctx.Database)
 	case *cnosql.DropContinuousQueryStatement:
@@ -109,7 +301,11 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeDropDatabaseStatement(stmt)
+		var msg *query.Message
+		msg, err = e.executeDropDatabaseStatement(stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
 	case *cnosql.DropMeasurementStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -119,12 +315,20 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeDropSeriesStatement(stmt, ctx.Database)
+		var msg *query.Message
+		msg, err = e.executeDropSeriesStatement(ctx, stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
 	case *cnosql.DropRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeDropRetentionPolicyStatement(stmt)
+		var msg *query.Message
+		msg, err = e.executeDropRetentionPolicyStatement(stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
 	case *cnosql.DropShardStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -140,8 +344,15 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropUserStatement(stmt)
+	case *cnosql.DropRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropRoleStatement(stmt)
 	case *cnosql.ExplainStatement:
-		if stmt.Analyze {
+		if stmt.SeriesStatement != nil {
+			rows, err = e.executeExplainSeriesStatement(ctx, stmt)
+		} else if stmt.Analyze {
 			rows, err = e.executeExplainAnalyzeStatement(ctx, stmt)
 		} else {
 			rows, err = e.executeExplainStatement(ctx, stmt)
@@ -150,22 +361,55 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeGrantStatement(stmt)
+		var msg *query.Message
+		rows, msg, err = e.executeGrantStatement(stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
 	case *cnosql.GrantAdminStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeGrantAdminStatement(stmt)
+	case *cnosql.GrantToRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeGrantToRoleStatement(stmt)
+	case *cnosql.GrantRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeGrantRoleStatement(stmt)
 	case *cnosql.RevokeStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeRevokeStatement(stmt)
+		var msg *query.Message
+		rows, msg, err = e.executeRevokeStatement(stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
 	case *cnosql.RevokeAdminStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeRevokeAdminStatement(stmt)
+	case *cnosql.RevokeFromRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeFromRoleStatement(stmt)
+	case *cnosql.RevokeRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeRoleStatement(stmt)
+	case *cnosql.RevokeTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeTokenStatement(ctx, stmt)
 	case *cnosql.ShowContinuousQueriesStatement:
 		rows, err = e.executeShowContinuousQueriesStatement(stmt)
 	case *cnosql.ShowDatabasesStatement:
@@ -174,34 +418,80 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 		rows, err = e.executeShowDiagnosticsStatement(stmt)
 	case *cnosql.ShowGrantsForUserStatement:
 		rows, err = e.executeShowGrantsForUserStatement(stmt)
+	case *cnosql.ShowGrantsForRoleStatement:
+		rows, err = e.executeShowGrantsForRoleStatement(stmt)
 	case *cnosql.ShowMeasurementsStatement:
 		return e.executeShowMeasurementsStatement(ctx, stmt)
+	case *cnosql.ShowFieldKeyCardinalityStatement:
+		rows, err = e.executeShowFieldKeyCardinalityStatement(ctx, stmt)
 	case *cnosql.ShowMeasurementCardinalityStatement:
 		rows, err = e.executeShowMeasurementCardinalityStatement(ctx, stmt)
+	case *cnosql.ShowOperationsStatement:
+		rows, err = e.executeShowOperationsStatement()
 	case *cnosql.ShowRetentionPoliciesStatement:
-		rows, err = e.executeShowRetentionPoliciesStatement(stmt)
+		rows, messages, err = e.executeShowRetentionPoliciesStatement(stmt)
 	case *cnosql.ShowSeriesCardinalityStatement:
 		rows, err = e.executeShowSeriesCardinalityStatement(ctx, stmt)
+	case *cnosql.ShowTagKeyCardinalityStatement:
+		rows, err = e.executeShowTagKeyCardinalityStatement(ctx, stmt)
+	case *cnosql.ShowTagValuesCardinalityStatement:
+		rows, err = e.executeShowTagValuesCardinalityStatement(ctx, stmt)
 	case *cnosql.ShowShardsStatement:
 		rows, err = e.executeShowShardsStatement(stmt)
 	case *cnosql.ShowShardGroupsStatement:
 		rows, err = e.executeShowShardGroupsStatement(stmt)
 	case *cnosql.ShowStatsStatement:
-		rows, err = e.executeShowStatsStatement(stmt)
+		rows, err = e.executeShowStatsStatement(ctx, stmt)
 	case *cnosql.ShowSubscriptionsStatement:
 		rows, err = e.executeShowSubscriptionsStatement(stmt)
+	case *cnosql.ShowFieldKeysStatement:
+		return e.executeShowFieldKeys(ctx, stmt)
+	case *cnosql.ShowSeriesStatement:
+		return e.executeShowSeries(ctx, stmt)
 	case *cnosql.ShowTagKeysStatement:
 		return e.executeShowTagKeys(ctx, stmt)
 	case *cnosql.ShowTagValuesStatement:
 		return e.executeShowTagValues(ctx, stmt)
 	case *cnosql.ShowUsersStatement:
 		rows, err = e.executeShowUsersStatement(stmt)
+	case *cnosql.ShowRolesStatement:
+		rows, err = e.executeShowRolesStatement(stmt)
+	case *cnosql.ShowTokensStatement:
+		rows, err = e.executeShowTokensStatement(ctx, stmt)
 	case *cnosql.SetPasswordUserStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeSetPasswordUserStatement(stmt)
-	case *cnosql.ShowQueriesStatement, *cnosql.KillQueryStatement:
+	case *cnosql.TruncateShardsStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeTruncateShardsStatement(stmt)
+	case *cnosql.PurgeDataStatement:
+		// Purging can't be undone, so unlike other writes it is refused
+		// rather than warned about in a read-only context.
+		if ctx.ReadOnly {
+			return fmt.Errorf("%s cannot be executed in a read-only context", stmt.String())
+		}
+		rows, err = e.executePurgeDataStatement(stmt, ctx.Database)
+	case *cnosql.PrecreateShardGroupsStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executePrecreateShardGroupsStatement(stmt)
+	case *cnosql.UndropShardGroupStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		var msg *query.Message
+		msg, err = e.executeUndropShardGroupStatement(stmt)
+		if msg != nil {
+			messages = append(messages, msg)
+		}
+	case *cnosql.RunContinuousQueryStatement:
+		return e.executeRunContinuousQueryStatement(ctx, stmt)
+	case *cnosql.ShowQueriesStatement, *cnosql.KillQueryStatement, *cnosql.ShowSessionsStatement, *cnosql.KillSessionStatement:
 		// Send query related statements to the task manager.
 		return e.TaskManager.ExecuteStatement(ctx, stmt)
 	default:
@@ -218,25 +508,94 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 	})
 }
 
+func (e *StatementExecutor) executeAlterDatabaseStatement(stmt *cnosql.AlterDatabaseStatement) error {
+	if stmt.IndexType != nil {
+		if err := validateIndexType(*stmt.IndexType); err != nil {
+			return err
+		}
+	}
+
+	du := &meta.DatabaseUpdate{
+		MaxSeriesN:      stmt.MaxSeriesN,
+		MaxValuesPerTag: stmt.MaxValuesPerTag,
+		IndexType:       stmt.IndexType,
+	}
+	return e.MetaClient.UpdateDatabase(stmt.Name, du)
+}
+
+// validateIndexType returns an error if index is not empty and is not the
+// name of a registered index. An empty index selects the server's default.
+func validateIndexType(index string) error {
+	if index == "" {
+		return nil
+	}
+	indexes := tsdb.RegisteredIndexes()
+	for _, name := range indexes {
+		if name == index {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown index %q; expected one of %s", index, strings.Join(indexes, ", "))
+}
+
 func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *cnosql.AlterRetentionPolicyStatement) error {
+	// Validate the durations the policy will have after the update. A
+	// missing policy is reported by the meta client below.
+	rpi, err := e.MetaClient.RetentionPolicy(stmt.Database, stmt.Name)
+	if err != nil {
+		return err
+	} else if rpi != nil {
+		duration, sgDuration := rpi.Duration, rpi.ShardGroupDuration
+		if stmt.Duration != nil {
+			duration = *stmt.Duration
+		}
+		if stmt.ShardGroupDuration != nil {
+			sgDuration = *stmt.ShardGroupDuration
+		}
+		if err := validateRetentionPolicyDurations(duration, sgDuration); err != nil {
+			return err
+		}
+	}
+
 	rpu := &meta.RetentionPolicyUpdate{
 		Duration:           stmt.Duration,
 		ReplicaN:           stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		FutureWriteLimit:   stmt.FutureWriteLimit,
+		PastWriteLimit:     stmt.PastWriteLimit,
+		ColdAfter:          stmt.ColdAfter,
+		Placement:          stmt.Placement,
+		DeletionDelay:      stmt.DeletionDelay,
+		MaxQueryRange:      stmt.MaxQueryRange,
 	}
 
 	// Update the retention policy.
 	return e.MetaClient.UpdateRetentionPolicy(stmt.Database, stmt.Name, rpu, stmt.Default)
 }
 
-func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *cnosql.CreateContinuousQueryStatement) error {
-	// Verify that retention policies exist.
+func (e *StatementExecutor) executeCreateContinuousQueryStatement(ctx *query.ExecutionContext, q *cnosql.CreateContinuousQueryStatement) error {
+	// Verify that the RESAMPLE clause covers the GROUP BY interval.
+	if err := q.Validate(); err != nil {
+		return err
+	}
+
+	// Verify that retention policies exist and that the user may write into
+	// the target, since the query runs with server privileges later on.
 	var err error
 	verifyRPFn := func(n cnosql.Node) {
 		if err != nil {
 			return
 		}
 		switch m := n.(type) {
+		case *cnosql.Target:
+			target := m
+			if target.Measurement.Database == "" {
+				target = &cnosql.Target{
+					Measurement: &cnosql.Measurement{Database: q.Database, Name: m.Measurement.Name},
+					Create:      m.Create,
+				}
+			}
+			err = authorizeTarget(ctx, target)
 		case *cnosql.Measurement:
 			var rp *meta.RetentionPolicyInfo
 			if rp, err = e.MetaClient.RetentionPolicy(m.Database, m.RetentionPolicy); err != nil {
@@ -255,7 +614,158 @@ func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *cnosql.Crea
 		return err
 	}
 
-	return e.MetaClient.CreateContinuousQuery(q.Database, q.Name, q.String())
+	return e.MetaClient.CreateContinuousQuery(q.Database, q.Name, q.String(), ctx.UserID)
+}
+
+// continuousQuery returns the parsed statement of the named continuous query.
+func (e *StatementExecutor) continuousQuery(database, name string) (*cnosql.CreateContinuousQueryStatement, error) {
+	dbi := e.MetaClient.Database(database)
+	if dbi == nil {
+		return nil, query.ErrDatabaseNotFound(database)
+	}
+
+	for _, cqi := range dbi.ContinuousQueries {
+		if cqi.Name != name {
+			continue
+		}
+		q, err := cnosql.ParseStatement(cqi.Query)
+		if err != nil {
+			return nil, err
+		}
+		cq, ok := q.(*cnosql.CreateContinuousQueryStatement)
+		if !ok {
+			return nil, fmt.Errorf("invalid continuous query %q: %s", cqi.Name, cqi.Query)
+		}
+		return cq, nil
+	}
+	return nil, meta.ErrContinuousQueryNotFound
+}
+
+// executeAlterContinuousQueryStatement changes the execution offset of a
+// continuous query by rewriting its stored CREATE statement.
+func (e *StatementExecutor) executeAlterContinuousQueryStatement(stmt *cnosql.AlterContinuousQueryStatement) error {
+	cq, err := e.continuousQuery(stmt.Database, stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	cq.Offset = stmt.Offset
+	if err := cq.Validate(); err != nil {
+		return err
+	}
+	return e.MetaClient.AlterContinuousQuery(stmt.Database, stmt.Name, cq.String())
+}
+
+// executeRunContinuousQueryStatement backfills the target of a continuous
+// query over a past time range. The range is widened to whole GROUP BY
+// intervals and each interval runs as its own SELECT INTO, so memory stays
+// bounded by one interval and a killed statement stops between intervals.
+// The points written by each interval are sent as a row of their own. Each
+// interval counts as a SELECT against the query limits of the user.
+func (e *StatementExecutor) executeRunContinuousQueryStatement(ctx *query.ExecutionContext, stmt *cnosql.RunContinuousQueryStatement) error {
+	cq, err := e.continuousQuery(stmt.Database, stmt.Name)
+	if err != nil {
+		return err
+	}
+	source := cq.Source
+	if source.Target == nil {
+		return fmt.Errorf("continuous query %q has no INTO clause", stmt.Name)
+	}
+	if err := e.NormalizeStatement(source, stmt.Database, ""); err != nil {
+		return err
+	}
+
+	interval, err := source.GroupByInterval()
+	if err != nil {
+		return err
+	} else if interval == 0 {
+		return fmt.Errorf("continuous query %q has no GROUP BY time interval", stmt.Name)
+	}
+	offset, err := source.GroupByOffset()
+	if err != nil {
+		return err
+	}
+
+	// Evaluate the range the same way as a "time >= start AND time < end"
+	// condition in the time zone of the query.
+	cond := &cnosql.BinaryExpr{
+		Op: cnosql.AND,
+		LHS: &cnosql.BinaryExpr{
+			Op:  cnosql.GTE,
+			LHS: &cnosql.VarRef{Val: "time"},
+			RHS: stmt.Start,
+		},
+		RHS: &cnosql.BinaryExpr{
+			Op:  cnosql.LT,
+			LHS: &cnosql.VarRef{Val: "time"},
+			RHS: stmt.End,
+		},
+	}
+	_, timeRange, err := cnosql.ConditionExpr(cond, &cnosql.NowValuer{Now: time.Now().UTC(), Location: source.Location})
+	if err != nil {
+		return err
+	} else if timeRange.Min.IsZero() || timeRange.Max.IsZero() || timeRange.Max.Before(timeRange.Min) {
+		return fmt.Errorf("invalid time range: %s AND %s", stmt.Start, stmt.End)
+	}
+
+	// Buckets partly inside the range are computed from all of their data,
+	// as a partial bucket would overwrite the target with a wrong value.
+	opt := query.IteratorOptions{
+		Interval: query.Interval{Duration: interval, Offset: offset},
+		Location: source.Location,
+	}
+	start, _ := opt.Window(timeRange.Min.UnixNano())
+	last := timeRange.Max.UnixNano()
+
+	seen := make(map[string]bool)
+	for t := start; t <= last; {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		_, next := opt.Window(t)
+		window := source.Clone()
+		if err := window.SetTimeRange(time.Unix(0, t), time.Unix(0, next)); err != nil {
+			return fmt.Errorf("unable to set time range: %s", err)
+		}
+
+		summary, err := e.executeSelect(ctx, window)
+		if err != nil {
+			return err
+		}
+
+		// Warnings such as the read-only one repeat for every interval
+		// and are only sent once.
+		var messages []*query.Message
+		for _, m := range summary.Messages {
+			if !seen[m.Text] {
+				seen[m.Text] = true
+				messages = append(messages, m)
+			}
+		}
+
+		// An interval without a summary wrote nothing.
+		row := &models.Row{
+			Name:    "result",
+			Columns: []string{"time", "written", "dropped"},
+			Values:  [][]interface{}{{nil, int64(0), int64(0)}},
+		}
+		if len(summary.Series) > 0 && len(summary.Series[0].Values) > 0 {
+			row = summary.Series[0]
+		}
+		row.Values[0][0] = time.Unix(0, t).UTC()
+		if err := ctx.Send(&query.Result{
+			Messages: messages,
+			Series:   []*models.Row{row},
+			Partial:  next <= last,
+		}); err != nil {
+			return err
+		}
+		t = next
+	}
+	return nil
 }
 
 func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *cnosql.CreateDatabaseStatement) error {
@@ -265,11 +775,17 @@ func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *cnosql.CreateDa
 		return meta.ErrInvalidName
 	}
 
-	if !stmt.RetentionPolicyCreate {
-		_, err := e.MetaClient.CreateDatabase(stmt.Name)
+	if err := validateIndexType(stmt.IndexType); err != nil {
 		return err
 	}
 
+	if !stmt.RetentionPolicyCreate {
+		if _, err := e.MetaClient.CreateDatabase(stmt.Name); err != nil {
+			return err
+		}
+		return e.setDatabaseIndexType(stmt.Name, stmt.IndexType)
+	}
+
 	// If we're doing, for example, CREATE DATABASE "db" WITH DURATION 1d then
 	// the name will not yet be set. We only need to validate non-empty
 	// retention policy names, such as in the statement:
@@ -278,14 +794,52 @@ func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *cnosql.CreateDa
 		return meta.ErrInvalidName
 	}
 
+	if stmt.RetentionPolicyDuration != nil {
+		if err := validateRetentionPolicyDurations(*stmt.RetentionPolicyDuration, stmt.RetentionPolicyShardGroupDuration); err != nil {
+			return err
+		}
+	}
+
 	spec := meta.RetentionPolicySpec{
 		Name:               stmt.RetentionPolicyName,
 		Duration:           stmt.RetentionPolicyDuration,
 		ReplicaN:           stmt.RetentionPolicyReplication,
 		ShardGroupDuration: stmt.RetentionPolicyShardGroupDuration,
 	}
-	_, err := e.MetaClient.CreateDatabaseWithRetentionPolicy(stmt.Name, &spec)
-	return err
+	if _, err := e.MetaClient.CreateDatabaseWithRetentionPolicy(stmt.Name, &spec); err != nil {
+		return err
+	}
+	return e.setDatabaseIndexType(stmt.Name, stmt.IndexType)
+}
+
+// setDatabaseIndexType stores the index of a newly created database. An
+// empty index leaves the database on the server's default.
+func (e *StatementExecutor) setDatabaseIndexType(database, index string) error {
+	if index == "" {
+		return nil
+	}
+	du := &meta.DatabaseUpdate{}
+	du.SetIndexType(index)
+	return e.MetaClient.UpdateDatabase(database, du)
+}
+
+// validateRetentionPolicyDurations returns an error if a retention policy
+// duration is below the supported minimum or shorter than its shard group
+// duration. A zero duration is infinite and a zero shard group duration is
+// derived from the retention policy duration, so neither is checked.
+func validateRetentionPolicyDurations(duration, sgDuration time.Duration) error {
+	if duration == 0 {
+		return nil
+	}
+	if duration < meta.MinRetentionPolicyDuration {
+		return fmt.Errorf("retention policy duration %s is below the minimum of %s",
+			cnosql.FormatDuration(duration), cnosql.FormatDuration(meta.MinRetentionPolicyDuration))
+	}
+	if sgDuration > duration {
+		return fmt.Errorf("shard duration %s is longer than retention policy duration %s; the maximum shard duration is %s",
+			cnosql.FormatDuration(sgDuration), cnosql.FormatDuration(duration), cnosql.FormatDuration(duration))
+	}
+	return nil
 }
 
 func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *cnosql.CreateRetentionPolicyStatement) error {
@@ -295,11 +849,33 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *cnosql.C
 		return meta.ErrInvalidName
 	}
 
+	if err := validateRetentionPolicyDurations(stmt.Duration, stmt.ShardGroupDuration); err != nil {
+		return err
+	}
+
 	spec := meta.RetentionPolicySpec{
 		Name:               stmt.Name,
 		Duration:           &stmt.Duration,
 		ReplicaN:           &stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		FutureWriteLimit:   &stmt.FutureWriteLimit,
+		PastWriteLimit:     &stmt.PastWriteLimit,
+		ColdAfter:          &stmt.ColdAfter,
+		Placement:          &stmt.Placement,
+		DeletionDelay:      &stmt.DeletionDelay,
+		MaxQueryRange:      &stmt.MaxQueryRange,
+	}
+	if ds := stmt.Downsample; ds != nil {
+		if ds.RetentionPolicy == stmt.Name {
+			return fmt.Errorf("retention policy %s cannot downsample into itself", stmt.Name)
+		}
+		spec.Downsample = &meta.DownsampleInfo{
+			Every:           ds.Every,
+			RetentionPolicy: ds.RetentionPolicy,
+		}
+		for _, call := range ds.Calls {
+			spec.Downsample.Calls = append(spec.Downsample.Calls, call.String())
+		}
 	}
 
 	// Create new retention policy.
@@ -308,7 +884,98 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *cnosql.C
 }
 
 func (e *StatementExecutor) executeCreateSubscriptionStatement(q *cnosql.CreateSubscriptionStatement) error {
-	return e.MetaClient.CreateSubscription(q.Database, q.RetentionPolicy, q.Name, q.Mode, q.Destinations)
+	withTLS := q.CA != "" || q.Cert != "" || q.Key != ""
+	for _, dest := range q.Destinations {
+		u, err := url.Parse(dest)
+		if err != nil {
+			// Reported by the meta client.
+			continue
+		}
+		switch u.Scheme {
+		case "http", "https", mqtt.Scheme, mqtt.SchemeTLS:
+		default:
+			if u.User != nil {
+				return fmt.Errorf("invalid destination %q: credentials are only supported by http, https, mqtt and mqtts destinations", cnosql.SanitizeURL(dest))
+			}
+		}
+		if withTLS && u.Scheme != "https" && u.Scheme != mqtt.SchemeTLS {
+			return fmt.Errorf("invalid destination %q: CA, CERT and KEY are only supported by https and mqtts destinations", cnosql.SanitizeURL(dest))
+		}
+		switch u.Scheme {
+		case kafka.Scheme:
+			if _, _, err := kafka.ParseURL(u); err != nil {
+				return fmt.Errorf("invalid kafka destination %q: %s, expected kafka://host:port[,host:port...]/topic", dest, err)
+			}
+		case mqtt.Scheme, mqtt.SchemeTLS:
+			if _, err := mqtt.ParseURL(u); err != nil {
+				return fmt.Errorf("invalid mqtt destination %q: %s, expected %s://[user:password@]host[:port]/topic[?qos=0|1][&client_id=id]", cnosql.SanitizeURL(dest), err, u.Scheme)
+			}
+		}
+	}
+	if err := validateSubscriptionTLS(q.CA, q.Cert, q.Key); err != nil {
+		return err
+	}
+	if err := validateSubscriptionWeights(q.Mode, q.Destinations, q.Weights); err != nil {
+		return err
+	}
+
+	var filter string
+	if q.Filter != nil {
+		if err := cnosql.ValidateSubscriptionFilter(q.Filter); err != nil {
+			return err
+		}
+		filter = q.Filter.String()
+	}
+	tlsInfo := meta.SubscriptionTLSInfo{CA: q.CA, Cert: q.Cert, Key: q.Key}
+	return e.MetaClient.CreateSubscription(q.Database, q.RetentionPolicy, q.Name, q.Mode, q.Destinations, q.Weights, filter, tlsInfo)
+}
+
+// validateSubscriptionWeights returns an error unless the destinations of a
+// BALANCE subscription have non-negative weights and at least one of them has
+// a positive weight. Destinations weighted 0 are standbys.
+func validateSubscriptionWeights(mode string, destinations []string, weights []int) error {
+	if mode != "BALANCE" {
+		return nil
+	} else if len(destinations) == 0 {
+		return errors.New("BALANCE mode requires at least one destination")
+	} else if len(weights) != len(destinations) {
+		return fmt.Errorf("BALANCE mode requires a weight per destination, got %d weights for %d destinations", len(weights), len(destinations))
+	}
+
+	active := false
+	for i, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("invalid weight %d of destination %q: must not be negative", w, cnosql.SanitizeURL(destinations[i]))
+		} else if w > 0 {
+			active = true
+		}
+	}
+	if !active {
+		return errors.New("BALANCE mode requires at least one destination with a positive weight, weight 0 is for standbys")
+	}
+	return nil
+}
+
+// validateSubscriptionTLS returns an error if the TLS files of a subscription
+// can't be loaded.
+func validateSubscriptionTLS(ca, cert, key string) error {
+	if (cert == "") != (key == "") {
+		return errors.New("CERT and KEY must be given together")
+	}
+	if ca != "" {
+		b, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return fmt.Errorf("invalid CA: %s", err)
+		} else if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			return fmt.Errorf("invalid CA: no certificates found in %s", ca)
+		}
+	}
+	if cert != "" {
+		if _, err := tls.LoadX509KeyPair(cert, key); err != nil {
+			return fmt.Errorf("invalid CERT or KEY: %s", err)
+		}
+	}
+	return nil
 }
 
 func (e *StatementExecutor) executeCreateUserStatement(q *cnosql.CreateUserStatement) error {
@@ -321,32 +988,151 @@ func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *cnosql.DeleteSeri
 		return query.ErrDatabaseNotFound(database)
 	}
 
-	// Convert "now()" to current time.
-	stmt.Condition = cnosql.Reduce(stmt.Condition, &cnosql.NowValuer{Now: time.Now().UTC()})
-
+	release, err := e.acquireOperation(OperationScope{Database: database}, stmt.String())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Convert "now()" to current time.
+	stmt.Condition = cnosql.Reduce(stmt.Condition, &cnosql.NowValuer{Now: time.Now().UTC()})
+
 	// Locally delete the series.
 	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
 }
 
+// executePurgeDataStatement deletes all data in a database older than the
+// statement's cutoff time. Shard groups ending before the cutoff are dropped
+// and the older points of shard groups spanning it are deleted. The shards of
+// dropped shard groups are kept for the deletion delay of their retention
+// policy, like those of expired shard groups, if the retention service runs.
+func (e *StatementExecutor) executePurgeDataStatement(stmt *cnosql.PurgeDataStatement, database string) (models.Rows, error) {
+	if stmt.Database != "" {
+		database = stmt.Database
+	}
+	if database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	dbi := e.MetaClient.Database(database)
+	if dbi == nil {
+		return nil, query.ErrDatabaseNotFound(database)
+	}
+
+	// Evaluate the cutoff the same way as a "time < expr" condition.
+	cond := &cnosql.BinaryExpr{
+		Op:  cnosql.LT,
+		LHS: &cnosql.VarRef{Val: "time"},
+		RHS: stmt.Time,
+	}
+	_, timeRange, err := cnosql.ConditionExpr(cond, &cnosql.NowValuer{Now: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	} else if timeRange.Max.IsZero() {
+		return nil, fmt.Errorf("invalid cutoff time: %s", stmt.Time)
+	}
+	cutoff := timeRange.Max.Add(time.Nanosecond)
+	cond.RHS = &cnosql.TimeLiteral{Val: cutoff}
+
+	release, err := e.acquireOperation(OperationScope{Database: database}, stmt.String())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var dropped, partial int
+	for _, rpi := range dbi.RetentionPolicies {
+		for _, sgi := range rpi.ShardGroups {
+			if sgi.Deleted() || !sgi.StartTime.Before(cutoff) {
+				continue
+			} else if sgi.EndTime.After(cutoff) {
+				partial += len(sgi.Shards)
+				continue
+			}
+
+			// The retention service removes the shards once the deletion
+			// delay has passed.
+			if e.DeferShardRemoval && rpi.DeletionDelay > 0 {
+				if err := e.MetaClient.DeleteShardGroup(database, rpi.Name, sgi.ID); err != nil {
+					return nil, err
+				}
+				dropped += len(sgi.Shards)
+				continue
+			}
+
+			// The shard group can't be restored once its shards are gone.
+			for _, si := range sgi.Shards {
+				if err := e.TSDBStore.DeleteShard(si.ID); err != nil {
+					return nil, err
+				}
+			}
+			if err := e.MetaClient.PurgeShardGroup(database, rpi.Name, sgi.ID); err != nil {
+				return nil, err
+			}
+			dropped += len(sgi.Shards)
+		}
+	}
+
+	if partial > 0 {
+		if err := e.TSDBStore.DeleteSeries(database, nil, cond); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*models.Row{{
+		Columns: []string{"database", "cutoff", "dropped_shards", "partial_shards"},
+		Values:  [][]interface{}{{database, cutoff.UTC().Format(time.RFC3339Nano), dropped, partial}},
+	}}, nil
+}
+
 func (e *StatementExecutor) executeDropContinuousQueryStatement(q *cnosql.DropContinuousQueryStatement) error {
-	return e.MetaClient.DropContinuousQuery(q.Database, q.Name)
+	if err := e.MetaClient.DropContinuousQuery(q.Database, q.Name); err != nil {
+		return err
+	}
+
+	// A continuous query recreated with the same name starts afresh.
+	if e.ContinuousQueryRuns != nil {
+		e.ContinuousQueryRuns.ResetContinuousQuery(q.Database, q.Name)
+	}
+	return nil
 }
 
 // executeDropDatabaseStatement drops a database from the cluster.
 // It does not return an error if the database was not found on any of
-// the nodes, or in the Meta store.
-func (e *StatementExecutor) executeDropDatabaseStatement(stmt *cnosql.DropDatabaseStatement) error {
-	if e.MetaClient.Database(stmt.Name) == nil {
-		return nil
+// the nodes, or in the Meta store. The returned message tells the two
+// cases apart so that a misspelled name does not go unnoticed.
+func (e *StatementExecutor) executeDropDatabaseStatement(stmt *cnosql.DropDatabaseStatement) (*query.Message, error) {
+	release, err := e.acquireOperation(OperationScope{Database: stmt.Name}, stmt.String())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	dbi := e.MetaClient.Database(stmt.Name)
+	if dbi == nil {
+		return notDroppedMessage(fmt.Sprintf("database %s did not exist", stmt.Name)), nil
+	}
+
+	// Gather what is about to be reclaimed before anything is deleted.
+	var shardIDs []uint64
+	for i := range dbi.RetentionPolicies {
+		shardIDs = append(shardIDs, retentionPolicyShardIDs(&dbi.RetentionPolicies[i])...)
+	}
+	size, err := e.TSDBStore.ShardsDiskSize(shardIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Locally delete the datababse.
 	if err := e.TSDBStore.DeleteDatabase(stmt.Name); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Remove the database from the Meta Store.
-	return e.MetaClient.DropDatabase(stmt.Name)
+	if err := e.MetaClient.DropDatabase(stmt.Name); err != nil {
+		return nil, err
+	}
+	return droppedMessage(fmt.Sprintf("database %s", stmt.Name), len(shardIDs), size), nil
 }
 
 func (e *StatementExecutor) executeDropMeasurementStatement(stmt *cnosql.DropMeasurementStatement, database string) error {
@@ -354,25 +1140,52 @@ func (e *StatementExecutor) executeDropMeasurementStatement(stmt *cnosql.DropMea
 		return query.ErrDatabaseNotFound(database)
 	}
 
+	release, err := e.acquireOperation(OperationScope{Database: database}, stmt.String())
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Locally drop the measurement
 	return e.TSDBStore.DeleteMeasurement(database, stmt.Name)
 }
 
-func (e *StatementExecutor) executeDropSeriesStatement(stmt *cnosql.DropSeriesStatement, database string) error {
+func (e *StatementExecutor) executeDropSeriesStatement(ctx *query.ExecutionContext, stmt *cnosql.DropSeriesStatement) (*query.Message, error) {
+	database := ctx.Database
 	if dbi := e.MetaClient.Database(database); dbi == nil {
-		return query.ErrDatabaseNotFound(database)
+		return nil, query.ErrDatabaseNotFound(database)
 	}
 
-	// Check for time in WHERE clause (not supported).
+	// A time condition in DROP SERIES is deprecated. It is executed as the
+	// equivalent DELETE, which only removes the points within the time range.
 	if cnosql.HasTimeExpr(stmt.Condition) {
-		return errors.New("DROP SERIES doesn't support time in WHERE clause")
+		del := &cnosql.DeleteSeriesStatement{Sources: stmt.Sources, Condition: stmt.Condition}
+		msg, err := e.deprecated(ctx, NoticeDropSeriesTime, "DROP SERIES doesn't support time in WHERE clause", del.String())
+		if err != nil {
+			return nil, err
+		}
+		return msg, e.executeDeleteSeriesStatement(del, database)
+	}
+
+	release, err := e.acquireOperation(OperationScope{Database: database}, stmt.String())
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	// Locally drop the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	return nil, e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
 }
 
 func (e *StatementExecutor) executeDropShardStatement(stmt *cnosql.DropShardStatement) error {
+	if e.OperationLocks != nil {
+		release, err := e.OperationLocks.AcquireShard(stmt.ID, stmt.String())
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
 	// Locally delete the shard.
 	if err := e.TSDBStore.DeleteShard(stmt.ID); err != nil {
 		return err
@@ -382,22 +1195,195 @@ func (e *StatementExecutor) executeDropShardStatement(stmt *cnosql.DropShardStat
 	return e.MetaClient.DropShard(stmt.ID)
 }
 
-func (e *StatementExecutor) executeDropRetentionPolicyStatement(stmt *cnosql.DropRetentionPolicyStatement) error {
+// executeTruncateShardsStatement truncates every open shard group at the
+// current time plus the statement's delay, so that later writes create new
+// shard groups.
+func (e *StatementExecutor) executeTruncateShardsStatement(stmt *cnosql.TruncateShardsStatement) error {
+	now := time.Now()
+	t := now.Add(stmt.Delay)
+
+	// A delay too large for time.Time wraps around into the past.
+	if t.Before(now) {
+		return fmt.Errorf("truncate time %s is in the past", t.UTC().Format(time.RFC3339))
+	}
+	return e.MetaClient.TruncateShardGroups(t)
+}
+
+// executeUndropShardGroupStatement restores a deleted shard group whose
+// shards have not been removed yet. The returned message warns if the group
+// is still expired under its retention policy, as the retention service will
+// delete it again.
+func (e *StatementExecutor) executeUndropShardGroupStatement(stmt *cnosql.UndropShardGroupStatement) (*query.Message, error) {
+	for _, di := range e.MetaClient.Databases() {
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.ID != stmt.ID {
+					continue
+				}
+				if err := e.MetaClient.UndeleteShardGroup(di.Name, rpi.Name, sgi.ID); err != nil {
+					return nil, err
+				}
+				if rpi.Duration != 0 && sgi.EndTime.Add(rpi.Duration).Before(time.Now()) {
+					return &query.Message{
+						Level: query.WarningLevel,
+						Text: fmt.Sprintf("shard group %d is still expired under retention policy %s and will be deleted again; alter the retention policy duration to keep it",
+							sgi.ID, rpi.Name),
+					}, nil
+				}
+				return nil, nil
+			}
+		}
+	}
+	return nil, meta.ErrShardGroupNotFound
+}
+
+// executePrecreateShardGroupsStatement creates the shard groups of every
+// retention policy covering the current time through the statement's
+// duration, and reports how many groups were created for each policy.
+func (e *StatementExecutor) executePrecreateShardGroupsStatement(stmt *cnosql.PrecreateShardGroupsStatement) (models.Rows, error) {
+	now := time.Now()
+	to := now.Add(stmt.Duration)
+	if to.Before(now) {
+		return nil, fmt.Errorf("precreate time %s is out of range", to.UTC().Format(time.RFC3339))
+	}
+
+	// existing returns the ids of the shard groups currently in each
+	// retention policy, keyed by database and then retention policy.
+	existing := func() (map[string]map[string]map[uint64]struct{}, int) {
+		ids := make(map[string]map[string]map[uint64]struct{})
+		var n int
+		for _, di := range e.MetaClient.Databases() {
+			ids[di.Name] = make(map[string]map[uint64]struct{})
+			for _, rpi := range di.RetentionPolicies {
+				m := make(map[uint64]struct{})
+				for _, sgi := range rpi.ShardGroups {
+					if !sgi.Deleted() {
+						m[sgi.ID] = struct{}{}
+					}
+				}
+				ids[di.Name][rpi.Name] = m
+				n += len(m)
+			}
+		}
+		return ids, n
+	}
+	before, n := existing()
+
+	// PrecreateShardGroups only extends retention policies whose last shard
+	// group is still open, so start every policy with the group for now.
+	for _, di := range e.MetaClient.Databases() {
+		for _, rpi := range di.RetentionPolicies {
+			if _, err := e.MetaClient.CreateShardGroup(di.Name, rpi.Name, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Each call creates at most one successive group per retention policy,
+	// so repeat until no more groups are needed.
+	for {
+		if err := e.MetaClient.PrecreateShardGroups(now, to); err != nil {
+			return nil, err
+		}
+		_, m := existing()
+		if m == n {
+			break
+		}
+		n = m
+	}
+
+	after, _ := existing()
+	row := &models.Row{Columns: []string{"database", "rp", "created"}}
+	for _, di := range e.MetaClient.Databases() {
+		for _, rpi := range di.RetentionPolicies {
+			var created int
+			for id := range after[di.Name][rpi.Name] {
+				if _, ok := before[di.Name][rpi.Name][id]; !ok {
+					created++
+				}
+			}
+			row.Values = append(row.Values, []interface{}{di.Name, rpi.Name, created})
+		}
+	}
+	return []*models.Row{row}, nil
+}
+
+// executeDropRetentionPolicyStatement drops a retention policy. Like DROP
+// DATABASE it succeeds if the retention policy does not exist, and reports
+// which case happened in the returned message.
+func (e *StatementExecutor) executeDropRetentionPolicyStatement(stmt *cnosql.DropRetentionPolicyStatement) (*query.Message, error) {
+	release, err := e.acquireOperation(OperationScope{Database: stmt.Database, RetentionPolicy: stmt.Name}, stmt.String())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	dbi := e.MetaClient.Database(stmt.Database)
 	if dbi == nil {
-		return nil
+		return notDroppedMessage(fmt.Sprintf("database %s did not exist", stmt.Database)), nil
 	}
 
-	if dbi.RetentionPolicy(stmt.Name) == nil {
-		return nil
+	rpi := dbi.RetentionPolicy(stmt.Name)
+	if rpi == nil {
+		return notDroppedMessage(fmt.Sprintf("retention policy %s on database %s did not exist", stmt.Name, stmt.Database)), nil
+	}
+
+	// Writes without an explicit retention policy fail once the default is
+	// gone, so dropping it must be asked for explicitly.
+	if dbi.DefaultRetentionPolicy == stmt.Name && !stmt.Force {
+		return nil, fmt.Errorf("cannot drop default retention policy %s on database %s; use DROP RETENTION POLICY ... FORCE or set a new default first", stmt.Name, stmt.Database)
+	}
+
+	shardIDs := retentionPolicyShardIDs(rpi)
+	size, err := e.TSDBStore.ShardsDiskSize(shardIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Locally drop the retention policy.
 	if err := e.TSDBStore.DeleteRetentionPolicy(stmt.Database, stmt.Name); err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := e.MetaClient.DropRetentionPolicy(stmt.Database, stmt.Name); err != nil {
+		return nil, err
+	}
+	return droppedMessage(fmt.Sprintf("retention policy %s on database %s", stmt.Name, stmt.Database), len(shardIDs), size), nil
+}
+
+// acquireOperation locks scope for a destructive operation if operation
+// locking is enabled. The returned function releases the lock.
+func (e *StatementExecutor) acquireOperation(scope OperationScope, desc string) (func(), error) {
+	if e.OperationLocks == nil {
+		return func() {}, nil
+	}
+	return e.OperationLocks.Acquire(scope, desc)
+}
+
+// retentionPolicyShardIDs returns the ids of the shards of all shard groups
+// in rpi that have not been deleted.
+func retentionPolicyShardIDs(rpi *meta.RetentionPolicyInfo) []uint64 {
+	var ids []uint64
+	for _, sgi := range rpi.ShardGroups {
+		if sgi.Deleted() {
+			continue
+		}
+		for _, si := range sgi.Shards {
+			ids = append(ids, si.ID)
+		}
+	}
+	return ids
+}
+
+func droppedMessage(what string, shardN int, size int64) *query.Message {
+	return &query.Message{
+		Level: query.InfoLevel,
+		Text:  fmt.Sprintf("dropped %s (%d shards, %d bytes reclaimed)", what, shardN, size),
 	}
+}
 
-	return e.MetaClient.DropRetentionPolicy(stmt.Database, stmt.Name)
+func notDroppedMessage(text string) *query.Message {
+	return &query.Message{Level: query.InfoLevel, Text: text}
 }
 
 func (e *StatementExecutor) executeDropSubscriptionStatement(q *cnosql.DropSubscriptionStatement) error {
@@ -408,12 +1394,24 @@ func (e *StatementExecutor) executeDropUserStatement(q *cnosql.DropUserStatement
 	return e.MetaClient.DropUser(q.Name)
 }
 
+func (e *StatementExecutor) executeCreateRoleStatement(q *cnosql.CreateRoleStatement) error {
+	return e.MetaClient.CreateRole(q.Name)
+}
+
+func (e *StatementExecutor) executeDropRoleStatement(q *cnosql.DropRoleStatement) error {
+	return e.MetaClient.DropRole(q.Name)
+}
+
 func (e *StatementExecutor) executeExplainStatement(ctx *query.ExecutionContext, q *cnosql.ExplainStatement) (models.Rows, error) {
 	opt := query.SelectOptions{
 		NodeID:      ctx.ExecutionOptions.NodeID,
 		MaxSeriesN:  e.MaxSelectSeriesN,
+		MaxPointN:   e.MaxSelectPointN,
 		MaxBucketsN: e.MaxSelectBucketsN,
 		Authorizer:  ctx.Authorizer,
+
+		// Report the limits the statement would exceed instead of failing.
+		ExplainLimits: true,
 	}
 
 	// Prepare the query for execution, but do not actually execute it.
@@ -439,10 +1437,128 @@ func (e *StatementExecutor) executeExplainStatement(ctx *query.ExecutionContext,
 	return models.Rows{row}, nil
 }
 
+// executeExplainSeriesStatement resolves the sources and condition of a
+// DELETE or DROP SERIES statement like the storage engine does when deleting,
+// and reports the measurements, series and shards it matches. Nothing is
+// deleted.
+func (e *StatementExecutor) executeExplainSeriesStatement(ctx *query.ExecutionContext, q *cnosql.ExplainStatement) (models.Rows, error) {
+	var sources cnosql.Sources
+	var condition cnosql.Expr
+	switch stmt := q.SeriesStatement.(type) {
+	case *cnosql.DeleteSeriesStatement:
+		sources, condition = stmt.Sources, stmt.Condition
+	case *cnosql.DropSeriesStatement:
+		sources, condition = stmt.Sources, stmt.Condition
+	default:
+		return nil, fmt.Errorf("cannot explain %s", q.SeriesStatement)
+	}
+
+	database := ctx.Database
+	if database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+	dbi := e.MetaClient.Database(database)
+	if dbi == nil {
+		return nil, query.ErrDatabaseNotFound(database)
+	}
+
+	// Split the time range from the tag condition.
+	condition = cnosql.Reduce(condition, &cnosql.NowValuer{Now: time.Now().UTC()})
+	cond, timeRange, err := cnosql.ConditionExpr(condition, nil)
+	if err != nil {
+		return nil, err
+	}
+	min, max := timeRange.MinTime(), timeRange.MaxTime()
+
+	// The series are deleted from the shards of every retention policy
+	// whose time range intersects the condition.
+	var shardIDs []uint64
+	for _, rpi := range dbi.RetentionPolicies {
+		for _, sgi := range rpi.ShardGroups {
+			if sgi.Deleted() || !sgi.Overlaps(min, max) {
+				continue
+			}
+			for _, si := range sgi.Shards {
+				shardIDs = append(shardIDs, si.ID)
+			}
+		}
+	}
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+
+	// Count the matching series of each measurement from the index.
+	series := make(map[string]int64)
+	var names []string
+	if err := e.TSDBStore.ForEachSeriesKey(databaseAuthorizer(ctx.Authorizer, dbi), shardIDs, sourcesCondition(sources, cond), tsdb.SeriesKeysOptions{Unordered: true}, func(key string) error {
+		name := string(models.ParseName([]byte(key)))
+		if _, ok := series[name]; !ok {
+			names = append(names, name)
+		}
+		series[name]++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	row := &models.Row{Columns: []string{"QUERY PLAN"}}
+	add := func(format string, a ...interface{}) {
+		row.Values = append(row.Values, []interface{}{fmt.Sprintf(format, a...)})
+	}
+	add("STATEMENT: %s", q.SeriesStatement)
+	add("DATABASE: %s", database)
+	if !timeRange.IsZero() {
+		add("TIME RANGE: %s - %s", formatTimeIn(min, nil), formatTimeIn(max, nil))
+	}
+	add("MEASUREMENTS: %d", len(names))
+	var seriesN int64
+	for _, name := range names {
+		add("SERIES OF %s: %d", name, series[name])
+		seriesN += series[name]
+	}
+	add("SERIES: %d", seriesN)
+	add("SHARDS: %s", joinUint64(shardIDs))
+	return models.Rows{row}, nil
+}
+
+// sourcesCondition returns cond limited to the measurements of sources.
+func sourcesCondition(sources cnosql.Sources, cond cnosql.Expr) cnosql.Expr {
+	var scond cnosql.Expr
+	for _, src := range sources {
+		mm := src.(*cnosql.Measurement)
+
+		var expr cnosql.Expr
+		if mm.Regex != nil {
+			expr = &cnosql.BinaryExpr{Op: cnosql.EQREGEX, LHS: &cnosql.VarRef{Val: "_name"}, RHS: &cnosql.RegexLiteral{Val: mm.Regex.Val}}
+		} else {
+			expr = &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: &cnosql.VarRef{Val: "_name"}, RHS: &cnosql.StringLiteral{Val: mm.Name}}
+		}
+
+		if scond == nil {
+			scond = expr
+		} else {
+			scond = &cnosql.BinaryExpr{Op: cnosql.OR, LHS: scond, RHS: expr}
+		}
+	}
+
+	if scond == nil {
+		return cond
+	} else if cond == nil {
+		return scond
+	}
+	return &cnosql.BinaryExpr{
+		Op:  cnosql.AND,
+		LHS: &cnosql.ParenExpr{Expr: scond},
+		RHS: &cnosql.ParenExpr{Expr: cond},
+	}
+}
+
 func (e *StatementExecutor) executeExplainAnalyzeStatement(ectx *query.ExecutionContext, q *cnosql.ExplainStatement) (models.Rows, error) {
 	stmt := q.Statement
+	stmtCtx, cancel := statementContext(ectx)
+	defer cancel()
+
 	t, span := tracing.NewTrace("select")
-	ctx := tracing.NewContextWithTrace(ectx, t)
+	ctx := tracing.NewContextWithTrace(stmtCtx, t)
 	ctx = tracing.NewContextWithSpan(ctx, span)
 	var aux query.Iterators
 	ctx = query.NewContextWithIterators(ctx, &aux)
@@ -464,12 +1580,13 @@ func (e *StatementExecutor) executeExplainAnalyzeStatement(ectx *query.Execution
 		var row *models.Row
 		row, _, err = em.Emit()
 		if err != nil {
+			err = statementErr(ectx, stmtCtx, err)
 			goto CLEANUP
 		} else if row == nil {
 			// Check if the query was interrupted while emitting.
 			select {
-			case <-ectx.Done():
-				err = ectx.Err()
+			case <-stmtCtx.Done():
+				err = statementErr(ectx, stmtCtx, nil)
 				goto CLEANUP
 			default:
 			}
@@ -499,139 +1616,521 @@ CLEANUP:
 	row := &models.Row{
 		Columns: []string{"EXPLAIN ANALYZE"},
 	}
-	for _, s := range strings.Split(t.Tree().String(), "\n") {
+	for _, s := range strings.Split(explainAnalyzeTree(t.Tree()).String(), "\n") {
 		row.Values = append(row.Values, []interface{}{s})
 	}
 
 	return models.Rows{row}, nil
 }
 
-func (e *StatementExecutor) executeGrantStatement(stmt *cnosql.GrantStatement) error {
-	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, stmt.Privilege)
+func (e *StatementExecutor) executeGrantStatement(stmt *cnosql.GrantStatement) (models.Rows, *query.Message, error) {
+	if stmt.Databases != nil || stmt.DatabaseRegex != nil {
+		return e.executePrivilegeOnDatabases(stmt.User, stmt.Databases, stmt.DatabaseRegex, func(database string) error {
+			return e.MetaClient.SetPrivilege(stmt.User, database, stmt.Privilege)
+		})
+	}
+
+	var err error
+	if stmt.Measurement != nil {
+		err = e.MetaClient.SetMeasurementPrivilege(stmt.User, measurementPrivilege(stmt.Measurement, stmt.Privilege))
+	} else if stmt.RetentionPolicy != "" {
+		err = e.MetaClient.SetPrivilegeOnRP(stmt.User, stmt.On, stmt.RetentionPolicy, stmt.Privilege)
+	} else {
+		err = e.MetaClient.SetPrivilege(stmt.User, stmt.On, stmt.Privilege)
+	}
+	return nil, nil, err
+}
+
+// executePrivilegeOnDatabases calls fn with each database of a GRANT or
+// REVOKE on a list of databases, or on the databases matched by re. The
+// databases matched by re are looked up at execution time. The outcome for
+// each database is returned as a row so a failure on one database doesn't
+// hide the databases that succeeded.
+func (e *StatementExecutor) executePrivilegeOnDatabases(user string, databases []string, re *cnosql.RegexLiteral, fn func(database string) error) (models.Rows, *query.Message, error) {
+	// Fail once rather than for every database if the user doesn't exist.
+	if _, err := e.MetaClient.User(user); err != nil {
+		return nil, nil, err
+	}
+
+	if re != nil {
+		databases = nil
+		for _, di := range e.MetaClient.Databases() {
+			if re.Val.MatchString(di.Name) {
+				databases = append(databases, di.Name)
+			}
+		}
+		if len(databases) == 0 {
+			return nil, &query.Message{
+				Level: query.WarningLevel,
+				Text:  fmt.Sprintf("no databases match %s, no privileges were changed", re.String()),
+			}, nil
+		}
+		sort.Strings(databases)
+	}
+
+	row := &models.Row{Columns: []string{"database", "success", "error"}}
+	for _, database := range databases {
+		if err := fn(database); err != nil {
+			row.Values = append(row.Values, []interface{}{database, false, err.Error()})
+		} else {
+			row.Values = append(row.Values, []interface{}{database, true, nil})
+		}
+	}
+	return models.Rows{row}, nil, nil
+}
+
+// measurementPrivilege returns the privilege p on the measurements selected by m.
+func measurementPrivilege(m *cnosql.Measurement, p cnosql.Privilege) meta.MeasurementPrivilege {
+	mp := meta.MeasurementPrivilege{
+		Database:        m.Database,
+		RetentionPolicy: m.RetentionPolicy,
+		Measurement:     m.Name,
+		Privilege:       p,
+	}
+	if m.Regex != nil {
+		mp.Measurement, mp.Regex = m.Regex.Val.String(), true
+	}
+	return mp
 }
 
 func (e *StatementExecutor) executeGrantAdminStatement(stmt *cnosql.GrantAdminStatement) error {
 	return e.MetaClient.SetAdminPrivilege(stmt.User, true)
 }
 
-func (e *StatementExecutor) executeRevokeStatement(stmt *cnosql.RevokeStatement) error {
+func (e *StatementExecutor) executeRevokeStatement(stmt *cnosql.RevokeStatement) (models.Rows, *query.Message, error) {
+	if stmt.Databases != nil || stmt.DatabaseRegex != nil {
+		return e.executePrivilegeOnDatabases(stmt.User, stmt.Databases, stmt.DatabaseRegex, func(database string) error {
+			return e.revokePrivilege(stmt.User, database, stmt.Privilege)
+		})
+	}
+
+	var err error
+	if stmt.Measurement != nil {
+		err = e.executeRevokeMeasurementStatement(stmt)
+	} else if stmt.RetentionPolicy != "" {
+		err = e.executeRevokeRetentionPolicyStatement(stmt)
+	} else {
+		err = e.revokePrivilege(stmt.User, stmt.On, stmt.Privilege)
+	}
+	return nil, nil, err
+}
+
+// revokePrivilege revokes the privilege revoked on database from user.
+func (e *StatementExecutor) revokePrivilege(user, database string, revoked cnosql.Privilege) error {
 	priv := cnosql.NoPrivileges
 
 	// Revoking all privileges means there's no need to look at existing user privileges.
-	if stmt.Privilege != cnosql.AllPrivileges {
-		p, err := e.MetaClient.UserPrivilege(stmt.User, stmt.On)
+	if revoked != cnosql.AllPrivileges {
+		p, err := e.MetaClient.UserPrivilege(user, database)
 		if err != nil {
 			return err
 		}
 		// Bit clear (AND NOT) the user's privilege with the revoked privilege.
-		priv = *p &^ stmt.Privilege
+		priv = *p &^ revoked
 	}
 
-	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, priv)
+	return e.MetaClient.SetPrivilege(user, database, priv)
 }
 
-func (e *StatementExecutor) executeRevokeAdminStatement(stmt *cnosql.RevokeAdminStatement) error {
-	return e.MetaClient.SetAdminPrivilege(stmt.User, false)
-}
+func (e *StatementExecutor) executeRevokeRetentionPolicyStatement(stmt *cnosql.RevokeStatement) error {
+	priv := cnosql.NoPrivileges
 
-func (e *StatementExecutor) executeSetPasswordUserStatement(q *cnosql.SetPasswordUserStatement) error {
-	return e.MetaClient.UpdateUser(q.Name, q.Password)
+	// Revoking all privileges means there's no need to look at existing user privileges.
+	if stmt.Privilege != cnosql.AllPrivileges {
+		p, err := e.MetaClient.UserPrivilegeOnRP(stmt.User, stmt.On, stmt.RetentionPolicy)
+		if err != nil {
+			return err
+		}
+		// Bit clear (AND NOT) the user's privilege with the revoked privilege.
+		priv = *p &^ stmt.Privilege
+	}
+
+	return e.MetaClient.SetPrivilegeOnRP(stmt.User, stmt.On, stmt.RetentionPolicy, priv)
 }
 
-func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error {
-	cur, err := e.createIterators(ctx, stmt, ctx.ExecutionOptions)
-	if err != nil {
+func (e *StatementExecutor) executeRevokeMeasurementStatement(stmt *cnosql.RevokeStatement) error {
+	mp := measurementPrivilege(stmt.Measurement, cnosql.NoPrivileges)
+
+	// Revoking all privileges means there's no need to look at existing user privileges.
+	if stmt.Privilege != cnosql.AllPrivileges {
+		privs, err := e.MetaClient.UserMeasurementPrivileges(stmt.User)
+		if err != nil {
+			return err
+		}
+		for _, p := range privs {
+			if p.Database == mp.Database && p.RetentionPolicy == mp.RetentionPolicy &&
+				p.Measurement == mp.Measurement && p.Regex == mp.Regex {
+				// Bit clear (AND NOT) the user's privilege with the revoked privilege.
+				mp.Privilege = p.Privilege &^ stmt.Privilege
+				break
+			}
+		}
+	}
+
+	return e.MetaClient.SetMeasurementPrivilege(stmt.User, mp)
+}
+
+func (e *StatementExecutor) executeRevokeAdminStatement(stmt *cnosql.RevokeAdminStatement) error {
+	return e.MetaClient.SetAdminPrivilege(stmt.User, false)
+}
+
+func (e *StatementExecutor) executeGrantToRoleStatement(stmt *cnosql.GrantToRoleStatement) error {
+	return e.MetaClient.SetRolePrivilege(stmt.Role, stmt.On, stmt.Privilege)
+}
+
+func (e *StatementExecutor) executeGrantRoleStatement(stmt *cnosql.GrantRoleStatement) error {
+	return e.MetaClient.GrantRole(stmt.User, stmt.Role)
+}
+
+func (e *StatementExecutor) executeRevokeFromRoleStatement(stmt *cnosql.RevokeFromRoleStatement) error {
+	priv := cnosql.NoPrivileges
+
+	// Revoking all privileges means there's no need to look at existing role privileges.
+	if stmt.Privilege != cnosql.AllPrivileges {
+		privs, err := e.MetaClient.RolePrivileges(stmt.Role)
+		if err != nil {
+			return err
+		}
+		// Bit clear (AND NOT) the role's privilege with the revoked privilege.
+		priv = privs[stmt.On] &^ stmt.Privilege
+	}
+
+	return e.MetaClient.SetRolePrivilege(stmt.Role, stmt.On, priv)
+}
+
+func (e *StatementExecutor) executeRevokeRoleStatement(stmt *cnosql.RevokeRoleStatement) error {
+	return e.MetaClient.RevokeRole(stmt.User, stmt.Role)
+}
+
+func (e *StatementExecutor) executeSetPasswordUserStatement(q *cnosql.SetPasswordUserStatement) error {
+	return e.MetaClient.UpdateUser(q.Name, q.Password)
+}
+
+func (e *StatementExecutor) executeAlterUserStatement(q *cnosql.AlterUserStatement) error {
+	return e.MetaClient.SetUserLocked(q.Name, q.Locked)
+}
+
+func (e *StatementExecutor) executeAlterUserQueryLimitStatement(q *cnosql.AlterUserQueryLimitStatement) error {
+	u := &meta.UserQueryLimitsUpdate{
+		MaxConcurrentQueries: q.MaxConcurrentQueries,
+		MaxQueriesPerMinute:  q.MaxQueriesPerMinute,
+	}
+	if q.Priority != "" {
+		priority, err := query.ParsePriority(q.Priority)
+		if err != nil {
+			return err
+		}
+		u.Priority = &priority
+	}
+	return e.MetaClient.UpdateUserQueryLimits(q.Name, u)
+}
+
+func (e *StatementExecutor) executeAlterUserDefaultDatabaseStatement(q *cnosql.AlterUserDefaultDatabaseStatement) error {
+	return e.MetaClient.SetUserDefaultDatabase(q.Name, q.Database, q.RetentionPolicy)
+}
+
+func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error {
+	summary, err := e.executeSelect(ctx, stmt)
+	if err != nil || summary == nil {
 		return err
 	}
+	return ctx.Send(summary)
+}
+
+// executeSelect runs a SELECT statement and streams its rows to the results
+// channel. A SELECT INTO only streams progress messages; the summary of the
+// points it wrote is returned instead so the caller decides how to report it.
+// Every SELECT counts against the query limits of the user and waits for an
+// admission slot, whichever statement runs it.
+func (e *StatementExecutor) executeSelect(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) (*query.Result, error) {
+	// Enforce the query limits of the user before any iterator is created.
+	if ctx.UserID != "" {
+		var maxConcurrent, maxPerMinute int64
+		if u, _ := e.MetaClient.User(ctx.UserID); u != nil {
+			if ui, ok := u.(*meta.UserInfo); ok {
+				maxConcurrent, maxPerMinute = ui.MaxConcurrentQueries, ui.MaxQueriesPerMinute
+			}
+		}
+		release, err := e.userQueries.acquire(ctx.UserID, maxConcurrent, maxPerMinute, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// Wait for a slot if the server already runs its maximum of SELECTs.
+	// Killed and timed out queries leave the queue.
+	release, err := e.admission.acquire(ctx, admissionRequest{
+		priority: ctx.Priority,
+		kill:     func() { ctx.Kill(query.ErrQueryPreempted) },
+	}, e.MaxConcurrentSelects, e.MaxQueuedSelects, e.MaxSelectQueueTime, e.PreemptLowPriority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	atomic.AddInt64(&e.runningSelects, 1)
+	defer atomic.AddInt64(&e.runningSelects, -1)
+
+	// Fail before reading anything if the results can't be written.
+	if stmt.Target != nil {
+		if err := authorizeTarget(ctx, stmt.Target); err != nil {
+			return nil, err
+		}
+		if err := e.ensureTargetRetentionPolicy(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Iterators and the emit loop stop once the statement timeout passes.
+	stmtCtx, cancel := statementContext(ctx)
+	defer cancel()
+
+	// Collect warnings about remote shards read from a non-preferred owner
+	// so they can be returned along with the results.
+	var warnings readWarnings
+	itrCtx := stmtCtx
+	if ctx.ReadConsistency == query.ReadConsistencyQuorum || !query.AuthorizerIsOpen(ctx.Authorizer) {
+		itrCtx = withReadWarnings(stmtCtx, &warnings)
+	}
+
+	cur, err := e.createIterators(itrCtx, stmt, ctx.ExecutionOptions)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate a row emitter from the iterator set.
 	em := query.NewEmitter(cur, ctx.ChunkSize)
 	defer em.Close()
+	defer func() { ctx.SetIteratorStats(cur.Stats()) }()
 
 	// Emit rows to the results channel.
 	var writeN int64
+	var drops intoDrops
 	var emitted bool
 
 	var pointsWriter *BufferedPointsWriter
+	var progress *intoProgress
 	if stmt.Target != nil {
-		pointsWriter = NewBufferedPointsWriter(e.PointsWriter, stmt.Target.Measurement.Database, stmt.Target.Measurement.RetentionPolicy, 10000)
+		pointsWriter = NewBufferedPointsWriterWithInterval(e.PointsWriter, stmt.Target.Measurement.Database, stmt.Target.Measurement.RetentionPolicy, e.intoWriteBatchSize(ctx.IntoWriteBatchSize), e.IntoFlushInterval)
+		pointsWriter.bufferedN = &e.bufferedPoints
+
+		pointsWriter.SetWorkers(e.IntoWriteConcurrency)
+		if limit := e.intoWriteRateLimit(ctx.IntoWriteRateLimit); limit > 0 {
+			pointsWriter.SetRateLimit(ctx, limit)
+			pointsWriter.throttledN = &e.throttledInto
+		}
+
+		// Points still buffered when the statement fails are dropped.
+		defer func() {
+			pointsWriter.Wait()
+			atomic.AddInt64(&e.bufferedPoints, -int64(pointsWriter.Len()))
+		}()
+
+		if ctx.IntoProgressPoints > 0 || ctx.IntoProgressInterval > 0 {
+			progress = &intoProgress{
+				points:   int64(ctx.IntoProgressPoints),
+				interval: ctx.IntoProgressInterval,
+				last:     time.Now(),
+			}
+		}
 	}
 
 	for {
 		row, partial, err := em.Emit()
 		if err != nil {
-			return err
+			return nil, statementErr(ctx, stmtCtx, err)
 		} else if row == nil {
 			// Check if the query was interrupted while emitting.
 			select {
-			case <-ctx.Done():
-				return ctx.Err()
+			case <-stmtCtx.Done():
+				return nil, statementErr(ctx, stmtCtx, nil)
 			default:
 			}
 			break
 		}
 
+		// Stop between rows once the statement timed out, as INTO rows
+		// are never sent.
+		select {
+		case <-stmtCtx.Done():
+			return nil, statementErr(ctx, stmtCtx, nil)
+		default:
+		}
+
 		// Write points back into system for INTO statements.
 		if stmt.Target != nil {
-			n, err := e.writeInto(pointsWriter, stmt, row)
+			n, err := e.writeInto(pointsWriter, stmt, row, &drops)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			writeN += n
+
+			// Buffered points and batches still being written count
+			// against the memory budget.
+			if err := e.checkMemory(ctx, pointsWriter.Bytes()); err != nil {
+				return nil, err
+			}
+
+			// Only flush between rows so a row is never split across writes.
+			now := time.Now()
+			if err := pointsWriter.FlushIfDue(now); err != nil {
+				return nil, err
+			}
+
+			// Report progress as partial results that only carry a message,
+			// so the final summary row is the same with or without them.
+			if progress != nil && progress.due(writeN, now) {
+				if err := ctx.Send(&query.Result{
+					Messages: []*query.Message{{
+						Level: query.InfoLevel,
+						Text:  fmt.Sprintf("%d points written", writeN),
+					}},
+					Partial: true,
+				}); err != nil {
+					return nil, err
+				}
+			}
 			continue
 		}
 
 		result := &query.Result{
-			Series:  []*models.Row{row},
-			Partial: partial,
+			Messages: warnings.Messages(),
+			Series:   []*models.Row{row},
+			Partial:  partial,
 		}
 
-		// Send results or exit if closing.
+		// Send results or exit if closing. The row counts as buffered
+		// memory until the receiver has taken it.
+		if err := e.checkMemory(ctx, rowSize(row)); err != nil {
+			return nil, err
+		}
 		if err := ctx.Send(result); err != nil {
-			return err
+			return nil, err
 		}
+		ctx.SetMemoryBytes(0)
+		ctx.AddRowsEmitted(len(row.Values))
 
 		emitted = true
 	}
 
-	// Flush remaining points and emit write count if an INTO statement.
+	// Flush remaining points and return the write count if an INTO statement.
 	if stmt.Target != nil {
 		if err := pointsWriter.Flush(); err != nil {
-			return err
+			return nil, err
 		}
+		ctx.SetMemoryBytes(0)
 
-		var messages []*query.Message
+		atomic.AddInt64(&e.intoPointsWritten, writeN)
+		atomic.AddInt64(&e.intoPointsDropped, drops.n)
+
+		messages := warnings.Messages()
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
+		if msg := drops.Message(); msg != nil {
+			messages = append(messages, msg)
+		}
 
-		return ctx.Send(&query.Result{
+		// The summary row carries no real timestamp, so it is always the
+		// Unix epoch in UTC regardless of the statement's TZ clause. The
+		// points written themselves keep their absolute bucket times; TZ
+		// only decides where GROUP BY time() buckets start.
+		return &query.Result{
 			Messages: messages,
 			Series: []*models.Row{{
 				Name:    "result",
-				Columns: []string{"time", "written"},
-				Values:  [][]interface{}{{time.Unix(0, 0).UTC(), writeN}},
+				Columns: []string{"time", "written", "dropped"},
+				Values:  [][]interface{}{{time.Unix(0, 0).UTC(), writeN, drops.n}},
 			}},
-		})
+		}, nil
 	}
 
 	// Always emit at least one result.
 	if !emitted {
-		return ctx.Send(&query.Result{
-			Series: make([]*models.Row, 0),
+		return nil, ctx.Send(&query.Result{
+			Messages: warnings.Messages(),
+			Series:   make([]*models.Row, 0),
 		})
 	}
 
+	return nil, nil
+}
+
+// checkMemory records n as the bytes held by the query for SHOW QUERIES and
+// returns an error if n exceeds the memory budget of a SELECT.
+func (e *StatementExecutor) checkMemory(ctx *query.ExecutionContext, n int64) error {
+	ctx.SetMemoryBytes(n)
+	if e.MaxSelectMemoryBytes > 0 && n > e.MaxSelectMemoryBytes {
+		return &query.MemoryBudgetExceededError{Bytes: n, Limit: e.MaxSelectMemoryBytes}
+	}
 	return nil
 }
 
+// rowSize approximates the number of bytes held by a result row.
+func rowSize(row *models.Row) int64 {
+	n := int64(len(row.Name))
+	for k, v := range row.Tags {
+		n += int64(len(k) + len(v))
+	}
+	for _, c := range row.Columns {
+		n += int64(len(c))
+	}
+	for _, values := range row.Values {
+		for _, v := range values {
+			// Assume an interface header plus an 8 byte payload for
+			// fixed-size values.
+			n += 16
+			if s, ok := v.(string); ok {
+				n += int64(len(s))
+			}
+		}
+	}
+	return n
+}
+
+// statementContext returns a context for running a statement of the query
+// of ctx that ends after the timeout of the query's execution options. The
+// task manager's query timeout and KILL QUERY still end it through ctx.
+func statementContext(ctx *query.ExecutionContext) (context.Context, context.CancelFunc) {
+	if ctx.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, ctx.Timeout)
+}
+
+// statementErr returns the error a statement run with stmtCtx ends with: the
+// error of the query if it was killed or timed out as a whole, the statement
+// timeout if it passed, and err otherwise.
+func statementErr(ctx *query.ExecutionContext, stmtCtx context.Context, err error) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if stmtCtx.Err() == context.DeadlineExceeded {
+		return query.ErrStatementTimeoutExceeded(ctx.Timeout)
+	}
+	return err
+}
+
 func (e *StatementExecutor) createIterators(ctx context.Context, stmt *cnosql.SelectStatement, opt query.ExecutionOptions) (query.Cursor, error) {
+	// Admins may query any time range. EXPLAIN does not create iterators so
+	// it still shows the plan of a query that would be rejected here.
+	if !opt.UserAdmin {
+		if err := e.checkQueryRange(stmt, time.Now(), cnosql.TimeRange{}); err != nil {
+			return nil, err
+		}
+	}
+
 	sopt := query.SelectOptions{
 		NodeID:      opt.NodeID,
 		MaxSeriesN:  e.MaxSelectSeriesN,
 		MaxPointN:   e.MaxSelectPointN,
 		MaxBucketsN: e.MaxSelectBucketsN,
 		Authorizer:  opt.Authorizer,
+
+		ReadConsistency: opt.ReadConsistency,
 	}
 
 	// Create a set of iterators from a selection.
@@ -642,28 +2141,191 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *cnosql.Se
 	return cur, nil
 }
 
+// checkQueryRange returns an error if stmt reads from a retention policy with
+// a maximum query range and its time range is unbounded below or longer than
+// that maximum. The time range of a subquery is limited by the time range of
+// the statements enclosing it, passed in as outer.
+//
+// Only statements executed as a SELECT are checked, including the SHOW
+// statements rewritten into one. SHOW SERIES without a time condition, SHOW
+// TAG KEYS, SHOW TAG VALUES and the cardinality statements answered from the
+// series index are exempt: they read no points, so their cost depends on the
+// number of series rather than on the time range.
+func (e *StatementExecutor) checkQueryRange(stmt *cnosql.SelectStatement, now time.Time, outer cnosql.TimeRange) error {
+	valuer := &cnosql.NowValuer{Now: now, Location: stmt.Location}
+	_, tr, err := cnosql.ConditionExpr(stmt.Condition, valuer)
+	if err != nil {
+		return err
+	}
+	tr = tr.Intersect(outer)
+
+	for _, source := range stmt.Sources {
+		switch source := source.(type) {
+		case *cnosql.Measurement:
+			rpi, err := e.MetaClient.RetentionPolicy(source.Database, source.RetentionPolicy)
+			if err != nil {
+				return err
+			} else if rpi == nil || rpi.MaxQueryRange == 0 {
+				continue
+			}
+
+			if tr.Min.IsZero() {
+				return fmt.Errorf("query against retention policy %q.%q has no lower time bound: the maximum query range is %s",
+					source.Database, source.RetentionPolicy, cnosql.FormatDuration(rpi.MaxQueryRange))
+			}
+			max := tr.Max
+			if max.IsZero() || max.After(now) {
+				max = now
+			}
+			if max.Sub(tr.Min) > rpi.MaxQueryRange {
+				return fmt.Errorf("query against retention policy %q.%q spans more than the maximum query range of %s",
+					source.Database, source.RetentionPolicy, cnosql.FormatDuration(rpi.MaxQueryRange))
+			}
+		case *cnosql.SubQuery:
+			if err := e.checkQueryRange(source.Statement, now, tr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// intoWriteBatchSize returns the number of points a SELECT INTO buffers
+// before writing them. A positive override from the execution options takes
+// precedence over the executor's setting.
+func (e *StatementExecutor) intoWriteBatchSize(override int) int {
+	if override > 0 {
+		return override
+	} else if e.IntoWriteBatchSize > 0 {
+		return e.IntoWriteBatchSize
+	}
+	return DefaultIntoWriteBatchSize
+}
+
+// intoWriteRateLimit returns the points per second a SELECT INTO may write.
+// A positive override from the execution options can only lower the
+// executor's limit, so clients can't bypass a limit set on the server.
+func (e *StatementExecutor) intoWriteRateLimit(override int) int {
+	if override > 0 && (e.IntoWriteRateLimit <= 0 || override < e.IntoWriteRateLimit) {
+		return override
+	}
+	return e.IntoWriteRateLimit
+}
+
 func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *cnosql.ShowContinuousQueriesStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
+	if stmt.Database != "" {
+		di := e.MetaClient.Database(stmt.Database)
+		if di == nil {
+			return nil, cnosdb.ErrDatabaseNotFound(stmt.Database)
+		}
+		dis = []meta.DatabaseInfo{*di}
+	}
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"name", "query"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"name", "query", "owner", "resample_every", "resample_for", "offset", "last_run", "last_duration", "last_error", "last_error_time"}, Name: di.Name}
 		for _, cqi := range di.ContinuousQueries {
-			row.Values = append(row.Values, []interface{}{cqi.Name, cqi.Query})
+			// Continuous queries created without authentication have no owner.
+			var owner interface{}
+			if cqi.Owner != "" {
+				owner = cqi.Owner
+			}
+
+			var resampleEvery, resampleFor, offset interface{}
+			if cqi.ResampleEvery > 0 {
+				resampleEvery = cqi.ResampleEvery.String()
+			}
+			if cqi.ResampleFor > 0 {
+				resampleFor = cqi.ResampleFor.String()
+			}
+			if cqi.Offset > 0 {
+				offset = cqi.Offset.String()
+			}
+
+			// Continuous queries that have not run on this node since
+			// startup report null rather than a zero time.
+			var lastRun, lastDuration, lastError, lastErrorTime interface{}
+			if e.ContinuousQueryRuns != nil {
+				if run, ok := e.ContinuousQueryRuns.LastContinuousQueryRun(di.Name, cqi.Name); ok {
+					// A query failing before it executes has an error
+					// but no execution.
+					if !run.Time.IsZero() {
+						lastRun, lastDuration = run.Time.UTC().Format(time.RFC3339Nano), run.Duration.String()
+					}
+					if run.Err != nil {
+						lastError, lastErrorTime = run.Err.Error(), run.ErrTime.UTC().Format(time.RFC3339Nano)
+					}
+				}
+			}
+			row.Values = append(row.Values, []interface{}{cqi.Name, cqi.Query, owner, resampleEvery, resampleFor, offset, lastRun, lastDuration, lastError, lastErrorTime})
 		}
 		rows = append(rows, row)
 	}
 	return rows, nil
 }
 
+// ContinuousQueryRun describes the most recent execution of a continuous query.
+type ContinuousQueryRun struct {
+	// Time is when the execution started.
+	Time time.Time
+
+	// Duration is how long the execution took.
+	Duration time.Duration
+
+	// Err is the error the last attempt to run the query failed with and
+	// ErrTime when it failed. Both are cleared by a successful execution.
+	Err     error
+	ErrTime time.Time
+}
+
+// ContinuousQueryRunStatser returns the last execution of a continuous query.
+// ok is false if the continuous query has not run since startup.
+type ContinuousQueryRunStatser interface {
+	LastContinuousQueryRun(database, name string) (run ContinuousQueryRun, ok bool)
+
+	// ResetContinuousQuery forgets the executions of a dropped continuous query.
+	ResetContinuousQuery(database, name string)
+}
+
 func (e *StatementExecutor) executeShowDatabasesStatement(ctx *query.ExecutionContext, q *cnosql.ShowDatabasesStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 	a := ctx.ExecutionOptions.CoarseAuthorizer
 
 	row := &models.Row{Name: "databases", Columns: []string{"name"}}
+	if q.WithDetail {
+		row.Columns = []string{"name", "rp_count", "default_rp", "continuous_query_count", "max_series", "series", "max_values_per_tag", "index", "shard_indexes"}
+	}
 	for _, di := range dis {
 		// Only include databases that the user is authorized to read or write.
-		if a.AuthorizeDatabase(cnosql.ReadPrivilege, di.Name) || a.AuthorizeDatabase(cnosql.WritePrivilege, di.Name) {
+		if !a.AuthorizeDatabase(cnosql.ReadPrivilege, di.Name) && !a.AuthorizeDatabase(cnosql.WritePrivilege, di.Name) {
+			continue
+		}
+		if q.WithDetail {
+			// The series count is the estimated cardinality on this node,
+			// which is what the limit applies to. Restricted users only
+			// count the series they may read.
+			seriesN, err := e.TSDBStore.SeriesCardinality(databaseAuthorizer(ctx.Authorizer, &di), di.Name)
+			if err != nil {
+				return nil, err
+			}
+			var maxSeriesN, maxValuesPerTag interface{}
+			if di.MaxSeriesN > 0 {
+				maxSeriesN = di.MaxSeriesN
+			}
+			if di.MaxValuesPerTag > 0 {
+				maxValuesPerTag = di.MaxValuesPerTag
+			}
+			// index is the index new shards are created with, null for the
+			// server's default. shard_indexes lists the indexes of the
+			// existing shards on this node, which may be mixed.
+			var index interface{}
+			if di.IndexType != "" {
+				index = di.IndexType
+			}
+			shardIndexes := strings.Join(e.TSDBStore.IndexTypes(di.Name), ",")
+			row.Values = append(row.Values, []interface{}{di.Name, len(di.RetentionPolicies), di.DefaultRetentionPolicy, len(di.ContinuousQueries), maxSeriesN, seriesN, maxValuesPerTag, index, shardIndexes})
+		} else {
 			row.Values = append(row.Values, []interface{}{di.Name})
 		}
 	}
@@ -698,103 +2360,639 @@ func (e *StatementExecutor) executeShowDiagnosticsStatement(stmt *cnosql.ShowDia
 	return rows, nil
 }
 
+// executeShowOperationsStatement lists the destructive operations holding a
+// lock, followed by the ones waiting for a conflicting operation to finish.
+func (e *StatementExecutor) executeShowOperationsStatement() (models.Rows, error) {
+	row := &models.Row{Columns: []string{"database", "retention_policy", "shard", "operation", "state", "since"}}
+	if e.OperationLocks != nil {
+		for _, op := range e.OperationLocks.Operations() {
+			row.Values = append(row.Values, []interface{}{op.Database, op.RetentionPolicy, op.ShardID, op.Desc, op.State(), op.Since.UTC().Format(time.RFC3339)})
+		}
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowGrantsForUserStatement(q *cnosql.ShowGrantsForUserStatement) (models.Rows, error) {
+	u, err := e.MetaClient.User(q.Name)
+	if err == meta.ErrUserNotFound {
+		return nil, fmt.Errorf("user not found: %s", q.Name)
+	} else if err != nil {
+		return nil, err
+	}
+
 	priv, err := e.MetaClient.UserPrivileges(q.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	row := &models.Row{Columns: []string{"database", "privilege"}}
-	for d, p := range priv {
-		row.Values = append(row.Values, []interface{}{d, p.String()})
+	// Sort by database so the output can be compared between calls.
+	databases := make([]string, 0, len(priv))
+	for d := range priv {
+		databases = append(databases, d)
 	}
-	return []*models.Row{row}, nil
-}
-
-func (e *StatementExecutor) executeShowMeasurementsStatement(ctx *query.ExecutionContext, q *cnosql.ShowMeasurementsStatement) error {
-	if q.Database == "" {
-		return ErrDatabaseNameRequired
+	sort.Strings(databases)
+	admin := u.AuthorizeUnrestricted()
+
+	// Without VERBOSE only the database grants are listed, after a first
+	// row for the admin privilege.
+	if !q.Verbose {
+		row := &models.Row{Columns: []string{"database", "privilege"}}
+		if admin {
+			row.Values = append(row.Values, []interface{}{nil, "ADMIN"})
+		}
+		for _, d := range databases {
+			row.Values = append(row.Values, []interface{}{d, priv[d].String()})
+		}
+		return []*models.Row{row}, nil
 	}
 
-	names, err := e.TSDBStore.MeasurementNames(ctx.Authorizer, q.Database, q.Condition)
-	if err != nil || len(names) == 0 {
-		return ctx.Send(&query.Result{
-			Err: err,
-		})
+	row := &models.Row{Columns: []string{"database", "retention_policy", "measurement", "privilege", "admin", "role"}}
+	for _, d := range databases {
+		row.Values = append(row.Values, []interface{}{d, nil, nil, priv[d].String(), admin, nil})
 	}
 
-	if q.Offset > 0 {
-		if q.Offset >= len(names) {
-			names = nil
-		} else {
-			names = names[q.Offset:]
+	ui, _ := u.(*meta.UserInfo)
+
+	// Grants on retention policies show the retention policy they are restricted to.
+	if ui != nil {
+		for _, rpp := range ui.RetentionPolicyPrivileges {
+			row.Values = append(row.Values, []interface{}{rpp.Database, rpp.RetentionPolicy, nil, rpp.Privilege.String(), admin, nil})
 		}
 	}
 
-	if q.Limit > 0 {
-		if q.Limit < len(names) {
-			names = names[:q.Limit]
+	// Grants on measurements show the measurements they are restricted to.
+	mprivs, err := e.MetaClient.UserMeasurementPrivileges(q.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, mp := range mprivs {
+		var rp interface{}
+		if mp.RetentionPolicy != "" {
+			rp = mp.RetentionPolicy
 		}
+		row.Values = append(row.Values, []interface{}{mp.Database, rp, mp.String(), mp.Privilege.String(), admin, nil})
 	}
 
-	values := make([][]interface{}, len(names))
-	for i, name := range names {
-		values[i] = []interface{}{string(name)}
+	// Grants received through roles name the role they come from.
+	if ui != nil {
+		for _, r := range ui.Roles {
+			rolePriv, err := e.MetaClient.RolePrivileges(r)
+			if err == meta.ErrRoleNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			for _, d := range sortedPrivilegeDatabases(rolePriv) {
+				row.Values = append(row.Values, []interface{}{d, nil, nil, rolePriv[d].String(), admin, r})
+			}
+		}
 	}
 
-	if len(values) == 0 {
-		return ctx.Send(&query.Result{})
+	// Still report admin status for a user without any database grants.
+	if len(row.Values) == 0 {
+		row.Values = append(row.Values, []interface{}{nil, nil, nil, nil, admin, nil})
 	}
-
-	return ctx.Send(&query.Result{
-		Series: []*models.Row{{
-			Name:    "measurements",
-			Columns: []string{"name"},
-			Values:  values,
-		}},
-	})
+	return []*models.Row{row}, nil
 }
 
-func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(ctx *query.ExecutionContext, stmt *cnosql.ShowMeasurementCardinalityStatement) (models.Rows, error) {
-	if stmt.Database == "" {
-		return nil, ErrDatabaseNameRequired
+func (e *StatementExecutor) executeShowGrantsForRoleStatement(q *cnosql.ShowGrantsForRoleStatement) (models.Rows, error) {
+	priv, err := e.MetaClient.RolePrivileges(q.Name)
+	if err == meta.ErrRoleNotFound {
+		return nil, fmt.Errorf("role not found: %s", q.Name)
+	} else if err != nil {
+		return nil, err
 	}
 
-	n, err := e.TSDBStore.MeasurementsCardinality(stmt.Database)
-	if err != nil {
-		return nil, err
+	row := &models.Row{Columns: []string{"database", "privilege"}}
+	for _, d := range sortedPrivilegeDatabases(priv) {
+		row.Values = append(row.Values, []interface{}{d, priv[d].String()})
 	}
+	return []*models.Row{row}, nil
+}
 
-	return []*models.Row{&models.Row{
-		Columns: []string{"cardinality estimation"},
-		Values:  [][]interface{}{{n}},
-	}}, nil
+// sortedPrivilegeDatabases returns the databases of priv sorted by name so
+// the output can be compared between calls.
+func sortedPrivilegeDatabases(priv map[string]cnosql.Privilege) []string {
+	databases := make([]string, 0, len(priv))
+	for d := range priv {
+		databases = append(databases, d)
+	}
+	sort.Strings(databases)
+	return databases
 }
 
-func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *cnosql.ShowRetentionPoliciesStatement) (models.Rows, error) {
+func (e *StatementExecutor) executeShowMeasurementsStatement(ctx *query.ExecutionContext, q *cnosql.ShowMeasurementsStatement) error {
 	if q.Database == "" {
-		return nil, ErrDatabaseNameRequired
+		return ErrDatabaseNameRequired
 	}
 
-	di := e.MetaClient.Database(q.Database)
-	if di == nil {
-		return nil, cnosdb.ErrDatabaseNotFound(q.Database)
+	// Restrict the lookup to a single retention policy for ON db.rp.
+	if q.RetentionPolicy != "" {
+		di := e.MetaClient.Database(q.Database)
+		if di == nil {
+			return cnosdb.ErrDatabaseNotFound(q.Database)
+		}
+		if di.RetentionPolicy(q.RetentionPolicy) == nil {
+			return cnosdb.ErrRetentionPolicyNotFound(q.RetentionPolicy)
+		}
 	}
 
-	row := &models.Row{Columns: []string{"name", "duration", "groupDuration", "replicaN", "default"}}
+	// Users restricted to some retention policies only see the measurements
+	// of those.
+	if rpa, ok := ctx.Authorizer.(retentionPolicyAuthorizer); ok && !query.AuthorizerIsOpen(ctx.Authorizer) {
+		return e.executeShowRetentionPolicyMeasurements(ctx, q, rpa)
+	}
+
+	// Only the number of readable measurements is returned for EXACT COUNT.
+	if q.ExactCount {
+		n, err := e.TSDBStore.MeasurementsCardinalityByExpr(ctx.Authorizer, q.Database, q.RetentionPolicy, q.Condition)
+		if err != nil {
+			return ctx.Send(&query.Result{Err: err})
+		}
+		return e.sendMeasurementsCount(ctx, n)
+	}
+
+	names, err := e.TSDBStore.MeasurementNames(ctx.Authorizer, q.Database, q.RetentionPolicy, q.Condition)
+	return e.sendMeasurementNames(ctx, q, names, err)
+}
+
+// executeShowRetentionPolicyMeasurements executes SHOW MEASUREMENTS for a
+// user restricted to some retention policies, merging the measurements of
+// the retention policies the user may read.
+func (e *StatementExecutor) executeShowRetentionPolicyMeasurements(ctx *query.ExecutionContext, q *cnosql.ShowMeasurementsStatement, auth retentionPolicyAuthorizer) error {
+	rps := []string{q.RetentionPolicy}
+	if q.RetentionPolicy == "" {
+		di := e.MetaClient.Database(q.Database)
+		if di == nil {
+			return cnosdb.ErrDatabaseNotFound(q.Database)
+		}
+		rps = rps[:0]
+		for _, rpi := range di.RetentionPolicies {
+			rps = append(rps, rpi.Name)
+		}
+	}
+
+	var names [][]byte
+	seen := make(map[string]struct{})
+	for _, rp := range rps {
+		if !auth.AuthorizeRetentionPolicyRead(q.Database, rp) {
+			continue
+		}
+		a, err := e.TSDBStore.MeasurementNames(auth.ForRetentionPolicy(rp), q.Database, rp, q.Condition)
+		if err != nil {
+			return ctx.Send(&query.Result{Err: err})
+		}
+		for _, name := range a {
+			if _, ok := seen[string(name)]; ok {
+				continue
+			}
+			seen[string(name)] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return bytes.Compare(names[i], names[j]) < 0 })
+
+	if q.ExactCount {
+		return e.sendMeasurementsCount(ctx, int64(len(names)))
+	}
+	return e.sendMeasurementNames(ctx, q, names, nil)
+}
+
+// sendMeasurementsCount sends the result of SHOW MEASUREMENTS EXACT CARDINALITY.
+func (e *StatementExecutor) sendMeasurementsCount(ctx *query.ExecutionContext, n int64) error {
+	return ctx.Send(&query.Result{
+		Series: []*models.Row{{
+			Name:    "measurements",
+			Columns: []string{"count"},
+			Values:  [][]interface{}{{n}},
+		}},
+	})
+}
+
+// sendMeasurementNames sends the result of SHOW MEASUREMENTS for the names
+// found, applying the OFFSET and LIMIT of q.
+func (e *StatementExecutor) sendMeasurementNames(ctx *query.ExecutionContext, q *cnosql.ShowMeasurementsStatement, names [][]byte, err error) error {
+	if err != nil || len(names) == 0 {
+		return ctx.Send(&query.Result{
+			Err: err,
+		})
+	}
+
+	if q.WithCounts {
+		return e.executeShowMeasurementsWithCounts(ctx, q, names)
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(names) {
+			names = nil
+		} else {
+			names = names[q.Offset:]
+		}
+	}
+
+	if q.Limit > 0 {
+		if q.Limit < len(names) {
+			names = names[:q.Limit]
+		}
+	}
+
+	values := make([][]interface{}, len(names))
+	for i, name := range names {
+		values[i] = []interface{}{string(name)}
+	}
+
+	if len(values) == 0 {
+		return ctx.Send(&query.Result{})
+	}
+
+	return ctx.Send(&query.Result{
+		Series: []*models.Row{{
+			Name:    "measurements",
+			Columns: []string{"name"},
+			Values:  values,
+		}},
+	})
+}
+
+// showMeasurementsCountConcurrency is the number of measurements counted at a
+// time by SHOW MEASUREMENTS WITH COUNTS.
+const showMeasurementsCountConcurrency = 4
+
+// executeShowMeasurementsWithCounts returns names with the series count of
+// each measurement, largest first. OFFSET and LIMIT apply after sorting.
+func (e *StatementExecutor) executeShowMeasurementsWithCounts(ctx *query.ExecutionContext, q *cnosql.ShowMeasurementsStatement, names [][]byte) error {
+	// Only the series the user may read are counted.
+	auth := ctx.Authorizer
+	if q.RetentionPolicy != "" {
+		auth = forRetentionPolicies(auth, []string{q.RetentionPolicy})
+	} else if di := e.MetaClient.Database(q.Database); di != nil {
+		auth = databaseAuthorizer(auth, di)
+	}
+
+	counts, err := e.TSDBStore.MeasurementSeriesCardinality(ctx, auth, q.Database, names, showMeasurementsCountConcurrency)
+	if err != nil {
+		return ctx.Send(&query.Result{Err: err})
+	}
+
+	// Last write times are not tracked per measurement, so lastWrite is null.
+	values := make([][]interface{}, len(names))
+	for i, name := range names {
+		values[i] = []interface{}{string(name), counts[i], nil}
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i][1].(int64) > values[j][1].(int64)
+	})
+
+	if q.Offset > 0 {
+		if q.Offset >= len(values) {
+			values = nil
+		} else {
+			values = values[q.Offset:]
+		}
+	}
+
+	if q.Limit > 0 {
+		if q.Limit < len(values) {
+			values = values[:q.Limit]
+		}
+	}
+
+	if len(values) == 0 {
+		return ctx.Send(&query.Result{})
+	}
+
+	return ctx.Send(&query.Result{
+		Series: []*models.Row{{
+			Name:    "measurements",
+			Columns: []string{"name", "series", "lastWrite"},
+			Values:  values,
+		}},
+	})
+}
+
+func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(ctx *query.ExecutionContext, stmt *cnosql.ShowMeasurementCardinalityStatement) (models.Rows, error) {
+	if stmt.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	auth := ctx.Authorizer
+	if di := e.MetaClient.Database(stmt.Database); di != nil {
+		auth = databaseAuthorizer(auth, di)
+	}
+
+	// Only the whole database can be estimated from the sketches; anything
+	// narrower is counted exactly from the index.
+	if stmt.Exact || stmt.Condition != nil {
+		n, err := e.TSDBStore.MeasurementsCardinalityByExpr(auth, stmt.Database, "", stmt.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		row := &models.Row{Columns: []string{"count"}}
+		if stmt.Offset == 0 {
+			row.Values = [][]interface{}{{n}}
+		}
+		return []*models.Row{row}, nil
+	}
+
+	n, err := e.TSDBStore.MeasurementsCardinality(auth, stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*models.Row{&models.Row{
+		Columns: []string{"cardinality estimation"},
+		Values:  [][]interface{}{{n}},
+	}}, nil
+}
+
+func (e *StatementExecutor) executeShowFieldKeyCardinalityStatement(ctx *query.ExecutionContext, stmt *cnosql.ShowFieldKeyCardinalityStatement) (models.Rows, error) {
+	database := stmt.Database
+	if len(stmt.Sources) > 0 {
+		database = stmt.Sources[0].(*cnosql.Measurement).Database
+	}
+	if database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+	if cnosql.HasTimeExpr(stmt.Condition) {
+		return nil, errors.New("SHOW FIELD KEY CARDINALITY doesn't support time in WHERE clause")
+	}
+	if len(stmt.Dimensions) > 0 {
+		return nil, errors.New("SHOW FIELD KEY CARDINALITY doesn't support GROUP BY")
+	}
+
+	di := e.MetaClient.Database(database)
+	if di == nil {
+		return nil, cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	rps, conds, err := sourceConditions(di, stmt.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	column := "count"
+	if !stmt.Exact {
+		column = "cardinality estimation"
+	}
+
+	var rows models.Rows
+	for _, rp := range rps {
+		rpi := di.RetentionPolicy(rp)
+		if rpi == nil {
+			continue
+		}
+
+		cond := conds[rp]
+		if cond == nil {
+			cond = stmt.Condition
+		} else if stmt.Condition != nil {
+			cond = &cnosql.BinaryExpr{Op: cnosql.AND, LHS: &cnosql.ParenExpr{Expr: cond}, RHS: &cnosql.ParenExpr{Expr: stmt.Condition}}
+		}
+
+		counts, err := e.TSDBStore.FieldKeyCardinality(forRetentionPolicies(ctx.Authorizer, []string{rp}), retentionPolicyShardIDs(rpi), cond, stmt.Exact)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			rows = append(rows, &models.Row{
+				Name:    c.Measurement,
+				Columns: []string{column},
+				Values:  [][]interface{}{{c.N}},
+			})
+		}
+	}
+
+	// OFFSET and LIMIT apply to measurements.
+	if stmt.Offset > 0 {
+		if stmt.Offset >= len(rows) {
+			rows = nil
+		} else {
+			rows = rows[stmt.Offset:]
+		}
+	}
+	if stmt.Limit > 0 && stmt.Limit < len(rows) {
+		rows = rows[:stmt.Limit]
+	}
+	return rows, nil
+}
+
+func (e *StatementExecutor) executeShowTagKeyCardinalityStatement(ctx *query.ExecutionContext, stmt *cnosql.ShowTagKeyCardinalityStatement) (models.Rows, error) {
+	database := stmt.Database
+	if len(stmt.Sources) > 0 {
+		database = stmt.Sources[0].(*cnosql.Measurement).Database
+	}
+	if database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+	if len(stmt.Dimensions) > 0 {
+		return nil, errors.New("SHOW TAG KEY CARDINALITY doesn't support GROUP BY")
+	}
+
+	di := e.MetaClient.Database(database)
+	if di == nil {
+		return nil, cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	// Determine appropriate time range. If one or fewer time boundaries provided
+	// then min/max possible time should be used instead.
+	valuer := &cnosql.NowValuer{Now: time.Now()}
+	cond, timeRange, err := cnosql.ConditionExpr(stmt.Condition, valuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restrict to the measurements and retention policies of the sources.
+	// Without sources every retention policy is used.
+	var rps []string
+	if len(stmt.Sources) == 0 {
+		for _, rpi := range di.RetentionPolicies {
+			rps = append(rps, rpi.Name)
+		}
+	} else {
+		var conds map[string]cnosql.Expr
+		if rps, conds, err = sourceConditions(di, stmt.Sources); err != nil {
+			return nil, err
+		}
+
+		var scond cnosql.Expr
+		for _, rp := range rps {
+			if scond == nil {
+				scond = conds[rp]
+			} else {
+				scond = &cnosql.BinaryExpr{Op: cnosql.OR, LHS: scond, RHS: conds[rp]}
+			}
+		}
+		if cond == nil {
+			cond = scond
+		} else {
+			cond = &cnosql.BinaryExpr{Op: cnosql.AND, LHS: &cnosql.ParenExpr{Expr: scond}, RHS: &cnosql.ParenExpr{Expr: cond}}
+		}
+	}
+
+	shardIDs, auth, err := e.shardIDsByTimeRange(ctx.Authorizer, di, rps, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	tagKeys, err := e.TSDBStore.TagKeys(auth, shardIDs, cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows models.Rows
+	for _, m := range tagKeys {
+		if len(m.Keys) == 0 {
+			continue
+		}
+		rows = append(rows, &models.Row{
+			Name:    m.Measurement,
+			Columns: []string{"count"},
+			Values:  [][]interface{}{{len(m.Keys)}},
+		})
+	}
+
+	// OFFSET and LIMIT apply to measurements.
+	if stmt.Offset > 0 {
+		if stmt.Offset >= len(rows) {
+			rows = nil
+		} else {
+			rows = rows[stmt.Offset:]
+		}
+	}
+	if stmt.Limit > 0 && stmt.Limit < len(rows) {
+		rows = rows[:stmt.Limit]
+	}
+	return rows, nil
+}
+
+func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *cnosql.ShowRetentionPoliciesStatement) (models.Rows, []*query.Message, error) {
+	if q.Database == "" {
+		return nil, nil, ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return nil, nil, cnosdb.ErrDatabaseNotFound(q.Database)
+	}
+
+	// Write statistics are not persisted, so a retention policy that has not
+	// been written to since startup reports null rather than zero traffic.
+	var unknown bool
+	row := &models.Row{Columns: []string{"name", "duration", "groupDuration", "replicaN", "default", "lastWrite", "writePointsPerMin", "shardGroups", "oldestStartTime", "newestEndTime", "duration_ns", "group_duration_ns", "futureWriteLimit", "pastWriteLimit", "coldAfter", "downsample", "placement", "deletionDelay", "queryRange"}}
 	for _, rpi := range di.RetentionPolicies {
-		row.Values = append(row.Values, []interface{}{rpi.Name, rpi.Duration.String(), rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name})
+		var lastWrite, perMin interface{}
+		if e.WriteStats != nil {
+			if ws, ok := e.WriteStats.RetentionPolicyWriteStats(di.Name, rpi.Name); ok {
+				lastWrite, perMin = ws.LastWrite.UTC().Format(time.RFC3339Nano), ws.WritePointsPerMin
+			}
+		}
+		unknown = unknown || lastWrite == nil
+
+		// The data window covered by the shard groups that still exist.
+		var groupN int
+		var oldest, newest time.Time
+		for _, sgi := range rpi.ShardGroups {
+			if sgi.Deleted() {
+				continue
+			}
+			if groupN == 0 || sgi.StartTime.Before(oldest) {
+				oldest = sgi.StartTime
+			}
+			if groupN == 0 || sgi.EndTime.After(newest) {
+				newest = sgi.EndTime
+			}
+			groupN++
+		}
+		var oldestStart, newestEnd interface{}
+		if groupN > 0 {
+			oldestStart, newestEnd = oldest.UTC().Format(time.RFC3339), newest.UTC().Format(time.RFC3339)
+		}
+
+		// An infinite duration is stored as zero. It is rendered as INF, as
+		// in CREATE RETENTION POLICY, and left as 0 in duration_ns.
+		duration := rpi.Duration.String()
+		if rpi.Duration == 0 {
+			duration = "INF"
+		}
+
+		// Unset write limits and cold tier thresholds are reported as null.
+		var futureLimit, pastLimit, coldAfter interface{}
+		if rpi.FutureWriteLimit > 0 {
+			futureLimit = rpi.FutureWriteLimit.String()
+		}
+		if rpi.PastWriteLimit > 0 {
+			pastLimit = rpi.PastWriteLimit.String()
+		}
+		if rpi.ColdAfter > 0 {
+			coldAfter = rpi.ColdAfter.String()
+		}
+		var downsample, placement, deletionDelay, queryRange interface{}
+		if rpi.Placement != "" {
+			placement = rpi.Placement
+		}
+		if rpi.DeletionDelay > 0 {
+			deletionDelay = rpi.DeletionDelay.String()
+		}
+		if rpi.MaxQueryRange > 0 {
+			queryRange = rpi.MaxQueryRange.String()
+		}
+		if ds := rpi.Downsample; ds != nil {
+			downsample = fmt.Sprintf("(%s) EVERY %s INTO %s", strings.Join(ds.Calls, ", "),
+				cnosql.FormatDuration(ds.Every), cnosql.QuoteIdent(ds.RetentionPolicy))
+		}
+
+		row.Values = append(row.Values, []interface{}{rpi.Name, duration, rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name, lastWrite, perMin, groupN, oldestStart, newestEnd, int64(rpi.Duration), int64(rpi.ShardGroupDuration), futureLimit, pastLimit, coldAfter, downsample, placement, deletionDelay, queryRange})
 	}
-	return []*models.Row{row}, nil
+
+	var messages []*query.Message
+	if unknown {
+		messages = append(messages, &query.Message{
+			Level: query.WarningLevel,
+			Text:  "lastWrite and writePointsPerMin are null for retention policies with no writes since startup: write traffic is unknown, not zero",
+		})
+	}
+	return []*models.Row{row}, messages, nil
 }
 
 func (e *StatementExecutor) executeShowShardsStatement(stmt *cnosql.ShowShardsStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
+	if stmt.Database != "" {
+		di := e.MetaClient.Database(stmt.Database)
+		if di == nil {
+			return nil, cnosdb.ErrDatabaseNotFound(stmt.Database)
+		}
+		if stmt.RetentionPolicy != "" && di.RetentionPolicy(stmt.RetentionPolicy) == nil {
+			return nil, cnosdb.ErrRetentionPolicyNotFound(stmt.RetentionPolicy)
+		}
+		dis = []meta.DatabaseInfo{*di}
+	}
+
+	// Storage statistics are only known for shards open on this node; the
+	// columns are null for other shards rather than zero.
+	var ids []uint64
+	for _, di := range dis {
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					ids = append(ids, si.ID)
+				}
+			}
+		}
+	}
+	diskStats, err := e.TSDBStore.ShardDiskStats(ids)
+	if err != nil {
+		return nil, err
+	}
 
+	now := time.Now()
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"id", "database", "rp", "shard_group", "start_time", "end_time", "expiry_time", "owners"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"id", "database", "rp", "shard_group", "start_time", "end_time", "expiry_time", "owners", "disk_bytes", "series", "last_modified", "state", "tier"}, Name: di.Name}
 		for _, rpi := range di.RetentionPolicies {
+			if stmt.RetentionPolicy != "" && rpi.Name != stmt.RetentionPolicy {
+				continue
+			}
 			for _, sgi := range rpi.ShardGroups {
 				// Shards associated with deleted shard groups are effectively deleted.
 				// Don't list them.
@@ -808,15 +3006,35 @@ func (e *StatementExecutor) executeShowShardsStatement(stmt *cnosql.ShowShardsSt
 						ownerIDs[i] = owner.NodeID
 					}
 
+					// Shards still accepting writes are hot whatever the
+					// state of their files.
+					state := e.TSDBStore.ShardState(si.ID)
+					if state != tsdb.ShardStateRemote && sgi.Contains(now) {
+						state = tsdb.ShardStateHot
+					}
+
+					var diskBytes, seriesN, lastModified, tier interface{}
+					if ds, ok := diskStats[si.ID]; ok {
+						diskBytes, seriesN, tier = ds.DiskBytes, ds.SeriesN, ds.Tier
+						if !ds.LastModified.IsZero() {
+							lastModified = formatTimeIn(ds.LastModified, stmt.Location)
+						}
+					}
+
 					row.Values = append(row.Values, []interface{}{
 						si.ID,
 						di.Name,
 						rpi.Name,
 						sgi.ID,
-						sgi.StartTime.UTC().Format(time.RFC3339),
-						sgi.EndTime.UTC().Format(time.RFC3339),
-						sgi.EndTime.Add(rpi.Duration).UTC().Format(time.RFC3339),
+						formatTimeIn(sgi.StartTime, stmt.Location),
+						formatTimeIn(sgi.EndTime, stmt.Location),
+						formatTimeIn(sgi.EndTime.Add(rpi.Duration), stmt.Location),
 						joinUint64(ownerIDs),
+						diskBytes,
+						seriesN,
+						lastModified,
+						state,
+						tier,
 					})
 				}
 			}
@@ -831,7 +3049,37 @@ func (e *StatementExecutor) executeShowSeriesCardinalityStatement(ctx *query.Exe
 		return nil, ErrDatabaseNameRequired
 	}
 
-	n, err := e.TSDBStore.SeriesCardinality(stmt.Database)
+	// Restricted users only count the series they may read.
+	auth := ctx.Authorizer
+	if di := e.MetaClient.Database(stmt.Database); di != nil {
+		auth = databaseAuthorizer(auth, di)
+	}
+
+	if stmt.Exact {
+		counts, err := e.TSDBStore.SeriesCardinalityByMeasurement(auth, stmt.Database)
+		if err != nil {
+			return nil, err
+		}
+
+		if stmt.Offset > 0 {
+			if stmt.Offset >= len(counts) {
+				counts = nil
+			} else {
+				counts = counts[stmt.Offset:]
+			}
+		}
+		if stmt.Limit > 0 && stmt.Limit < len(counts) {
+			counts = counts[:stmt.Limit]
+		}
+
+		row := &models.Row{Columns: []string{"measurement", "count"}}
+		for _, c := range counts {
+			row.Values = append(row.Values, []interface{}{c.Measurement, c.N})
+		}
+		return []*models.Row{row}, nil
+	}
+
+	n, err := e.TSDBStore.SeriesCardinality(auth, stmt.Database)
 	if err != nil {
 		return nil, err
 	}
@@ -844,79 +3092,186 @@ func (e *StatementExecutor) executeShowSeriesCardinalityStatement(ctx *query.Exe
 
 func (e *StatementExecutor) executeShowShardGroupsStatement(stmt *cnosql.ShowShardGroupsStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
+	if stmt.Database != "" {
+		di := e.MetaClient.Database(stmt.Database)
+		if di == nil {
+			return nil, cnosdb.ErrDatabaseNotFound(stmt.Database)
+		}
+		dis = []meta.DatabaseInfo{*di}
+	}
 
-	row := &models.Row{Columns: []string{"id", "database", "rp", "start_time", "end_time", "expiry_time"}, Name: "shard groups"}
+	// Only time conditions are supported; they select the shard groups
+	// overlapping the time range.
+	valuer := &cnosql.NowValuer{Now: time.Now(), Location: stmt.Location}
+	cond, timeRange, err := cnosql.ConditionExpr(stmt.Condition, valuer)
+	if err != nil {
+		return nil, err
+	} else if cond != nil {
+		return nil, fmt.Errorf("SHOW SHARD GROUPS only supports time conditions: %s", cond)
+	}
+
+	type shardGroup struct {
+		database string
+		rpi      *meta.RetentionPolicyInfo
+		sgi      meta.ShardGroupInfo
+	}
+	now := time.Now()
+	var groups []shardGroup
 	for _, di := range dis {
-		for _, rpi := range di.RetentionPolicies {
+		for i := range di.RetentionPolicies {
+			rpi := &di.RetentionPolicies[i]
+			// ShardGroupsByTimeRange leaves out deleted shard groups, so the
+			// time range is applied here.
 			for _, sgi := range rpi.ShardGroups {
-				// Shards associated with deleted shard groups are effectively deleted.
-				// Don't list them.
-				if sgi.Deleted() {
+				if !sgi.Overlaps(timeRange.MinTime(), timeRange.MaxTime()) {
 					continue
 				}
-
-				row.Values = append(row.Values, []interface{}{
-					sgi.ID,
-					di.Name,
-					rpi.Name,
-					sgi.StartTime.UTC().Format(time.RFC3339),
-					sgi.EndTime.UTC().Format(time.RFC3339),
-					sgi.EndTime.Add(rpi.Duration).UTC().Format(time.RFC3339),
-				})
+				// Shards associated with deleted shard groups are effectively
+				// deleted. Only list those still within the deletion delay,
+				// which can be restored with UNDROP SHARD GROUP.
+				if sgi.Deleted() && !now.Before(sgi.PurgeAt(rpi.DeletionDelay)) {
+					continue
+				}
+				groups = append(groups, shardGroup{database: di.Name, rpi: rpi, sgi: sgi})
 			}
 		}
 	}
 
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].database != groups[j].database {
+			return groups[i].database < groups[j].database
+		}
+		if groups[i].rpi.Name != groups[j].rpi.Name {
+			return groups[i].rpi.Name < groups[j].rpi.Name
+		}
+		return groups[i].sgi.StartTime.Before(groups[j].sgi.StartTime)
+	})
+
+	// purge_time is set for deleted shard groups and is when their shards
+	// will be removed.
+	row := &models.Row{Columns: []string{"id", "database", "rp", "start_time", "end_time", "expiry_time", "purge_time"}, Name: "shard groups"}
+	for _, g := range groups {
+		var purgeTime interface{}
+		if g.sgi.Deleted() {
+			purgeTime = formatTimeIn(g.sgi.PurgeAt(g.rpi.DeletionDelay), stmt.Location)
+		}
+		row.Values = append(row.Values, []interface{}{
+			g.sgi.ID,
+			g.database,
+			g.rpi.Name,
+			formatTimeIn(g.sgi.StartTime, stmt.Location),
+			formatTimeIn(g.sgi.EndTime, stmt.Location),
+			formatTimeIn(g.sgi.EndTime.Add(g.rpi.Duration), stmt.Location),
+			purgeTime,
+		})
+	}
+
 	return []*models.Row{row}, nil
 }
 
-func (e *StatementExecutor) executeShowStatsStatement(stmt *cnosql.ShowStatsStatement) (models.Rows, error) {
+func (e *StatementExecutor) executeShowStatsStatement(ctx *query.ExecutionContext, stmt *cnosql.ShowStatsStatement) (models.Rows, error) {
 	var rows []*models.Row
 
-	if _, ok := e.TSDBStore.(*tsdb.Store); stmt.Module == "indexes" && ok {
-		// The cost of collecting indexes measurements grows with the size of the indexes, so only collect this
-		// stat when explicitly requested.
-		b := e.TSDBStore.(*tsdb.Store).IndexBytes()
-		row := &models.Row{
+	// The cost of collecting indexes measurements grows with the size of the
+	// indexes, so only collect this stat when the module is explicitly named.
+	var indexes bool
+	var modules []string
+	for _, m := range stmt.Modules {
+		if m == "indexes" {
+			indexes = true
+		} else {
+			modules = append(modules, m)
+		}
+	}
+
+	if store, ok := e.TSDBStore.(*tsdb.Store); indexes && ok {
+		rows = append(rows, &models.Row{
 			Name:    "indexes",
 			Columns: []string{"memoryBytes"},
-			Values:  [][]interface{}{{b}},
-		}
-		rows = append(rows, row)
+			Values:  [][]interface{}{{store.IndexBytes()}},
+		})
 
-	} else {
-		stats, err := e.Monitor.Statistics(nil)
-		if err != nil {
-			return nil, err
+		// Break the total down per database, leaving out databases the
+		// user has no privileges on.
+		byDatabase := store.IndexBytesByDatabase()
+		names := make([]string, 0, len(byDatabase))
+		for name := range byDatabase {
+			names = append(names, name)
 		}
+		sort.Strings(names)
 
-		for _, stat := range stats {
-			if stmt.Module != "" && stat.Name != stmt.Module {
+		a := ctx.ExecutionOptions.CoarseAuthorizer
+		for _, name := range names {
+			if !a.AuthorizeDatabase(cnosql.ReadPrivilege, name) && !a.AuthorizeDatabase(cnosql.WritePrivilege, name) {
 				continue
 			}
-			row := &models.Row{Name: stat.Name, Tags: stat.Tags}
+			rows = append(rows, &models.Row{
+				Name:    "indexes",
+				Tags:    map[string]string{"database": name},
+				Columns: []string{"memoryBytes"},
+				Values:  [][]interface{}{{byDatabase[name]}},
+			})
+		}
+	}
 
-			values := make([]interface{}, 0, len(stat.Values))
-			for _, k := range stat.ValueNames() {
-				row.Columns = append(row.Columns, k)
-				values = append(values, stat.Values[k])
-			}
-			row.Values = [][]interface{}{values}
-			rows = append(rows, row)
+	// Only indexes was requested.
+	if indexes && len(modules) == 0 {
+		return rows, nil
+	}
+
+	stats, err := e.Monitor.Statistics(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		if !stmt.MatchModule(stat.Name) {
+			continue
+		}
+		row := &models.Row{Name: stat.Name, Tags: stat.Tags}
+
+		values := make([]interface{}, 0, len(stat.Values))
+		for _, k := range stat.ValueNames() {
+			row.Columns = append(row.Columns, k)
+			values = append(values, stat.Values[k])
 		}
+		row.Values = [][]interface{}{values}
+		rows = append(rows, row)
 	}
 	return rows, nil
 }
 
 func (e *StatementExecutor) executeShowSubscriptionsStatement(stmt *cnosql.ShowSubscriptionsStatement) (models.Rows, error) {
+	deliveries, err := e.subscriptionDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
 	dis := e.MetaClient.Databases()
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"rp", "name", "mode", "destinations"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"rp", "name", "mode", "destinations", "weights", "filter", "points_written", "write_failures", "last_error", "unhealthy_destinations", "health_transitions", "queue_bytes", "queue_oldest_age", "points_discarded"}, Name: di.Name}
 		for _, rpi := range di.RetentionPolicies {
 			for _, si := range rpi.Subscriptions {
-				row.Values = append(row.Values, []interface{}{rpi.Name, si.Name, si.Mode, si.Destinations})
+				// Subscriptions this node has not delivered for report zeros.
+				d := deliveries[subscriptionKey{database: di.Name, rp: rpi.Name, name: si.Name}]
+				var weights, filter, oldestAge interface{}
+				if len(si.Weights) > 0 {
+					weights = si.Weights
+				}
+				if si.Filter != "" {
+					filter = si.Filter
+				}
+				if d.queueOldestAge > 0 {
+					oldestAge = d.queueOldestAge.String()
+				}
+				// Never display the credentials of destinations.
+				destinations := make([]string, len(si.Destinations))
+				for i, dest := range si.Destinations {
+					destinations[i] = cnosql.SanitizeURL(dest)
+				}
+				row.Values = append(row.Values, []interface{}{rpi.Name, si.Name, si.Mode, destinations, weights, filter, d.pointsWritten, d.writeFailures, d.lastError, d.unhealthy, d.healthTransitions, d.queueBytes, oldestAge, d.pointsDiscarded})
 			}
 		}
 		if len(row.Values) > 0 {
@@ -926,6 +3281,271 @@ func (e *StatementExecutor) executeShowSubscriptionsStatement(stmt *cnosql.ShowS
 	return rows, nil
 }
 
+type subscriptionKey struct {
+	database string
+	rp       string
+	name     string
+}
+
+type subscriptionDelivery struct {
+	pointsWritten int64
+	writeFailures int64
+	lastError     string
+	lastErrorTime int64
+
+	unhealthy         []string // destinations failing their health check
+	healthTransitions int64
+
+	// Writes queued for retry after failing.
+	queueBytes      int64
+	queueOldestAge  time.Duration
+	pointsDiscarded int64
+}
+
+// subscriptionDeliveries sums the subscriber statistics of every destination
+// of each subscription, keeping the most recent write error and listing the
+// unhealthy destinations, along with the state of its retry queue.
+func (e *StatementExecutor) subscriptionDeliveries() (map[subscriptionKey]subscriptionDelivery, error) {
+	deliveries := make(map[subscriptionKey]subscriptionDelivery)
+	if e.Monitor == nil {
+		return deliveries, nil
+	}
+
+	stats, err := e.Monitor.Statistics(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		k := subscriptionKey{database: stat.Tags["database"], rp: stat.Tags["time_to_live"], name: stat.Tags["name"]}
+		if stat.Name == "subscriber_queue" {
+			d := deliveries[k]
+			d.queueBytes, _ = stat.Values["queueBytes"].(int64)
+			if v, ok := stat.Values["oldestAgeNs"].(int64); ok {
+				d.queueOldestAge = time.Duration(v)
+			}
+			d.pointsDiscarded, _ = stat.Values["pointsDiscarded"].(int64)
+			deliveries[k] = d
+			continue
+		} else if stat.Name != "subscriber" || stat.Tags["destination"] == "" {
+			continue
+		}
+
+		d := deliveries[k]
+		if v, ok := stat.Values["pointsWritten"].(int64); ok {
+			d.pointsWritten += v
+		}
+		if v, ok := stat.Values["writeFailures"].(int64); ok {
+			d.writeFailures += v
+		}
+		if t, ok := stat.Values["lastErrorTime"].(int64); ok && t > d.lastErrorTime {
+			d.lastErrorTime = t
+			d.lastError, _ = stat.Values["lastError"].(string)
+		}
+		if healthy, ok := stat.Values["healthy"].(bool); ok && !healthy {
+			d.unhealthy = append(d.unhealthy, stat.Tags["destination"])
+		}
+		for _, k := range []string{"healthyTransitions", "unhealthyTransitions"} {
+			if v, ok := stat.Values[k].(int64); ok {
+				d.healthTransitions += v
+			}
+		}
+		deliveries[k] = d
+	}
+	return deliveries, nil
+}
+
+// executeShowFieldKeys returns the field keys of each measurement along with
+// their type. A field written with different types in different shards is
+// returned once per type.
+func (e *StatementExecutor) executeShowFieldKeys(ctx *query.ExecutionContext, q *cnosql.ShowFieldKeysStatement) error {
+	database := q.Database
+	if len(q.Sources) > 0 {
+		database = q.Sources[0].(*cnosql.Measurement).Database
+	}
+	if database == "" {
+		return ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(database)
+	if di == nil {
+		return fmt.Errorf("database not found: %s", database)
+	}
+
+	rps, conds, err := sourceConditions(di, q.Sources)
+	if err != nil {
+		return err
+	}
+
+	var fieldKeys []tsdb.FieldKeys
+	for _, rp := range rps {
+		rpi := di.RetentionPolicy(rp)
+		if rpi == nil {
+			continue
+		}
+
+		keys, err := e.TSDBStore.FieldKeys(forRetentionPolicies(ctx.Authorizer, []string{rp}), retentionPolicyShardIDs(rpi), conds[rp])
+		if err != nil {
+			return ctx.Send(&query.Result{
+				Err: err,
+			})
+		}
+		fieldKeys = append(fieldKeys, keys...)
+	}
+
+	emitted := false
+	for _, m := range fieldKeys {
+		var values [][]interface{}
+		for _, fk := range m.Keys {
+			for _, typ := range fk.Types {
+				values = append(values, []interface{}{fk.Key, typ.String()})
+			}
+		}
+
+		if q.Offset > 0 {
+			if q.Offset >= len(values) {
+				values = nil
+			} else {
+				values = values[q.Offset:]
+			}
+		}
+		if q.Limit > 0 && q.Limit < len(values) {
+			values = values[:q.Limit]
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := ctx.Send(&query.Result{
+			Series: []*models.Row{{
+				Name:    m.Measurement,
+				Columns: []string{"fieldKey", "fieldType"},
+				Values:  values,
+			}},
+		}); err != nil {
+			return err
+		}
+		emitted = true
+	}
+
+	// Ensure at least one result is emitted.
+	if !emitted {
+		return ctx.Send(&query.Result{})
+	}
+	return nil
+}
+
+// sourceConditions groups sources by retention policy and returns, for each
+// one, a condition on _name matching its measurements. Without sources all
+// measurements of the default retention policy are matched.
+func sourceConditions(di *meta.DatabaseInfo, sources cnosql.Sources) ([]string, map[string]cnosql.Expr, error) {
+	var rps []string
+	conds := make(map[string]cnosql.Expr)
+	if len(sources) == 0 {
+		rps = append(rps, di.DefaultRetentionPolicy)
+		conds[di.DefaultRetentionPolicy] = nil
+	}
+	for _, src := range sources {
+		mm := src.(*cnosql.Measurement)
+		if mm.Database != di.Name {
+			return nil, nil, errors.New("sources must belong to the same database")
+		}
+
+		var expr cnosql.Expr
+		if mm.Regex != nil {
+			expr = &cnosql.BinaryExpr{Op: cnosql.EQREGEX, LHS: &cnosql.VarRef{Val: "_name"}, RHS: &cnosql.RegexLiteral{Val: mm.Regex.Val}}
+		} else {
+			expr = &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: &cnosql.VarRef{Val: "_name"}, RHS: &cnosql.StringLiteral{Val: mm.Name}}
+		}
+
+		if cond, ok := conds[mm.RetentionPolicy]; !ok {
+			rps = append(rps, mm.RetentionPolicy)
+			conds[mm.RetentionPolicy] = expr
+		} else {
+			conds[mm.RetentionPolicy] = &cnosql.BinaryExpr{Op: cnosql.OR, LHS: cond, RHS: expr}
+		}
+	}
+	return rps, conds, nil
+}
+
+// executeShowSeries streams the series keys matching the statement in chunks
+// of ctx.ChunkSize rather than materializing the whole result. A ChunkSize of
+// zero sends all keys in a single result, like the select emitter.
+func (e *StatementExecutor) executeShowSeries(ctx *query.ExecutionContext, q *cnosql.ShowSeriesStatement) error {
+	if q.Database == "" {
+		return ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return fmt.Errorf("database not found: %s", q.Database)
+	}
+
+	// Determine appropriate time range. If one or fewer time boundaries provided
+	// then min/max possible time should be used instead.
+	valuer := &cnosql.NowValuer{Now: time.Now()}
+	cond, timeRange, err := cnosql.ConditionExpr(q.Condition, valuer)
+	if err != nil {
+		return err
+	}
+
+	// Get the shards of the retention policies of the sources, so their
+	// series are authorized with the privileges on those policies.
+	shardIDs, auth, err := e.shardIDsByTimeRange(ctx.Authorizer, di, sourceRetentionPolicies(q.Sources, q.Database), timeRange)
+	if err != nil {
+		return err
+	}
+
+	var values [][]interface{}
+	send := func(partial bool) error {
+		err := ctx.Send(&query.Result{
+			Series: []*models.Row{{
+				Columns: []string{"key"},
+				Values:  values,
+			}},
+			Partial: partial,
+		})
+		values = nil
+		return err
+	}
+
+	// The store applies the sources, offset and limit, and stops reading
+	// the index once the limit is reached.
+	opt := tsdb.SeriesKeysOptions{Offset: q.Offset, Limit: q.Limit}
+	emitted := false
+	if err := e.TSDBStore.ForEachSeriesKey(auth, shardIDs, sourcesCondition(q.Sources, cond), opt, func(key string) error {
+		// Send a full chunk only once another key is known to follow.
+		if ctx.ChunkSize > 0 && len(values) == ctx.ChunkSize {
+			if err := send(true); err != nil {
+				return err
+			}
+			emitted = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		values = append(values, []interface{}{key})
+		return nil
+	}); err != nil {
+		return ctx.Send(&query.Result{Err: err})
+	}
+
+	if len(values) > 0 {
+		return send(false)
+	}
+
+	// Ensure at least one result is emitted.
+	if !emitted {
+		return ctx.Send(&query.Result{})
+	}
+	return nil
+}
+
 func (e *StatementExecutor) executeShowTagKeys(ctx *query.ExecutionContext, q *cnosql.ShowTagKeysStatement) error {
 	if q.Database == "" {
 		return ErrDatabaseNameRequired
@@ -947,23 +3567,12 @@ func (e *StatementExecutor) executeShowTagKeys(ctx *query.ExecutionContext, q *c
 	}
 
 	// Get all shards for all retention policies.
-	var allGroups []meta.ShardGroupInfo
-	for _, rpi := range di.RetentionPolicies {
-		sgis, err := e.MetaClient.ShardGroupsByTimeRange(q.Database, rpi.Name, timeRange.MinTime(), timeRange.MaxTime())
-		if err != nil {
-			return err
-		}
-		allGroups = append(allGroups, sgis...)
-	}
-
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
+	shardIDs, auth, err := e.shardIDsByTimeRange(ctx.Authorizer, di, nil, timeRange)
+	if err != nil {
+		return err
 	}
 
-	tagKeys, err := e.TSDBStore.TagKeys(ctx.Authorizer, shardIDs, cond)
+	tagKeys, err := e.TSDBStore.TagKeys(auth, shardIDs, cond)
 	if err != nil {
 		return ctx.Send(&query.Result{
 			Err: err,
@@ -972,6 +3581,9 @@ func (e *StatementExecutor) executeShowTagKeys(ctx *query.ExecutionContext, q *c
 
 	emitted := false
 	for _, m := range tagKeys {
+		if !sourcesMatchMeasurement(q.Sources, m.Measurement) {
+			continue
+		}
 		keys := m.Keys
 
 		if q.Offset > 0 {
@@ -1003,14 +3615,101 @@ func (e *StatementExecutor) executeShowTagKeys(ctx *query.ExecutionContext, q *c
 		}); err != nil {
 			return err
 		}
-		emitted = true
+		emitted = true
+	}
+
+	// Ensure at least one result is emitted.
+	if !emitted {
+		return ctx.Send(&query.Result{})
+	}
+	return nil
+}
+
+// shardIDsByTimeRange returns the ids of the shards of the database's shard
+// groups overlapping the time range in the named retention policies, or in
+// all of them if rps is nil. Shard groups of different retention policies
+// may overlap, so each id is only returned once. Retention policies the
+// authorizer may not read are skipped. The returned fine authorizer checks
+// the series of the shards with the privileges of auth on the retention
+// policies read. The maximum query range of the retention policies is not
+// applied, see checkQueryRange.
+func (e *StatementExecutor) shardIDsByTimeRange(auth query.FineAuthorizer, di *meta.DatabaseInfo, rps []string, timeRange cnosql.TimeRange) ([]uint64, query.FineAuthorizer, error) {
+	if rps == nil {
+		for _, rpi := range di.RetentionPolicies {
+			rps = append(rps, rpi.Name)
+		}
+	}
+
+	var shardIDs []uint64
+	var readRPs []string
+	seen := make(map[uint64]struct{})
+	for _, rp := range rps {
+		if !authorizeRetentionPolicyRead(auth, di.Name, rp) {
+			continue
+		}
+		readRPs = append(readRPs, rp)
+		sgis, err := e.MetaClient.ShardGroupsByTimeRange(di.Name, rp, timeRange.MinTime(), timeRange.MaxTime())
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, sgi := range sgis {
+			for _, si := range sgi.Shards {
+				if _, ok := seen[si.ID]; ok {
+					continue
+				}
+				seen[si.ID] = struct{}{}
+				shardIDs = append(shardIDs, si.ID)
+			}
+		}
+	}
+	return shardIDs, forRetentionPolicies(auth, readRPs), nil
+}
+
+// sourceRetentionPolicies returns the retention policies of database named
+// by the measurement sources. It returns nil, for all retention policies, if
+// there are no sources or a source names no retention policy.
+func sourceRetentionPolicies(sources cnosql.Sources, database string) []string {
+	if len(sources) == 0 {
+		return nil
+	}
+	rps := []string{}
+	seen := make(map[string]struct{})
+	for _, src := range sources {
+		mm, ok := src.(*cnosql.Measurement)
+		if !ok || (mm.Database != "" && mm.Database != database) {
+			continue
+		} else if mm.RetentionPolicy == "" {
+			return nil
+		}
+		if _, ok := seen[mm.RetentionPolicy]; !ok {
+			seen[mm.RetentionPolicy] = struct{}{}
+			rps = append(rps, mm.RetentionPolicy)
+		}
 	}
+	return rps
+}
 
-	// Ensure at least one result is emitted.
-	if !emitted {
-		return ctx.Send(&query.Result{})
+// sourcesMatchMeasurement returns true if name is selected by any of the
+// measurement sources, matching regexes the same way SELECT does. An empty
+// source list selects every measurement.
+func sourcesMatchMeasurement(sources cnosql.Sources, name string) bool {
+	if len(sources) == 0 {
+		return true
 	}
-	return nil
+	for _, src := range sources {
+		mm, ok := src.(*cnosql.Measurement)
+		if !ok {
+			continue
+		}
+		if mm.Regex != nil {
+			if mm.Regex.Val.MatchString(name) {
+				return true
+			}
+		} else if mm.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (e *StatementExecutor) executeShowTagValues(ctx *query.ExecutionContext, q *cnosql.ShowTagValuesStatement) error {
@@ -1034,30 +3733,42 @@ func (e *StatementExecutor) executeShowTagValues(ctx *query.ExecutionContext, q
 	}
 
 	// Get all shards for all retention policies.
-	var allGroups []meta.ShardGroupInfo
-	for _, rpi := range di.RetentionPolicies {
-		sgis, err := e.MetaClient.ShardGroupsByTimeRange(q.Database, rpi.Name, timeRange.MinTime(), timeRange.MaxTime())
-		if err != nil {
-			return err
-		}
-		allGroups = append(allGroups, sgis...)
+	shardIDs, auth, err := e.shardIDsByTimeRange(ctx.Authorizer, di, nil, timeRange)
+	if err != nil {
+		return err
 	}
 
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
+	// Restrict the lookup to the WITH KEY tag keys.
+	if keyCond := tagKeyCondition(q.Op, q.TagKeyExpr); keyCond != nil {
+		if cond == nil {
+			cond = keyCond
+		} else {
+			cond = &cnosql.BinaryExpr{
+				Op:  cnosql.AND,
+				LHS: &cnosql.ParenExpr{Expr: cond},
+				RHS: &cnosql.ParenExpr{Expr: keyCond},
+			}
 		}
 	}
 
-	tagValues, err := e.TSDBStore.TagValues(ctx.Authorizer, shardIDs, cond)
+	tagValues, err := e.TSDBStore.TagValuesWithOptions(auth, shardIDs, cond, tsdb.TagValuesOptions{
+		Offset: q.Offset,
+		Limit:  q.Limit,
+	})
 	if err != nil {
 		return ctx.Send(&query.Result{Err: err})
 	}
 
 	emitted := false
 	for _, m := range tagValues {
-		values := m.Values
+		// Filter on the key again rather than trusting the store, so that
+		// pagination only counts values of the requested keys.
+		values := make([]tsdb.KeyValue, 0, len(m.Values))
+		for _, v := range m.Values {
+			if tagKeyMatches(q.Op, q.TagKeyExpr, v.Key) {
+				values = append(values, v)
+			}
+		}
 
 		if q.Offset > 0 {
 			if q.Offset >= len(values) {
@@ -1101,43 +3812,350 @@ func (e *StatementExecutor) executeShowTagValues(ctx *query.ExecutionContext, q
 	return nil
 }
 
+// executeShowTagValuesCardinalityStatement counts the distinct values of the
+// WITH KEY tag keys per measurement. Tag values are not sketched, so both the
+// estimated and the EXACT form count the values in the index.
+func (e *StatementExecutor) executeShowTagValuesCardinalityStatement(ctx *query.ExecutionContext, q *cnosql.ShowTagValuesCardinalityStatement) (models.Rows, error) {
+	if q.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+	if len(q.Dimensions) > 0 {
+		return nil, errors.New("SHOW TAG VALUES CARDINALITY doesn't support GROUP BY")
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return nil, fmt.Errorf("database not found: %s", q.Database)
+	}
+
+	// Determine appropriate time range. If one or fewer time boundaries provided
+	// then min/max possible time should be used instead.
+	valuer := &cnosql.NowValuer{Now: time.Now()}
+	cond, timeRange, err := cnosql.ConditionExpr(q.Condition, valuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all shards for all retention policies.
+	shardIDs, auth, err := e.shardIDsByTimeRange(ctx.Authorizer, di, nil, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := e.TSDBStore.TagValuesCardinality(auth, shardIDs, tagKeyCondition(q.Op, q.TagKeyExpr), cond)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(counts) {
+			counts = nil
+		} else {
+			counts = counts[q.Offset:]
+		}
+	}
+	if q.Limit > 0 && q.Limit < len(counts) {
+		counts = counts[:q.Limit]
+	}
+
+	rows := make(models.Rows, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, &models.Row{
+			Name:    c.Measurement,
+			Columns: []string{"count"},
+			Values:  [][]interface{}{{c.N}},
+		})
+	}
+	return rows, nil
+}
+
+// tagKeyCondition returns the condition on _tagKey for a WITH KEY clause.
+func tagKeyCondition(op cnosql.Token, expr cnosql.Literal) cnosql.Expr {
+	if expr == nil {
+		return nil
+	}
+	list, ok := expr.(*cnosql.ListLiteral)
+	if !ok {
+		return &cnosql.BinaryExpr{Op: op, LHS: &cnosql.VarRef{Val: "_tagKey"}, RHS: expr}
+	}
+
+	var cond cnosql.Expr
+	for _, tagKey := range list.Vals {
+		tagExpr := &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: &cnosql.VarRef{Val: "_tagKey"}, RHS: &cnosql.StringLiteral{Val: tagKey}}
+		if cond == nil {
+			cond = tagExpr
+		} else {
+			cond = &cnosql.BinaryExpr{Op: cnosql.OR, LHS: cond, RHS: tagExpr}
+		}
+	}
+	return cond
+}
+
+// tagKeyMatches returns true if key satisfies a WITH KEY clause.
+func tagKeyMatches(op cnosql.Token, expr cnosql.Literal, key string) bool {
+	switch expr := expr.(type) {
+	case *cnosql.ListLiteral:
+		for _, k := range expr.Vals {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	case *cnosql.StringLiteral:
+		switch op {
+		case cnosql.EQ:
+			return key == expr.Val
+		case cnosql.NEQ:
+			return key != expr.Val
+		}
+	case *cnosql.RegexLiteral:
+		switch op {
+		case cnosql.EQREGEX:
+			return expr.Val.MatchString(key)
+		case cnosql.NEQREGEX:
+			return !expr.Val.MatchString(key)
+		}
+	}
+	return true
+}
+
 func (e *StatementExecutor) executeShowUsersStatement(q *cnosql.ShowUsersStatement) (models.Rows, error) {
-	row := &models.Row{Columns: []string{"user", "admin"}}
+	if q.WithDetail {
+		row := &models.Row{Columns: []string{"user", "admin", "locked", "external", "default_database", "default_retention_policy", "created", "last_login"}}
+		for _, ui := range e.MetaClient.Users() {
+			row.Values = append(row.Values, []interface{}{ui.Name, ui.Admin, ui.Locked, ui.External, ui.DefaultDatabase, ui.DefaultRetentionPolicy, formatOptionalTime(ui.CreatedAt), formatOptionalTime(ui.LastLogin)})
+		}
+		return []*models.Row{row}, nil
+	}
+
+	if !q.WithGrants {
+		row := &models.Row{Columns: []string{"user", "admin", "locked", "external", "default_database", "default_retention_policy"}}
+		for _, ui := range e.MetaClient.Users() {
+			row.Values = append(row.Values, []interface{}{ui.Name, ui.Admin, ui.Locked, ui.External, ui.DefaultDatabase, ui.DefaultRetentionPolicy})
+		}
+		return []*models.Row{row}, nil
+	}
+
+	// One row per user and database privilege. Users without any grants
+	// still get a row so admins are not left out.
+	row := &models.Row{Columns: []string{"user", "admin", "locked", "external", "default_database", "default_retention_policy", "database", "privilege"}}
+	for _, ui := range e.MetaClient.Users() {
+		priv, err := e.MetaClient.UserPrivileges(ui.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		databases := make([]string, 0, len(priv))
+		for d := range priv {
+			databases = append(databases, d)
+		}
+		sort.Strings(databases)
+
+		if len(databases) == 0 {
+			row.Values = append(row.Values, []interface{}{ui.Name, ui.Admin, ui.Locked, ui.External, ui.DefaultDatabase, ui.DefaultRetentionPolicy, nil, nil})
+		}
+		for _, d := range databases {
+			row.Values = append(row.Values, []interface{}{ui.Name, ui.Admin, ui.Locked, ui.External, ui.DefaultDatabase, ui.DefaultRetentionPolicy, d, priv[d].String()})
+		}
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowRolesStatement(q *cnosql.ShowRolesStatement) (models.Rows, error) {
+	// Collect the users granted each role.
+	users := make(map[string][]string)
 	for _, ui := range e.MetaClient.Users() {
-		row.Values = append(row.Values, []interface{}{ui.Name, ui.Admin})
+		for _, r := range ui.Roles {
+			users[r] = append(users[r], ui.Name)
+		}
+	}
+
+	row := &models.Row{Columns: []string{"role", "users"}}
+	for _, ri := range e.MetaClient.Roles() {
+		members := users[ri.Name]
+		if members == nil {
+			members = []string{}
+		}
+		row.Values = append(row.Values, []interface{}{ri.Name, members})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeCreateTokenStatement(ctx *query.ExecutionContext, q *cnosql.CreateTokenStatement) (models.Rows, error) {
+	// Without FOR the token is for the user running the statement.
+	username := q.User
+	if username == "" {
+		username = ctx.UserID
+	}
+	if username == "" {
+		return nil, errors.New("CREATE TOKEN requires FOR <user> when authentication is disabled")
+	}
+
+	var expiry time.Time
+	if q.Duration > 0 {
+		expiry = time.Now().UTC().Add(q.Duration)
+	}
+
+	token, ti, err := e.MetaClient.CreateToken(username, q.Database, q.Privilege, expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	// This is the only time the token is shown, only its hash is kept.
+	row := &models.Row{Columns: []string{"id", "user", "token", "expiry"}}
+	row.Values = append(row.Values, []interface{}{ti.ID, ti.User, token, formatOptionalTime(ti.Expiry)})
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeRevokeTokenStatement(ctx *query.ExecutionContext, q *cnosql.RevokeTokenStatement) error {
+	// Users who aren't admins may only revoke their own tokens.
+	if ctx.UserID != "" && !ctx.UserAdmin {
+		owned := false
+		for _, ti := range e.MetaClient.Tokens() {
+			if ti.ID == q.ID {
+				owned = ti.User == ctx.UserID
+				break
+			}
+		}
+		if !owned {
+			return meta.ErrTokenNotFound
+		}
+	}
+	return e.MetaClient.DropToken(q.ID)
+}
+
+func (e *StatementExecutor) executeShowTokensStatement(ctx *query.ExecutionContext, q *cnosql.ShowTokensStatement) (models.Rows, error) {
+	// Users who aren't admins only see their own tokens.
+	username := q.User
+	if username == "" && ctx.UserID != "" && !ctx.UserAdmin {
+		username = ctx.UserID
+	}
+
+	// The secret of a token is never shown.
+	row := &models.Row{Columns: []string{"id", "user", "database", "privilege", "created", "expiry", "last_used"}}
+	for _, ti := range e.MetaClient.Tokens() {
+		if username != "" && ti.User != username {
+			continue
+		}
+		var database, privilege interface{}
+		if ti.Privilege != cnosql.NoPrivileges {
+			database, privilege = ti.Database, ti.Privilege.String()
+		}
+		row.Values = append(row.Values, []interface{}{
+			ti.ID,
+			ti.User,
+			database,
+			privilege,
+			formatOptionalTime(ti.CreatedAt),
+			formatOptionalTime(ti.Expiry),
+			formatOptionalTime(ti.LastUsed),
+		})
 	}
 	return []*models.Row{row}, nil
 }
 
+// formatOptionalTime returns t formatted for listings, or nil if t is zero.
+func formatOptionalTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// intoProgress decides when a SELECT INTO reports the number of points it
+// has written so far.
+type intoProgress struct {
+	points   int64
+	interval time.Duration
+
+	lastN int64
+	last  time.Time
+}
+
+// due returns true if progress should be reported after n points have been
+// written, and if so records it as the last report.
+func (p *intoProgress) due(n int64, now time.Time) bool {
+	if n == p.lastN {
+		return false
+	} else if (p.points <= 0 || n-p.lastN < p.points) && (p.interval <= 0 || now.Sub(p.last) < p.interval) {
+		return false
+	}
+	p.lastN, p.last = n, now
+	return true
+}
+
 // BufferedPointsWriter adds buffering to a pointsWriter so that SELECT INTO queries
 // write their points to the destination in batches.
 type BufferedPointsWriter struct {
+	// Approximate bytes of the buffered points and the batches being
+	// written. Updated atomically and kept first for 64-bit alignment.
+	bytes int64
+
 	w               pointsWriter
 	buf             []models.Point
+	capacity        int
 	database        string
 	retentionPolicy string
+
+	// Buffered points are written at least this often. Zero disables it.
+	flushInterval time.Duration
+	lastFlush     time.Time
+
+	// Paces the batches written. Nil is unlimited.
+	limiter    limiter.Rate
+	limiterCtx context.Context
+
+	// Optional counter of writers waiting on their limiter.
+	throttledN *int64
+
+	// Limits the batches written concurrently. Nil writes synchronously.
+	workers chan struct{}
+	wg      sync.WaitGroup
+
+	mu  sync.Mutex
+	err error // first error of a worker
+
+	// Optional counter of points buffered across writers.
+	bufferedN *int64
 }
 
 // NewBufferedPointsWriter returns a new BufferedPointsWriter.
 func NewBufferedPointsWriter(w pointsWriter, database, retentionPolicy string, capacity int) *BufferedPointsWriter {
+	// The batch size may come from the client, so the buffer grows as points
+	// are written instead of being allocated up front.
+	size := capacity
+	if size > DefaultIntoWriteBatchSize {
+		size = DefaultIntoWriteBatchSize
+	}
 	return &BufferedPointsWriter{
 		w:               w,
-		buf:             make([]models.Point, 0, capacity),
+		buf:             make([]models.Point, 0, size),
+		capacity:        capacity,
 		database:        database,
 		retentionPolicy: retentionPolicy,
 	}
 }
 
+// NewBufferedPointsWriterWithInterval returns a new BufferedPointsWriter whose
+// FlushIfDue writes out the buffered points once interval has passed since the
+// last write.
+func NewBufferedPointsWriterWithInterval(w pointsWriter, database, retentionPolicy string, capacity int, interval time.Duration) *BufferedPointsWriter {
+	bw := NewBufferedPointsWriter(w, database, retentionPolicy, capacity)
+	bw.flushInterval = interval
+	bw.lastFlush = time.Now()
+	return bw
+}
+
 // WritePointsInto implements pointsWriter for BufferedPointsWriter.
 func (w *BufferedPointsWriter) WritePointsInto(req *IntoWriteRequest) error {
 	// Make sure we're buffering points only for the expected destination.
 	if req.Database != w.database || req.RetentionPolicy != w.retentionPolicy {
-		return fmt.Errorf("writer for %s.%s can't write into %s.%s", w.database, w.retentionPolicy, req.Database, req.RetentionPolicy)
+		return fmt.Errorf("writer for %q.%q can't write into %q.%q", w.database, w.retentionPolicy, req.Database, req.RetentionPolicy)
 	}
 
 	for i := 0; i < len(req.Points); {
 		// Get the available space in the buffer.
-		avail := cap(w.buf) - len(w.buf)
+		avail := w.capacity - len(w.buf)
 
 		// Calculate number of points to copy into the buffer.
 		n := len(req.Points[i:])
@@ -1147,13 +4165,14 @@ func (w *BufferedPointsWriter) WritePointsInto(req *IntoWriteRequest) error {
 
 		// Copy points into buffer.
 		w.buf = append(w.buf, req.Points[i:n+i]...)
+		w.addBuffered(req.Points[i : n+i])
 
 		// Advance the index by number of points copied.
 		i += n
 
-		// If buffer is full, flush points to underlying writer.
-		if len(w.buf) == cap(w.buf) {
-			if err := w.Flush(); err != nil {
+		// If buffer is full, write points to underlying writer.
+		if len(w.buf) == w.capacity {
+			if err := w.writeBuffer(); err != nil {
 				return err
 			}
 		}
@@ -1162,33 +4181,229 @@ func (w *BufferedPointsWriter) WritePointsInto(req *IntoWriteRequest) error {
 	return nil
 }
 
-// Flush writes all buffered points to the underlying writer.
+// SetWorkers makes the writer write full batches on up to n goroutines while
+// the caller keeps buffering. Batches may be written out of order. Values
+// below 2 write synchronously. It must be called before any points are written.
+func (w *BufferedPointsWriter) SetWorkers(n int) {
+	if n > 1 {
+		w.workers = make(chan struct{}, n)
+	} else {
+		w.workers = nil
+	}
+}
+
+// SetRateLimit limits the points per second the writer writes. Waiting for
+// the limit ends with ctx.
+func (w *BufferedPointsWriter) SetRateLimit(ctx context.Context, pointsPerSec int) {
+	// The burst must hold a whole batch, as batches are not split.
+	burst := w.capacity
+	if burst < pointsPerSec {
+		burst = pointsPerSec
+	}
+	w.limiter = limiter.NewRate(pointsPerSec, burst)
+	w.limiterCtx = ctx
+}
+
+// throttle waits until n points may be written under the rate limit.
+func (w *BufferedPointsWriter) throttle(n int) error {
+	if w.limiter == nil {
+		return nil
+	}
+	if w.throttledN != nil {
+		atomic.AddInt64(w.throttledN, 1)
+		defer atomic.AddInt64(w.throttledN, -1)
+	}
+	return w.limiter.WaitN(w.limiterCtx, n)
+}
+
+// Flush writes all buffered points to the underlying writer and waits for
+// the batches being written by workers. It returns the first write error.
 func (w *BufferedPointsWriter) Flush() error {
-	if len(w.buf) == 0 {
+	if err := w.writeBuffer(); err != nil {
+		return err
+	}
+	w.Wait()
+	return w.workerErr()
+}
+
+// Wait waits for the batches being written by workers.
+func (w *BufferedPointsWriter) Wait() { w.wg.Wait() }
+
+// writeBuffer writes the buffered points to the underlying writer, on a
+// worker if the writer has any. It returns the first error of a worker so the
+// caller stops as soon as a batch fails.
+func (w *BufferedPointsWriter) writeBuffer() error {
+	if err := w.workerErr(); err != nil {
+		return err
+	} else if len(w.buf) == 0 {
 		return nil
 	}
 
-	if err := w.w.WritePointsInto(&IntoWriteRequest{
-		Database:        w.database,
-		RetentionPolicy: w.retentionPolicy,
-		Points:          w.buf,
-	}); err != nil {
+	if err := w.throttle(len(w.buf)); err != nil {
 		return err
 	}
 
-	// Clear the buffer.
-	w.buf = w.buf[:0]
+	if w.workers == nil {
+		if err := w.w.WritePointsInto(&IntoWriteRequest{
+			Database:        w.database,
+			RetentionPolicy: w.retentionPolicy,
+			Points:          w.buf,
+		}); err != nil {
+			return err
+		}
+
+		// Clear the buffer.
+		w.removeBuffered(w.buf)
+		w.buf = w.buf[:0]
+		w.lastFlush = time.Now()
+		return nil
+	}
 
+	// Hand the buffer to a worker, waiting for one to be free.
+	points := w.buf
+	w.buf = make([]models.Point, 0, cap(points))
+	w.lastFlush = time.Now()
+
+	w.workers <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.workers
+			w.wg.Done()
+		}()
+
+		err := w.w.WritePointsInto(&IntoWriteRequest{
+			Database:        w.database,
+			RetentionPolicy: w.retentionPolicy,
+			Points:          points,
+		})
+		w.removeBuffered(points)
+		if err != nil {
+			w.mu.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.mu.Unlock()
+		}
+	}()
 	return nil
 }
 
+// workerErr returns the first error a worker failed to write a batch with.
+func (w *BufferedPointsWriter) workerErr() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// addBuffered counts points as buffered until removeBuffered is called
+// with them.
+func (w *BufferedPointsWriter) addBuffered(points []models.Point) {
+	atomic.AddInt64(&w.bytes, pointsSize(points))
+	if w.bufferedN != nil {
+		atomic.AddInt64(w.bufferedN, int64(len(points)))
+	}
+}
+
+func (w *BufferedPointsWriter) removeBuffered(points []models.Point) {
+	atomic.AddInt64(&w.bytes, -pointsSize(points))
+	if w.bufferedN != nil {
+		atomic.AddInt64(w.bufferedN, -int64(len(points)))
+	}
+}
+
+// pointsSize approximates the number of bytes held by points.
+func pointsSize(points []models.Point) int64 {
+	var n int64
+	for _, p := range points {
+		n += int64(p.StringSize())
+	}
+	return n
+}
+
+// FlushIfDue writes all buffered points if the writer has a flush interval
+// and it has passed since the last write. It also returns the first error of
+// a worker, so calling it between rows stops the caller after a failed batch.
+func (w *BufferedPointsWriter) FlushIfDue(now time.Time) error {
+	if w.flushInterval <= 0 || now.Sub(w.lastFlush) < w.flushInterval {
+		return w.workerErr()
+	}
+	if len(w.buf) == 0 {
+		w.lastFlush = now
+		return w.workerErr()
+	}
+	return w.writeBuffer()
+}
+
 // Len returns the number of points buffered.
 func (w *BufferedPointsWriter) Len() int { return len(w.buf) }
 
+// Bytes returns the approximate number of bytes of the buffered points and
+// the batches being written.
+func (w *BufferedPointsWriter) Bytes() int64 { return atomic.LoadInt64(&w.bytes) }
+
 // Cap returns the capacity (in points) of the buffer.
-func (w *BufferedPointsWriter) Cap() int { return cap(w.buf) }
+func (w *BufferedPointsWriter) Cap() int { return w.capacity }
+
+// authorizeTarget returns an error if the user of ctx may not write into the
+// database of target. Queries without a coarse authorizer, such as continuous
+// queries, are not restricted.
+func authorizeTarget(ctx *query.ExecutionContext, target *cnosql.Target) error {
+	database := target.Measurement.Database
+	if database == "" {
+		return errNoDatabaseInTarget
+	}
+	priv := target.RequiredPrivilege()
+	a := ctx.CoarseAuthorizer
+	if a == nil || a.AuthorizeDatabase(priv, database) {
+		return nil
+	}
+	return meta.ErrAuthorize{
+		User:     ctx.UserID,
+		Database: database,
+		Message:  fmt.Sprintf("%s, requires %s on target database %s", target, priv, database),
+	}
+}
+
+// ensureTargetRetentionPolicy returns an error if the retention policy of
+// the INTO target does not exist, unless the target has WITH CREATE. Then the
+// policy is created with the duration, replication and shard duration of the
+// first source measurement's retention policy, or with defaults.
+func (e *StatementExecutor) ensureTargetRetentionPolicy(stmt *cnosql.SelectStatement) error {
+	m := stmt.Target.Measurement
+	if rpi, err := e.MetaClient.RetentionPolicy(m.Database, m.RetentionPolicy); err != nil {
+		return err
+	} else if rpi != nil {
+		return nil
+	} else if !stmt.Target.Create {
+		return fmt.Errorf("target retention policy %q does not exist on database %q: create it or use INTO ... WITH CREATE", m.RetentionPolicy, m.Database)
+	}
+
+	spec := &meta.RetentionPolicySpec{Name: m.RetentionPolicy}
+	for _, source := range stmt.Sources {
+		sm, ok := source.(*cnosql.Measurement)
+		if !ok {
+			continue
+		}
+		rpi, err := e.MetaClient.RetentionPolicy(sm.Database, sm.RetentionPolicy)
+		if err != nil || rpi == nil {
+			continue
+		}
+		duration, replicaN := rpi.Duration, rpi.ReplicaN
+		spec.Duration = &duration
+		spec.ReplicaN = &replicaN
+		spec.ShardGroupDuration = rpi.ShardGroupDuration
+		break
+	}
+
+	// Another statement may have created the policy in the meantime.
+	if _, err := e.MetaClient.CreateRetentionPolicy(m.Database, spec, false); err != nil && err != meta.ErrRetentionPolicyExists {
+		return err
+	}
+	return nil
+}
 
-func (e *StatementExecutor) writeInto(w pointsWriter, stmt *cnosql.SelectStatement, row *models.Row) (n int64, err error) {
+func (e *StatementExecutor) writeInto(w pointsWriter, stmt *cnosql.SelectStatement, row *models.Row, drops *intoDrops) (n int64, err error) {
 	if stmt.Target.Measurement.Database == "" {
 		return 0, errNoDatabaseInTarget
 	}
@@ -1200,12 +4415,15 @@ func (e *StatementExecutor) writeInto(w pointsWriter, stmt *cnosql.SelectStateme
 	// it might seem weird to have the write be in the Executor, but the interweaving of
 	// limitedRowWriter and ExecuteAggregate/Raw makes it ridiculously hard to make sure that the
 	// results will be the same as when queried normally.
-	name := stmt.Target.Measurement.Name
+	name := stmt.Target.MeasurementName(row.Name)
 	if name == "" {
-		name = row.Name
+		return 0, errNoMeasurementInTarget
 	}
 
-	points, err := convertRowToPoints(name, row)
+	// Series are grouped by the statement's tag dimensions, so those are
+	// written as tags of the target to keep one target series per group.
+	_, tagKeys := stmt.Dimensions.Normalize()
+	points, err := convertRowToPoints(name, row, tagKeys, drops)
 	if err != nil {
 		return 0, err
 	}
@@ -1223,14 +4441,61 @@ func (e *StatementExecutor) writeInto(w pointsWriter, stmt *cnosql.SelectStateme
 
 var errNoDatabaseInTarget = errors.New("no database in target")
 
+var errNoMeasurementInTarget = errors.New("no measurement in target: the source of the :MEASUREMENT back-reference has no name")
+
+// maxIntoDropReasons is the number of reasons for dropped points a SELECT
+// INTO reports.
+const maxIntoDropReasons = 5
+
+// intoDrops counts the result values of a SELECT INTO that could not be
+// converted into points and keeps the reasons for the first few.
+type intoDrops struct {
+	n       int64
+	reasons []string
+}
+
+// add records a dropped point of measurement name at time t.
+func (d *intoDrops) add(name string, t time.Time, err error) {
+	d.n++
+	if len(d.reasons) < maxIntoDropReasons {
+		d.reasons = append(d.reasons, fmt.Sprintf("%s at %s: %s", name, t.UTC().Format(time.RFC3339Nano), err))
+	}
+}
+
+// Message returns a warning summarizing the dropped points, or nil if no
+// points were dropped.
+func (d *intoDrops) Message() *query.Message {
+	if d.n == 0 {
+		return nil
+	}
+	text := fmt.Sprintf("dropped %d points that could not be written: %s", d.n, strings.Join(d.reasons, "; "))
+	if d.n > int64(len(d.reasons)) {
+		text += "; ..."
+	}
+	return &query.Message{Level: query.WarningLevel, Text: text}
+}
+
 // convertRowToPoints will convert a query result Row into Points that can be written back in.
-func convertRowToPoints(measurementName string, row *models.Row) ([]models.Point, error) {
-	// figure out which parts of the result are the time and which are the fields
+// The row's tags become the tags of the points. tagKeys are the GROUP BY tag
+// dimensions of the query: a column with one of those names is written as a
+// tag rather than a field. Values that can't be stored are dropped and
+// recorded in drops.
+func convertRowToPoints(measurementName string, row *models.Row, tagKeys []string, drops *intoDrops) ([]models.Point, error) {
+	isTagKey := make(map[string]bool, len(tagKeys))
+	for _, k := range tagKeys {
+		isTagKey[k] = true
+	}
+
+	// figure out which parts of the result are the time, which are tags and
+	// which are the fields
 	timeIndex := -1
 	fieldIndexes := make(map[string]int)
+	tagIndexes := make(map[string]int)
 	for i, c := range row.Columns {
 		if c == "time" {
 			timeIndex = i
+		} else if isTagKey[c] {
+			tagIndexes[c] = i
 		} else {
 			fieldIndexes[c] = i
 		}
@@ -1254,9 +4519,24 @@ func convertRowToPoints(measurementName string, row *models.Row) ([]models.Point
 			}
 		}
 
-		p, err := models.NewPoint(measurementName, models.NewTags(row.Tags), vals, v[timeIndex].(time.Time))
+		tags := row.Tags
+		if len(tagIndexes) > 0 {
+			tags = make(map[string]string, len(row.Tags)+len(tagIndexes))
+			for k, val := range row.Tags {
+				tags[k] = val
+			}
+			for tagKey, tagIndex := range tagIndexes {
+				if tags[tagKey] == "" && v[tagIndex] != nil {
+					tags[tagKey] = fmt.Sprint(v[tagIndex])
+				}
+			}
+		}
+
+		t := v[timeIndex].(time.Time)
+		p, err := models.NewPoint(measurementName, models.NewTags(tags), vals, t)
 		if err != nil {
 			// Drop points that can't be stored
+			drops.add(measurementName, t, err)
 			continue
 		}
 
@@ -1286,6 +4566,14 @@ func (e *StatementExecutor) NormalizeStatement(stmt cnosql.Statement, defaultDat
 			if node.Database == "" {
 				node.Database = defaultDatabase
 			}
+		case *cnosql.ShowTagKeyCardinalityStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *cnosql.ShowTagValuesCardinalityStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
 		case *cnosql.ShowTagValuesStatement:
 			if node.Database == "" {
 				node.Database = defaultDatabase
@@ -1298,11 +4586,27 @@ func (e *StatementExecutor) NormalizeStatement(stmt cnosql.Statement, defaultDat
 			if node.Database == "" {
 				node.Database = defaultDatabase
 			}
+		case *cnosql.ShowFieldKeyCardinalityStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *cnosql.ShowFieldKeysStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *cnosql.ShowSeriesStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
 		case *cnosql.Measurement:
-			switch stmt.(type) {
+			switch stmt := stmt.(type) {
 			case *cnosql.DropSeriesStatement, *cnosql.DeleteSeriesStatement:
 				// DB and RP not supported by these statements so don't rewrite into invalid
 				// statements
+			case *cnosql.ExplainStatement:
+				if stmt.SeriesStatement == nil {
+					err = e.normalizeMeasurement(node, defaultDatabase, defaultRetentionPolicy)
+				}
 			default:
 				err = e.normalizeMeasurement(node, defaultDatabase, defaultRetentionPolicy)
 			}
@@ -1334,12 +4638,16 @@ func (e *StatementExecutor) normalizeMeasurement(m *cnosql.Measurement, defaultD
 		return cnosdb.ErrDatabaseNotFound(m.Database)
 	}
 
-	// If no retention policy was specified, use the default.
+	// If no retention policy was specified, use the default. The retention
+	// policy requested for the query belongs to the default database, so an
+	// INTO target in another database uses the default of its own database.
 	if m.RetentionPolicy == "" {
-		if defaultRetentionPolicy != "" {
+		if defaultRetentionPolicy != "" && (!m.IsTarget || m.Database == defaultDatabase) {
 			m.RetentionPolicy = defaultRetentionPolicy
 		} else if di.DefaultRetentionPolicy != "" {
 			m.RetentionPolicy = di.DefaultRetentionPolicy
+		} else if m.IsTarget {
+			return fmt.Errorf("default retention policy not set for target database: %s", di.Name)
 		} else {
 			return fmt.Errorf("default retention policy not set for: %s", di.Name)
 		}
@@ -1368,12 +4676,25 @@ type TSDBStore interface {
 	DeleteSeries(database string, sources []cnosql.Source, condition cnosql.Expr) error
 	DeleteShard(id uint64) error
 
-	MeasurementNames(auth query.FineAuthorizer, database string, cond cnosql.Expr) ([][]byte, error)
+	ShardsDiskSize(ids []uint64) (int64, error)
+	ShardDiskStats(ids []uint64) (map[uint64]tsdb.ShardDiskStat, error)
+	ShardState(id uint64) string
+
+	MeasurementNames(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) ([][]byte, error)
+	TagValuesCardinality(auth query.FineAuthorizer, shardIDs []uint64, key, cond cnosql.Expr) ([]tsdb.MeasurementTagValueN, error)
+	FieldKeyCardinality(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, exact bool) ([]tsdb.MeasurementFieldKeyN, error)
+	FieldKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.FieldKeys, error)
+	ForEachSeriesKey(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error
+	SeriesCardinalityByMeasurement(auth query.FineAuthorizer, database string) ([]tsdb.MeasurementSeriesN, error)
+	MeasurementSeriesCardinality(ctx context.Context, auth query.FineAuthorizer, database string, names [][]byte, concurrency int) ([]int64, error)
 	TagKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error)
 	TagValues(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagValues, error)
+	TagValuesWithOptions(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.TagValuesOptions) ([]tsdb.TagValues, error)
 
-	SeriesCardinality(database string) (int64, error)
-	MeasurementsCardinality(database string) (int64, error)
+	SeriesCardinality(auth query.FineAuthorizer, database string) (int64, error)
+	MeasurementsCardinality(auth query.FineAuthorizer, database string) (int64, error)
+	IndexTypes(database string) []string
+	MeasurementsCardinalityByExpr(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) (int64, error)
 
 	ShardGroup(ids []uint64) tsdb.ShardGroup
 }
@@ -1391,6 +4712,14 @@ type ShardIteratorCreator interface {
 	ShardIteratorCreator(id uint64) query.IteratorCreator
 }
 
+// formatTimeIn renders t as RFC3339 in loc, or in UTC if loc is nil.
+func formatTimeIn(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
 // joinUint64 returns a comma-delimited string of uint64 numbers.
 func joinUint64(a []uint64) string {
 	var buf bytes.Buffer
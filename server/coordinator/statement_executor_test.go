@@ -0,0 +1,3173 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+	"github.com/cnosdb/cnosdb/vend/db/tsdb"
+)
+
+func TestStatementExecutor_ExecuteStatement_Timeout(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		timeout      time.Duration
+		queryTimeout time.Duration
+		kill         bool
+		exp          string
+	}{
+		{
+			name:    "statement timeout",
+			timeout: 20 * time.Millisecond,
+			exp:     "query timeout exceeded after 20ms",
+		},
+		{
+			name:         "statement timeout shorter than query timeout",
+			timeout:      20 * time.Millisecond,
+			queryTimeout: time.Minute,
+			exp:          "query timeout exceeded after 20ms",
+		},
+		{
+			name:         "query timeout shorter than statement timeout",
+			timeout:      time.Minute,
+			queryTimeout: 20 * time.Millisecond,
+			exp:          query.ErrQueryTimeoutLimitExceeded.Error(),
+		},
+		{
+			name:    "killed before statement timeout",
+			timeout: time.Minute,
+			kill:    true,
+			exp:     query.ErrQueryInterrupted.Error(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := query.NewTaskManager()
+			tm.QueryTimeout = tt.queryTimeout
+			defer tm.Close()
+
+			stmt := cnosql.MustParseStatement(`SELECT value FROM db0.rp0.cpu`)
+			ctx, detach, err := tm.AttachQuery(&cnosql.Query{Statements: cnosql.Statements{stmt}}, query.ExecutionOptions{
+				Database:  "db0",
+				UserAdmin: true,
+				Timeout:   tt.timeout,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer detach()
+			ctx.Results = make(chan *query.Result, 1)
+
+			if tt.kill {
+				time.AfterFunc(20*time.Millisecond, func() { tm.KillQuery(ctx.QueryID) })
+			}
+
+			e := &StatementExecutor{ShardMapper: &endlessShardMapper{}}
+			done := make(chan error, 1)
+			go func() { done <- e.ExecuteStatement(ctx, stmt) }()
+
+			select {
+			case err := <-done:
+				if err == nil || err.Error() != tt.exp {
+					t.Fatalf("unexpected error: %v, expected %q", err, tt.exp)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("statement did not stop")
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_QueryProgress(t *testing.T) {
+	tm := query.NewTaskManager()
+	defer tm.Close()
+
+	stmt := cnosql.MustParseStatement(`SELECT value FROM db0.rp0.cpu`)
+	ctx, detach, err := tm.AttachQuery(&cnosql.Query{Statements: cnosql.Statements{stmt}}, query.ExecutionOptions{
+		Database:  "db0",
+		UserAdmin: true,
+		ChunkSize: 10,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer detach()
+	ctx.Results = make(chan *query.Result)
+
+	e := &StatementExecutor{ShardMapper: &endlessShardMapper{}}
+	done := make(chan error, 1)
+	go func() { done <- e.ExecuteStatement(ctx, stmt) }()
+
+	progress := func() query.QueryInfo {
+		for _, qi := range tm.Queries() {
+			if qi.ID == ctx.QueryID {
+				return qi
+			}
+		}
+		t.Fatal("query not listed")
+		return query.QueryInfo{}
+	}
+
+	// The rows received are counted, and the row waiting for the receiver
+	// is held in memory.
+	for i := 0; i < 2; i++ {
+		<-ctx.Results
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for qi := progress(); qi.Rows != 20 || qi.MemoryBytes == 0; qi = progress() {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected progress: %d rows, %d bytes", qi.Rows, qi.MemoryBytes)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	results := make(chan *query.Result, 1)
+	if err := tm.ExecuteStatement(&query.ExecutionContext{Context: context.Background(), Results: results}, &cnosql.ShowQueriesStatement{}); err != nil {
+		t.Fatal(err)
+	}
+	row := (<-results).Series[0]
+	if exp := []string{"rows", "memory_bytes"}; !reflect.DeepEqual(row.Columns[6:8], exp) {
+		t.Fatalf("unexpected columns: %v", row.Columns)
+	} else if row.Values[0][6] != int64(20) || row.Values[0][7].(int64) <= 0 {
+		t.Fatalf("unexpected values: %v", row.Values[0])
+	}
+
+	tm.KillQuery(ctx.QueryID)
+	if err := <-done; err != query.ErrQueryInterrupted {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRowSize(t *testing.T) {
+	row := &models.Row{
+		Name:    "cpu",
+		Tags:    map[string]string{"host": "a"},
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{int64(0), "idle"}, {int64(1), nil}},
+	}
+	// The names, plus 16 bytes per value and the bytes of the strings.
+	if n := rowSize(row); n != 3+5+9+4*16+4 {
+		t.Fatalf("unexpected size: %d", n)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ExplainEstimates(t *testing.T) {
+	e := &StatementExecutor{
+		ShardMapper: &endlessShardMapper{cost: query.IteratorCost{
+			NumShards:    2,
+			NumSeries:    5,
+			CachedValues: 10,
+			BlocksRead:   3,
+		}},
+		MaxSelectSeriesN:  4,
+		MaxSelectBucketsN: 100,
+	}
+
+	for _, tt := range []struct {
+		name string
+		stmt string
+		exp  []string
+	}{
+		{
+			name: "raw",
+			stmt: `EXPLAIN SELECT value FROM db0.rp0.cpu`,
+			exp: []string{
+				"ESTIMATES",
+				"SHARDS: 2",
+				"SERIES OF cpu: 5",
+				"SERIES: 5",
+				"BUCKETS: 0",
+				"POINTS: 3010",
+				"LIMIT EXCEEDED: max-select-series (5/4)",
+			},
+		},
+		{
+			name: "aggregate",
+			stmt: `EXPLAIN SELECT mean(value), max(value) FROM db0.rp0.cpu, db0.rp0.mem WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-01T04:00:00Z' GROUP BY time(1m)`,
+			exp: []string{
+				"ESTIMATES",
+				"SHARDS: 2",
+				"SERIES OF cpu: 5",
+				"SERIES OF mem: 5",
+				"SERIES: 10",
+				"BUCKETS: 240",
+				"POINTS: 12040",
+				"LIMIT EXCEEDED: max-select-series (10/4)",
+				"LIMIT EXCEEDED: max-select-buckets (240/100)",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan := queryPlan(results)
+			i := indexOf(plan, "ESTIMATES")
+			if i < 0 {
+				t.Fatalf("no estimates in plan: %q", plan)
+			}
+			if got := plan[i:]; !equalStrings(got, tt.exp) {
+				t.Fatalf("unexpected estimates:\n%q\nexpected:\n%q", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ExplainSeries(t *testing.T) {
+	mustParseTime := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	shardGroup := func(id uint64, start string) meta.ShardGroupInfo {
+		return meta.ShardGroupInfo{
+			ID:        id,
+			StartTime: mustParseTime(start),
+			EndTime:   mustParseTime(start).Add(24 * time.Hour),
+			Shards:    []meta.ShardInfo{{ID: id * 10}},
+		}
+	}
+	deleted := shardGroup(3, "2000-01-03T00:00:00Z")
+	deleted.DeletedAt = time.Now()
+
+	var cond cnosql.Expr
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				if name != "db0" {
+					return nil
+				}
+				return &meta.DatabaseInfo{
+					Name: "db0",
+					RetentionPolicies: []meta.RetentionPolicyInfo{{
+						Name: "rp0",
+						ShardGroups: []meta.ShardGroupInfo{
+							shardGroup(1, "2000-01-01T00:00:00Z"),
+							shardGroup(2, "2000-01-02T00:00:00Z"),
+							deleted,
+						},
+					}},
+				}
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			ForEachSeriesKeyFn: func(auth query.FineAuthorizer, shardIDs []uint64, c cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error {
+				if !opt.Unordered {
+					t.Fatal("keys sorted")
+				}
+				cond = c
+				for _, key := range []string{"cpu,host=a", "cpu,host=b", "mem,host=a"} {
+					if err := fn(key); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			DeleteSeriesFn: func(database string, sources []cnosql.Source, condition cnosql.Expr) error {
+				t.Fatal("series deleted")
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		stmt string
+		cond string
+		exp  []string
+	}{
+		{
+			name: "delete",
+			stmt: `EXPLAIN DELETE FROM cpu, /m.*/ WHERE host = 'a' AND time >= '2000-01-02T00:00:00Z'`,
+			cond: `(_name = 'cpu' OR _name =~ /m.*/) AND (host = 'a')`,
+			exp: []string{
+				`STATEMENT: DELETE FROM cpu, /m.*/ WHERE host = 'a' AND time >= '2000-01-02T00:00:00Z'`,
+				"DATABASE: db0",
+				"TIME RANGE: 2000-01-02T00:00:00Z - 2262-04-11T23:47:16Z",
+				"MEASUREMENTS: 2",
+				"SERIES OF cpu: 2",
+				"SERIES OF mem: 1",
+				"SERIES: 3",
+				"SHARDS: 20",
+			},
+		},
+		{
+			name: "drop series",
+			stmt: `EXPLAIN DROP SERIES WHERE host = 'a'`,
+			cond: `host = 'a'`,
+			exp: []string{
+				`STATEMENT: DROP SERIES WHERE host = 'a'`,
+				"DATABASE: db0",
+				"MEASUREMENTS: 2",
+				"SERIES OF cpu: 2",
+				"SERIES OF mem: 1",
+				"SERIES: 3",
+				"SHARDS: 10,20",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{Database: "db0"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := queryPlan(results); !equalStrings(got, tt.exp) {
+				t.Fatalf("unexpected plan:\n%q\nexpected:\n%q", got, tt.exp)
+			}
+			if cond.String() != tt.cond {
+				t.Fatalf("unexpected condition: %s", cond)
+			}
+		})
+	}
+
+	if _, err := executeStatement(e, `EXPLAIN DELETE FROM cpu`, query.ExecutionOptions{Database: "db1"}); err == nil || err.Error() != "database not found: db1" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// executeStatement parses and executes stmt and returns the results e sent.
+func executeStatement(e *StatementExecutor, stmt string, opt query.ExecutionOptions) ([]*query.Result, error) {
+	s, err := cnosql.ParseStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &query.ExecutionContext{
+		Context:          context.Background(),
+		Results:          make(chan *query.Result, 100),
+		ExecutionOptions: opt,
+	}
+	err = e.ExecuteStatement(ctx, s)
+	close(ctx.Results)
+
+	var results []*query.Result
+	for r := range ctx.Results {
+		results = append(results, r)
+	}
+	return results, err
+}
+
+func TestStatementExecutor_ExecuteStatement_PurgeData(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		deferShardRemoval bool
+		deletionDelay     time.Duration
+		expGroups         []string
+		expShards         []uint64
+	}{
+		{name: "no retention service", deletionDelay: time.Hour, expGroups: []string{"purge 10"}, expShards: []uint64{1, 2}},
+		{name: "no deletion delay", deferShardRemoval: true, expGroups: []string{"purge 10"}, expShards: []uint64{1, 2}},
+		{name: "deletion delay", deferShardRemoval: true, deletionDelay: time.Hour, expGroups: []string{"delete 10"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			var deletedGroups []string
+			var deletedShards []uint64
+			e := &StatementExecutor{
+				MetaClient: &testMetaClient{
+					DatabaseFn: func(name string) *meta.DatabaseInfo {
+						return &meta.DatabaseInfo{
+							Name: name,
+							RetentionPolicies: []meta.RetentionPolicyInfo{{
+								Name:          "rp0",
+								DeletionDelay: tt.deletionDelay,
+								ShardGroups: []meta.ShardGroupInfo{{
+									ID:        10,
+									StartTime: start,
+									EndTime:   start.Add(24 * time.Hour),
+									Shards:    []meta.ShardInfo{{ID: 1}, {ID: 2}},
+								}},
+							}},
+						}
+					},
+					DeleteShardGroupFn: func(database, policy string, id uint64) error {
+						deletedGroups = append(deletedGroups, fmt.Sprintf("delete %d", id))
+						return nil
+					},
+					PurgeShardGroupFn: func(database, policy string, id uint64) error {
+						deletedGroups = append(deletedGroups, fmt.Sprintf("purge %d", id))
+						return nil
+					},
+				},
+				TSDBStore: &testTSDBStore{
+					DeleteShardFn: func(id uint64) error {
+						deletedShards = append(deletedShards, id)
+						return nil
+					},
+				},
+				DeferShardRemoval: tt.deferShardRemoval,
+			}
+
+			if _, err := executeStatement(e, `PURGE DATA ON db0 BEFORE '2001-01-01T00:00:00Z'`, query.ExecutionOptions{UserAdmin: true}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(deletedGroups, tt.expGroups) {
+				t.Fatalf("unexpected shard groups deleted: got %q, exp %q", deletedGroups, tt.expGroups)
+			}
+			if !reflect.DeepEqual(deletedShards, tt.expShards) {
+				t.Fatalf("unexpected shards deleted: got %v, exp %v", deletedShards, tt.expShards)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_PurgeDataUndrop(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	delay := time.Hour
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", DeletionDelay: &delay}); err != nil {
+		t.Fatal(err)
+	}
+	sgi, err := c.CreateShardGroup("db0", "rp0", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			DeleteShardFn: func(id uint64) error { return nil },
+		},
+	}
+	opt := query.ExecutionOptions{UserAdmin: true}
+
+	// Without the retention service the shards are deleted right away, so
+	// the shard group can't be restored despite the deletion delay.
+	if _, err := executeStatement(e, `PURGE DATA ON db0 BEFORE '2001-01-01T00:00:00Z'`, opt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeStatement(e, fmt.Sprintf(`UNDROP SHARD GROUP %d`, sgi.ID), opt); err != meta.ErrShardGroupShardsDeleted {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// queryPlan returns the lines of the QUERY PLAN rows in results.
+func queryPlan(results []*query.Result) []string {
+	var lines []string
+	for _, r := range results {
+		for _, row := range r.Series {
+			for _, v := range row.Values {
+				lines = append(lines, v[0].(string))
+			}
+		}
+	}
+	return lines
+}
+
+func indexOf(a []string, s string) int {
+	for i := range a {
+		if a[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStatementExecutor_ExecuteStatement_RunContinuousQueryLimits(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO db0.rp0.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END`, ""); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("analyst", "password", false); err != nil {
+		t.Fatal(err)
+	}
+	perMinute := int64(2)
+	if err := c.UpdateUserQueryLimits("analyst", &meta.UserQueryLimitsUpdate{MaxQueriesPerMinute: &perMinute}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &StatementExecutor{MetaClient: c, ShardMapper: &emptyShardMapper{}}
+	opt := query.ExecutionOptions{UserID: "analyst", UserAdmin: true}
+
+	// Each interval counts as a query, the third one is rate limited.
+	results, err := executeStatement(e, `RUN CONTINUOUS QUERY cq0 ON db0 BETWEEN '2000-01-01T00:00:00Z' AND '2000-01-01T03:00:00Z'`, opt)
+	if e, ok := err.(*query.RateLimitError); !ok || e.Limit != "max-queries-per-minute" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("unexpected results: %d", len(results))
+	}
+	for i, r := range results {
+		exp := []interface{}{time.Date(2000, 1, 1, i, 0, 0, 0, time.UTC), int64(0), int64(0)}
+		if len(r.Series) != 1 || !reflect.DeepEqual(r.Series[0].Values, [][]interface{}{exp}) {
+			t.Fatalf("unexpected result %d: %v", i, r.Series)
+		}
+	}
+
+	// A SELECT INTO is limited the same way.
+	if _, err := executeStatement(e, `SELECT mean(value) INTO db0.rp0.cpu_1h FROM db0.rp0.cpu WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-01T01:00:00Z' GROUP BY time(1h)`, opt); err == nil {
+		t.Fatal("expected error")
+	} else if _, ok := err.(*query.RateLimitError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowGrantsForUser(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for _, db := range []string{"db0", "db1"} {
+		if _, err := c.CreateDatabaseWithRetentionPolicy(db, &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, err := range []error{
+		func() error { _, err := c.CreateUser("alice", "password", false); return err }(),
+		func() error { _, err := c.CreateUser("root", "password", true); return err }(),
+		c.SetPrivilege("alice", "db1", cnosql.ReadPrivilege),
+		c.SetPrivilege("alice", "db0", cnosql.AllPrivileges),
+		c.SetPrivilegeOnRP("alice", "db1", "rp0", cnosql.WritePrivilege),
+		c.SetMeasurementPrivilege("alice", meta.MeasurementPrivilege{Database: "db1", Measurement: "cpu", Privilege: cnosql.WritePrivilege}),
+		c.CreateRole("readers"),
+		c.SetRolePrivilege("readers", "db1", cnosql.ReadPrivilege),
+		c.GrantRole("alice", "readers"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := &StatementExecutor{MetaClient: c}
+	opt := query.ExecutionOptions{UserAdmin: true}
+	for _, tt := range []struct {
+		stmt    string
+		columns []string
+		values  [][]interface{}
+	}{
+		{
+			// Only the database grants, sorted by database.
+			stmt:    `SHOW GRANTS FOR alice`,
+			columns: []string{"database", "privilege"},
+			values: [][]interface{}{
+				{"db0", "ALL PRIVILEGES"},
+				{"db1", "READ"},
+			},
+		},
+		{
+			stmt:    `SHOW GRANTS FOR root`,
+			columns: []string{"database", "privilege"},
+			values:  [][]interface{}{{nil, "ADMIN"}},
+		},
+		{
+			stmt:    `SHOW GRANTS FOR alice VERBOSE`,
+			columns: []string{"database", "retention_policy", "measurement", "privilege", "admin", "role"},
+			values: [][]interface{}{
+				{"db0", nil, nil, "ALL PRIVILEGES", false, nil},
+				{"db1", nil, nil, "READ", false, nil},
+				{"db1", "rp0", nil, "WRITE", false, nil},
+				{"db1", nil, "cpu", "WRITE", false, nil},
+				{"db1", nil, nil, "READ", false, "readers"},
+			},
+		},
+		{
+			stmt:    `SHOW GRANTS FOR root VERBOSE`,
+			columns: []string{"database", "retention_policy", "measurement", "privilege", "admin", "role"},
+			values:  [][]interface{}{{nil, nil, nil, nil, true, nil}},
+		},
+	} {
+		results, err := executeStatement(e, tt.stmt, opt)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		if len(results) != 1 || len(results[0].Series) != 1 {
+			t.Fatalf("%s: unexpected results: %v", tt.stmt, results)
+		}
+		row := results[0].Series[0]
+		if !reflect.DeepEqual(row.Columns, tt.columns) {
+			t.Errorf("%s: unexpected columns: %q", tt.stmt, row.Columns)
+		}
+		if !reflect.DeepEqual(row.Values, tt.values) {
+			t.Errorf("%s: unexpected values: %v", tt.stmt, row.Values)
+		}
+	}
+
+	if _, err := executeStatement(e, `SHOW GRANTS FOR bob`, opt); err == nil || err.Error() != "user not found: bob" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowRetentionPolicies(t *testing.T) {
+	day := 24 * time.Hour
+	di := &meta.DatabaseInfo{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "week",
+		RetentionPolicies: []meta.RetentionPolicyInfo{
+			{Name: "forever", ReplicaN: 1, ShardGroupDuration: 7 * day},
+			{
+				Name:               "week",
+				ReplicaN:           2,
+				Duration:           7 * day,
+				ShardGroupDuration: day,
+				ShardGroups: []meta.ShardGroupInfo{
+					{ID: 1, StartTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), DeletedAt: time.Date(2000, 1, 9, 0, 0, 0, 0, time.UTC)},
+					{ID: 3, StartTime: time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2000, 1, 4, 0, 0, 0, 0, time.UTC)},
+					{ID: 2, StartTime: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)},
+				},
+				FutureWriteLimit: time.Hour,
+				PastWriteLimit:   2 * day,
+				ColdAfter:        3 * day,
+				Downsample:       &meta.DownsampleInfo{Calls: []string{"mean(value)", "max(value)"}, Every: time.Hour, RetentionPolicy: "forever"},
+				Placement:        "ssd",
+				DeletionDelay:    time.Hour,
+				MaxQueryRange:    day,
+			},
+		},
+	}
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				if name != di.Name {
+					return nil
+				}
+				return di
+			},
+		},
+	}
+
+	columns := []string{"name", "duration", "groupDuration", "replicaN", "default", "lastWrite", "writePointsPerMin", "shardGroups", "oldestStartTime", "newestEndTime", "duration_ns", "group_duration_ns", "futureWriteLimit", "pastWriteLimit", "coldAfter", "downsample", "placement", "deletionDelay", "queryRange"}
+	for _, tt := range []struct {
+		name   string
+		stmt   string
+		values [][]interface{}
+		err    string
+	}{
+		{
+			name: "Policies",
+			stmt: `SHOW RETENTION POLICIES ON db0`,
+			values: [][]interface{}{
+				// An infinite duration is INF but zero nanoseconds, and the
+				// unset options are null.
+				{"forever", "INF", "168h0m0s", 1, false, nil, nil, 0, nil, nil, int64(0), int64(7 * day), nil, nil, nil, nil, nil, nil, nil},
+				// The deleted shard group is not counted.
+				{"week", "168h0m0s", "24h0m0s", 2, true, nil, nil, 2, "2000-01-02T00:00:00Z", "2000-01-04T00:00:00Z", int64(7 * day), int64(day),
+					"1h0m0s", "48h0m0s", "72h0m0s", "(mean(value), max(value)) EVERY 1h INTO forever", "ssd", "1h0m0s", "24h0m0s"},
+			},
+		},
+		{name: "DatabaseNotFound", stmt: `SHOW RETENTION POLICIES ON db1`, err: "database not found: db1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != 1 || len(results[0].Series) != 1 {
+				t.Fatalf("unexpected results: %v", results)
+			}
+			row := results[0].Series[0]
+			if !reflect.DeepEqual(row.Columns, columns) {
+				t.Fatalf("unexpected columns: %q", row.Columns)
+			} else if !reflect.DeepEqual(row.Values, tt.values) {
+				t.Fatalf("unexpected values:\ngot %v\nexp %v", row.Values, tt.values)
+			}
+
+			// Without write statistics, the write traffic is unknown.
+			if msgs := results[0].Messages; len(msgs) != 1 || msgs[0].Level != query.WarningLevel {
+				t.Fatalf("unexpected messages: %v", msgs)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_DropReport(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp1"}, false); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateShardGroup("db0", "rp1", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	var deleted []string
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			DeleteDatabaseFn: func(name string) error {
+				deleted = append(deleted, name)
+				return nil
+			},
+			DeleteRetentionPolicyFn: func(database, name string) error {
+				deleted = append(deleted, database+"."+name)
+				return nil
+			},
+			ShardsDiskSizeFn: func(ids []uint64) (int64, error) {
+				return int64(1024 * len(ids)), nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt     string
+		readOnly bool
+		strict   bool
+		messages []string
+		err      string
+		deleted  []string
+	}{
+		{stmt: `DROP RETENTION POLICY rp2 ON db0`, messages: []string{"retention policy rp2 on database db0 did not exist"}},
+		{stmt: `DROP RETENTION POLICY rp0 ON db1`, messages: []string{"database db1 did not exist"}},
+		{stmt: `DROP DATABASE db1`, messages: []string{"database db1 did not exist"}},
+		// A strict read-only context refuses the statement whether or not
+		// there is anything to drop.
+		{stmt: `DROP DATABASE db1`, readOnly: true, strict: true, err: "permission denied: 'DROP DATABASE db1' cannot be executed in a read only context, please use a POST request instead"},
+		{stmt: `DROP RETENTION POLICY rp1 ON db0`, readOnly: true, strict: true, err: "permission denied: 'DROP RETENTION POLICY rp1 ON db0' cannot be executed in a read only context, please use a POST request instead"},
+		{
+			stmt:     `DROP RETENTION POLICY rp1 ON db0`,
+			readOnly: true,
+			messages: []string{
+				"deprecated use of 'DROP RETENTION POLICY rp1 ON db0' in a read only context, please use a POST request instead",
+				"dropped retention policy rp1 on database db0 (1 shards, 1024 bytes reclaimed)",
+			},
+			deleted: []string{"db0.rp1"},
+		},
+		{stmt: `DROP DATABASE db0`, messages: []string{"dropped database db0 (0 shards, 0 bytes reclaimed)"}, deleted: []string{"db0"}},
+	} {
+		deleted = nil
+		opt := query.ExecutionOptions{UserAdmin: true, ReadOnly: tt.readOnly, StrictReadOnly: tt.strict}
+		results, err := executeStatement(e, tt.stmt, opt)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.stmt, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		} else {
+			var messages []string
+			if len(results) == 1 {
+				for _, m := range results[0].Messages {
+					messages = append(messages, m.Text)
+				}
+			}
+			if !reflect.DeepEqual(messages, tt.messages) {
+				t.Fatalf("%s: unexpected messages: %q", tt.stmt, messages)
+			}
+		}
+		if !reflect.DeepEqual(deleted, tt.deleted) {
+			t.Fatalf("%s: unexpected deletions: %q", tt.stmt, deleted)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowShards(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	duration := 30 * 24 * time.Hour
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", Duration: &duration, ShardGroupDuration: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	sgi, err := c.CreateShardGroup("db0", "rp0", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	si := sgi.Shards[0]
+
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			ShardDiskStatsFn: func(ids []uint64) (map[uint64]tsdb.ShardDiskStat, error) {
+				return map[uint64]tsdb.ShardDiskStat{si.ID: {
+					DiskBytes:    1024,
+					SeriesN:      3,
+					LastModified: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+					Tier:         "cold",
+				}}, nil
+			},
+			ShardStateFn: func(id uint64) string { return tsdb.ShardStateCold },
+		},
+	}
+
+	ownerIDs := make([]uint64, len(si.Owners))
+	for i, owner := range si.Owners {
+		ownerIDs[i] = owner.NodeID
+	}
+	columns := []string{"id", "database", "rp", "shard_group", "start_time", "end_time", "expiry_time", "owners", "disk_bytes", "series", "last_modified", "state", "tier"}
+	values := [][]interface{}{{
+		si.ID, "db0", "rp0", sgi.ID,
+		"2000-01-01T00:00:00Z", "2000-01-02T00:00:00Z", "2000-02-01T00:00:00Z",
+		joinUint64(ownerIDs),
+		int64(1024), int64(3), "2000-01-02T00:00:00Z", "cold", "cold",
+	}}
+
+	results, err := executeStatement(e, `SHOW SHARDS`, query.ExecutionOptions{UserAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(results) != 1 || len(results[0].Series) != 1 {
+		t.Fatalf("unexpected results: %v", results)
+	} else if len(results[0].Messages) != 0 {
+		t.Fatalf("unexpected messages: %v", results[0].Messages)
+	}
+	if row := results[0].Series[0]; row.Name != "db0" || !reflect.DeepEqual(row.Columns, columns) || !reflect.DeepEqual(row.Values, values) {
+		t.Fatalf("unexpected row: %v %v", row.Columns, row.Values)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowShardsState(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", ShardGroupDuration: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	var shardIDs []uint64
+	for _, ts := range []time.Time{time.Now().Add(-48 * time.Hour), time.Now()} {
+		sgi, err := c.CreateShardGroup("db0", "rp0", ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shardIDs = append(shardIDs, sgi.Shards[0].ID)
+	}
+
+	var state string
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			ShardDiskStatsFn: func(ids []uint64) (map[uint64]tsdb.ShardDiskStat, error) { return nil, nil },
+			ShardStateFn:     func(id uint64) string { return state },
+		},
+	}
+
+	// The shard of the current shard group is hot if it is open on this
+	// node.
+	for _, tt := range []struct {
+		state string
+		exp   []string
+	}{
+		{state: tsdb.ShardStateCold, exp: []string{tsdb.ShardStateCold, tsdb.ShardStateHot}},
+		{state: tsdb.ShardStateCompacting, exp: []string{tsdb.ShardStateCompacting, tsdb.ShardStateHot}},
+		{state: tsdb.ShardStateRemote, exp: []string{tsdb.ShardStateRemote, tsdb.ShardStateRemote}},
+	} {
+		state = tt.state
+		results, err := executeStatement(e, `SHOW SHARDS`, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		row := results[0].Series[0]
+		col := -1
+		for i, name := range row.Columns {
+			if name == "state" {
+				col = i
+			}
+		}
+		states := make([]string, len(row.Values))
+		for i, v := range row.Values {
+			if v[0] != shardIDs[i] {
+				t.Fatalf("unexpected shard: %v", v[0])
+			}
+			states[i] = v[col].(string)
+		}
+		if !reflect.DeepEqual(states, tt.exp) {
+			t.Fatalf("%s: unexpected states: %v", tt.state, states)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_DropSeriesTime(t *testing.T) {
+	var deleted []string
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name}
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			DeleteSeriesFn: func(database string, sources []cnosql.Source, condition cnosql.Expr) error {
+				deleted = append(deleted, condition.String())
+				return nil
+			},
+		},
+	}
+	stmt := `DROP SERIES FROM cpu WHERE time < '2000-01-01T00:00:00Z'`
+
+	// The deprecation notice of a time condition is sent once per session,
+	// and every time outside of a session. The statement is executed as the
+	// equivalent DELETE either way.
+	for _, tt := range []struct {
+		session uint64
+		notice  bool
+	}{
+		{session: 1, notice: true},
+		{session: 1},
+		{session: 2, notice: true},
+		{session: 0, notice: true},
+		{session: 0, notice: true},
+	} {
+		deleted = nil
+		results, err := executeStatement(e, stmt, query.ExecutionOptions{Database: "db0", UserAdmin: true, SessionID: tt.session})
+		if err != nil {
+			t.Fatal(err)
+		} else if exp := []string{`time < '2000-01-01T00:00:00Z'`}; !reflect.DeepEqual(deleted, exp) {
+			t.Fatalf("session %d: unexpected deletions: %q", tt.session, deleted)
+		}
+
+		var exp []*query.Message
+		if tt.notice {
+			exp = []*query.Message{{
+				Level:       query.WarningLevel,
+				Text:        "DROP SERIES doesn't support time in WHERE clause",
+				Code:        query.DeprecationCode,
+				Replacement: `DELETE FROM cpu WHERE time < '2000-01-01T00:00:00Z'`,
+			}}
+		}
+		if len(results) != 1 || !reflect.DeepEqual(results[0].Messages, exp) {
+			t.Fatalf("session %d: unexpected results: %+v", tt.session, results)
+		}
+	}
+
+	// An escalated notice fails the statement in every session, and nothing
+	// is deleted.
+	e.EscalatedNotices = []string{NoticeDropSeriesTime}
+	for _, session := range []uint64{1, 3} {
+		deleted = nil
+		_, err := executeStatement(e, stmt, query.ExecutionOptions{Database: "db0", UserAdmin: true, SessionID: session})
+		if _, ok := err.(ErrDeprecated); !ok {
+			t.Fatalf("session %d: unexpected error: %v", session, err)
+		} else if len(deleted) != 0 {
+			t.Fatalf("session %d: unexpected deletions: %q", session, deleted)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowShardGroups(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	duration, delay := 30*24*time.Hour, 1000*time.Hour
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", Duration: &duration, ShardGroupDuration: 24 * time.Hour, DeletionDelay: &delay}); err != nil {
+		t.Fatal(err)
+	}
+	// Daylight saving time starts in New York on 2000-04-02 at 07:00 UTC.
+	var ids []uint64
+	for day := 1; day <= 3; day++ {
+		sgi, err := c.CreateShardGroup("db0", "rp0", time.Date(2000, 4, day, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, sgi.ID)
+	}
+	if err := c.DeleteShardGroup("db0", "rp0", ids[2]); err != nil {
+		t.Fatal(err)
+	}
+	rpi, err := c.RetentionPolicy("db0", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var purgeAt time.Time
+	for _, sgi := range rpi.ShardGroups {
+		if sgi.ID == ids[2] {
+			purgeAt = sgi.DeletedAt.Add(delay)
+		}
+	}
+
+	e := &StatementExecutor{MetaClient: c}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []string{"id", "database", "rp", "start_time", "end_time", "expiry_time", "purge_time"}
+	for _, tt := range []struct {
+		name   string
+		stmt   string
+		values [][]interface{}
+	}{
+		{
+			name: "UTC",
+			stmt: `SHOW SHARD GROUPS ON db0`,
+			values: [][]interface{}{
+				{ids[0], "db0", "rp0", "2000-04-01T00:00:00Z", "2000-04-02T00:00:00Z", "2000-05-02T00:00:00Z", nil},
+				{ids[1], "db0", "rp0", "2000-04-02T00:00:00Z", "2000-04-03T00:00:00Z", "2000-05-03T00:00:00Z", nil},
+				// The deleted shard group is listed until it is purged.
+				{ids[2], "db0", "rp0", "2000-04-03T00:00:00Z", "2000-04-04T00:00:00Z", "2000-05-04T00:00:00Z", purgeAt.UTC().Format(time.RFC3339)},
+			},
+		},
+		{
+			// The time condition is in New York time too, so the first shard
+			// group ends before it. The offset changes within the second.
+			name: "TZ",
+			stmt: `SHOW SHARD GROUPS ON db0 WHERE time >= '2000-04-02 00:00:00' TZ('America/New_York')`,
+			values: [][]interface{}{
+				{ids[1], "db0", "rp0", "2000-04-01T19:00:00-05:00", "2000-04-02T20:00:00-04:00", "2000-05-02T20:00:00-04:00", nil},
+				{ids[2], "db0", "rp0", "2000-04-02T20:00:00-04:00", "2000-04-03T20:00:00-04:00", "2000-05-03T20:00:00-04:00", purgeAt.In(newYork).Format(time.RFC3339)},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+			if err != nil {
+				t.Fatal(err)
+			} else if len(results) != 1 || len(results[0].Series) != 1 {
+				t.Fatalf("unexpected results: %v", results)
+			}
+			row := results[0].Series[0]
+			if !reflect.DeepEqual(row.Columns, columns) {
+				t.Fatalf("unexpected columns: %q", row.Columns)
+			} else if !reflect.DeepEqual(row.Values, tt.values) {
+				t.Fatalf("unexpected values:\ngot %v\nexp %v", row.Values, tt.values)
+			}
+		})
+	}
+}
+
+// emptyShardMapper maps every source to a shard with the schema of
+// endlessShardGroup but without points.
+type emptyShardMapper struct{}
+
+func (*emptyShardMapper) MapShards(sources cnosql.Sources, t cnosql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
+	return &emptyShardGroup{}, nil
+}
+
+type emptyShardGroup struct {
+	endlessShardGroup
+}
+
+func (*emptyShardGroup) CreateIterator(ctx context.Context, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	return nil, nil
+}
+
+// endlessShardMapper maps every source to a shard whose float field "value"
+// has an endless series of points and whose iterators have the given cost.
+type endlessShardMapper struct {
+	cost query.IteratorCost
+}
+
+func (m *endlessShardMapper) MapShards(sources cnosql.Sources, t cnosql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
+	return &endlessShardGroup{cost: m.cost}, nil
+}
+
+type endlessShardGroup struct {
+	cost query.IteratorCost
+}
+
+func (*endlessShardGroup) CreateIterator(ctx context.Context, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	return &endlessFloatIterator{name: m.Name}, nil
+}
+
+func (sg *endlessShardGroup) IteratorCost(m *cnosql.Measurement, opt query.IteratorOptions) (query.IteratorCost, error) {
+	return sg.cost, nil
+}
+
+func (*endlessShardGroup) FieldDimensions(m *cnosql.Measurement) (map[string]cnosql.DataType, map[string]struct{}, error) {
+	return map[string]cnosql.DataType{"value": cnosql.Float}, map[string]struct{}{}, nil
+}
+
+func (*endlessShardGroup) MapType(m *cnosql.Measurement, field string) cnosql.DataType {
+	if field == "value" {
+		return cnosql.Float
+	}
+	return cnosql.Unknown
+}
+
+func (*endlessShardGroup) Close() error { return nil }
+
+type endlessFloatIterator struct {
+	name string
+	time int64
+}
+
+func (itr *endlessFloatIterator) Next() (*query.FloatPoint, error) {
+	itr.time++
+	return &query.FloatPoint{Name: itr.name, Time: itr.time, Value: 1}, nil
+}
+
+func (itr *endlessFloatIterator) Stats() query.IteratorStats {
+	return query.IteratorStats{SeriesN: 1, PointN: int(itr.time)}
+}
+
+func (*endlessFloatIterator) Close() error { return nil }
+
+// newIntoStatementExecutor returns a statement executor reading points from
+// the database db0 with the retention policy rp0 and writing the points of
+// SELECT INTO statements to the returned writer.
+func newIntoStatementExecutor(t *testing.T, points []query.FloatPoint) (*StatementExecutor, *intoPointsWriter) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	}
+	w := &intoPointsWriter{}
+	return &StatementExecutor{
+		MetaClient:   c,
+		ShardMapper:  &pointsShardMapper{points: points},
+		PointsWriter: w,
+	}, w
+}
+
+// intoPointsWriter records the points written by SELECT INTO statements.
+// Writes fail with err if it is set.
+type intoPointsWriter struct {
+	mu       sync.Mutex
+	requests []*IntoWriteRequest
+	err      error
+}
+
+func (w *intoPointsWriter) WritePointsInto(req *IntoWriteRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	// The buffered writer reuses the points slice.
+	w.requests = append(w.requests, &IntoWriteRequest{
+		Database:        req.Database,
+		RetentionPolicy: req.RetentionPolicy,
+		Points:          append([]models.Point(nil), req.Points...),
+	})
+	return nil
+}
+
+// reset forgets the written points.
+func (w *intoPointsWriter) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.requests = nil
+}
+
+// writeN returns the number of writes.
+func (w *intoPointsWriter) writeN() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.requests)
+}
+
+// points returns the written points in line protocol, sorted.
+func (w *intoPointsWriter) points() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var a []string
+	for _, req := range w.requests {
+		for _, p := range req.Points {
+			a = append(a, p.String())
+		}
+	}
+	sort.Strings(a)
+	return a
+}
+
+// pointsShardMapper maps every source to a shard with the given points of
+// the float field "value", which must be sorted by series, as a shard
+// returns them, and then by time. Every measurement
+// has the tag keys host and region.
+type pointsShardMapper struct {
+	points []query.FloatPoint
+}
+
+func (m *pointsShardMapper) MapShards(sources cnosql.Sources, t cnosql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
+	return &pointsShardGroup{points: m.points}, nil
+}
+
+type pointsShardGroup struct {
+	points []query.FloatPoint
+}
+
+func (sg *pointsShardGroup) CreateIterator(ctx context.Context, m *cnosql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	var points []query.FloatPoint
+	for _, p := range sg.points {
+		if (m.Regex != nil && m.Regex.Val.MatchString(p.Name)) || p.Name == m.Name {
+			points = append(points, p)
+		}
+	}
+	return &floatPointsIterator{points: points}, nil
+}
+
+func (*pointsShardGroup) IteratorCost(m *cnosql.Measurement, opt query.IteratorOptions) (query.IteratorCost, error) {
+	return query.IteratorCost{}, nil
+}
+
+func (*pointsShardGroup) FieldDimensions(m *cnosql.Measurement) (map[string]cnosql.DataType, map[string]struct{}, error) {
+	return map[string]cnosql.DataType{"value": cnosql.Float}, map[string]struct{}{"host": {}, "region": {}}, nil
+}
+
+func (*pointsShardGroup) MapType(m *cnosql.Measurement, field string) cnosql.DataType {
+	switch field {
+	case "value":
+		return cnosql.Float
+	case "host", "region":
+		return cnosql.Tag
+	}
+	return cnosql.Unknown
+}
+
+func (*pointsShardGroup) Close() error { return nil }
+
+type floatPointsIterator struct {
+	points []query.FloatPoint
+}
+
+func (itr *floatPointsIterator) Next() (*query.FloatPoint, error) {
+	if len(itr.points) == 0 {
+		return nil, nil
+	}
+	p := itr.points[0]
+	itr.points = itr.points[1:]
+	return &p, nil
+}
+
+func (*floatPointsIterator) Stats() query.IteratorStats { return query.IteratorStats{} }
+
+func (*floatPointsIterator) Close() error { return nil }
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoGroupByTags(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a", "region": "east"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a", "region": "east"}), Time: int64(3 * time.Minute), Value: 4},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a", "region": "west"}), Time: int64(time.Minute), Value: 2},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b", "region": "east"}), Time: int64(2 * time.Minute), Value: 3},
+	})
+
+	for _, tt := range []struct {
+		name       string
+		dimensions string
+		points     []string
+	}{
+		{
+			name:       "Wildcard",
+			dimensions: "*",
+			points:     []string{"cpu_max,host=a,region=east max=4 0", "cpu_max,host=a,region=west max=2 0", "cpu_max,host=b,region=east max=3 0"},
+		},
+		{
+			name:       "TagKeys",
+			dimensions: "host, region",
+			points:     []string{"cpu_max,host=a,region=east max=4 0", "cpu_max,host=a,region=west max=2 0", "cpu_max,host=b,region=east max=3 0"},
+		},
+		{
+			name:       "TagKey",
+			dimensions: "host",
+			points:     []string{"cpu_max,host=a max=4 0", "cpu_max,host=b max=3 0"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w.reset()
+			stmt := fmt.Sprintf(`SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h GROUP BY time(1h), %s`, tt.dimensions)
+			if _, err := executeStatement(e, stmt, query.ExecutionOptions{UserAdmin: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			// Each group is its own series in the target.
+			if points := w.points(); !reflect.DeepEqual(points, tt.points) {
+				t.Fatalf("unexpected points: %q", points)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoWriteBatchSize(t *testing.T) {
+	var points []query.FloatPoint
+	for i := 0; i < 5; i++ {
+		points = append(points, query.FloatPoint{Name: "cpu", Time: int64(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+	e, w := newIntoStatementExecutor(t, points)
+	stmt := `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 5m GROUP BY time(1m)`
+
+	for _, tt := range []struct {
+		name      string
+		batchSize int // of the executor
+		override  int // of the statement
+		writeN    int
+	}{
+		{name: "Default", writeN: 1},
+		{name: "One", batchSize: 1, writeN: 5},
+		{name: "Partial", batchSize: 2, writeN: 3},
+		{name: "Larger", batchSize: 100, writeN: 1},
+		{name: "OverrideOne", batchSize: 100, override: 1, writeN: 5},
+		{name: "OverrideLarger", batchSize: 1, override: 100, writeN: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w.reset()
+			e.IntoWriteBatchSize = tt.batchSize
+			results, err := executeStatement(e, stmt, query.ExecutionOptions{UserAdmin: true, IntoWriteBatchSize: tt.override})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := w.writeN(); n != tt.writeN {
+				t.Fatalf("unexpected number of writes: %d", n)
+			} else if n := len(w.points()); n != 5 {
+				t.Fatalf("unexpected number of points written: %d", n)
+			}
+			if len(results) != 1 || len(results[0].Series) != 1 || results[0].Series[0].Values[0][1] != int64(5) {
+				t.Fatalf("unexpected results: %v", results)
+			}
+		})
+	}
+
+	// A failed write fails the statement, whether it happens while the
+	// points are written or when the last points are flushed.
+	w.err = errors.New("write failed")
+	for _, batchSize := range []int{1, 100} {
+		e.IntoWriteBatchSize = batchSize
+		if _, err := executeStatement(e, stmt, query.ExecutionOptions{UserAdmin: true}); err != w.err {
+			t.Fatalf("batch size %d: unexpected error: %v", batchSize, err)
+		}
+	}
+}
+
+func TestBufferedPointsWriter_FlushIfDue(t *testing.T) {
+	t0 := time.Now()
+	points := []models.Point{
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, time.Unix(0, 0)),
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 2.0}, time.Unix(1, 0)),
+	}
+
+	w := &intoPointsWriter{}
+	bw := NewBufferedPointsWriterWithInterval(w, "db0", "rp0", 10, time.Minute)
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Points are only written once the interval has passed.
+	if err := bw.FlushIfDue(t0.Add(30 * time.Second)); err != nil {
+		t.Fatal(err)
+	} else if n := w.writeN(); n != 0 {
+		t.Fatalf("unexpected writes: %d", n)
+	}
+	if err := bw.FlushIfDue(t0.Add(2 * time.Minute)); err != nil {
+		t.Fatal(err)
+	} else if n := w.writeN(); n != 1 || bw.Len() != 0 {
+		t.Fatalf("unexpected writes: %d, %d points buffered", n, bw.Len())
+	}
+
+	// Nothing is written without buffered points.
+	if err := bw.FlushIfDue(t0.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if n := w.writeN(); n != 1 {
+		t.Fatalf("unexpected writes: %d", n)
+	}
+
+	// A failed write is returned.
+	w.err = errors.New("write failed")
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	} else if err := bw.FlushIfDue(t0.Add(2 * time.Hour)); err != w.err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without an interval only full buffers are written.
+	w = &intoPointsWriter{}
+	bw = NewBufferedPointsWriterWithInterval(w, "db0", "rp0", 10, 0)
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	} else if err := bw.FlushIfDue(t0.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if n := w.writeN(); n != 0 {
+		t.Fatalf("unexpected writes: %d", n)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoFlushInterval(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b"}), Time: 0, Value: 2},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "c"}), Time: 0, Value: 3},
+	})
+	stmt := `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 1m GROUP BY time(1m), host`
+
+	for _, tt := range []struct {
+		interval time.Duration
+		writeN   int
+	}{
+		{writeN: 1},
+		{interval: time.Hour, writeN: 1},
+		// The points of each row are written as soon as the interval has
+		// passed.
+		{interval: time.Nanosecond, writeN: 3},
+	} {
+		w.reset()
+		e.IntoFlushInterval = tt.interval
+		if _, err := executeStatement(e, stmt, query.ExecutionOptions{UserAdmin: true}); err != nil {
+			t.Fatal(err)
+		}
+		if n := w.writeN(); n != tt.writeN {
+			t.Fatalf("%s: unexpected writes: %d", tt.interval, n)
+		} else if points := w.points(); len(points) != 3 {
+			t.Fatalf("%s: unexpected points: %q", tt.interval, points)
+		}
+	}
+}
+
+func TestBufferedPointsWriter_Bytes(t *testing.T) {
+	points := []models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), models.Fields{"value": 1.0}, time.Unix(0, 0)),
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 2.0}, time.Unix(1, 0)),
+	}
+	size := int64(points[0].StringSize() + points[1].StringSize())
+
+	// Buffered points count until they are written.
+	w := &intoPointsWriter{}
+	bw := NewBufferedPointsWriter(w, "db0", "rp0", 10)
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	} else if n := bw.Bytes(); n != size {
+		t.Fatalf("unexpected bytes: %d, expected %d", n, size)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	} else if n := bw.Bytes(); n != 0 {
+		t.Fatalf("unexpected bytes: %d", n)
+	}
+
+	// The points of a failed write are still buffered.
+	w.err = errors.New("write failed")
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	} else if err := bw.Flush(); err != w.err {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n := bw.Bytes(); n != size {
+		t.Fatalf("unexpected bytes: %d, expected %d", n, size)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_MaxSelectMemory(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b"}), Time: 0, Value: 2},
+	})
+	stmt := `SELECT max(value) FROM db0.rp0.cpu WHERE time >= 0 AND time < 1m GROUP BY time(1m), host`
+	opt := query.ExecutionOptions{UserAdmin: true}
+
+	results, err := executeStatement(e, stmt, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := rowSize(results[0].Series[0])
+
+	// Each row must fit the budget while it is sent.
+	e.MaxSelectMemoryBytes = size
+	if _, err := executeStatement(e, stmt, opt); err != nil {
+		t.Fatal(err)
+	}
+	e.MaxSelectMemoryBytes = size - 1
+	if _, err := executeStatement(e, stmt, opt); err == nil {
+		t.Fatal("expected error")
+	} else if e, ok := err.(*query.MemoryBudgetExceededError); !ok || e.Bytes != size || e.Limit != size-1 {
+		t.Fatalf("unexpected error: %v", err)
+	} else if exp := fmt.Sprintf("memory budget exceeded: max-select-memory-bytes limit exceeded: (%d/%d)", size, size-1); err.Error() != exp {
+		t.Fatalf("unexpected message: %s", err)
+	}
+
+	// So must the points a SELECT INTO buffers.
+	stmt = `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 1m GROUP BY time(1m), host`
+	e.MaxSelectMemoryBytes = 0
+	if _, err := executeStatement(e, stmt, opt); err != nil {
+		t.Fatal(err)
+	}
+	points := w.points()
+	if len(points) != 2 {
+		t.Fatalf("unexpected points: %q", points)
+	}
+	w.reset()
+	e.MaxSelectMemoryBytes = int64(len(points[0]))
+	if _, err := executeStatement(e, stmt, opt); err == nil {
+		t.Fatal("expected error")
+	} else if _, ok := err.(*query.MemoryBudgetExceededError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e.MaxSelectMemoryBytes = int64(len(points[0]) + len(points[1]))
+	if _, err := executeStatement(e, stmt, opt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntoProgress_Due(t *testing.T) {
+	t0 := time.Now()
+
+	// Reported every 10 points.
+	p := &intoProgress{points: 10, last: t0}
+	for _, tt := range []struct {
+		n   int64
+		exp bool
+	}{
+		{n: 5}, {n: 10, exp: true}, {n: 10}, {n: 19}, {n: 25, exp: true},
+	} {
+		if got := p.due(tt.n, t0); got != tt.exp {
+			t.Fatalf("%d points: got %v, exp %v", tt.n, got, tt.exp)
+		}
+	}
+
+	// Reported every minute, if points were written since.
+	p = &intoProgress{interval: time.Minute, last: t0}
+	if p.due(5, t0.Add(30*time.Second)) {
+		t.Fatal("unexpected progress before the interval")
+	} else if !p.due(5, t0.Add(time.Minute)) {
+		t.Fatal("expected progress after the interval")
+	} else if p.due(5, t0.Add(3*time.Minute)) {
+		t.Fatal("unexpected progress without new points")
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoProgress(t *testing.T) {
+	e, _ := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b"}), Time: 0, Value: 2},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "c"}), Time: 0, Value: 3},
+	})
+	stmt := `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 1m GROUP BY time(1m), host`
+
+	for _, tt := range []struct {
+		name     string
+		opt      query.ExecutionOptions
+		progress []string
+	}{
+		{name: "none"},
+		{name: "points", opt: query.ExecutionOptions{IntoProgressPoints: 2}, progress: []string{"2 points written"}},
+		{name: "interval", opt: query.ExecutionOptions{IntoProgressInterval: time.Nanosecond}, progress: []string{"1 points written", "2 points written", "3 points written"}},
+	} {
+		tt.opt.UserAdmin = true
+		results, err := executeStatement(e, stmt, tt.opt)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		// Progress is sent as partial results with only a message, and the
+		// summary row is unchanged.
+		var progress []string
+		for _, r := range results[:len(results)-1] {
+			if !r.Partial || len(r.Series) != 0 || len(r.Messages) != 1 || r.Messages[0].Level != query.InfoLevel {
+				t.Fatalf("%s: unexpected progress: %+v", tt.name, r)
+			}
+			progress = append(progress, r.Messages[0].Text)
+		}
+		if !reflect.DeepEqual(progress, tt.progress) {
+			t.Fatalf("%s: unexpected progress: %q", tt.name, progress)
+		}
+		if r := results[len(results)-1]; r.Partial || !reflect.DeepEqual(r.Series[0].Values, [][]interface{}{{time.Unix(0, 0).UTC(), int64(3), int64(0)}}) {
+			t.Fatalf("%s: unexpected summary: %+v", tt.name, r.Series[0])
+		}
+	}
+}
+
+func TestIntoDrops_Message(t *testing.T) {
+	var d intoDrops
+	if msg := d.Message(); msg != nil {
+		t.Fatalf("unexpected message: %v", msg)
+	}
+
+	errNoFields := errors.New("no fields")
+	d.add("cpu", time.Unix(0, 0), errNoFields)
+	if msg := d.Message(); msg.Level != query.WarningLevel || msg.Text != "dropped 1 points that could not be written: cpu at 1970-01-01T00:00:00Z: no fields" {
+		t.Fatalf("unexpected message: %v", msg)
+	}
+
+	// Only the first reasons are kept.
+	for i := 1; i < maxIntoDropReasons+2; i++ {
+		d.add("cpu", time.Unix(int64(i), 0), errNoFields)
+	}
+	if d.n != maxIntoDropReasons+2 || len(d.reasons) != maxIntoDropReasons {
+		t.Fatalf("unexpected drops: %d, %q", d.n, d.reasons)
+	} else if text := d.Message().Text; !strings.HasPrefix(text, "dropped 7 points that could not be written: cpu at 1970-01-01T00:00:00Z: no fields; ") || !strings.HasSuffix(text, "cpu at 1970-01-01T00:00:04Z: no fields; ...") {
+		t.Fatalf("unexpected message: %s", text)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoDropped(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b"}), Time: 0, Value: math.NaN()},
+	})
+
+	results, err := executeStatement(e, `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 1m GROUP BY time(1m), host`, query.ExecutionOptions{UserAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points := w.points(); !reflect.DeepEqual(points, []string{"cpu_max,host=a max=1 0"}) {
+		t.Fatalf("unexpected points: %q", points)
+	}
+
+	// The value without a representation is dropped and reported.
+	r := results[0]
+	if exp := [][]interface{}{{time.Unix(0, 0).UTC(), int64(1), int64(1)}}; !reflect.DeepEqual(r.Series[0].Values, exp) {
+		t.Fatalf("unexpected summary: %v", r.Series[0].Values)
+	} else if len(r.Messages) != 1 || !strings.HasPrefix(r.Messages[0].Text, "dropped 1 points that could not be written: cpu_max at 1970-01-01T00:00:00Z: ") {
+		t.Fatalf("unexpected messages: %v", r.Messages)
+	}
+
+	for _, stat := range e.Statistics(nil) {
+		if stat.Name == "select_into" {
+			if stat.Values[statIntoPointsWritten] != int64(1) || stat.Values[statIntoPointsDropped] != int64(1) {
+				t.Fatalf("unexpected statistics: %v", stat.Values)
+			}
+			return
+		}
+	}
+	t.Fatal("no select_into statistics")
+}
+
+// concurrentIntoWriter records the most writes it has seen in progress at
+// once. Writes block until release is closed and fail with err if it is set.
+type concurrentIntoWriter struct {
+	intoPointsWriter
+	release chan struct{}
+
+	mu      sync.Mutex
+	active  int
+	maxSeen int
+}
+
+func (w *concurrentIntoWriter) WritePointsInto(req *IntoWriteRequest) error {
+	w.mu.Lock()
+	w.active++
+	if w.active > w.maxSeen {
+		w.maxSeen = w.active
+	}
+	w.mu.Unlock()
+
+	<-w.release
+	err := w.intoPointsWriter.WritePointsInto(req)
+
+	w.mu.Lock()
+	w.active--
+	w.mu.Unlock()
+	return err
+}
+
+func (w *concurrentIntoWriter) inProgress() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.active
+}
+
+func TestBufferedPointsWriter_Workers(t *testing.T) {
+	var points []models.Point
+	for i := 0; i < 10; i++ {
+		points = append(points, models.MustNewPoint("cpu", nil, models.Fields{"value": float64(i)}, time.Unix(int64(i), 0)))
+	}
+
+	w := &concurrentIntoWriter{release: make(chan struct{})}
+	bw := NewBufferedPointsWriter(w, "db0", "rp0", 2)
+	bw.SetWorkers(3)
+
+	// Full batches are written in the background, at most 3 at once, while
+	// the caller keeps buffering.
+	done := make(chan error, 1)
+	go func() {
+		done <- bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points[:7]})
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.inProgress() != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected writes in progress: %d", w.inProgress())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(w.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	} else if bw.Len() != 1 {
+		t.Fatalf("unexpected points buffered: %d", bw.Len())
+	}
+
+	// Flush writes the rest and waits for every batch.
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points[7:]}); err != nil {
+		t.Fatal(err)
+	} else if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(w.points()); n != 10 {
+		t.Fatalf("unexpected points written: %d", n)
+	} else if w.maxSeen != 3 {
+		t.Fatalf("unexpected concurrent writes: %d", w.maxSeen)
+	}
+
+	// The first failed batch stops the writer.
+	w.err = errors.New("write failed")
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points[:2]}); err != nil {
+		t.Fatal(err)
+	}
+	bw.Wait()
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points[:2]}); err != w.err {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := bw.Flush(); err != w.err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoWriteConcurrency(t *testing.T) {
+	var points []query.FloatPoint
+	for i := 0; i < 10; i++ {
+		points = append(points, query.FloatPoint{Name: "cpu", Time: int64(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+	e, w := newIntoStatementExecutor(t, points)
+	e.IntoWriteBatchSize = 3
+	e.IntoWriteConcurrency = 4
+
+	results, err := executeStatement(e, `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 10m GROUP BY time(1m)`, query.ExecutionOptions{UserAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every batch is written before the statement completes.
+	if n := w.writeN(); n != 4 {
+		t.Fatalf("unexpected writes: %d", n)
+	} else if n := len(w.points()); n != 10 {
+		t.Fatalf("unexpected points: %d", n)
+	} else if v := results[0].Series[0].Values[0][1]; v != int64(10) {
+		t.Fatalf("unexpected written: %v", v)
+	}
+
+	// A failed batch fails the statement.
+	w.reset()
+	w.err = errors.New("write failed")
+	if _, err := executeStatement(e, `SELECT max(value) INTO db0.rp0.cpu_max FROM db0.rp0.cpu WHERE time >= 0 AND time < 10m GROUP BY time(1m)`, query.ExecutionOptions{UserAdmin: true}); err != w.err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_IntoWriteRateLimit(t *testing.T) {
+	for _, tt := range []struct {
+		limit, override, exp int
+	}{
+		{},
+		{limit: 100, exp: 100},
+		{override: 50, exp: 50},
+		{limit: 100, override: 50, exp: 50},
+		// The query can't raise the limit of the server.
+		{limit: 100, override: 200, exp: 100},
+	} {
+		e := &StatementExecutor{IntoWriteRateLimit: tt.limit}
+		if got := e.intoWriteRateLimit(tt.override); got != tt.exp {
+			t.Fatalf("limit %d, override %d: got %d, exp %d", tt.limit, tt.override, got, tt.exp)
+		}
+	}
+}
+
+func TestBufferedPointsWriter_RateLimit(t *testing.T) {
+	var points []models.Point
+	for i := 0; i < 20; i++ {
+		points = append(points, models.MustNewPoint("cpu", nil, models.Fields{"value": float64(i)}, time.Unix(int64(i), 0)))
+	}
+
+	// Two batches of 10 points at 100 points per second take 200ms.
+	w := &intoPointsWriter{}
+	bw := NewBufferedPointsWriter(w, "db0", "rp0", 10)
+	bw.SetRateLimit(context.Background(), 100)
+	start := time.Now()
+	if err := bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points}); err != nil {
+		t.Fatal(err)
+	} else if d := time.Since(start); d < 150*time.Millisecond {
+		t.Fatalf("writes not paced: %s", d)
+	} else if n := w.writeN(); n != 2 {
+		t.Fatalf("unexpected writes: %d", n)
+	}
+
+	// Waiting for the limit ends with the context, and is counted while it
+	// lasts.
+	var throttled int64
+	ctx, cancel := context.WithCancel(context.Background())
+	bw = NewBufferedPointsWriter(w, "db0", "rp0", 10)
+	bw.SetRateLimit(ctx, 1)
+	bw.throttledN = &throttled
+	done := make(chan error, 1)
+	go func() {
+		done <- bw.WritePointsInto(&IntoWriteRequest{Database: "db0", RetentionPolicy: "rp0", Points: points[:10]})
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt64(&throttled) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("writer not throttled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n := atomic.LoadInt64(&throttled); n != 0 {
+		t.Fatalf("unexpected throttled writers: %d", n)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoMeasurementBackReference(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 1},
+		{Name: "cpu", Tags: query.NewTags(map[string]string{"host": "b"}), Time: 0, Value: 2},
+		{Name: "disk", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 3},
+		{Name: "mem", Tags: query.NewTags(map[string]string{"host": "a"}), Time: 0, Value: 4},
+		{Name: "mem", Tags: query.NewTags(map[string]string{"host": "b"}), Time: int64(time.Minute), Value: 5},
+	})
+
+	for _, tt := range []struct {
+		name    string
+		sources string
+		points  []string
+	}{
+		{
+			name:    "Regex",
+			sources: "db0.rp0./.*/",
+			points:  []string{"cpu,host=a max=1 0", "cpu,host=b max=2 0", "disk,host=a max=3 0", "mem,host=a max=4 0", "mem,host=b max=5 0"},
+		},
+		{
+			name:    "Measurements",
+			sources: "db0.rp0.mem, db0.rp0.cpu",
+			points:  []string{"cpu,host=a max=1 0", "cpu,host=b max=2 0", "mem,host=a max=4 0", "mem,host=b max=5 0"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w.reset()
+			// The emitter returns a row per point and the rows of every
+			// measurement are written in one batch, so each row must keep
+			// its own measurement.
+			stmt := fmt.Sprintf(`SELECT max(value) INTO db0.rp0.:MEASUREMENT FROM %s WHERE time >= 0 AND time < 1h GROUP BY time(1h), *`, tt.sources)
+			if _, err := executeStatement(e, stmt, query.ExecutionOptions{UserAdmin: true, ChunkSize: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if n := w.writeN(); n != 1 {
+				t.Fatalf("unexpected number of writes: %d", n)
+			} else if points := w.points(); !reflect.DeepEqual(points, tt.points) {
+				t.Fatalf("unexpected points: %q", points)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_SelectIntoAuthorizeTarget(t *testing.T) {
+	e, w := newIntoStatementExecutor(t, []query.FloatPoint{{Name: "cpu", Value: 1}})
+	if _, err := e.MetaClient.CreateDatabaseWithRetentionPolicy("db1", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := &meta.UserInfo{Name: "admin", Admin: true}
+	reader := &meta.UserInfo{Name: "reader", Privileges: map[string]cnosql.Privilege{"db0": cnosql.ReadPrivilege, "db1": cnosql.ReadPrivilege}}
+	writer := &meta.UserInfo{Name: "writer", Privileges: map[string]cnosql.Privilege{"db0": cnosql.ReadPrivilege, "db1": cnosql.WritePrivilege}}
+	for _, tt := range []struct {
+		name string
+		user *meta.UserInfo
+		stmt string
+		err  string
+	}{
+		{name: "Admin", user: admin, stmt: `SELECT max(value) INTO db1.rp0.cpu FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h`},
+		{name: "Writer", user: writer, stmt: `SELECT max(value) INTO db1.rp0.cpu FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h`},
+		// Continuous queries run without a coarse authorizer.
+		{name: "Unrestricted", stmt: `SELECT max(value) INTO db1.rp0.cpu FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h`},
+		{
+			name: "Reader",
+			user: reader,
+			stmt: `SELECT max(value) INTO db1.rp0.cpu FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h`,
+			err:  "reader not authorized to execute INTO db1.rp0.cpu, requires WRITE on target database db1",
+		},
+		{
+			name: "WriterCreate",
+			user: writer,
+			stmt: `SELECT max(value) INTO db1.rp1.cpu WITH CREATE FROM db0.rp0.cpu WHERE time >= 0 AND time < 1h`,
+			err:  "writer not authorized to execute INTO db1.rp1.cpu WITH CREATE, requires ALL PRIVILEGES on target database db1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w.reset()
+			opt := query.ExecutionOptions{UserAdmin: true}
+			if tt.user != nil {
+				opt.UserID, opt.CoarseAuthorizer = tt.user.Name, tt.user
+			}
+			_, err := executeStatement(e, tt.stmt, opt)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				} else if points := w.points(); len(points) != 1 {
+					t.Fatalf("unexpected points: %q", points)
+				}
+				return
+			}
+
+			// Nothing is read or written.
+			if _, ok := err.(meta.ErrAuthorize); !ok || err.Error() != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			} else if points := w.points(); len(points) != 0 {
+				t.Fatalf("unexpected points: %q", points)
+			}
+		})
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_CreateContinuousQueryAuthorizeTarget(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for _, db := range []string{"db0", "db1"} {
+		if _, err := c.CreateDatabaseWithRetentionPolicy(db, &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e := &StatementExecutor{MetaClient: c}
+
+	reader := &meta.UserInfo{Name: "reader", Privileges: map[string]cnosql.Privilege{"db0": cnosql.ReadPrivilege, "db1": cnosql.ReadPrivilege}}
+	writer := &meta.UserInfo{Name: "writer", Privileges: map[string]cnosql.Privilege{"db0": cnosql.ReadPrivilege, "db1": cnosql.WritePrivilege}}
+	for _, tt := range []struct {
+		name string
+		user *meta.UserInfo
+		stmt string
+		err  string
+	}{
+		{name: "writer", user: writer, stmt: `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT max(value) INTO db1.rp0.cpu_max FROM db0.rp0.cpu GROUP BY time(1h) END`},
+		// Without authentication the query has no owner.
+		{name: "unrestricted", stmt: `CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT max(value) INTO db1.rp0.cpu_max FROM db0.rp0.cpu GROUP BY time(1h) END`},
+		{
+			name: "reader",
+			user: reader,
+			stmt: `CREATE CONTINUOUS QUERY cq2 ON db0 BEGIN SELECT max(value) INTO db1.rp0.cpu_max FROM db0.rp0.cpu GROUP BY time(1h) END`,
+			err:  "reader not authorized to execute INTO db1.rp0.cpu_max, requires WRITE on target database db1",
+		},
+		// Targets without a database write into the query's database.
+		{
+			name: "default database",
+			user: writer,
+			stmt: `CREATE CONTINUOUS QUERY cq3 ON db0 BEGIN SELECT max(value) INTO cpu_max FROM cpu GROUP BY time(1h) END`,
+			err:  "writer not authorized to execute INTO db0..cpu_max, requires WRITE on target database db0",
+		},
+		{
+			name: "create",
+			user: writer,
+			stmt: `CREATE CONTINUOUS QUERY cq4 ON db0 BEGIN SELECT max(value) INTO db1.rp1.cpu_max WITH CREATE FROM db0.rp0.cpu GROUP BY time(1h) END`,
+			err:  "writer not authorized to execute INTO db1.rp1.cpu_max WITH CREATE, requires ALL PRIVILEGES on target database db1",
+		},
+	} {
+		opt := query.ExecutionOptions{UserAdmin: true}
+		if tt.user != nil {
+			opt.UserID, opt.CoarseAuthorizer = tt.user.Name, tt.user
+		}
+		_, err := executeStatement(e, tt.stmt, opt)
+		if tt.err == "" && err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		} else if tt.err != "" {
+			if _, ok := err.(meta.ErrAuthorize); !ok || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+		}
+	}
+
+	// Only the authorized queries are created, with the user creating them
+	// as the owner.
+	results, err := executeStatement(e, `SHOW CONTINUOUS QUERIES`, query.ExecutionOptions{UserAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var owners [][]interface{}
+	for _, row := range results[0].Series {
+		if row.Columns[2] != "owner" {
+			t.Fatalf("unexpected columns: %q", row.Columns)
+		}
+		for _, v := range row.Values {
+			owners = append(owners, []interface{}{v[0], v[2]})
+		}
+	}
+	if exp := [][]interface{}{{"cq0", "writer"}, {"cq1", nil}}; !reflect.DeepEqual(owners, exp) {
+		t.Fatalf("unexpected owners: %v", owners)
+	}
+}
+
+func TestConvertRowToPoints_TagColumns(t *testing.T) {
+	row := &models.Row{
+		Name:    "cpu",
+		Tags:    map[string]string{"region": "east"},
+		Columns: []string{"time", "host", "value"},
+		Values: [][]interface{}{
+			{time.Unix(0, 0), "a", 1.0},
+			{time.Unix(0, 1), "b", 2.0},
+			// A null tag column leaves the tag out.
+			{time.Unix(0, 2), nil, 3.0},
+		},
+	}
+
+	var drops intoDrops
+	points, err := convertRowToPoints("cpu_copy", row, []string{"host", "region"}, &drops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, p := range points {
+		lines = append(lines, p.String())
+	}
+	if exp := []string{"cpu_copy,host=a,region=east value=1 0", "cpu_copy,host=b,region=east value=2 1", "cpu_copy,region=east value=3 2"}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("unexpected points: %q", lines)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_QueryRangeIndexExempt(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	queryRange := 30 * 24 * time.Hour
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0", MaxQueryRange: &queryRange}); err != nil {
+		t.Fatal(err)
+	}
+	e := &StatementExecutor{
+		MetaClient:  c,
+		ShardMapper: &emptyShardMapper{},
+		TSDBStore: &testTSDBStore{
+			ForEachSeriesKeyFn: func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error {
+				return fn("cpu,host=a")
+			},
+			TagKeysFn: func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error) {
+				return []tsdb.TagKeys{{Measurement: "cpu", Keys: []string{"host"}}}, nil
+			},
+		},
+	}
+
+	// The statements answered from the series index read no points and are
+	// not limited.
+	for _, stmt := range []string{`SHOW SERIES ON db0`, `SHOW TAG KEYS ON db0`} {
+		results, err := executeStatement(e, stmt, query.ExecutionOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		} else if len(results) != 1 || len(results[0].Series) != 1 {
+			t.Fatalf("%s: unexpected results: %v", stmt, results)
+		}
+	}
+
+	// A SELECT is.
+	if _, err := executeStatement(e, `SELECT value FROM db0.rp0.cpu`, query.ExecutionOptions{}); err == nil || err.Error() != `query against retention policy "db0"."rp0" has no lower time bound: the maximum query range is 30d` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowSeriesSources(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp1"}, false); err != nil {
+		t.Fatal(err)
+	}
+	shardIDs := make(map[string]uint64)
+	for _, rp := range []string{"rp0", "rp1"} {
+		sgi, err := c.CreateShardGroup("db0", rp, time.Unix(0, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shardIDs[rp] = sgi.Shards[0].ID
+	}
+
+	// The store returns the page of the series of the sources the authorizer
+	// may read.
+	var read []uint64
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			ForEachSeriesKeyFn: func(auth query.FineAuthorizer, ids []uint64, cond cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error {
+				read = ids
+				var skipped, n int
+				for _, key := range []string{"cpu,host=a", "cpu_total,host=a", "mem,host=a"} {
+					name, tags := models.ParseKeyBytes([]byte(key))
+					if cond != nil && !cnosql.EvalBool(cond, map[string]interface{}{"_name": string(name)}) {
+						continue
+					}
+					if !query.AuthorizerIsOpen(auth) && !auth.AuthorizeSeriesRead("db0", name, tags) {
+						continue
+					}
+					if skipped < opt.Offset {
+						skipped++
+						continue
+					}
+					if err := fn(key); err != nil {
+						return err
+					}
+					if n++; n == opt.Limit {
+						return nil
+					}
+				}
+				return nil
+			},
+		},
+	}
+
+	// The user may only read rp1.
+	user := &meta.UserInfo{
+		Name: "reader",
+		RetentionPolicyPrivileges: []meta.RetentionPolicyPrivilege{
+			{Database: "db0", RetentionPolicy: "rp1", Privilege: cnosql.ReadPrivilege},
+		},
+	}
+	for _, tt := range []struct {
+		stmt string
+		user *meta.UserInfo
+		read []uint64
+		keys [][]interface{}
+	}{
+		{stmt: `SHOW SERIES ON db0`, read: []uint64{shardIDs["rp0"], shardIDs["rp1"]}, keys: [][]interface{}{{"cpu,host=a"}, {"cpu_total,host=a"}, {"mem,host=a"}}},
+		// Sources without a retention policy read the default one.
+		{stmt: `SHOW SERIES ON db0 FROM cpu, mem`, read: []uint64{shardIDs["rp0"]}, keys: [][]interface{}{{"cpu,host=a"}, {"mem,host=a"}}},
+		{stmt: `SHOW SERIES ON db0 FROM db0.rp1./^cpu/`, read: []uint64{shardIDs["rp1"]}, keys: [][]interface{}{{"cpu,host=a"}, {"cpu_total,host=a"}}},
+		{stmt: `SHOW SERIES ON db0 FROM db0.rp1.cpu`, user: user, read: []uint64{shardIDs["rp1"]}, keys: [][]interface{}{{"cpu,host=a"}}},
+		{stmt: `SHOW SERIES ON db0 LIMIT 1 OFFSET 1`, read: []uint64{shardIDs["rp0"], shardIDs["rp1"]}, keys: [][]interface{}{{"cpu_total,host=a"}}},
+		{stmt: `SHOW SERIES ON db0 FROM /^cpu/ OFFSET 1`, read: []uint64{shardIDs["rp0"]}, keys: [][]interface{}{{"cpu_total,host=a"}}},
+		// The series of rp0 are not read with the privileges on rp1.
+		{stmt: `SHOW SERIES ON db0 FROM db0.rp0.cpu`, user: user},
+	} {
+		s, err := cnosql.ParseStatement(tt.stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Rewrite and normalize the statement as the query executor does.
+		if s, err = query.RewriteStatement(s); err != nil {
+			t.Fatal(err)
+		} else if err := e.NormalizeStatement(s, "db0", ""); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := &query.ExecutionContext{
+			Context:          context.Background(),
+			Results:          make(chan *query.Result, 100),
+			ExecutionOptions: query.ExecutionOptions{UserAdmin: true},
+		}
+		if tt.user != nil {
+			ctx.UserAdmin, ctx.Authorizer = false, tt.user
+		}
+		read = nil
+		if err := e.ExecuteStatement(ctx, s); err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		close(ctx.Results)
+
+		var keys [][]interface{}
+		for r := range ctx.Results {
+			for _, row := range r.Series {
+				keys = append(keys, row.Values...)
+			}
+		}
+		if !reflect.DeepEqual(read, tt.read) {
+			t.Fatalf("%s: unexpected shards read: %v", tt.stmt, read)
+		} else if !reflect.DeepEqual(keys, tt.keys) {
+			t.Fatalf("%s: unexpected keys: %v", tt.stmt, keys)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowFieldKeys(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateShardGroup("db0", "rp0", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shards disagree on the type of cpu's value field.
+	var cond cnosql.Expr
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			FieldKeysFn: func(auth query.FineAuthorizer, shardIDs []uint64, c cnosql.Expr) ([]tsdb.FieldKeys, error) {
+				cond = c
+				var keys []tsdb.FieldKeys
+				for _, m := range []tsdb.FieldKeys{
+					{Measurement: "cpu", Keys: []tsdb.FieldKey{
+						{Key: "host", Types: []cnosql.DataType{cnosql.String}},
+						{Key: "value", Types: []cnosql.DataType{cnosql.Float, cnosql.Integer}},
+					}},
+					{Measurement: "mem", Keys: []tsdb.FieldKey{
+						{Key: "free", Types: []cnosql.DataType{cnosql.Integer}},
+					}},
+				} {
+					if c == nil || cnosql.EvalBool(c, map[string]interface{}{"_name": m.Measurement}) {
+						keys = append(keys, m)
+					}
+				}
+				return keys, nil
+			},
+		},
+	}
+
+	type row struct {
+		name   string
+		values [][]interface{}
+	}
+	for _, tt := range []struct {
+		stmt string
+		cond string
+		rows []row
+	}{
+		{
+			stmt: `SHOW FIELD KEYS ON db0`,
+			rows: []row{
+				{name: "cpu", values: [][]interface{}{{"host", "string"}, {"value", "float"}, {"value", "integer"}}},
+				{name: "mem", values: [][]interface{}{{"free", "integer"}}},
+			},
+		},
+		{
+			stmt: `SHOW FIELD KEYS FROM db0.rp0.cpu`,
+			cond: `_name = 'cpu'`,
+			rows: []row{{name: "cpu", values: [][]interface{}{{"host", "string"}, {"value", "float"}, {"value", "integer"}}}},
+		},
+		// OFFSET and LIMIT apply to the rows of each measurement, and
+		// measurements left without rows are omitted.
+		{
+			stmt: `SHOW FIELD KEYS ON db0 LIMIT 1 OFFSET 1`,
+			rows: []row{{name: "cpu", values: [][]interface{}{{"value", "float"}}}},
+		},
+		{
+			stmt: `SHOW FIELD KEYS ON db0 LIMIT 2`,
+			rows: []row{
+				{name: "cpu", values: [][]interface{}{{"host", "string"}, {"value", "float"}}},
+				{name: "mem", values: [][]interface{}{{"free", "integer"}}},
+			},
+		},
+		{stmt: `SHOW FIELD KEYS ON db0 OFFSET 3`},
+	} {
+		cond = nil
+		results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+
+		var rows []row
+		for _, r := range results {
+			for _, s := range r.Series {
+				if !reflect.DeepEqual(s.Columns, []string{"fieldKey", "fieldType"}) {
+					t.Fatalf("%s: unexpected columns: %v", tt.stmt, s.Columns)
+				}
+				rows = append(rows, row{name: s.Name, values: s.Values})
+			}
+		}
+		if !reflect.DeepEqual(rows, tt.rows) {
+			t.Fatalf("%s: unexpected rows: %v", tt.stmt, rows)
+		}
+		var got string
+		if cond != nil {
+			got = cond.String()
+		}
+		if got != tt.cond {
+			t.Fatalf("%s: unexpected condition: %s", tt.stmt, got)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowCardinalityAuthorized(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The store counts the series the authorizer may read.
+	keys := []string{"cpu,host=a", "cpu,host=b", "mem,host=a"}
+	seriesN := func(auth query.FineAuthorizer, measurement string) int64 {
+		var n int64
+		for _, key := range keys {
+			name, tags := models.ParseKeyBytes([]byte(key))
+			if measurement != "" && string(name) != measurement {
+				continue
+			} else if !query.AuthorizerIsOpen(auth) && !auth.AuthorizeSeriesRead("db0", name, tags) {
+				continue
+			}
+			n++
+		}
+		return n
+	}
+	names := func(auth query.FineAuthorizer) [][]byte {
+		var a [][]byte
+		for _, name := range []string{"cpu", "mem"} {
+			if seriesN(auth, name) > 0 {
+				a = append(a, []byte(name))
+			}
+		}
+		return a
+	}
+	e := &StatementExecutor{
+		MetaClient: c,
+		TSDBStore: &testTSDBStore{
+			MeasurementNamesFn: func(auth query.FineAuthorizer, database, rp string, cond cnosql.Expr) ([][]byte, error) {
+				return names(auth), nil
+			},
+			MeasurementsCardinalityFn: func(auth query.FineAuthorizer, database string) (int64, error) {
+				return int64(len(names(auth))), nil
+			},
+			MeasurementSeriesCardinalityFn: func(ctx context.Context, auth query.FineAuthorizer, database string, a [][]byte, concurrency int) ([]int64, error) {
+				counts := make([]int64, len(a))
+				for i, name := range a {
+					counts[i] = seriesN(auth, string(name))
+				}
+				return counts, nil
+			},
+			SeriesCardinalityFn: func(auth query.FineAuthorizer, database string) (int64, error) {
+				return seriesN(auth, ""), nil
+			},
+			SeriesCardinalityByMeasurementFn: func(auth query.FineAuthorizer, database string) ([]tsdb.MeasurementSeriesN, error) {
+				var counts []tsdb.MeasurementSeriesN
+				for _, name := range names(auth) {
+					counts = append(counts, tsdb.MeasurementSeriesN{Measurement: string(name), N: seriesN(auth, string(name))})
+				}
+				return counts, nil
+			},
+		},
+	}
+
+	// The user only holds a grant on cpu.
+	user := &meta.UserInfo{
+		Name: "reader",
+		MeasurementPrivileges: []meta.MeasurementPrivilege{
+			{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+		},
+	}
+	for _, tt := range []struct {
+		stmt   string
+		admin  [][]interface{}
+		reader [][]interface{}
+	}{
+		{stmt: `SHOW SERIES CARDINALITY ON db0`, admin: [][]interface{}{{int64(3)}}, reader: [][]interface{}{{int64(2)}}},
+		{stmt: `SHOW SERIES EXACT CARDINALITY ON db0`, admin: [][]interface{}{{"cpu", int64(2)}, {"mem", int64(1)}}, reader: [][]interface{}{{"cpu", int64(2)}}},
+		{stmt: `SHOW MEASUREMENT CARDINALITY ON db0`, admin: [][]interface{}{{int64(2)}}, reader: [][]interface{}{{int64(1)}}},
+		{stmt: `SHOW MEASUREMENTS ON db0 WITH COUNTS`, admin: [][]interface{}{{"cpu", int64(2), nil}, {"mem", int64(1), nil}}, reader: [][]interface{}{{"cpu", int64(2), nil}}},
+	} {
+		for _, auth := range []query.FineAuthorizer{query.OpenAuthorizer, user} {
+			results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{Authorizer: auth})
+			if err != nil {
+				t.Fatalf("%s: %v", tt.stmt, err)
+			}
+
+			var values [][]interface{}
+			for _, r := range results {
+				if r.Err != nil {
+					t.Fatalf("%s: %v", tt.stmt, r.Err)
+				}
+				for _, row := range r.Series {
+					values = append(values, row.Values...)
+				}
+			}
+			exp := tt.admin
+			if auth == user {
+				exp = tt.reader
+			}
+			if !reflect.DeepEqual(values, exp) {
+				t.Fatalf("%s: unexpected values: got %v, exp %v", tt.stmt, values, exp)
+			}
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowTagsOverlappingShardGroups(t *testing.T) {
+	di := &meta.DatabaseInfo{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{
+			{Name: "rp0"},
+			{Name: "rp1"},
+		},
+	}
+	// The shard groups overlap within rp0 and across the retention
+	// policies, sharing shards 2 and 3.
+	groups := map[string][]meta.ShardGroupInfo{
+		"rp0": {
+			{ID: 1, Shards: []meta.ShardInfo{{ID: 1}, {ID: 2}}},
+			{ID: 2, Shards: []meta.ShardInfo{{ID: 2}, {ID: 3}}},
+		},
+		"rp1": {
+			{ID: 3, Shards: []meta.ShardInfo{{ID: 3}, {ID: 4}}},
+		},
+	}
+
+	var shardIDs [][]uint64
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo { return di },
+			ShardGroupsByTimeRangeFn: func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+				return groups[policy], nil
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			TagKeysFn: func(auth query.FineAuthorizer, ids []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error) {
+				shardIDs = append(shardIDs, ids)
+				return []tsdb.TagKeys{{Measurement: "cpu", Keys: []string{"host"}}}, nil
+			},
+			TagValuesWithOptionsFn: func(auth query.FineAuthorizer, ids []uint64, cond cnosql.Expr, opt tsdb.TagValuesOptions) ([]tsdb.TagValues, error) {
+				shardIDs = append(shardIDs, ids)
+				return []tsdb.TagValues{{Measurement: "cpu", Values: []tsdb.KeyValue{{Key: "host", Value: "server01"}}}}, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt   string
+		values [][]interface{}
+	}{
+		{stmt: `SHOW TAG KEYS ON db0`, values: [][]interface{}{{"host"}}},
+		{stmt: `SHOW TAG VALUES ON db0 WITH KEY = host`, values: [][]interface{}{{"host", "server01"}}},
+	} {
+		shardIDs = nil
+		results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+
+		// Each shard is read once.
+		if exp := [][]uint64{{1, 2, 3, 4}}; !reflect.DeepEqual(shardIDs, exp) {
+			t.Fatalf("%s: unexpected shards: %v", tt.stmt, shardIDs)
+		}
+		if len(results) != 1 || len(results[0].Series) != 1 || !reflect.DeepEqual(results[0].Series[0].Values, tt.values) {
+			t.Fatalf("%s: unexpected results: %v", tt.stmt, results)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowTagKeyCardinality(t *testing.T) {
+	di := &meta.DatabaseInfo{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "rp0",
+		RetentionPolicies:      []meta.RetentionPolicyInfo{{Name: "rp0"}, {Name: "rp1"}},
+	}
+	t0 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		policies []string
+		shardIDs []uint64
+		conds    []string
+	)
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo { return di },
+			// Each retention policy has a shard group before t0 and one
+			// after it.
+			ShardGroupsByTimeRangeFn: func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+				policies = append(policies, policy)
+				base := uint64(1)
+				if policy == "rp1" {
+					base = 3
+				}
+				var groups []meta.ShardGroupInfo
+				if min.Before(t0) {
+					groups = append(groups, meta.ShardGroupInfo{Shards: []meta.ShardInfo{{ID: base}}})
+				}
+				if !max.Before(t0) {
+					groups = append(groups, meta.ShardGroupInfo{Shards: []meta.ShardInfo{{ID: base + 1}}})
+				}
+				return groups, nil
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			TagKeysFn: func(auth query.FineAuthorizer, ids []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error) {
+				shardIDs = ids
+				if cond != nil {
+					conds = append(conds, cond.String())
+				}
+				var tagKeys []tsdb.TagKeys
+				for _, m := range []tsdb.TagKeys{
+					{Measurement: "cpu", Keys: []string{"host", "region"}},
+					{Measurement: "disk"},
+					{Measurement: "mem", Keys: []string{"host"}},
+				} {
+					if cond == nil || cnosql.EvalBool(cond, map[string]interface{}{"_name": m.Measurement, "host": "a"}) {
+						tagKeys = append(tagKeys, m)
+					}
+				}
+				return tagKeys, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt     string
+		policies []string
+		shardIDs []uint64
+		conds    []string
+		rows     map[string]int
+	}{
+		// Measurements without tag keys have no row.
+		{
+			stmt:     `SHOW TAG KEY CARDINALITY ON db0`,
+			policies: []string{"rp0", "rp1"},
+			shardIDs: []uint64{1, 2, 3, 4},
+			rows:     map[string]int{"cpu": 2, "mem": 1},
+		},
+		// Only the shards of the time range are read.
+		{
+			stmt:     `SHOW TAG KEY CARDINALITY ON db0 WHERE time >= '2000-01-01T00:00:00Z'`,
+			policies: []string{"rp0", "rp1"},
+			shardIDs: []uint64{2, 4},
+			rows:     map[string]int{"cpu": 2, "mem": 1},
+		},
+		{
+			stmt:     `SHOW TAG KEY CARDINALITY ON db0 WHERE time < '2000-01-01T00:00:00Z' AND host = 'a'`,
+			policies: []string{"rp0", "rp1"},
+			shardIDs: []uint64{1, 3},
+			conds:    []string{`host = 'a'`},
+			rows:     map[string]int{"cpu": 2, "mem": 1},
+		},
+		// The sources restrict the retention policies and measurements.
+		{
+			stmt:     `SHOW TAG KEY CARDINALITY FROM db0.rp1.cpu WHERE time >= '2000-01-01T00:00:00Z'`,
+			policies: []string{"rp1"},
+			shardIDs: []uint64{4},
+			conds:    []string{`_name = 'cpu'`},
+			rows:     map[string]int{"cpu": 2},
+		},
+		// OFFSET and LIMIT apply to the measurements with tag keys.
+		{
+			stmt:     `SHOW TAG KEY CARDINALITY ON db0 LIMIT 1 OFFSET 1`,
+			policies: []string{"rp0", "rp1"},
+			shardIDs: []uint64{1, 2, 3, 4},
+			rows:     map[string]int{"mem": 1},
+		},
+	} {
+		policies, shardIDs, conds = nil, nil, nil
+		results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		if !reflect.DeepEqual(policies, tt.policies) {
+			t.Fatalf("%s: unexpected retention policies: %v", tt.stmt, policies)
+		} else if !reflect.DeepEqual(shardIDs, tt.shardIDs) {
+			t.Fatalf("%s: unexpected shards: %v", tt.stmt, shardIDs)
+		} else if !reflect.DeepEqual(conds, tt.conds) {
+			t.Fatalf("%s: unexpected condition: %q", tt.stmt, conds)
+		}
+
+		rows := make(map[string]int)
+		for _, row := range results[0].Series {
+			if !reflect.DeepEqual(row.Columns, []string{"count"}) {
+				t.Fatalf("%s: unexpected columns: %v", tt.stmt, row.Columns)
+			}
+			rows[row.Name] = row.Values[0][0].(int)
+		}
+		if !reflect.DeepEqual(rows, tt.rows) {
+			t.Fatalf("%s: unexpected rows: %v", tt.stmt, rows)
+		}
+	}
+
+	if _, err := executeStatement(e, `SHOW TAG KEY CARDINALITY ON db0 GROUP BY host`, query.ExecutionOptions{UserAdmin: true}); err == nil || err.Error() != "SHOW TAG KEY CARDINALITY doesn't support GROUP BY" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowTagValuesCardinality(t *testing.T) {
+	di := &meta.DatabaseInfo{Name: "db0", RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}}}
+
+	var key, cond cnosql.Expr
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo { return di },
+			ShardGroupsByTimeRangeFn: func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+				return []meta.ShardGroupInfo{{Shards: []meta.ShardInfo{{ID: 1}}}}, nil
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			TagValuesCardinalityFn: func(auth query.FineAuthorizer, shardIDs []uint64, k, c cnosql.Expr) ([]tsdb.MeasurementTagValueN, error) {
+				key, cond = k, c
+				return []tsdb.MeasurementTagValueN{{Measurement: "cpu", N: 3}, {Measurement: "disk", N: 2}, {Measurement: "mem", N: 1}}, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt string
+		key  string
+		cond string
+		rows map[string]int64
+	}{
+		{
+			stmt: `SHOW TAG VALUES CARDINALITY ON db0 WITH KEY = host`,
+			key:  `_tagKey = 'host'`,
+			rows: map[string]int64{"cpu": 3, "disk": 2, "mem": 1},
+		},
+		{
+			stmt: `SHOW TAG VALUES CARDINALITY ON db0 WITH KEY IN (host, region) WHERE region = 'east'`,
+			key:  `_tagKey = 'host' OR _tagKey = 'region'`,
+			cond: `region = 'east'`,
+			rows: map[string]int64{"cpu": 3, "disk": 2, "mem": 1},
+		},
+		// OFFSET and LIMIT apply to measurements.
+		{
+			stmt: `SHOW TAG VALUES CARDINALITY ON db0 WITH KEY =~ /.*/ LIMIT 1 OFFSET 1`,
+			key:  `_tagKey =~ /.*/`,
+			rows: map[string]int64{"disk": 2},
+		},
+	} {
+		key, cond = nil, nil
+		results, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		if key.String() != tt.key {
+			t.Fatalf("%s: unexpected key condition: %s", tt.stmt, key)
+		} else if (cond == nil && tt.cond != "") || (cond != nil && cond.String() != tt.cond) {
+			t.Fatalf("%s: unexpected condition: %v", tt.stmt, cond)
+		}
+
+		rows := make(map[string]int64)
+		for _, row := range results[0].Series {
+			rows[row.Name] = row.Values[0][0].(int64)
+		}
+		if !reflect.DeepEqual(rows, tt.rows) {
+			t.Fatalf("%s: unexpected rows: %v", tt.stmt, rows)
+		}
+	}
+
+	if _, err := executeStatement(e, `SHOW TAG VALUES CARDINALITY ON db0 WITH KEY = host GROUP BY region`, query.ExecutionOptions{UserAdmin: true}); err == nil || err.Error() != "SHOW TAG VALUES CARDINALITY doesn't support GROUP BY" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowMeasurementCardinalityCondition(t *testing.T) {
+	var cond cnosql.Expr
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo { return nil },
+		},
+		TSDBStore: &testTSDBStore{
+			MeasurementsCardinalityFn: func(auth query.FineAuthorizer, database string) (int64, error) {
+				return 10, nil
+			},
+			MeasurementsCardinalityByExprFn: func(auth query.FineAuthorizer, database, rp string, c cnosql.Expr) (int64, error) {
+				cond = c
+				return 2, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt   string
+		cond   string
+		column string
+		values [][]interface{}
+	}{
+		// The whole database is estimated.
+		{stmt: `SHOW MEASUREMENT CARDINALITY ON db0`, column: "cardinality estimation", values: [][]interface{}{{int64(10)}}},
+		// Anything narrower is counted exactly.
+		{stmt: `SHOW MEASUREMENT EXACT CARDINALITY ON db0`, column: "count", values: [][]interface{}{{int64(2)}}},
+		{stmt: `SHOW MEASUREMENT CARDINALITY ON db0 WHERE host = 'a'`, cond: `host = 'a'`, column: "count", values: [][]interface{}{{int64(2)}}},
+		{stmt: `SHOW MEASUREMENT CARDINALITY ON db0 FROM cpu, /^mem/ WHERE host = 'a'`, cond: `(_name = 'cpu' OR _name =~ /^mem/) AND (host = 'a')`, column: "count", values: [][]interface{}{{int64(2)}}},
+		{stmt: `SHOW MEASUREMENT EXACT CARDINALITY ON db0 OFFSET 1`, column: "count"},
+	} {
+		stmt, err := query.RewriteStatement(cnosql.MustParseStatement(tt.stmt))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		cond = nil
+		ctx := &query.ExecutionContext{
+			Context:          context.Background(),
+			Results:          make(chan *query.Result, 1),
+			ExecutionOptions: query.ExecutionOptions{UserAdmin: true},
+		}
+		if err := e.ExecuteStatement(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+
+		row := (<-ctx.Results).Series[0]
+		if (cond == nil && tt.cond != "") || (cond != nil && cond.String() != tt.cond) {
+			t.Fatalf("%s: unexpected condition: %v", tt.stmt, cond)
+		} else if !reflect.DeepEqual(row.Columns, []string{tt.column}) {
+			t.Fatalf("%s: unexpected columns: %v", tt.stmt, row.Columns)
+		} else if !reflect.DeepEqual(row.Values, tt.values) {
+			t.Fatalf("%s: unexpected values: %v", tt.stmt, row.Values)
+		}
+	}
+}
+
+func TestSourcesMatchMeasurement(t *testing.T) {
+	sources := func(s string) cnosql.Sources {
+		return cnosql.MustParseStatement(`SHOW TAG KEYS FROM ` + s).(*cnosql.ShowTagKeysStatement).Sources
+	}
+	for _, tt := range []struct {
+		sources cnosql.Sources
+		name    string
+		exp     bool
+	}{
+		{name: "cpu", exp: true},
+		{sources: sources(`cpu`), name: "cpu", exp: true},
+		{sources: sources(`cpu`), name: "cpu_load"},
+		{sources: sources(`/^cpu/`), name: "cpu_load", exp: true},
+		{sources: sources(`/^cpu/`), name: "mem"},
+		{sources: sources(`mem, /load$/`), name: "cpu_load", exp: true},
+	} {
+		if got := sourcesMatchMeasurement(tt.sources, tt.name); got != tt.exp {
+			t.Fatalf("%v matches %s: got %v, exp %v", tt.sources, tt.name, got, tt.exp)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowTagKeysRegexSources(t *testing.T) {
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name, RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}}}
+			},
+			ShardGroupsByTimeRangeFn: func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+				return []meta.ShardGroupInfo{{Shards: []meta.ShardInfo{{ID: 1}}}}, nil
+			},
+		},
+		// The store returns more measurements than the sources select.
+		TSDBStore: &testTSDBStore{
+			TagKeysFn: func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error) {
+				return []tsdb.TagKeys{
+					{Measurement: "cpu", Keys: []string{"host"}},
+					{Measurement: "cpu_load", Keys: []string{"core", "host"}},
+					{Measurement: "mem", Keys: []string{"host"}},
+				}, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt  string
+		names []string
+	}{
+		{stmt: `SHOW TAG KEYS ON db0`, names: []string{"cpu", "cpu_load", "mem"}},
+		{stmt: `SHOW TAG KEYS ON db0 FROM /^cpu/`, names: []string{"cpu", "cpu_load"}},
+		{stmt: `SHOW TAG KEYS ON db0 FROM /load$/, mem`, names: []string{"cpu_load", "mem"}},
+		// OFFSET and LIMIT apply per measurement.
+		{stmt: `SHOW TAG KEYS ON db0 FROM /^cpu/ LIMIT 1 OFFSET 1`, names: []string{"cpu_load"}},
+	} {
+		stmt, err := query.RewriteStatement(cnosql.MustParseStatement(tt.stmt))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		ctx := &query.ExecutionContext{
+			Context:          context.Background(),
+			Results:          make(chan *query.Result, 10),
+			ExecutionOptions: query.ExecutionOptions{UserAdmin: true},
+		}
+		if err := e.ExecuteStatement(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		close(ctx.Results)
+
+		var names []string
+		for r := range ctx.Results {
+			for _, row := range r.Series {
+				names = append(names, row.Name)
+			}
+		}
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Fatalf("%s: unexpected measurements: %v", tt.stmt, names)
+		}
+	}
+}
+
+func TestTagKeyMatches(t *testing.T) {
+	with := func(s string) (cnosql.Token, cnosql.Literal) {
+		stmt := cnosql.MustParseStatement(`SHOW TAG VALUES WITH KEY ` + s).(*cnosql.ShowTagValuesStatement)
+		return stmt.Op, stmt.TagKeyExpr
+	}
+	for _, tt := range []struct {
+		with string
+		key  string
+		exp  bool
+	}{
+		{with: `= host`, key: "host", exp: true},
+		{with: `= host`, key: "region"},
+		{with: `!= host`, key: "region", exp: true},
+		{with: `IN (host, region)`, key: "region", exp: true},
+		{with: `IN (host, region)`, key: "zone"},
+		{with: `=~ /^reg/`, key: "region", exp: true},
+		{with: `!~ /^reg/`, key: "region"},
+	} {
+		op, expr := with(tt.with)
+		if got := tagKeyMatches(op, expr, tt.key); got != tt.exp {
+			t.Fatalf("WITH KEY %s matches %s: got %v, exp %v", tt.with, tt.key, got, tt.exp)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowTagValuesWithKey(t *testing.T) {
+	var cond cnosql.Expr
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name, RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}}}
+			},
+			ShardGroupsByTimeRangeFn: func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+				return []meta.ShardGroupInfo{{Shards: []meta.ShardInfo{{ID: 1}}}}, nil
+			},
+		},
+		// The store returns the values of every key.
+		TSDBStore: &testTSDBStore{
+			TagValuesWithOptionsFn: func(auth query.FineAuthorizer, shardIDs []uint64, c cnosql.Expr, opt tsdb.TagValuesOptions) ([]tsdb.TagValues, error) {
+				cond = c
+				return []tsdb.TagValues{{Measurement: "cpu", Values: []tsdb.KeyValue{
+					{Key: "host", Value: "a"},
+					{Key: "host", Value: "b"},
+					{Key: "region", Value: "east"},
+					{Key: "zone", Value: "z1"},
+				}}}, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt   string
+		cond   string
+		values [][]interface{}
+	}{
+		{
+			stmt:   `SHOW TAG VALUES ON db0 WITH KEY = region`,
+			cond:   `_tagKey = 'region'`,
+			values: [][]interface{}{{"region", "east"}},
+		},
+		{
+			stmt:   `SHOW TAG VALUES ON db0 FROM cpu WITH KEY IN (host, zone) WHERE region = 'east'`,
+			cond:   `((_name = 'cpu') AND (region = 'east')) AND (_tagKey = 'host' OR _tagKey = 'zone')`,
+			values: [][]interface{}{{"host", "a"}, {"host", "b"}, {"zone", "z1"}},
+		},
+		// Pagination only counts the values of the requested keys.
+		{
+			stmt:   `SHOW TAG VALUES ON db0 WITH KEY =~ /^(host|zone)$/ LIMIT 2 OFFSET 1`,
+			cond:   `_tagKey =~ /^(host|zone)$/`,
+			values: [][]interface{}{{"host", "b"}, {"zone", "z1"}},
+		},
+	} {
+		stmt, err := query.RewriteStatement(cnosql.MustParseStatement(tt.stmt))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		cond = nil
+		ctx := &query.ExecutionContext{
+			Context:          context.Background(),
+			Results:          make(chan *query.Result, 1),
+			ExecutionOptions: query.ExecutionOptions{UserAdmin: true},
+		}
+		if err := e.ExecuteStatement(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+
+		if cond == nil || cond.String() != tt.cond {
+			t.Fatalf("%s: unexpected condition: %v", tt.stmt, cond)
+		} else if values := (<-ctx.Results).Series[0].Values; !reflect.DeepEqual(values, tt.values) {
+			t.Fatalf("%s: unexpected values: %v", tt.stmt, values)
+		}
+	}
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowMeasurementsRetentionPolicy(t *testing.T) {
+	var rps []string
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				if name != "db0" {
+					return nil
+				}
+				return &meta.DatabaseInfo{Name: name, RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0"}, {Name: "rp1"}}}
+			},
+		},
+		TSDBStore: &testTSDBStore{
+			MeasurementNamesFn: func(auth query.FineAuthorizer, database, rp string, cond cnosql.Expr) ([][]byte, error) {
+				rps = append(rps, rp)
+				return [][]byte{[]byte("cpu")}, nil
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		stmt string
+		rps  []string
+		err  string
+	}{
+		{stmt: `SHOW MEASUREMENTS ON db0`, rps: []string{""}},
+		{stmt: `SHOW MEASUREMENTS ON db0.rp1`, rps: []string{"rp1"}},
+		{stmt: `SHOW MEASUREMENTS ON db0.rp2`, err: "retention policy not found: rp2"},
+		{stmt: `SHOW MEASUREMENTS ON db1.rp0`, err: "database not found: db1"},
+	} {
+		rps = nil
+		_, err := executeStatement(e, tt.stmt, query.ExecutionOptions{UserAdmin: true})
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: unexpected error: %v", tt.stmt, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.stmt, err)
+		}
+		if !reflect.DeepEqual(rps, tt.rps) {
+			t.Fatalf("%s: unexpected retention policies: %q", tt.stmt, rps)
+		}
+	}
+}
+
+// testContinuousQueryRuns returns the runs of continuous queries by name and
+// records the ones that are reset.
+type testContinuousQueryRuns struct {
+	runs  map[string]ContinuousQueryRun
+	reset []string
+}
+
+func (r *testContinuousQueryRuns) LastContinuousQueryRun(database, name string) (ContinuousQueryRun, bool) {
+	run, ok := r.runs[name]
+	return run, ok
+}
+
+func (r *testContinuousQueryRuns) ResetContinuousQuery(database, name string) {
+	r.reset = append(r.reset, database+"."+name)
+}
+
+func TestStatementExecutor_ExecuteStatement_ShowContinuousQueriesLastError(t *testing.T) {
+	t0 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.FixedZone("", 3600))
+	errFailed := errors.New("query failed")
+	runs := &testContinuousQueryRuns{runs: map[string]ContinuousQueryRun{
+		"cq0": {Time: t0, Duration: time.Second},
+		"cq1": {Time: t0, Duration: time.Second, Err: errFailed, ErrTime: t0.Add(time.Second)},
+		"cq2": {Err: errFailed, ErrTime: t0},
+	}}
+	e := &StatementExecutor{
+		MetaClient: &testMetaClient{
+			DatabasesFn: func() []meta.DatabaseInfo {
+				return []meta.DatabaseInfo{{
+					Name: "db0",
+					ContinuousQueries: []meta.ContinuousQueryInfo{
+						{Name: "cq0", Query: "q0"}, {Name: "cq1", Query: "q1"}, {Name: "cq2", Query: "q2"}, {Name: "cq3", Query: "q3"},
+					},
+				}}
+			},
+			DropContinuousQueryFn: func(database, name string) error {
+				if name == "cq9" {
+					return errors.New("continuous query not found")
+				}
+				return nil
+			},
+		},
+		ContinuousQueryRuns: runs,
+	}
+	opt := query.ExecutionOptions{UserAdmin: true}
+
+	results, err := executeStatement(e, `SHOW CONTINUOUS QUERIES`, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := results[0].Series[0]
+	if !reflect.DeepEqual(row.Columns[6:], []string{"last_run", "last_duration", "last_error", "last_error_time"}) {
+		t.Fatalf("unexpected columns: %q", row.Columns)
+	}
+	var values [][]interface{}
+	for _, v := range row.Values {
+		values = append(values, v[6:])
+	}
+	// Queries failing before they execute have no last run, and those that
+	// haven't run since startup report null.
+	if exp := [][]interface{}{
+		{"1999-12-31T23:00:00Z", "1s", nil, nil},
+		{"1999-12-31T23:00:00Z", "1s", "query failed", "1999-12-31T23:00:01Z"},
+		{nil, nil, "query failed", "1999-12-31T23:00:00Z"},
+		{nil, nil, nil, nil},
+	}; !reflect.DeepEqual(values, exp) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	// Dropping a continuous query forgets its runs, unless the drop fails.
+	if _, err := executeStatement(e, `DROP CONTINUOUS QUERY cq1 ON db0`, opt); err != nil {
+		t.Fatal(err)
+	} else if _, err := executeStatement(e, `DROP CONTINUOUS QUERY cq9 ON db0`, opt); err == nil {
+		t.Fatal("expected error")
+	}
+	if !reflect.DeepEqual(runs.reset, []string{"db0.cq1"}) {
+		t.Fatalf("unexpected reset queries: %v", runs.reset)
+	}
+}
+
+// testMetaClient is a MetaClient whose methods call the function fields.
+// Methods without a function field panic.
+type testMetaClient struct {
+	MetaClient
+
+	DataNodeFn               func(id uint64) (*meta.NodeInfo, error)
+	DatabaseFn               func(name string) *meta.DatabaseInfo
+	DatabasesFn              func() []meta.DatabaseInfo
+	DeleteShardGroupFn       func(database, policy string, id uint64) error
+	DropContinuousQueryFn    func(database, name string) error
+	PurgeShardGroupFn        func(database, policy string, id uint64) error
+	ShardGroupsByTimeRangeFn func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error)
+	UserFn                   func(name string) (meta.User, error)
+}
+
+func (c *testMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	return c.DataNodeFn(id)
+}
+
+func (c *testMetaClient) Database(name string) *meta.DatabaseInfo {
+	return c.DatabaseFn(name)
+}
+
+func (c *testMetaClient) Databases() []meta.DatabaseInfo {
+	return c.DatabasesFn()
+}
+
+func (c *testMetaClient) DeleteShardGroup(database, policy string, id uint64) error {
+	return c.DeleteShardGroupFn(database, policy, id)
+}
+
+func (c *testMetaClient) DropContinuousQuery(database, name string) error {
+	return c.DropContinuousQueryFn(database, name)
+}
+
+func (c *testMetaClient) PurgeShardGroup(database, policy string, id uint64) error {
+	return c.PurgeShardGroupFn(database, policy, id)
+}
+
+func (c *testMetaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
+}
+
+func (c *testMetaClient) User(name string) (meta.User, error) {
+	return c.UserFn(name)
+}
+
+// testTSDBStore is a TSDBStore whose methods call the function fields.
+// Methods without a function field panic.
+type testTSDBStore struct {
+	TSDBStore
+
+	DeleteDatabaseFn                 func(name string) error
+	DeleteRetentionPolicyFn          func(database, name string) error
+	DeleteSeriesFn                   func(database string, sources []cnosql.Source, condition cnosql.Expr) error
+	DeleteShardFn                    func(id uint64) error
+	FieldKeysFn                      func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.FieldKeys, error)
+	ForEachSeriesKeyFn               func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error
+	MeasurementNamesFn               func(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) ([][]byte, error)
+	MeasurementsCardinalityFn        func(auth query.FineAuthorizer, database string) (int64, error)
+	MeasurementsCardinalityByExprFn  func(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) (int64, error)
+	MeasurementSeriesCardinalityFn   func(ctx context.Context, auth query.FineAuthorizer, database string, names [][]byte, concurrency int) ([]int64, error)
+	SeriesCardinalityFn              func(auth query.FineAuthorizer, database string) (int64, error)
+	SeriesCardinalityByMeasurementFn func(auth query.FineAuthorizer, database string) ([]tsdb.MeasurementSeriesN, error)
+	ShardGroupFn                     func(ids []uint64) tsdb.ShardGroup
+	ShardDiskStatsFn                 func(ids []uint64) (map[uint64]tsdb.ShardDiskStat, error)
+	ShardsDiskSizeFn                 func(ids []uint64) (int64, error)
+	ShardStateFn                     func(id uint64) string
+	TagKeysFn                        func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error)
+	TagValuesCardinalityFn           func(auth query.FineAuthorizer, shardIDs []uint64, key, cond cnosql.Expr) ([]tsdb.MeasurementTagValueN, error)
+	TagValuesWithOptionsFn           func(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.TagValuesOptions) ([]tsdb.TagValues, error)
+}
+
+func (s *testTSDBStore) DeleteDatabase(name string) error {
+	return s.DeleteDatabaseFn(name)
+}
+
+func (s *testTSDBStore) DeleteRetentionPolicy(database, name string) error {
+	return s.DeleteRetentionPolicyFn(database, name)
+}
+
+func (s *testTSDBStore) DeleteShard(id uint64) error {
+	return s.DeleteShardFn(id)
+}
+
+func (s *testTSDBStore) DeleteSeries(database string, sources []cnosql.Source, condition cnosql.Expr) error {
+	return s.DeleteSeriesFn(database, sources, condition)
+}
+
+func (s *testTSDBStore) FieldKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.FieldKeys, error) {
+	return s.FieldKeysFn(auth, shardIDs, cond)
+}
+
+func (s *testTSDBStore) ForEachSeriesKey(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.SeriesKeysOptions, fn func(key string) error) error {
+	return s.ForEachSeriesKeyFn(auth, shardIDs, cond, opt, fn)
+}
+
+func (s *testTSDBStore) MeasurementNames(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) ([][]byte, error) {
+	return s.MeasurementNamesFn(auth, database, retentionPolicy, cond)
+}
+
+func (s *testTSDBStore) MeasurementsCardinalityByExpr(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) (int64, error) {
+	return s.MeasurementsCardinalityByExprFn(auth, database, retentionPolicy, cond)
+}
+
+func (s *testTSDBStore) MeasurementsCardinality(auth query.FineAuthorizer, database string) (int64, error) {
+	return s.MeasurementsCardinalityFn(auth, database)
+}
+
+func (s *testTSDBStore) MeasurementSeriesCardinality(ctx context.Context, auth query.FineAuthorizer, database string, names [][]byte, concurrency int) ([]int64, error) {
+	return s.MeasurementSeriesCardinalityFn(ctx, auth, database, names, concurrency)
+}
+
+func (s *testTSDBStore) SeriesCardinality(auth query.FineAuthorizer, database string) (int64, error) {
+	return s.SeriesCardinalityFn(auth, database)
+}
+
+func (s *testTSDBStore) SeriesCardinalityByMeasurement(auth query.FineAuthorizer, database string) ([]tsdb.MeasurementSeriesN, error) {
+	return s.SeriesCardinalityByMeasurementFn(auth, database)
+}
+
+func (s *testTSDBStore) ShardGroup(ids []uint64) tsdb.ShardGroup {
+	return s.ShardGroupFn(ids)
+}
+
+func (s *testTSDBStore) ShardDiskStats(ids []uint64) (map[uint64]tsdb.ShardDiskStat, error) {
+	return s.ShardDiskStatsFn(ids)
+}
+
+func (s *testTSDBStore) ShardsDiskSize(ids []uint64) (int64, error) {
+	return s.ShardsDiskSizeFn(ids)
+}
+
+func (s *testTSDBStore) ShardState(id uint64) string {
+	return s.ShardStateFn(id)
+}
+
+func (s *testTSDBStore) TagKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error) {
+	return s.TagKeysFn(auth, shardIDs, cond)
+}
+
+func (s *testTSDBStore) TagValuesCardinality(auth query.FineAuthorizer, shardIDs []uint64, key, cond cnosql.Expr) ([]tsdb.MeasurementTagValueN, error) {
+	return s.TagValuesCardinalityFn(auth, shardIDs, key, cond)
+}
+
+func (s *testTSDBStore) TagValuesWithOptions(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt tsdb.TagValuesOptions) ([]tsdb.TagValues, error) {
+	return s.TagValuesWithOptionsFn(auth, shardIDs, cond, opt)
+}
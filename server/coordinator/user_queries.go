@@ -0,0 +1,110 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+)
+
+// The keys for statistics generated by the "coordinator_user_queries" module.
+const (
+	statUserQueriesActive      = "queriesActive"      // Number of SELECT statements the user is running.
+	statUserQueriesExecuted    = "queriesExecuted"    // Number of SELECT statements the user started.
+	statUserQueriesRateLimited = "queriesRateLimited" // Number of SELECT statements rejected by the user's limits.
+	userQueriesStatisticsName  = "coordinator_user_queries"
+
+	// userQueryRateWindow is the window the per minute limit is counted over.
+	userQueryRateWindow = time.Minute
+
+	// userQueryConcurrencyRetryAfter is the retry hint given when a user
+	// runs too many queries at once. When one finishes can't be known.
+	userQueryConcurrencyRetryAfter = time.Second
+)
+
+type userQueryValues struct {
+	active      int64
+	executed    int64
+	rateLimited int64
+
+	// Start and number of queries of the current rate window.
+	windowStart time.Time
+	windowN     int64
+}
+
+// userQueryLimiter enforces the per user limits on concurrent queries and
+// queries per minute, and counts the queries of each user for SHOW STATS.
+type userQueryLimiter struct {
+	mu     sync.Mutex
+	values map[string]*userQueryValues
+}
+
+// acquire admits a query of user at now, or returns a *query.RateLimitError
+// if it would exceed maxConcurrent running queries or maxPerMinute queries
+// started within the minute. Zero limits are unlimited. The returned
+// function must be called once an admitted query finishes.
+func (l *userQueryLimiter) acquire(user string, maxConcurrent, maxPerMinute int64, now time.Time) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.values == nil {
+		l.values = make(map[string]*userQueryValues)
+	}
+	v := l.values[user]
+	if v == nil {
+		v = &userQueryValues{}
+		l.values[user] = v
+	}
+
+	if now.Sub(v.windowStart) >= userQueryRateWindow {
+		v.windowStart, v.windowN = now, 0
+	}
+
+	if maxConcurrent > 0 && v.active >= maxConcurrent {
+		v.rateLimited++
+		return nil, &query.RateLimitError{
+			User:       user,
+			Limit:      "max-concurrent-queries",
+			Max:        maxConcurrent,
+			RetryAfter: userQueryConcurrencyRetryAfter,
+		}
+	} else if maxPerMinute > 0 && v.windowN >= maxPerMinute {
+		v.rateLimited++
+		return nil, &query.RateLimitError{
+			User:       user,
+			Limit:      "max-queries-per-minute",
+			Max:        maxPerMinute,
+			RetryAfter: v.windowStart.Add(userQueryRateWindow).Sub(now),
+		}
+	}
+
+	v.active++
+	v.executed++
+	v.windowN++
+	return func() {
+		l.mu.Lock()
+		v.active--
+		l.mu.Unlock()
+	}, nil
+}
+
+// Statistics returns one statistic per user that ran a query.
+func (l *userQueryLimiter) Statistics(tags map[string]string) []models.Statistic {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	statistics := make([]models.Statistic, 0, len(l.values))
+	for user, v := range l.values {
+		statistics = append(statistics, models.Statistic{
+			Name: userQueriesStatisticsName,
+			Tags: models.StatisticTags{"user": user}.Merge(tags),
+			Values: map[string]interface{}{
+				statUserQueriesActive:      v.active,
+				statUserQueriesExecuted:    v.executed,
+				statUserQueriesRateLimited: v.rateLimited,
+			},
+		})
+	}
+	return statistics
+}
@@ -73,6 +73,17 @@ type queue struct {
 	// The segments that exist on disk
 	segments segments
 }
+
+// Queue is the queue used for hinted handoff, exported so other services can
+// buffer data on disk the same way.
+type Queue = queue
+
+// NewQueue returns a queue that will store segments in dir and that will not
+// consume more than maxSize on disk. The queue must be opened before use.
+func NewQueue(dir string, maxSize int64) (*Queue, error) {
+	return newQueue(dir, maxSize)
+}
+
 type queuePos struct {
 	head string
 	tail string
@@ -233,6 +244,13 @@ func (l *queue) Position() (*queuePos, error) {
 	return qp, nil
 }
 
+// DiskUsage returns the total size on disk used by the queue.
+func (l *queue) DiskUsage() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.diskUsage()
+}
+
 // diskUsage returns the total size on disk used by the queue
 func (l *queue) diskUsage() int64 {
 	var size int64
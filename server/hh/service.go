@@ -191,6 +191,18 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 	return nil
 }
 
+// Pending returns whether writes for node ownerID are queued and not yet
+// delivered, i.e. whether the node is missing writes made through this node.
+func (s *Service) Pending(ownerID uint64) bool {
+	s.mu.RLock()
+	processor, ok := s.processors[ownerID]
+	s.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	return processor.Head() != processor.Tail()
+}
+
 // Diagnostics returns diagnostic information.
 func (s *Service) Diagnostics() (*diagnostics.Diagnostics, error) {
 	s.mu.RLock()
@@ -55,7 +55,10 @@ type HTTPConfig struct {
 	MaxConcurrentWriteLimit int            `toml:"max-concurrent-write-limit"`
 	MaxEnqueuedWriteLimit   int            `toml:"max-enqueued-write-limit"`
 	EnqueuedWriteTimeout    time.Duration  `toml:"enqueued-write-timeout"`
+	StrictReadOnly          bool           `toml:"strict-read-only"`
+	LDAP                    LDAPConfig     `toml:"ldap"`
 	TLS                     *tls.Config    `toml:"-"`
+	AuthProvider            AuthProvider   `toml:"-"`
 }
 
 func NewHTTPConfig() HTTPConfig {
@@ -74,6 +77,7 @@ func NewHTTPConfig() HTTPConfig {
 		BindSocket:            DefaultBindSocket,
 		MaxBodySize:           DefaultMaxBodySize,
 		EnqueuedWriteTimeout:  DefaultEnqueuedWriteTimeout,
+		LDAP:                  NewLDAPConfig(),
 	}
 }
 
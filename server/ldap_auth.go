@@ -0,0 +1,185 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/cnosdb/pkg/ldap"
+	"github.com/cnosdb/cnosdb/vend/common/pkg/toml"
+)
+
+const (
+	// DefaultLDAPMaxIdleConnections is the default number of idle connections
+	// kept to the LDAP server.
+	DefaultLDAPMaxIdleConnections = 4
+
+	// DefaultLDAPGroupSearchFilter is the default filter finding the groups
+	// of a user. {dn} and {username} are replaced by the escaped DN and name
+	// of the user.
+	DefaultLDAPGroupSearchFilter = "(member={dn})"
+
+	// DefaultLDAPGroupAttribute is the default attribute holding group names.
+	DefaultLDAPGroupAttribute = "cn"
+)
+
+// LDAPConfig represents the configuration of the authentication of users
+// against an LDAP directory.
+type LDAPConfig struct {
+	Enabled            bool          `toml:"enabled"`
+	URL                string        `toml:"url"`
+	StartTLS           bool          `toml:"start-tls"`
+	CAFile             string        `toml:"ca-file"`
+	InsecureSkipVerify bool          `toml:"insecure-skip-verify"`
+	Timeout            toml.Duration `toml:"timeout"`
+	MaxIdleConnections int           `toml:"max-idle-connections"`
+	BindDN             string        `toml:"bind-dn"`
+	GroupSearchBaseDN  string        `toml:"group-search-base-dn"`
+	GroupSearchFilter  string        `toml:"group-search-filter"`
+	GroupAttribute     string        `toml:"group-attribute"`
+	AdminGroups        []string      `toml:"admin-groups"`
+	CreateUsers        bool          `toml:"create-users"`
+}
+
+// NewLDAPConfig returns an instance of LDAPConfig with defaults.
+func NewLDAPConfig() LDAPConfig {
+	return LDAPConfig{
+		Timeout:            toml.Duration(ldap.DefaultTimeout),
+		MaxIdleConnections: DefaultLDAPMaxIdleConnections,
+		GroupSearchFilter:  DefaultLDAPGroupSearchFilter,
+		GroupAttribute:     DefaultLDAPGroupAttribute,
+	}
+}
+
+// Validate returns an error if the config is invalid.
+func (c LDAPConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.URL == "" {
+		return errors.New("ldap url must be set")
+	} else if !strings.Contains(c.BindDN, "{username}") {
+		return errors.New("ldap bind-dn must contain {username}")
+	} else if len(c.AdminGroups) > 0 && c.GroupSearchBaseDN == "" {
+		return errors.New("ldap group-search-base-dn must be set to map admin-groups")
+	} else if c.MaxIdleConnections < 0 {
+		return errors.New("ldap max-idle-connections must not be negative")
+	}
+	return nil
+}
+
+// LDAPAuthProvider authenticates users by binding to an LDAP directory as
+// them. Members of the admin groups are admins.
+type LDAPAuthProvider struct {
+	config LDAPConfig
+	pool   *ldap.Pool
+}
+
+// NewLDAPAuthProvider returns a new instance of LDAPAuthProvider.
+func NewLDAPAuthProvider(c LDAPConfig) (*LDAPAuthProvider, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ldap ca-file: %s", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ldap ca-file: no certificates found in %s", c.CAFile)
+		}
+	}
+
+	return &LDAPAuthProvider{
+		config: c,
+		pool: &ldap.Pool{
+			Dialer: ldap.Dialer{
+				URL:       c.URL,
+				TLSConfig: tlsConfig,
+				StartTLS:  c.StartTLS,
+				Timeout:   time.Duration(c.Timeout),
+			},
+			MaxIdle: c.MaxIdleConnections,
+		},
+	}, nil
+}
+
+// Authenticate binds as the user and looks up their groups if admin groups
+// are configured.
+func (p *LDAPAuthProvider) Authenticate(username, password string) (*ExternalUser, error) {
+	if password == "" {
+		return nil, ldap.ErrEmptyPassword
+	}
+
+	var u *ExternalUser
+	err := p.do(func(conn *ldap.Conn) error {
+		var err error
+		u, err = p.authenticate(conn, username, password)
+		return err
+	})
+	return u, err
+}
+
+func (p *LDAPAuthProvider) authenticate(conn *ldap.Conn, username, password string) (*ExternalUser, error) {
+	dn := strings.ReplaceAll(p.config.BindDN, "{username}", ldap.EscapeDN(username))
+	if err := conn.Bind(dn, password); err != nil {
+		return nil, err
+	}
+
+	u := &ExternalUser{Name: username, Create: p.config.CreateUsers}
+	if len(p.config.AdminGroups) == 0 {
+		return u, nil
+	}
+
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(dn),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(p.config.GroupSearchFilter)
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     p.config.GroupSearchBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: []string{p.config.GroupAttribute},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	admin := false
+	for _, e := range entries {
+		for _, g := range e.Values(p.config.GroupAttribute) {
+			for _, ag := range p.config.AdminGroups {
+				admin = admin || strings.EqualFold(g, ag)
+			}
+		}
+	}
+	u.Admin = &admin
+	return u, nil
+}
+
+// do runs fn on a pooled connection. It is retried once on a new connection
+// if the pooled one turns out to be broken, e.g. closed by the server while
+// idle.
+func (p *LDAPAuthProvider) do(fn func(conn *ldap.Conn) error) error {
+	for i := 0; ; i++ {
+		conn, err := p.pool.Get()
+		if err != nil {
+			return err
+		}
+		err = fn(conn)
+		p.pool.Put(conn)
+
+		if _, ok := err.(*ldap.Error); ok || err == nil || i > 0 {
+			return err
+		}
+	}
+}
+
+// Close closes the idle connections to the LDAP server.
+func (p *LDAPAuthProvider) Close() error {
+	return p.pool.Close()
+}
@@ -0,0 +1,368 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/pkg/ldap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ldapTestServer is a fake LDAP directory binding users with their passwords
+// and returning the groups of a member to searches with an equality filter.
+type ldapTestServer struct {
+	ln net.Listener
+
+	// Passwords and groups of users by DN.
+	passwords map[string]string
+	groups    map[string][]string
+
+	// Whether connections are closed after each response.
+	closeConns bool
+
+	mu       sync.Mutex
+	binds    []string
+	searches []string
+}
+
+func newLDAPTestServer(t *testing.T, passwords map[string]string, groups map[string][]string) *ldapTestServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &ldapTestServer{ln: ln, passwords: passwords, groups: groups}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *ldapTestServer) URL() string { return "ldap://" + s.ln.Addr().String() }
+
+func (s *ldapTestServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		_, msg, err := berRead(r)
+		if err != nil {
+			return
+		}
+		parts := berChildren(msg)
+		id, op := parts[0], parts[1]
+		args := berChildren(op.contents)
+
+		var resp [][]byte
+		switch op.tag {
+		case 0x60: // BindRequest
+			dn, password := string(args[1].contents), string(args[2].contents)
+			s.mu.Lock()
+			s.binds = append(s.binds, dn)
+			s.mu.Unlock()
+
+			code := 49
+			if pw, ok := s.passwords[dn]; ok && pw == password {
+				code = 0
+			}
+			resp = append(resp, berResult(0x61, code))
+		case 0x63: // SearchRequest with an equality filter
+			value := string(berChildren(args[6].contents)[1].contents)
+			s.mu.Lock()
+			s.searches = append(s.searches, value)
+			s.mu.Unlock()
+
+			for _, g := range s.groups[value] {
+				attr := berTLV(0x30, berTLV(0x04, []byte("cn")), berTLV(0x31, berTLV(0x04, []byte(g))))
+				resp = append(resp, berTLV(0x64, berTLV(0x04, []byte("cn="+g)), berTLV(0x30, attr)))
+			}
+			resp = append(resp, berResult(0x65, 0))
+		default: // UnbindRequest
+			return
+		}
+
+		for _, op := range resp {
+			if _, err := conn.Write(berTLV(0x30, berTLV(id.tag, id.contents), op)); err != nil {
+				return
+			}
+		}
+		if s.closeConns {
+			return
+		}
+	}
+}
+
+// berElement is a BER element read by the fake LDAP server.
+type berElement struct {
+	tag      byte
+	contents []byte
+}
+
+// berTLV encodes a BER element.
+func berTLV(tag byte, contents ...[]byte) []byte {
+	var b []byte
+	for _, c := range contents {
+		b = append(b, c...)
+	}
+	n := len(b)
+	hdr := []byte{tag}
+	if n < 0x80 {
+		hdr = append(hdr, byte(n))
+	} else {
+		hdr = append(hdr, 0x82, byte(n>>8), byte(n))
+	}
+	return append(hdr, b...)
+}
+
+// berResult encodes an LDAPResult with the result code.
+func berResult(tag byte, code int) []byte {
+	return berTLV(tag, berTLV(0x0a, []byte{byte(code)}), berTLV(0x04), berTLV(0x04))
+}
+
+// berRead reads an element with a length of up to two octets.
+func berRead(r io.Reader) (byte, []byte, error) {
+	hdr := make([]byte, 2)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return 0, nil, err
+	}
+	n := int(hdr[1])
+	if n&0x80 != 0 {
+		l := make([]byte, n&0x7f)
+		if _, err := io.ReadFull(r, l); err != nil {
+			return 0, nil, err
+		}
+		n = 0
+		for _, c := range l {
+			n = n<<8 | int(c)
+		}
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return hdr[0], b, err
+}
+
+// berChildren decodes the elements making up a constructed element.
+func berChildren(b []byte) []berElement {
+	var a []berElement
+	for len(b) > 0 {
+		n, hdr := int(b[1]), 2
+		if n&0x80 != 0 {
+			k := n & 0x7f
+			n = 0
+			for _, c := range b[2 : 2+k] {
+				n = n<<8 | int(c)
+			}
+			hdr += k
+		}
+		a = append(a, berElement{tag: b[0], contents: b[hdr : hdr+n]})
+		b = b[hdr+n:]
+	}
+	return a
+}
+
+func TestLDAPAuthProvider_Authenticate(t *testing.T) {
+	const (
+		aliceDN = `uid=alice,ou=people,dc=example,dc=com`
+		johnDN  = `uid=doe\, john,ou=people,dc=example,dc=com`
+	)
+	s := newLDAPTestServer(t,
+		map[string]string{aliceDN: "alice-pw", johnDN: "john-pw"},
+		map[string][]string{aliceDN: {"users", "DBAdmins"}, johnDN: {"users"}},
+	)
+
+	config := NewLDAPConfig()
+	config.Enabled = true
+	config.URL = s.URL()
+	config.BindDN = "uid={username},ou=people,dc=example,dc=com"
+	config.GroupSearchBaseDN = "ou=groups,dc=example,dc=com"
+	config.AdminGroups = []string{"dbadmins"}
+	config.CreateUsers = true
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewLDAPAuthProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// Members of an admin group are admins, whatever the case of the group.
+	u, err := p.Authenticate("alice", "alice-pw")
+	if err != nil {
+		t.Fatal(err)
+	} else if u.Name != "alice" || u.Admin == nil || !*u.Admin || !u.Create {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+
+	// User names are escaped in the bind DN and in the group filter.
+	u, err = p.Authenticate("doe, john", "john-pw")
+	if err != nil {
+		t.Fatal(err)
+	} else if u.Name != "doe, john" || u.Admin == nil || *u.Admin {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+	s.mu.Lock()
+	binds, searches := s.binds, s.searches
+	s.mu.Unlock()
+	if exp := []string{aliceDN, johnDN}; !reflect.DeepEqual(binds, exp) {
+		t.Fatalf("unexpected binds: %q", binds)
+	} else if !reflect.DeepEqual(searches, exp) {
+		t.Fatalf("unexpected searches: %q", searches)
+	}
+
+	// Wrong and empty passwords fail.
+	if _, err := p.Authenticate("alice", "wrong"); err == nil {
+		t.Fatal("expected error")
+	} else if e, ok := err.(*ldap.Error); !ok || e.Code != ldap.ResultInvalidCredentials {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.Authenticate("alice", ""); err != ldap.ErrEmptyPassword {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The groups are not searched without admin groups, so the admin
+	// privilege is left alone.
+	config.AdminGroups = nil
+	p2, err := NewLDAPAuthProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p2.Close()
+	if u, err := p2.Authenticate("alice", "alice-pw"); err != nil {
+		t.Fatal(err)
+	} else if u.Admin != nil {
+		t.Fatalf("unexpected admin privilege: %v", *u.Admin)
+	}
+	s.mu.Lock()
+	n := len(s.searches)
+	s.mu.Unlock()
+	if n != 2 {
+		t.Fatalf("unexpected searches: %d", n)
+	}
+}
+
+func TestLDAPAuthProvider_BrokenConnection(t *testing.T) {
+	const dn = "uid=alice,dc=example,dc=com"
+	s := newLDAPTestServer(t, map[string]string{dn: "alice-pw"}, nil)
+
+	// The server closes the connections after each response, so the
+	// pooled connection is broken when it is reused.
+	s.closeConns = true
+
+	config := NewLDAPConfig()
+	config.Enabled = true
+	config.URL = s.URL()
+	config.BindDN = "uid={username},dc=example,dc=com"
+	p, err := NewLDAPAuthProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.Authenticate("alice", "alice-pw"); err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+	}
+
+	// Failed binds are not retried.
+	if _, err := p.Authenticate("alice", "wrong"); err == nil {
+		t.Fatal("expected error")
+	}
+	s.mu.Lock()
+	n := len(s.binds)
+	s.mu.Unlock()
+	if n != 4 {
+		t.Fatalf("unexpected binds: %d", n)
+	}
+}
+
+// testAuthProvider authenticates the users with the given passwords.
+type testAuthProvider map[string]*struct {
+	password string
+	user     ExternalUser
+}
+
+func (p testAuthProvider) Authenticate(username, password string) (*ExternalUser, error) {
+	if u, ok := p[username]; ok && u.password == password {
+		eu := u.user
+		return &eu, nil
+	}
+	return nil, errors.New("invalid credentials")
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	c := meta.NewClient(&meta.Config{Dir: t.TempDir()})
+	c.WithPasswordHasher(&meta.PasswordHasher{Algorithm: meta.PasswordHashBcrypt, BcryptCost: bcrypt.MinCost})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateUser("admin", "local-pw", true); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("carol", "carol-pw", false); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := true
+	p := testAuthProvider{
+		// The directory has a user named like the local admin.
+		"admin": {password: "ldap-pw", user: ExternalUser{Name: "admin", Admin: &admin, Create: true}},
+		"bob":   {password: "bob-pw", user: ExternalUser{Name: "bob", Admin: &admin, Create: true}},
+		"dave":  {password: "dave-pw", user: ExternalUser{Name: "dave"}},
+	}
+
+	for _, tt := range []struct {
+		username, password string
+		err                error
+		admin              bool
+	}{
+		// The directory can't authenticate local users, which still log
+		// in with their local password.
+		{username: "admin", password: "ldap-pw", err: meta.ErrAuthenticate},
+		{username: "admin", password: "local-pw", admin: true},
+		// Users unknown to the directory fall back to meta.
+		{username: "carol", password: "carol-pw"},
+		{username: "carol", password: "wrong", err: meta.ErrAuthenticate},
+		// Users known to the directory are created if the provider asks.
+		{username: "bob", password: "bob-pw", admin: true},
+		{username: "dave", password: "dave-pw", err: meta.ErrUserNotFound},
+	} {
+		u, err := authenticateUser(p, c, tt.username, tt.password)
+		if err != tt.err {
+			t.Fatalf("%s/%s: unexpected error: %v", tt.username, tt.password, err)
+		} else if err != nil {
+			continue
+		}
+		if u.ID() != tt.username {
+			t.Fatalf("%s: unexpected user: %s", tt.username, u.ID())
+		} else if ui := u.(*meta.UserInfo); ui.Admin != tt.admin {
+			t.Fatalf("%s: unexpected admin privilege: %v", tt.username, ui.Admin)
+		}
+	}
+
+	// The local admin is untouched.
+	if ui, err := c.User("admin"); err != nil {
+		t.Fatal(err)
+	} else if ui.(*meta.UserInfo).External {
+		t.Fatal("local user taken over by the directory")
+	}
+	if ui, err := c.User("bob"); err != nil {
+		t.Fatal(err)
+	} else if !ui.(*meta.UserInfo).External {
+		t.Fatal("directory user not marked as external")
+	}
+}
@@ -253,11 +253,26 @@ func (f *msgpackFormatter) WriteResponse(w io.Writer, resp Response) (err error)
 				_ = enc.WriteString("messages")
 				_ = enc.WriteArrayHeader(uint32(len(result.Messages)))
 				for _, msg := range result.Messages {
-					_ = enc.WriteMapHeader(2)
+					sz := 2
+					if msg.Code != "" {
+						sz++
+					}
+					if msg.Replacement != "" {
+						sz++
+					}
+					_ = enc.WriteMapHeader(uint32(sz))
 					_ = enc.WriteString("level")
 					_ = enc.WriteString(msg.Level)
 					_ = enc.WriteString("text")
 					_ = enc.WriteString(msg.Text)
+					if msg.Code != "" {
+						_ = enc.WriteString("code")
+						_ = enc.WriteString(msg.Code)
+					}
+					if msg.Replacement != "" {
+						_ = enc.WriteString("replacement")
+						_ = enc.WriteString(msg.Replacement)
+					}
 				}
 			}
 			_ = enc.WriteString("series")
@@ -14,9 +14,10 @@ type Config struct {
 	CheckInterval toml.Duration `toml:"check-interval"`
 }
 
-// NewConfig returns an instance of Config with defaults.
+// NewConfig returns an instance of Config with defaults. The service is
+// disabled by default since it deletes data.
 func NewConfig() Config {
-	return Config{Enabled: true, CheckInterval: toml.Duration(30 * time.Minute)}
+	return Config{Enabled: false, CheckInterval: toml.Duration(30 * time.Minute)}
 }
 
 // Validate returns an error if the Config is invalid.
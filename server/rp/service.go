@@ -2,6 +2,7 @@
 package rp
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -22,6 +23,12 @@ type Service struct {
 		DeleteShard(shardID uint64) error
 	}
 
+	// OperationLocks serializes shard removal with other destructive
+	// operations on the shard. Optional.
+	OperationLocks interface {
+		AcquireShard(shardID uint64, desc string) (release func(), err error)
+	}
+
 	config Config
 	wg     sync.WaitGroup
 	done   chan struct{}
@@ -93,18 +100,21 @@ func (s *Service) run() {
 			// Without the message, they may see the error message and assume they
 			// have to do it manually.
 			var retryNeeded bool
+			now := time.Now().UTC()
 			dbs := s.MetaClient.Databases()
 			for _, d := range dbs {
 				for _, r := range d.RetentionPolicies {
-					// Build list of already deleted shards.
-					for _, g := range r.DeletedShardGroups() {
+					// Build list of already deleted shards whose deletion delay
+					// has elapsed. Shards still within the delay are kept so
+					// their shard group can be restored.
+					for _, g := range r.PurgeableShardGroups(now) {
 						for _, sh := range g.Shards {
 							deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
 						}
 					}
 
 					// Determine all shards that have expired and need to be deleted.
-					for _, g := range r.ExpiredShardGroups(time.Now().UTC()) {
+					for _, g := range r.ExpiredShardGroups(now) {
 						if err := s.MetaClient.DeleteShardGroup(d.Name, r.Name, g.ID); err != nil {
 							log.Info("Failed to delete shard group",
 								logger.Database(d.Name),
@@ -120,6 +130,13 @@ func (s *Service) run() {
 							logger.ShardGroup(g.ID),
 							logger.RetentionPolicy(r.Name))
 
+						if r.DeletionDelay > 0 {
+							log.Info("Deferring shard removal",
+								logger.ShardGroup(g.ID),
+								logger.DurationLiteral("deletion_delay", r.DeletionDelay))
+							continue
+						}
+
 						// Store all the shard IDs that may possibly need to be removed locally.
 						for _, sh := range g.Shards {
 							deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
@@ -131,7 +148,7 @@ func (s *Service) run() {
 			// Remove shards if we store them locally
 			for _, id := range s.TSDBStore.ShardIDs() {
 				if info, ok := deletedShardIDs[id]; ok {
-					if err := s.TSDBStore.DeleteShard(id); err != nil {
+					if err := s.deleteShard(id); err != nil {
 						log.Info("Failed to delete shard",
 							logger.Database(info.db),
 							logger.Shard(id),
@@ -160,3 +177,15 @@ func (s *Service) run() {
 		}
 	}
 }
+
+// deleteShard removes a local shard, holding its operation lock if locking is enabled.
+func (s *Service) deleteShard(id uint64) error {
+	if s.OperationLocks != nil {
+		release, err := s.OperationLocks.AcquireShard(id, fmt.Sprintf("retention policy enforcement: delete shard %d", id))
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+	return s.TSDBStore.DeleteShard(id)
+}
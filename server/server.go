@@ -18,8 +18,10 @@ import (
 	"github.com/cnosdb/cnosdb/pkg/logger"
 	"github.com/cnosdb/cnosdb/pkg/network"
 	"github.com/cnosdb/cnosdb/pkg/utils"
+	"github.com/cnosdb/cnosdb/server/continuous_querier"
 	"github.com/cnosdb/cnosdb/server/coordinator"
 	"github.com/cnosdb/cnosdb/server/hh"
+	"github.com/cnosdb/cnosdb/server/rp"
 	"github.com/cnosdb/cnosdb/server/snapshotter"
 	"github.com/cnosdb/cnosdb/server/subscriber"
 	"github.com/cnosdb/cnosdb/vend/db/models"
@@ -50,7 +52,7 @@ type Server struct {
 	tcpMux       cmux.CMux
 	tcpListener  net.Listener
 
-	httpHandler http.Handler
+	httpHandler *Handler
 	httpServer  *http.Server
 
 	Node       *cnosdb.Node
@@ -58,12 +60,19 @@ type Server struct {
 	metaServer *meta.Server
 	metaClient meta.MetaClient
 
-	tsdbStore     *tsdb.Store
-	queryExecutor *query.Executor
-	pointsWriter  *coordinator.PointsWriter
-	shardWriter   *coordinator.ShardWriter
-	hintedHandoff *hh.Service
-	subscriber    *subscriber.Service
+	// Hashes passwords of new and migrated users.
+	passwordHasher *meta.PasswordHasher
+
+	tsdbStore      *tsdb.Store
+	queryExecutor  *query.Executor
+	pointsWriter   *coordinator.PointsWriter
+	operationLocks *coordinator.OperationLocks
+	auditLog       *coordinator.AuditLog
+	slowQueryLog   *coordinator.SlowQueryLog
+	ldapAuth       *LDAPAuthProvider
+	shardWriter    *coordinator.ShardWriter
+	hintedHandoff  *hh.Service
+	subscriber     *subscriber.Service
 
 	coordinatorService *coordinator.Service
 	snapshotterService *snapshotter.Service
@@ -137,6 +146,18 @@ func (s *Server) Close() {
 		_ = s.pointsWriter.Close()
 	}
 
+	if s.auditLog != nil {
+		_ = s.auditLog.Close()
+	}
+
+	if s.slowQueryLog != nil {
+		_ = s.slowQueryLog.Close()
+	}
+
+	if s.ldapAuth != nil {
+		_ = s.ldapAuth.Close()
+	}
+
 	if s.queryExecutor != nil {
 		_ = s.queryExecutor.Close()
 	}
@@ -185,6 +206,9 @@ func (s *Server) initTSDBStore() error {
 
 	s.tsdbStore.EngineOptions.EngineVersion = s.Config.Data.Engine
 	s.tsdbStore.EngineOptions.IndexVersion = s.Config.Data.Index
+	s.tsdbStore.ColdShardIDs = s.coldShardIDs
+	s.tsdbStore.DatabaseLimits = s.databaseLimits
+	s.tsdbStore.DatabaseIndexType = s.databaseIndexType
 
 	s.shardWriter = coordinator.NewShardWriter(time.Duration(s.Config.Coordinator.ShardWriterTimeout),
 		s.Config.Coordinator.MaxRemoteWriteConnections)
@@ -204,8 +228,14 @@ func (s *Server) initTSDBStore() error {
 	s.subscriber = subscriber.NewService(s.Config.Subscriber)
 	s.subscriber.MetaClient = s.metaClient
 
+	s.operationLocks = coordinator.NewOperationLocks()
+	s.operationLocks.Timeout = time.Duration(s.Config.Coordinator.OperationLockTimeout)
+	s.operationLocks.MetaClient = s.metaClient
+	s.monitor.RegisterDiagnosticsClient("operations", s.operationLocks)
+	s.monitor.RegisterDiagnosticsClient("password-hash", s.passwordHasher)
+
 	s.queryExecutor = query.NewExecutor()
-	s.queryExecutor.StatementExecutor = &coordinator.StatementExecutor{
+	statementExecutor := &coordinator.StatementExecutor{
 		MetaClient:  s.metaClient,
 		TaskManager: s.queryExecutor.TaskManager,
 		TSDBStore:   s.tsdbStore,
@@ -214,16 +244,78 @@ func (s *Server) initTSDBStore() error {
 			TSDBStore: coordinator.LocalTSDBStore{
 				Store: s.tsdbStore,
 			},
+			HintedHandoff: s.hintedHandoff,
 		},
 		Monitor:           s.monitor,
 		PointsWriter:      s.pointsWriter,
+		WriteStats:        s.pointsWriter,
 		MaxSelectPointN:   s.Config.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  s.Config.Coordinator.MaxSelectSeriesN,
 		MaxSelectBucketsN: s.Config.Coordinator.MaxSelectBucketsN,
+
+		MaxSelectMemoryBytes: int64(s.Config.Coordinator.MaxSelectMemory),
+
+		MaxConcurrentSelects: s.Config.Coordinator.MaxConcurrentSelects,
+		MaxQueuedSelects:     s.Config.Coordinator.MaxQueuedSelects,
+		MaxSelectQueueTime:   time.Duration(s.Config.Coordinator.MaxSelectQueueTime),
+		PreemptLowPriority:   s.Config.Coordinator.PreemptLowPriority,
+
+		IntoWriteBatchSize:   s.Config.Coordinator.IntoWriteBatchSize,
+		IntoFlushInterval:    time.Duration(s.Config.Coordinator.IntoFlushInterval),
+		IntoWriteConcurrency: s.Config.Coordinator.IntoWriteConcurrency,
+		IntoWriteRateLimit:   s.Config.Coordinator.IntoWriteRateLimit,
+
+		RuntimeStatsThreshold: time.Duration(s.Config.Coordinator.RuntimeStatsThreshold),
+		EscalatedNotices:      s.Config.Coordinator.EscalateNotices,
+		OperationLocks:        s.operationLocks,
+	}
+	if sinks := s.auditSinks(); len(sinks) > 0 {
+		s.auditLog = coordinator.NewAuditLog(sinks...)
+		s.auditLog.WithLogger(s.logger)
+		statementExecutor.AuditLog = s.auditLog
+	}
+	if c := s.Config.Coordinator; c.SlowQueryThreshold > 0 {
+		if sinks := s.slowQuerySinks(); len(sinks) > 0 {
+			s.slowQueryLog = coordinator.NewSlowQueryLog(time.Duration(c.SlowQueryThreshold), c.SlowQuerySampleN, sinks...)
+			s.slowQueryLog.WithLogger(s.logger)
+			statementExecutor.SlowQueryLog = s.slowQueryLog
+		}
 	}
+	s.queryExecutor.StatementExecutor = statementExecutor
+	s.monitor.RegisterDiagnosticsClient("coordinator", statementExecutor)
 	s.queryExecutor.TaskManager.QueryTimeout = time.Duration(s.Config.Coordinator.QueryTimeout)
 	s.queryExecutor.TaskManager.LogQueriesAfter = time.Duration(s.Config.Coordinator.LogQueriesAfter)
 	s.queryExecutor.TaskManager.MaxConcurrentQueries = s.Config.Coordinator.MaxConcurrentQueries
+	s.queryExecutor.TaskManager.RemoteQueryKiller = &coordinator.RemoteQueryKiller{
+		Dialer: &coordinator.NodeDialer{
+			MetaClient: s.metaClient,
+			Timeout:    time.Duration(s.Config.Coordinator.ShardMapperTimeout),
+		},
+	}
+
+	// The continuous query service also runs the downsampling of retention
+	// policies created with a DOWNSAMPLE clause.
+	if s.Config.ContinuousQuery.Enabled {
+		srv := continuous_querier.NewService(s.Config.ContinuousQuery)
+		srv.MetaClient = s.metaClient
+		srv.QueryExecutor = s.queryExecutor
+		srv.Monitor = s.monitor
+		srv.WithLogger(s.logger)
+		statementExecutor.ContinuousQueryRuns = srv
+		s.services = append(s.services, srv)
+	}
+
+	// The retention service drops expired shard groups and removes their
+	// shards once the retention policy's deletion delay has passed.
+	if s.Config.RetentionPolicy.Enabled {
+		srv := rp.NewService(s.Config.RetentionPolicy)
+		srv.MetaClient = s.metaClient
+		srv.TSDBStore = s.tsdbStore
+		srv.OperationLocks = s.operationLocks
+		srv.WithLogger(s.logger)
+		statementExecutor.DeferShardRemoval = true
+		s.services = append(s.services, srv)
+	}
 
 	s.coordinatorService = coordinator.NewService(s.Config.Coordinator)
 	s.coordinatorService.TSDBStore = s.tsdbStore
@@ -232,6 +324,7 @@ func (s *Server) initTSDBStore() error {
 	s.snapshotterService = snapshotter.NewService()
 	s.snapshotterService.TSDBStore = s.tsdbStore
 	s.snapshotterService.MetaClient = s.metaClient
+	s.snapshotterService.OperationLocks = s.operationLocks
 
 	// Open TSDB store.
 	if err := s.tsdbStore.Open(); err != nil {
@@ -262,6 +355,77 @@ func (s *Server) initTSDBStore() error {
 	return nil
 }
 
+// coldShardIDs returns the IDs of all shards in shard groups that have been
+// over for longer than the cold-after duration of their retention policy.
+func (s *Server) coldShardIDs(now time.Time) []uint64 {
+	var ids []uint64
+	for _, di := range s.metaClient.Databases() {
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ColdShardGroups(now) {
+				for _, sh := range sgi.Shards {
+					ids = append(ids, sh.ID)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// databaseLimits returns the write limits configured for a database.
+func (s *Server) databaseLimits(database string) tsdb.DatabaseLimits {
+	di := s.metaClient.Database(database)
+	if di == nil {
+		return tsdb.DatabaseLimits{}
+	}
+	return tsdb.DatabaseLimits{
+		MaxSeriesN:      di.MaxSeriesN,
+		MaxValuesPerTag: di.MaxValuesPerTag,
+	}
+}
+
+// databaseIndexType returns the index new shards of a database use, or an
+// empty string for the configured default.
+func (s *Server) databaseIndexType(database string) string {
+	if di := s.metaClient.Database(database); di != nil {
+		return di.IndexType
+	}
+	return ""
+}
+
+// auditSinks returns the audit sinks enabled in the coordinator config.
+func (s *Server) auditSinks() []coordinator.AuditSink {
+	c := s.Config.Coordinator
+	var sinks []coordinator.AuditSink
+	if c.AuditLogPath != "" {
+		sinks = append(sinks, coordinator.NewAuditFile(c.AuditLogPath, c.AuditLogMaxSize, c.AuditLogMaxBackups, c.AuditLogMaxAge))
+	}
+	if c.AuditDatabase != "" {
+		sinks = append(sinks, &coordinator.AuditPoints{
+			PointsWriter:    s.pointsWriter,
+			Database:        c.AuditDatabase,
+			RetentionPolicy: c.AuditRetentionPolicy,
+		})
+	}
+	return sinks
+}
+
+// slowQuerySinks returns the slow query sinks enabled in the coordinator config.
+func (s *Server) slowQuerySinks() []coordinator.SlowQuerySink {
+	c := s.Config.Coordinator
+	var sinks []coordinator.SlowQuerySink
+	if c.SlowQueryLogPath != "" {
+		sinks = append(sinks, coordinator.NewSlowQueryFile(c.SlowQueryLogPath, c.SlowQueryLogMaxSize, c.SlowQueryLogMaxBackups, c.SlowQueryLogMaxAge))
+	}
+	if c.SlowQueryDatabase != "" {
+		sinks = append(sinks, &coordinator.SlowQueryPoints{
+			PointsWriter:    s.pointsWriter,
+			Database:        c.SlowQueryDatabase,
+			RetentionPolicy: c.SlowQueryRetentionPolicy,
+		})
+	}
+	return sinks
+}
+
 func (s *Server) initHTTPServer() error {
 	ln, err := net.Listen("tcp", s.Config.HTTPD.BindAddress)
 	if err != nil {
@@ -272,6 +436,15 @@ func (s *Server) initHTTPServer() error {
 	s.httpMux = cmux.New(s.listener)
 	s.httpListener = s.httpMux.Match(cmux.HTTP1Fast())
 
+	if s.Config.HTTPD.LDAP.Enabled {
+		p, err := NewLDAPAuthProvider(s.Config.HTTPD.LDAP)
+		if err != nil {
+			return err
+		}
+		s.ldapAuth = p
+		s.Config.HTTPD.AuthProvider = p
+	}
+
 	h := NewHandler(&s.Config.HTTPD)
 	h.Version = "0.0.0"
 	h.metaClient = s.metaClient
@@ -316,12 +489,18 @@ func (s *Server) openServices() error {
 }
 
 func (s *Server) initMetaClient() error {
+	s.passwordHasher = meta.NewPasswordHasher(s.Config.Meta)
+
 	var metaCli meta.MetaClient
 	if s.Config.Cluster == false {
-		metaCli = meta.NewClient(s.Config.Meta)
+		c := meta.NewClient(s.Config.Meta)
+		c.WithPasswordHasher(s.passwordHasher)
+		metaCli = c
 	} else {
 		s.logger.Info("waiting to be added to cluster")
-		metaCli = meta.NewRemoteClient()
+		c := meta.NewRemoteClient()
+		c.WithPasswordHasher(s.passwordHasher)
+		metaCli = c
 		for {
 			if len(s.Node.Peers) == 0 {
 				time.Sleep(time.Second)
@@ -343,11 +522,38 @@ func (s *Server) initMetaClient() error {
 	// if the node ID is > 0 then we need to initialize the metaclient
 	if s.Node.ID > 0 {
 		s.metaClient.WaitForDataChanged()
+		if s.Config.Cluster {
+			s.syncNodeTags(s.metaClient, s.Node.ID)
+		}
 	}
 
 	return nil
 }
 
+// syncNodeTags stores the configured node-tags on the data node if they
+// differ from the tags in the meta store.
+func (s *Server) syncNodeTags(metaClient meta.MetaClient, id uint64) {
+	n, err := metaClient.DataNode(id)
+	if err != nil || n == nil {
+		return
+	}
+	if len(n.Tags) == len(s.Config.NodeTags) {
+		equal := true
+		for k, v := range s.Config.NodeTags {
+			if tv, ok := n.Tags[k]; !ok || tv != v {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return
+		}
+	}
+	if err := metaClient.SetDataNodeTags(id, s.Config.NodeTags); err != nil {
+		s.logger.Error("unable to set node tags", zap.Uint64("id", id), zap.Error(err))
+	}
+}
+
 func (s *Server) startHTTPServer() {
 	srv := http.NewServeMux()
 	srv.Handle("/", s.httpHandler)
@@ -358,7 +564,12 @@ func (s *Server) startHTTPServer() {
 	srv.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	srv.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
-	s.httpServer = &http.Server{Addr: s.Config.HTTPD.BindAddress, Handler: srv}
+	s.httpServer = &http.Server{
+		Addr:        s.Config.HTTPD.BindAddress,
+		Handler:     srv,
+		ConnContext: s.httpHandler.ConnContext,
+		ConnState:   s.httpHandler.ConnState,
+	}
 
 	go utils.WithRecovery(func() {
 		err := s.httpServer.Serve(s.httpListener)
@@ -446,6 +657,7 @@ func (s *Server) joinCluster(conn net.Conn, peers []string) {
 		time.Sleep(time.Second)
 		n, err = metaClient.CreateDataNode(s.HTTPAddr(), s.TCPAddr())
 	}
+	s.syncNodeTags(metaClient, n.ID)
 	metaClient.Close()
 
 	s.Node.ID = n.ID
@@ -485,6 +697,9 @@ func (s *Server) remoteAddr(addr string) string {
 func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 	var statistics []models.Statistic
 	statistics = append(statistics, s.queryExecutor.Statistics(tags)...)
+	if m, ok := s.queryExecutor.StatementExecutor.(monitor.Reporter); ok {
+		statistics = append(statistics, m.Statistics(tags)...)
+	}
 	statistics = append(statistics, s.tsdbStore.Statistics(tags)...)
 	statistics = append(statistics, s.pointsWriter.Statistics(tags)...)
 	for _, srv := range s.services {
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/cnosdb/cnosdb/meta"
+)
+
+// connContextKey is the key of the connection of a request in its context.
+type connContextKey struct{}
+
+// ConnContext stores the connection of each request in its context so the
+// request can be tracked in a query session. It is set on the http.Server.
+func (h *Handler) ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// ConnState removes closed connections from their query sessions. It is set
+// on the http.Server.
+func (h *Handler) ConnState(c net.Conn, state http.ConnState) {
+	if state != http.StateClosed && state != http.StateHijacked {
+		return
+	}
+	for _, id := range h.sessionConns.remove(c) {
+		h.QueryExecutor.TaskManager.CloseSessionConn(id, c)
+	}
+}
+
+// openSession adds the connection of r to the query session of user and
+// its remote host. It returns the ID of the session, or zero if the
+// connection isn't known.
+func (h *Handler) openSession(r *http.Request, user meta.User) uint64 {
+	c, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok || h.QueryExecutor == nil {
+		return 0
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	var username string
+	if user != nil {
+		username = user.ID()
+	}
+
+	s := h.QueryExecutor.TaskManager.OpenSession(host, username, c)
+	h.sessionConns.add(c, s.ID)
+	return s.ID
+}
+
+// sessionConns tracks the query sessions of each connection. A connection
+// carries the requests of a single session unless its requests authenticate
+// as different users.
+type sessionConns struct {
+	mu    sync.Mutex
+	conns map[net.Conn][]uint64
+}
+
+// add records that c is a connection of the session id.
+func (sc *sessionConns) add(c net.Conn, id uint64) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.conns == nil {
+		sc.conns = make(map[net.Conn][]uint64)
+	}
+	for _, other := range sc.conns[c] {
+		if other == id {
+			return
+		}
+	}
+	sc.conns[c] = append(sc.conns[c], id)
+}
+
+// remove forgets c and returns the sessions it was a connection of.
+func (sc *sessionConns) remove(c net.Conn) []uint64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	ids := sc.conns[c]
+	delete(sc.conns, c)
+	return ids
+}
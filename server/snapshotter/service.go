@@ -52,6 +52,12 @@ type Service struct {
 		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 	}
 
+	// OperationLocks serializes backup and restore of a shard with other
+	// destructive operations on it. Optional.
+	OperationLocks interface {
+		AcquireShard(shardID uint64, desc string) (release func(), err error)
+	}
+
 	Listener net.Listener
 	Logger   *zap.Logger
 }
@@ -134,6 +140,13 @@ func (s *Service) handleConn(conn net.Conn) error {
 
 	switch RequestType(typ[0]) {
 	case RequestShardBackup:
+		if s.OperationLocks != nil {
+			release, err := s.OperationLocks.AcquireShard(r.ShardID, fmt.Sprintf("backup shard %d", r.ShardID))
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
 		if err := s.TSDBStore.BackupShard(r.ShardID, r.Since, conn); err != nil {
 			return err
 		}
@@ -165,6 +178,14 @@ func (s *Service) updateShardsLive(conn net.Conn) error {
 	}
 	sid := binary.BigEndian.Uint64(sidBytes[:])
 
+	if s.OperationLocks != nil {
+		release, err := s.OperationLocks.AcquireShard(sid, fmt.Sprintf("restore shard %d", sid))
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
 	if err := s.TSDBStore.SetShardEnabled(sid, false); err != nil {
 		return err
 	}
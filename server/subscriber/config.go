@@ -16,6 +16,30 @@ const (
 	// DefaultHTTPTimeout is the default HTTP timeout for a Config.
 	DefaultHTTPTimeout = 30 * time.Second
 
+	// DefaultKafkaTimeout is the default Kafka request timeout for a Config.
+	DefaultKafkaTimeout = 30 * time.Second
+
+	// DefaultMQTTTimeout is the default MQTT connect and publish timeout for a Config.
+	DefaultMQTTTimeout = 30 * time.Second
+
+	// DefaultHealthCheckInterval is the default interval of destination
+	// health checks for a Config.
+	DefaultHealthCheckInterval = 10 * time.Second
+
+	// DefaultQueueMaxSize is the default maximum size in bytes of the retry
+	// queue of each subscription.
+	DefaultQueueMaxSize = 100 * 1024 * 1024
+
+	// DefaultQueueMaxAge is the default maximum age of a queued write.
+	DefaultQueueMaxAge = 24 * time.Hour
+
+	// DefaultRetryInterval is the default interval between retries of the
+	// queued writes. It doubles after each failure up to DefaultRetryMaxInterval.
+	DefaultRetryInterval = time.Second
+
+	// DefaultRetryMaxInterval is the default maximum interval between retries.
+	DefaultRetryMaxInterval = time.Minute
+
 	// DefaultWriteConcurrency is the default write concurrency for a Config.
 	DefaultWriteConcurrency = 40
 
@@ -30,6 +54,13 @@ type Config struct {
 
 	HTTPTimeout toml.Duration `toml:"http-timeout"`
 
+	// The timeout of requests to the brokers of kafka:// destinations.
+	KafkaTimeout toml.Duration `toml:"kafka-timeout"`
+
+	// The timeout of connecting and publishing to the brokers of mqtt:// and
+	// mqtts:// destinations.
+	MQTTTimeout toml.Duration `toml:"mqtt-timeout"`
+
 	// InsecureSkipVerify gets passed to the http client, if true, it will
 	// skip https certificate verification. Defaults to false
 	InsecureSkipVerify bool `toml:"insecure-skip-verify"`
@@ -38,6 +69,27 @@ type Config struct {
 	// empty string, the default system certs will be used
 	CaCerts string `toml:"ca-certs"`
 
+	// The interval at which the destinations of ANY and BALANCE mode
+	// subscriptions are health checked. Writes are only sent to healthy
+	// destinations. 0 disables the health checks.
+	HealthCheckInterval toml.Duration `toml:"health-check-interval"`
+
+	// The directory where the writes a subscription failed to deliver are
+	// queued for retry. If empty, failed writes are dropped.
+	QueueDir string `toml:"queue-dir"`
+
+	// The maximum size in bytes of the retry queue of each subscription.
+	// Failed writes are discarded when the queue is full.
+	QueueMaxSize int64 `toml:"queue-max-size"`
+
+	// The maximum age of a queued write before it is discarded.
+	QueueMaxAge toml.Duration `toml:"queue-max-age"`
+
+	// The interval between retries of queued writes, doubled after each
+	// failure up to retry-max-interval.
+	RetryInterval    toml.Duration `toml:"retry-interval"`
+	RetryMaxInterval toml.Duration `toml:"retry-max-interval"`
+
 	// The number of writer goroutines processing the write channel.
 	WriteConcurrency int `toml:"write-concurrency"`
 
@@ -51,12 +103,19 @@ type Config struct {
 // NewConfig returns a new instance of a subscriber config.
 func NewConfig() Config {
 	return Config{
-		Enabled:            true,
-		HTTPTimeout:        toml.Duration(DefaultHTTPTimeout),
-		InsecureSkipVerify: false,
-		CaCerts:            "",
-		WriteConcurrency:   DefaultWriteConcurrency,
-		WriteBufferSize:    DefaultWriteBufferSize,
+		Enabled:             true,
+		HTTPTimeout:         toml.Duration(DefaultHTTPTimeout),
+		KafkaTimeout:        toml.Duration(DefaultKafkaTimeout),
+		MQTTTimeout:         toml.Duration(DefaultMQTTTimeout),
+		InsecureSkipVerify:  false,
+		CaCerts:             "",
+		HealthCheckInterval: toml.Duration(DefaultHealthCheckInterval),
+		QueueMaxSize:        DefaultQueueMaxSize,
+		QueueMaxAge:         toml.Duration(DefaultQueueMaxAge),
+		RetryInterval:       toml.Duration(DefaultRetryInterval),
+		RetryMaxInterval:    toml.Duration(DefaultRetryMaxInterval),
+		WriteConcurrency:    DefaultWriteConcurrency,
+		WriteBufferSize:     DefaultWriteBufferSize,
 	}
 }
 
@@ -66,6 +125,14 @@ func (c Config) Validate() error {
 		return errors.New("http-timeout must be greater than 0")
 	}
 
+	if c.KafkaTimeout <= 0 {
+		return errors.New("kafka-timeout must be greater than 0")
+	}
+
+	if c.MQTTTimeout <= 0 {
+		return errors.New("mqtt-timeout must be greater than 0")
+	}
+
 	if c.CaCerts != "" && !fileExists(c.CaCerts) {
 		abspath, err := filepath.Abs(c.CaCerts)
 		if err != nil {
@@ -74,6 +141,25 @@ func (c Config) Validate() error {
 		return fmt.Errorf("ca-certs file %s does not exist", abspath)
 	}
 
+	if c.HealthCheckInterval < 0 {
+		return errors.New("health-check-interval must be greater than or equal to 0")
+	}
+
+	if c.QueueDir != "" {
+		if c.QueueMaxSize <= 0 {
+			return errors.New("queue-max-size must be greater than 0")
+		}
+		if c.QueueMaxAge <= 0 {
+			return errors.New("queue-max-age must be greater than 0")
+		}
+		if c.RetryInterval <= 0 {
+			return errors.New("retry-interval must be greater than 0")
+		}
+		if c.RetryMaxInterval < c.RetryInterval {
+			return errors.New("retry-max-interval must be greater than or equal to retry-interval")
+		}
+	}
+
 	if c.WriteBufferSize <= 0 {
 		return errors.New("write-buffer-size must be greater than 0")
 	}
@@ -99,9 +185,17 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	}
 
 	return diagnostics.RowFromMap(map[string]interface{}{
-		"enabled":           true,
-		"http-timeout":      c.HTTPTimeout,
-		"write-concurrency": c.WriteConcurrency,
-		"write-buffer-size": c.WriteBufferSize,
+		"enabled":               true,
+		"http-timeout":          c.HTTPTimeout,
+		"kafka-timeout":         c.KafkaTimeout,
+		"mqtt-timeout":          c.MQTTTimeout,
+		"health-check-interval": c.HealthCheckInterval,
+		"queue-dir":             c.QueueDir,
+		"queue-max-size":        c.QueueMaxSize,
+		"queue-max-age":         c.QueueMaxAge,
+		"retry-interval":        c.RetryInterval,
+		"retry-max-interval":    c.RetryMaxInterval,
+		"write-concurrency":     c.WriteConcurrency,
+		"write-buffer-size":     c.WriteBufferSize,
 	}), nil
 }
@@ -0,0 +1,54 @@
+package subscriber
+
+import (
+	"github.com/cnosdb/cnosdb/server/coordinator"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+)
+
+// filterPoints returns a copy of p with only the points matching filter, or
+// nil if no point matches. A regex filter matches measurement names, any
+// other filter is a condition on tags where _name refers to the measurement.
+func filterPoints(p *coordinator.WritePointsRequest, filter cnosql.Expr) *coordinator.WritePointsRequest {
+	var points []models.Point
+	for _, pt := range p.Points {
+		if matchPoint(pt, filter) {
+			points = append(points, pt)
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	} else if len(points) == len(p.Points) {
+		return p
+	}
+
+	return &coordinator.WritePointsRequest{
+		Database:         p.Database,
+		RetentionPolicy:  p.RetentionPolicy,
+		ConsistencyLevel: p.ConsistencyLevel,
+		Points:           points,
+	}
+}
+
+func matchPoint(pt models.Point, filter cnosql.Expr) bool {
+	if re, ok := filter.(*cnosql.RegexLiteral); ok {
+		return re.Val.Match(pt.Name())
+	}
+	eval := cnosql.ValuerEval{Valuer: pointValuer{pt: pt}}
+	b, _ := eval.Eval(filter).(bool)
+	return b
+}
+
+// pointValuer resolves the tags of a point. Missing tags are empty, the same
+// as when querying, so "host != 'a'" matches points without a host tag.
+type pointValuer struct {
+	pt models.Point
+}
+
+// Value returns the measurement name for _name and the tag value otherwise.
+func (v pointValuer) Value(key string) (interface{}, bool) {
+	if key == "_name" {
+		return string(v.pt.Name()), true
+	}
+	return v.pt.Tags().GetString(key), true
+}
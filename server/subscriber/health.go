@@ -0,0 +1,120 @@
+package subscriber
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/cnosdb/pkg/kafka"
+	"github.com/cnosdb/cnosdb/pkg/mqtt"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"go.uber.org/zap"
+)
+
+// healthCheck returns an error if a destination is unreachable.
+type healthCheck func() error
+
+// newHealthCheck returns the health check of the destination u: a HEAD
+// request for HTTP destinations and a TCP dial of the brokers for Kafka and
+// MQTT destinations. UDP destinations can't be checked and nil is returned.
+func (s *Service) newHealthCheck(u url.URL, tlsConfig *tls.Config) (healthCheck, error) {
+	switch u.Scheme {
+	case "http", "https":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if u.Scheme == "https" {
+			if tlsConfig == nil {
+				tlsConfig = new(tls.Config)
+			} else {
+				tlsConfig = tlsConfig.Clone()
+			}
+			tlsConfig.InsecureSkipVerify = s.conf.InsecureSkipVerify
+			transport.TLSClientConfig = tlsConfig
+		}
+		client := &http.Client{
+			Timeout:   time.Duration(s.conf.HTTPTimeout),
+			Transport: transport,
+		}
+		addr := u.String()
+		return func() error {
+			resp, err := client.Head(addr)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("health check of %s returned %s", cnosql.SanitizeURL(addr), resp.Status)
+			}
+			return nil
+		}, nil
+	case kafka.Scheme:
+		brokers, _, err := kafka.ParseURL(&u)
+		if err != nil {
+			return nil, err
+		}
+		timeout := time.Duration(s.conf.KafkaTimeout)
+		return func() error {
+			// A single reachable broker is enough to find the leaders.
+			var errs []string
+			for _, addr := range brokers {
+				conn, err := net.DialTimeout("tcp", addr, timeout)
+				if err == nil {
+					conn.Close()
+					return nil
+				}
+				errs = append(errs, err.Error())
+			}
+			return fmt.Errorf("no broker reachable: %s", strings.Join(errs, "; "))
+		}, nil
+	case mqtt.Scheme, mqtt.SchemeTLS:
+		opt, err := mqtt.ParseURL(&u)
+		if err != nil {
+			return nil, err
+		}
+		timeout := time.Duration(s.conf.MQTTTimeout)
+		return func() error {
+			conn, err := net.DialTimeout("tcp", opt.Addr, timeout)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// runHealthChecks checks the destinations of b every interval until b is
+// closed, so writes in ANY and BALANCE modes are only sent to healthy
+// destinations.
+func (b *balancewriter) runHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for i, check := range b.checks {
+			if check == nil {
+				continue
+			}
+			err := check()
+			if b.stats[i].setHealthy(err == nil) {
+				if err != nil {
+					b.logger.Warn("Subscription destination is unhealthy",
+						zap.String("destination", b.stats[i].dest), zap.Error(err))
+				} else {
+					b.logger.Info("Subscription destination is healthy again",
+						zap.String("destination", b.stats[i].dest))
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.closing:
+			return
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"io/ioutil"
+	"net/url"
 	"time"
 
 	"github.com/cnosdb/cnosdb/client"
@@ -21,6 +22,7 @@ func NewHTTP(addr string, timeout time.Duration) (*HTTP, error) {
 }
 
 // NewHTTPS returns a new HTTPS points writer with default options and HTTPS configured.
+// Credentials in addr are sent using basic authentication.
 func NewHTTPS(addr string, timeout time.Duration, unsafeSsl bool, caCerts string, tlsConfig *tls.Config) (*HTTP, error) {
 	tlsConfig, err := createTLSConfig(caCerts, tlsConfig)
 	if err != nil {
@@ -33,6 +35,12 @@ func NewHTTPS(addr string, timeout time.Duration, unsafeSsl bool, caCerts string
 		InsecureSkipVerify: unsafeSsl,
 		TLSConfig:          tlsConfig,
 	}
+	if u, err := url.Parse(addr); err == nil && u.User != nil {
+		conf.Username = u.User.Username()
+		conf.Password, _ = u.User.Password()
+		u.User = nil
+		conf.Addr = u.String()
+	}
 
 	c, err := client.NewHTTPClient(conf)
 	if err != nil {
@@ -0,0 +1,43 @@
+package subscriber
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/cnosdb/cnosdb/pkg/kafka"
+	"github.com/cnosdb/cnosdb/server/coordinator"
+)
+
+// Kafka supports writing points to a Kafka topic using the line protocol.
+// Each point is written as one message keyed by its series key, so the points
+// of a series stay ordered within a partition.
+type Kafka struct {
+	p *kafka.Producer
+}
+
+// NewKafka returns a new Kafka points writer for a destination of the form
+// kafka://host1:port1[,host2:port2...]/topic.
+func NewKafka(u url.URL, timeout time.Duration) (*Kafka, error) {
+	brokers, topic, err := kafka.ParseURL(&u)
+	if err != nil {
+		return nil, err
+	}
+	return &Kafka{p: kafka.NewProducer(brokers, topic, timeout)}, nil
+}
+
+// WritePoints writes points to the topic as a single batch.
+func (k *Kafka) WritePoints(p *coordinator.WritePointsRequest) error {
+	msgs := make([]kafka.Message, 0, len(p.Points))
+	for _, pt := range p.Points {
+		msgs = append(msgs, kafka.Message{
+			Key:   pt.Key(),
+			Value: pt.AppendString(nil),
+		})
+	}
+	return k.p.Produce(msgs)
+}
+
+// Close closes the connections to the brokers.
+func (k *Kafka) Close() error {
+	return k.p.Close()
+}
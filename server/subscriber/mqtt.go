@@ -0,0 +1,48 @@
+package subscriber
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+
+	"github.com/cnosdb/cnosdb/pkg/mqtt"
+	"github.com/cnosdb/cnosdb/server/coordinator"
+)
+
+// MQTT supports writing points to an MQTT topic using the line protocol.
+// Each write is published as one message holding its points separated by
+// newlines.
+type MQTT struct {
+	p *mqtt.Publisher
+}
+
+// NewMQTT returns a new MQTT points writer for a destination of the form
+// mqtt[s]://[user:password@]host[:port]/topic[?qos=0|1][&client_id=id].
+// tlsConfig is used by mqtts destinations.
+func NewMQTT(u url.URL, timeout time.Duration, tlsConfig *tls.Config) (*MQTT, error) {
+	opt, err := mqtt.ParseURL(&u)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTT{p: mqtt.NewPublisher(opt, tlsConfig, timeout)}, nil
+}
+
+// WritePoints publishes points to the topic as a single message.
+func (m *MQTT) WritePoints(p *coordinator.WritePointsRequest) error {
+	if len(p.Points) == 0 {
+		return nil
+	}
+	var b []byte
+	for i, pt := range p.Points {
+		if i > 0 {
+			b = append(b, '\n')
+		}
+		b = pt.AppendString(b)
+	}
+	return m.p.Publish(b)
+}
+
+// Close disconnects from the broker.
+func (m *MQTT) Close() error {
+	return m.p.Close()
+}
@@ -0,0 +1,246 @@
+package subscriber
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdb/cnosdb/server/coordinator"
+	"github.com/cnosdb/cnosdb/server/hh"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"go.uber.org/zap"
+)
+
+// Statistics for the retry queue of a subscription.
+const (
+	statQueueBytes      = "queueBytes"      // Size of the queue on disk.
+	statQueueOldestAge  = "oldestAgeNs"     // Age of the oldest queued write in ns, 0 if the queue is empty.
+	statPointsQueued    = "pointsQueued"    // Points appended to the queue after a failed write.
+	statPointsRetried   = "pointsRetried"   // Points written from the queue.
+	statPointsDiscarded = "pointsDiscarded" // Points dropped because the queue was full or they were too old.
+)
+
+// retryQueue keeps the writes a subscription failed to deliver on disk and
+// retries them with exponential backoff until they are written or older
+// than the maximum age.
+//
+// In ALL mode a retried write is sent to every destination again, including
+// the ones that accepted it the first time.
+type retryQueue struct {
+	queue *hh.Queue
+	dir   string
+	pw    PointsWriter
+
+	maxAge           time.Duration
+	retryInterval    time.Duration
+	retryMaxInterval time.Duration
+
+	pointsQueued    int64
+	pointsRetried   int64
+	pointsDiscarded int64
+
+	// Set when the subscription was dropped, so its data is removed.
+	remove int32
+
+	closing chan struct{}
+	wg      sync.WaitGroup
+	logger  *zap.Logger
+}
+
+// newRetryQueue opens the queue in dir and starts retrying its writes to pw.
+// Writes queued before a restart are retried as well.
+func newRetryQueue(dir string, c Config, pw PointsWriter, logger *zap.Logger) (*retryQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	queue, err := hh.NewQueue(dir, c.QueueMaxSize)
+	if err != nil {
+		return nil, err
+	} else if err := queue.Open(); err != nil {
+		return nil, err
+	}
+
+	r := &retryQueue{
+		queue:            queue,
+		dir:              dir,
+		pw:               pw,
+		maxAge:           time.Duration(c.QueueMaxAge),
+		retryInterval:    time.Duration(c.RetryInterval),
+		retryMaxInterval: time.Duration(c.RetryMaxInterval),
+		closing:          make(chan struct{}),
+		logger:           logger,
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run()
+	}()
+	return r, nil
+}
+
+// Enqueue appends a write that failed to the queue. The points are discarded
+// if the queue is full.
+func (r *retryQueue) Enqueue(p *coordinator.WritePointsRequest) error {
+	if err := r.queue.Append(marshalRequest(time.Now(), p)); err != nil {
+		atomic.AddInt64(&r.pointsDiscarded, int64(len(p.Points)))
+		return err
+	}
+	atomic.AddInt64(&r.pointsQueued, int64(len(p.Points)))
+	return nil
+}
+
+// run retries the queued writes, doubling the interval between attempts
+// while they fail.
+func (r *retryQueue) run() {
+	interval := r.retryInterval
+	for {
+		select {
+		case <-r.closing:
+			return
+		case <-time.After(interval):
+		}
+
+		if err := r.flush(); err != nil {
+			interval *= 2
+			if interval > r.retryMaxInterval {
+				interval = r.retryMaxInterval
+			}
+			continue
+		}
+		interval = r.retryInterval
+	}
+}
+
+// flush writes the queued writes in order until the queue is empty or a write
+// fails. Writes older than the maximum age are discarded.
+func (r *retryQueue) flush() error {
+	for {
+		select {
+		case <-r.closing:
+			return nil
+		default:
+		}
+
+		b, err := r.queue.Current()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		queued, p, err := unmarshalRequest(b)
+		if err != nil {
+			// A corrupt entry can never be written, so skip it.
+			r.logger.Info("Discarding unreadable queued write", zap.Error(err))
+		} else if time.Since(queued) > r.maxAge {
+			atomic.AddInt64(&r.pointsDiscarded, int64(len(p.Points)))
+		} else if err := r.pw.WritePoints(p); err != nil {
+			return err
+		} else {
+			atomic.AddInt64(&r.pointsRetried, int64(len(p.Points)))
+		}
+
+		if err := r.queue.Advance(); err != nil {
+			return err
+		}
+	}
+}
+
+// oldestAge returns the age of the write at the head of the queue, or 0 if
+// the queue is empty.
+func (r *retryQueue) oldestAge() time.Duration {
+	b, err := r.queue.Current()
+	if err != nil || len(b) < 8 {
+		return 0
+	}
+	return time.Since(time.Unix(0, int64(binary.BigEndian.Uint64(b))))
+}
+
+// RemoveOnClose removes the data of the queue when it is closed, instead of
+// keeping it to be retried after a restart.
+func (r *retryQueue) RemoveOnClose() {
+	atomic.StoreInt32(&r.remove, 1)
+}
+
+// Close stops the retries and closes the queue.
+func (r *retryQueue) Close() error {
+	close(r.closing)
+	r.wg.Wait()
+	if err := r.queue.Close(); err != nil {
+		return err
+	}
+	if atomic.LoadInt32(&r.remove) == 1 {
+		return r.queue.Remove()
+	}
+	return nil
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (r *retryQueue) Statistics(tags models.StatisticTags) models.Statistic {
+	return models.Statistic{
+		Name: "subscriber_queue",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statQueueBytes:      r.queue.DiskUsage(),
+			statQueueOldestAge:  int64(r.oldestAge()),
+			statPointsQueued:    atomic.LoadInt64(&r.pointsQueued),
+			statPointsRetried:   atomic.LoadInt64(&r.pointsRetried),
+			statPointsDiscarded: atomic.LoadInt64(&r.pointsDiscarded),
+		},
+	}
+}
+
+// marshalRequest encodes the time a write was queued, its database and
+// retention policy followed by its points in line protocol.
+func marshalRequest(queued time.Time, p *coordinator.WritePointsRequest) []byte {
+	b := make([]byte, 8, 8+4+len(p.Database)+len(p.RetentionPolicy))
+	binary.BigEndian.PutUint64(b, uint64(queued.UnixNano()))
+	b = appendString(b, p.Database)
+	b = appendString(b, p.RetentionPolicy)
+	for _, pt := range p.Points {
+		b = pt.AppendString(b)
+		b = append(b, '\n')
+	}
+	return b
+}
+
+func unmarshalRequest(b []byte) (time.Time, *coordinator.WritePointsRequest, error) {
+	if len(b) < 8 {
+		return time.Time{}, nil, fmt.Errorf("too short: len = %d", len(b))
+	}
+	queued := time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+	b = b[8:]
+
+	p := &coordinator.WritePointsRequest{}
+	var err error
+	if p.Database, b, err = readString(b); err != nil {
+		return time.Time{}, nil, err
+	} else if p.RetentionPolicy, b, err = readString(b); err != nil {
+		return time.Time{}, nil, err
+	}
+	if p.Points, err = models.ParsePoints(b); err != nil {
+		return time.Time{}, nil, err
+	}
+	return queued, p, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	var n [2]byte
+	binary.BigEndian.PutUint16(n[:], uint16(len(s)))
+	return append(append(b, n[:]...), s...)
+}
+
+func readString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, fmt.Errorf("too short: len = %d", len(b))
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, fmt.Errorf("too short: len = %d, expected %d", len(b), 2+n)
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
@@ -0,0 +1,294 @@
+package subscriber
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/server/coordinator"
+	"github.com/cnosdb/cnosdb/server/hh"
+	"github.com/cnosdb/cnosdb/vend/common/pkg/toml"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"go.uber.org/zap"
+)
+
+// testPointsWriter records the writes it receives and fails them while err
+// is set.
+type testPointsWriter struct {
+	mu     sync.Mutex
+	err    error
+	writes []*coordinator.WritePointsRequest
+	failed int
+}
+
+func (w *testPointsWriter) WritePoints(p *coordinator.WritePointsRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		w.failed++
+		return w.err
+	}
+	w.writes = append(w.writes, p)
+	return nil
+}
+
+// SetErr makes the following writes fail with err, or succeed if err is nil.
+func (w *testPointsWriter) SetErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+}
+
+// Writes returns the points of the successful writes, one string per write.
+func (w *testPointsWriter) Writes() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var a []string
+	for _, p := range w.writes {
+		var b []byte
+		for _, pt := range p.Points {
+			b = append(pt.AppendString(b), '\n')
+		}
+		a = append(a, p.Database+"/"+p.RetentionPolicy+": "+string(b))
+	}
+	return a
+}
+
+// Failed returns the number of failed writes.
+func (w *testPointsWriter) Failed() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.failed
+}
+
+// mustWriteRequest returns a write of the points in line protocol.
+func mustWriteRequest(tb testing.TB, db, rp, data string) *coordinator.WritePointsRequest {
+	tb.Helper()
+	points, err := models.ParsePointsString(data)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return &coordinator.WritePointsRequest{Database: db, RetentionPolicy: rp, Points: points}
+}
+
+// newTestQueueConfig returns a config whose retries don't happen during the
+// tests unless they wait for them.
+func newTestQueueConfig() Config {
+	c := NewConfig()
+	c.RetryInterval = toml.Duration(time.Hour)
+	c.RetryMaxInterval = toml.Duration(time.Hour)
+	return c
+}
+
+func mustOpenRetryQueue(tb testing.TB, dir string, c Config, pw PointsWriter) *retryQueue {
+	tb.Helper()
+	r, err := newRetryQueue(dir, c, pw, zap.NewNop())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
+
+// queueStats returns the counters of the queue statistics.
+func queueStats(r *retryQueue) (queued, retried, discarded int64) {
+	v := r.Statistics(nil).Values
+	return v[statPointsQueued].(int64), v[statPointsRetried].(int64), v[statPointsDiscarded].(int64)
+}
+
+func TestRetryQueue_Replay(t *testing.T) {
+	dir := t.TempDir()
+	pw := &testPointsWriter{}
+	r := mustOpenRetryQueue(t, dir, newTestQueueConfig(), pw)
+
+	if err := r.Enqueue(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10\ncpu value=2 20")); err != nil {
+		t.Fatal(err)
+	} else if err := r.Enqueue(mustWriteRequest(t, "db1", "", "mem value=3 30")); err != nil {
+		t.Fatal(err)
+	}
+	v := r.Statistics(nil).Values
+	if v[statQueueBytes].(int64) == 0 || v[statQueueOldestAge].(int64) == 0 {
+		t.Fatalf("unexpected statistics: %v", v)
+	}
+
+	// The queued writes are kept across a restart.
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	c := newTestQueueConfig()
+	c.RetryInterval = toml.Duration(time.Millisecond)
+	c.RetryMaxInterval = toml.Duration(time.Millisecond)
+	r = mustOpenRetryQueue(t, dir, c, pw)
+	defer r.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pw.Writes()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	exp := []string{"db0/rp0: cpu value=1 10\ncpu value=2 20\n", "db1/: mem value=3 30\n"}
+	if got := pw.Writes(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected writes: %q", got)
+	}
+	if queued, retried, discarded := queueStats(r); queued != 0 || retried != 3 || discarded != 0 {
+		t.Fatalf("unexpected counters: queued=%d, retried=%d, discarded=%d", queued, retried, discarded)
+	} else if age := r.oldestAge(); age != 0 {
+		t.Fatalf("unexpected oldest age: %s", age)
+	}
+}
+
+func TestRetryQueue_Flush(t *testing.T) {
+	pw := &testPointsWriter{}
+	r := mustOpenRetryQueue(t, t.TempDir(), newTestQueueConfig(), pw)
+	defer r.Close()
+
+	for _, data := range []string{"cpu value=1 10", "cpu value=2 20"} {
+		if err := r.Enqueue(mustWriteRequest(t, "db0", "rp0", data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A failed write stops the flush and stays at the head of the queue.
+	errWrite := errors.New("write failed")
+	pw.SetErr(errWrite)
+	if err := r.flush(); err != errWrite {
+		t.Fatalf("unexpected error: %v", err)
+	} else if pw.Failed() != 1 {
+		t.Fatalf("unexpected failed writes: %d", pw.Failed())
+	}
+
+	pw.SetErr(nil)
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"db0/rp0: cpu value=1 10\n", "db0/rp0: cpu value=2 20\n"}; !reflect.DeepEqual(pw.Writes(), exp) {
+		t.Fatalf("unexpected writes: %q", pw.Writes())
+	}
+	if queued, retried, discarded := queueStats(r); queued != 2 || retried != 2 || discarded != 0 {
+		t.Fatalf("unexpected counters: queued=%d, retried=%d, discarded=%d", queued, retried, discarded)
+	}
+}
+
+func TestRetryQueue_MaxSize(t *testing.T) {
+	c := newTestQueueConfig()
+	c.QueueMaxSize = 100
+	r := mustOpenRetryQueue(t, t.TempDir(), c, &testPointsWriter{})
+	defer r.Close()
+
+	// The queue holds the first write, but not the second one.
+	if err := r.Enqueue(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Enqueue(mustWriteRequest(t, "db0", "rp0", "cpu value=2 20\ncpu value=3 30\ncpu value=4 40")); err != hh.ErrQueueFull {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued, retried, discarded := queueStats(r); queued != 1 || retried != 0 || discarded != 3 {
+		t.Fatalf("unexpected counters: queued=%d, retried=%d, discarded=%d", queued, retried, discarded)
+	}
+}
+
+func TestRetryQueue_MaxAge(t *testing.T) {
+	c := newTestQueueConfig()
+	c.QueueMaxAge = toml.Duration(time.Hour)
+	pw := &testPointsWriter{}
+	r := mustOpenRetryQueue(t, t.TempDir(), c, pw)
+	defer r.Close()
+
+	// Writes queued before the maximum age are discarded, the others are
+	// written.
+	now := time.Now()
+	for _, tt := range []struct {
+		queued time.Time
+		data   string
+	}{
+		{queued: now.Add(-2 * time.Hour), data: "cpu value=1 10\ncpu value=2 20"},
+		{queued: now.Add(-time.Minute), data: "cpu value=3 30"},
+	} {
+		if err := r.queue.Append(marshalRequest(tt.queued, mustWriteRequest(t, "db0", "rp0", tt.data))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if age := r.oldestAge(); age < 2*time.Hour {
+		t.Fatalf("unexpected oldest age: %s", age)
+	}
+
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"db0/rp0: cpu value=3 30\n"}; !reflect.DeepEqual(pw.Writes(), exp) {
+		t.Fatalf("unexpected writes: %q", pw.Writes())
+	}
+	if _, retried, discarded := queueStats(r); retried != 1 || discarded != 2 {
+		t.Fatalf("unexpected counters: retried=%d, discarded=%d", retried, discarded)
+	}
+}
+
+func TestRetryQueue_CorruptRecord(t *testing.T) {
+	pw := &testPointsWriter{}
+	r := mustOpenRetryQueue(t, t.TempDir(), newTestQueueConfig(), pw)
+	defer r.Close()
+
+	// Unreadable records are skipped, and the writes after them are still
+	// written.
+	valid := marshalRequest(time.Now(), mustWriteRequest(t, "db0", "rp0", "cpu value=1 10"))
+	for _, b := range [][]byte{
+		valid[:4],
+		valid[:9],
+		append(valid[:len(valid):len(valid)], "cpu value="...),
+		valid,
+	} {
+		if err := r.queue.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"db0/rp0: cpu value=1 10\n"}; !reflect.DeepEqual(pw.Writes(), exp) {
+		t.Fatalf("unexpected writes: %q", pw.Writes())
+	}
+	if _, retried, discarded := queueStats(r); retried != 1 || discarded != 0 {
+		t.Fatalf("unexpected counters: retried=%d, discarded=%d", retried, discarded)
+	}
+}
+
+func TestUnmarshalRequest(t *testing.T) {
+	queued := time.Unix(0, 1234567890)
+	b := marshalRequest(queued, mustWriteRequest(t, "db0", "rp0", "cpu,host=a value=1 10"))
+
+	tm, p, err := unmarshalRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	} else if !tm.Equal(queued) {
+		t.Fatalf("unexpected queue time: %s", tm)
+	} else if p.Database != "db0" || p.RetentionPolicy != "rp0" || len(p.Points) != 1 || p.Points[0].String() != "cpu,host=a value=1 10" {
+		t.Fatalf("unexpected request: %+v", p)
+	}
+
+	// Records truncated before the points fail.
+	for n := 0; n < 8+2+3+2+3; n++ {
+		if _, _, err := unmarshalRequest(b[:n]); err == nil {
+			t.Fatalf("%d: expected error", n)
+		}
+	}
+}
+
+func TestRetryQueue_RemoveOnClose(t *testing.T) {
+	dir := t.TempDir()
+	r := mustOpenRetryQueue(t, dir, newTestQueueConfig(), &testPointsWriter{})
+	if err := r.Enqueue(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+		t.Fatal(err)
+	}
+	r.RemoveOnClose()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue of a dropped subscription starts empty.
+	pw := &testPointsWriter{}
+	r = mustOpenRetryQueue(t, dir, newTestQueueConfig(), pw)
+	defer r.Close()
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	} else if len(pw.Writes()) != 0 {
+		t.Fatalf("unexpected writes: %q", pw.Writes())
+	}
+}
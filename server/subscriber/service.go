@@ -1,16 +1,22 @@
 package subscriber
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cnosdb/cnosdb/meta"
 	"github.com/cnosdb/cnosdb/monitor"
+	"github.com/cnosdb/cnosdb/pkg/kafka"
+	"github.com/cnosdb/cnosdb/pkg/mqtt"
 	"github.com/cnosdb/cnosdb/server/coordinator"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/logger"
 	"github.com/cnosdb/cnosdb/vend/db/models"
 	"go.uber.org/zap"
@@ -21,6 +27,12 @@ const (
 	statCreateFailures = "createFailures"
 	statPointsWritten  = "pointsWritten"
 	statWriteFailures  = "writeFailures"
+	statLastError      = "lastError"     // Most recent write error, empty if none.
+	statLastErrorTime  = "lastErrorTime" // Time of the most recent write error in ns, 0 if none.
+
+	statHealthy              = "healthy"              // Whether the last health check of the destination passed.
+	statHealthyTransitions   = "healthyTransitions"   // Times the destination recovered.
+	statUnhealthyTransitions = "unhealthyTransitions" // Times the destination failed its health check.
 )
 
 // PointsWriter is an interface for writing points to a subscription destination.
@@ -45,7 +57,7 @@ type Service struct {
 		Databases() []meta.DatabaseInfo
 		WaitForDataChanged() chan struct{}
 	}
-	NewPointsWriter func(u url.URL) (PointsWriter, error)
+	NewPointsWriter func(u url.URL, tlsConfig *tls.Config) (PointsWriter, error)
 	Logger          *zap.Logger
 	update          chan struct{}
 	stats           *Statistics
@@ -183,43 +195,85 @@ func (s *Service) Update() error {
 	}
 }
 
-func (s *Service) createSubscription(se subEntry, mode string, destinations []string) (PointsWriter, error) {
+func (s *Service) createSubscription(se subEntry, si meta.SubscriptionInfo) (PointsWriter, error) {
+	mode, destinations := si.Mode, si.Destinations
+
 	var bm BalanceMode
 	switch mode {
 	case "ALL":
 		bm = ALL
 	case "ANY":
 		bm = ANY
+	case "BALANCE":
+		bm = BALANCE
+		if len(si.Weights) != len(destinations) {
+			return nil, fmt.Errorf("got %d weights for %d destinations", len(si.Weights), len(destinations))
+		}
 	default:
 		return nil, fmt.Errorf("unknown balance mode %q", mode)
 	}
+	// Health checks let ANY and BALANCE modes skip destinations that are down.
+	checkHealth := bm != ALL && s.conf.HealthCheckInterval > 0
+
+	tlsConfig, err := s.subscriptionTLSConfig(si.TLS)
+	if err != nil {
+		return nil, err
+	}
+
 	writers := make([]PointsWriter, 0, len(destinations))
-	stats := make([]writerStats, 0, len(destinations))
+	stats := make([]*writerStats, 0, len(destinations))
+	checks := make([]healthCheck, 0, len(destinations))
 	// add only valid destinations
 	for _, dest := range destinations {
+		// Credentials in the destination must not be logged or reported.
+		redacted := cnosql.SanitizeURL(dest)
 		u, err := url.Parse(dest)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse destination: %s", dest)
+			closeWriters(writers)
+			return nil, fmt.Errorf("failed to parse destination: %s", redacted)
 		}
-		w, err := s.NewPointsWriter(*u)
+		w, err := s.NewPointsWriter(*u, tlsConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create writer for destination: %s", dest)
+			closeWriters(writers)
+			return nil, fmt.Errorf("failed to create writer for destination: %s", redacted)
 		}
 		writers = append(writers, w)
-		stats = append(stats, writerStats{dest: dest})
+		stats = append(stats, &writerStats{dest: redacted})
+
+		var check healthCheck
+		if checkHealth {
+			if check, err = s.newHealthCheck(*u, tlsConfig); err != nil {
+				closeWriters(writers)
+				return nil, fmt.Errorf("failed to create health check for destination: %s", redacted)
+			}
+		}
+		checks = append(checks, check)
 	}
 
-	return &balancewriter{
+	b := &balancewriter{
 		bm:      bm,
 		writers: writers,
 		stats:   stats,
+		checks:  checks,
+		weights: si.Weights,
+		current: make([]int, len(si.Weights)),
+		closing: make(chan struct{}),
+		logger:  s.Logger.With(logger.Database(se.db), logger.RetentionPolicy(se.rp), zap.String("subscription", se.name)),
 		defaultTags: models.StatisticTags{
 			"database":     se.db,
 			"time_to_live": se.rp,
 			"name":         se.name,
 			"mode":         mode,
 		},
-	}, nil
+	}
+	if checkHealth {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.runHealthChecks(time.Duration(s.conf.HealthCheckInterval))
+		}()
+	}
+	return b, nil
 }
 
 // Points returns a channel into which write point requests can be sent.
@@ -230,8 +284,7 @@ func (s *Service) Points() chan<- *coordinator.WritePointsRequest {
 // run read points from the points channel and writes them to the subscriptions.
 func (s *Service) run() {
 	var wg sync.WaitGroup
-	s.subs = make(map[subEntry]chanWriter)
-	// Perform initial update
+	// Perform initial update, which creates s.subs under subMu.
 	s.updateSubs(&wg)
 	for {
 		select {
@@ -245,8 +298,14 @@ func (s *Service) run() {
 			}
 			for se, cw := range s.subs {
 				if p.Database == se.db && p.RetentionPolicy == se.rp {
+					wr := p
+					if cw.filter != nil {
+						if wr = filterPoints(p, cw.filter); wr == nil {
+							continue
+						}
+					}
 					select {
-					case cw.writeRequests <- p:
+					case cw.writeRequests <- wr:
 					default:
 						atomic.AddInt64(&s.stats.WriteFailures, 1)
 					}
@@ -292,26 +351,64 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 				if _, ok := s.subs[se]; ok {
 					continue
 				}
-				sub, err := s.createSubscription(se, si.Mode, si.Destinations)
+				var filter cnosql.Expr
+				if si.Filter != "" {
+					var err error
+					if filter, err = cnosql.ParseSubscriptionFilter(si.Filter); err != nil {
+						atomic.AddInt64(&s.stats.CreateFailures, 1)
+						s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
+						continue
+					}
+				}
+				sub, err := s.createSubscription(se, si)
 				if err != nil {
 					atomic.AddInt64(&s.stats.CreateFailures, 1)
 					s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
 					continue
 				}
+				var queue *retryQueue
+				if s.conf.QueueDir != "" {
+					dir := filepath.Join(s.conf.QueueDir, url.PathEscape(se.db), url.PathEscape(se.rp), url.PathEscape(se.name))
+					if queue, err = newRetryQueue(dir, s.conf, sub, s.Logger); err != nil {
+						if c, ok := sub.(io.Closer); ok {
+							c.Close()
+						}
+						atomic.AddInt64(&s.stats.CreateFailures, 1)
+						s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
+						continue
+					}
+				}
 				cw := chanWriter{
 					writeRequests: make(chan *coordinator.WritePointsRequest, s.conf.WriteBufferSize),
 					pw:            sub,
+					filter:        filter,
+					queue:         queue,
+					queueTags:     models.StatisticTags{"database": se.db, "time_to_live": se.rp, "name": se.name},
 					pointsWritten: &s.stats.PointsWritten,
 					failures:      &s.stats.WriteFailures,
 					logger:        s.Logger,
 				}
+				var workers sync.WaitGroup
 				for i := 0; i < s.conf.WriteConcurrency; i++ {
-					wg.Add(1)
+					workers.Add(1)
 					go func() {
-						defer wg.Done()
+						defer workers.Done()
 						cw.Run()
 					}()
 				}
+				// Release the destinations once the chanWriter is closed
+				// and its pending writes are done.
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					workers.Wait()
+					if cw.queue != nil {
+						cw.queue.Close()
+					}
+					if c, ok := cw.pw.(io.Closer); ok {
+						c.Close()
+					}
+				}()
 				s.subs[se] = cw
 				s.Logger.Info("Added new subscription",
 					logger.Database(se.db),
@@ -323,7 +420,10 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	// Remove deleted subs
 	for se := range s.subs {
 		if !allEntries[se] {
-			// Close the chanWriter
+			// Close the chanWriter, dropping the writes it could not deliver.
+			if q := s.subs[se].queue; q != nil {
+				q.RemoveOnClose()
+			}
 			s.subs[se].Close()
 
 			// Remove it from the set
@@ -335,8 +435,35 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	}
 }
 
-// newPointsWriter returns a new PointsWriter from the given URL.
-func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
+// subscriptionTLSConfig returns the TLS config of the HTTPS destinations of a
+// subscription: the service's config with the subscription's CA and client
+// certificate, if any.
+func (s *Service) subscriptionTLSConfig(info meta.SubscriptionTLSInfo) (*tls.Config, error) {
+	tlsConfig, err := createTLSConfig(s.conf.CaCerts, s.conf.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if info.CA != "" {
+		if tlsConfig, err = loadCaCerts(info.CA, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+	if info.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(info.Cert, info.Key)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = new(tls.Config)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newPointsWriter returns a new PointsWriter from the given URL. tlsConfig is
+// used by HTTPS and MQTTS destinations.
+func (s *Service) newPointsWriter(u url.URL, tlsConfig *tls.Config) (PointsWriter, error) {
 	switch u.Scheme {
 	case "udp":
 		return NewUDP(u.Host), nil
@@ -346,7 +473,19 @@ func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
 		if s.conf.InsecureSkipVerify {
 			s.Logger.Warn("'insecure-skip-verify' is true. This will skip all certificate verifications.")
 		}
-		return NewHTTPS(u.String(), time.Duration(s.conf.HTTPTimeout), s.conf.InsecureSkipVerify, s.conf.CaCerts, s.conf.TLS)
+		return NewHTTPS(u.String(), time.Duration(s.conf.HTTPTimeout), s.conf.InsecureSkipVerify, "", tlsConfig)
+	case kafka.Scheme:
+		return NewKafka(u, time.Duration(s.conf.KafkaTimeout))
+	case mqtt.Scheme, mqtt.SchemeTLS:
+		if u.Scheme == mqtt.SchemeTLS && s.conf.InsecureSkipVerify {
+			if tlsConfig == nil {
+				tlsConfig = new(tls.Config)
+			} else {
+				tlsConfig = tlsConfig.Clone()
+			}
+			tlsConfig.InsecureSkipVerify = true
+		}
+		return NewMQTT(u, time.Duration(s.conf.MQTTTimeout), tlsConfig)
 	default:
 		return nil, fmt.Errorf("unknown destination scheme %s", u.Scheme)
 	}
@@ -356,6 +495,9 @@ func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
 type chanWriter struct {
 	writeRequests chan *coordinator.WritePointsRequest
 	pw            PointsWriter
+	filter        cnosql.Expr // nil if every point is forwarded
+	queue         *retryQueue // nil if failed writes are dropped
+	queueTags     models.StatisticTags
 	pointsWritten *int64
 	failures      *int64
 	logger        *zap.Logger
@@ -372,6 +514,11 @@ func (c chanWriter) Run() {
 		if err != nil {
 			c.logger.Info(err.Error())
 			atomic.AddInt64(c.failures, 1)
+			if c.queue != nil {
+				if err := c.queue.Enqueue(wr); err != nil {
+					c.logger.Info("Failed to queue write for retry", zap.Error(err))
+				}
+			}
 		} else {
 			atomic.AddInt64(c.pointsWritten, int64(len(wr.Points)))
 		}
@@ -380,10 +527,14 @@ func (c chanWriter) Run() {
 
 // Statistics returns statistics for periodic monitoring.
 func (c chanWriter) Statistics(tags map[string]string) []models.Statistic {
+	statistics := []models.Statistic{}
 	if m, ok := c.pw.(monitor.Reporter); ok {
-		return m.Statistics(tags)
+		statistics = m.Statistics(tags)
+	}
+	if c.queue != nil {
+		statistics = append(statistics, c.queue.Statistics(c.queueTags.Merge(tags)))
 	}
-	return []models.Statistic{}
+	return statistics
 }
 
 // BalanceMode specifies what balance mode to use on a subscription.
@@ -395,24 +546,95 @@ const (
 
 	// ANY indicates to send writes to a single subscriber destination, round robin.
 	ANY
+
+	// BALANCE indicates to send writes to a single subscriber destination,
+	// weighted round robin. Destinations weighted 0 are only written to when
+	// the others fail.
+	BALANCE
 )
 
 type writerStats struct {
 	dest          string
 	failures      int64
 	pointsWritten int64
+
+	// Destinations are healthy until a health check fails.
+	unhealthy            int32
+	healthyTransitions   int64
+	unhealthyTransitions int64
+
+	mu          sync.Mutex
+	lastErr     string
+	lastErrTime time.Time
+}
+
+// setLastError records err as the most recent write error.
+func (w *writerStats) setLastError(err error) {
+	w.mu.Lock()
+	w.lastErr, w.lastErrTime = err.Error(), time.Now()
+	w.mu.Unlock()
+}
+
+// lastError returns the most recent write error and when it happened in ns,
+// or zero values if no write has failed.
+func (w *writerStats) lastError() (string, int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.lastErrTime.IsZero() {
+		return "", 0
+	}
+	return w.lastErr, w.lastErrTime.UnixNano()
+}
+
+// healthy returns whether the last health check of the destination passed.
+func (w *writerStats) healthy() bool {
+	return atomic.LoadInt32(&w.unhealthy) == 0
+}
+
+// setHealthy records the result of a health check and returns whether the
+// destination changed state.
+func (w *writerStats) setHealthy(healthy bool) bool {
+	if healthy {
+		if !atomic.CompareAndSwapInt32(&w.unhealthy, 1, 0) {
+			return false
+		}
+		atomic.AddInt64(&w.healthyTransitions, 1)
+		return true
+	}
+	if !atomic.CompareAndSwapInt32(&w.unhealthy, 0, 1) {
+		return false
+	}
+	atomic.AddInt64(&w.unhealthyTransitions, 1)
+	return true
 }
 
 // balances writes across PointsWriters according to BalanceMode
 type balancewriter struct {
 	bm          BalanceMode
 	writers     []PointsWriter
-	stats       []writerStats
+	stats       []*writerStats
+	checks      []healthCheck // nil entries are not health checked
+	closing     chan struct{}
+	wg          sync.WaitGroup
+	logger      *zap.Logger
 	defaultTags models.StatisticTags
 	i           int
+
+	// Weighted round robin state of BALANCE mode.
+	mu      sync.Mutex
+	weights []int
+	current []int
 }
 
 func (b *balancewriter) WritePoints(p *coordinator.WritePointsRequest) error {
+	if b.bm == BALANCE {
+		return b.writeBalanced(p)
+	}
+
+	// In ANY mode only healthy destinations are written to, unless none
+	// are healthy, in which case all of them are tried.
+	skipUnhealthy := b.bm == ANY && b.anyHealthy()
+
 	var lastErr error
 	for range b.writers {
 		// round robin through destinations.
@@ -420,33 +642,138 @@ func (b *balancewriter) WritePoints(p *coordinator.WritePointsRequest) error {
 		w := b.writers[i]
 		b.i = (b.i + 1) % len(b.writers)
 
+		if skipUnhealthy && !b.stats[i].healthy() {
+			continue
+		}
+
 		// write points to destination.
-		err := w.WritePoints(p)
-		if err != nil {
+		if err := b.write(i, w, p); err != nil {
 			lastErr = err
-			atomic.AddInt64(&b.stats[i].failures, 1)
-		} else {
-			atomic.AddInt64(&b.stats[i].pointsWritten, int64(len(p.Points)))
-			if b.bm == ANY {
-				break
-			}
+		} else if b.bm == ANY {
+			// Delivered, whichever destinations failed before.
+			return nil
 		}
 	}
 	return lastErr
 }
 
+// writeBalanced writes p to the destination picked by weighted round robin,
+// falling back to the other weighted destinations and then to the standbys
+// until one of them accepts it. As in ANY mode, unhealthy destinations are
+// skipped unless none are healthy.
+func (b *balancewriter) writeBalanced(p *coordinator.WritePointsRequest) error {
+	skipUnhealthy := b.anyHealthy()
+	eligible := func(i int) bool {
+		return !skipUnhealthy || b.stats[i].healthy()
+	}
+
+	order := make([]int, 0, len(b.writers))
+	first := b.next(eligible)
+	if first >= 0 {
+		order = append(order, first)
+	}
+	for i, w := range b.weights {
+		if w > 0 && i != first && eligible(i) {
+			order = append(order, i)
+		}
+	}
+	for i, w := range b.weights {
+		if w == 0 && eligible(i) {
+			order = append(order, i)
+		}
+	}
+
+	var lastErr error
+	for _, i := range order {
+		if lastErr = b.write(i, b.writers[i], p); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// next returns the eligible destination with a positive weight picked by
+// smooth weighted round robin, or -1 if there is none. Each destination is
+// picked in proportion to its weight, interleaved with the others.
+func (b *balancewriter) next(eligible func(int) bool) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	best, total := -1, 0
+	for i, w := range b.weights {
+		if w <= 0 || !eligible(i) {
+			continue
+		}
+		b.current[i] += w
+		total += w
+		if best < 0 || b.current[i] > b.current[best] {
+			best = i
+		}
+	}
+	if best >= 0 {
+		b.current[best] -= total
+	}
+	return best
+}
+
+// write writes p to the i-th destination w and records the outcome.
+func (b *balancewriter) write(i int, w PointsWriter, p *coordinator.WritePointsRequest) error {
+	if err := w.WritePoints(p); err != nil {
+		atomic.AddInt64(&b.stats[i].failures, 1)
+		b.stats[i].setLastError(err)
+		return err
+	}
+	atomic.AddInt64(&b.stats[i].pointsWritten, int64(len(p.Points)))
+	return nil
+}
+
+// anyHealthy returns whether at least one destination is healthy.
+func (b *balancewriter) anyHealthy() bool {
+	for _, st := range b.stats {
+		if st.healthy() {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops the health checks and closes the destinations that hold
+// resources, such as Kafka producers.
+func (b *balancewriter) Close() error {
+	close(b.closing)
+	b.wg.Wait()
+	closeWriters(b.writers)
+	return nil
+}
+
+// closeWriters closes the writers implementing io.Closer.
+func closeWriters(writers []PointsWriter) {
+	for _, w := range writers {
+		if c, ok := w.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
 // Statistics returns statistics for periodic monitoring.
 func (b *balancewriter) Statistics(tags map[string]string) []models.Statistic {
 	statistics := make([]models.Statistic, len(b.stats))
 	for i := range b.stats {
 		subTags := b.defaultTags.Merge(tags)
 		subTags["destination"] = b.stats[i].dest
+		lastErr, lastErrTime := b.stats[i].lastError()
 		statistics[i] = models.Statistic{
 			Name: "subscriber",
 			Tags: subTags,
 			Values: map[string]interface{}{
 				statPointsWritten: atomic.LoadInt64(&b.stats[i].pointsWritten),
 				statWriteFailures: atomic.LoadInt64(&b.stats[i].failures),
+				statLastError:     lastErr,
+				statLastErrorTime: lastErrTime,
+
+				statHealthy:              b.stats[i].healthy(),
+				statHealthyTransitions:   atomic.LoadInt64(&b.stats[i].healthyTransitions),
+				statUnhealthyTransitions: atomic.LoadInt64(&b.stats[i].unhealthyTransitions),
 			},
 		}
 	}
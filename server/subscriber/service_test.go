@@ -0,0 +1,381 @@
+package subscriber
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/common/pkg/toml"
+)
+
+// testHealthServer answers the health checks of a destination with a status
+// that can be changed during a test.
+type testHealthServer struct {
+	*httptest.Server
+	status int32
+	checks int32
+}
+
+func newTestHealthServer(t *testing.T) *testHealthServer {
+	s := &testHealthServer{status: http.StatusNoContent}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.checks, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&s.status)))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetHealthy makes the following health checks pass or fail.
+func (s *testHealthServer) SetHealthy(healthy bool) {
+	status := http.StatusNoContent
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+	atomic.StoreInt32(&s.status, int32(status))
+}
+
+// testWriters returns the writers of the destinations by host.
+type testWriters struct {
+	mu      sync.Mutex
+	writers map[string]*testPointsWriter
+}
+
+func (w *testWriters) NewPointsWriter(u url.URL, _ *tls.Config) (PointsWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.writers == nil {
+		w.writers = make(map[string]*testPointsWriter)
+	}
+	pw, ok := w.writers[u.Host]
+	if !ok {
+		pw = &testPointsWriter{}
+		w.writers[u.Host] = pw
+	}
+	return pw, nil
+}
+
+// Writer returns the writer of the destination served by s.
+func (w *testWriters) Writer(s *httptest.Server) *testPointsWriter {
+	u, _ := url.Parse(s.URL)
+	pw, _ := w.NewPointsWriter(*u, nil)
+	return pw.(*testPointsWriter)
+}
+
+// waitFor fails the test if cond is still false after a few seconds.
+func waitFor(tb testing.TB, what string, cond func() bool) {
+	tb.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			tb.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newTestBalanceWriter returns the writer of a subscription in mode to the
+// servers, health checked every millisecond.
+func newTestBalanceWriter(t *testing.T, mode string, writers *testWriters, servers ...*testHealthServer) *balancewriter {
+	c := NewConfig()
+	c.HealthCheckInterval = toml.Duration(time.Millisecond)
+	s := NewService(c)
+	s.NewPointsWriter = writers.NewPointsWriter
+
+	si := meta.SubscriptionInfo{Name: "s0", Mode: mode}
+	for _, srv := range servers {
+		si.Destinations = append(si.Destinations, srv.URL)
+	}
+	pw, err := s.createSubscription(subEntry{db: "db0", rp: "rp0", name: "s0"}, si)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := pw.(*balancewriter)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+// waitForHealth waits until the health of the i-th destination of b is
+// healthy and it was checked again since.
+func waitForHealth(t *testing.T, b *balancewriter, srv *testHealthServer, i int, healthy bool) {
+	t.Helper()
+	waitFor(t, "health check", func() bool { return b.stats[i].healthy() == healthy })
+	n := atomic.LoadInt32(&srv.checks)
+	waitFor(t, "health check", func() bool { return atomic.LoadInt32(&srv.checks) > n+1 })
+}
+
+func TestBalanceWriter_ANY(t *testing.T) {
+	a, b := newTestHealthServer(t), newTestHealthServer(t)
+	writers := &testWriters{}
+	w := newTestBalanceWriter(t, "ANY", writers, a, b)
+	wa, wb := writers.Writer(a.Server), writers.Writer(b.Server)
+
+	// Writes go round robin to the healthy destinations.
+	for i := 0; i < 4; i++ {
+		if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if na, nb := len(wa.Writes()), len(wb.Writes()); na != 2 || nb != 2 {
+		t.Fatalf("unexpected writes: %d, %d", na, nb)
+	}
+
+	// A failed write is sent to the next destination.
+	errWrite := errors.New("write failed")
+	wa.SetErr(errWrite)
+	for i := 0; i < 2; i++ {
+		if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if na, nb := len(wa.Writes()), len(wb.Writes()); na != 2 || nb != 4 || wa.Failed() != 2 {
+		t.Fatalf("unexpected writes: %d, %d, %d failed", na, nb, wa.Failed())
+	}
+	wa.SetErr(nil)
+
+	// Unhealthy destinations are skipped.
+	b.SetHealthy(false)
+	waitForHealth(t, w, b, 1, false)
+	for i := 0; i < 4; i++ {
+		if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if na, nb := len(wa.Writes()), len(wb.Writes()); na != 6 || nb != 4 {
+		t.Fatalf("unexpected writes: %d, %d", na, nb)
+	}
+
+	// So a failed write to the only healthy destination is not retried on
+	// the unhealthy one.
+	wa.SetErr(errWrite)
+	if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != errWrite {
+		t.Fatalf("unexpected error: %v", err)
+	} else if nb := len(wb.Writes()); nb != 4 {
+		t.Fatalf("unexpected writes: %d", nb)
+	}
+
+	// Once every destination is unhealthy, all of them are tried.
+	a.SetHealthy(false)
+	waitForHealth(t, w, a, 0, false)
+	if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+		t.Fatal(err)
+	} else if nb := len(wb.Writes()); nb != 5 {
+		t.Fatalf("unexpected writes: %d", nb)
+	}
+	wb.SetErr(errWrite)
+	if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != errWrite {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Recovered destinations are written to again.
+	wa.SetErr(nil)
+	wb.SetErr(nil)
+	a.SetHealthy(true)
+	b.SetHealthy(true)
+	waitForHealth(t, w, a, 0, true)
+	waitForHealth(t, w, b, 1, true)
+	for i := 0; i < 2; i++ {
+		if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if na, nb := len(wa.Writes()), len(wb.Writes()); na != 7 || nb != 6 {
+		t.Fatalf("unexpected writes: %d, %d", na, nb)
+	}
+
+	stats := w.Statistics(nil)
+	for i, srv := range []*testHealthServer{a, b} {
+		v := stats[i].Values
+		if stats[i].Tags["destination"] != srv.URL || stats[i].Tags["mode"] != "ANY" {
+			t.Fatalf("unexpected tags: %v", stats[i].Tags)
+		} else if v[statHealthy] != true || v[statHealthyTransitions] != int64(1) || v[statUnhealthyTransitions] != int64(1) {
+			t.Fatalf("%s: unexpected statistics: %v", srv.URL, v)
+		} else if v[statLastError] != "write failed" || v[statLastErrorTime].(int64) == 0 {
+			t.Fatalf("%s: unexpected last error: %v", srv.URL, v)
+		}
+	}
+}
+
+func TestBalanceWriter_ALL(t *testing.T) {
+	a, b := newTestHealthServer(t), newTestHealthServer(t)
+	writers := &testWriters{}
+	w := newTestBalanceWriter(t, "ALL", writers, a, b)
+	wa, wb := writers.Writer(a.Server), writers.Writer(b.Server)
+
+	// Destinations are not health checked in ALL mode, and every
+	// destination gets every write.
+	b.SetHealthy(false)
+	wa.SetErr(errors.New("write failed"))
+	if err := w.WritePoints(mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")); err == nil {
+		t.Fatal("expected error")
+	} else if wa.Failed() != 1 || len(wb.Writes()) != 1 {
+		t.Fatalf("unexpected writes: %d failed, %d", wa.Failed(), len(wb.Writes()))
+	}
+	if n := atomic.LoadInt32(&b.checks); n != 0 {
+		t.Fatalf("unexpected health checks: %d", n)
+	}
+}
+
+func TestService_HealthCheck(t *testing.T) {
+	s := NewService(NewConfig())
+
+	// HTTP destinations are unhealthy on server errors.
+	srv := newTestHealthServer(t)
+	u, _ := url.Parse(srv.URL)
+	check, err := s.newHealthCheck(*u, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := check(); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetHealthy(false)
+	if err := check(); err == nil || err.Error() != "health check of "+srv.URL+" returned 503 Service Unavailable" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Kafka and MQTT destinations are healthy if a broker accepts
+	// connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	for _, tt := range []struct {
+		dest    string
+		healthy bool
+	}{
+		{dest: "kafka://" + ln.Addr().String() + "/t", healthy: true},
+		{dest: "kafka://" + closed.Addr().String() + "," + ln.Addr().String() + "/t", healthy: true},
+		{dest: "kafka://" + closed.Addr().String() + "/t"},
+		{dest: "mqtt://" + ln.Addr().String() + "/t", healthy: true},
+		{dest: "mqtt://" + closed.Addr().String() + "/t"},
+	} {
+		u, err := url.Parse(tt.dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check, err := s.newHealthCheck(*u, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dest, err)
+		} else if err := check(); (err == nil) != tt.healthy {
+			t.Fatalf("%s: unexpected health check result: %v", tt.dest, err)
+		}
+	}
+
+	// UDP destinations are not checked.
+	if check, err := s.newHealthCheck(url.URL{Scheme: "udp", Host: "localhost:8089"}, nil); err != nil || check != nil {
+		t.Fatalf("unexpected health check: %v", err)
+	}
+}
+
+// testMetaClient returns the databases of the subscriptions.
+type testMetaClient struct {
+	mu        sync.Mutex
+	databases []meta.DatabaseInfo
+	changed   chan struct{}
+}
+
+func (c *testMetaClient) Databases() []meta.DatabaseInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.databases
+}
+
+func (c *testMetaClient) WaitForDataChanged() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.changed
+}
+
+// SetSubscriptions replaces the subscriptions of rp0 in db0.
+func (c *testMetaClient) SetSubscriptions(subs ...meta.SubscriptionInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.databases = []meta.DatabaseInfo{{
+		Name:              "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0", Subscriptions: subs}},
+	}}
+	if c.changed != nil {
+		close(c.changed)
+	}
+	c.changed = make(chan struct{})
+}
+
+// queueStatistics returns the values of the queue statistics of the only
+// subscription of s.
+func queueStatistics(s *Service) map[string]interface{} {
+	for _, st := range s.Statistics(nil) {
+		if st.Name == "subscriber_queue" {
+			return st.Values
+		}
+	}
+	return nil
+}
+
+func TestService_Queue(t *testing.T) {
+	dest := newTestHealthServer(t)
+	writers := &testWriters{}
+	pw := writers.Writer(dest.Server)
+
+	c := NewConfig()
+	c.QueueDir = t.TempDir()
+	c.RetryInterval = toml.Duration(time.Millisecond)
+	c.RetryMaxInterval = toml.Duration(time.Millisecond)
+	c.WriteConcurrency = 1
+	s := NewService(c)
+	s.NewPointsWriter = writers.NewPointsWriter
+	mc := &testMetaClient{}
+	mc.SetSubscriptions(meta.SubscriptionInfo{Name: "s0", Mode: "ANY", Destinations: []string{dest.URL}})
+	s.MetaClient = mc
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Writes failing while the destination is down are queued and retried
+	// once it accepts them, in order.
+	pw.SetErr(errors.New("write failed"))
+	s.Points() <- mustWriteRequest(t, "db0", "rp0", "cpu value=1 10")
+	s.Points() <- mustWriteRequest(t, "db0", "other", "cpu value=2 20")
+	s.Points() <- mustWriteRequest(t, "db0", "rp0", "cpu value=3 30")
+	waitFor(t, "queued writes", func() bool { return queueStatistics(s)[statPointsQueued] == int64(2) })
+	pw.SetErr(nil)
+	waitFor(t, "retries", func() bool { return len(pw.Writes()) == 2 })
+	if got, exp := pw.Writes(), []string{"db0/rp0: cpu value=1 10\n", "db0/rp0: cpu value=3 30\n"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected writes: %q", got)
+	} else if v := queueStatistics(s); v[statPointsRetried] != int64(2) || v[statPointsDiscarded] != int64(0) {
+		t.Fatalf("unexpected queue statistics: %v", v)
+	}
+
+	// Dropping the subscription removes its queue.
+	mc.SetSubscriptions()
+	waitFor(t, "dropped subscription", func() bool {
+		s.subMu.RLock()
+		defer s.subMu.RUnlock()
+		return len(s.subs) == 0
+	})
+}
@@ -207,19 +207,27 @@ type Node interface {
 func (*Query) node()     {}
 func (Statements) node() {}
 
+func (*AlterDatabaseStatement) node()              {}
 func (*AlterRetentionPolicyStatement) node()       {}
+func (*AlterUserStatement) node()                  {}
+func (*AlterUserQueryLimitStatement) node()        {}
+func (*AlterUserDefaultDatabaseStatement) node()   {}
 func (*CreateContinuousQueryStatement) node()      {}
 func (*CreateDatabaseStatement) node()             {}
 func (*CreateRetentionPolicyStatement) node()      {}
+func (*CreateRoleStatement) node()                 {}
 func (*CreateSubscriptionStatement) node()         {}
+func (*CreateTokenStatement) node()                {}
 func (*CreateUserStatement) node()                 {}
 func (*Distinct) node()                            {}
 func (*DeleteSeriesStatement) node()               {}
 func (*DeleteStatement) node()                     {}
+func (*AlterContinuousQueryStatement) node()       {}
 func (*DropContinuousQueryStatement) node()        {}
 func (*DropDatabaseStatement) node()               {}
 func (*DropMeasurementStatement) node()            {}
 func (*DropRetentionPolicyStatement) node()        {}
+func (*DropRoleStatement) node()                   {}
 func (*DropSeriesStatement) node()                 {}
 func (*DropShardStatement) node()                  {}
 func (*DropSubscriptionStatement) node()           {}
@@ -227,25 +235,40 @@ func (*DropUserStatement) node()                   {}
 func (*ExplainStatement) node()                    {}
 func (*GrantStatement) node()                      {}
 func (*GrantAdminStatement) node()                 {}
+func (*GrantRoleStatement) node()                  {}
+func (*GrantToRoleStatement) node()                {}
 func (*KillQueryStatement) node()                  {}
+func (*KillSessionStatement) node()                {}
+func (*PrecreateShardGroupsStatement) node()       {}
+func (*PurgeDataStatement) node()                  {}
+func (*UndropShardGroupStatement) node()           {}
+func (*RunContinuousQueryStatement) node()         {}
 func (*RevokeStatement) node()                     {}
 func (*RevokeAdminStatement) node()                {}
+func (*RevokeRoleStatement) node()                 {}
+func (*RevokeFromRoleStatement) node()             {}
+func (*RevokeTokenStatement) node()                {}
 func (*SelectStatement) node()                     {}
 func (*SetPasswordUserStatement) node()            {}
 func (*ShowContinuousQueriesStatement) node()      {}
 func (*ShowGrantsForUserStatement) node()          {}
+func (*ShowGrantsForRoleStatement) node()          {}
 func (*ShowDatabasesStatement) node()              {}
 func (*ShowFieldKeyCardinalityStatement) node()    {}
 func (*ShowFieldKeysStatement) node()              {}
 func (*ShowRetentionPoliciesStatement) node()      {}
+func (*ShowRolesStatement) node()                  {}
 func (*ShowMeasurementCardinalityStatement) node() {}
 func (*ShowMeasurementsStatement) node()           {}
+func (*ShowOperationsStatement) node()             {}
 func (*ShowQueriesStatement) node()                {}
 func (*ShowSeriesStatement) node()                 {}
+func (*ShowSessionsStatement) node()               {}
 func (*ShowSeriesCardinalityStatement) node()      {}
 func (*ShowShardGroupsStatement) node()            {}
 func (*ShowShardsStatement) node()                 {}
 func (*ShowStatsStatement) node()                  {}
+func (*ShowTokensStatement) node()                 {}
 func (*ShowSubscriptionsStatement) node()          {}
 func (*ShowDiagnosticsStatement) node()            {}
 func (*ShowTagKeyCardinalityStatement) node()      {}
@@ -253,6 +276,7 @@ func (*ShowTagKeysStatement) node()                {}
 func (*ShowTagValuesCardinalityStatement) node()   {}
 func (*ShowTagValuesStatement) node()              {}
 func (*ShowUsersStatement) node()                  {}
+func (*TruncateShardsStatement) node()             {}
 
 func (*BinaryExpr) node()      {}
 func (*BooleanLiteral) node()  {}
@@ -336,39 +360,59 @@ type ExecutionPrivilege struct {
 // ExecutionPrivileges is a list of privileges required to execute a statement.
 type ExecutionPrivileges []ExecutionPrivilege
 
+func (*AlterDatabaseStatement) stmt()              {}
 func (*AlterRetentionPolicyStatement) stmt()       {}
+func (*AlterUserStatement) stmt()                  {}
+func (*AlterUserQueryLimitStatement) stmt()        {}
+func (*AlterUserDefaultDatabaseStatement) stmt()   {}
 func (*CreateContinuousQueryStatement) stmt()      {}
 func (*CreateDatabaseStatement) stmt()             {}
 func (*CreateRetentionPolicyStatement) stmt()      {}
+func (*CreateRoleStatement) stmt()                 {}
 func (*CreateSubscriptionStatement) stmt()         {}
+func (*CreateTokenStatement) stmt()                {}
 func (*CreateUserStatement) stmt()                 {}
 func (*DeleteSeriesStatement) stmt()               {}
 func (*DeleteStatement) stmt()                     {}
+func (*AlterContinuousQueryStatement) stmt()       {}
 func (*DropContinuousQueryStatement) stmt()        {}
 func (*DropDatabaseStatement) stmt()               {}
 func (*DropMeasurementStatement) stmt()            {}
 func (*DropRetentionPolicyStatement) stmt()        {}
+func (*DropRoleStatement) stmt()                   {}
 func (*DropSeriesStatement) stmt()                 {}
 func (*DropSubscriptionStatement) stmt()           {}
 func (*DropUserStatement) stmt()                   {}
 func (*ExplainStatement) stmt()                    {}
 func (*GrantStatement) stmt()                      {}
 func (*GrantAdminStatement) stmt()                 {}
+func (*GrantRoleStatement) stmt()                  {}
+func (*GrantToRoleStatement) stmt()                {}
 func (*KillQueryStatement) stmt()                  {}
+func (*KillSessionStatement) stmt()                {}
+func (*PrecreateShardGroupsStatement) stmt()       {}
+func (*PurgeDataStatement) stmt()                  {}
+func (*UndropShardGroupStatement) stmt()           {}
+func (*RunContinuousQueryStatement) stmt()         {}
 func (*ShowContinuousQueriesStatement) stmt()      {}
 func (*ShowGrantsForUserStatement) stmt()          {}
+func (*ShowGrantsForRoleStatement) stmt()          {}
 func (*ShowDatabasesStatement) stmt()              {}
 func (*ShowFieldKeyCardinalityStatement) stmt()    {}
 func (*ShowFieldKeysStatement) stmt()              {}
 func (*ShowMeasurementCardinalityStatement) stmt() {}
 func (*ShowMeasurementsStatement) stmt()           {}
+func (*ShowOperationsStatement) stmt()             {}
 func (*ShowQueriesStatement) stmt()                {}
 func (*ShowRetentionPoliciesStatement) stmt()      {}
+func (*ShowRolesStatement) stmt()                  {}
 func (*ShowSeriesStatement) stmt()                 {}
+func (*ShowSessionsStatement) stmt()               {}
 func (*ShowSeriesCardinalityStatement) stmt()      {}
 func (*ShowShardGroupsStatement) stmt()            {}
 func (*ShowShardsStatement) stmt()                 {}
 func (*ShowStatsStatement) stmt()                  {}
+func (*ShowTokensStatement) stmt()                 {}
 func (*DropShardStatement) stmt()                  {}
 func (*ShowSubscriptionsStatement) stmt()          {}
 func (*ShowDiagnosticsStatement) stmt()            {}
@@ -379,8 +423,64 @@ func (*ShowTagValuesStatement) stmt()              {}
 func (*ShowUsersStatement) stmt()                  {}
 func (*RevokeStatement) stmt()                     {}
 func (*RevokeAdminStatement) stmt()                {}
+func (*RevokeRoleStatement) stmt()                 {}
+func (*RevokeFromRoleStatement) stmt()             {}
+func (*RevokeTokenStatement) stmt()                {}
 func (*SelectStatement) stmt()                     {}
 func (*SetPasswordUserStatement) stmt()            {}
+func (*TruncateShardsStatement) stmt()             {}
+
+// IsMutatingStatement returns true if executing stmt changes data or
+// metadata, which includes a SELECT writing its results INTO a measurement.
+func IsMutatingStatement(stmt Statement) bool {
+	switch stmt := stmt.(type) {
+	case *AlterContinuousQueryStatement,
+		*AlterDatabaseStatement,
+		*AlterRetentionPolicyStatement,
+		*AlterUserStatement,
+		*AlterUserQueryLimitStatement,
+		*AlterUserDefaultDatabaseStatement,
+		*CreateContinuousQueryStatement,
+		*CreateDatabaseStatement,
+		*CreateRetentionPolicyStatement,
+		*CreateRoleStatement,
+		*CreateSubscriptionStatement,
+		*CreateTokenStatement,
+		*CreateUserStatement,
+		*DeleteSeriesStatement,
+		*DeleteStatement,
+		*DropContinuousQueryStatement,
+		*DropDatabaseStatement,
+		*DropMeasurementStatement,
+		*DropRetentionPolicyStatement,
+		*DropRoleStatement,
+		*DropSeriesStatement,
+		*DropShardStatement,
+		*DropSubscriptionStatement,
+		*DropUserStatement,
+		*GrantAdminStatement,
+		*GrantRoleStatement,
+		*GrantStatement,
+		*GrantToRoleStatement,
+		*KillQueryStatement,
+		*KillSessionStatement,
+		*PrecreateShardGroupsStatement,
+		*PurgeDataStatement,
+		*RevokeAdminStatement,
+		*RevokeFromRoleStatement,
+		*RevokeRoleStatement,
+		*RevokeStatement,
+		*RevokeTokenStatement,
+		*RunContinuousQueryStatement,
+		*SetPasswordUserStatement,
+		*TruncateShardsStatement,
+		*UndropShardGroupStatement:
+		return true
+	case *SelectStatement:
+		return stmt.Target != nil
+	}
+	return false
+}
 
 // Expr represents an expression that can be evaluated to a value.
 type Expr interface {
@@ -592,6 +692,10 @@ type CreateDatabaseStatement struct {
 
 	// RetentionPolicyShardGroupDuration indicates shard group duration for the new database.
 	RetentionPolicyShardGroupDuration time.Duration
+
+	// IndexType is the index used by shards of the new database. Empty uses
+	// the server's configured index.
+	IndexType string
 }
 
 // String returns a string representation of the create database statement.
@@ -618,6 +722,13 @@ func (s *CreateDatabaseStatement) String() string {
 			_, _ = buf.WriteString(QuoteIdent(s.RetentionPolicyName))
 		}
 	}
+	if s.IndexType != "" {
+		if !s.RetentionPolicyCreate {
+			_, _ = buf.WriteString(" WITH")
+		}
+		_, _ = buf.WriteString(" INDEX ")
+		_, _ = buf.WriteString(QuoteString(s.IndexType))
+	}
 
 	return buf.String()
 }
@@ -653,6 +764,9 @@ type DropRetentionPolicyStatement struct {
 
 	// Name of the database to drop the policy from.
 	Database string
+
+	// Force allows dropping the database's default retention policy.
+	Force bool
 }
 
 // String returns a string representation of the drop retention policy statement.
@@ -662,6 +776,9 @@ func (s *DropRetentionPolicyStatement) String() string {
 	_, _ = buf.WriteString(QuoteIdent(s.Name))
 	_, _ = buf.WriteString(" ON ")
 	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	if s.Force {
+		_, _ = buf.WriteString(" FORCE")
+	}
 	return buf.String()
 }
 
@@ -724,6 +841,109 @@ func (s *DropUserStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// CreateRoleStatement represents a command for creating a new role.
+type CreateRoleStatement struct {
+	// Name of the role to be created.
+	Name string
+}
+
+// String returns a string representation of the create role statement.
+func (s *CreateRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateRoleStatement.
+func (s *CreateRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DropRoleStatement represents a command for dropping a role.
+type DropRoleStatement struct {
+	// Name of the role to drop.
+	Name string
+}
+
+// String returns a string representation of the drop role statement.
+func (s *DropRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropRoleStatement.
+func (s *DropRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// CreateTokenStatement represents a command for creating an API token.
+type CreateTokenStatement struct {
+	// Name of the user the token authenticates as.
+	// Blank for the user running the statement.
+	User string
+
+	// Privilege the token is restricted to on Database.
+	// NoPrivileges keeps all the privileges of the user.
+	Privilege Privilege
+	Database  string
+
+	// Duration after which the token expires. Zero never expires.
+	Duration time.Duration
+}
+
+// String returns a string representation of the create token statement.
+func (s *CreateTokenStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE TOKEN")
+	if s.User != "" {
+		_, _ = buf.WriteString(" FOR ")
+		_, _ = buf.WriteString(QuoteIdent(s.User))
+	}
+	if s.Privilege != NoPrivileges {
+		_, _ = buf.WriteString(" WITH ")
+		_, _ = buf.WriteString(s.Privilege.String())
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Duration > 0 {
+		_, _ = buf.WriteString(" DURATION ")
+		_, _ = buf.WriteString(FormatDuration(s.Duration))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateTokenStatement.
+// Users may create tokens for themselves, only admins may create them for other users.
+func (s *CreateTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	if s.User != "" {
+		return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+	}
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
+// RevokeTokenStatement represents a command for revoking an API token.
+type RevokeTokenStatement struct {
+	// ID of the token to revoke.
+	ID string
+}
+
+// String returns a string representation of the revoke token statement.
+func (s *RevokeTokenStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("REVOKE TOKEN ")
+	_, _ = buf.WriteString(QuoteString(s.ID))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a RevokeTokenStatement.
+// Users may revoke their own tokens, which the statement executor checks.
+func (s *RevokeTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
 // Privilege is a type of action a user can be granted the right to use.
 type Privilege int
 
@@ -764,6 +984,22 @@ type GrantStatement struct {
 	// Database to grant the privilege to.
 	On string
 
+	// Retention policy of the database the privilege is restricted to. It
+	// is empty if the privilege is granted on the whole database.
+	RetentionPolicy string
+
+	// Measurements of the database the privilege is restricted to. It is
+	// nil if the privilege is granted on the whole database.
+	Measurement *Measurement
+
+	// Databases the privilege is granted on when it is granted on a list of
+	// databases. On is empty then.
+	Databases []string
+
+	// Regex matching the databases the privilege is granted on. On is empty
+	// then.
+	DatabaseRegex *RegexLiteral
+
 	// Who to grant the privilege to.
 	User string
 }
@@ -774,7 +1010,7 @@ func (s *GrantStatement) String() string {
 	_, _ = buf.WriteString("GRANT ")
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
-	_, _ = buf.WriteString(QuoteIdent(s.On))
+	writePrivilegeTarget(&buf, s.On, s.RetentionPolicy, s.Measurement, s.Databases, s.DatabaseRegex)
 	_, _ = buf.WriteString(" TO ")
 	_, _ = buf.WriteString(QuoteIdent(s.User))
 	return buf.String()
@@ -790,6 +1026,27 @@ func (s *GrantStatement) DefaultDatabase() string {
 	return s.On
 }
 
+// writePrivilegeTarget writes the target of a granted or revoked privilege.
+func writePrivilegeTarget(buf *strings.Builder, on, rp string, m *Measurement, dbs []string, re *RegexLiteral) {
+	switch {
+	case m != nil:
+		_, _ = buf.WriteString(m.String())
+	case rp != "":
+		_, _ = buf.WriteString(QuoteIdent(on, rp))
+	case re != nil:
+		_, _ = buf.WriteString(re.String())
+	case dbs != nil:
+		for i, db := range dbs {
+			if i > 0 {
+				_, _ = buf.WriteString(", ")
+			}
+			_, _ = buf.WriteString(QuoteIdent(db))
+		}
+	default:
+		_, _ = buf.WriteString(QuoteIdent(on))
+	}
+}
+
 // GrantAdminStatement represents a command for granting admin privilege.
 type GrantAdminStatement struct {
 	// Who to grant the privilege to.
@@ -809,6 +1066,64 @@ func (s *GrantAdminStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// GrantToRoleStatement represents a command for granting a privilege to a role.
+type GrantToRoleStatement struct {
+	// The privilege to be granted.
+	Privilege Privilege
+
+	// Database to grant the privilege to.
+	On string
+
+	// Role to grant the privilege to.
+	Role string
+}
+
+// String returns a string representation of the grant to role statement.
+func (s *GrantToRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("GRANT ")
+	_, _ = buf.WriteString(s.Privilege.String())
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.On))
+	_, _ = buf.WriteString(" TO ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantToRoleStatement.
+func (s *GrantToRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *GrantToRoleStatement) DefaultDatabase() string {
+	return s.On
+}
+
+// GrantRoleStatement represents a command for granting a role to a user.
+type GrantRoleStatement struct {
+	// Role to be granted.
+	Role string
+
+	// Who to grant the role to.
+	User string
+}
+
+// String returns a string representation of the grant role statement.
+func (s *GrantRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("GRANT ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" TO ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantRoleStatement.
+func (s *GrantRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // KillQueryStatement represents a command for killing a query.
 type KillQueryStatement struct {
 	// The query to kill.
@@ -835,6 +1150,23 @@ func (s *KillQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// KillSessionStatement represents a command for killing a client session.
+type KillSessionStatement struct {
+	// The session to kill.
+	SessionID uint64
+}
+
+// String returns a string representation of the kill session statement.
+func (s *KillSessionStatement) String() string {
+	return "KILL SESSION " + strconv.FormatUint(s.SessionID, 10)
+}
+
+// RequiredPrivileges returns the privilege required to execute a KillSessionStatement.
+// Users may kill their own sessions, which the task manager checks.
+func (s *KillSessionStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
 // SetPasswordUserStatement represents a command for changing user password.
 type SetPasswordUserStatement struct {
 	// Plain-text password.
@@ -859,6 +1191,110 @@ func (s *SetPasswordUserStatement) RequiredPrivileges() (ExecutionPrivileges, er
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// AlterUserStatement represents a command to lock or unlock a user.
+type AlterUserStatement struct {
+	// Name of the user to alter.
+	Name string
+
+	// Whether the user is locked or unlocked.
+	Locked bool
+}
+
+// String returns a string representation of the alter user statement.
+func (s *AlterUserStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("ALTER USER ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	if s.Locked {
+		_, _ = buf.WriteString(" LOCK")
+	} else {
+		_, _ = buf.WriteString(" UNLOCK")
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterUserStatement.
+func (s *AlterUserStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// AlterUserQueryLimitStatement represents a command to set the query limits
+// of a user.
+type AlterUserQueryLimitStatement struct {
+	// Name of the user to alter.
+	Name string
+
+	// Maximum number of queries the user may run at once. Zero means unlimited.
+	MaxConcurrentQueries *int64
+
+	// Maximum number of queries the user may start per minute. Zero means
+	// unlimited.
+	MaxQueriesPerMinute *int64
+
+	// Priority of the queries of the user that don't request one: "low",
+	// "normal" or "high". Empty leaves it unchanged.
+	Priority string
+}
+
+// String returns a string representation of the alter user query limit statement.
+func (s *AlterUserQueryLimitStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("ALTER USER ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" WITH QUERY LIMIT")
+	if s.MaxConcurrentQueries != nil {
+		_, _ = buf.WriteString(" CONCURRENT ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxConcurrentQueries, 10))
+	}
+	if s.MaxQueriesPerMinute != nil {
+		_, _ = buf.WriteString(" PER MINUTE ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxQueriesPerMinute, 10))
+	}
+	if s.Priority != "" {
+		_, _ = buf.WriteString(" PRIORITY ")
+		_, _ = buf.WriteString(strings.ToUpper(s.Priority))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterUserQueryLimitStatement.
+func (s *AlterUserQueryLimitStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// AlterUserDefaultDatabaseStatement represents a command to set the database
+// and retention policy used for requests of a user that don't specify any.
+type AlterUserDefaultDatabaseStatement struct {
+	// Name of the user to alter.
+	Name string
+
+	// Default database of the user. An empty name clears the defaults.
+	Database string
+
+	// Default retention policy of the user. An empty name uses the default
+	// retention policy of the database.
+	RetentionPolicy string
+}
+
+// String returns a string representation of the alter user default database statement.
+func (s *AlterUserDefaultDatabaseStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("ALTER USER ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" SET DEFAULT DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	if s.RetentionPolicy != "" {
+		_, _ = buf.WriteString(" RETENTION POLICY ")
+		_, _ = buf.WriteString(QuoteIdent(s.RetentionPolicy))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterUserDefaultDatabaseStatement.
+func (s *AlterUserDefaultDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // RevokeStatement represents a command to revoke a privilege from a user.
 type RevokeStatement struct {
 	// The privilege to be revoked.
@@ -867,6 +1303,22 @@ type RevokeStatement struct {
 	// Database to revoke the privilege from.
 	On string
 
+	// Retention policy of the database the privilege is revoked on. It is
+	// empty if the privilege is revoked on the whole database.
+	RetentionPolicy string
+
+	// Measurements of the database the privilege is revoked on. It is nil
+	// if the privilege is revoked on the whole database.
+	Measurement *Measurement
+
+	// Databases the privilege is revoked on when it is revoked on a list of
+	// databases. On is empty then.
+	Databases []string
+
+	// Regex matching the databases the privilege is revoked on. On is empty
+	// then.
+	DatabaseRegex *RegexLiteral
+
 	// Who to revoke privilege from.
 	User string
 }
@@ -877,7 +1329,7 @@ func (s *RevokeStatement) String() string {
 	_, _ = buf.WriteString("REVOKE ")
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
-	_, _ = buf.WriteString(QuoteIdent(s.On))
+	writePrivilegeTarget(&buf, s.On, s.RetentionPolicy, s.Measurement, s.Databases, s.DatabaseRegex)
 	_, _ = buf.WriteString(" FROM ")
 	_, _ = buf.WriteString(QuoteIdent(s.User))
 	return buf.String()
@@ -912,6 +1364,64 @@ func (s *RevokeAdminStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// RevokeFromRoleStatement represents a command to revoke a privilege from a role.
+type RevokeFromRoleStatement struct {
+	// The privilege to be revoked.
+	Privilege Privilege
+
+	// Database to revoke the privilege from.
+	On string
+
+	// Role to revoke the privilege from.
+	Role string
+}
+
+// String returns a string representation of the revoke from role statement.
+func (s *RevokeFromRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("REVOKE ")
+	_, _ = buf.WriteString(s.Privilege.String())
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.On))
+	_, _ = buf.WriteString(" FROM ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeFromRoleStatement.
+func (s *RevokeFromRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *RevokeFromRoleStatement) DefaultDatabase() string {
+	return s.On
+}
+
+// RevokeRoleStatement represents a command to revoke a role from a user.
+type RevokeRoleStatement struct {
+	// Role to be revoked.
+	Role string
+
+	// Who to revoke the role from.
+	User string
+}
+
+// String returns a string representation of the revoke role statement.
+func (s *RevokeRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("REVOKE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" FROM ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeRoleStatement.
+func (s *RevokeRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // CreateRetentionPolicyStatement represents a command to create a retention policy.
 type CreateRetentionPolicyStatement struct {
 	// Name of policy to create.
@@ -931,6 +1441,27 @@ type CreateRetentionPolicyStatement struct {
 
 	// Shard Duration.
 	ShardGroupDuration time.Duration
+
+	// How far ahead of the current time points may be written. Zero is unlimited.
+	FutureWriteLimit time.Duration
+
+	// How far behind the current time points may be written. Zero is unlimited.
+	PastWriteLimit time.Duration
+
+	// How long after a shard group ends it moves to the cold tier. Zero disables it.
+	ColdAfter time.Duration
+
+	// Downsampling of the policy's data into another policy. Nil disables it.
+	Downsample *Downsample
+
+	// Node tag selector restricting the nodes shards are placed on.
+	Placement string
+
+	// How long the shards of expired shard groups are kept before removal.
+	DeletionDelay time.Duration
+
+	// Maximum time range a SELECT may span. Zero is unlimited.
+	MaxQueryRange time.Duration
 }
 
 // String returns a string representation of the create retention policy.
@@ -948,12 +1479,70 @@ func (s *CreateRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(" SHARD DURATION ")
 		_, _ = buf.WriteString(FormatDuration(s.ShardGroupDuration))
 	}
+	if s.FutureWriteLimit > 0 {
+		_, _ = buf.WriteString(" FUTURE LIMIT ")
+		_, _ = buf.WriteString(FormatDuration(s.FutureWriteLimit))
+	}
+	if s.PastWriteLimit > 0 {
+		_, _ = buf.WriteString(" PAST LIMIT ")
+		_, _ = buf.WriteString(FormatDuration(s.PastWriteLimit))
+	}
+	if s.ColdAfter > 0 {
+		_, _ = buf.WriteString(" COLD AFTER ")
+		_, _ = buf.WriteString(FormatDuration(s.ColdAfter))
+	}
+	if s.Downsample != nil {
+		_, _ = buf.WriteString(" ")
+		_, _ = buf.WriteString(s.Downsample.String())
+	}
+	if s.Placement != "" {
+		_, _ = buf.WriteString(" PLACEMENT ")
+		_, _ = buf.WriteString(QuoteString(s.Placement))
+	}
+	if s.DeletionDelay > 0 {
+		_, _ = buf.WriteString(" DELETION DELAY ")
+		_, _ = buf.WriteString(FormatDuration(s.DeletionDelay))
+	}
+	if s.MaxQueryRange > 0 {
+		_, _ = buf.WriteString(" QUERY RANGE ")
+		_, _ = buf.WriteString(FormatDuration(s.MaxQueryRange))
+	}
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
 	return buf.String()
 }
 
+// Downsample represents the DOWNSAMPLE clause of a retention policy, which
+// periodically aggregates the policy's data into another retention policy.
+type Downsample struct {
+	// Aggregate calls applied to the data, such as mean(*).
+	Calls []*Call
+
+	// Interval the data is aggregated over.
+	Every time.Duration
+
+	// Retention policy the aggregated data is written into.
+	RetentionPolicy string
+}
+
+// String returns a string representation of the downsample clause.
+func (d *Downsample) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DOWNSAMPLE (")
+	for i, call := range d.Calls {
+		if i > 0 {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(call.String())
+	}
+	_, _ = buf.WriteString(") EVERY ")
+	_, _ = buf.WriteString(FormatDuration(d.Every))
+	_, _ = buf.WriteString(" INTO ")
+	_, _ = buf.WriteString(QuoteIdent(d.RetentionPolicy))
+	return buf.String()
+}
+
 // RequiredPrivileges returns the privilege required to execute a CreateRetentionPolicyStatement.
 func (s *CreateRetentionPolicyStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
@@ -964,6 +1553,57 @@ func (s *CreateRetentionPolicyStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// AlterDatabaseStatement represents a command to alter the settings of an
+// existing database.
+type AlterDatabaseStatement struct {
+	// Name of the database to alter.
+	Name string
+
+	// Maximum number of series the database may hold. Zero means unlimited.
+	MaxSeriesN *int64
+
+	// Maximum number of values a tag key may have within a measurement.
+	// Zero means unlimited.
+	MaxValuesPerTag *int64
+
+	// Index used by shards created from now on. Existing shards keep theirs.
+	IndexType *string
+}
+
+// String returns a string representation of the alter database statement.
+func (s *AlterDatabaseStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("ALTER DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" SET")
+
+	if s.MaxSeriesN != nil {
+		_, _ = buf.WriteString(" MAX SERIES ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxSeriesN, 10))
+	}
+
+	if s.MaxValuesPerTag != nil {
+		_, _ = buf.WriteString(" MAX TAG VALUES ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxValuesPerTag, 10))
+	}
+
+	if s.IndexType != nil {
+		_, _ = buf.WriteString(" INDEX ")
+		_, _ = buf.WriteString(QuoteString(*s.IndexType))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterDatabaseStatement.
+func (s *AlterDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *AlterDatabaseStatement) DefaultDatabase() string {
+	return s.Name
+}
+
 // AlterRetentionPolicyStatement represents a command to alter an existing retention policy.
 type AlterRetentionPolicyStatement struct {
 	// Name of policy to alter.
@@ -983,6 +1623,24 @@ type AlterRetentionPolicyStatement struct {
 
 	// Duration of the Shard.
 	ShardGroupDuration *time.Duration
+
+	// How far ahead of the current time points may be written.
+	FutureWriteLimit *time.Duration
+
+	// How far behind the current time points may be written.
+	PastWriteLimit *time.Duration
+
+	// How long after a shard group ends it moves to the cold tier.
+	ColdAfter *time.Duration
+
+	// Node tag selector restricting the nodes shards are placed on.
+	Placement *string
+
+	// How long the shards of expired shard groups are kept before removal.
+	DeletionDelay *time.Duration
+
+	// Maximum time range a SELECT may span.
+	MaxQueryRange *time.Duration
 }
 
 // String returns a string representation of the alter retention policy statement.
@@ -1008,6 +1666,36 @@ func (s *AlterRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(FormatDuration(*s.ShardGroupDuration))
 	}
 
+	if s.FutureWriteLimit != nil {
+		_, _ = buf.WriteString(" FUTURE LIMIT ")
+		_, _ = buf.WriteString(FormatDuration(*s.FutureWriteLimit))
+	}
+
+	if s.PastWriteLimit != nil {
+		_, _ = buf.WriteString(" PAST LIMIT ")
+		_, _ = buf.WriteString(FormatDuration(*s.PastWriteLimit))
+	}
+
+	if s.ColdAfter != nil {
+		_, _ = buf.WriteString(" COLD AFTER ")
+		_, _ = buf.WriteString(FormatDuration(*s.ColdAfter))
+	}
+
+	if s.Placement != nil {
+		_, _ = buf.WriteString(" PLACEMENT ")
+		_, _ = buf.WriteString(QuoteString(*s.Placement))
+	}
+
+	if s.DeletionDelay != nil {
+		_, _ = buf.WriteString(" DELETION DELAY ")
+		_, _ = buf.WriteString(FormatDuration(*s.DeletionDelay))
+	}
+
+	if s.MaxQueryRange != nil {
+		_, _ = buf.WriteString(" QUERY RANGE ")
+		_, _ = buf.WriteString(FormatDuration(*s.MaxQueryRange))
+	}
+
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
@@ -1134,6 +1822,7 @@ func (s *SelectStatement) Clone() *SelectStatement {
 				Name:            s.Target.Measurement.Name,
 				Regex:           CloneRegexLiteral(s.Target.Measurement.Regex),
 			},
+			Create: s.Target.Create,
 		}
 	}
 	for _, f := range s.Fields {
@@ -1822,7 +2511,7 @@ func (s *SelectStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	}
 
 	if s.Target != nil {
-		ep = append(ep, ExecutionPrivilege{Admin: false, Name: s.Target.Measurement.Database, Privilege: WritePrivilege})
+		ep = append(ep, ExecutionPrivilege{Admin: false, Name: s.Target.Measurement.Database, Privilege: s.Target.RequiredPrivilege()})
 	}
 	return ep, nil
 }
@@ -2070,6 +2759,9 @@ func ExprNames(expr Expr) []VarRef {
 type Target struct {
 	// Measurement to write into.
 	Measurement *Measurement
+
+	// Create the retention policy of the measurement if it does not exist.
+	Create bool
 }
 
 // String returns a string representation of the Target.
@@ -2081,17 +2773,49 @@ func (t *Target) String() string {
 	var buf strings.Builder
 	_, _ = buf.WriteString("INTO ")
 	_, _ = buf.WriteString(t.Measurement.String())
-	if t.Measurement.Name == "" {
+	if t.IsBackReference() {
 		_, _ = buf.WriteString(":MEASUREMENT")
 	}
+	if t.Create {
+		_, _ = buf.WriteString(" WITH CREATE")
+	}
+
+	return buf.String()
+}
+
+// RequiredPrivilege returns the privilege required on the database of the
+// target. Creating a missing retention policy requires all privileges.
+func (t *Target) RequiredPrivilege() Privilege {
+	if t.Create {
+		return AllPrivileges
+	}
+	return WritePrivilege
+}
+
+// IsBackReference returns true if the target is written with the :MEASUREMENT
+// back-reference, which writes each result into a measurement named after the
+// measurement it was read from.
+func (t *Target) IsBackReference() bool {
+	return t.Measurement.Name == ""
+}
 
-	return buf.String()
+// MeasurementName returns the name of the measurement a result read from
+// source is written into.
+func (t *Target) MeasurementName(source string) string {
+	if t.IsBackReference() {
+		return source
+	}
+	return t.Measurement.Name
 }
 
 // ExplainStatement represents a command for explaining a select statement.
 type ExplainStatement struct {
 	Statement *SelectStatement
 
+	// Statement explained instead of a SELECT, either a *DeleteSeriesStatement
+	// or a *DropSeriesStatement. Explaining it doesn't delete anything.
+	SeriesStatement Statement
+
 	Analyze bool
 }
 
@@ -2102,12 +2826,21 @@ func (e *ExplainStatement) String() string {
 	if e.Analyze {
 		buf.WriteString("ANALYZE ")
 	}
-	buf.WriteString(e.Statement.String())
+	if e.SeriesStatement != nil {
+		buf.WriteString(e.SeriesStatement.String())
+	} else {
+		buf.WriteString(e.Statement.String())
+	}
 	return buf.String()
 }
 
 // RequiredPrivileges returns the privilege required to execute a ExplainStatement.
+// Explaining a DELETE or DROP SERIES only reads the index, so it requires read
+// privilege.
 func (e *ExplainStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	if e.SeriesStatement != nil {
+		return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+	}
 	return e.Statement.RequiredPrivileges()
 }
 
@@ -2294,6 +3027,102 @@ func (s *DropShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// TruncateShardsStatement represents a command for truncating all open shard
+// groups so that writes after the delay create new shard groups.
+type TruncateShardsStatement struct {
+	// Delay after the current time at which the shard groups are truncated.
+	Delay time.Duration
+}
+
+// String returns a string representation of the truncate shards statement.
+func (s *TruncateShardsStatement) String() string {
+	var buf strings.Builder
+	buf.WriteString("TRUNCATE SHARDS ")
+	buf.WriteString(FormatDuration(s.Delay))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// TruncateShardsStatement.
+func (s *TruncateShardsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// PrecreateShardGroupsStatement represents a command for creating the shard
+// groups of every retention policy from now until a duration from now.
+type PrecreateShardGroupsStatement struct {
+	// Duration after the current time the shard groups must cover.
+	Duration time.Duration
+}
+
+// String returns a string representation of the precreate shard groups statement.
+func (s *PrecreateShardGroupsStatement) String() string {
+	var buf strings.Builder
+	buf.WriteString("PRECREATE SHARD GROUPS FOR ")
+	buf.WriteString(FormatDuration(s.Duration))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// PrecreateShardGroupsStatement.
+func (s *PrecreateShardGroupsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// UndropShardGroupStatement represents a command for restoring a shard
+// group that was deleted but whose shards have not been removed yet.
+type UndropShardGroupStatement struct {
+	// ID of the shard group to restore.
+	ID uint64
+}
+
+// String returns a string representation of the undrop shard group statement.
+func (s *UndropShardGroupStatement) String() string {
+	var buf strings.Builder
+	buf.WriteString("UNDROP SHARD GROUP ")
+	buf.WriteString(strconv.FormatUint(s.ID, 10))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an
+// UndropShardGroupStatement.
+func (s *UndropShardGroupStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// PurgeDataStatement represents a command for deleting all data in a
+// database older than a cutoff time.
+type PurgeDataStatement struct {
+	// Database to purge. If empty, the default database is used.
+	Database string
+
+	// Time before which all data is deleted.
+	Time Expr
+}
+
+// String returns a string representation of the purge data statement.
+func (s *PurgeDataStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("PURGE DATA")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	_, _ = buf.WriteString(" BEFORE ")
+	_, _ = buf.WriteString(s.Time.String())
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a PurgeDataStatement.
+func (s *PurgeDataStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *PurgeDataStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // ShowSeriesCardinalityStatement represents a command for listing series cardinality.
 type ShowSeriesCardinalityStatement struct {
 	// Database to query. If blank, use the default database.
@@ -2366,20 +3195,32 @@ func (s *ShowSeriesCardinalityStatement) DefaultDatabase() string {
 }
 
 // ShowContinuousQueriesStatement represents a command for listing continuous queries.
-type ShowContinuousQueriesStatement struct{}
+type ShowContinuousQueriesStatement struct {
+	// Database to list continuous queries for. All databases if blank.
+	Database string
+}
 
 // String returns a string representation of the show continuous queries statement.
-func (s *ShowContinuousQueriesStatement) String() string { return "SHOW CONTINUOUS QUERIES" }
+func (s *ShowContinuousQueriesStatement) String() string {
+	if s.Database != "" {
+		return "SHOW CONTINUOUS QUERIES ON " + QuoteIdent(s.Database)
+	}
+	return "SHOW CONTINUOUS QUERIES"
+}
 
 // RequiredPrivileges returns the privilege required to execute a ShowContinuousQueriesStatement.
 func (s *ShowContinuousQueriesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
-	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: ReadPrivilege}}, nil
 }
 
 // ShowGrantsForUserStatement represents a command for listing user privileges.
 type ShowGrantsForUserStatement struct {
 	// Name of the user to display privileges.
 	Name string
+
+	// Also show the grants on retention policies, measurements and through
+	// roles, and whether the user is an admin.
+	Verbose bool
 }
 
 // String returns a string representation of the show grants for user.
@@ -2387,6 +3228,9 @@ func (s *ShowGrantsForUserStatement) String() string {
 	var buf strings.Builder
 	_, _ = buf.WriteString("SHOW GRANTS FOR ")
 	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	if s.Verbose {
+		_, _ = buf.WriteString(" VERBOSE")
+	}
 
 	return buf.String()
 }
@@ -2396,11 +3240,76 @@ func (s *ShowGrantsForUserStatement) RequiredPrivileges() (ExecutionPrivileges,
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// ShowGrantsForRoleStatement represents a command for listing role privileges.
+type ShowGrantsForRoleStatement struct {
+	// Name of the role to display privileges.
+	Name string
+}
+
+// String returns a string representation of the show grants for role.
+func (s *ShowGrantsForRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW GRANTS FOR ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowGrantsForRoleStatement
+func (s *ShowGrantsForRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowRolesStatement represents a command for listing roles.
+type ShowRolesStatement struct{}
+
+// String returns a string representation of the ShowRolesStatement.
+func (s *ShowRolesStatement) String() string {
+	return "SHOW ROLES"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowRolesStatement
+func (s *ShowRolesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowTokensStatement represents a command for listing API tokens.
+type ShowTokensStatement struct {
+	// Name of the user whose tokens are listed.
+	// Blank for the tokens visible to the user running the statement.
+	User string
+}
+
+// String returns a string representation of the ShowTokensStatement.
+func (s *ShowTokensStatement) String() string {
+	if s.User != "" {
+		return "SHOW TOKENS FOR " + QuoteIdent(s.User)
+	}
+	return "SHOW TOKENS"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowTokensStatement.
+// Users may list their own tokens, only admins may list the tokens of other users.
+func (s *ShowTokensStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	if s.User != "" {
+		return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+	}
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
 // ShowDatabasesStatement represents a command for listing all databases in the cluster.
-type ShowDatabasesStatement struct{}
+type ShowDatabasesStatement struct {
+	// Include retention policy and continuous query details per database.
+	WithDetail bool
+}
 
 // String returns a string representation of the show databases command.
-func (s *ShowDatabasesStatement) String() string { return "SHOW DATABASES" }
+func (s *ShowDatabasesStatement) String() string {
+	if s.WithDetail {
+		return "SHOW DATABASES WITH DETAIL"
+	}
+	return "SHOW DATABASES"
+}
 
 // RequiredPrivileges returns the privilege required to execute a ShowDatabasesStatement.
 func (s *ShowDatabasesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
@@ -2426,6 +3335,9 @@ type CreateContinuousQueryStatement struct {
 
 	// Maximum duration to resample previous queries.
 	ResampleFor time.Duration
+
+	// Delay of every execution after its scheduled time.
+	Offset time.Duration
 }
 
 // String returns a string representation of the statement.
@@ -2442,6 +3354,9 @@ func (s *CreateContinuousQueryStatement) String() string {
 			fmt.Fprintf(&buf, "FOR %s ", FormatDuration(s.ResampleFor))
 		}
 	}
+	if s.Offset > 0 {
+		fmt.Fprintf(&buf, "OFFSET %s ", FormatDuration(s.Offset))
+	}
 	fmt.Fprintf(&buf, "BEGIN %s END", s.Source.String())
 	return buf.String()
 }
@@ -2472,23 +3387,63 @@ func (s *CreateContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileg
 	return ep, nil
 }
 
-func (s *CreateContinuousQueryStatement) validate() error {
+// Validate checks that the RESAMPLE clause is consistent with the GROUP BY
+// interval of the query: FOR must cover at least one full interval and
+// EVERY must not be larger than FOR. The OFFSET must be shorter than the
+// interval the query runs at, so an execution starts before the next one
+// is due.
+func (s *CreateContinuousQueryStatement) Validate() error {
 	interval, err := s.Source.GroupByInterval()
 	if err != nil {
 		return err
 	}
 
 	if s.ResampleFor != 0 {
-		if s.ResampleEvery != 0 && s.ResampleEvery > interval {
-			interval = s.ResampleEvery
-		}
 		if interval > s.ResampleFor {
 			return fmt.Errorf("FOR duration must be >= GROUP BY time duration: must be a minimum of %s, got %s", FormatDuration(interval), FormatDuration(s.ResampleFor))
 		}
+		if s.ResampleEvery > s.ResampleFor {
+			return fmt.Errorf("EVERY duration must be <= FOR duration: must be a maximum of %s, got %s", FormatDuration(s.ResampleFor), FormatDuration(s.ResampleEvery))
+		}
+	}
+
+	if s.Offset > 0 {
+		every := interval
+		if s.ResampleEvery != 0 {
+			every = s.ResampleEvery
+		}
+		if s.Offset >= every {
+			return fmt.Errorf("OFFSET duration must be < run interval: must be less than %s, got %s", FormatDuration(every), FormatDuration(s.Offset))
+		}
 	}
 	return nil
 }
 
+// AlterContinuousQueryStatement represents a command for changing the
+// execution offset of a continuous query.
+type AlterContinuousQueryStatement struct {
+	Name     string
+	Database string
+
+	// Delay of every execution after its scheduled time.
+	Offset time.Duration
+}
+
+// String returns a string representation of the statement.
+func (s *AlterContinuousQueryStatement) String() string {
+	return fmt.Sprintf("ALTER CONTINUOUS QUERY %s ON %s OFFSET %s", QuoteIdent(s.Name), QuoteIdent(s.Database), FormatDuration(s.Offset))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute an AlterContinuousQueryStatement.
+func (s *AlterContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *AlterContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // DropContinuousQueryStatement represents a command for removing a continuous query.
 type DropContinuousQueryStatement struct {
 	Name     string
@@ -2510,6 +3465,35 @@ func (s *DropContinuousQueryStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// RunContinuousQueryStatement represents a command for running a continuous
+// query over a past time range to backfill its target.
+type RunContinuousQueryStatement struct {
+	Name     string
+	Database string
+
+	// Time range to backfill. Start is inclusive and End exclusive.
+	Start Expr
+	End   Expr
+}
+
+// String returns a string representation of the statement.
+func (s *RunContinuousQueryStatement) String() string {
+	return fmt.Sprintf("RUN CONTINUOUS QUERY %s ON %s BETWEEN %s AND %s", QuoteIdent(s.Name), QuoteIdent(s.Database), s.Start, s.End)
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a RunContinuousQueryStatement.
+func (s *RunContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{
+		{Admin: false, Name: s.Database, Privilege: ReadPrivilege},
+		{Admin: false, Name: s.Database, Privilege: WritePrivilege},
+	}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *RunContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // ShowMeasurementCardinalityStatement represents a command for listing measurement cardinality.
 type ShowMeasurementCardinalityStatement struct {
 	Exact         bool // If false then cardinality estimation will be used.
@@ -2580,6 +3564,13 @@ type ShowMeasurementsStatement struct {
 
 	WildcardDatabase        bool
 	WildcardRetentionPolicy bool
+
+	// Include the series count of each measurement, sorted by it (WITH COUNTS).
+	WithCounts bool
+
+	// Return only the number of matching measurements (EXACT COUNT).
+	ExactCount bool
+
 	// Measurement name or regex.
 	Source Source
 
@@ -2616,6 +3607,12 @@ func (s *ShowMeasurementsStatement) String() string {
 			_, _ = buf.WriteString(s.RetentionPolicy)
 		}
 	}
+	if s.ExactCount {
+		_, _ = buf.WriteString(" EXACT COUNT")
+	}
+	if s.WithCounts {
+		_, _ = buf.WriteString(" WITH COUNTS")
+	}
 	if s.Source != nil {
 		_, _ = buf.WriteString(" WITH MEASUREMENT ")
 		if m, ok := s.Source.(*Measurement); ok && m.Regex != nil {
@@ -2674,11 +3671,20 @@ func (s *DropMeasurementStatement) RequiredPrivileges() (ExecutionPrivileges, er
 }
 
 // ShowQueriesStatement represents a command for listing all running queries.
-type ShowQueriesStatement struct{}
+type ShowQueriesStatement struct {
+	// An expression evaluated on the columns of each query.
+	Condition Expr
+}
 
 // String returns a string representation of the show queries statement.
 func (s *ShowQueriesStatement) String() string {
-	return "SHOW QUERIES"
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW QUERIES")
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	return buf.String()
 }
 
 // RequiredPrivileges returns the privilege required to execute a ShowQueriesStatement.
@@ -2686,6 +3692,31 @@ func (s *ShowQueriesStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
+// ShowSessionsStatement represents a command for listing client sessions.
+type ShowSessionsStatement struct{}
+
+// String returns a string representation of the show sessions statement.
+func (s *ShowSessionsStatement) String() string { return "SHOW SESSIONS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowSessionsStatement.
+// Users other than admins only see their own sessions.
+func (s *ShowSessionsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
+// ShowOperationsStatement represents a command for listing the destructive
+// operations, such as drops, deletes and backups, running or waiting for a
+// conflicting operation to finish.
+type ShowOperationsStatement struct{}
+
+// String returns a string representation of the show operations statement.
+func (s *ShowOperationsStatement) String() string { return "SHOW OPERATIONS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowOperationsStatement.
+func (s *ShowOperationsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowRetentionPoliciesStatement represents a command for listing retention policies.
 type ShowRetentionPoliciesStatement struct {
 	// Name of the database to list policies for.
@@ -2713,46 +3744,131 @@ func (s *ShowRetentionPoliciesStatement) DefaultDatabase() string {
 	return s.Database
 }
 
-// ShowStatsStatement displays statistics for a given module.
+// ShowStatsStatement displays statistics for the given modules.
 type ShowStatsStatement struct {
-	Module string
+	// Modules to display. All modules if blank.
+	Modules []string
+
+	// Regex matching the modules to display. Used instead of Modules.
+	ModuleRegex *RegexLiteral
 }
 
 // String returns a string representation of a ShowStatsStatement.
 func (s *ShowStatsStatement) String() string {
 	var buf strings.Builder
 	_, _ = buf.WriteString("SHOW STATS")
-	if s.Module != "" {
+	if s.ModuleRegex != nil {
 		_, _ = buf.WriteString(" FOR ")
-		_, _ = buf.WriteString(QuoteString(s.Module))
+		_, _ = buf.WriteString(s.ModuleRegex.String())
+	} else if len(s.Modules) > 0 {
+		_, _ = buf.WriteString(" FOR ")
+		for i, module := range s.Modules {
+			if i > 0 {
+				_, _ = buf.WriteString(", ")
+			}
+			_, _ = buf.WriteString(QuoteString(module))
+		}
 	}
 	return buf.String()
 }
 
+// MatchModule returns true if the statistics of module should be displayed.
+func (s *ShowStatsStatement) MatchModule(module string) bool {
+	if s.ModuleRegex != nil {
+		return s.ModuleRegex.Val.MatchString(module)
+	}
+	if len(s.Modules) == 0 {
+		return true
+	}
+	for _, m := range s.Modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
 // RequiredPrivileges returns the privilege(s) required to execute a ShowStatsStatement
 func (s *ShowStatsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
 // ShowShardGroupsStatement represents a command for displaying shard groups in the cluster.
-type ShowShardGroupsStatement struct{}
+type ShowShardGroupsStatement struct {
+	// Database to list shard groups for. All databases if blank.
+	Database string
+
+	// An expression evaluated on the time range of each shard group.
+	Condition Expr
+
+	// Time zone used to render timestamps. UTC if nil.
+	Location *time.Location
+}
 
 // String returns a string representation of the SHOW SHARD GROUPS command.
-func (s *ShowShardGroupsStatement) String() string { return "SHOW SHARD GROUPS" }
+func (s *ShowShardGroupsStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW SHARD GROUPS")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	if s.Location != nil {
+		_, _ = fmt.Fprintf(&buf, " TZ('%s')", s.Location)
+	}
+	return buf.String()
+}
 
 // RequiredPrivileges returns the privileges required to execute the statement.
+// Listing the shard groups of a single database only requires read access to it.
 func (s *ShowShardGroupsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	if s.Database != "" {
+		return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: ReadPrivilege}}, nil
+	}
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
 // ShowShardsStatement represents a command for displaying shards in the cluster.
-type ShowShardsStatement struct{}
+type ShowShardsStatement struct {
+	// Database to list shards for. All databases if blank.
+	Database string
+
+	// Retention policy to list shards for. All retention policies of
+	// Database if blank.
+	RetentionPolicy string
+
+	// Time zone used to render timestamps. UTC if nil.
+	Location *time.Location
+}
 
 // String returns a string representation.
-func (s *ShowShardsStatement) String() string { return "SHOW SHARDS" }
+func (s *ShowShardsStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW SHARDS")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		if s.RetentionPolicy != "" {
+			_, _ = buf.WriteString(QuoteIdent(s.Database, s.RetentionPolicy))
+		} else {
+			_, _ = buf.WriteString(QuoteIdent(s.Database))
+		}
+	}
+	if s.Location != nil {
+		_, _ = fmt.Fprintf(&buf, " TZ('%s')", s.Location)
+	}
+	return buf.String()
+}
 
 // RequiredPrivileges returns the privileges required to execute the statement.
+// Listing the shards of a single database only requires read access to it.
 func (s *ShowShardsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	if s.Database != "" {
+		return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: ReadPrivilege}}, nil
+	}
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
@@ -2785,6 +3901,23 @@ type CreateSubscriptionStatement struct {
 	RetentionPolicy string
 	Destinations    []string
 	Mode            string
+
+	// Weights holds the weight of each destination in BALANCE mode, where
+	// writes are spread across the destinations in proportion to their
+	// weights. Destinations weighted 0 are standbys, only written to when
+	// the others fail. Nil in the other modes.
+	Weights []int
+
+	// Filter restricts the points forwarded to the destinations. It is
+	// either a regex matched against measurement names or a condition on
+	// tags, where _name refers to the measurement. Nil forwards every point.
+	Filter Expr
+
+	// Files of the CA certificates used to verify HTTPS destinations and of
+	// the client certificate and key presented to them.
+	CA   string
+	Cert string
+	Key  string
 }
 
 // String returns a string representation of the CreateSubscriptionStatement.
@@ -2803,12 +3936,75 @@ func (s *CreateSubscriptionStatement) String() string {
 		if i != 0 {
 			_, _ = buf.WriteString(", ")
 		}
-		_, _ = buf.WriteString(QuoteString(dest))
+		_, _ = buf.WriteString(QuoteString(SanitizeURL(dest)))
+		if i < len(s.Weights) {
+			_, _ = buf.WriteString(" WEIGHT ")
+			_, _ = buf.WriteString(strconv.Itoa(s.Weights[i]))
+		}
+	}
+	if s.CA != "" || s.Cert != "" || s.Key != "" {
+		_, _ = buf.WriteString(" WITH")
+		if s.CA != "" {
+			_, _ = buf.WriteString(" CA ")
+			_, _ = buf.WriteString(QuoteString(s.CA))
+		}
+		if s.Cert != "" {
+			_, _ = buf.WriteString(" CERT ")
+			_, _ = buf.WriteString(QuoteString(s.Cert))
+		}
+		if s.Key != "" {
+			_, _ = buf.WriteString(" KEY ")
+			_, _ = buf.WriteString(QuoteString(s.Key))
+		}
+	}
+	if s.Filter != nil {
+		_, _ = buf.WriteString(" FILTER ")
+		_, _ = buf.WriteString(s.Filter.String())
 	}
 
 	return buf.String()
 }
 
+// ValidateSubscriptionFilter returns an error if expr is not a valid
+// subscription filter: a regex, or comparisons of tags with strings or
+// regexes combined with AND and OR.
+func ValidateSubscriptionFilter(expr Expr) error {
+	if _, ok := expr.(*RegexLiteral); ok {
+		return nil
+	}
+	return validateSubscriptionCondition(expr)
+}
+
+func validateSubscriptionCondition(expr Expr) error {
+	switch expr := expr.(type) {
+	case *ParenExpr:
+		return validateSubscriptionCondition(expr.Expr)
+	case *BinaryExpr:
+		switch expr.Op {
+		case AND, OR:
+			if err := validateSubscriptionCondition(expr.LHS); err != nil {
+				return err
+			}
+			return validateSubscriptionCondition(expr.RHS)
+		case EQ, NEQ:
+			if _, ok := expr.RHS.(*StringLiteral); !ok {
+				return fmt.Errorf("invalid subscription filter: %s, tags must be compared with a string", expr)
+			}
+		case EQREGEX, NEQREGEX:
+			if _, ok := expr.RHS.(*RegexLiteral); !ok {
+				return fmt.Errorf("invalid subscription filter: %s, tags must be matched against a regex", expr)
+			}
+		default:
+			return fmt.Errorf("invalid subscription filter: %s, unsupported operator %s", expr, expr.Op)
+		}
+		if _, ok := expr.LHS.(*VarRef); !ok {
+			return fmt.Errorf("invalid subscription filter: %s, expected a tag key", expr)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid subscription filter: %s, expected a regex or a tag condition", expr)
+}
+
 // RequiredPrivileges returns the privilege required to execute a CreateSubscriptionStatement.
 func (s *CreateSubscriptionStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
@@ -3149,10 +4345,21 @@ func (s *ShowTagValuesCardinalityStatement) DefaultDatabase() string {
 }
 
 // ShowUsersStatement represents a command for listing users.
-type ShowUsersStatement struct{}
+type ShowUsersStatement struct {
+	// Include the database privileges of each user.
+	WithGrants bool
+
+	// Include when each user was created and last logged in.
+	WithDetail bool
+}
 
 // String returns a string representation of the ShowUsersStatement.
 func (s *ShowUsersStatement) String() string {
+	if s.WithGrants {
+		return "SHOW USERS WITH GRANTS"
+	} else if s.WithDetail {
+		return "SHOW USERS WITH DETAIL"
+	}
 	return "SHOW USERS"
 }
 
@@ -3930,7 +5137,11 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Condition)
 
 	case *ExplainStatement:
-		Walk(v, n.Statement)
+		if n.SeriesStatement != nil {
+			Walk(v, n.SeriesStatement)
+		} else {
+			Walk(v, n.Statement)
+		}
 
 	case *Field:
 		Walk(v, n.Expr)
@@ -3962,6 +5173,12 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
 
+	case *ShowShardGroupsStatement:
+		Walk(v, n.Condition)
+
+	case *ShowQueriesStatement:
+		Walk(v, n.Condition)
+
 	case *ShowSeriesCardinalityStatement:
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
@@ -125,6 +125,10 @@ func init() {
 			})
 		})
 		show.Group(GRANTS).Handle(FOR, func(p *Parser) (Statement, error) {
+			if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+				return p.parseGrantsForRoleStatement()
+			}
+			p.Unscan()
 			return p.parseGrantsForUserStatement()
 		})
 		show.Group(MEASUREMENT).Handle(EXACT, func(p *Parser) (Statement, error) {
@@ -136,15 +140,24 @@ func init() {
 		show.Handle(MEASUREMENTS, func(p *Parser) (Statement, error) {
 			return p.parseShowMeasurementsStatement()
 		})
+		show.Handle(OPERATIONS, func(p *Parser) (Statement, error) {
+			return &ShowOperationsStatement{}, nil
+		})
 		show.Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowQueriesStatement()
 		})
 		show.Group(RETENTION).Handle(POLICIES, func(p *Parser) (Statement, error) {
 			return p.parseShowRetentionPoliciesStatement()
 		})
+		show.Handle(ROLES, func(p *Parser) (Statement, error) {
+			return &ShowRolesStatement{}, nil
+		})
 		show.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowSeriesStatement()
 		})
+		show.Handle(SESSIONS, func(p *Parser) (Statement, error) {
+			return &ShowSessionsStatement{}, nil
+		})
 		show.Group(SHARD).Handle(GROUPS, func(p *Parser) (Statement, error) {
 			return p.parseShowShardGroupsStatement()
 		})
@@ -168,6 +181,9 @@ func init() {
 				return p.parseShowTagValuesStatement()
 			})
 		})
+		show.Handle(TOKENS, func(p *Parser) (Statement, error) {
+			return p.parseShowTokensStatement()
+		})
 		show.Handle(USERS, func(p *Parser) (Statement, error) {
 			return p.parseShowUsersStatement()
 		})
@@ -188,6 +204,12 @@ func init() {
 		create.Handle(SUBSCRIPTION, func(p *Parser) (Statement, error) {
 			return p.parseCreateSubscriptionStatement()
 		})
+		create.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseCreateRoleStatement()
+		})
+		create.Handle(TOKEN, func(p *Parser) (Statement, error) {
+			return p.parseCreateTokenStatement()
+		})
 	})
 	Language.Group(DROP).With(func(drop *ParseTree) {
 		drop.Group(CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
@@ -202,6 +224,9 @@ func init() {
 		drop.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 			return p.parseDropRetentionPolicyStatement()
 		})
+		drop.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseDropRoleStatement()
+		})
 		drop.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseDropSeriesStatement()
 		})
@@ -227,10 +252,37 @@ func init() {
 	Language.Group(ALTER, RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 		return p.parseAlterRetentionPolicyStatement()
 	})
+	Language.Group(ALTER).Handle(DATABASE, func(p *Parser) (Statement, error) {
+		return p.parseAlterDatabaseStatement()
+	})
+	Language.Group(ALTER, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseAlterContinuousQueryStatement()
+	})
+	Language.Group(ALTER).Handle(USER, func(p *Parser) (Statement, error) {
+		return p.parseAlterUserStatement()
+	})
 	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
 		return p.parseSetPasswordUserStatement()
 	})
 	Language.Group(KILL).Handle(QUERY, func(p *Parser) (Statement, error) {
 		return p.parseKillQueryStatement()
 	})
+	Language.Group(KILL).Handle(SESSION, func(p *Parser) (Statement, error) {
+		return p.parseKillSessionStatement()
+	})
+	Language.Group(TRUNCATE).Handle(SHARDS, func(p *Parser) (Statement, error) {
+		return p.parseTruncateShardsStatement()
+	})
+	Language.Group(PRECREATE, SHARD).Handle(GROUPS, func(p *Parser) (Statement, error) {
+		return p.parsePrecreateShardGroupsStatement()
+	})
+	Language.Handle(PURGE, func(p *Parser) (Statement, error) {
+		return p.parsePurgeDataStatement()
+	})
+	Language.Group(UNDROP, SHARD).Handle(GROUP, func(p *Parser) (Statement, error) {
+		return p.parseUndropShardGroupStatement()
+	})
+	Language.Group(RUN, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseRunContinuousQueryStatement()
+	})
 }
@@ -124,6 +124,120 @@ func (p *Parser) parseSetPasswordUserStatement() (*SetPasswordUserStatement, err
 	return stmt, nil
 }
 
+// parseAlterUserStatement parses a string and returns an AlterUserStatement
+// or an AlterUserQueryLimitStatement.
+// This function assumes the "ALTER USER" tokens have already been consumed.
+func (p *Parser) parseAlterUserStatement() (Statement, error) {
+	// Parse username
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse LOCK, UNLOCK, WITH QUERY LIMIT or SET DEFAULT DATABASE.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch {
+	case tok == IDENT && strings.ToUpper(lit) == "LOCK":
+		return &AlterUserStatement{Name: ident, Locked: true}, nil
+	case tok == IDENT && strings.ToUpper(lit) == "UNLOCK":
+		return &AlterUserStatement{Name: ident}, nil
+	case tok == WITH:
+		return p.parseAlterUserQueryLimitStatement(ident)
+	case tok == SET:
+		return p.parseAlterUserDefaultDatabaseStatement(ident)
+	}
+	return nil, newParseError(tokstr(tok, lit), []string{"LOCK", "UNLOCK", "WITH", "SET"}, pos)
+}
+
+// parseAlterUserDefaultDatabaseStatement parses the default database and
+// retention policy of a user.
+// This function assumes the "ALTER USER <name> SET" tokens have already been consumed.
+func (p *Parser) parseAlterUserDefaultDatabaseStatement(name string) (*AlterUserDefaultDatabaseStatement, error) {
+	stmt := &AlterUserDefaultDatabaseStatement{Name: name}
+
+	if err := p.parseTokens([]Token{DEFAULT, DATABASE}); err != nil {
+		return nil, err
+	}
+	db, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Database = db
+
+	// Parse optional RETENTION POLICY.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != RETENTION {
+		p.Unscan()
+		return stmt, nil
+	}
+	if err := p.parseTokens([]Token{POLICY}); err != nil {
+		return nil, err
+	}
+	if stmt.RetentionPolicy, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseAlterUserQueryLimitStatement parses the query limits of a user.
+// This function assumes the "ALTER USER <name> WITH" tokens have already been consumed.
+func (p *Parser) parseAlterUserQueryLimitStatement(name string) (*AlterUserQueryLimitStatement, error) {
+	stmt := &AlterUserQueryLimitStatement{Name: name}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != QUERY {
+		return nil, newParseError(tokstr(tok, lit), []string{"QUERY"}, pos)
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != LIMIT {
+		return nil, newParseError(tokstr(tok, lit), []string{"LIMIT"}, pos)
+	}
+
+	// Parse one or more CONCURRENT, PER MINUTE or PRIORITY limits.
+	for i := 0; ; i++ {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok == IDENT && strings.ToUpper(lit) == "PRIORITY" {
+			if stmt.Priority != "" {
+				return nil, &ParseError{Message: "found duplicate PRIORITY option", Pos: pos}
+			}
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			priority := strings.ToLower(lit)
+			if tok != IDENT || (priority != "low" && priority != "normal" && priority != "high") {
+				return nil, newParseError(tokstr(tok, lit), []string{"LOW", "NORMAL", "HIGH"}, pos)
+			}
+			stmt.Priority = priority
+			continue
+		} else if tok == IDENT && strings.ToUpper(lit) == "CONCURRENT" {
+			if stmt.MaxConcurrentQueries != nil {
+				return nil, &ParseError{Message: "found duplicate CONCURRENT option", Pos: pos}
+			}
+			n, err := p.parseLimit()
+			if err != nil {
+				return nil, err
+			}
+			stmt.MaxConcurrentQueries = &n
+			continue
+		} else if tok != IDENT || strings.ToUpper(lit) != "PER" {
+			if i > 0 {
+				p.Unscan()
+				break
+			}
+			return nil, newParseError(tokstr(tok, lit), []string{"CONCURRENT", "PER", "PRIORITY"}, pos)
+		}
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "MINUTE" {
+			return nil, newParseError(tokstr(tok, lit), []string{"MINUTE"}, pos)
+		}
+		if stmt.MaxQueriesPerMinute != nil {
+			return nil, &ParseError{Message: "found duplicate PER MINUTE option", Pos: pos}
+		}
+		n, err := p.parseLimit()
+		if err != nil {
+			return nil, err
+		}
+		stmt.MaxQueriesPerMinute = &n
+	}
+
+	return stmt, nil
+}
+
 // parseKillQueryStatement parses a string and returns a kill statement.
 // This function assumes the KILL token has already been consumed.
 func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
@@ -144,6 +258,16 @@ func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
 	return &KillQueryStatement{QueryID: qid, Host: host}, nil
 }
 
+// parseKillSessionStatement parses a string and returns a kill session statement.
+// This function assumes the KILL SESSION tokens have already been consumed.
+func (p *Parser) parseKillSessionStatement() (*KillSessionStatement, error) {
+	id, err := p.ParseUInt64()
+	if err != nil {
+		return nil, err
+	}
+	return &KillSessionStatement{SessionID: id}, nil
+}
+
 // parseCreateSubscriptionStatement parses a string and returns a CreateSubscriptionStatement.
 // This function assumes the "CREATE SUBSCRIPTION" tokens have already been consumed.
 func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatement, error) {
@@ -182,23 +306,142 @@ func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatemen
 		return nil, newParseError(tokstr(tok, lit), []string{"DESTINATIONS"}, pos)
 	}
 
-	// Expect one of "ANY ALL" keywords.
-	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok == ALL || tok == ANY {
+	// Expect one of "ANY ALL BALANCE" keywords.
+	switch tok, pos, lit := p.ScanIgnoreWhitespace(); {
+	case tok == ALL || tok == ANY:
 		stmt.Mode = tokens[tok]
-	} else {
-		return nil, newParseError(tokstr(tok, lit), []string{"ALL", "ANY"}, pos)
+	case tok == IDENT && strings.ToUpper(lit) == "BALANCE":
+		stmt.Mode = "BALANCE"
+	default:
+		return nil, newParseError(tokstr(tok, lit), []string{"ALL", "ANY", "BALANCE"}, pos)
 	}
 
 	// Read list of destinations.
-	var destinations []string
-	if destinations, err = p.parseStringList(); err != nil {
+	if stmt.Mode == "BALANCE" {
+		if stmt.Destinations, stmt.Weights, err = p.parseWeightedDestinations(); err != nil {
+			return nil, err
+		}
+	} else if stmt.Destinations, err = p.parseStringList(); err != nil {
 		return nil, err
 	}
-	stmt.Destinations = destinations
+
+	// Parse optional TLS options.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		if err := p.parseSubscriptionTLSOptions(stmt); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional FILTER clause.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "FILTER" {
+		if stmt.Filter, err = p.parseSubscriptionFilter(pos); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
 
 	return stmt, nil
 }
 
+// parseWeightedDestinations parses the destinations of a BALANCE
+// subscription, each followed by an optional WEIGHT defaulting to 1.
+func (p *Parser) parseWeightedDestinations() ([]string, []int, error) {
+	var destinations []string
+	var weights []int
+	for {
+		dest, err := p.parseString()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		weight := 1
+		if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "WEIGHT" {
+			if weight, err = p.ParseInt(0, math.MaxInt32); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			p.Unscan()
+		}
+		destinations = append(destinations, dest)
+		weights = append(weights, weight)
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+			p.Unscan()
+			return destinations, weights, nil
+		}
+	}
+}
+
+// parseSubscriptionTLSOptions parses the CA, CERT and KEY options of a
+// subscription, in any order. This function assumes WITH has been consumed.
+func (p *Parser) parseSubscriptionTLSOptions(stmt *CreateSubscriptionStatement) error {
+	found := false
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		var opt *string
+		switch {
+		case tok == IDENT && strings.ToUpper(lit) == "CA":
+			opt = &stmt.CA
+		case tok == IDENT && strings.ToUpper(lit) == "CERT":
+			opt = &stmt.Cert
+		case tok == KEY:
+			opt = &stmt.Key
+		default:
+			if !found {
+				return newParseError(tokstr(tok, lit), []string{"CA", "CERT", "KEY"}, pos)
+			}
+			p.Unscan()
+			return nil
+		}
+
+		if *opt != "" {
+			return &ParseError{Message: fmt.Sprintf("found duplicate %s option", strings.ToUpper(lit)), Pos: pos}
+		}
+		v, err := p.parseString()
+		if err != nil {
+			return err
+		} else if v == "" {
+			return &ParseError{Message: fmt.Sprintf("%s file must not be empty", strings.ToUpper(lit)), Pos: pos}
+		}
+		*opt = v
+		found = true
+	}
+}
+
+// ParseSubscriptionFilter parses and validates the filter of a subscription.
+func ParseSubscriptionFilter(s string) (Expr, error) {
+	p := NewParser(strings.NewReader(s))
+	expr, err := p.parseSubscriptionFilter(Pos{})
+	if err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != EOF {
+		return nil, newParseError(tokstr(tok, lit), []string{"EOF"}, pos)
+	}
+	return expr, nil
+}
+
+// parseSubscriptionFilter parses a regex matching measurement names or a
+// condition on tags. Validation errors are reported at pos.
+func (p *Parser) parseSubscriptionFilter(pos Pos) (Expr, error) {
+	var expr Expr
+	if re, err := p.parseRegex(); err != nil {
+		return nil, err
+	} else if re != nil {
+		expr = re
+	} else if expr, err = p.ParseExpr(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateSubscriptionFilter(expr); err != nil {
+		return nil, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return expr, nil
+}
+
 // parseCreateRetentionPolicyStatement parses a string and returns a create retention policy statement.
 // This function assumes the CREATE RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicyStatement, error) {
@@ -272,6 +515,71 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 		p.Unscan()
 	}
 
+	// Parse optional FUTURE LIMIT, PAST LIMIT, COLD AFTER, DOWNSAMPLE, PLACEMENT,
+	// DELETION DELAY and QUERY RANGE clauses.
+	found := make(map[string]struct{})
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		opt := strings.ToUpper(lit)
+		if tok == QUERY {
+			opt = tok.String()
+		} else if tok != IDENT || (opt != "FUTURE" && opt != "PAST" && opt != "COLD" && opt != "DOWNSAMPLE" && opt != "PLACEMENT" && opt != "DELETION") {
+			p.Unscan()
+			break
+		} else if _, ok := found[opt]; ok {
+			return nil, &ParseError{
+				Message: fmt.Sprintf("found duplicate %s option", opt),
+				Pos:     pos,
+			}
+		}
+
+		switch opt {
+		case "FUTURE":
+			d, err := p.parseWriteLimit()
+			if err != nil {
+				return nil, err
+			}
+			stmt.FutureWriteLimit = d
+		case "PAST":
+			d, err := p.parseWriteLimit()
+			if err != nil {
+				return nil, err
+			}
+			stmt.PastWriteLimit = d
+		case "COLD":
+			d, err := p.parseColdAfter()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ColdAfter = d
+		case "DOWNSAMPLE":
+			ds, err := p.parseDownsample()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Downsample = ds
+		case "PLACEMENT":
+			placement, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Placement = placement
+		case "DELETION":
+			d, err := p.parseDeletionDelay()
+			if err != nil {
+				return nil, err
+			}
+			stmt.DeletionDelay = d
+		case "QUERY":
+			d, err := p.parseQueryRange()
+			if err != nil {
+				return nil, err
+			}
+			stmt.MaxQueryRange = d
+		}
+		found[opt] = struct{}{}
+	}
+
 	// Parse optional DEFAULT token.
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DEFAULT {
 		stmt.Default = true
@@ -282,6 +590,73 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 	return stmt, nil
 }
 
+// parseDownsample parses the calls, interval and target retention policy of
+// a DOWNSAMPLE clause. This function assumes the DOWNSAMPLE token has already
+// been consumed.
+func (p *Parser) parseDownsample() (*Downsample, error) {
+	ds := &Downsample{}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != LPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{"("}, pos)
+	}
+
+	// Parse the comma-delimited list of aggregate calls.
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		p.Unscan()
+
+		expr, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		call, ok := expr.(*Call)
+		if !ok {
+			return nil, newParseError(tokstr(tok, lit), []string{"function call"}, pos)
+		}
+		ds.Calls = append(ds.Calls, call)
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok == RPAREN {
+			break
+		} else if tok != COMMA {
+			return nil, newParseError(tokstr(tok, lit), []string{",", ")"}, pos)
+		}
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != EVERY {
+		return nil, newParseError(tokstr(tok, lit), []string{"EVERY"}, pos)
+	}
+
+	// The interval must be finite and non-zero.
+	tok, pos, _ := p.ScanIgnoreWhitespace()
+	if tok == INF {
+		return nil, &ParseError{
+			Message: "invalid duration INF for downsample interval",
+			Pos:     pos,
+		}
+	}
+	p.Unscan()
+
+	d, err := p.ParseDuration()
+	if err != nil {
+		return nil, err
+	} else if d == 0 {
+		return nil, &ParseError{
+			Message: "downsample interval must be greater than zero",
+			Pos:     pos,
+		}
+	}
+	ds.Every = d
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != INTO {
+		return nil, newParseError(tokstr(tok, lit), []string{"INTO"}, pos)
+	}
+
+	if ds.RetentionPolicy, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
 // parseAlterRetentionPolicyStatement parses a string and returns an alter retention policy statement.
 // This function assumes the ALTER RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseAlterRetentionPolicyStatement() (*AlterRetentionPolicyStatement, error) {
@@ -309,14 +684,18 @@ func (p *Parser) parseAlterRetentionPolicyStatement() (*AlterRetentionPolicyStat
 	}
 	stmt.Database = ident
 
-	// Loop through option tokens (DURATION, REPLICATION, SHARD DURATION, DEFAULT, etc.).
-	found := make(map[Token]struct{})
+	// Loop through option tokens (DURATION, REPLICATION, SHARD DURATION, FUTURE LIMIT, DEFAULT, etc.).
+	found := make(map[string]struct{})
 Loop:
 	for {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
-		if _, ok := found[tok]; ok {
+		opt := tok.String()
+		if tok == IDENT {
+			opt = strings.ToUpper(lit)
+		}
+		if _, ok := found[opt]; ok {
 			return nil, &ParseError{
-				Message: fmt.Sprintf("found duplicate %s option", tok),
+				Message: fmt.Sprintf("found duplicate %s option", opt),
 				Pos:     pos,
 			}
 		}
@@ -357,19 +736,183 @@ Loop:
 			}
 		case DEFAULT:
 			stmt.Default = true
+		case QUERY:
+			d, err := p.parseQueryRange()
+			if err != nil {
+				return nil, err
+			}
+			stmt.MaxQueryRange = &d
+		case IDENT:
+			if opt != "FUTURE" && opt != "PAST" && opt != "COLD" && opt != "PLACEMENT" && opt != "DELETION" {
+				if len(found) == 0 {
+					return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "FUTURE", "PAST", "COLD", "PLACEMENT", "DELETION", "QUERY", "DEFAULT"}, pos)
+				}
+				p.Unscan()
+				break Loop
+			}
+
+			if opt == "PLACEMENT" {
+				placement, err := p.parseString()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Placement = &placement
+				break
+			}
+
+			var d time.Duration
+			var err error
+			switch opt {
+			case "COLD":
+				d, err = p.parseColdAfter()
+			case "DELETION":
+				d, err = p.parseDeletionDelay()
+			default:
+				d, err = p.parseWriteLimit()
+			}
+			if err != nil {
+				return nil, err
+			}
+			switch opt {
+			case "FUTURE":
+				stmt.FutureWriteLimit = &d
+			case "PAST":
+				stmt.PastWriteLimit = &d
+			case "COLD":
+				stmt.ColdAfter = &d
+			case "DELETION":
+				stmt.DeletionDelay = &d
+			}
 		default:
 			if len(found) == 0 {
-				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "DEFAULT"}, pos)
+				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "FUTURE", "PAST", "COLD", "PLACEMENT", "DELETION", "QUERY", "DEFAULT"}, pos)
 			}
 			p.Unscan()
 			break Loop
 		}
-		found[tok] = struct{}{}
+		found[opt] = struct{}{}
 	}
 
 	return stmt, nil
 }
 
+// parseWriteLimit parses the LIMIT duration following a FUTURE or PAST
+// retention policy option. INF disables the limit.
+func (p *Parser) parseWriteLimit() (time.Duration, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != LIMIT {
+		return 0, newParseError(tokstr(tok, lit), []string{"LIMIT"}, pos)
+	}
+	return p.ParseDuration()
+}
+
+// parseColdAfter parses the AFTER duration following a COLD retention
+// policy option. INF disables the cold tier.
+func (p *Parser) parseColdAfter() (time.Duration, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "AFTER" {
+		return 0, newParseError(tokstr(tok, lit), []string{"AFTER"}, pos)
+	}
+	return p.ParseDuration()
+}
+
+// parseDeletionDelay parses the DELAY duration following a DELETION retention
+// policy option.
+func (p *Parser) parseDeletionDelay() (time.Duration, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "DELAY" {
+		return 0, newParseError(tokstr(tok, lit), []string{"DELAY"}, pos)
+	}
+	return p.ParseDuration()
+}
+
+// parseQueryRange parses the RANGE duration following a QUERY retention
+// policy option. INF disables the limit.
+func (p *Parser) parseQueryRange() (time.Duration, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "RANGE" {
+		return 0, newParseError(tokstr(tok, lit), []string{"RANGE"}, pos)
+	}
+	return p.ParseDuration()
+}
+
+// parseAlterDatabaseStatement parses a string and returns an AlterDatabaseStatement.
+// This function assumes the "ALTER DATABASE" tokens have already been consumed.
+func (p *Parser) parseAlterDatabaseStatement() (*AlterDatabaseStatement, error) {
+	stmt := &AlterDatabaseStatement{}
+
+	// Parse the database name.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SET {
+		return nil, newParseError(tokstr(tok, lit), []string{"SET"}, pos)
+	}
+
+	// Parse one or more MAX or INDEX settings.
+	for i := 0; ; i++ {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok == IDENT && strings.ToUpper(lit) == "INDEX" {
+			if stmt.IndexType != nil {
+				return nil, &ParseError{Message: "found duplicate INDEX option", Pos: pos}
+			}
+			index, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			stmt.IndexType = &index
+			continue
+		} else if tok != IDENT || strings.ToUpper(lit) != "MAX" {
+			if i > 0 {
+				p.Unscan()
+				break
+			}
+			return nil, newParseError(tokstr(tok, lit), []string{"MAX", "INDEX"}, pos)
+		}
+
+		switch tok, pos, lit := p.ScanIgnoreWhitespace(); tok {
+		case SERIES:
+			if stmt.MaxSeriesN != nil {
+				return nil, &ParseError{Message: "found duplicate MAX SERIES option", Pos: pos}
+			}
+			n, err := p.parseLimit()
+			if err != nil {
+				return nil, err
+			}
+			stmt.MaxSeriesN = &n
+		case TAG:
+			if stmt.MaxValuesPerTag != nil {
+				return nil, &ParseError{Message: "found duplicate MAX TAG VALUES option", Pos: pos}
+			}
+			if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != VALUES {
+				return nil, newParseError(tokstr(tok, lit), []string{"VALUES"}, pos)
+			}
+			n, err := p.parseLimit()
+			if err != nil {
+				return nil, err
+			}
+			stmt.MaxValuesPerTag = &n
+		default:
+			return nil, newParseError(tokstr(tok, lit), []string{"SERIES", "TAG"}, pos)
+		}
+	}
+
+	return stmt, nil
+}
+
+// parseLimit parses a non-negative 64-bit integer limit.
+func (p *Parser) parseLimit() (int64, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != INTEGER {
+		return 0, newParseError(tokstr(tok, lit), []string{"integer"}, pos)
+	}
+
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil {
+		return 0, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return n, nil
+}
+
 // ParseInt parses a string representing a base 10 integer and returns the number.
 // It returns an error if the parsed number is outside the range [min, max].
 func (p *Parser) ParseInt(min, max int) (int, error) {
@@ -542,21 +1085,28 @@ func (p *Parser) parseStringList() ([]string, error) {
 // parseRevokeStatement parses a string and returns a revoke statement.
 // This function assumes the REVOKE token has already been consumed.
 func (p *Parser) parseRevokeStatement() (Statement, error) {
+	// Check for REVOKE ROLE and REVOKE TOKEN.
+	switch tok, _, _ := p.ScanIgnoreWhitespace(); tok {
+	case ROLE:
+		return p.parseRevokeRoleStatement()
+	case TOKEN:
+		return p.parseRevokeTokenStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be revoked.
 	priv, err := p.parsePrivilege()
 	if err != nil {
+		if err, ok := err.(*ParseError); ok {
+			err.Expected = append(err.Expected, "ROLE", "TOKEN")
+		}
 		return nil, err
 	}
 
 	// Check for ON or FROM clauses.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 	if tok == ON {
-		stmt, err := p.parseRevokeOnStatement()
-		if err != nil {
-			return nil, err
-		}
-		stmt.Privilege = priv
-		return stmt, nil
+		return p.parseRevokeOnStatement(priv)
 	} else if tok == FROM {
 		// Admin privilege is only revoked on ALL PRIVILEGES.
 		if priv != AllPrivileges {
@@ -572,17 +1122,15 @@ func (p *Parser) parseRevokeStatement() (Statement, error) {
 	return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
 }
 
-// parseRevokeOnStatement parses a string and returns a revoke statement.
+// parseRevokeOnStatement parses a string and returns a revoke statement, or a
+// revoke from role statement if the privilege is revoked FROM ROLE.
 // This function assumes the [PRIVILEGE] ON tokens have already been consumed.
-func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
-	stmt := &RevokeStatement{}
-
-	// Parse the name of the database.
-	lit, err := p.ParseIdent()
+func (p *Parser) parseRevokeOnStatement(priv Privilege) (Statement, error) {
+	// Parse the databases and the optional retention policy or measurements.
+	t, err := p.parsePrivilegeTarget()
 	if err != nil {
 		return nil, err
 	}
-	stmt.On = lit
 
 	// Parse FROM clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -592,11 +1140,61 @@ func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
 	}
 
+	// Parse the name of the role.
+	if tok, pos, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		if t.measurement != nil {
+			return nil, &ParseError{Message: "measurement privileges can only be revoked from users", Pos: pos}
+		} else if t.retentionPolicy != "" {
+			return nil, &ParseError{Message: "retention policy privileges can only be revoked from users", Pos: pos}
+		} else if t.databases != nil || t.databaseRegex != nil {
+			return nil, &ParseError{Message: "privileges on multiple databases can only be revoked from users", Pos: pos}
+		}
+		role, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &RevokeFromRoleStatement{Privilege: priv, On: t.database, Role: role}, nil
+	}
+	p.Unscan()
+
 	// Parse the name of the user.
-	lit, err = p.ParseIdent()
+	user, err := p.ParseIdent()
 	if err != nil {
 		return nil, err
 	}
+
+	return &RevokeStatement{
+		Privilege:       priv,
+		On:              t.database,
+		RetentionPolicy: t.retentionPolicy,
+		Measurement:     t.measurement,
+		Databases:       t.databases,
+		DatabaseRegex:   t.databaseRegex,
+		User:            user,
+	}, nil
+}
+
+// parseRevokeRoleStatement parses a string and returns a revoke role statement.
+// This function assumes the REVOKE ROLE tokens have already been consumed.
+func (p *Parser) parseRevokeRoleStatement() (*RevokeRoleStatement, error) {
+	stmt := &RevokeRoleStatement{}
+
+	// Parse the name of the role.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Role = lit
+
+	// Check for required FROM token.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+
+	// Parse the name of the user.
+	if lit, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
 	stmt.User = lit
 
 	return stmt, nil
@@ -621,21 +1219,25 @@ func (p *Parser) parseRevokeAdminStatement() (*RevokeAdminStatement, error) {
 // parseGrantStatement parses a string and returns a grant statement.
 // This function assumes the GRANT token has already been consumed.
 func (p *Parser) parseGrantStatement() (Statement, error) {
+	// Check for GRANT ROLE.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseGrantRoleStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be granted.
 	priv, err := p.parsePrivilege()
 	if err != nil {
+		if err, ok := err.(*ParseError); ok {
+			err.Expected = append(err.Expected, "ROLE")
+		}
 		return nil, err
 	}
 
 	// Check for ON or TO clauses.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 	if tok == ON {
-		stmt, err := p.parseGrantOnStatement()
-		if err != nil {
-			return nil, err
-		}
-		stmt.Privilege = priv
-		return stmt, nil
+		return p.parseGrantOnStatement(priv)
 	} else if tok == TO {
 		// Admin privilege is only granted on ALL PRIVILEGES.
 		if priv != AllPrivileges {
@@ -651,17 +1253,15 @@ func (p *Parser) parseGrantStatement() (Statement, error) {
 	return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
 }
 
-// parseGrantOnStatement parses a string and returns a grant statement.
+// parseGrantOnStatement parses a string and returns a grant statement, or a
+// grant to role statement if the privilege is granted TO ROLE.
 // This function assumes the [PRIVILEGE] ON tokens have already been consumed.
-func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
-	stmt := &GrantStatement{}
-
-	// Parse the name of the database.
-	lit, err := p.ParseIdent()
+func (p *Parser) parseGrantOnStatement(priv Privilege) (Statement, error) {
+	// Parse the databases and the optional retention policy or measurements.
+	t, err := p.parsePrivilegeTarget()
 	if err != nil {
 		return nil, err
 	}
-	stmt.On = lit
 
 	// Parse TO clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -671,11 +1271,136 @@ func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
 	}
 
+	// Parse the name of the role.
+	if tok, pos, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		if t.measurement != nil {
+			return nil, &ParseError{Message: "measurement privileges can only be granted to users", Pos: pos}
+		} else if t.retentionPolicy != "" {
+			return nil, &ParseError{Message: "retention policy privileges can only be granted to users", Pos: pos}
+		} else if t.databases != nil || t.databaseRegex != nil {
+			return nil, &ParseError{Message: "privileges on multiple databases can only be granted to users", Pos: pos}
+		}
+		role, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &GrantToRoleStatement{Privilege: priv, On: t.database, Role: role}, nil
+	}
+	p.Unscan()
+
 	// Parse the name of the user.
-	lit, err = p.ParseIdent()
+	user, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GrantStatement{
+		Privilege:       priv,
+		On:              t.database,
+		RetentionPolicy: t.retentionPolicy,
+		Measurement:     t.measurement,
+		Databases:       t.databases,
+		DatabaseRegex:   t.databaseRegex,
+		User:            user,
+	}, nil
+}
+
+// privilegeTarget is the target of a granted or revoked privilege.
+type privilegeTarget struct {
+	database        string
+	retentionPolicy string
+	measurement     *Measurement
+
+	// Databases of a list of more than one database, or the regex matching
+	// the databases. The database is empty then.
+	databases     []string
+	databaseRegex *RegexLiteral
+}
+
+// parsePrivilegeTarget parses the target of a granted or revoked privilege.
+// It is either a database, a retention policy of a database in the form
+// db.rp, or measurements of a database in the form db.rp.measurement or
+// db.rp./regex/, where rp may be empty to target the measurements in all
+// retention policies. Whole databases may also be targeted by a
+// comma-separated list or a regex.
+func (p *Parser) parsePrivilegeTarget() (*privilegeTarget, error) {
+	// Parse a regex matching databases.
+	if re, err := p.parseRegex(); err != nil {
+		return nil, err
+	} else if re != nil {
+		return &privilegeTarget{databaseRegex: re}, nil
+	}
+
+	_, pos, _ := p.ScanIgnoreWhitespace()
+	p.Unscan()
+
+	idents, err := p.parseSegmentedIdents()
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := p.parseRegex()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(idents) == 1 && re == nil:
+		return p.parsePrivilegeDatabases(idents[0])
+	case len(idents) == 2 && re == nil && idents[1] != "":
+		return &privilegeTarget{database: idents[0], retentionPolicy: idents[1]}, nil
+	case len(idents) == 2 && re != nil:
+		return &privilegeTarget{database: idents[0], measurement: &Measurement{Database: idents[0], RetentionPolicy: idents[1], Regex: re}}, nil
+	case len(idents) == 3 && re == nil:
+		return &privilegeTarget{database: idents[0], measurement: &Measurement{Database: idents[0], RetentionPolicy: idents[1], Name: idents[2]}}, nil
+	}
+	return nil, &ParseError{Message: "expected database, database.retention_policy or database.retention_policy.measurement", Pos: pos}
+}
+
+// parsePrivilegeDatabases parses the rest of a comma-separated list of
+// databases starting with db.
+func (p *Parser) parsePrivilegeDatabases(db string) (*privilegeTarget, error) {
+	dbs := []string{db}
+	for {
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+			p.Unscan()
+			break
+		}
+
+		db, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+
+	if len(dbs) == 1 {
+		return &privilegeTarget{database: db}, nil
+	}
+	return &privilegeTarget{databases: dbs}, nil
+}
+
+// parseGrantRoleStatement parses a string and returns a grant role statement.
+// This function assumes the GRANT ROLE tokens have already been consumed.
+func (p *Parser) parseGrantRoleStatement() (*GrantRoleStatement, error) {
+	stmt := &GrantRoleStatement{}
+
+	// Parse the name of the role.
+	lit, err := p.ParseIdent()
 	if err != nil {
 		return nil, err
 	}
+	stmt.Role = lit
+
+	// Check for required TO token.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+
+	// Parse the name of the user.
+	if lit, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
 	stmt.User = lit
 
 	return stmt, nil
@@ -846,6 +1571,16 @@ func (p *Parser) parseTarget(tr targetRequirement) (*Target, error) {
 		t.Measurement.Name = idents[2]
 	}
 
+	// Parse optional WITH CREATE.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		if err := p.parseTokens([]Token{CREATE}); err != nil {
+			return nil, err
+		}
+		t.Create = true
+	} else {
+		p.Unscan()
+	}
+
 	return t, nil
 }
 
@@ -898,6 +1633,39 @@ func (p *Parser) parseDeleteStatement() (Statement, error) {
 	return stmt, nil
 }
 
+// parsePurgeDataStatement parses a string and returns a PurgeDataStatement.
+// This function assumes the "PURGE" token has already been consumed.
+func (p *Parser) parsePurgeDataStatement() (*PurgeDataStatement, error) {
+	stmt := &PurgeDataStatement{}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "DATA" {
+		return nil, newParseError(tokstr(tok, lit), []string{"DATA"}, pos)
+	}
+
+	// Parse optional ON clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "BEFORE" {
+		return nil, newParseError(tokstr(tok, lit), []string{"BEFORE"}, pos)
+	}
+
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Time = expr
+
+	return stmt, nil
+}
+
 // parseShowSeriesStatement parses a string and returns a Statement.
 // This function assumes the "SHOW SERIES" tokens have already been consumed.
 func (p *Parser) parseShowSeriesStatement() (Statement, error) {
@@ -1089,8 +1857,27 @@ func (p *Parser) parseShowMeasurementsStatement() (*ShowMeasurementsStatement, e
 		p.Unscan()
 	}
 
-	// Parse optional WITH clause.
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+	// Parse optional EXACT COUNT clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == EXACT {
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToLower(lit) != "count" {
+			return nil, newParseError(tokstr(tok, lit), []string{"COUNT"}, pos)
+		}
+		stmt.ExactCount = true
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional WITH COUNTS and WITH MEASUREMENT clauses.
+	tok, _, _ := p.ScanIgnoreWhitespace()
+	if tok == WITH {
+		if next, _, lit := p.ScanIgnoreWhitespace(); next == IDENT && strings.ToLower(lit) == "counts" {
+			stmt.WithCounts = true
+			tok, _, _ = p.ScanIgnoreWhitespace()
+		} else {
+			p.Unscan()
+		}
+	}
+	if tok == WITH {
 		// Parse required MEASUREMENT token.
 		if err := p.parseTokens([]Token{MEASUREMENT}); err != nil {
 			return nil, err
@@ -1132,13 +1919,24 @@ func (p *Parser) parseShowMeasurementsStatement() (*ShowMeasurementsStatement, e
 		return nil, err
 	}
 
+	if stmt.ExactCount && stmt.WithCounts {
+		return nil, errors.New("EXACT COUNT and WITH COUNTS cannot be used together")
+	}
+
 	return stmt, nil
 }
 
 // parseShowQueriesStatement parses a string and returns a ShowQueriesStatement.
 // This function assumes the "SHOW QUERIES" tokens have been consumed.
 func (p *Parser) parseShowQueriesStatement() (*ShowQueriesStatement, error) {
-	return &ShowQueriesStatement{}, nil
+	stmt := &ShowQueriesStatement{}
+	var err error
+
+	// Parse condition: "WHERE EXPR".
+	if stmt.Condition, err = p.parseCondition(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
 }
 
 // parseShowRetentionPoliciesStatement parses a string and returns a ShowRetentionPoliciesStatement.
@@ -1455,7 +2253,22 @@ func (p *Parser) parseTagKeyExpr() (Token, Literal, error) {
 // parseShowUsersStatement parses a string and returns a ShowUsersStatement.
 // This function assumes the "SHOW USERS" tokens have been consumed.
 func (p *Parser) parseShowUsersStatement() (*ShowUsersStatement, error) {
-	return &ShowUsersStatement{}, nil
+	stmt := &ShowUsersStatement{}
+
+	// Parse optional WITH GRANTS or WITH DETAIL clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		switch tok, pos, lit := p.ScanIgnoreWhitespace(); {
+		case tok == GRANTS:
+			stmt.WithGrants = true
+		case tok == IDENT && strings.ToUpper(lit) == "DETAIL":
+			stmt.WithDetail = true
+		default:
+			return nil, newParseError(tokstr(tok, lit), []string{"GRANTS", "DETAIL"}, pos)
+		}
+	} else {
+		p.Unscan()
+	}
+	return stmt, nil
 }
 
 // parseShowSubscriptionsStatement parses a string and returns a ShowSubscriptionsStatement
@@ -1625,28 +2438,93 @@ func (p *Parser) parseDropSeriesStatement() (*DropSeriesStatement, error) {
 	if stmt.Condition == nil && stmt.Sources == nil {
 		return nil, newParseError(tokstr(tok, lit), []string{"FROM", "WHERE"}, pos)
 	}
-
-	return stmt, nil
+
+	return stmt, nil
+}
+
+// parseDropShardStatement parses a string and returns a
+// DropShardStatement. This function assumes the "DROP SHARD" tokens
+// have already been consumed.
+func (p *Parser) parseDropShardStatement() (*DropShardStatement, error) {
+	var err error
+	stmt := &DropShardStatement{}
+
+	// Parse the ID of the shard to be dropped.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseUndropShardGroupStatement parses a string and returns an UndropShardGroupStatement.
+// This function assumes the "UNDROP SHARD GROUP" tokens have already been consumed.
+func (p *Parser) parseUndropShardGroupStatement() (*UndropShardGroupStatement, error) {
+	id, err := p.ParseUInt64()
+	if err != nil {
+		return nil, err
+	}
+	return &UndropShardGroupStatement{ID: id}, nil
+}
+
+// parseTruncateShardsStatement parses a string and returns a TruncateShardsStatement.
+// This function assumes the "TRUNCATE SHARDS" tokens have already been consumed.
+func (p *Parser) parseTruncateShardsStatement() (*TruncateShardsStatement, error) {
+	// INF would never truncate anything.
+	if tok, pos, _ := p.ScanIgnoreWhitespace(); tok == INF {
+		return nil, &ParseError{
+			Message: "invalid duration INF for truncate delay",
+			Pos:     pos,
+		}
+	}
+	p.Unscan()
+
+	d, err := p.ParseDuration()
+	if err != nil {
+		return nil, err
+	}
+	return &TruncateShardsStatement{Delay: d}, nil
 }
 
-// parseDropShardStatement parses a string and returns a
-// DropShardStatement. This function assumes the "DROP SHARD" tokens
-// have already been consumed.
-func (p *Parser) parseDropShardStatement() (*DropShardStatement, error) {
-	var err error
-	stmt := &DropShardStatement{}
+// parsePrecreateShardGroupsStatement parses a string and returns a PrecreateShardGroupsStatement.
+// This function assumes the "PRECREATE SHARD GROUPS" tokens have already been consumed.
+func (p *Parser) parsePrecreateShardGroupsStatement() (*PrecreateShardGroupsStatement, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FOR {
+		return nil, newParseError(tokstr(tok, lit), []string{"FOR"}, pos)
+	}
 
-	// Parse the ID of the shard to be dropped.
-	if stmt.ID, err = p.ParseUInt64(); err != nil {
+	// Shard groups can't be created for an unbounded time range.
+	tok, pos, _ := p.ScanIgnoreWhitespace()
+	if tok == INF {
+		return nil, &ParseError{
+			Message: "invalid duration INF for precreate shard groups",
+			Pos:     pos,
+		}
+	}
+	p.Unscan()
+
+	d, err := p.ParseDuration()
+	if err != nil {
 		return nil, err
 	}
-	return stmt, nil
+	return &PrecreateShardGroupsStatement{Duration: d}, nil
 }
 
 // parseShowContinuousQueriesStatement parses a string and returns a ShowContinuousQueriesStatement.
 // This function assumes the "SHOW CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesStatement, error) {
-	return &ShowContinuousQueriesStatement{}, nil
+	stmt := &ShowContinuousQueriesStatement{}
+
+	// Parse optional ON clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
+	return stmt, nil
 }
 
 // parseGrantsForUserStatement parses a string and returns a ShowGrantsForUserStatement.
@@ -1661,13 +2539,47 @@ func (p *Parser) parseGrantsForUserStatement() (*ShowGrantsForUserStatement, err
 	}
 	stmt.Name = lit
 
+	// Parse the optional VERBOSE.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "VERBOSE" {
+		stmt.Verbose = true
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
+// parseGrantsForRoleStatement parses a string and returns a ShowGrantsForRoleStatement.
+// This function assumes the "SHOW GRANTS FOR ROLE" tokens have already been consumed.
+func (p *Parser) parseGrantsForRoleStatement() (*ShowGrantsForRoleStatement, error) {
+	stmt := &ShowGrantsForRoleStatement{}
+
+	// Parse the name of the role to be displayed.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
 	return stmt, nil
 }
 
 // parseShowDatabasesStatement parses a string and returns a ShowDatabasesStatement.
 // This function assumes the "SHOW DATABASE" tokens have already been consumed.
 func (p *Parser) parseShowDatabasesStatement() (*ShowDatabasesStatement, error) {
-	return &ShowDatabasesStatement{}, nil
+	stmt := &ShowDatabasesStatement{}
+
+	// Parse optional WITH DETAIL clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok != IDENT || strings.ToLower(lit) != "detail" {
+			return nil, newParseError(tokstr(tok, lit), []string{"DETAIL"}, pos)
+		}
+		stmt.WithDetail = true
+	} else {
+		p.Unscan()
+	}
+	return stmt, nil
 }
 
 // parseCreateContinuousQueriesStatement parses a string and returns a CreateContinuousQueryStatement.
@@ -1702,6 +2614,15 @@ func (p *Parser) parseCreateContinuousQueryStatement() (*CreateContinuousQuerySt
 		p.Unscan()
 	}
 
+	// Parse optional OFFSET clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == OFFSET {
+		if stmt.Offset, err = p.ParseDuration(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
 	// Expect a "BEGIN SELECT" tokens.
 	if err := p.parseTokens([]Token{BEGIN, SELECT}); err != nil {
 		return nil, err
@@ -1735,7 +2656,7 @@ func (p *Parser) parseCreateContinuousQueryStatement() (*CreateContinuousQuerySt
 		return nil, newParseError(tokstr(tok, lit), []string{"END"}, pos)
 	}
 
-	if err := stmt.validate(); err != nil {
+	if err := stmt.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -1756,16 +2677,17 @@ func (p *Parser) parseCreateDatabaseStatement() (*CreateDatabaseStatement, error
 
 	// Look for "WITH"
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
-		// validate that at least one of DURATION, NAME, REPLICATION or SHARD is provided
+		// validate that at least one of DURATION, NAME, REPLICATION, SHARD or INDEX is provided
 		tok, pos, lit := p.ScanIgnoreWhitespace()
-		if tok != DURATION && tok != NAME && tok != REPLICATION && tok != SHARD {
-			return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "NAME", "REPLICATION", "SHARD"}, pos)
+		isIndex := tok == IDENT && strings.ToUpper(lit) == "INDEX"
+		if tok != DURATION && tok != NAME && tok != REPLICATION && tok != SHARD && !isIndex {
+			return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "NAME", "REPLICATION", "SHARD", "INDEX"}, pos)
 		}
 		// rewind
 		p.Unscan()
 
 		// mark statement as having a RetentionPolicyInfo defined
-		stmt.RetentionPolicyCreate = true
+		stmt.RetentionPolicyCreate = !isIndex
 
 		// Look for "DURATION"
 		if err := p.parseTokens([]Token{DURATION}); err != nil {
@@ -1813,6 +2735,16 @@ func (p *Parser) parseCreateDatabaseStatement() (*CreateDatabaseStatement, error
 				return nil, err
 			}
 		}
+
+		// Look for "INDEX"
+		if tok, _, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "INDEX" {
+			p.Unscan()
+		} else {
+			stmt.IndexType, err = p.parseString()
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		p.Unscan()
 	}
@@ -1892,6 +2824,13 @@ func (p *Parser) parseDropRetentionPolicyStatement() (*DropRetentionPolicyStatem
 		return nil, err
 	}
 
+	// Parse optional FORCE token.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "FORCE" {
+		stmt.Force = true
+	} else {
+		p.Unscan()
+	}
+
 	return stmt, nil
 }
 
@@ -1949,6 +2888,118 @@ func (p *Parser) parseDropUserStatement() (*DropUserStatement, error) {
 	return stmt, nil
 }
 
+// parseCreateRoleStatement parses a string and returns a CreateRoleStatement.
+// This function assumes the "CREATE ROLE" tokens have already been consumed.
+func (p *Parser) parseCreateRoleStatement() (*CreateRoleStatement, error) {
+	stmt := &CreateRoleStatement{}
+
+	// Parse the name of the role to be created.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
+// parseCreateTokenStatement parses a string and returns a CreateTokenStatement.
+// This function assumes the "CREATE TOKEN" tokens have already been consumed.
+func (p *Parser) parseCreateTokenStatement() (*CreateTokenStatement, error) {
+	stmt := &CreateTokenStatement{}
+
+	// Parse optional FOR clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
+		lit, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.User = lit
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional WITH clause restricting the token to a privilege on a database.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		priv, err := p.parsePrivilege()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Privilege = priv
+
+		if err := p.parseTokens([]Token{ON}); err != nil {
+			return nil, err
+		}
+		if stmt.Database, err = p.ParseIdent(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional DURATION clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DURATION {
+		d, err := p.ParseDuration()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Duration = d
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
+// parseRevokeTokenStatement parses a string and returns a RevokeTokenStatement.
+// This function assumes the "REVOKE TOKEN" tokens have already been consumed.
+func (p *Parser) parseRevokeTokenStatement() (*RevokeTokenStatement, error) {
+	stmt := &RevokeTokenStatement{}
+
+	// Parse the id of the token to be revoked.
+	id, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+	stmt.ID = id
+
+	return stmt, nil
+}
+
+// parseShowTokensStatement parses a string and returns a ShowTokensStatement.
+// This function assumes the "SHOW TOKENS" tokens have already been consumed.
+func (p *Parser) parseShowTokensStatement() (*ShowTokensStatement, error) {
+	stmt := &ShowTokensStatement{}
+
+	// Parse optional FOR clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
+		lit, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.User = lit
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
+// parseDropRoleStatement parses a string and returns a DropRoleStatement.
+// This function assumes the "DROP ROLE" tokens have already been consumed.
+func (p *Parser) parseDropRoleStatement() (*DropRoleStatement, error) {
+	stmt := &DropRoleStatement{}
+
+	// Parse the name of the role to be dropped.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
 // parseExplainStatement parses a string and return an ExplainStatement.
 // This function assumes the EXPLAIN token has already been consumed.
 func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
@@ -1960,8 +3011,31 @@ func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
 		p.Unscan()
 	}
 
-	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SELECT {
-		return nil, newParseError(tokstr(tok, lit), []string{"SELECT"}, pos)
+	// DELETE and DROP SERIES are only planned, so they can't be analyzed.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch {
+	case tok == DELETE && !stmt.Analyze:
+		s, err := p.parseDeleteStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmt.SeriesStatement = s
+		return stmt, nil
+	case tok == DROP && !stmt.Analyze:
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SERIES {
+			return nil, newParseError(tokstr(tok, lit), []string{"SERIES"}, pos)
+		}
+		s, err := p.parseDropSeriesStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmt.SeriesStatement = s
+		return stmt, nil
+	case tok != SELECT:
+		if stmt.Analyze {
+			return nil, newParseError(tokstr(tok, lit), []string{"SELECT"}, pos)
+		}
+		return nil, newParseError(tokstr(tok, lit), []string{"SELECT", "DELETE", "DROP"}, pos)
 	}
 
 	s, err := p.parseSelectStatement(targetNotRequired)
@@ -1975,13 +3049,62 @@ func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
 // parseShowShardGroupsStatement parses a string for "SHOW SHARD GROUPS" statement.
 // This function assumes the "SHOW SHARD GROUPS" tokens have already been consumed.
 func (p *Parser) parseShowShardGroupsStatement() (*ShowShardGroupsStatement, error) {
-	return &ShowShardGroupsStatement{}, nil
+	stmt := &ShowShardGroupsStatement{}
+	var err error
+
+	// Parse optional ON clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		if stmt.Database, err = p.ParseIdent(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse condition: "WHERE EXPR".
+	if stmt.Condition, err = p.parseCondition(); err != nil {
+		return nil, err
+	}
+
+	// Parse timezone: "TZ(<timezone>)".
+	if stmt.Location, err = p.parseLocation(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
 }
 
 // parseShowShardsStatement parses a string for "SHOW SHARDS" statement.
 // This function assumes the "SHOW SHARDS" tokens have already been consumed.
 func (p *Parser) parseShowShardsStatement() (*ShowShardsStatement, error) {
-	return &ShowShardsStatement{}, nil
+	stmt := &ShowShardsStatement{}
+	var err error
+
+	// Parse optional ON clause: "ON <database>[.<retention policy>]".
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		idents, err := p.parseSegmentedIdents()
+		if err != nil {
+			return nil, err
+		}
+		switch len(idents) {
+		case 1:
+			stmt.Database = idents[0]
+		case 2:
+			stmt.Database, stmt.RetentionPolicy = idents[0], idents[1]
+		default:
+			return nil, errors.New("invalid ON clause: expected <database>[.<retention policy>]")
+		}
+		if stmt.Database == "" {
+			return nil, errors.New("database name required")
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse timezone: "TZ(<timezone>)".
+	if stmt.Location, err = p.parseLocation(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
 }
 
 // parseShowStatsStatement parses a string and returns a ShowStatsStatement.
@@ -1991,7 +3114,12 @@ func (p *Parser) parseShowStatsStatement() (*ShowStatsStatement, error) {
 	var err error
 
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
-		stmt.Module, err = p.parseString()
+		// Parse either a module regex or a list of module names.
+		if stmt.ModuleRegex, err = p.parseRegex(); err != nil {
+			return nil, err
+		} else if stmt.ModuleRegex == nil {
+			stmt.Modules, err = p.parseStringList()
+		}
 	} else {
 		p.Unscan()
 	}
@@ -2013,6 +3141,41 @@ func (p *Parser) parseShowDiagnosticsStatement() (*ShowDiagnosticsStatement, err
 	return stmt, err
 }
 
+// parseAlterContinuousQueryStatement parses a string and returns an AlterContinuousQueryStatement.
+// This function assumes the "ALTER CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseAlterContinuousQueryStatement() (*AlterContinuousQueryStatement, error) {
+	stmt := &AlterContinuousQueryStatement{}
+
+	// Read the name of the query to alter.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database the query belongs to.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Expect an "OFFSET" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != OFFSET {
+		return nil, newParseError(tokstr(tok, lit), []string{"OFFSET"}, pos)
+	}
+
+	if stmt.Offset, err = p.ParseDuration(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseDropContinuousQueriesStatement parses a string and returns a DropContinuousQueryStatement.
 // This function assumes the "DROP CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatement, error) {
@@ -2039,6 +3202,56 @@ func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatem
 	return stmt, nil
 }
 
+// parseRunContinuousQueryStatement parses a string and returns a RunContinuousQueryStatement.
+// This function assumes the "RUN CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseRunContinuousQueryStatement() (*RunContinuousQueryStatement, error) {
+	stmt := &RunContinuousQueryStatement{}
+
+	// Read the name of the query to run.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database the query belongs to.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "BETWEEN" {
+		return nil, newParseError(tokstr(tok, lit), []string{"BETWEEN"}, pos)
+	}
+
+	// AND binds looser than every operator allowed in a time expression,
+	// so the range parses as a single AND of the start and end times.
+	_, pos, _ := p.ScanIgnoreWhitespace()
+	p.Unscan()
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	be, ok := expr.(*BinaryExpr)
+	if !ok || be.Op != AND || isLogicalExpr(be.LHS) || isLogicalExpr(be.RHS) {
+		return nil, &ParseError{Message: "time range must be of the form BETWEEN <start> AND <end>", Pos: pos}
+	}
+	stmt.Start, stmt.End = be.LHS, be.RHS
+
+	return stmt, nil
+}
+
+// isLogicalExpr returns true if expr is an AND or OR expression.
+func isLogicalExpr(expr Expr) bool {
+	be, ok := expr.(*BinaryExpr)
+	return ok && (be.Op == AND || be.Op == OR)
+}
+
 // parseFields parses a list of one or more fields.
 func (p *Parser) parseFields() (Fields, error) {
 	var fields Fields
@@ -938,6 +938,49 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT * INTO "db"."rp".:MEASUREMENT FROM /<regex>/
+		{
+			s: `SELECT * INTO "backup"."autogen".:MEASUREMENT FROM /.*/ GROUP BY *`,
+			stmt: &cnosql.SelectStatement{
+				IsRawQuery: true,
+				Fields:     []*cnosql.Field{{Expr: &cnosql.Wildcard{}}},
+				Target: &cnosql.Target{
+					Measurement: &cnosql.Measurement{Database: "backup", RetentionPolicy: "autogen", IsTarget: true},
+				},
+				Sources: []cnosql.Source{&cnosql.Measurement{
+					Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(".*")}},
+				},
+				Dimensions: []*cnosql.Dimension{{Expr: &cnosql.Wildcard{}}},
+			},
+		},
+
+		// SELECT * INTO "db"."rp".<measurement> WITH CREATE
+		{
+			s: `SELECT value INTO "archive"."rp90d"."cpu" WITH CREATE FROM cpu`,
+			stmt: &cnosql.SelectStatement{
+				IsRawQuery: true,
+				Fields:     []*cnosql.Field{{Expr: &cnosql.VarRef{Val: "value"}}},
+				Target: &cnosql.Target{
+					Measurement: &cnosql.Measurement{Database: "archive", RetentionPolicy: "rp90d", Name: "cpu", IsTarget: true},
+					Create:      true,
+				},
+				Sources: []cnosql.Source{&cnosql.Measurement{Name: "cpu"}},
+			},
+		},
+
+		// SELECT * INTO "db"..<measurement> leaves the retention policy to the target database
+		{
+			s: `SELECT value INTO "otherdb".."cpu" FROM cpu`,
+			stmt: &cnosql.SelectStatement{
+				IsRawQuery: true,
+				Fields:     []*cnosql.Field{{Expr: &cnosql.VarRef{Val: "value"}}},
+				Target: &cnosql.Target{
+					Measurement: &cnosql.Measurement{Database: "otherdb", Name: "cpu", IsTarget: true},
+				},
+				Sources: []cnosql.Source{&cnosql.Measurement{Name: "cpu"}},
+			},
+		},
+
 		// SELECT * FROM "db"."rp"./<regex>/
 		{
 			s: `SELECT * FROM "db"."rp"./cpu.*/`,
@@ -1759,11 +1802,66 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// EXPLAIN DELETE ...
+		{
+			s: `EXPLAIN DELETE FROM cpu WHERE host = 'serverA'`,
+			stmt: &cnosql.ExplainStatement{
+				SeriesStatement: &cnosql.DeleteSeriesStatement{
+					Sources: []cnosql.Source{&cnosql.Measurement{Name: "cpu"}},
+					Condition: &cnosql.BinaryExpr{
+						Op:  cnosql.EQ,
+						LHS: &cnosql.VarRef{Val: "host"},
+						RHS: &cnosql.StringLiteral{Val: "serverA"},
+					},
+				},
+			},
+		},
+
+		// EXPLAIN DROP SERIES ...
+		{
+			s: `EXPLAIN DROP SERIES FROM cpu`,
+			stmt: &cnosql.ExplainStatement{
+				SeriesStatement: &cnosql.DropSeriesStatement{
+					Sources: []cnosql.Source{&cnosql.Measurement{Name: "cpu"}},
+				},
+			},
+		},
+
 		// SHOW GRANTS
 		{
 			s:    `SHOW GRANTS FOR jdoe`,
 			stmt: &cnosql.ShowGrantsForUserStatement{Name: "jdoe"},
 		},
+		{
+			s:    `SHOW GRANTS FOR jdoe VERBOSE`,
+			stmt: &cnosql.ShowGrantsForUserStatement{Name: "jdoe", Verbose: true},
+		},
+		{
+			s:    `SHOW GRANTS FOR verbose`,
+			stmt: &cnosql.ShowGrantsForUserStatement{Name: "verbose"},
+		},
+
+		// SHOW GRANTS FOR ROLE
+		{
+			s:    `SHOW GRANTS FOR ROLE readers`,
+			stmt: &cnosql.ShowGrantsForRoleStatement{Name: "readers"},
+		},
+
+		// SHOW ROLES
+		{
+			s:    `SHOW ROLES`,
+			stmt: &cnosql.ShowRolesStatement{},
+		},
+
+		// SHOW TOKENS
+		{
+			s:    `SHOW TOKENS`,
+			stmt: &cnosql.ShowTokensStatement{},
+		},
+		{
+			s:    `SHOW TOKENS FOR jdoe`,
+			stmt: &cnosql.ShowTokensStatement{User: "jdoe"},
+		},
 
 		// SHOW DATABASES
 		{
@@ -1771,6 +1869,12 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.ShowDatabasesStatement{},
 		},
 
+		// SHOW DATABASES WITH DETAIL
+		{
+			s:    `SHOW DATABASES WITH DETAIL`,
+			stmt: &cnosql.ShowDatabasesStatement{WithDetail: true},
+		},
+
 		// SHOW SERIES statement
 		{
 			s:    `SHOW SERIES`,
@@ -1938,6 +2042,41 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SHOW MEASUREMENTS WITH COUNTS
+		{
+			s: `SHOW MEASUREMENTS WITH COUNTS LIMIT 20`,
+			stmt: &cnosql.ShowMeasurementsStatement{
+				WithCounts: true,
+				Limit:      20,
+			},
+		},
+
+		// SHOW MEASUREMENTS EXACT COUNT
+		{
+			s: `SHOW MEASUREMENTS ON db0 EXACT COUNT WHERE region = 'uswest'`,
+			stmt: &cnosql.ShowMeasurementsStatement{
+				Database:   "db0",
+				ExactCount: true,
+				Condition: &cnosql.BinaryExpr{
+					Op:  cnosql.EQ,
+					LHS: &cnosql.VarRef{Val: "region"},
+					RHS: &cnosql.StringLiteral{Val: "uswest"},
+				},
+			},
+		},
+
+		// SHOW MEASUREMENTS WITH COUNTS WITH MEASUREMENT =~ /regex/
+		{
+			s: `SHOW MEASUREMENTS ON db0 WITH COUNTS WITH MEASUREMENT =~ /[cg]pu/`,
+			stmt: &cnosql.ShowMeasurementsStatement{
+				Database:   "db0",
+				WithCounts: true,
+				Source: &cnosql.Measurement{
+					Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`[cg]pu`)},
+				},
+			},
+		},
+
 		// SHOW MEASUREMENTS WITH MEASUREMENT = cpu
 		{
 			s: `SHOW MEASUREMENTS WITH MEASUREMENT = cpu`,
@@ -2044,6 +2183,26 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.ShowQueriesStatement{},
 		},
 
+		// SHOW QUERIES WHERE
+		{
+			s: `SHOW QUERIES WHERE "user" = 'bob' AND "database" = 'db0'`,
+			stmt: &cnosql.ShowQueriesStatement{
+				Condition: &cnosql.BinaryExpr{
+					Op: cnosql.AND,
+					LHS: &cnosql.BinaryExpr{
+						Op:  cnosql.EQ,
+						LHS: &cnosql.VarRef{Val: "user"},
+						RHS: &cnosql.StringLiteral{Val: "bob"},
+					},
+					RHS: &cnosql.BinaryExpr{
+						Op:  cnosql.EQ,
+						LHS: &cnosql.VarRef{Val: "database"},
+						RHS: &cnosql.StringLiteral{Val: "db0"},
+					},
+				},
+			},
+		},
+
 		// KILL QUERY 4
 		{
 			s: `KILL QUERY 4`,
@@ -2061,6 +2220,24 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// KILL SESSION 7
+		{
+			s:    `KILL SESSION 7`,
+			stmt: &cnosql.KillSessionStatement{SessionID: 7},
+		},
+
+		// SHOW SESSIONS
+		{
+			s:    `SHOW SESSIONS`,
+			stmt: &cnosql.ShowSessionsStatement{},
+		},
+
+		// SHOW OPERATIONS
+		{
+			s:    `SHOW OPERATIONS`,
+			stmt: &cnosql.ShowOperationsStatement{},
+		},
+
 		// SHOW RETENTION POLICIES
 		{
 			s:    `SHOW RETENTION POLICIES`,
@@ -2598,6 +2775,18 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.ShowUsersStatement{},
 		},
 
+		// SHOW USERS WITH GRANTS
+		{
+			s:    `SHOW USERS WITH GRANTS`,
+			stmt: &cnosql.ShowUsersStatement{WithGrants: true},
+		},
+
+		// SHOW USERS WITH DETAIL
+		{
+			s:    `SHOW USERS WITH DETAIL`,
+			stmt: &cnosql.ShowUsersStatement{WithDetail: true},
+		},
+
 		// SHOW FIELD KEYS
 		{
 			skip: true,
@@ -2824,6 +3013,12 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.ShowContinuousQueriesStatement{},
 		},
 
+		// SHOW CONTINUOUS QUERIES ON a database
+		{
+			s:    `SHOW CONTINUOUS QUERIES ON db0`,
+			stmt: &cnosql.ShowContinuousQueriesStatement{Database: "db0"},
+		},
+
 		// CREATE CONTINUOUS QUERY ... INTO <measurement>
 		{
 			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE EVERY 1m FOR 1h BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(5m) END`,
@@ -2850,6 +3045,31 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		{
+			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE EVERY 10m OFFSET 2m BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(5m) END`,
+			stmt: &cnosql.CreateContinuousQueryStatement{
+				Name:     "myquery",
+				Database: "testdb",
+				Source: &cnosql.SelectStatement{
+					Fields:  []*cnosql.Field{{Expr: &cnosql.Call{Name: "count", Args: []cnosql.Expr{&cnosql.VarRef{Val: "field1"}}}}},
+					Target:  &cnosql.Target{Measurement: &cnosql.Measurement{Name: "measure1", IsTarget: true}},
+					Sources: []cnosql.Source{&cnosql.Measurement{Name: "myseries"}},
+					Dimensions: []*cnosql.Dimension{
+						{
+							Expr: &cnosql.Call{
+								Name: "time",
+								Args: []cnosql.Expr{
+									&cnosql.DurationLiteral{Val: 5 * time.Minute},
+								},
+							},
+						},
+					},
+				},
+				ResampleEvery: 10 * time.Minute,
+				Offset:        2 * time.Minute,
+			},
+		},
+
 		{
 			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE FOR 1h BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(5m) END`,
 			stmt: &cnosql.CreateContinuousQueryStatement{
@@ -3060,6 +3280,22 @@ func TestParser_ParseStatement(t *testing.T) {
 				RetentionPolicyShardGroupDuration: 10 * time.Minute,
 			},
 		},
+		{
+			s: `CREATE DATABASE testdb WITH INDEX 'tsi1'`,
+			stmt: &cnosql.CreateDatabaseStatement{
+				Name:      "testdb",
+				IndexType: "tsi1",
+			},
+		},
+		{
+			s: `CREATE DATABASE testdb WITH DURATION 24h INDEX 'inmem'`,
+			stmt: &cnosql.CreateDatabaseStatement{
+				Name:                    "testdb",
+				RetentionPolicyCreate:   true,
+				RetentionPolicyDuration: duration(24 * time.Hour),
+				IndexType:               "inmem",
+			},
+		},
 
 		// CREATE USER statement
 		{
@@ -3089,6 +3325,46 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// ALTER USER statement
+		{
+			s:    `ALTER USER jdoe LOCK`,
+			stmt: &cnosql.AlterUserStatement{Name: "jdoe", Locked: true},
+		},
+		{
+			s:    `ALTER USER "jdoe" unlock`,
+			stmt: &cnosql.AlterUserStatement{Name: "jdoe"},
+		},
+		{
+			s:    `ALTER USER jdoe WITH QUERY LIMIT CONCURRENT 4 PER MINUTE 120`,
+			stmt: &cnosql.AlterUserQueryLimitStatement{Name: "jdoe", MaxConcurrentQueries: intptr64(4), MaxQueriesPerMinute: intptr64(120)},
+		},
+		{
+			s:    `ALTER USER jdoe WITH QUERY LIMIT PER MINUTE 0`,
+			stmt: &cnosql.AlterUserQueryLimitStatement{Name: "jdoe", MaxQueriesPerMinute: intptr64(0)},
+		},
+		{
+			s:    `ALTER USER jdoe WITH QUERY LIMIT CONCURRENT 2 PRIORITY low`,
+			stmt: &cnosql.AlterUserQueryLimitStatement{Name: "jdoe", MaxConcurrentQueries: intptr64(2), Priority: "low"},
+		},
+		{
+			s:    `ALTER USER jdoe SET DEFAULT DATABASE mydb`,
+			stmt: &cnosql.AlterUserDefaultDatabaseStatement{Name: "jdoe", Database: "mydb"},
+		},
+		{
+			s:    `ALTER USER jdoe SET DEFAULT DATABASE "mydb" RETENTION POLICY "rp1"`,
+			stmt: &cnosql.AlterUserDefaultDatabaseStatement{Name: "jdoe", Database: "mydb", RetentionPolicy: "rp1"},
+		},
+		{
+			s:    `ALTER USER jdoe SET DEFAULT DATABASE ""`,
+			stmt: &cnosql.AlterUserDefaultDatabaseStatement{Name: "jdoe"},
+		},
+
+		// ALTER CONTINUOUS QUERY statement
+		{
+			s:    `ALTER CONTINUOUS QUERY myquery ON foo OFFSET 5m`,
+			stmt: &cnosql.AlterContinuousQueryStatement{Name: "myquery", Database: "foo", Offset: 5 * time.Minute},
+		},
+
 		// DROP CONTINUOUS QUERY statement
 		{
 			s:    `DROP CONTINUOUS QUERY myquery ON foo`,
@@ -3117,6 +3393,14 @@ func TestParser_ParseStatement(t *testing.T) {
 				Database: `mydb`,
 			},
 		},
+		{
+			s: `DROP RETENTION POLICY "1h.cpu" ON mydb FORCE`,
+			stmt: &cnosql.DropRetentionPolicyStatement{
+				Name:     `1h.cpu`,
+				Database: `mydb`,
+				Force:    true,
+			},
+		},
 
 		// DROP USER statement
 		{
@@ -3124,6 +3408,164 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.DropUserStatement{Name: "jdoe"},
 		},
 
+		// GRANT ... ON measurement
+		{
+			s: `GRANT READ ON testdb.autogen.cpu TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege:   cnosql.ReadPrivilege,
+				On:          "testdb",
+				Measurement: &cnosql.Measurement{Database: "testdb", RetentionPolicy: "autogen", Name: "cpu"},
+				User:        "jdoe",
+			},
+		},
+		{
+			s: `GRANT READ ON testdb../^public_/ TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege:   cnosql.ReadPrivilege,
+				On:          "testdb",
+				Measurement: &cnosql.Measurement{Database: "testdb", Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`^public_`)}},
+				User:        "jdoe",
+			},
+		},
+
+		// GRANT ... ON retention policy
+		{
+			s: `GRANT READ ON testdb.downsampled TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege:       cnosql.ReadPrivilege,
+				On:              "testdb",
+				RetentionPolicy: "downsampled",
+				User:            "jdoe",
+			},
+		},
+
+		// GRANT ... ON multiple databases
+		{
+			s: `GRANT READ ON "svc_a", svc_b TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege: cnosql.ReadPrivilege,
+				Databases: []string{"svc_a", "svc_b"},
+				User:      "jdoe",
+			},
+		},
+		{
+			s: `GRANT WRITE ON /^svc_.*/ TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege:     cnosql.WritePrivilege,
+				DatabaseRegex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`^svc_.*`)},
+				User:          "jdoe",
+			},
+		},
+
+		// REVOKE ... ON multiple databases
+		{
+			s: `REVOKE ALL PRIVILEGES ON svc_a, svc_b, svc_c FROM jdoe`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege: cnosql.AllPrivileges,
+				Databases: []string{"svc_a", "svc_b", "svc_c"},
+				User:      "jdoe",
+			},
+		},
+		{
+			s: `REVOKE READ ON /svc_/ FROM jdoe`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege:     cnosql.ReadPrivilege,
+				DatabaseRegex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`svc_`)},
+				User:          "jdoe",
+			},
+		},
+
+		// REVOKE ... ON retention policy
+		{
+			s: `REVOKE READ ON testdb.raw FROM jdoe`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege:       cnosql.ReadPrivilege,
+				On:              "testdb",
+				RetentionPolicy: "raw",
+				User:            "jdoe",
+			},
+		},
+
+		// REVOKE ... ON measurement
+		{
+			s: `REVOKE ALL PRIVILEGES ON testdb..billing FROM jdoe`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege:   cnosql.AllPrivileges,
+				On:          "testdb",
+				Measurement: &cnosql.Measurement{Database: "testdb", Name: "billing"},
+				User:        "jdoe",
+			},
+		},
+
+		// CREATE ROLE
+		{
+			s:    `CREATE ROLE readers`,
+			stmt: &cnosql.CreateRoleStatement{Name: "readers"},
+		},
+
+		// DROP ROLE
+		{
+			s:    `DROP ROLE readers`,
+			stmt: &cnosql.DropRoleStatement{Name: "readers"},
+		},
+
+		// GRANT ... TO ROLE
+		{
+			s: `GRANT READ ON testdb TO ROLE readers`,
+			stmt: &cnosql.GrantToRoleStatement{
+				Privilege: cnosql.ReadPrivilege,
+				On:        "testdb",
+				Role:      "readers",
+			},
+		},
+
+		// GRANT ROLE
+		{
+			s:    `GRANT ROLE readers TO jdoe`,
+			stmt: &cnosql.GrantRoleStatement{Role: "readers", User: "jdoe"},
+		},
+
+		// REVOKE ... FROM ROLE
+		{
+			s: `REVOKE ALL PRIVILEGES ON testdb FROM ROLE readers`,
+			stmt: &cnosql.RevokeFromRoleStatement{
+				Privilege: cnosql.AllPrivileges,
+				On:        "testdb",
+				Role:      "readers",
+			},
+		},
+
+		// REVOKE ROLE
+		{
+			s:    `REVOKE ROLE readers FROM jdoe`,
+			stmt: &cnosql.RevokeRoleStatement{Role: "readers", User: "jdoe"},
+		},
+
+		// CREATE TOKEN
+		{
+			s:    `CREATE TOKEN`,
+			stmt: &cnosql.CreateTokenStatement{},
+		},
+		{
+			s: `CREATE TOKEN FOR jdoe WITH READ ON testdb DURATION 30d`,
+			stmt: &cnosql.CreateTokenStatement{
+				User:      "jdoe",
+				Privilege: cnosql.ReadPrivilege,
+				Database:  "testdb",
+				Duration:  30 * 24 * time.Hour,
+			},
+		},
+		{
+			s:    `CREATE TOKEN DURATION INF`,
+			stmt: &cnosql.CreateTokenStatement{},
+		},
+
+		// REVOKE TOKEN
+		{
+			s:    `REVOKE TOKEN '3f2a9c1d'`,
+			stmt: &cnosql.RevokeTokenStatement{ID: "3f2a9c1d"},
+		},
+
 		// GRANT READ
 		{
 			s: `GRANT READ ON testdb TO jdoe`,
@@ -3300,6 +3742,112 @@ func TestParser_ParseStatement(t *testing.T) {
 				ShardGroupDuration: time.Second,
 			},
 		},
+		// CREATE RETENTION POLICY with write limits
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 PAST LIMIT 2h FUTURE LIMIT 10m DEFAULT`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:             "policy1",
+				Database:         "testdb",
+				Duration:         time.Hour,
+				Replication:      2,
+				FutureWriteLimit: 10 * time.Minute,
+				PastWriteLimit:   2 * time.Hour,
+				Default:          true,
+			},
+		},
+		// CREATE RETENTION POLICY with cold tier
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 52w REPLICATION 1 COLD AFTER 4w`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:        "policy1",
+				Database:    "testdb",
+				Duration:    52 * 7 * 24 * time.Hour,
+				Replication: 1,
+				ColdAfter:   4 * 7 * 24 * time.Hour,
+			},
+		},
+		// CREATE RETENTION POLICY with placement
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1d REPLICATION 2 PLACEMENT 'disk=ssd' DEFAULT`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:        "policy1",
+				Database:    "testdb",
+				Duration:    24 * time.Hour,
+				Replication: 2,
+				Placement:   "disk=ssd",
+				Default:     true,
+			},
+		},
+		// CREATE RETENTION POLICY with deletion delay
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1d REPLICATION 1 DELETION DELAY 2d`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:          "policy1",
+				Database:      "testdb",
+				Duration:      24 * time.Hour,
+				Replication:   1,
+				DeletionDelay: 48 * time.Hour,
+			},
+		},
+		// CREATE RETENTION POLICY with query range
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION INF REPLICATION 1 QUERY RANGE 30d`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:          "policy1",
+				Database:      "testdb",
+				Duration:      0,
+				Replication:   1,
+				MaxQueryRange: 30 * 24 * time.Hour,
+			},
+		},
+		// CREATE RETENTION POLICY with downsampling
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1d REPLICATION 1 DOWNSAMPLE (mean(*), max(value)) EVERY 1h INTO "rp_1h" DEFAULT`,
+			stmt: &cnosql.CreateRetentionPolicyStatement{
+				Name:        "policy1",
+				Database:    "testdb",
+				Duration:    24 * time.Hour,
+				Replication: 1,
+				Downsample: &cnosql.Downsample{
+					Calls: []*cnosql.Call{
+						{Name: "mean", Args: []cnosql.Expr{&cnosql.Wildcard{}}},
+						{Name: "max", Args: []cnosql.Expr{&cnosql.VarRef{Val: "value"}}},
+					},
+					Every:           time.Hour,
+					RetentionPolicy: "rp_1h",
+				},
+				Default: true,
+			},
+		},
+
+		// ALTER DATABASE
+		{
+			s:    `ALTER DATABASE mydb SET MAX SERIES 1000`,
+			stmt: &cnosql.AlterDatabaseStatement{Name: "mydb", MaxSeriesN: intptr64(1000)},
+		},
+		{
+			s:    `ALTER DATABASE mydb SET MAX SERIES 0`,
+			stmt: &cnosql.AlterDatabaseStatement{Name: "mydb", MaxSeriesN: intptr64(0)},
+		},
+		{
+			s:    `ALTER DATABASE mydb SET MAX TAG VALUES 100000`,
+			stmt: &cnosql.AlterDatabaseStatement{Name: "mydb", MaxValuesPerTag: intptr64(100000)},
+		},
+		{
+			s: `ALTER DATABASE mydb SET MAX SERIES 1000 MAX TAG VALUES 10`,
+			stmt: &cnosql.AlterDatabaseStatement{
+				Name:            "mydb",
+				MaxSeriesN:      intptr64(1000),
+				MaxValuesPerTag: intptr64(10),
+			},
+		},
+		{
+			s: `ALTER DATABASE mydb SET INDEX 'tsi1' MAX SERIES 10`,
+			stmt: func() *cnosql.AlterDatabaseStatement {
+				index := "tsi1"
+				return &cnosql.AlterDatabaseStatement{Name: "mydb", IndexType: &index, MaxSeriesN: intptr64(10)}
+			}(),
+		},
 
 		// ALTER RETENTION POLICY
 		{
@@ -3356,18 +3904,143 @@ func TestParser_ParseStatement(t *testing.T) {
 			s:    `ALTER RETENTION POLICY default ON testdb DURATION 0s REPLICATION 1 SHARD DURATION 0s`,
 			stmt: newAlterRetentionPolicyStatement("default", "testdb", time.Duration(0), 0, 1, false),
 		},
+		// ALTER RETENTION POLICY with write limits
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb FUTURE LIMIT 1h PAST LIMIT INF`,
+			stmt: func() *cnosql.AlterRetentionPolicyStatement {
+				stmt := newAlterRetentionPolicyStatement("policy1", "testdb", -1, -1, -1, false)
+				future, past := time.Hour, time.Duration(0)
+				stmt.FutureWriteLimit, stmt.PastWriteLimit = &future, &past
+				return stmt
+			}(),
+		},
+		// ALTER RETENTION POLICY with cold tier
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb COLD AFTER 30d`,
+			stmt: func() *cnosql.AlterRetentionPolicyStatement {
+				stmt := newAlterRetentionPolicyStatement("policy1", "testdb", -1, -1, -1, false)
+				coldAfter := 30 * 24 * time.Hour
+				stmt.ColdAfter = &coldAfter
+				return stmt
+			}(),
+		},
+		// ALTER RETENTION POLICY with placement
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb PLACEMENT 'disk=ssd,zone=a'`,
+			stmt: func() *cnosql.AlterRetentionPolicyStatement {
+				stmt := newAlterRetentionPolicyStatement("policy1", "testdb", -1, -1, -1, false)
+				placement := "disk=ssd,zone=a"
+				stmt.Placement = &placement
+				return stmt
+			}(),
+		},
+		// ALTER RETENTION POLICY with deletion delay
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb DELETION DELAY 7d`,
+			stmt: func() *cnosql.AlterRetentionPolicyStatement {
+				stmt := newAlterRetentionPolicyStatement("policy1", "testdb", -1, -1, -1, false)
+				delay := 7 * 24 * time.Hour
+				stmt.DeletionDelay = &delay
+				return stmt
+			}(),
+		},
+		// ALTER RETENTION POLICY with query range
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb QUERY RANGE 30d`,
+			stmt: func() *cnosql.AlterRetentionPolicyStatement {
+				stmt := newAlterRetentionPolicyStatement("policy1", "testdb", -1, -1, -1, false)
+				d := 30 * 24 * time.Hour
+				stmt.MaxQueryRange = &d
+				return stmt
+			}(),
+		},
+
+		// RUN CONTINUOUS QUERY
+		{
+			s: `RUN CONTINUOUS QUERY cq ON db BETWEEN '2000-01-01T00:00:00Z' AND '2000-01-02T00:00:00Z'`,
+			stmt: &cnosql.RunContinuousQueryStatement{
+				Name:     "cq",
+				Database: "db",
+				Start:    &cnosql.StringLiteral{Val: "2000-01-01T00:00:00Z"},
+				End:      &cnosql.StringLiteral{Val: "2000-01-02T00:00:00Z"},
+			},
+		},
+		{
+			s: `RUN CONTINUOUS QUERY cq ON db BETWEEN now() - 7d AND now()`,
+			stmt: &cnosql.RunContinuousQueryStatement{
+				Name:     "cq",
+				Database: "db",
+				Start: &cnosql.BinaryExpr{
+					Op:  cnosql.SUB,
+					LHS: &cnosql.Call{Name: "now"},
+					RHS: &cnosql.DurationLiteral{Val: 7 * 24 * time.Hour},
+				},
+				End: &cnosql.Call{Name: "now"},
+			},
+		},
+
+		// UNDROP SHARD GROUP
+		{
+			s:    `UNDROP SHARD GROUP 12`,
+			stmt: &cnosql.UndropShardGroupStatement{ID: 12},
+		},
+
+		// TRUNCATE SHARDS
+		{
+			s:    `TRUNCATE SHARDS 10m`,
+			stmt: &cnosql.TruncateShardsStatement{Delay: 10 * time.Minute},
+		},
+		{
+			s:    `TRUNCATE SHARDS 0s`,
+			stmt: &cnosql.TruncateShardsStatement{},
+		},
+
+		// PRECREATE SHARD GROUPS
+		{
+			s:    `PRECREATE SHARD GROUPS FOR 1d`,
+			stmt: &cnosql.PrecreateShardGroupsStatement{Duration: 24 * time.Hour},
+		},
+
+		// PURGE DATA
+		{
+			s: `PURGE DATA BEFORE '2000-01-01T00:00:00Z'`,
+			stmt: &cnosql.PurgeDataStatement{
+				Time: &cnosql.StringLiteral{Val: "2000-01-01T00:00:00Z"},
+			},
+		},
+		{
+			s: `PURGE DATA ON mydb BEFORE now() - 30d`,
+			stmt: &cnosql.PurgeDataStatement{
+				Database: "mydb",
+				Time: &cnosql.BinaryExpr{
+					Op:  cnosql.SUB,
+					LHS: &cnosql.Call{Name: "now"},
+					RHS: &cnosql.DurationLiteral{Val: 30 * 24 * time.Hour},
+				},
+			},
+		},
 
 		// SHOW STATS
 		{
-			s: `SHOW STATS`,
+			s:    `SHOW STATS`,
+			stmt: &cnosql.ShowStatsStatement{},
+		},
+		{
+			s: `SHOW STATS FOR 'cluster'`,
 			stmt: &cnosql.ShowStatsStatement{
-				Module: "",
+				Modules: []string{"cluster"},
 			},
 		},
 		{
-			s: `SHOW STATS FOR 'cluster'`,
+			s: `SHOW STATS FOR 'write', 'queryExecutor'`,
+			stmt: &cnosql.ShowStatsStatement{
+				Modules: []string{"write", "queryExecutor"},
+			},
+		},
+		{
+			s: `SHOW STATS FOR /shard.*/`,
 			stmt: &cnosql.ShowStatsStatement{
-				Module: "cluster",
+				ModuleRegex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`shard.*`)},
 			},
 		},
 
@@ -3377,12 +4050,49 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &cnosql.ShowShardGroupsStatement{},
 		},
 
+		// SHOW SHARD GROUPS with a time zone
+		{
+			s:    `SHOW SHARD GROUPS TZ('America/Los_Angeles')`,
+			stmt: &cnosql.ShowShardGroupsStatement{Location: LosAngeles},
+		},
+
+		// SHOW SHARD GROUPS ON a database with a time condition
+		{
+			s: `SHOW SHARD GROUPS ON db0 WHERE time > '2020-01-01T00:00:00Z'`,
+			stmt: &cnosql.ShowShardGroupsStatement{
+				Database: "db0",
+				Condition: &cnosql.BinaryExpr{
+					Op:  cnosql.GT,
+					LHS: &cnosql.VarRef{Val: "time"},
+					RHS: &cnosql.StringLiteral{Val: "2020-01-01T00:00:00Z"},
+				},
+			},
+		},
+
 		// SHOW SHARDS
 		{
 			s:    `SHOW SHARDS`,
 			stmt: &cnosql.ShowShardsStatement{},
 		},
 
+		// SHOW SHARDS with a time zone
+		{
+			s:    `SHOW SHARDS TZ('America/Los_Angeles')`,
+			stmt: &cnosql.ShowShardsStatement{Location: LosAngeles},
+		},
+
+		// SHOW SHARDS ON a database
+		{
+			s:    `SHOW SHARDS ON db0`,
+			stmt: &cnosql.ShowShardsStatement{Database: "db0"},
+		},
+
+		// SHOW SHARDS ON a retention policy with a time zone
+		{
+			s:    `SHOW SHARDS ON db0.rp0 TZ('America/Los_Angeles')`,
+			stmt: &cnosql.ShowShardsStatement{Database: "db0", RetentionPolicy: "rp0", Location: LosAngeles},
+		},
+
 		// SHOW DIAGNOSTICS
 		{
 			s:    `SHOW DIAGNOSTICS`,
@@ -3406,6 +4116,61 @@ func TestParser_ParseStatement(t *testing.T) {
 				Mode:            "ANY",
 			},
 		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS BALANCE 'http://a:9092' WEIGHT 4, 'http://b:9092', 'http://c:9092' WEIGHT 0`,
+			stmt: &cnosql.CreateSubscriptionStatement{
+				Name:            "name",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				Destinations:    []string{"http://a:9092", "http://b:9092", "http://c:9092"},
+				Weights:         []int{4, 1, 0},
+				Mode:            "BALANCE",
+			},
+		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'https://host1:8086' WITH KEY 'key.pem' CA 'ca.pem' CERT 'cert.pem' FILTER /^alerts$/`,
+			stmt: &cnosql.CreateSubscriptionStatement{
+				Name:            "name",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				Destinations:    []string{"https://host1:8086"},
+				Mode:            "ALL",
+				Filter:          &cnosql.RegexLiteral{Val: regexp.MustCompile(`^alerts$`)},
+				CA:              "ca.pem",
+				Cert:            "cert.pem",
+				Key:             "key.pem",
+			},
+		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://host1:9093' FILTER /^(alerts|health)$/`,
+			stmt: &cnosql.CreateSubscriptionStatement{
+				Name:            "name",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				Destinations:    []string{"udp://host1:9093"},
+				Mode:            "ALL",
+				Filter:          &cnosql.RegexLiteral{Val: regexp.MustCompile(`^(alerts|health)$`)},
+			},
+		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://host1:9093' FILTER _name = 'alerts' AND (region = 'uswest' OR host =~ /^web/)`,
+			stmt: &cnosql.CreateSubscriptionStatement{
+				Name:            "name",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				Destinations:    []string{"udp://host1:9093"},
+				Mode:            "ALL",
+				Filter: &cnosql.BinaryExpr{
+					Op:  cnosql.AND,
+					LHS: &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: &cnosql.VarRef{Val: "_name"}, RHS: &cnosql.StringLiteral{Val: "alerts"}},
+					RHS: &cnosql.ParenExpr{Expr: &cnosql.BinaryExpr{
+						Op:  cnosql.OR,
+						LHS: &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: &cnosql.VarRef{Val: "region"}, RHS: &cnosql.StringLiteral{Val: "uswest"}},
+						RHS: &cnosql.BinaryExpr{Op: cnosql.EQREGEX, LHS: &cnosql.VarRef{Val: "host"}, RHS: &cnosql.RegexLiteral{Val: regexp.MustCompile(`^web`)}},
+					}},
+				},
+			},
+		},
 
 		// DROP SUBSCRIPTION
 		{
@@ -3424,10 +4189,11 @@ func TestParser_ParseStatement(t *testing.T) {
 		},
 
 		// Errors
-		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, TRUNCATE, PRECREATE, PURGE, UNDROP, RUN at line 1, char 1`},
 		{s: `SELECT`, err: `found EOF, expected identifier, string, number, bool at line 1, char 8`},
-		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, TRUNCATE, PRECREATE, PURGE, UNDROP, RUN at line 1, char 1`},
 		{s: `SELECT field1 X`, err: `found X, expected FROM at line 1, char 15`},
+		{s: `SELECT field1 INTO m WITH FROM myseries`, err: `found FROM, expected CREATE at line 1, char 27`},
 		{s: `SELECT field1 FROM "series" WHERE X +;`, err: `found ;, expected identifier, string, number, bool at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries GROUP`, err: `found EOF, expected BY at line 1, char 35`},
 		{s: `SELECT field1 FROM myseries LIMIT`, err: `found EOF, expected integer at line 1, char 35`},
@@ -3459,31 +4225,47 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `DROP MEASUREMENT`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `DROP SERIES`, err: `found EOF, expected FROM, WHERE at line 1, char 13`},
 		{s: `DROP SERIES FROM`, err: `found EOF, expected identifier at line 1, char 18`},
+		{s: `EXPLAIN SHOW DATABASES`, err: `found SHOW, expected SELECT, DELETE, DROP at line 1, char 9`},
+		{s: `EXPLAIN DROP MEASUREMENT cpu`, err: `found MEASUREMENT, expected SERIES at line 1, char 14`},
+		{s: `EXPLAIN ANALYZE DELETE FROM cpu`, err: `found DELETE, expected SELECT at line 1, char 17`},
 		{s: `DROP SERIES FROM src WHERE`, err: `found EOF, expected identifier, string, number, bool at line 1, char 28`},
 		{s: `DROP SERIES FROM "foo".myseries`, err: `retention policy not supported at line 1, char 1`},
 		{s: `DROP SERIES FROM foo..myseries`, err: `database not supported at line 1, char 1`},
+		{s: `SHOW USERS WITH`, err: `found EOF, expected GRANTS, DETAIL at line 1, char 17`},
+		{s: `SHOW DATABASES WITH`, err: `found EOF, expected DETAIL at line 1, char 21`},
 		{s: `SHOW CONTINUOUS`, err: `found EOF, expected QUERIES at line 1, char 17`},
+		{s: `SHOW CONTINUOUS QUERIES ON`, err: `found EOF, expected identifier at line 1, char 28`},
 		{s: `SHOW RETENTION`, err: `found EOF, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION ON`, err: `found ON, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION POLICIES ON`, err: `found EOF, expected identifier at line 1, char 28`},
 		{s: `SHOW SHARD`, err: `found EOF, expected GROUPS at line 1, char 12`},
-		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, RETENTION, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
+		{s: `SHOW SHARDS TZ('Nowhere/Special')`, err: `unable to find time zone Nowhere/Special`},
+		{s: `SHOW SHARDS ON`, err: `found EOF, expected identifier at line 1, char 16`},
+		{s: `SHOW SHARD GROUPS ON`, err: `found EOF, expected identifier at line 1, char 22`},
+		{s: `SHOW SHARDS ON db0.rp0.m0`, err: `invalid ON clause: expected <database>[.<retention policy>]`},
+		{s: `SHOW SHARD GROUPS TZ(1)`, err: `expected string argument in tz()`},
+		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, OPERATIONS, QUERIES, RETENTION, ROLES, SERIES, SESSIONS, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, TOKENS, USERS at line 1, char 6`},
 		{s: `SHOW STATS FOR`, err: `found EOF, expected string at line 1, char 16`},
 		{s: `SHOW DIAGNOSTICS FOR`, err: `found EOF, expected string at line 1, char 22`},
 		{s: `SHOW GRANTS`, err: `found EOF, expected FOR at line 1, char 13`},
 		{s: `SHOW GRANTS FOR`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `DROP CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 17`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db OFFSET 10s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `OFFSET duration must be < run interval: must be less than 10s, got 10s`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 1m OFFSET 1h BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `OFFSET duration must be < run interval: must be less than 1m, got 1h`},
+		{s: `ALTER CONTINUOUS QUERY myquery ON foo`, err: `found EOF, expected OFFSET at line 1, char 39`},
+		{s: `ALTER CONTINUOUS QUERY myquery ON foo OFFSET`, err: `found EOF, expected duration at line 1, char 46`},
 		{s: `DROP CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 23`},
 		{s: `DROP CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 31`},
 		{s: `DROP CONTINUOUS QUERY myquery ON`, err: `found EOF, expected identifier at line 1, char 34`},
 		{s: `CREATE CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 19`},
 		{s: `CREATE CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
-		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SUBSCRIPTION at line 1, char 8`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `EVERY duration must be <= FOR duration: must be a maximum of 5s, got 10s`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 1h FOR 30m BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`, err: `EVERY duration must be <= FOR duration: must be a maximum of 30m, got 1h`},
+		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, ROLE, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
+		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SUBSCRIPTION, ROLE, TOKEN at line 1, char 8`},
 		{s: `CREATE DATABASE`, err: `found EOF, expected identifier at line 1, char 17`},
-		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD at line 1, char 31`},
+		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD, INDEX at line 1, char 31`},
 		{s: `CREATE DATABASE "testdb" WITH DURATION`, err: `found EOF, expected duration at line 1, char 40`},
 		{s: `CREATE DATABASE "testdb" WITH REPLICATION`, err: `found EOF, expected integer at line 1, char 43`},
 		{s: `CREATE DATABASE "testdb" WITH NAME`, err: `found EOF, expected identifier at line 1, char 36`},
@@ -3510,10 +4292,42 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE SUBSCRIPTION "name" ON "db"`, err: `found EOF, expected . at line 1, char 35`},
 		{s: `CREATE SUBSCRIPTION "name" ON "db".`, err: `found EOF, expected identifier at line 1, char 36`},
 		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp"`, err: `found EOF, expected DESTINATIONS at line 1, char 40`},
-		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS`, err: `found EOF, expected ALL, ANY at line 1, char 54`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS`, err: `found EOF, expected ALL, ANY, BALANCE at line 1, char 54`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS BALANCE 'http://a:1' WEIGHT`, err: `found EOF, expected integer at line 1, char 82`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS BALANCE 'http://a:1' WEIGHT -1`, err: `found -, expected integer at line 1, char 82`},
 		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL `, err: `found EOF, expected string at line 1, char 59`},
-		{s: `GRANT`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 7`},
-		{s: `GRANT BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 7`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'https://h:1' WITH`, err: `found EOF, expected CA, CERT, KEY at line 1, char 77`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'https://h:1' WITH CA 'a' CA 'b'`, err: `found duplicate CA option at line 1, char 84`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'https://h:1' WITH CERT ''`, err: `CERT file must not be empty at line 1, char 77`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:1' FILTER /(alerts/`, err: "error parsing regexp: missing closing ): `(alerts` at line 1, char 76"},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:1' FILTER value > 1`, err: `invalid subscription filter: value > 1, unsupported operator > at line 1, char 70`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:1' FILTER host = 1`, err: `invalid subscription filter: host = 1, tags must be compared with a string at line 1, char 70`},
+		{s: `CREATE ROLE`, err: `found EOF, expected identifier at line 1, char 13`},
+		{s: `DROP ROLE`, err: `found EOF, expected identifier at line 1, char 11`},
+		{s: `SHOW GRANTS FOR ROLE`, err: `found EOF, expected identifier at line 1, char 22`},
+		{s: `GRANT ROLE`, err: `found EOF, expected identifier at line 1, char 12`},
+		{s: `GRANT ROLE readers`, err: `found EOF, expected TO at line 1, char 20`},
+		{s: `GRANT ROLE readers TO`, err: `found EOF, expected identifier at line 1, char 23`},
+		{s: `GRANT READ ON testdb TO ROLE`, err: `found EOF, expected identifier at line 1, char 30`},
+		{s: `REVOKE ROLE readers`, err: `found EOF, expected FROM at line 1, char 21`},
+		{s: `REVOKE READ ON testdb FROM ROLE`, err: `found EOF, expected identifier at line 1, char 33`},
+		{s: `CREATE TOKEN FOR`, err: `found EOF, expected identifier at line 1, char 18`},
+		{s: `CREATE TOKEN WITH ROLE`, err: `found ROLE, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 19`},
+		{s: `CREATE TOKEN WITH READ`, err: `found EOF, expected ON at line 1, char 24`},
+		{s: `CREATE TOKEN DURATION`, err: `found EOF, expected duration at line 1, char 23`},
+		{s: `REVOKE TOKEN jdoe`, err: `found jdoe, expected string at line 1, char 14`},
+		{s: `SHOW TOKENS FOR`, err: `found EOF, expected identifier at line 1, char 17`},
+		{s: `GRANT READ ON testdb.autogen. TO jdoe`, err: `found TO, expected identifier at line 1, char 31`},
+		{s: `GRANT READ ON testdb.autogen TO ROLE readers`, err: `retention policy privileges can only be granted to users at line 1, char 33`},
+		{s: `REVOKE READ ON testdb.autogen FROM ROLE readers`, err: `retention policy privileges can only be revoked from users at line 1, char 36`},
+		{s: `GRANT READ ON testdb.autogen.cpu TO ROLE readers`, err: `measurement privileges can only be granted to users at line 1, char 37`},
+		{s: `REVOKE READ ON testdb.autogen.cpu FROM ROLE readers`, err: `measurement privileges can only be revoked from users at line 1, char 40`},
+		{s: `GRANT READ ON svc_a, svc_b TO ROLE readers`, err: `privileges on multiple databases can only be granted to users at line 1, char 31`},
+		{s: `REVOKE READ ON /svc_/ FROM ROLE readers`, err: `privileges on multiple databases can only be revoked from users at line 1, char 28`},
+		{s: `GRANT READ ON svc_a, TO jdoe`, err: `found TO, expected identifier at line 1, char 22`},
+		{s: `GRANT READ ON svc_a.autogen, svc_b TO jdoe`, err: `found ,, expected TO at line 1, char 28`},
+		{s: `GRANT`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES], ROLE at line 1, char 7`},
+		{s: `GRANT BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES], ROLE at line 1, char 7`},
 		{s: `GRANT READ`, err: `found EOF, expected ON at line 1, char 12`},
 		{s: `GRANT READ FROM`, err: `found FROM, expected ON at line 1, char 12`},
 		{s: `GRANT READ ON`, err: `found EOF, expected identifier at line 1, char 15`},
@@ -3544,11 +4358,13 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `GRANT ALL PRIVILEGES ON testdb TO`, err: `found EOF, expected identifier at line 1, char 35`},
 		{s: `GRANT ALL TO`, err: `found EOF, expected identifier at line 1, char 14`},
 		{s: `GRANT ALL PRIVILEGES TO`, err: `found EOF, expected identifier at line 1, char 25`},
-		{s: `KILL`, err: `found EOF, expected QUERY at line 1, char 6`},
+		{s: `KILL`, err: `found EOF, expected QUERY, SESSION at line 1, char 6`},
 		{s: `KILL QUERY 10s`, err: `found 10s, expected integer at line 1, char 12`},
 		{s: `KILL QUERY 4 ON 'host'`, err: `found host, expected identifier at line 1, char 16`},
-		{s: `REVOKE`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 8`},
-		{s: `REVOKE BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 8`},
+		{s: `KILL SESSION`, err: `found EOF, expected integer at line 1, char 14`},
+		{s: `KILL SESSION abc`, err: `found abc, expected integer at line 1, char 14`},
+		{s: `REVOKE`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES], ROLE, TOKEN at line 1, char 8`},
+		{s: `REVOKE BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES], ROLE, TOKEN at line 1, char 8`},
 		{s: `REVOKE READ`, err: `found EOF, expected ON at line 1, char 13`},
 		{s: `REVOKE READ TO`, err: `found TO, expected ON at line 1, char 13`},
 		{s: `REVOKE READ ON`, err: `found EOF, expected identifier at line 1, char 16`},
@@ -3592,11 +4408,64 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 0`, err: `invalid value 0: must be 1 <= n <= 2147483647 at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION bad`, err: `found bad, expected integer at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 84`},
-		{s: `ALTER`, err: `found EOF, expected RETENTION at line 1, char 7`},
+		{s: `PRECREATE SHARD GROUPS`, err: `found EOF, expected FOR at line 1, char 24`},
+		{s: `PRECREATE SHARD GROUPS FOR INF`, err: `invalid duration INF for precreate shard groups at line 1, char 28`},
+		{s: `PURGE`, err: `found EOF, expected DATA at line 1, char 7`},
+		{s: `PURGE DATA`, err: `found EOF, expected BEFORE at line 1, char 12`},
+		{s: `PURGE DATA ON`, err: `found EOF, expected identifier at line 1, char 15`},
+		{s: `PURGE DATA ON mydb BEFORE`, err: `found EOF, expected identifier, string, number, bool at line 1, char 27`},
+		{s: `TRUNCATE`, err: `found EOF, expected SHARDS at line 1, char 10`},
+		{s: `TRUNCATE SHARDS`, err: `found EOF, expected duration at line 1, char 17`},
+		{s: `TRUNCATE SHARDS INF`, err: `invalid duration INF for truncate delay at line 1, char 17`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 FUTURE LIMIT 1h FUTURE LIMIT 2h`, err: `found duplicate FUTURE option at line 1, char 85`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE mean(*)`, err: `found mean, expected ( at line 1, char 80`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE (value)`, err: `found value, expected function call at line 1, char 81`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE (mean(*) INTO`, err: `found INTO, expected ,, ) at line 1, char 89`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE (mean(*)) INTO rp`, err: `found INTO, expected EVERY at line 1, char 90`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE (mean(*)) EVERY 0s INTO rp`, err: `downsample interval must be greater than zero at line 1, char 96`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 DOWNSAMPLE (mean(*)) EVERY 1h`, err: `found EOF, expected INTO at line 1, char 98`},
+		{s: `ALTER`, err: `found EOF, expected RETENTION, DATABASE, CONTINUOUS, USER at line 1, char 7`},
+		{s: `ALTER USER`, err: `found EOF, expected identifier at line 1, char 12`},
+		{s: `ALTER USER jdoe`, err: `found EOF, expected LOCK, UNLOCK, WITH, SET at line 1, char 17`},
+		{s: `ALTER USER jdoe DROP`, err: `found DROP, expected LOCK, UNLOCK, WITH, SET at line 1, char 17`},
+		{s: `ALTER USER jdoe SET`, err: `found EOF, expected DEFAULT at line 1, char 21`},
+		{s: `ALTER USER jdoe SET DEFAULT`, err: `found EOF, expected DATABASE at line 1, char 29`},
+		{s: `ALTER USER jdoe SET DEFAULT DATABASE`, err: `found EOF, expected identifier at line 1, char 38`},
+		{s: `ALTER USER jdoe SET DEFAULT DATABASE mydb RETENTION`, err: `found EOF, expected POLICY at line 1, char 53`},
+		{s: `ALTER USER jdoe SET DEFAULT DATABASE mydb RETENTION POLICY`, err: `found EOF, expected identifier at line 1, char 60`},
+		{s: `ALTER USER jdoe WITH`, err: `found EOF, expected QUERY at line 1, char 22`},
+		{s: `ALTER USER jdoe WITH QUERY`, err: `found EOF, expected LIMIT at line 1, char 28`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT`, err: `found EOF, expected CONCURRENT, PER, PRIORITY at line 1, char 34`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT CONCURRENT`, err: `found EOF, expected integer at line 1, char 45`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT CONCURRENT -1`, err: `found -, expected integer at line 1, char 45`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT PER HOUR 10`, err: `found HOUR, expected MINUTE at line 1, char 38`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT CONCURRENT 1 CONCURRENT 2`, err: `found duplicate CONCURRENT option at line 1, char 47`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT PRIORITY urgent`, err: `found urgent, expected LOW, NORMAL, HIGH at line 1, char 43`},
+		{s: `ALTER USER jdoe WITH QUERY LIMIT PRIORITY LOW PRIORITY HIGH`, err: `found duplicate PRIORITY option at line 1, char 47`},
+		{s: `ALTER DATABASE`, err: `found EOF, expected identifier at line 1, char 16`},
+		{s: `ALTER DATABASE mydb`, err: `found EOF, expected SET at line 1, char 21`},
+		{s: `ALTER DATABASE mydb SET`, err: `found EOF, expected MAX, INDEX at line 1, char 25`},
+		{s: `ALTER DATABASE mydb SET INDEX tsi1`, err: `found tsi1, expected string at line 1, char 31`},
+		{s: `ALTER DATABASE mydb SET INDEX 'tsi1' INDEX 'inmem'`, err: `found duplicate INDEX option at line 1, char 38`},
+		{s: `ALTER DATABASE mydb SET MAX`, err: `found EOF, expected SERIES, TAG at line 1, char 29`},
+		{s: `ALTER DATABASE mydb SET MAX TAG`, err: `found EOF, expected VALUES at line 1, char 33`},
+		{s: `ALTER DATABASE mydb SET MAX TAG VALUES 1 MAX TAG VALUES 2`, err: `found duplicate MAX TAG VALUES option at line 1, char 46`},
+		{s: `ALTER DATABASE mydb SET MAX SERIES`, err: `found EOF, expected integer at line 1, char 36`},
+		{s: `ALTER DATABASE mydb SET MAX SERIES -1`, err: `found -, expected integer at line 1, char 36`},
 		{s: `ALTER RETENTION`, err: `found EOF, expected POLICY at line 1, char 17`},
 		{s: `ALTER RETENTION POLICY`, err: `found EOF, expected identifier at line 1, char 24`},
 		{s: `ALTER RETENTION POLICY policy1`, err: `found EOF, expected ON at line 1, char 32`}, {s: `ALTER RETENTION POLICY policy1 ON`, err: `found EOF, expected identifier at line 1, char 35`},
-		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, DEFAULT at line 1, char 42`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, FUTURE, PAST, COLD, PLACEMENT, DELETION, QUERY, DEFAULT at line 1, char 42`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb DELETION 7d`, err: `found 7d, expected DELAY at line 1, char 51`},
+		{s: `UNDROP SHARD GROUP`, err: `found EOF, expected integer at line 1, char 20`},
+		{s: `UNDROP SHARD`, err: `found EOF, expected GROUP at line 1, char 14`},
+		{s: `RUN CONTINUOUS QUERY cq ON db`, err: `found EOF, expected BETWEEN at line 1, char 31`},
+		{s: `RUN CONTINUOUS QUERY cq ON db BETWEEN now()`, err: `time range must be of the form BETWEEN <start> AND <end> at line 1, char 39`},
+		{s: `RUN CONTINUOUS QUERY cq ON db BETWEEN 1 AND 2 AND 3`, err: `time range must be of the form BETWEEN <start> AND <end> at line 1, char 39`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb COLD 30d`, err: `found 30d, expected AFTER at line 1, char 47`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb PLACEMENT disk`, err: `found disk, expected string at line 1, char 52`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb FUTURE 1h`, err: `found 1h, expected LIMIT at line 1, char 49`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb PAST LIMIT 1h PAST LIMIT 2h`, err: `found duplicate PAST option at line 1, char 56`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb REPLICATION 1 REPLICATION 2`, err: `found duplicate REPLICATION option at line 1, char 56`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION 15251w`, err: `overflowed duration 15251w: choose a smaller duration or INF at line 1, char 51`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION INF SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 70`},
@@ -3607,7 +4476,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SET PASSWORD FOR dejan`, err: `found EOF, expected = at line 1, char 24`},
 		{s: `SET PASSWORD FOR dejan =`, err: `found EOF, expected string at line 1, char 25`},
 		{s: `SET PASSWORD FOR dejan = bla`, err: `found bla, expected string at line 1, char 26`},
-		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, TRUNCATE, PRECREATE, PURGE, UNDROP, RUN at line 1, char 1`},
 		{s: `SELECT * FROM cpu WHERE "tagkey" = $$`, err: `empty bound parameter`},
 
 		// Create a database with a bound parameter.
@@ -4383,3 +5252,7 @@ func duration(v time.Duration) *time.Duration {
 func intptr(v int) *int {
 	return &v
 }
+
+func intptr64(v int64) *int64 {
+	return &v
+}
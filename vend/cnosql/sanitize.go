@@ -1,6 +1,7 @@
 package cnosql
 
 import (
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -9,6 +10,8 @@ var (
 	sanitizeSetPassword = regexp.MustCompile(`(?i)password\s+for[^=]*=\s+(["']?[^\s"]+["']?)`)
 
 	sanitizeCreatePassword = regexp.MustCompile(`(?i)with\s+password\s+(["']?[^\s"]+["']?)`)
+
+	sanitizeURLPassword = regexp.MustCompile(`(?i)['"][a-z][a-z0-9+.-]*://[^:@/'"\s]*:([^@/'"\s]*)@`)
 )
 
 // Sanitize attempts to sanitize passwords out of a raw query.
@@ -43,5 +46,34 @@ func Sanitize(query string) string {
 		buf.WriteString(query[i:])
 		query = buf.String()
 	}
+
+	if matches := sanitizeURLPassword.FindAllStringSubmatchIndex(query, -1); matches != nil {
+		var buf strings.Builder
+		i := 0
+		for _, match := range matches {
+			buf.WriteString(query[i:match[2]])
+			buf.WriteString("***")
+			i = match[3]
+		}
+		buf.WriteString(query[i:])
+		query = buf.String()
+	}
 	return query
 }
+
+// SanitizeURL replaces the password of the URL s with ***, such as the
+// credentials of a subscription destination. Other strings are returned
+// unchanged.
+func SanitizeURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	} else if _, ok := u.User.Password(); !ok {
+		return s
+	}
+
+	// Setting the password to *** would escape it, so splice it in.
+	user := url.User(u.User.Username()).String()
+	u.User = nil
+	return u.Scheme + "://" + user + ":***@" + strings.TrimPrefix(u.String(), u.Scheme+"://")
+}
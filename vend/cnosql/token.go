@@ -110,11 +110,14 @@ const (
 	NAME
 	OFFSET
 	ON
+	OPERATIONS
 	ORDER
 	PASSWORD
 	POLICY
 	POLICIES
+	PRECREATE
 	PRIVILEGES
+	PURGE
 	QUERIES
 	QUERY
 	READ
@@ -122,8 +125,13 @@ const (
 	RESAMPLE
 	RETENTION
 	REVOKE
+	ROLE
+	ROLES
+	RUN
 	SELECT
 	SERIES
+	SESSION
+	SESSIONS
 	SET
 	SHOW
 	SHARD
@@ -135,6 +143,10 @@ const (
 	SUBSCRIPTIONS
 	TAG
 	TO
+	TOKEN
+	TOKENS
+	TRUNCATE
+	UNDROP
 	USER
 	USERS
 	VALUES
@@ -233,11 +245,14 @@ var tokens = [...]string{
 	NAME:          "NAME",
 	OFFSET:        "OFFSET",
 	ON:            "ON",
+	OPERATIONS:    "OPERATIONS",
 	ORDER:         "ORDER",
 	PASSWORD:      "PASSWORD",
 	POLICY:        "POLICY",
 	POLICIES:      "POLICIES",
+	PRECREATE:     "PRECREATE",
 	PRIVILEGES:    "PRIVILEGES",
+	PURGE:         "PURGE",
 	QUERIES:       "QUERIES",
 	QUERY:         "QUERY",
 	READ:          "READ",
@@ -245,8 +260,13 @@ var tokens = [...]string{
 	RESAMPLE:      "RESAMPLE",
 	RETENTION:     "RETENTION",
 	REVOKE:        "REVOKE",
+	ROLE:          "ROLE",
+	ROLES:         "ROLES",
+	RUN:           "RUN",
 	SELECT:        "SELECT",
 	SERIES:        "SERIES",
+	SESSION:       "SESSION",
+	SESSIONS:      "SESSIONS",
 	SET:           "SET",
 	SHOW:          "SHOW",
 	SHARD:         "SHARD",
@@ -258,6 +278,10 @@ var tokens = [...]string{
 	SUBSCRIPTIONS: "SUBSCRIPTIONS",
 	TAG:           "TAG",
 	TO:            "TO",
+	TOKEN:         "TOKEN",
+	TOKENS:        "TOKENS",
+	TRUNCATE:      "TRUNCATE",
+	UNDROP:        "UNDROP",
 	USER:          "USER",
 	USERS:         "USERS",
 	VALUES:        "VALUES",
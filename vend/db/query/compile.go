@@ -1172,34 +1172,47 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 	opt.StartTime, opt.EndTime = c.TimeRange.MinTimeNano(), c.TimeRange.MaxTimeNano()
 	opt.Ascending = c.Ascending
 
-	if sopt.MaxBucketsN > 0 && !stmt.IsRawQuery && c.TimeRange.MinTimeNano() > cnosql.MinTime {
-		interval, err := stmt.GroupByInterval()
+	if sopt.MaxBucketsN > 0 {
+		buckets, err := bucketsN(stmt, opt)
 		if err != nil {
 			shards.Close()
 			return nil, err
 		}
-
-		if interval > 0 {
-			// Determine the start and end time matched to the interval (may not match the actual times).
-			first, _ := opt.Window(opt.StartTime)
-			last, _ := opt.Window(opt.EndTime - 1)
-
-			// Determine the number of buckets by finding the time span and dividing by the interval.
-			buckets := (last - first + int64(interval)) / int64(interval)
-			if int(buckets) > sopt.MaxBucketsN {
-				shards.Close()
-				return nil, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, sopt.MaxBucketsN)
-			}
+		if buckets > int64(sopt.MaxBucketsN) && !sopt.ExplainLimits {
+			shards.Close()
+			return nil, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, sopt.MaxBucketsN)
 		}
 	}
 
 	columns := stmt.ColumnNames()
 	return &preparedStatement{
-		stmt:      stmt,
-		opt:       opt,
-		ic:        shards,
-		columns:   columns,
-		maxPointN: sopt.MaxPointN,
-		now:       c.Options.Now,
+		stmt:        stmt,
+		opt:         opt,
+		ic:          shards,
+		columns:     columns,
+		maxPointN:   sopt.MaxPointN,
+		maxBucketsN: sopt.MaxBucketsN,
+		now:         c.Options.Now,
 	}, nil
 }
+
+// bucketsN returns the number of GROUP BY time() buckets stmt creates
+// between the start and end time of opt. It is zero for raw queries, queries
+// without a GROUP BY interval and queries without a lower time bound.
+func bucketsN(stmt *cnosql.SelectStatement, opt IteratorOptions) (int64, error) {
+	if stmt.IsRawQuery || opt.StartTime <= cnosql.MinTime {
+		return 0, nil
+	}
+
+	interval, err := stmt.GroupByInterval()
+	if err != nil || interval <= 0 {
+		return 0, err
+	}
+
+	// Determine the start and end time matched to the interval (may not match the actual times).
+	first, _ := opt.Window(opt.StartTime)
+	last, _ := opt.Window(opt.EndTime - 1)
+
+	// Determine the number of buckets by finding the time span and dividing by the interval.
+	return (last - first + int64(interval)) / int64(interval), nil
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 )
 
 // ExecutionContext contains state that the query is currently executing with.
@@ -18,6 +19,10 @@ type ExecutionContext struct {
 	// The query task information available to the StatementExecutor.
 	task *Task
 
+	// Kills the query with an error, as KILL QUERY does. Nil if the query
+	// isn't managed by a task manager.
+	kill func(err error) error
+
 	// Output channel where results and errors should be sent.
 	Results chan *Result
 
@@ -27,6 +32,9 @@ type ExecutionContext struct {
 	mu   sync.RWMutex
 	done chan struct{}
 	err  error
+
+	// Stats of the iterators of the statement being executed.
+	iteratorStats IteratorStats
 }
 
 func (ctx *ExecutionContext) watch() {
@@ -80,6 +88,59 @@ func (ctx *ExecutionContext) Err() error {
 	return ctx.err
 }
 
+// AddRowsEmitted adds n to the number of rows the query has emitted, as
+// reported by SHOW QUERIES.
+func (ctx *ExecutionContext) AddRowsEmitted(n int) {
+	if ctx.task != nil {
+		atomic.AddInt64(&ctx.task.rowsN, int64(n))
+	}
+}
+
+// SetMemoryBytes sets the approximate number of bytes of results the query
+// currently holds, as reported by SHOW QUERIES.
+func (ctx *ExecutionContext) SetMemoryBytes(n int64) {
+	if ctx.task != nil {
+		atomic.StoreInt64(&ctx.task.memoryBytes, n)
+	}
+}
+
+// Kill kills the query as KILL QUERY does, with err as the error of the
+// query. It does nothing if the query isn't managed by a task manager.
+func (ctx *ExecutionContext) Kill(err error) error {
+	if ctx.kill == nil {
+		return nil
+	}
+	return ctx.kill(err)
+}
+
+// SetIteratorStats records the stats of the iterators of the statement
+// being executed, e.g. for the slow query log.
+func (ctx *ExecutionContext) SetIteratorStats(stats IteratorStats) {
+	ctx.mu.Lock()
+	ctx.iteratorStats = stats
+	ctx.mu.Unlock()
+}
+
+// IteratorStats returns the stats set by SetIteratorStats.
+func (ctx *ExecutionContext) IteratorStats() IteratorStats {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	return ctx.iteratorStats
+}
+
+// AddQueryParticipant records that node nodeID runs part of the query
+// executing with ctx, so that killing the query also kills it there. It
+// returns the ID of the query, or zero if ctx doesn't belong to a query
+// managed by a TaskManager.
+func AddQueryParticipant(ctx context.Context, nodeID uint64) uint64 {
+	task, _ := ctx.Value(monitorContextKey{}).(*Task)
+	if task == nil {
+		return 0
+	}
+	task.addParticipant(nodeID)
+	return task.id
+}
+
 func (ctx *ExecutionContext) Value(key interface{}) interface{} {
 	switch key {
 	case monitorContextKey{}:
@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -39,6 +40,10 @@ var (
 
 	// ErrAlreadyKilled is returned when attempting to kill a query that has already been killed.
 	ErrAlreadyKilled = errors.New("already killed")
+
+	// ErrQueryPreempted is returned when a low priority query is killed to
+	// make room for a high priority one.
+	ErrQueryPreempted = errors.New("query preempted by a high priority query")
 )
 
 // Statistics for the Executor
@@ -62,12 +67,69 @@ func ErrMaxSelectPointsLimitExceeded(n, limit int) error {
 	return fmt.Errorf("max-select-point limit exceeed: (%d/%d)", n, limit)
 }
 
+// ErrStatementTimeoutExceeded is an error when a statement runs longer than
+// the timeout requested for its query.
+func ErrStatementTimeoutExceeded(timeout time.Duration) error {
+	return fmt.Errorf("query timeout exceeded after %s", timeout)
+}
+
 // ErrMaxConcurrentQueriesLimitExceeded is an error when a query cannot be run
 // because the maximum number of queries has been reached.
 func ErrMaxConcurrentQueriesLimitExceeded(n, limit int) error {
 	return fmt.Errorf("max-concurrent-queries limit exceeded(%d, %d)", n, limit)
 }
 
+// RateLimitError is returned when a query is rejected because its user has
+// reached one of their query limits.
+type RateLimitError struct {
+	// User the query was run as.
+	User string
+
+	// Name of the limit that was reached, e.g. "max-concurrent-queries".
+	Limit string
+
+	// Value of the limit.
+	Max int64
+
+	// Suggested delay before running the query again.
+	RetryAfter time.Duration
+}
+
+// Error returns the string representation of the error.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited: user %q reached %s limit of %d, retry after %s", e.User, e.Limit, e.Max, e.RetryAfter)
+}
+
+// MemoryBudgetExceededError is returned when a query holds more memory than
+// its max-select-memory-bytes limit allows.
+type MemoryBudgetExceededError struct {
+	// Approximate number of bytes the query held.
+	Bytes int64
+
+	// Value of the limit.
+	Limit int64
+}
+
+// Error returns the string representation of the error.
+func (e *MemoryBudgetExceededError) Error() string {
+	return fmt.Sprintf("memory budget exceeded: max-select-memory-bytes limit exceeded: (%d/%d)", e.Bytes, e.Limit)
+}
+
+// ServerBusyError is returned when a SELECT statement could not start
+// because the server was running its maximum number of them.
+type ServerBusyError struct {
+	// Value of the max-concurrent-selects limit.
+	Limit int64
+
+	// Why the statement was not admitted, e.g. the queue was full.
+	Reason string
+}
+
+// Error returns the string representation of the error.
+func (e *ServerBusyError) Error() string {
+	return fmt.Sprintf("server busy: max-concurrent-selects limit of %d reached and %s", e.Limit, e.Reason)
+}
+
 // CoarseAuthorizer determines if certain operations are authorized at the database level.
 //
 // It is supported both in OSS and Enterprise.
@@ -141,22 +203,164 @@ type ExecutionOptions struct {
 	// CoarseAuthorizer handles database-level authorization
 	CoarseAuthorizer CoarseAuthorizer
 
+	// Name of the user issuing the query. Blank if authentication is disabled.
+	UserID string
+
+	// Set if the user is an admin and may see the queries of other users.
+	UserAdmin bool
+
+	// The client session issuing the query. Zero if the query isn't issued
+	// by a tracked session.
+	SessionID uint64
+
 	// The requested maximum number of points to return in each result.
 	ChunkSize int
 
+	// The number of points a SELECT INTO buffers before writing them.
+	// Zero uses the statement executor's setting.
+	IntoWriteBatchSize int
+
+	// If either is positive, a SELECT INTO sends partial results reporting
+	// the points written so far every IntoProgressPoints points or every
+	// IntoProgressInterval, whichever comes first.
+	IntoProgressPoints   int
+	IntoProgressInterval time.Duration
+
+	// The maximum points per second a SELECT INTO writes. Zero uses the
+	// statement executor's setting, which this can only lower.
+	IntoWriteRateLimit int
+
+	// The maximum time each SELECT of the query runs for. Zero only applies
+	// the task manager's query timeout, which still ends the query if it is
+	// shorter.
+	Timeout time.Duration
+
 	// If this query is being executed in a read-only context.
 	ReadOnly bool
 
+	// If set, statements changing data or metadata fail in a read-only
+	// context rather than being executed with a warning.
+	StrictReadOnly bool
+
 	// Node to execute on.
 	NodeID uint64
 
 	// Quiet suppresses non-essential output from the query executor.
 	Quiet bool
 
+	// ReadConsistency controls which owners remote shards are read from.
+	ReadConsistency ReadConsistency
+
+	// Priority orders the SELECT statements of the query against those of
+	// other queries waiting to run.
+	Priority Priority
+
 	// AbortCh is a channel that signals when results are no longer desired by the caller.
 	AbortCh <-chan struct{}
 }
 
+// ReadConsistency controls how a SELECT chooses between the owners of a shard.
+type ReadConsistency int
+
+const (
+	// ReadConsistencyLocal reads each shard from the local node if it owns
+	// the shard and from a random owner otherwise. This is the default.
+	ReadConsistencyLocal ReadConsistency = iota
+
+	// ReadConsistencyQuorum prefers owners that have acknowledged the latest
+	// writes, fails over to other owners when one is unreachable, and warns
+	// when the result may miss recently written points.
+	ReadConsistencyQuorum
+)
+
+// ErrInvalidReadConsistency is returned when parsing an unknown read consistency.
+var ErrInvalidReadConsistency = errors.New("invalid read consistency")
+
+// ParseReadConsistency parses a read consistency from its name: "local" or "quorum-read".
+func ParseReadConsistency(s string) (ReadConsistency, error) {
+	switch strings.ToLower(s) {
+	case "", "local":
+		return ReadConsistencyLocal, nil
+	case "quorum-read":
+		return ReadConsistencyQuorum, nil
+	default:
+		return 0, ErrInvalidReadConsistency
+	}
+}
+
+// String returns the name of the read consistency.
+func (c ReadConsistency) String() string {
+	switch c {
+	case ReadConsistencyLocal:
+		return "local"
+	case ReadConsistencyQuorum:
+		return "quorum-read"
+	default:
+		return fmt.Sprintf("ReadConsistency(%d)", int(c))
+	}
+}
+
+// Priority is the scheduling class of a query.
+type Priority int
+
+const (
+	// LowPriority queries run after the others waiting, and may be killed to
+	// make room for high priority queries.
+	LowPriority Priority = -1
+
+	// NormalPriority is the default.
+	NormalPriority Priority = 0
+
+	// HighPriority queries run before the others waiting.
+	HighPriority Priority = 1
+)
+
+// ErrInvalidPriority is returned when parsing an unknown priority.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+// ParsePriority parses a priority from its name: "low", "normal" or "high".
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return LowPriority, nil
+	case "", "normal":
+		return NormalPriority, nil
+	case "high":
+		return HighPriority, nil
+	default:
+		return 0, ErrInvalidPriority
+	}
+}
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case LowPriority:
+		return "low"
+	case NormalPriority:
+		return "normal"
+	case HighPriority:
+		return "high"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+// MarshalText encodes the priority as its name.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a priority from its name.
+func (p *Priority) UnmarshalText(text []byte) error {
+	v, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
 type (
 	iteratorsContextKey struct{}
 	monitorContextKey   struct{}
@@ -422,14 +626,30 @@ func (e *Executor) recover(query *cnosql.Query, results chan *Result) {
 // Task is the internal data structure for managing queries.
 // For the public use data structure that gets returned, see Task.
 type Task struct {
+	// Progress counters, updated atomically while SHOW QUERIES reads them.
+	// Kept first for 64-bit alignment.
+	rowsN       int64
+	memoryBytes int64
+
+	id        uint64
+	nodeID    uint64
 	query     string
 	database  string
+	user      string
+	session   uint64
+	priority  Priority
 	status    TaskStatus
 	startTime time.Time
 	closing   chan struct{}
 	monitorCh chan error
 	err       error
 	mu        sync.Mutex
+
+	// Other nodes running part of the query, and whether the kill is
+	// still being propagated to them.
+	participants map[uint64]struct{}
+	draining     bool
+	detached     bool
 }
 
 // Monitor starts a new goroutine that will monitor a query. The function
@@ -474,6 +694,57 @@ func (q *Task) close() {
 	q.mu.Unlock()
 }
 
+// Status returns the status of the task.
+func (q *Task) Status() TaskStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.draining {
+		return DrainingTask
+	}
+	return q.status
+}
+
+// addParticipant records that node nodeID runs part of the query.
+func (q *Task) addParticipant(nodeID uint64) {
+	q.mu.Lock()
+	if q.participants == nil {
+		q.participants = make(map[uint64]struct{})
+	}
+	q.participants[nodeID] = struct{}{}
+	q.mu.Unlock()
+}
+
+// drain marks the killed task as draining and returns the nodes the kill
+// must be propagated to.
+func (q *Task) drain() []uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	nodeIDs := make([]uint64, 0, len(q.participants))
+	for nodeID := range q.participants {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	q.draining = len(nodeIDs) > 0
+	return nodeIDs
+}
+
+// drained marks the task as no longer draining and returns whether it was
+// detached meanwhile.
+func (q *Task) drained() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.draining = false
+	return q.detached
+}
+
+// detach marks the task as detached and returns whether it is still
+// draining, in which case it must be kept until it is drained.
+func (q *Task) detach() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.detached = true
+	return q.draining
+}
+
 func (q *Task) kill() error {
 	q.mu.Lock()
 	if q.status == KilledTask {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/cnosdb/cnosdb/vend/cnosql"
@@ -47,13 +48,83 @@ func (p *preparedStatement) Explain() (string, error) {
 		fmt.Fprintf(&buf, "NUMBER OF BLOCKS: %d\n", node.Cost.BlocksRead)
 		fmt.Fprintf(&buf, "SIZE OF BLOCKS: %d\n", node.Cost.BlockSize)
 	}
+
+	if err := p.explainEstimates(&buf, ic.nodes); err != nil {
+		return "", err
+	}
 	return buf.String(), nil
 }
 
+// estimatedPointsPerBlock is the number of points assumed in each block
+// when estimating the points a query scans. It is the maximum number of
+// points the storage engine writes to a block by default.
+const estimatedPointsPerBlock = 1000
+
+// explainEstimates writes the shards, series, buckets and points the query
+// is estimated to read, and which SELECT limits they would exceed.
+func (p *preparedStatement) explainEstimates(buf *bytes.Buffer, nodes []planNode) error {
+	// Each expression on a measurement reads the same series, so count the
+	// series of a measurement once.
+	var shardN, pointN int64
+	series := make(map[string]int64)
+	var names []string
+	for _, node := range nodes {
+		if node.Cost.NumShards > shardN {
+			shardN = node.Cost.NumShards
+		}
+		if _, ok := series[node.Measurement]; !ok {
+			names = append(names, node.Measurement)
+		}
+		if node.Cost.NumSeries > series[node.Measurement] {
+			series[node.Measurement] = node.Cost.NumSeries
+		}
+		pointN += node.Cost.CachedValues + node.Cost.BlocksRead*estimatedPointsPerBlock
+	}
+	sort.Strings(names)
+
+	var seriesN int64
+	for _, name := range names {
+		seriesN += series[name]
+	}
+
+	buckets, err := bucketsN(p.stmt, p.opt)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("ESTIMATES\n")
+	fmt.Fprintf(buf, "SHARDS: %d\n", shardN)
+	for _, name := range names {
+		fmt.Fprintf(buf, "SERIES OF %s: %d\n", name, series[name])
+	}
+	fmt.Fprintf(buf, "SERIES: %d\n", seriesN)
+	fmt.Fprintf(buf, "BUCKETS: %d\n", buckets)
+	fmt.Fprintf(buf, "POINTS: %d\n", pointN)
+
+	for _, limit := range []struct {
+		name  string
+		n     int64
+		limit int
+	}{
+		{"max-select-series", seriesN, p.opt.MaxSeriesN},
+		{"max-select-buckets", buckets, p.maxBucketsN},
+		{"max-select-point", pointN, p.maxPointN},
+	} {
+		if limit.limit > 0 && limit.n > int64(limit.limit) {
+			fmt.Fprintf(buf, "LIMIT EXCEEDED: %s (%d/%d)\n", limit.name, limit.n, limit.limit)
+		}
+	}
+	return nil
+}
+
 type planNode struct {
-	Expr cnosql.Expr
-	Aux  []cnosql.VarRef
-	Cost IteratorCost
+	Expr        cnosql.Expr
+	Aux         []cnosql.VarRef
+	Cost        IteratorCost
+	Measurement string
 }
 
 type explainIteratorCreator struct {
@@ -70,9 +141,10 @@ func (e *explainIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.M
 		return nil, err
 	}
 	e.nodes = append(e.nodes, planNode{
-		Expr: opt.Expr,
-		Aux:  opt.Aux,
-		Cost: cost,
+		Expr:        opt.Expr,
+		Aux:         opt.Aux,
+		Cost:        cost,
+		Measurement: m.Name,
 	})
 	return &nilFloatIterator{}, nil
 }
@@ -10,6 +10,9 @@ import (
 )
 
 const (
+	// InfoLevel is the message level for an informational message.
+	InfoLevel = "info"
+
 	// WarningLevel is the message level for a warning.
 	WarningLevel = "warning"
 )
@@ -63,10 +66,30 @@ func LimitTagSets(a []*TagSet, slimit, soffset int) []*TagSet {
 	return a[soffset : soffset+slimit]
 }
 
+// DeprecationCode is the message code for a deprecated statement form.
+const DeprecationCode = "deprecation"
+
 // Message represents a user-facing message to be included with the result.
 type Message struct {
 	Level string `json:"level"`
 	Text  string `json:"text"`
+
+	// Code classifies the message, e.g. DeprecationCode. Optional.
+	Code string `json:"code,omitempty"`
+
+	// Replacement is the suggested replacement for a deprecated form. Optional.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// DeprecationWarning generates a warning message that tells the user the form
+// they are using is deprecated and what to use instead.
+func DeprecationWarning(text, replacement string) *Message {
+	return &Message{
+		Level:       WarningLevel,
+		Text:        text,
+		Code:        DeprecationCode,
+		Replacement: replacement,
+	}
 }
 
 // ReadOnlyWarning generates a warning message that tells the user the command
@@ -81,6 +104,12 @@ func ReadOnlyWarning(stmt string) *Message {
 	}
 }
 
+// ReadOnlyError returns the error refusing a statement changing data or
+// metadata in a strict read-only context.
+func ReadOnlyError(stmt string) error {
+	return fmt.Errorf("permission denied: '%s' cannot be executed in a read only context, please use a POST request instead", stmt)
+}
+
 // Result represents a resultset returned from a single statement.
 // Rows represents a list of rows that can be sorted consistently by name/tag.
 type Result struct {
@@ -37,6 +37,13 @@ type SelectOptions struct {
 
 	// Maximum number of buckets for a statement.
 	MaxBucketsN int
+
+	// If set, preparing a statement does not fail when it exceeds
+	// MaxBucketsN, so that EXPLAIN can report the limits it would exceed.
+	ExplainLimits bool
+
+	// ReadConsistency controls which owners remote shards are read from.
+	ReadConsistency ReadConsistency
 }
 
 // ShardMapper retrieves and maps shards into an IteratorCreator that can later be
@@ -103,9 +110,10 @@ type preparedStatement struct {
 		IteratorCreator
 		io.Closer
 	}
-	columns   []string
-	maxPointN int
-	now       time.Time
+	columns     []string
+	maxPointN   int
+	maxBucketsN int
+	now         time.Time
 }
 
 func (p *preparedStatement) Select(ctx context.Context) (Cursor, error) {
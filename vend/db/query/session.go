@@ -0,0 +1,194 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+)
+
+// Session represents a client of the query service: the connections of one
+// user from one remote host. Services accepting connections, such as the
+// HTTP service, open sessions with TaskManager.OpenSession.
+type Session struct {
+	// Time of the last request in Unix nanoseconds. Updated atomically and
+	// kept first for 64-bit alignment.
+	lastActivity int64
+
+	ID              uint64
+	RemoteAddr      string
+	User            string
+	AuthenticatedAt time.Time
+
+	// Open connections of the session, closed when the session is killed.
+	// Guarded by the mutex of the task manager.
+	conns map[io.Closer]struct{}
+}
+
+// Touch records activity on the session.
+func (s *Session) Touch() {
+	atomic.StoreInt64(&s.lastActivity, time.Now().UnixNano())
+}
+
+// LastActivity returns the time of the last request of the session.
+func (s *Session) LastActivity() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastActivity))
+}
+
+type sessionKey struct {
+	remoteAddr string
+	user       string
+}
+
+// OpenSession returns the session of user connected from remoteAddr, opening
+// a new one if there is none, and adds conn to its connections. conn is
+// closed if the session is killed; the caller must call CloseSessionConn once
+// conn is closed.
+func (t *TaskManager) OpenSession(remoteAddr, user string, conn io.Closer) *Session {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sessions == nil {
+		t.sessions = make(map[uint64]*Session)
+		t.sessionKeys = make(map[sessionKey]*Session)
+	}
+
+	k := sessionKey{remoteAddr: remoteAddr, user: user}
+	s := t.sessionKeys[k]
+	if s == nil {
+		t.nextSessionID++
+		s = &Session{
+			ID:              t.nextSessionID,
+			RemoteAddr:      remoteAddr,
+			User:            user,
+			AuthenticatedAt: time.Now(),
+			conns:           make(map[io.Closer]struct{}),
+		}
+		t.sessions[s.ID] = s
+		t.sessionKeys[k] = s
+	}
+	s.conns[conn] = struct{}{}
+	s.Touch()
+	return s
+}
+
+// CloseSessionConn removes a closed connection from a session. The session
+// is removed once it has neither connections nor running queries.
+func (t *TaskManager) CloseSessionConn(id uint64, conn io.Closer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if s := t.sessions[id]; s != nil {
+		delete(s.conns, conn)
+		t.removeIdleSession(s)
+	}
+}
+
+// KillSession kills the running queries of a session and closes its
+// connections.
+func (t *TaskManager) KillSession(id uint64) error {
+	t.mu.Lock()
+	s := t.sessions[id]
+	if s == nil {
+		t.mu.Unlock()
+		return fmt.Errorf("no such session id: %d", id)
+	}
+	t.removeSession(s)
+
+	var tasks []*Task
+	for _, qi := range t.queries {
+		if qi.session == id {
+			tasks = append(tasks, qi)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, qi := range tasks {
+		// Queries killed concurrently are already taken care of.
+		_ = qi.kill()
+	}
+	for c := range s.conns {
+		c.Close()
+	}
+	return nil
+}
+
+// removeIdleSession removes s if it has neither connections nor running
+// queries. The mutex must be held.
+func (t *TaskManager) removeIdleSession(s *Session) {
+	if len(s.conns) > 0 || t.sessionQueriesN(s.ID) > 0 {
+		return
+	}
+	t.removeSession(s)
+}
+
+// removeSession removes s from the sessions. The mutex must be held.
+func (t *TaskManager) removeSession(s *Session) {
+	delete(t.sessions, s.ID)
+	delete(t.sessionKeys, sessionKey{remoteAddr: s.RemoteAddr, user: s.User})
+}
+
+// sessionQueriesN returns the number of running queries of a session. The
+// mutex must be held.
+func (t *TaskManager) sessionQueriesN(id uint64) int {
+	n := 0
+	for _, qi := range t.queries {
+		if qi.session == id {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *TaskManager) executeKillSessionStatement(ctx *ExecutionContext, stmt *cnosql.KillSessionStatement) error {
+	// Users other than admins may only kill their own sessions. Sessions
+	// of other users are reported as missing so they aren't disclosed.
+	if ctx.UserID != "" && !ctx.UserAdmin {
+		t.mu.RLock()
+		s := t.sessions[stmt.SessionID]
+		t.mu.RUnlock()
+		if s == nil || s.User != ctx.UserID {
+			return fmt.Errorf("no such session id: %d", stmt.SessionID)
+		}
+	}
+	return t.KillSession(stmt.SessionID)
+}
+
+func (t *TaskManager) executeShowSessionsStatement(ctx *ExecutionContext) models.Rows {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// Users other than admins only ever see their own sessions.
+	restricted := ctx.UserID != "" && !ctx.UserAdmin
+
+	sessions := make([]*Session, 0, len(t.sessions))
+	for _, s := range t.sessions {
+		if restricted && s.User != ctx.UserID {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+
+	values := make([][]interface{}, 0, len(sessions))
+	for _, s := range sessions {
+		values = append(values, []interface{}{
+			s.ID,
+			s.RemoteAddr,
+			s.User,
+			s.AuthenticatedAt.UTC().Format(time.RFC3339Nano),
+			t.sessionQueriesN(s.ID),
+			len(s.conns),
+			s.LastActivity().UTC().Format(time.RFC3339Nano),
+		})
+	}
+
+	return []*models.Row{{
+		Columns: []string{"id", "remote_addr", "user", "authenticated", "active_queries", "connections", "last_activity"},
+		Values:  values,
+	}}
+}
@@ -10,10 +10,6 @@ import (
 // RewriteStatement rewrites stmt into a new statement, if applicable.
 func RewriteStatement(stmt cnosql.Statement) (cnosql.Statement, error) {
 	switch stmt := stmt.(type) {
-	case *cnosql.ShowFieldKeysStatement:
-		return rewriteShowFieldKeysStatement(stmt)
-	case *cnosql.ShowFieldKeyCardinalityStatement:
-		return rewriteShowFieldKeyCardinalityStatement(stmt)
 	case *cnosql.ShowMeasurementsStatement:
 		return rewriteShowMeasurementsStatement(stmt)
 	case *cnosql.ShowMeasurementCardinalityStatement:
@@ -24,8 +20,6 @@ func RewriteStatement(stmt cnosql.Statement) (cnosql.Statement, error) {
 		return rewriteShowSeriesCardinalityStatement(stmt)
 	case *cnosql.ShowTagKeysStatement:
 		return rewriteShowTagKeysStatement(stmt)
-	case *cnosql.ShowTagKeyCardinalityStatement:
-		return rewriteShowTagKeyCardinalityStatement(stmt)
 	case *cnosql.ShowTagValuesStatement:
 		return rewriteShowTagValuesStatement(stmt)
 	case *cnosql.ShowTagValuesCardinalityStatement:
@@ -35,60 +29,6 @@ func RewriteStatement(stmt cnosql.Statement) (cnosql.Statement, error) {
 	}
 }
 
-func rewriteShowFieldKeysStatement(stmt *cnosql.ShowFieldKeysStatement) (cnosql.Statement, error) {
-	return &cnosql.SelectStatement{
-		Fields: cnosql.Fields([]*cnosql.Field{
-			{Expr: &cnosql.VarRef{Val: "fieldKey"}},
-			{Expr: &cnosql.VarRef{Val: "fieldType"}},
-		}),
-		Sources:    rewriteSources(stmt.Sources, "_fieldKeys", stmt.Database),
-		Condition:  rewriteSourcesCondition(stmt.Sources, nil),
-		Offset:     stmt.Offset,
-		Limit:      stmt.Limit,
-		SortFields: stmt.SortFields,
-		OmitTime:   true,
-		Dedupe:     true,
-		IsRawQuery: true,
-	}, nil
-}
-
-func rewriteShowFieldKeyCardinalityStatement(stmt *cnosql.ShowFieldKeyCardinalityStatement) (cnosql.Statement, error) {
-	// Check for time in WHERE clause (not supported).
-	if cnosql.HasTimeExpr(stmt.Condition) {
-		return nil, errors.New("SHOW FIELD KEY CARDINALITY doesn't support time in WHERE clause")
-	}
-
-	// Use all field keys, if zero.
-	if len(stmt.Sources) == 0 {
-		stmt.Sources = cnosql.Sources{
-			&cnosql.Measurement{Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`.+`)}},
-		}
-	}
-
-	return &cnosql.SelectStatement{
-		Fields: []*cnosql.Field{
-			{
-				Expr: &cnosql.Call{
-					Name: "count",
-					Args: []cnosql.Expr{
-						&cnosql.Call{
-							Name: "distinct",
-							Args: []cnosql.Expr{&cnosql.VarRef{Val: "_fieldKey"}},
-						},
-					},
-				},
-				Alias: "count",
-			},
-		},
-		Sources:    rewriteSources2(stmt.Sources, stmt.Database),
-		Condition:  stmt.Condition,
-		Dimensions: stmt.Dimensions,
-		Offset:     stmt.Offset,
-		Limit:      stmt.Limit,
-		OmitTime:   true,
-	}, nil
-}
-
 func rewriteShowMeasurementsStatement(stmt *cnosql.ShowMeasurementsStatement) (cnosql.Statement, error) {
 	var sources cnosql.Sources
 	if stmt.Source != nil {
@@ -120,6 +60,18 @@ func rewriteShowMeasurementCardinalityStatement(stmt *cnosql.ShowMeasurementCard
 		return nil, errors.New("SHOW MEASUREMENT EXACT CARDINALITY doesn't support time in WHERE clause")
 	}
 
+	// Without GROUP BY the sources and condition are evaluated against the
+	// index by the statement executor.
+	if stmt.Dimensions == nil {
+		return &cnosql.ShowMeasurementCardinalityStatement{
+			Exact:     stmt.Exact,
+			Database:  stmt.Database,
+			Condition: rewriteSourcesCondition(stmt.Sources, stmt.Condition),
+			Limit:     stmt.Limit,
+			Offset:    stmt.Offset,
+		}, nil
+	}
+
 	// Use all measurements, if zero.
 	if len(stmt.Sources) == 0 {
 		stmt.Sources = cnosql.Sources{
@@ -153,6 +105,19 @@ func rewriteShowMeasurementCardinalityStatement(stmt *cnosql.ShowMeasurementCard
 }
 
 func rewriteShowSeriesStatement(stmt *cnosql.ShowSeriesStatement) (cnosql.Statement, error) {
+	// Without a time condition the index can answer the query directly, so
+	// leave it to the statement executor which streams the series keys.
+	if !cnosql.HasTimeExpr(stmt.Condition) {
+		return &cnosql.ShowSeriesStatement{
+			Database:   stmt.Database,
+			Sources:    stmt.Sources,
+			Condition:  rewriteSourcesCondition(stmt.Sources, stmt.Condition),
+			SortFields: stmt.SortFields,
+			Limit:      stmt.Limit,
+			Offset:     stmt.Offset,
+		}, nil
+	}
+
 	s := &cnosql.SelectStatement{
 		Condition:  stmt.Condition,
 		Offset:     stmt.Offset,
@@ -163,14 +128,6 @@ func rewriteShowSeriesStatement(stmt *cnosql.ShowSeriesStatement) (cnosql.Statem
 		Dedupe:     true,
 		IsRawQuery: true,
 	}
-	// Check if we can exclusively use the index.
-	if !cnosql.HasTimeExpr(stmt.Condition) {
-		s.Fields = []*cnosql.Field{{Expr: &cnosql.VarRef{Val: "key"}}}
-		s.Sources = rewriteSources(stmt.Sources, "_series", stmt.Database)
-		s.Condition = rewriteSourcesCondition(s.Sources, s.Condition)
-		return s, nil
-	}
-
 	// The query is bounded by time then it will have to query TSM data rather
 	// than utilising the index via system iterators.
 	s.Fields = []*cnosql.Field{
@@ -188,6 +145,12 @@ func rewriteShowSeriesCardinalityStatement(stmt *cnosql.ShowSeriesCardinalitySta
 		return stmt, nil
 	}
 
+	// The exact per-measurement breakdown of the whole database is answered
+	// from the index by the statement executor.
+	if stmt.Exact && stmt.Sources == nil && stmt.Condition == nil && stmt.Dimensions == nil {
+		return stmt, nil
+	}
+
 	// Check for time in WHERE clause (not supported).
 	if cnosql.HasTimeExpr(stmt.Condition) {
 		return nil, errors.New("SHOW SERIES EXACT CARDINALITY doesn't support time in WHERE clause")
@@ -222,52 +185,14 @@ func rewriteShowSeriesCardinalityStatement(stmt *cnosql.ShowSeriesCardinalitySta
 	}, nil
 }
 
+// rewriteShowTagValuesStatement pushes the sources into the condition. The
+// WITH KEY clause is applied by the statement executor.
 func rewriteShowTagValuesStatement(stmt *cnosql.ShowTagValuesStatement) (cnosql.Statement, error) {
-	var expr cnosql.Expr
-	if list, ok := stmt.TagKeyExpr.(*cnosql.ListLiteral); ok {
-		for _, tagKey := range list.Vals {
-			tagExpr := &cnosql.BinaryExpr{
-				Op:  cnosql.EQ,
-				LHS: &cnosql.VarRef{Val: "_tagKey"},
-				RHS: &cnosql.StringLiteral{Val: tagKey},
-			}
-
-			if expr != nil {
-				expr = &cnosql.BinaryExpr{
-					Op:  cnosql.OR,
-					LHS: expr,
-					RHS: tagExpr,
-				}
-			} else {
-				expr = tagExpr
-			}
-		}
-	} else {
-		expr = &cnosql.BinaryExpr{
-			Op:  stmt.Op,
-			LHS: &cnosql.VarRef{Val: "_tagKey"},
-			RHS: stmt.TagKeyExpr,
-		}
-	}
-
-	// Set condition or "AND" together.
-	condition := stmt.Condition
-	if condition == nil {
-		condition = expr
-	} else {
-		condition = &cnosql.BinaryExpr{
-			Op:  cnosql.AND,
-			LHS: &cnosql.ParenExpr{Expr: condition},
-			RHS: &cnosql.ParenExpr{Expr: expr},
-		}
-	}
-	condition = rewriteSourcesCondition(stmt.Sources, condition)
-
 	return &cnosql.ShowTagValuesStatement{
 		Database:   stmt.Database,
 		Op:         stmt.Op,
 		TagKeyExpr: stmt.TagKeyExpr,
-		Condition:  condition,
+		Condition:  rewriteSourcesCondition(stmt.Sources, stmt.Condition),
 		SortFields: stmt.SortFields,
 		Limit:      stmt.Limit,
 		Offset:     stmt.Offset,
@@ -275,79 +200,22 @@ func rewriteShowTagValuesStatement(stmt *cnosql.ShowTagValuesStatement) (cnosql.
 }
 
 func rewriteShowTagValuesCardinalityStatement(stmt *cnosql.ShowTagValuesCardinalityStatement) (cnosql.Statement, error) {
-	// Use all measurements, if zero.
-	if len(stmt.Sources) == 0 {
-		stmt.Sources = cnosql.Sources{
-			&cnosql.Measurement{Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`.+`)}},
-		}
-	}
-
-	var expr cnosql.Expr
-	if list, ok := stmt.TagKeyExpr.(*cnosql.ListLiteral); ok {
-		for _, tagKey := range list.Vals {
-			tagExpr := &cnosql.BinaryExpr{
-				Op:  cnosql.EQ,
-				LHS: &cnosql.VarRef{Val: "_tagKey"},
-				RHS: &cnosql.StringLiteral{Val: tagKey},
-			}
-
-			if expr != nil {
-				expr = &cnosql.BinaryExpr{
-					Op:  cnosql.OR,
-					LHS: expr,
-					RHS: tagExpr,
-				}
-			} else {
-				expr = tagExpr
-			}
-		}
-	} else {
-		expr = &cnosql.BinaryExpr{
-			Op:  stmt.Op,
-			LHS: &cnosql.VarRef{Val: "_tagKey"},
-			RHS: stmt.TagKeyExpr,
-		}
-	}
-
-	// Set condition or "AND" together.
-	condition := stmt.Condition
-	if condition == nil {
-		condition = expr
-	} else {
-		condition = &cnosql.BinaryExpr{
-			Op:  cnosql.AND,
-			LHS: &cnosql.ParenExpr{Expr: condition},
-			RHS: &cnosql.ParenExpr{Expr: expr},
-		}
-	}
-
-	return &cnosql.SelectStatement{
-		Fields: []*cnosql.Field{
-			{
-				Expr: &cnosql.Call{
-					Name: "count",
-					Args: []cnosql.Expr{
-						&cnosql.Call{
-							Name: "distinct",
-							Args: []cnosql.Expr{&cnosql.VarRef{Val: "_tagValue"}},
-						},
-					},
-				},
-				Alias: "count",
-			},
-		},
-		Sources:    rewriteSources2(stmt.Sources, stmt.Database),
-		Condition:  condition,
+	return &cnosql.ShowTagValuesCardinalityStatement{
+		Database:   stmt.Database,
+		Exact:      stmt.Exact,
+		Op:         stmt.Op,
+		TagKeyExpr: stmt.TagKeyExpr,
+		Condition:  rewriteSourcesCondition(stmt.Sources, stmt.Condition),
 		Dimensions: stmt.Dimensions,
-		Offset:     stmt.Offset,
 		Limit:      stmt.Limit,
-		OmitTime:   true,
+		Offset:     stmt.Offset,
 	}, nil
 }
 
 func rewriteShowTagKeysStatement(stmt *cnosql.ShowTagKeysStatement) (cnosql.Statement, error) {
 	return &cnosql.ShowTagKeysStatement{
 		Database:   stmt.Database,
+		Sources:    stmt.Sources,
 		Condition:  rewriteSourcesCondition(stmt.Sources, stmt.Condition),
 		SortFields: stmt.SortFields,
 		Limit:      stmt.Limit,
@@ -357,46 +225,6 @@ func rewriteShowTagKeysStatement(stmt *cnosql.ShowTagKeysStatement) (cnosql.Stat
 	}, nil
 }
 
-func rewriteShowTagKeyCardinalityStatement(stmt *cnosql.ShowTagKeyCardinalityStatement) (cnosql.Statement, error) {
-	// Check for time in WHERE clause (not supported).
-	if cnosql.HasTimeExpr(stmt.Condition) {
-		return nil, errors.New("SHOW TAG KEY EXACT CARDINALITY doesn't support time in WHERE clause")
-	}
-
-	// Use all measurements, if zero.
-	if len(stmt.Sources) == 0 {
-		stmt.Sources = cnosql.Sources{
-			&cnosql.Measurement{Regex: &cnosql.RegexLiteral{Val: regexp.MustCompile(`.+`)}},
-		}
-	}
-
-	return &cnosql.SelectStatement{
-		Fields: []*cnosql.Field{
-			{
-				Expr: &cnosql.Call{
-					Name: "count",
-					Args: []cnosql.Expr{
-						&cnosql.Call{
-							Name: "distinct",
-							Args: []cnosql.Expr{&cnosql.VarRef{Val: "_tagKey"}},
-						},
-					},
-				},
-				Alias: "count",
-			},
-		},
-		Sources:    rewriteSources2(stmt.Sources, stmt.Database),
-		Condition:  stmt.Condition,
-		Dimensions: stmt.Dimensions,
-		Offset:     stmt.Offset,
-		Limit:      stmt.Limit,
-		OmitTime:   true,
-	}, nil
-}
-
-// rewriteSources rewrites sources to include the provided system iterator.
-//
-// rewriteSources also sets the default database where necessary.
 func rewriteSources(sources cnosql.Sources, systemIterator, defaultDatabase string) cnosql.Sources {
 	newSources := cnosql.Sources{}
 	for _, src := range sources {
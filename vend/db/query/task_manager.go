@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cnosdb/cnosdb/vend/cnosql"
@@ -28,6 +29,10 @@ const (
 	// KilledTask is set when the task is killed, but resources are still
 	// being used.
 	KilledTask
+
+	// DrainingTask is reported for a killed task until the other nodes
+	// running part of it acknowledged the kill.
+	DrainingTask
 )
 
 func (t TaskStatus) String() string {
@@ -36,6 +41,8 @@ func (t TaskStatus) String() string {
 		return "running"
 	case KilledTask:
 		return "killed"
+	case DrainingTask:
+		return "killed (draining)"
 	default:
 		return "unknown"
 	}
@@ -51,6 +58,8 @@ func (t *TaskStatus) UnmarshalJSON(data []byte) error {
 		*t = RunningTask
 	} else if bytes.Equal(data, []byte("killed")) {
 		*t = KilledTask
+	} else if bytes.Equal(data, []byte("killed (draining)")) {
+		*t = DrainingTask
 	} else if bytes.Equal(data, []byte("unknown")) {
 		*t = TaskStatus(0)
 	} else {
@@ -75,11 +84,27 @@ type TaskManager struct {
 	// Defaults to discarding all log output.
 	Logger *zap.Logger
 
+	// RemoteQueryKiller kills the parts of killed queries running on other
+	// nodes. If nil, those parts stop when their connection is closed.
+	RemoteQueryKiller RemoteQueryKiller
+
 	// Used for managing and tracking running queries.
 	queries  map[uint64]*Task
 	nextID   uint64
 	mu       sync.RWMutex
 	shutdown bool
+
+	// Client sessions by ID and by remote address and user.
+	sessions      map[uint64]*Session
+	sessionKeys   map[sessionKey]*Session
+	nextSessionID uint64
+}
+
+// RemoteQueryKiller kills the parts of queries that run on other nodes.
+type RemoteQueryKiller interface {
+	// KillRemoteQuery kills the part of the query qid started on node origin
+	// that runs on node nodeID. It returns once that node acknowledged.
+	KillRemoteQuery(nodeID, origin, qid uint64) error
 }
 
 // NewTaskManager creates a new TaskManager.
@@ -96,7 +121,7 @@ func NewTaskManager() *TaskManager {
 func (t *TaskManager) ExecuteStatement(ctx *ExecutionContext, stmt cnosql.Statement) error {
 	switch stmt := stmt.(type) {
 	case *cnosql.ShowQueriesStatement:
-		rows, err := t.executeShowQueriesStatement(stmt)
+		rows, err := t.executeShowQueriesStatement(ctx, stmt)
 		if err != nil {
 			return err
 		}
@@ -116,6 +141,22 @@ func (t *TaskManager) ExecuteStatement(ctx *ExecutionContext, stmt cnosql.Statem
 		ctx.Send(&Result{
 			Messages: messages,
 		})
+	case *cnosql.ShowSessionsStatement:
+		ctx.Send(&Result{
+			Series: t.executeShowSessionsStatement(ctx),
+		})
+	case *cnosql.KillSessionStatement:
+		var messages []*Message
+		if ctx.ReadOnly {
+			messages = append(messages, ReadOnlyWarning(stmt.String()))
+		}
+
+		if err := t.executeKillSessionStatement(ctx, stmt); err != nil {
+			return err
+		}
+		ctx.Send(&Result{
+			Messages: messages,
+		})
 	default:
 		return ErrInvalidQuery
 	}
@@ -126,14 +167,31 @@ func (t *TaskManager) executeKillQueryStatement(stmt *cnosql.KillQueryStatement)
 	return t.KillQuery(stmt.QueryID)
 }
 
-func (t *TaskManager) executeShowQueriesStatement(q *cnosql.ShowQueriesStatement) (models.Rows, error) {
+func (t *TaskManager) executeShowQueriesStatement(ctx *ExecutionContext, q *cnosql.ShowQueriesStatement) (models.Rows, error) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
 	now := time.Now()
 
+	// Users other than admins only ever see their own queries.
+	restricted := ctx.UserID != "" && !ctx.UserAdmin
+
 	values := make([][]interface{}, 0, len(t.queries))
 	for id, qi := range t.queries {
+		if restricted && qi.user != ctx.UserID {
+			continue
+		}
+		if q.Condition != nil && !cnosql.EvalBool(q.Condition, map[string]interface{}{
+			"qid":      int64(id),
+			"query":    qi.query,
+			"database": qi.database,
+			"user":     qi.user,
+			"status":   qi.Status().String(),
+			"priority": qi.priority.String(),
+		}) {
+			continue
+		}
+
 		d := now.Sub(qi.startTime)
 
 		switch {
@@ -145,11 +203,11 @@ func (t *TaskManager) executeShowQueriesStatement(q *cnosql.ShowQueriesStatement
 			d = d - (d % time.Microsecond)
 		}
 
-		values = append(values, []interface{}{id, qi.query, qi.database, d.String(), qi.status.String()})
+		values = append(values, []interface{}{id, qi.query, qi.database, qi.user, d.String(), qi.Status().String(), atomic.LoadInt64(&qi.rowsN), atomic.LoadInt64(&qi.memoryBytes), qi.priority.String()})
 	}
 
 	return []*models.Row{{
-		Columns: []string{"qid", "query", "database", "duration", "status"},
+		Columns: []string{"qid", "query", "database", "user", "duration", "status", "rows", "memory_bytes", "priority"},
 		Values:  values,
 	}}, nil
 }
@@ -184,8 +242,13 @@ func (t *TaskManager) AttachQuery(q *cnosql.Query, opt ExecutionOptions, interru
 
 	qid := t.nextID
 	query := &Task{
+		id:        qid,
+		nodeID:    opt.NodeID,
 		query:     q.String(),
 		database:  opt.Database,
+		user:      opt.UserID,
+		session:   opt.SessionID,
+		priority:  opt.Priority,
 		status:    RunningTask,
 		startTime: time.Now(),
 		closing:   make(chan struct{}),
@@ -215,6 +278,10 @@ func (t *TaskManager) AttachQuery(q *cnosql.Query, opt ExecutionOptions, interru
 		QueryID:          qid,
 		task:             query,
 		ExecutionOptions: opt,
+		kill: func(err error) error {
+			query.setError(err)
+			return t.KillQuery(qid)
+		},
 	}
 	ctx.watch()
 	return ctx, func() { t.DetachQuery(qid) }, nil
@@ -222,7 +289,8 @@ func (t *TaskManager) AttachQuery(q *cnosql.Query, opt ExecutionOptions, interru
 
 // KillQuery enters a query into the killed state and closes the channel
 // from the TaskManager. This method can be used to forcefully terminate a
-// running query.
+// running query. The kill is propagated to the other nodes running part of
+// the query, and the query is reported as draining until they acknowledged.
 func (t *TaskManager) KillQuery(qid uint64) error {
 	t.mu.Lock()
 	query := t.queries[qid]
@@ -231,7 +299,33 @@ func (t *TaskManager) KillQuery(qid uint64) error {
 	if query == nil {
 		return fmt.Errorf("no such query id: %d", qid)
 	}
-	return query.kill()
+	if err := query.kill(); err != nil {
+		return err
+	}
+
+	if t.RemoteQueryKiller != nil {
+		if nodeIDs := query.drain(); len(nodeIDs) > 0 {
+			go t.drainQuery(query, nodeIDs)
+		}
+	}
+	return nil
+}
+
+// drainQuery kills the parts of query running on the nodes nodeIDs. The
+// query is removed once they acknowledged if it was detached meanwhile.
+func (t *TaskManager) drainQuery(query *Task, nodeIDs []uint64) {
+	for _, nodeID := range nodeIDs {
+		if err := t.RemoteQueryKiller.KillRemoteQuery(nodeID, query.nodeID, query.id); err != nil {
+			t.Logger.Info("Failed to kill remote query",
+				zap.Uint64("qid", query.id), zap.Uint64("node_id", nodeID), zap.Error(err))
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if detached := query.drained(); detached && t.queries[query.id] == query {
+		delete(t.queries, query.id)
+	}
 }
 
 // DetachQuery removes a query from the query table. If the query is not in the
@@ -246,7 +340,14 @@ func (t *TaskManager) DetachQuery(qid uint64) error {
 	}
 
 	query.close()
-	delete(t.queries, qid)
+	if !query.detach() {
+		delete(t.queries, qid)
+	}
+
+	// The session may have lost its connections while the query ran.
+	if s := t.sessions[query.session]; s != nil {
+		t.removeIdleSession(s)
+	}
 	return nil
 }
 
@@ -255,8 +356,17 @@ type QueryInfo struct {
 	ID       uint64        `json:"id"`
 	Query    string        `json:"query"`
 	Database string        `json:"database"`
+	User     string        `json:"user"`
 	Duration time.Duration `json:"duration"`
 	Status   TaskStatus    `json:"status"`
+
+	// Rows is the number of rows emitted so far.
+	Rows int64 `json:"rows"`
+
+	// MemoryBytes approximates the result data the query currently holds.
+	MemoryBytes int64 `json:"memory_bytes"`
+
+	Priority Priority `json:"priority"`
 }
 
 // Queries returns a list of all running queries with information about them.
@@ -268,11 +378,15 @@ func (t *TaskManager) Queries() []QueryInfo {
 	queries := make([]QueryInfo, 0, len(t.queries))
 	for id, qi := range t.queries {
 		queries = append(queries, QueryInfo{
-			ID:       id,
-			Query:    qi.query,
-			Database: qi.database,
-			Duration: now.Sub(qi.startTime),
-			Status:   qi.status,
+			ID:          id,
+			Query:       qi.query,
+			Database:    qi.database,
+			User:        qi.user,
+			Duration:    now.Sub(qi.startTime),
+			Status:      qi.Status(),
+			Rows:        atomic.LoadInt64(&qi.rowsN),
+			MemoryBytes: atomic.LoadInt64(&qi.memoryBytes),
+			Priority:    qi.priority,
 		})
 	}
 	return queries
@@ -69,6 +69,10 @@ const (
 
 	// DefaultSeriesIDSetCacheSize is the default number of series ID sets to cache in the TSI index.
 	DefaultSeriesIDSetCacheSize = 100
+
+	// DefaultColdCheckInterval is how often the store looks for shards to
+	// move to the cold directory.
+	DefaultColdCheckInterval = time.Duration(10 * time.Minute)
 )
 
 // Config holds the configuration for the tsbd package.
@@ -85,6 +89,14 @@ type Config struct {
 	// disks or when WAL write contention is seen.  A value of 0 fsyncs every write to the WAL.
 	WALFsyncDelay toml.Duration `toml:"wal-fsync-delay"`
 
+	// ColdDir is the directory shards are moved to once they are older than
+	// the cold-after duration of their retention policy. Relocated shards are
+	// read-only. An empty value disables the cold tier.
+	ColdDir string `toml:"cold-dir"`
+
+	// ColdCheckInterval is how often shards are checked for a move to ColdDir.
+	ColdCheckInterval toml.Duration `toml:"cold-check-interval"`
+
 	// Enables unicode validation on series keys on write.
 	ValidateKeys bool `toml:"validate-keys"`
 
@@ -145,6 +157,8 @@ func NewConfig() Config {
 
 		QueryLogEnabled: true,
 
+		ColdCheckInterval: toml.Duration(DefaultColdCheckInterval),
+
 		CacheMaxMemorySize:             toml.Size(DefaultCacheMaxMemorySize),
 		CacheSnapshotMemorySize:        toml.Size(DefaultCacheSnapshotMemorySize),
 		CacheSnapshotWriteColdDuration: toml.Duration(DefaultCacheSnapshotWriteColdDuration),
@@ -176,6 +190,10 @@ func (c *Config) Validate() error {
 		return errors.New("max-concurrent-compactions must be non-negative")
 	}
 
+	if c.ColdDir != "" && c.ColdCheckInterval <= 0 {
+		return errors.New("cold-check-interval must be positive")
+	}
+
 	if c.SeriesIDSetCacheSize < 0 {
 		return errors.New("series-id-set-cache-size must be non-negative")
 	}
@@ -211,6 +229,8 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"dir":                                c.Dir,
 		"wal-dir":                            c.WALDir,
 		"wal-fsync-delay":                    c.WALFsyncDelay,
+		"cold-dir":                           c.ColdDir,
+		"cold-check-interval":                c.ColdCheckInterval,
 		"cache-max-memory-size":              c.CacheMaxMemorySize,
 		"cache-snapshot-memory-size":         c.CacheSnapshotMemorySize,
 		"cache-snapshot-write-cold-duration": c.CacheSnapshotWriteColdDuration,
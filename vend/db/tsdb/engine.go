@@ -79,6 +79,8 @@ type Engine interface {
 	LastModified() time.Time
 	DiskSize() int64
 	IsIdle() bool
+	IsCompacting() bool
+	HasWALSegments() bool
 	Free() error
 
 	io.WriterTo
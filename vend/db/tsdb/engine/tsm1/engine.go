@@ -886,15 +886,24 @@ func (e *Engine) LoadMetadataIndex(shardID uint64, index tsdb.Index) error {
 // shard is fully compacted.
 func (e *Engine) IsIdle() bool {
 	cacheEmpty := e.Cache.Size() == 0
+	return cacheEmpty && !e.IsCompacting() && e.CompactionPlan.FullyCompacted()
+}
 
+// IsCompacting returns true if a cache snapshot or TSM compaction is running.
+func (e *Engine) IsCompacting() bool {
 	runningCompactions := atomic.LoadInt64(&e.stats.CacheCompactionsActive)
 	runningCompactions += atomic.LoadInt64(&e.stats.TSMCompactionsActive[0])
 	runningCompactions += atomic.LoadInt64(&e.stats.TSMCompactionsActive[1])
 	runningCompactions += atomic.LoadInt64(&e.stats.TSMCompactionsActive[2])
 	runningCompactions += atomic.LoadInt64(&e.stats.TSMFullCompactionsActive)
 	runningCompactions += atomic.LoadInt64(&e.stats.TSMOptimizeCompactionsActive)
+	return runningCompactions > 0
+}
 
-	return cacheEmpty && runningCompactions == 0 && e.CompactionPlan.FullyCompacted()
+// HasWALSegments returns true if the WAL holds writes that have not been
+// snapshotted into TSM files yet.
+func (e *Engine) HasWALSegments() bool {
+	return e.WALEnabled && e.WAL.DiskSizeBytes() > 0
 }
 
 // Free releases any resources held by the engine to free up memory or CPU.
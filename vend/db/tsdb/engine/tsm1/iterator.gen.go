@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/metrics"
@@ -154,14 +155,31 @@ type floatInstrumentedIterator struct {
 	query.FloatIterator
 	span  *tracing.Span
 	group *metrics.Group
+
+	// Points returned and time spent reading them.
+	pointN   int64
+	readTime time.Duration
 }
 
 func newFloatInstrumentedIterator(inner query.FloatIterator, span *tracing.Span, group *metrics.Group) *floatInstrumentedIterator {
 	return &floatInstrumentedIterator{FloatIterator: inner, span: span, group: group}
 }
 
+func (itr *floatInstrumentedIterator) Next() (*query.FloatPoint, error) {
+	start := time.Now()
+	p, err := itr.FloatIterator.Next()
+	itr.readTime += time.Since(start)
+	if p != nil {
+		itr.pointN++
+	}
+	return p, err
+}
+
 func (itr *floatInstrumentedIterator) Close() error {
-	var f fields.Fields
+	f := fields.Fields{
+		fields.Int64("points_read", itr.pointN),
+		fields.Duration("read_time", itr.readTime),
+	}
 	itr.group.ForEach(func(v metrics.Metric) {
 		switch m := v.(type) {
 		case *metrics.Counter:
@@ -634,14 +652,31 @@ type integerInstrumentedIterator struct {
 	query.IntegerIterator
 	span  *tracing.Span
 	group *metrics.Group
+
+	// Points returned and time spent reading them.
+	pointN   int64
+	readTime time.Duration
 }
 
 func newIntegerInstrumentedIterator(inner query.IntegerIterator, span *tracing.Span, group *metrics.Group) *integerInstrumentedIterator {
 	return &integerInstrumentedIterator{IntegerIterator: inner, span: span, group: group}
 }
 
+func (itr *integerInstrumentedIterator) Next() (*query.IntegerPoint, error) {
+	start := time.Now()
+	p, err := itr.IntegerIterator.Next()
+	itr.readTime += time.Since(start)
+	if p != nil {
+		itr.pointN++
+	}
+	return p, err
+}
+
 func (itr *integerInstrumentedIterator) Close() error {
-	var f fields.Fields
+	f := fields.Fields{
+		fields.Int64("points_read", itr.pointN),
+		fields.Duration("read_time", itr.readTime),
+	}
 	itr.group.ForEach(func(v metrics.Metric) {
 		switch m := v.(type) {
 		case *metrics.Counter:
@@ -1114,14 +1149,31 @@ type unsignedInstrumentedIterator struct {
 	query.UnsignedIterator
 	span  *tracing.Span
 	group *metrics.Group
+
+	// Points returned and time spent reading them.
+	pointN   int64
+	readTime time.Duration
 }
 
 func newUnsignedInstrumentedIterator(inner query.UnsignedIterator, span *tracing.Span, group *metrics.Group) *unsignedInstrumentedIterator {
 	return &unsignedInstrumentedIterator{UnsignedIterator: inner, span: span, group: group}
 }
 
+func (itr *unsignedInstrumentedIterator) Next() (*query.UnsignedPoint, error) {
+	start := time.Now()
+	p, err := itr.UnsignedIterator.Next()
+	itr.readTime += time.Since(start)
+	if p != nil {
+		itr.pointN++
+	}
+	return p, err
+}
+
 func (itr *unsignedInstrumentedIterator) Close() error {
-	var f fields.Fields
+	f := fields.Fields{
+		fields.Int64("points_read", itr.pointN),
+		fields.Duration("read_time", itr.readTime),
+	}
 	itr.group.ForEach(func(v metrics.Metric) {
 		switch m := v.(type) {
 		case *metrics.Counter:
@@ -1594,14 +1646,31 @@ type stringInstrumentedIterator struct {
 	query.StringIterator
 	span  *tracing.Span
 	group *metrics.Group
+
+	// Points returned and time spent reading them.
+	pointN   int64
+	readTime time.Duration
 }
 
 func newStringInstrumentedIterator(inner query.StringIterator, span *tracing.Span, group *metrics.Group) *stringInstrumentedIterator {
 	return &stringInstrumentedIterator{StringIterator: inner, span: span, group: group}
 }
 
+func (itr *stringInstrumentedIterator) Next() (*query.StringPoint, error) {
+	start := time.Now()
+	p, err := itr.StringIterator.Next()
+	itr.readTime += time.Since(start)
+	if p != nil {
+		itr.pointN++
+	}
+	return p, err
+}
+
 func (itr *stringInstrumentedIterator) Close() error {
-	var f fields.Fields
+	f := fields.Fields{
+		fields.Int64("points_read", itr.pointN),
+		fields.Duration("read_time", itr.readTime),
+	}
 	itr.group.ForEach(func(v metrics.Metric) {
 		switch m := v.(type) {
 		case *metrics.Counter:
@@ -2074,14 +2143,31 @@ type booleanInstrumentedIterator struct {
 	query.BooleanIterator
 	span  *tracing.Span
 	group *metrics.Group
+
+	// Points returned and time spent reading them.
+	pointN   int64
+	readTime time.Duration
 }
 
 func newBooleanInstrumentedIterator(inner query.BooleanIterator, span *tracing.Span, group *metrics.Group) *booleanInstrumentedIterator {
 	return &booleanInstrumentedIterator{BooleanIterator: inner, span: span, group: group}
 }
 
+func (itr *booleanInstrumentedIterator) Next() (*query.BooleanPoint, error) {
+	start := time.Now()
+	p, err := itr.BooleanIterator.Next()
+	itr.readTime += time.Since(start)
+	if p != nil {
+		itr.pointN++
+	}
+	return p, err
+}
+
 func (itr *booleanInstrumentedIterator) Close() error {
-	var f fields.Fields
+	f := fields.Fields{
+		fields.Int64("points_read", itr.pointN),
+		fields.Duration("read_time", itr.readTime),
+	}
 	itr.group.ForEach(func(v metrics.Metric) {
 		switch m := v.(type) {
 		case *metrics.Counter:
@@ -2,6 +2,7 @@ package tsdb
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -1398,6 +1399,11 @@ func (is IndexSet) measurementNamesByExpr(auth query.FineAuthorizer, expr cnosql
 				return nil, err
 			}
 
+			// Nothing can intersect with an empty set.
+			if e.Op == cnosql.AND && len(lhs) == 0 {
+				return nil, nil
+			}
+
 			rhs, err := is.measurementNamesByExpr(auth, e.RHS)
 			if err != nil {
 				return nil, err
@@ -1569,6 +1575,10 @@ func (is IndexSet) measurementNamesByPredicate(auth query.FineAuthorizer, expr c
 }
 
 func (is IndexSet) measurementNamesByTagFilter(auth query.FineAuthorizer, op cnosql.Token, key, val string, regex *regexp.Regexp) ([][]byte, error) {
+	if op == cnosql.EQ && val != "" {
+		return is.measurementNamesByTagValue(auth, key, val)
+	}
+
 	var names [][]byte
 
 	mitr, err := is.measurementIterator()
@@ -1695,6 +1705,51 @@ func (is IndexSet) measurementNamesByTagFilter(auth query.FineAuthorizer, op cno
 	return names, nil
 }
 
+// measurementNamesByTagValue returns the sorted names of the measurements
+// owning a readable, undeleted series with the tag key and value. The indexes
+// keep their tag values per measurement, so each measurement takes one
+// lookup in the tag value index, and the series of the value are only read
+// for measurements holding it.
+func (is IndexSet) measurementNamesByTagValue(auth query.FineAuthorizer, key, val string) ([][]byte, error) {
+	mitr, err := is.measurementIterator()
+	if err != nil {
+		return nil, err
+	} else if mitr == nil {
+		return nil, nil
+	}
+	defer mitr.Close()
+
+	if query.AuthorizerIsOpen(auth) {
+		auth = query.OpenAuthorizer
+	}
+
+	k, v := []byte(key), []byte(val)
+	var names [][]byte
+	for {
+		me, err := mitr.Next()
+		if err != nil {
+			return nil, err
+		} else if me == nil {
+			break
+		}
+
+		if ok, err := is.HasTagValue(me, k, v); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		if ok, err := is.tagValueHasAuthorizedSeries(auth, me, k, v); err != nil {
+			return nil, err
+		} else if ok {
+			names = append(names, me)
+		}
+	}
+
+	bytesutil.Sort(names)
+	return names, nil
+}
+
 func (is IndexSet) measurementNamesByTagPredicate(auth query.FineAuthorizer, op cnosql.Token, key, val string, regex *regexp.Regexp) ([][]byte, error) {
 	var names [][]byte
 
@@ -2081,6 +2136,48 @@ func (is IndexSet) MeasurementSeriesIDIterator(name []byte) (SeriesIDIterator, e
 	return FilterUndeletedSeriesIDIterator(is.SeriesFile, itr), nil
 }
 
+// measurementSeriesN returns the number of non-tombstoned series for the
+// provided measurement that auth may read.
+func (is IndexSet) measurementSeriesN(ctx context.Context, auth query.FineAuthorizer, name []byte) (int64, error) {
+	release := is.SeriesFile.Retain()
+	defer release()
+
+	itr, err := is.MeasurementSeriesIDIterator(name)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	open := query.AuthorizerIsOpen(auth)
+
+	var n, i int64
+	for ; ; i++ {
+		// Check for cancellation every so often.
+		if i%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		e, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if e.SeriesID == 0 {
+			return n, nil
+		}
+
+		if !open {
+			name, tags := is.SeriesFile.Series(e.SeriesID)
+			if !auth.AuthorizeSeriesRead(is.Database(), name, tags) {
+				continue
+			}
+		}
+		n++
+	}
+}
+
 // measurementSeriesIDIterator does not provide any locking on the Series file.
 //
 // See  MeasurementSeriesIDIterator for more details.
@@ -2788,67 +2885,11 @@ func (is IndexSet) MeasurementTagKeyValuesByExpr(auth query.FineAuthorizer, name
 	// all.
 	if expr == nil {
 		for ki, key := range keys {
-			vitr, err := is.tagValueIterator(name, []byte(key))
+			values, err := is.measurementTagKeyValues(auth, name, []byte(key), 0)
 			if err != nil {
 				return nil, err
-			} else if vitr == nil {
-				break
-			}
-			defer vitr.Close()
-
-			// If no authorizer present then return all values.
-			if query.AuthorizerIsOpen(auth) {
-				for {
-					val, err := vitr.Next()
-					if err != nil {
-						return nil, err
-					} else if val == nil {
-						break
-					}
-					results[ki] = append(results[ki], string(val))
-				}
-				continue
-			}
-
-			// Authorization is present — check all series with matching tag values
-			// and measurements for the presence of an authorized series.
-			for {
-				val, err := vitr.Next()
-				if err != nil {
-					return nil, err
-				} else if val == nil {
-					break
-				}
-
-				sitr, err := is.tagValueSeriesIDIterator(name, []byte(key), val)
-				if err != nil {
-					return nil, err
-				} else if sitr == nil {
-					continue
-				}
-				defer sitr.Close()
-				sitr = FilterUndeletedSeriesIDIterator(is.SeriesFile, sitr)
-
-				for {
-					se, err := sitr.Next()
-					if err != nil {
-						return nil, err
-					}
-
-					if se.SeriesID == 0 {
-						break
-					}
-
-					name, tags := is.SeriesFile.Series(se.SeriesID)
-					if auth.AuthorizeSeriesRead(is.Database(), name, tags) {
-						results[ki] = append(results[ki], string(val))
-						break
-					}
-				}
-				if err := sitr.Close(); err != nil {
-					return nil, err
-				}
 			}
+			results[ki] = values
 		}
 		return results, nil
 	}
@@ -2873,6 +2914,97 @@ func (is IndexSet) MeasurementTagKeyValuesByExpr(auth query.FineAuthorizer, name
 	return results, nil
 }
 
+// MeasurementTagKeyValuesN returns the sorted values of the sorted keys of the
+// measurement, like MeasurementTagKeyValuesByExpr without an expression, but
+// stops once n values have been collected over all keys. The values of later
+// keys are left empty. If n is not positive all values are returned.
+func (is IndexSet) MeasurementTagKeyValuesN(auth query.FineAuthorizer, name []byte, keys []string, n int) ([][]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	release := is.SeriesFile.Retain()
+	defer release()
+
+	results := make([][]string, len(keys))
+	remaining := n
+	for ki, key := range keys {
+		values, err := is.measurementTagKeyValues(auth, name, []byte(key), remaining)
+		if err != nil {
+			return nil, err
+		}
+		results[ki] = values
+
+		if n > 0 {
+			if remaining -= len(values); remaining <= 0 {
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+// measurementTagKeyValues returns up to n sorted values of the measurement's
+// tag key that have an authorized series. If n is not positive all values
+// are returned.
+func (is IndexSet) measurementTagKeyValues(auth query.FineAuthorizer, name, key []byte, n int) ([]string, error) {
+	vitr, err := is.tagValueIterator(name, key)
+	if err != nil {
+		return nil, err
+	} else if vitr == nil {
+		return nil, nil
+	}
+	defer vitr.Close()
+
+	var values []string
+	for n <= 0 || len(values) < n {
+		val, err := vitr.Next()
+		if err != nil {
+			return nil, err
+		} else if val == nil {
+			break
+		}
+
+		// If no authorizer present then return all values.
+		if query.AuthorizerIsOpen(auth) {
+			values = append(values, string(val))
+			continue
+		}
+
+		// Authorization is present — check all series with matching tag values
+		// and measurements for the presence of an authorized series.
+		if ok, err := is.tagValueHasAuthorizedSeries(auth, name, key, val); err != nil {
+			return nil, err
+		} else if ok {
+			values = append(values, string(val))
+		}
+	}
+	return values, nil
+}
+
+// tagValueHasAuthorizedSeries returns true if auth may read a series of the
+// measurement with the tag key and value.
+func (is IndexSet) tagValueHasAuthorizedSeries(auth query.FineAuthorizer, name, key, value []byte) (bool, error) {
+	sitr, err := is.tagValueSeriesIDIterator(name, key, value)
+	if err != nil || sitr == nil {
+		return false, err
+	}
+	defer sitr.Close()
+	sitr = FilterUndeletedSeriesIDIterator(is.SeriesFile, sitr)
+
+	for {
+		se, err := sitr.Next()
+		if err != nil || se.SeriesID == 0 {
+			return false, err
+		}
+
+		mname, tags := is.SeriesFile.Series(se.SeriesID)
+		if auth.AuthorizeSeriesRead(is.Database(), mname, tags) {
+			return true, nil
+		}
+	}
+}
+
 // TagSets returns an ordered list of tag sets for a measurement by dimension
 // and filtered by an optional conditional expression.
 func (is IndexSet) TagSets(sfile *SeriesFile, name []byte, opt query.IteratorOptions) ([]*query.TagSet, error) {
@@ -73,6 +73,10 @@ var (
 	// ErrUnknownFieldType is returned when the type of a field cannot be determined.
 	ErrUnknownFieldType = errors.New("unknown field type")
 
+	// ErrShardCold is returned when writing to a shard that has been moved
+	// to the cold tier.
+	ErrShardCold = errors.New("shard is in the cold tier and read-only")
+
 	// ErrShardNotIdle is returned when an operation requring the shard to be idle/cold is
 	// attempted on a hot shard.
 	ErrShardNotIdle = errors.New("shard not idle")
@@ -114,12 +118,39 @@ type PartialWriteError struct {
 
 	// A sorted slice of series keys that were dropped.
 	DroppedKeys [][]byte
+
+	// Cause is the typed error the points were dropped for, if there is one.
+	Cause error
 }
 
 func (e PartialWriteError) Error() string {
 	return fmt.Sprintf("partial write: %s dropped=%d", e.Reason, e.Dropped)
 }
 
+// MaxSeriesPerDatabaseError is the cause of a partial write that dropped
+// points because they would create series beyond the limit of their database.
+type MaxSeriesPerDatabaseError struct {
+	Database string
+	Limit    int64
+}
+
+func (e MaxSeriesPerDatabaseError) Error() string {
+	return fmt.Sprintf("max series limit of database %q exceeded: (%d)", e.Database, e.Limit)
+}
+
+// MaxValuesPerTagError is the cause of a partial write that dropped points
+// because they would add values to a tag key beyond the limit of their
+// database.
+type MaxValuesPerTagError struct {
+	Measurement string
+	TagKey      string
+	Limit       int64
+}
+
+func (e MaxValuesPerTagError) Error() string {
+	return fmt.Sprintf("max values per tag limit exceeded (%d): measurement=%q tag=%q", e.Limit, e.Measurement, e.TagKey)
+}
+
 // Shard represents a self-contained time series database. An inverted index of
 // the measurement and tag data is kept along with the raw time series data.
 // Data can be split across many shards. The query engine in TSDB is responsible
@@ -140,6 +171,9 @@ type Shard struct {
 	index   Index
 	enabled bool
 
+	// cold is set once the shard's data is in the cold tier directory.
+	cold bool
+
 	// expvar-based stats.
 	stats       *ShardStatistics
 	defaultTags models.StatisticTags
@@ -293,8 +327,62 @@ func (s *Shard) Statistics(tags map[string]string) []models.Statistic {
 	return statistics
 }
 
-// Path returns the path set on the shard when it was created.
-func (s *Shard) Path() string { return s.path }
+// Path returns the path set on the shard when it was created, or the cold
+// tier path it was moved to.
+func (s *Shard) Path() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.path
+}
+
+// Cold returns true if the shard's data is in the cold tier directory.
+func (s *Shard) Cold() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cold
+}
+
+// setCold sets whether the shard rejects writes as a cold tier shard.
+func (s *Shard) setCold(cold bool) {
+	s.mu.Lock()
+	s.cold = cold
+	s.mu.Unlock()
+}
+
+// relocate closes the shard, calls swap to put its data in place at path and
+// reopens the shard from path as a read-only cold shard. If swap fails the
+// shard is reopened from its original path.
+func (s *Shard) relocate(path string, swap func() error) error {
+	s.mu.Lock()
+	if s._engine == nil {
+		// The shard was closed, most likely for deletion, since the move began.
+		s.mu.Unlock()
+		return ErrEngineClosed
+	}
+	enabled := s.enabled
+	if err := s.close(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	if err := swap(); err != nil {
+		s.mu.Unlock()
+		if e := s.Open(); e != nil {
+			s.logger.Warn("Error reopening shard after failed move", zap.Error(e))
+		}
+		s.SetEnabled(enabled)
+		return err
+	}
+
+	s.path = path
+	s.cold = true
+	s.mu.Unlock()
+	if err := s.Open(); err != nil {
+		return err
+	}
+	s.SetEnabled(enabled)
+	return nil
+}
 
 // Open initializes and opens the shard's store.
 func (s *Shard) Open() error {
@@ -451,6 +539,24 @@ func (s *Shard) IsIdle() bool {
 	return engine.IsIdle()
 }
 
+// IsCompacting returns true if the shard is running a compaction.
+func (s *Shard) IsCompacting() bool {
+	engine, err := s.Engine()
+	if err != nil {
+		return false
+	}
+	return engine.IsCompacting()
+}
+
+// HasWALSegments returns true if the shard has writes in its WAL.
+func (s *Shard) HasWALSegments() bool {
+	engine, err := s.Engine()
+	if err != nil {
+		return false
+	}
+	return engine.HasWALSegments()
+}
+
 func (s *Shard) Free() error {
 	engine, err := s.Engine()
 	if err != nil {
@@ -497,6 +603,10 @@ func (s *Shard) WritePoints(points []models.Point) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if s.cold {
+		return ErrShardCold
+	}
+
 	engine, err := s.engineNoLock()
 	if err != nil {
 		return err
@@ -2,6 +2,8 @@ package tsdb
 
 import (
 	"bytes"
+	"container/heap"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,13 +15,16 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cnosdb/cnosdb/vend/cnosql"
 	"github.com/cnosdb/cnosdb/vend/db/logger"
 	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/bytesutil"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/estimator"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/estimator/hll"
+	"github.com/cnosdb/cnosdb/vend/db/pkg/file"
 	"github.com/cnosdb/cnosdb/vend/db/pkg/limiter"
 	"github.com/cnosdb/cnosdb/vend/db/query"
 	"go.uber.org/zap"
@@ -94,6 +99,20 @@ type Store struct {
 
 	EngineOptions EngineOptions
 
+	// ColdShardIDs returns the IDs of shards old enough to be moved to the
+	// cold tier. Shards are only moved if it is set along with
+	// EngineOptions.Config.ColdDir.
+	ColdShardIDs func(now time.Time) []uint64
+
+	// DatabaseLimits returns the write limits of a database. Writes are not
+	// limited if it is nil.
+	DatabaseLimits func(database string) DatabaseLimits
+
+	// DatabaseIndexType returns the index new shards of a database are
+	// created with. EngineOptions.IndexVersion is used if it is nil or
+	// returns an empty string.
+	DatabaseIndexType func(database string) string
+
 	baseLogger *zap.Logger
 	Logger     *zap.Logger
 
@@ -139,13 +158,13 @@ func (s *Store) Statistics(tags map[string]string) []models.Statistic {
 	statistics := make([]models.Statistic, 0, len(databases))
 	for _, database := range databases {
 		log := s.Logger.With(logger.Database(database))
-		sc, err := s.SeriesCardinality(database)
+		sc, err := s.SeriesCardinality(query.OpenAuthorizer, database)
 		if err != nil {
 			log.Info("Cannot retrieve series cardinality", zap.Error(err))
 			continue
 		}
 
-		mc, err := s.MeasurementsCardinality(database)
+		mc, err := s.MeasurementsCardinality(query.OpenAuthorizer, database)
 		if err != nil {
 			log.Info("Cannot retrieve measurement cardinality", zap.Error(err))
 			continue
@@ -194,6 +213,34 @@ func (s *Store) IndexBytes() int {
 	return b
 }
 
+// IndexBytesByDatabase returns the memory used by the indexes of each
+// database, keyed by database name.
+func (s *Store) IndexBytesByDatabase() map[string]int {
+	sets := make(map[string]IndexSet)
+	s.mu.RLock()
+	for _, shard := range s.shards {
+		is := sets[shard.database]
+		if is.SeriesFile == nil {
+			is.SeriesFile = shard.sfile
+		}
+		is.Indexes = append(is.Indexes, shard.index)
+		sets[shard.database] = is
+	}
+	s.mu.RUnlock()
+
+	bytes := make(map[string]int, len(sets))
+	for name, is := range sets {
+		is = is.DedupeInmemIndexes()
+
+		var b int
+		for _, idx := range is.Indexes {
+			b += idx.Bytes()
+		}
+		bytes[name] = b
+	}
+	return bytes
+}
+
 // Path returns the store's root path.
 func (s *Store) Path() string { return s.path }
 
@@ -217,6 +264,11 @@ func (s *Store) Open() error {
 	if err := os.MkdirAll(s.path, 0777); err != nil {
 		return err
 	}
+	if dir := s.EngineOptions.Config.ColdDir; dir != "" {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+	}
 
 	if err := s.loadShards(); err != nil {
 		return err
@@ -232,6 +284,14 @@ func (s *Store) Open() error {
 		}()
 	}
 
+	if s.EngineOptions.Config.ColdDir != "" && s.ColdShardIDs != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.monitorColdShards()
+		}()
+	}
+
 	return nil
 }
 
@@ -293,124 +353,145 @@ func (s *Store) loadShards() error {
 	resC := make(chan *res)
 	var n int
 
-	// Determine how many shards we need to open by checking the store path.
-	dbDirs, err := ioutil.ReadDir(s.path)
-	if err != nil {
-		return err
-	}
-
-	for _, db := range dbDirs {
-		dbPath := filepath.Join(s.path, db.Name())
-		if !db.IsDir() {
-			log.Info("Skipping database dir", zap.String("name", db.Name()), zap.String("reason", "not a directory"))
-			continue
-		}
-
-		if s.EngineOptions.DatabaseFilter != nil && !s.EngineOptions.DatabaseFilter(db.Name()) {
-			log.Info("Skipping database dir", logger.Database(db.Name()), zap.String("reason", "failed database filter"))
-			continue
-		}
-
-		// Load series file.
-		sfile, err := s.openSeriesFile(db.Name())
-		if err != nil {
-			return err
-		}
-
-		// Retrieve database index.
-		idx, err := s.createIndexIfNotExists(db.Name())
-		if err != nil {
-			return err
-		}
-
-		// Load each retention policy within the database directory.
-		rpDirs, err := ioutil.ReadDir(dbPath)
+	// Determine how many shards we need to open by checking the store path
+	// and the cold tier path, if one is configured.
+	for _, root := range s.shardRoots() {
+		cold := root != s.path
+		dbDirs, err := ioutil.ReadDir(root)
 		if err != nil {
 			return err
 		}
 
-		for _, rp := range rpDirs {
-			rpPath := filepath.Join(s.path, db.Name(), rp.Name())
-			if !rp.IsDir() {
-				log.Info("Skipping retention policy dir", zap.String("name", rp.Name()), zap.String("reason", "not a directory"))
+		for _, db := range dbDirs {
+			dbPath := filepath.Join(root, db.Name())
+			if !db.IsDir() {
+				log.Info("Skipping database dir", zap.String("name", db.Name()), zap.String("reason", "not a directory"))
 				continue
 			}
 
-			// The .series directory is not a retention policy.
-			if rp.Name() == SeriesFileDirectory {
+			if s.EngineOptions.DatabaseFilter != nil && !s.EngineOptions.DatabaseFilter(db.Name()) {
+				log.Info("Skipping database dir", logger.Database(db.Name()), zap.String("reason", "failed database filter"))
 				continue
 			}
 
-			if s.EngineOptions.RetentionPolicyFilter != nil && !s.EngineOptions.RetentionPolicyFilter(db.Name(), rp.Name()) {
-				log.Info("Skipping retention policy dir", logger.RetentionPolicy(rp.Name()), zap.String("reason", "failed retention policy filter"))
-				continue
+			// Load series file.
+			sfile, err := s.openSeriesFile(db.Name())
+			if err != nil {
+				return err
+			}
+
+			// Retrieve database index.
+			idx, err := s.createIndexIfNotExists(db.Name())
+			if err != nil {
+				return err
 			}
 
-			shardDirs, err := ioutil.ReadDir(rpPath)
+			// Load each retention policy within the database directory.
+			rpDirs, err := ioutil.ReadDir(dbPath)
 			if err != nil {
 				return err
 			}
 
-			for _, sh := range shardDirs {
-				// Series file should not be in a retention policy but skip just in case.
-				if sh.Name() == SeriesFileDirectory {
-					log.Warn("Skipping series file in retention policy dir", zap.String("path", filepath.Join(s.path, db.Name(), rp.Name())))
+			for _, rp := range rpDirs {
+				rpPath := filepath.Join(root, db.Name(), rp.Name())
+				if !rp.IsDir() {
+					log.Info("Skipping retention policy dir", zap.String("name", rp.Name()), zap.String("reason", "not a directory"))
 					continue
 				}
 
-				n++
-				go func(db, rp, sh string) {
-					t.Take()
-					defer t.Release()
-
-					start := time.Now()
-					path := filepath.Join(s.path, db, rp, sh)
-					walPath := filepath.Join(s.EngineOptions.Config.WALDir, db, rp, sh)
-
-					// Shard file names are numeric shardIDs
-					shardID, err := strconv.ParseUint(sh, 10, 64)
-					if err != nil {
-						log.Info("invalid shard ID found at path", zap.String("path", path))
-						resC <- &res{err: fmt.Errorf("%s is not a valid ID. Skipping shard.", sh)}
-						return
+				// The .series directory is not a retention policy.
+				if rp.Name() == SeriesFileDirectory {
+					continue
+				}
+
+				if s.EngineOptions.RetentionPolicyFilter != nil && !s.EngineOptions.RetentionPolicyFilter(db.Name(), rp.Name()) {
+					log.Info("Skipping retention policy dir", logger.RetentionPolicy(rp.Name()), zap.String("reason", "failed retention policy filter"))
+					continue
+				}
+
+				shardDirs, err := ioutil.ReadDir(rpPath)
+				if err != nil {
+					return err
+				}
+
+				for _, sh := range shardDirs {
+					// Series file should not be in a retention policy but skip just in case.
+					if sh.Name() == SeriesFileDirectory {
+						log.Warn("Skipping series file in retention policy dir", zap.String("path", filepath.Join(root, db.Name(), rp.Name())))
+						continue
 					}
 
-					if s.EngineOptions.ShardFilter != nil && !s.EngineOptions.ShardFilter(db, rp, shardID) {
-						log.Info("skipping shard", zap.String("path", path), logger.Shard(shardID))
-						resC <- &res{}
-						return
+					if cold && strings.HasSuffix(sh.Name(), coldShardTmpExt) {
+						// An interrupted move to the cold tier. The hot copy is intact.
+						log.Info("Removing incomplete cold shard", zap.String("path", filepath.Join(rpPath, sh.Name())))
+						if err := os.RemoveAll(filepath.Join(rpPath, sh.Name())); err != nil {
+							return err
+						}
+						continue
+					} else if !cold && s.coldShardExists(db.Name(), rp.Name(), sh.Name()) {
+						// A completed move to the cold tier whose hot copy was not removed.
+						log.Info("Removing relocated hot shard", zap.String("path", filepath.Join(rpPath, sh.Name())))
+						if err := os.RemoveAll(filepath.Join(rpPath, sh.Name())); err != nil {
+							return err
+						}
+						continue
 					}
 
-					// Copy options and assign shared index.
-					opt := s.EngineOptions
-					opt.InmemIndex = idx
+					n++
+					go func(root, db, rp, sh string, cold bool) {
+						t.Take()
+						defer t.Release()
+
+						start := time.Now()
+						path := filepath.Join(root, db, rp, sh)
+						walPath := filepath.Join(s.EngineOptions.Config.WALDir, db, rp, sh)
+
+						// Shard file names are numeric shardIDs
+						shardID, err := strconv.ParseUint(sh, 10, 64)
+						if err != nil {
+							log.Info("invalid shard ID found at path", zap.String("path", path))
+							resC <- &res{err: fmt.Errorf("%s is not a valid ID. Skipping shard.", sh)}
+							return
+						}
+
+						if s.EngineOptions.ShardFilter != nil && !s.EngineOptions.ShardFilter(db, rp, shardID) {
+							log.Info("skipping shard", zap.String("path", path), logger.Shard(shardID))
+							resC <- &res{}
+							return
+						}
 
-					// Provide an implementation of the ShardIDSets
-					opt.SeriesIDSets = shardSet{store: s, db: db}
+						// Copy options and assign shared index.
+						opt := s.EngineOptions
+						opt.InmemIndex = idx
 
-					// Existing shards should continue to use inmem index.
-					if _, err := os.Stat(filepath.Join(path, "index")); os.IsNotExist(err) {
-						opt.IndexVersion = InmemIndexName
-					}
+						// Provide an implementation of the ShardIDSets
+						opt.SeriesIDSets = shardSet{store: s, db: db}
 
-					// Open engine.
-					shard := NewShard(shardID, path, walPath, sfile, opt)
+						// Existing shards should continue to use inmem index.
+						if _, err := os.Stat(filepath.Join(path, "index")); os.IsNotExist(err) {
+							opt.IndexVersion = InmemIndexName
+						}
 
-					// Disable compactions, writes and queries until all shards are loaded
-					shard.EnableOnOpen = false
-					shard.CompactionDisabled = s.EngineOptions.CompactionDisabled
-					shard.WithLogger(s.baseLogger)
+						// Open engine.
+						shard := NewShard(shardID, path, walPath, sfile, opt)
+						shard.cold = cold
 
-					err = shard.Open()
-					if err != nil {
-						log.Info("Failed to open shard", logger.Shard(shardID), zap.Error(err))
-						resC <- &res{err: fmt.Errorf("Failed to open shard: %d: %s", shardID, err)}
-						return
-					}
+						// Disable compactions, writes and queries until all shards are loaded
+						shard.EnableOnOpen = false
+						shard.CompactionDisabled = s.EngineOptions.CompactionDisabled
+						shard.WithLogger(s.baseLogger)
+
+						err = shard.Open()
+						if err != nil {
+							log.Info("Failed to open shard", logger.Shard(shardID), zap.Error(err))
+							resC <- &res{err: fmt.Errorf("Failed to open shard: %d: %s", shardID, err)}
+							return
+						}
 
-					resC <- &res{s: shard}
-					log.Info("Opened shard", zap.String("index_version", shard.IndexType()), zap.String("path", path), zap.Duration("duration", time.Since(start)))
-				}(db.Name(), rp.Name(), sh.Name())
+						resC <- &res{s: shard}
+						log.Info("Opened shard", zap.String("index_version", shard.IndexType()), zap.String("path", path), zap.Duration("duration", time.Since(start)))
+					}(root, db.Name(), rp.Name(), sh.Name(), cold)
+				}
 			}
 		}
 	}
@@ -644,6 +725,11 @@ func (s *Store) CreateShard(database, retentionPolicy string, shardID uint64, en
 	opt := s.EngineOptions
 	opt.InmemIndex = idx
 	opt.SeriesIDSets = shardSet{store: s, db: database}
+	if s.DatabaseIndexType != nil {
+		if index := s.DatabaseIndexType(database); index != "" {
+			opt.IndexVersion = index
+		}
+	}
 
 	path := filepath.Join(s.path, database, retentionPolicy, strconv.FormatUint(shardID, 10))
 	shard := NewShard(shardID, path, walPath, sfile, opt)
@@ -792,7 +878,7 @@ func (s *Store) DeleteShard(shardID uint64) error {
 	}
 
 	// Remove the on-disk shard data.
-	if err := os.RemoveAll(sh.path); err != nil {
+	if err := os.RemoveAll(sh.Path()); err != nil {
 		return err
 	}
 
@@ -849,6 +935,11 @@ func (s *Store) DeleteDatabase(name string) error {
 	if err := os.RemoveAll(filepath.Join(s.EngineOptions.Config.WALDir, name)); err != nil {
 		return err
 	}
+	if dir := s.EngineOptions.Config.ColdDir; dir != "" {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
 
 	for _, sh := range shards {
 		delete(s.shards, sh.id)
@@ -909,6 +1000,13 @@ func (s *Store) DeleteRetentionPolicy(database, name string) error {
 		return err
 	}
 
+	// Remove the retention policy folder from the cold tier.
+	if dir := s.EngineOptions.Config.ColdDir; dir != "" {
+		if err := os.RemoveAll(filepath.Join(dir, database, name)); err != nil {
+			return err
+		}
+	}
+
 	s.mu.Lock()
 	state := s.databases[database]
 	for _, sh := range shards {
@@ -976,6 +1074,14 @@ func byDatabase(name string) func(sh *Shard) bool {
 	}
 }
 
+// byRetentionPolicy provides a predicate for filterShards that matches on the
+// database and retention policy names passed in.
+func byRetentionPolicy(database, rp string) func(sh *Shard) bool {
+	return func(sh *Shard) bool {
+		return sh.database == database && sh.retentionPolicy == rp
+	}
+}
+
 // walkShards apply a function to each shard in parallel. fn must be safe for
 // concurrent use. If any of the functions return an error, the first error is
 // returned.
@@ -1068,6 +1174,249 @@ func (s *Store) DiskSize() (int64, error) {
 	return size, nil
 }
 
+// ShardsDiskSize returns the size of the shard files in bytes for the given
+// shard ids. Shards not present on this node are ignored. This size does not
+// include the WAL size.
+func (s *Store) ShardsDiskSize(ids []uint64) (int64, error) {
+	var size int64
+	for _, sh := range s.Shards(ids) {
+		sz, err := sh.DiskSize()
+		if err != nil {
+			return 0, err
+		}
+		size += sz
+	}
+	return size, nil
+}
+
+// ShardDiskStat describes the local storage used by a shard.
+type ShardDiskStat struct {
+	DiskBytes    int64
+	SeriesN      int64
+	LastModified time.Time
+	Tier         string
+}
+
+// ShardDiskStats returns the storage statistics of the given shards that are
+// open on this node, keyed by shard id. Shards not found are omitted.
+func (s *Store) ShardDiskStats(ids []uint64) (map[uint64]ShardDiskStat, error) {
+	stats := make(map[uint64]ShardDiskStat, len(ids))
+	for _, sh := range s.Shards(ids) {
+		sz, err := sh.DiskSize()
+		if err != nil {
+			return nil, err
+		}
+		tier := ShardTierHot
+		if sh.Cold() {
+			tier = ShardTierCold
+		}
+		stats[sh.ID()] = ShardDiskStat{
+			DiskBytes:    sz,
+			SeriesN:      sh.SeriesN(),
+			LastModified: sh.LastModified(),
+			Tier:         tier,
+		}
+	}
+	return stats, nil
+}
+
+// Shard states returned by ShardState.
+const (
+	// ShardStateHot means the shard has writes in its WAL.
+	ShardStateHot = "hot"
+
+	// ShardStateCompacting means a compaction of the shard is running.
+	ShardStateCompacting = "compacting"
+
+	// ShardStateCold means the shard is fully written to TSM files.
+	ShardStateCold = "cold"
+
+	// ShardStateRemote means the shard is not open on this node.
+	ShardStateRemote = "remote"
+)
+
+// ShardState returns the state of the shard on this node. The store does not
+// know the time range of a shard, so callers that do may also consider a
+// shard whose range includes now as hot.
+func (s *Store) ShardState(id uint64) string {
+	sh := s.Shard(id)
+	switch {
+	case sh == nil:
+		return ShardStateRemote
+	case sh.HasWALSegments():
+		return ShardStateHot
+	case sh.IsCompacting():
+		return ShardStateCompacting
+	default:
+		return ShardStateCold
+	}
+}
+
+// Storage tiers reported in ShardDiskStat.
+const (
+	// ShardTierHot means the shard's data is in the store's data directory.
+	ShardTierHot = "hot"
+
+	// ShardTierCold means the shard's data has been moved to the cold
+	// directory and the shard is read-only.
+	ShardTierCold = "cold"
+)
+
+// coldShardTmpExt is appended to the directory a shard is copied to before
+// it is renamed into place in the cold tier.
+const coldShardTmpExt = ".tmp"
+
+// shardRoots returns the directories shards are loaded from.
+func (s *Store) shardRoots() []string {
+	if dir := s.EngineOptions.Config.ColdDir; dir != "" {
+		return []string{s.path, dir}
+	}
+	return []string{s.path}
+}
+
+// coldShardExists returns true if a complete copy of the shard is in the
+// cold tier.
+func (s *Store) coldShardExists(db, rp, sh string) bool {
+	dir := s.EngineOptions.Config.ColdDir
+	if dir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, db, rp, sh))
+	return err == nil
+}
+
+// monitorColdShards periodically moves shards reported by ColdShardIDs to
+// the cold tier.
+func (s *Store) monitorColdShards() {
+	t := time.NewTicker(time.Duration(s.EngineOptions.Config.ColdCheckInterval))
+	defer t.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-t.C:
+			for _, sh := range s.Shards(s.ColdShardIDs(time.Now())) {
+				select {
+				case <-s.closing:
+					return
+				default:
+				}
+
+				if sh.Cold() {
+					continue
+				}
+				start := time.Now()
+				if err := s.moveShardToCold(sh); err == ErrShardNotIdle {
+					// Still being written to or compacted; retry on the next check.
+					continue
+				} else if err != nil {
+					s.Logger.Warn("Error moving shard to cold tier", logger.Shard(sh.ID()), zap.Error(err))
+					continue
+				}
+				s.Logger.Info("Moved shard to cold tier", logger.Shard(sh.ID()), zap.String("path", sh.Path()), zap.Duration("duration", time.Since(start)))
+			}
+		}
+	}
+}
+
+// moveShardToCold moves the data of sh to the cold directory and reopens it
+// read-only. The data is copied and synced to a temporary directory, which is
+// renamed into place before the hot copy is removed, so a crash at any point
+// leaves a complete copy for loadShards to open. ErrShardNotIdle is returned
+// if the shard has unsnapshotted writes or compactions to run.
+func (s *Store) moveShardToCold(sh *Shard) error {
+	hotPath := sh.Path()
+	coldPath := filepath.Join(s.EngineOptions.Config.ColdDir, sh.Database(), sh.RetentionPolicy(), strconv.FormatUint(sh.ID(), 10))
+	tmpPath := coldPath + coldShardTmpExt
+
+	// Reject writes, then make sure everything written so far is in TSM
+	// files, which don't change while compactions are stopped.
+	sh.setCold(true)
+	if !sh.IsIdle() {
+		sh.setCold(false)
+		return ErrShardNotIdle
+	}
+	sh.SetCompactionsEnabled(false)
+	defer sh.SetCompactionsEnabled(true)
+
+	if err := os.RemoveAll(tmpPath); err != nil {
+		sh.setCold(false)
+		return err
+	}
+	if err := copyDir(hotPath, tmpPath); err != nil {
+		sh.setCold(false)
+		os.RemoveAll(tmpPath)
+		return err
+	}
+
+	if err := sh.relocate(coldPath, func() error {
+		if err := file.RenameFile(tmpPath, coldPath); err != nil {
+			return err
+		}
+		return file.SyncDir(filepath.Dir(coldPath))
+	}); err != nil {
+		sh.setCold(false)
+		os.RemoveAll(tmpPath)
+		return err
+	}
+
+	return os.RemoveAll(hotPath)
+}
+
+// copyDir copies the directory tree at src to dst, syncing every file and
+// directory written.
+func copyDir(src, dst string) error {
+	var dirs []string
+	if err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			dirs = append(dirs, target)
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	}); err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := file.SyncDir(dir); err != nil {
+			return err
+		}
+	}
+	return file.SyncDir(filepath.Dir(dst))
+}
+
+// copyFile copies the file at src to dst and syncs it.
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 // sketchesForDatabase returns merged sketches for the provided database, by
 // walking each shard in the database and merging the sketches found there.
 func (s *Store) sketchesForDatabase(dbName string, getSketches func(*Shard) (estimator.Sketch, estimator.Sketch, error)) (estimator.Sketch, estimator.Sketch, error) {
@@ -1110,7 +1459,21 @@ func (s *Store) sketchesForDatabase(dbName string, getSketches func(*Shard) (est
 // Cardinality is calculated exactly by unioning all shards' bitsets of series
 // IDs. The result of this method cannot be combined with any other results.
 //
-func (s *Store) SeriesCardinality(database string) (int64, error) {
+// If auth is restricted, only the series it may read are counted, one
+// measurement at a time.
+func (s *Store) SeriesCardinality(auth query.FineAuthorizer, database string) (int64, error) {
+	if !query.AuthorizerIsOpen(auth) {
+		counts, err := s.SeriesCardinalityByMeasurement(auth, database)
+		if err != nil {
+			return 0, err
+		}
+		var n int64
+		for _, c := range counts {
+			n += c.N
+		}
+		return n, nil
+	}
+
 	s.mu.RLock()
 	shards := s.filterShards(byDatabase(database))
 	s.mu.RUnlock()
@@ -1156,7 +1519,18 @@ func (s *Store) SeriesSketches(database string) (estimator.Sketch, estimator.Ske
 //
 // Cardinality is calculated using a sketch-based estimation. The result of this
 // method cannot be combined with any other results.
-func (s *Store) MeasurementsCardinality(database string) (int64, error) {
+//
+// The sketches can't tell which measurements auth may read, so if auth is
+// restricted the measurements it may read are counted exactly instead.
+func (s *Store) MeasurementsCardinality(auth query.FineAuthorizer, database string) (int64, error) {
+	if !query.AuthorizerIsOpen(auth) {
+		names, err := s.MeasurementNames(auth, database, "", nil)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(names)), nil
+	}
+
 	ss, ts, err := s.sketchesForDatabase(database, func(sh *Shard) (estimator.Sketch, estimator.Sketch, error) {
 		if sh == nil {
 			return nil, nil, errors.New("shard nil, can't get cardinality")
@@ -1192,7 +1566,7 @@ func (s *Store) BackupShard(id uint64, since time.Time, w io.Writer) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := s.shardRelativePath(shard)
 	if err != nil {
 		return err
 	}
@@ -1206,7 +1580,7 @@ func (s *Store) ExportShard(id uint64, start time.Time, end time.Time, w io.Writ
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := s.shardRelativePath(shard)
 	if err != nil {
 		return err
 	}
@@ -1222,7 +1596,7 @@ func (s *Store) RestoreShard(id uint64, r io.Reader) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := s.shardRelativePath(shard)
 	if err != nil {
 		return err
 	}
@@ -1240,7 +1614,7 @@ func (s *Store) ImportShard(id uint64, r io.Reader) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := s.shardRelativePath(shard)
 	if err != nil {
 		return err
 	}
@@ -1255,7 +1629,16 @@ func (s *Store) ShardRelativePath(id uint64) (string, error) {
 	if shard == nil {
 		return "", fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
-	return relativePath(s.path, shard.path)
+	return s.shardRelativePath(shard)
+}
+
+// shardRelativePath returns the path of the shard relative to the store or,
+// for shards in the cold tier, to the cold directory.
+func (s *Store) shardRelativePath(sh *Shard) (string, error) {
+	if sh.Cold() {
+		return relativePath(s.EngineOptions.Config.ColdDir, sh.Path())
+	}
+	return relativePath(s.path, sh.Path())
 }
 
 // DeleteSeries loops through the local shards and deletes the series data for
@@ -1407,55 +1790,751 @@ func (s *Store) WriteToShard(shardID uint64, points []models.Point) error {
 		sh.SetCompactionsEnabled(true)
 	}
 
-	return sh.WritePoints(points)
+	points, limitErr := s.limitWrite(sh, points)
+
+	if err := sh.WritePoints(points); err != nil {
+		if perr, ok := err.(PartialWriteError); ok && limitErr != nil {
+			return *mergePartialWriteErrors(limitErr, &perr)
+		}
+		return err
+	} else if limitErr != nil {
+		return *limitErr
+	}
+	return nil
 }
 
-// MeasurementNames returns a slice of all measurements. Measurements accepts an
-// optional condition expression. If cond is nil, then all measurements for the
-// database will be returned.
-func (s *Store) MeasurementNames(auth query.FineAuthorizer, database string, cond cnosql.Expr) ([][]byte, error) {
-	s.mu.RLock()
-	shards := s.filterShards(byDatabase(database))
-	s.mu.RUnlock()
+// DatabaseLimits are the limits on writes to a database. Zero values are
+// unlimited.
+type DatabaseLimits struct {
+	// MaxSeriesN is the maximum number of series in the database.
+	MaxSeriesN int64
 
-	sfile := s.seriesFile(database)
-	if sfile == nil {
-		return nil, nil
+	// MaxValuesPerTag is the maximum number of values of a tag key within
+	// a measurement. Like max-values-per-tag it is checked against the
+	// index of the shard written to.
+	MaxValuesPerTag int64
+}
+
+// limitWrite returns the points of a write to sh that are within the limits
+// of its database, and a partial write error for the others.
+func (s *Store) limitWrite(sh *Shard, points []models.Point) ([]models.Point, *PartialWriteError) {
+	if s.DatabaseLimits == nil {
+		return points, nil
 	}
+	limits := s.DatabaseLimits(sh.database)
 
-	// Build indexset.
-	is := IndexSet{Indexes: make([]Index, 0, len(shards)), SeriesFile: sfile}
-	for _, sh := range shards {
-		index, err := sh.Index()
-		if err != nil {
-			return nil, err
-		}
-		is.Indexes = append(is.Indexes, index)
+	var werr *PartialWriteError
+	if limits.MaxSeriesN > 0 {
+		points, werr = limitSeries(sh.sfile, sh.database, limits.MaxSeriesN, points)
+	}
+	if limits.MaxValuesPerTag > 0 {
+		var terr *PartialWriteError
+		points, terr = limitTagValues(sh, limits.MaxValuesPerTag, points)
+		werr = mergePartialWriteErrors(werr, terr)
 	}
-	is = is.DedupeInmemIndexes()
-	return is.MeasurementNamesByExpr(auth, cond)
-}
 
-// MeasurementSeriesCounts returns the number of measurements and series in all
-// the shards' indices.
-func (s *Store) MeasurementSeriesCounts(database string) (measurements int, series int) {
-	// TODO: implement me
-	return 0, 0
+	if werr != nil {
+		atomic.AddInt64(&sh.stats.WritePointsDropped, int64(werr.Dropped))
+	}
+	return points, werr
 }
 
-type TagKeys struct {
-	Measurement string
-	Keys        []string
+// mergePartialWriteErrors adds the dropped points of b to a and returns it.
+// The reason and cause of a are kept. Either may be nil.
+func mergePartialWriteErrors(a, b *PartialWriteError) *PartialWriteError {
+	if a == nil {
+		return b
+	} else if b == nil {
+		return a
+	}
+	a.Dropped += b.Dropped
+	a.DroppedKeys = bytesutil.SortDedup(append(a.DroppedKeys, b.DroppedKeys...))
+	return a
 }
 
-type TagKeysSlice []TagKeys
+// limitSeries returns the points that don't create series beyond max in the
+// series file of database, and a partial write error for the others. Points
+// of existing series are always kept. Concurrent writes check the limit
+// independently, so it may be exceeded by the series they create together.
+func limitSeries(sfile *SeriesFile, database string, max int64, points []models.Point) ([]models.Point, *PartialWriteError) {
+	n := int64(sfile.SeriesCount())
 
-func (a TagKeysSlice) Len() int           { return len(a) }
-func (a TagKeysSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a TagKeysSlice) Less(i, j int) bool { return a[i].Measurement < a[j].Measurement }
+	var (
+		kept    []models.Point
+		dropped [][]byte
+		created map[string]struct{}
+		buf     []byte
+	)
+	for i, p := range points {
+		if !sfile.HasSeries(p.Name(), p.Tags(), buf) {
+			if _, ok := created[string(p.Key())]; !ok {
+				if n >= max {
+					// Keep the points checked so far on the first drop.
+					if dropped == nil {
+						kept = append(make([]models.Point, 0, len(points)), points[:i]...)
+					}
+					dropped = append(dropped, p.Key())
+					continue
+				}
 
-// TagKeys returns the tag keys in the given database, matching the condition.
-func (s *Store) TagKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]TagKeys, error) {
+				if created == nil {
+					created = make(map[string]struct{})
+				}
+				created[string(p.Key())] = struct{}{}
+				n++
+			}
+		}
+
+		if dropped != nil {
+			kept = append(kept, p)
+		}
+	}
+
+	if dropped == nil {
+		return points, nil
+	}
+
+	cause := MaxSeriesPerDatabaseError{Database: database, Limit: max}
+	return kept, &PartialWriteError{
+		Reason:      cause.Error(),
+		Dropped:     len(dropped),
+		DroppedKeys: bytesutil.SortDedup(dropped),
+		Cause:       cause,
+	}
+}
+
+// limitTagValues returns the points that don't add values to a tag key of
+// their measurement beyond max in the index of sh, and a partial write error
+// naming the first tag key over the limit for the others.
+func limitTagValues(sh *Shard, max int64, points []models.Point) ([]models.Point, *PartialWriteError) {
+	index, err := sh.Index()
+	if err != nil {
+		// The write fails on the shard with the same error.
+		return points, nil
+	}
+
+	var (
+		kept    []models.Point
+		dropped [][]byte
+		cause   *MaxValuesPerTagError
+
+		// New values of each measurement and tag key accepted so far.
+		added = make(map[string]map[string]struct{})
+	)
+	for i, p := range points {
+		name := p.Name()
+
+		// Collect the new tag values of the point before accepting any, so
+		// the values of a dropped point don't count towards the limit.
+		var pending []string
+		var over *MaxValuesPerTagError
+		for _, tag := range p.Tags() {
+			if ok, _ := index.HasTagValue(name, tag.Key, tag.Value); ok {
+				continue
+			}
+			k := string(name) + "\x00" + string(tag.Key)
+			if _, ok := added[k][string(tag.Value)]; ok {
+				continue
+			}
+			if int64(index.TagKeyCardinality(name, tag.Key)+len(added[k])) >= max {
+				over = &MaxValuesPerTagError{Measurement: string(name), TagKey: string(tag.Key), Limit: max}
+				break
+			}
+			pending = append(pending, k, string(tag.Value))
+		}
+
+		if over != nil {
+			if cause == nil {
+				cause = over
+			}
+			// Keep the points checked so far on the first drop.
+			if dropped == nil {
+				kept = append(make([]models.Point, 0, len(points)), points[:i]...)
+			}
+			dropped = append(dropped, p.Key())
+			continue
+		}
+
+		for j := 0; j < len(pending); j += 2 {
+			if added[pending[j]] == nil {
+				added[pending[j]] = make(map[string]struct{})
+			}
+			added[pending[j]][pending[j+1]] = struct{}{}
+		}
+		if dropped != nil {
+			kept = append(kept, p)
+		}
+	}
+
+	if dropped == nil {
+		return points, nil
+	}
+
+	return kept, &PartialWriteError{
+		Reason:      cause.Error(),
+		Dropped:     len(dropped),
+		DroppedKeys: bytesutil.SortDedup(dropped),
+		Cause:       *cause,
+	}
+}
+
+// MeasurementsCardinalityByExpr returns the exact number of measurements in
+// the database matching the condition and readable by auth. The condition may
+// only compare the measurement name and tags with string or regex literals;
+// any other predicate, such as a field comparison, returns an error because
+// it cannot be evaluated against the index.
+func (s *Store) MeasurementsCardinalityByExpr(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) (int64, error) {
+	names, err := s.MeasurementNames(auth, database, retentionPolicy, cond)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(names)), nil
+}
+
+// validateIndexCondition returns an error if expr cannot be evaluated using
+// only the index.
+func validateIndexCondition(expr cnosql.Expr) error {
+	switch expr := expr.(type) {
+	case nil:
+		return nil
+	case *cnosql.ParenExpr:
+		return validateIndexCondition(expr.Expr)
+	case *cnosql.BinaryExpr:
+		switch expr.Op {
+		case cnosql.AND, cnosql.OR:
+			if err := validateIndexCondition(expr.LHS); err != nil {
+				return err
+			}
+			return validateIndexCondition(expr.RHS)
+		case cnosql.EQ, cnosql.NEQ, cnosql.EQREGEX, cnosql.NEQREGEX:
+			if ref, ok := expr.LHS.(*cnosql.VarRef); ok {
+				if ref.Type != cnosql.Unknown && ref.Type != cnosql.Tag {
+					return fmt.Errorf("fields are not indexed and cannot be used in this condition: %s", ref.Val)
+				}
+				switch expr.RHS.(type) {
+				case *cnosql.StringLiteral, *cnosql.RegexLiteral:
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("condition cannot be evaluated against the index: %s", expr)
+}
+
+// MeasurementNames returns a slice of all measurements. Measurements accepts an
+// optional condition expression. If cond is nil, then all measurements for the
+// database will be returned. If retentionPolicy is not blank, only the shards
+// of that retention policy are consulted. The condition is resolved entirely through the
+// index, so it may only compare tags and the measurement name with string or
+// regex literals.
+func (s *Store) MeasurementNames(auth query.FineAuthorizer, database, retentionPolicy string, cond cnosql.Expr) ([][]byte, error) {
+	if err := validateIndexCondition(cond); err != nil {
+		return nil, err
+	}
+
+	filter := byDatabase(database)
+	if retentionPolicy != "" {
+		filter = byRetentionPolicy(database, retentionPolicy)
+	}
+
+	s.mu.RLock()
+	shards := s.filterShards(filter)
+	s.mu.RUnlock()
+
+	sfile := s.seriesFile(database)
+	if sfile == nil {
+		return nil, nil
+	}
+
+	// Build indexset.
+	is := IndexSet{Indexes: make([]Index, 0, len(shards)), SeriesFile: sfile}
+	for _, sh := range shards {
+		index, err := sh.Index()
+		if err != nil {
+			return nil, err
+		}
+		is.Indexes = append(is.Indexes, index)
+	}
+	is = is.DedupeInmemIndexes()
+	return is.MeasurementNamesByExpr(auth, cond)
+}
+
+// MeasurementSeriesCardinality returns the number of series auth may read of
+// each of the named measurements in the database, in the order of names. At
+// most concurrency measurements are counted at a time. Counting stops early
+// if ctx is cancelled.
+func (s *Store) MeasurementSeriesCardinality(ctx context.Context, auth query.FineAuthorizer, database string, names [][]byte, concurrency int) ([]int64, error) {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	counts := make([]int64, len(names))
+	sfile := s.seriesFile(database)
+	if sfile == nil {
+		return counts, nil
+	}
+
+	// Build indexset.
+	is := IndexSet{Indexes: make([]Index, 0, len(shards)), SeriesFile: sfile}
+	for _, sh := range shards {
+		index, err := sh.Index()
+		if err != nil {
+			return nil, err
+		}
+		is.Indexes = append(is.Indexes, index)
+	}
+	is = is.DedupeInmemIndexes()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	limit := limiter.NewFixed(concurrency)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for i, name := range names {
+		limit.Take()
+		if err := ctx.Err(); err != nil {
+			limit.Release()
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, name []byte) {
+			defer wg.Done()
+			defer limit.Release()
+
+			n, err := is.measurementSeriesN(ctx, auth, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			counts[i] = n
+		}(i, name)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return counts, nil
+}
+
+// MeasurementSeriesN is the number of series of a measurement.
+type MeasurementSeriesN struct {
+	Measurement string
+	N           int64
+}
+
+// IndexTypes returns the sorted index types of the open shards of a database.
+func (s *Store) IndexTypes(database string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.databases[database]
+	if state == nil {
+		return nil
+	}
+	a := make([]string, 0, len(state.indexTypes))
+	for idx := range state.indexTypes {
+		a = append(a, idx)
+	}
+	sort.Strings(a)
+	return a
+}
+
+// SeriesCardinalityByMeasurement returns the exact number of series auth may
+// read of every measurement in the database with such series, in measurement
+// name order.
+func (s *Store) SeriesCardinalityByMeasurement(auth query.FineAuthorizer, database string) ([]MeasurementSeriesN, error) {
+	names, err := s.MeasurementNames(auth, database, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.MeasurementSeriesCardinality(context.Background(), auth, database, names, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MeasurementSeriesN, len(names))
+	for i, name := range names {
+		results[i] = MeasurementSeriesN{Measurement: string(name), N: counts[i]}
+	}
+	return results, nil
+}
+
+// MeasurementSeriesCounts returns the number of measurements and series in all
+// the shards' indices.
+func (s *Store) MeasurementSeriesCounts(database string) (measurements int, series int) {
+	// TODO: implement me
+	return 0, 0
+}
+
+type TagKeys struct {
+	Measurement string
+	Keys        []string
+}
+
+type TagKeysSlice []TagKeys
+
+func (a TagKeysSlice) Len() int           { return len(a) }
+func (a TagKeysSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a TagKeysSlice) Less(i, j int) bool { return a[i].Measurement < a[j].Measurement }
+
+// FieldKey is a field key of a measurement and the types it has been written
+// with. A key has more than one type if shards disagree on it.
+type FieldKey struct {
+	Key   string
+	Types []cnosql.DataType
+}
+
+// FieldKeys is the set of field keys of a measurement.
+type FieldKeys struct {
+	Measurement string
+	Keys        []FieldKey
+}
+
+// FieldKeys returns the field keys and their types for the measurements in
+// the given shards matching the condition. The condition may only refer to
+// the measurement name.
+func (s *Store) FieldKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]FieldKeys, error) {
+	shards, names, err := s.measurementNamesInShards(auth, shardIDs, cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []FieldKeys
+	for _, name := range names {
+		// Collect every type each field has in any shard.
+		types := make(map[string]map[cnosql.DataType]struct{})
+		for _, shard := range shards {
+			mf := shard.MeasurementFields(name)
+			if mf == nil {
+				continue
+			}
+			mf.ForEachField(func(key string, typ cnosql.DataType) bool {
+				if types[key] == nil {
+					types[key] = make(map[cnosql.DataType]struct{})
+				}
+				types[key][typ] = struct{}{}
+				return true
+			})
+		}
+		if len(types) == 0 {
+			continue
+		}
+
+		keys := make([]FieldKey, 0, len(types))
+		for key, set := range types {
+			fk := FieldKey{Key: key, Types: make([]cnosql.DataType, 0, len(set))}
+			for typ := range set {
+				fk.Types = append(fk.Types, typ)
+			}
+			sort.Slice(fk.Types, func(i, j int) bool { return fk.Types[i] < fk.Types[j] })
+			keys = append(keys, fk)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+
+		results = append(results, FieldKeys{
+			Measurement: string(name),
+			Keys:        keys,
+		})
+	}
+	return results, nil
+}
+
+// MeasurementFieldKeyN is the number of field keys of a measurement.
+type MeasurementFieldKeyN struct {
+	Measurement string
+	N           int64
+}
+
+// FieldKeyCardinality returns the number of field keys of each measurement in
+// the given shards matching the condition. If exact is false the number is
+// estimated with a sketch of the keys, so no set of keys is built; it is
+// exact when a single shard holds the measurement.
+func (s *Store) FieldKeyCardinality(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, exact bool) ([]MeasurementFieldKeyN, error) {
+	shards, names, err := s.measurementNamesInShards(auth, shardIDs, cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MeasurementFieldKeyN
+	for _, name := range names {
+		var mfs []*MeasurementFields
+		for _, shard := range shards {
+			if mf := shard.MeasurementFields(name); mf != nil && mf.FieldN() > 0 {
+				mfs = append(mfs, mf)
+			}
+		}
+
+		var n int64
+		switch {
+		case len(mfs) == 0:
+			continue
+		case len(mfs) == 1:
+			n = int64(mfs[0].FieldN())
+		case exact:
+			keys := make(map[string]struct{})
+			for _, mf := range mfs {
+				mf.ForEachField(func(key string, _ cnosql.DataType) bool {
+					keys[key] = struct{}{}
+					return true
+				})
+			}
+			n = int64(len(keys))
+		default:
+			sketch := hll.NewDefaultPlus()
+			for _, mf := range mfs {
+				mf.ForEachField(func(key string, _ cnosql.DataType) bool {
+					sketch.Add([]byte(key))
+					return true
+				})
+			}
+			n = int64(sketch.Count())
+		}
+		results = append(results, MeasurementFieldKeyN{Measurement: string(name), N: n})
+	}
+	return results, nil
+}
+
+// measurementNamesInShards returns the given shards that exist and the names
+// of their measurements matching the condition and readable by auth.
+func (s *Store) measurementNamesInShards(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]*Shard, [][]byte, error) {
+	if len(shardIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	// Get all the shards we're interested in.
+	shards := s.Shards(shardIDs)
+	is := IndexSet{Indexes: make([]Index, 0, len(shards))}
+	for _, shard := range shards {
+		if is.SeriesFile == nil {
+			sfile, err := shard.SeriesFile()
+			if err != nil {
+				return nil, nil, err
+			}
+			is.SeriesFile = sfile
+		}
+
+		index, err := shard.Index()
+		if err != nil {
+			return nil, nil, err
+		}
+		is.Indexes = append(is.Indexes, index)
+	}
+	if is.SeriesFile == nil {
+		return nil, nil, nil
+	}
+
+	// Determine list of measurements.
+	is = is.DedupeInmemIndexes()
+	names, err := is.MeasurementNamesByExpr(auth, cond)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shards, names, nil
+}
+
+// SeriesKeysOptions controls the keys passed on by ForEachSeriesKey.
+type SeriesKeysOptions struct {
+	// Offset and Limit select the page of keys passed to fn, counted across
+	// all measurements. Limit is unbounded if zero.
+	Offset int
+	Limit  int
+
+	// Unordered passes the keys of a measurement in index order rather than
+	// sorted, so none of them are buffered.
+	Unordered bool
+}
+
+// ForEachSeriesKey calls fn with the key of every series in the given shards
+// matching the condition, one measurement at a time in name order and sorted
+// within a measurement unless opt.Unordered is set. Series that auth does not
+// allow to be read are skipped before the offset and limit are applied.
+// Iteration stops once the limit is reached or at the first error returned
+// by fn.
+//
+// Sorting a measurement's keys requires reading all of them first, as the
+// index returns them in series ID order. With a limit only the smallest keys
+// still needed are kept; without one the keys of a single measurement are
+// buffered at a time.
+func (s *Store) ForEachSeriesKey(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt SeriesKeysOptions, fn func(key string) error) error {
+	if len(shardIDs) == 0 {
+		return nil
+	}
+
+	measurementExpr := cnosql.CloneExpr(cond)
+	measurementExpr = cnosql.Reduce(cnosql.RewriteExpr(measurementExpr, func(e cnosql.Expr) cnosql.Expr {
+		switch e := e.(type) {
+		case *cnosql.BinaryExpr:
+			switch e.Op {
+			case cnosql.EQ, cnosql.NEQ, cnosql.EQREGEX, cnosql.NEQREGEX:
+				tag, ok := e.LHS.(*cnosql.VarRef)
+				if !ok || tag.Val != "_name" {
+					return nil
+				}
+			}
+		}
+		return e
+	}), nil)
+
+	filterExpr := cnosql.CloneExpr(cond)
+	filterExpr = cnosql.Reduce(cnosql.RewriteExpr(filterExpr, func(e cnosql.Expr) cnosql.Expr {
+		switch e := e.(type) {
+		case *cnosql.BinaryExpr:
+			switch e.Op {
+			case cnosql.EQ, cnosql.NEQ, cnosql.EQREGEX, cnosql.NEQREGEX:
+				tag, ok := e.LHS.(*cnosql.VarRef)
+				if !ok || cnosql.IsSystemName(tag.Val) {
+					return nil
+				}
+			}
+		}
+		return e
+	}), nil)
+
+	// Get all the shards we're interested in.
+	is := IndexSet{Indexes: make([]Index, 0, len(shardIDs))}
+	s.mu.RLock()
+	for _, sid := range shardIDs {
+		shard, ok := s.shards[sid]
+		if !ok {
+			continue
+		}
+
+		if is.SeriesFile == nil {
+			sfile, err := shard.SeriesFile()
+			if err != nil {
+				s.mu.RUnlock()
+				return err
+			}
+			is.SeriesFile = sfile
+		}
+
+		index, err := shard.Index()
+		if err != nil {
+			s.mu.RUnlock()
+			return err
+		}
+		is.Indexes = append(is.Indexes, index)
+	}
+	s.mu.RUnlock()
+
+	if len(is.Indexes) == 0 {
+		return nil
+	}
+
+	// Determine list of measurements.
+	is = is.DedupeInmemIndexes()
+	names, err := is.MeasurementNamesByExpr(nil, measurementExpr)
+	if err != nil {
+		return err
+	}
+
+	release := is.SeriesFile.Retain()
+	defer release()
+
+	database := is.Database()
+	skipped, n := 0, 0
+	emit := func(key string) (bool, error) {
+		if skipped < opt.Offset {
+			skipped++
+			return true, nil
+		}
+		if err := fn(key); err != nil {
+			return false, err
+		}
+		n++
+		return opt.Limit == 0 || n < opt.Limit, nil
+	}
+
+	for _, name := range names {
+		// The smallest keys needed to fill the rest of the page are kept
+		// when a limit is set.
+		var keys seriesKeyHeap
+		need := opt.Offset - skipped + opt.Limit - n
+
+		more, err := func() (bool, error) {
+			itr, err := is.measurementSeriesByExprIterator(name, filterExpr)
+			if err != nil {
+				return false, err
+			} else if itr == nil {
+				return true, nil
+			}
+			defer itr.Close()
+
+			for {
+				e, err := itr.Next()
+				if err != nil {
+					return false, err
+				} else if e.SeriesID == 0 {
+					return true, nil
+				}
+
+				name, tags := is.SeriesFile.Series(e.SeriesID)
+				if name == nil {
+					continue
+				}
+				if !query.AuthorizerIsOpen(auth) && !auth.AuthorizeSeriesRead(database, name, tags) {
+					continue
+				}
+				key := string(models.MakeKey(name, tags))
+
+				switch {
+				case opt.Unordered:
+					if more, err := emit(key); !more || err != nil {
+						return false, err
+					}
+				case opt.Limit == 0:
+					keys = append(keys, key)
+				case len(keys) < need:
+					heap.Push(&keys, key)
+				case key < keys[0]:
+					keys[0] = key
+					heap.Fix(&keys, 0)
+				}
+			}
+		}()
+		if !more || err != nil {
+			return err
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			if more, err := emit(key); !more || err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// seriesKeyHeap is a max-heap of series keys, used to keep the smallest keys
+// of a measurement.
+type seriesKeyHeap []string
+
+func (h seriesKeyHeap) Len() int            { return len(h) }
+func (h seriesKeyHeap) Less(i, j int) bool  { return h[i] > h[j] }
+func (h seriesKeyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *seriesKeyHeap) Push(x interface{}) { *h = append(*h, x.(string)) }
+func (h *seriesKeyHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// TagKeys returns the tag keys in the given database, matching the condition.
+func (s *Store) TagKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]TagKeys, error) {
 	if len(shardIDs) == 0 {
 		return nil, nil
 	}
@@ -1599,6 +2678,43 @@ type TagValues struct {
 
 type TagValuesSlice []TagValues
 
+// MeasurementTagValueN is the number of distinct tag values of a measurement.
+type MeasurementTagValueN struct {
+	Measurement string
+	N           int64
+}
+
+// TagValuesCardinality returns, per measurement, the number of distinct
+// values of the tag keys matching key, a condition on _tagKey, in the series
+// matching cond. Values shared by several matching keys are counted once.
+func (s *Store) TagValuesCardinality(auth query.FineAuthorizer, shardIDs []uint64, key, cond cnosql.Expr) ([]MeasurementTagValueN, error) {
+	if key == nil {
+		return nil, errors.New("a tag key condition is required")
+	}
+	if cond != nil {
+		key = &cnosql.BinaryExpr{
+			Op:  cnosql.AND,
+			LHS: &cnosql.ParenExpr{Expr: cond},
+			RHS: &cnosql.ParenExpr{Expr: key},
+		}
+	}
+
+	tagValues, err := s.TagValues(auth, shardIDs, key)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MeasurementTagValueN, 0, len(tagValues))
+	for _, m := range tagValues {
+		values := make(map[string]struct{}, len(m.Values))
+		for _, kv := range m.Values {
+			values[kv.Value] = struct{}{}
+		}
+		results = append(results, MeasurementTagValueN{Measurement: m.Measurement, N: int64(len(values))})
+	}
+	return results, nil
+}
+
 func (a TagValuesSlice) Len() int           { return len(a) }
 func (a TagValuesSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a TagValuesSlice) Less(i, j int) bool { return a[i].Measurement < a[j].Measurement }
@@ -1619,9 +2735,27 @@ func (a tagValuesSlice) Len() int           { return len(a) }
 func (a tagValuesSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a tagValuesSlice) Less(i, j int) bool { return bytes.Compare(a[i].name, a[j].name) == -1 }
 
+// TagValuesOptions holds pagination hints for TagValuesWithOptions.
+type TagValuesOptions struct {
+	// Offset and Limit describe the page of each measurement's values the
+	// caller will return. Limit is unbounded if zero.
+	Offset int
+	Limit  int
+}
+
 // TagValues returns the tag keys and values for the provided shards, where the
 // tag values satisfy the provided condition.
 func (s *Store) TagValues(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]TagValues, error) {
+	return s.TagValuesWithOptions(auth, shardIDs, cond, TagValuesOptions{})
+}
+
+// TagValuesWithOptions is like TagValues but uses the pagination hints in opt
+// to stop reading the index once Offset+Limit values of a measurement have
+// been found. Results are not sliced, so the caller still applies the offset
+// and limit; the values kept are the same leading values TagValues returns.
+// A WHERE filter on series makes early termination unsafe, in which case all
+// values are read.
+func (s *Store) TagValuesWithOptions(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr, opt TagValuesOptions) ([]TagValues, error) {
 	if cond == nil {
 		return nil, errors.New("a condition is required")
 	}
@@ -1734,8 +2868,15 @@ func (s *Store) TagValues(auth query.FineAuthorizer, shardIDs []uint64, cond cno
 
 		// get all the tag values for each key in the keyset.
 		// Each slice in the results contains the sorted values associated
-		// with each tag key for the measurement from the key set.
-		if result.values, err = is.MeasurementTagKeyValuesByExpr(auth, name, result.keys, filterExpr, true); err != nil {
+		// with each tag key for the measurement from the key set. Values are
+		// ordered by key then value, so without a filter only the first
+		// Offset+Limit of them are needed.
+		if filterExpr == nil && opt.Limit > 0 {
+			result.values, err = is.MeasurementTagKeyValuesN(auth, name, result.keys, opt.Offset+opt.Limit)
+		} else {
+			result.values, err = is.MeasurementTagKeyValuesByExpr(auth, name, result.keys, filterExpr, true)
+		}
+		if err != nil {
 			return nil, err
 		}
 
@@ -0,0 +1,569 @@
+package tsdb_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cnosdb/cnosdb/meta"
+	"github.com/cnosdb/cnosdb/vend/cnosql"
+	"github.com/cnosdb/cnosdb/vend/db/models"
+	"github.com/cnosdb/cnosdb/vend/db/query"
+	"github.com/cnosdb/cnosdb/vend/db/tsdb"
+	_ "github.com/cnosdb/cnosdb/vend/db/tsdb/engine"
+	_ "github.com/cnosdb/cnosdb/vend/db/tsdb/index"
+)
+
+// Ensure the series cardinality only counts the series a user restricted to
+// some measurements may read.
+func TestStore_SeriesCardinality_Authorized(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA value=1 0`,
+				`cpu,host=serverB value=1 0`,
+				`mem,host=serverA value=1 0`,
+				`disk,host=serverA value=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`cpu,host=serverC value=1 10`,
+				`mem,host=serverB value=1 10`,
+			)
+
+			// The user only holds a grant on cpu.
+			user := &meta.UserInfo{
+				Name: "reader",
+				MeasurementPrivileges: []meta.MeasurementPrivilege{
+					{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+				},
+			}
+
+			counts, err := s.SeriesCardinalityByMeasurement(user, "db0")
+			if err != nil {
+				t.Fatal(err)
+			} else if exp := []tsdb.MeasurementSeriesN{{Measurement: "cpu", N: 3}}; !reflect.DeepEqual(counts, exp) {
+				t.Fatalf("unexpected counts: got %v, exp %v", counts, exp)
+			}
+
+			counts, err = s.SeriesCardinalityByMeasurement(query.OpenAuthorizer, "db0")
+			if err != nil {
+				t.Fatal(err)
+			} else if exp := []tsdb.MeasurementSeriesN{{Measurement: "cpu", N: 3}, {Measurement: "disk", N: 1}, {Measurement: "mem", N: 2}}; !reflect.DeepEqual(counts, exp) {
+				t.Fatalf("unexpected counts: got %v, exp %v", counts, exp)
+			}
+
+			if n, err := s.SeriesCardinality(user, "db0"); err != nil {
+				t.Fatal(err)
+			} else if n != 3 {
+				t.Fatalf("unexpected series cardinality: got %d, exp 3", n)
+			}
+			if n, err := s.SeriesCardinality(query.OpenAuthorizer, "db0"); err != nil {
+				t.Fatal(err)
+			} else if n != 6 {
+				t.Fatalf("unexpected series cardinality: got %d, exp 6", n)
+			}
+
+			if n, err := s.MeasurementsCardinality(user, "db0"); err != nil {
+				t.Fatal(err)
+			} else if n != 1 {
+				t.Fatalf("unexpected measurement cardinality: got %d, exp 1", n)
+			}
+
+			names := [][]byte{[]byte("cpu"), []byte("disk"), []byte("mem")}
+			if a, err := s.MeasurementSeriesCardinality(context.Background(), user, "db0", names, 2); err != nil {
+				t.Fatal(err)
+			} else if exp := []int64{3, 0, 0}; !reflect.DeepEqual(a, exp) {
+				t.Fatalf("unexpected measurement series cardinality: got %v, exp %v", a, exp)
+			}
+		})
+	}
+}
+
+// Ensure the series keys are paged across measurements in key order, and
+// limited to the measurements and series of the condition.
+func TestStore_ForEachSeriesKey(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverD value=1 0`,
+				`cpu,host=serverB value=1 0`,
+				`mem,host=serverA value=1 0`,
+				`disk,host=serverA value=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`cpu,host=serverC value=1 10`,
+				`cpu,host=serverA value=1 10`,
+				`mem,host=serverB value=1 10`,
+			)
+
+			all := []string{
+				"cpu,host=serverA", "cpu,host=serverB", "cpu,host=serverC", "cpu,host=serverD",
+				"disk,host=serverA",
+				"mem,host=serverA", "mem,host=serverB",
+			}
+			for _, tt := range []struct {
+				name string
+				cond string
+				opt  tsdb.SeriesKeysOptions
+				exp  []string
+			}{
+				{name: "all", exp: all},
+				{name: "limit", opt: tsdb.SeriesKeysOptions{Limit: 2}, exp: all[:2]},
+				{name: "offset", opt: tsdb.SeriesKeysOptions{Offset: 3}, exp: all[3:]},
+				{name: "offset across measurements", opt: tsdb.SeriesKeysOptions{Offset: 3, Limit: 3}, exp: all[3:6]},
+				{name: "offset past end", opt: tsdb.SeriesKeysOptions{Offset: 10, Limit: 1}},
+				{name: "sources", cond: `_name = 'mem' OR _name =~ /^d/`, exp: all[4:]},
+				{name: "sources and tags", cond: `(_name = 'cpu' OR _name = 'mem') AND (host = 'serverB')`, opt: tsdb.SeriesKeysOptions{Offset: 1}, exp: []string{"mem,host=serverB"}},
+			} {
+				var cond cnosql.Expr
+				if tt.cond != "" {
+					cond = cnosql.MustParseExpr(tt.cond)
+				}
+				var got []string
+				if err := s.ForEachSeriesKey(query.OpenAuthorizer, []uint64{1, 2}, cond, tt.opt, func(key string) error {
+					got = append(got, key)
+					return nil
+				}); err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				} else if !reflect.DeepEqual(got, tt.exp) {
+					t.Fatalf("%s: unexpected keys: got %v, exp %v", tt.name, got, tt.exp)
+				}
+			}
+
+			// Unordered keys are passed as they are read, and the scan stops at
+			// the limit.
+			var got []string
+			if err := s.ForEachSeriesKey(query.OpenAuthorizer, []uint64{1, 2}, nil, tsdb.SeriesKeysOptions{Offset: 1, Limit: 5, Unordered: true}, func(key string) error {
+				got = append(got, key)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			} else if len(got) != 5 {
+				t.Fatalf("unexpected keys: %v", got)
+			}
+			sort.Strings(got)
+			for _, key := range got {
+				if i := sort.SearchStrings(all, key); i == len(all) || all[i] != key {
+					t.Fatalf("unexpected key: %s", key)
+				}
+			}
+
+			// The offset and limit apply to the series the user may read.
+			user := &meta.UserInfo{
+				Name: "reader",
+				MeasurementPrivileges: []meta.MeasurementPrivilege{
+					{Database: "db0", Measurement: "mem", Privilege: cnosql.ReadPrivilege},
+				},
+			}
+			got = nil
+			if err := s.ForEachSeriesKey(user, []uint64{1, 2}, nil, tsdb.SeriesKeysOptions{Offset: 1, Limit: 1}, func(key string) error {
+				got = append(got, key)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			} else if exp := []string{"mem,host=serverB"}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("unexpected keys: got %v, exp %v", got, exp)
+			}
+
+			// Errors of fn stop the iteration.
+			errStop := errors.New("stop")
+			n := 0
+			if err := s.ForEachSeriesKey(query.OpenAuthorizer, []uint64{1, 2}, nil, tsdb.SeriesKeysOptions{}, func(key string) error {
+				n++
+				return errStop
+			}); err != errStop {
+				t.Fatalf("unexpected error: %v", err)
+			} else if n != 1 {
+				t.Fatalf("unexpected calls: %d", n)
+			}
+		})
+	}
+}
+
+// Ensure a field written with different types in different shards is
+// returned with each of its types.
+func TestStore_FieldKeys(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA value=1,ok=true 0`,
+				`mem,host=serverA free=1i 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`cpu,host=serverA value=1i,desc="idle" 10`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 3,
+				`cpu,host=serverA value="n/a" 20`,
+			)
+
+			cpu := tsdb.FieldKeys{Measurement: "cpu", Keys: []tsdb.FieldKey{
+				{Key: "desc", Types: []cnosql.DataType{cnosql.String}},
+				{Key: "ok", Types: []cnosql.DataType{cnosql.Boolean}},
+				{Key: "value", Types: []cnosql.DataType{cnosql.Float, cnosql.Integer, cnosql.String}},
+			}}
+			mem := tsdb.FieldKeys{Measurement: "mem", Keys: []tsdb.FieldKey{
+				{Key: "free", Types: []cnosql.DataType{cnosql.Integer}},
+			}}
+			for _, tt := range []struct {
+				name     string
+				shardIDs []uint64
+				cond     string
+				exp      []tsdb.FieldKeys
+			}{
+				{name: "all", shardIDs: []uint64{1, 2, 3}, exp: []tsdb.FieldKeys{cpu, mem}},
+				{name: "measurement", shardIDs: []uint64{1, 2, 3}, cond: `_name = 'cpu'`, exp: []tsdb.FieldKeys{cpu}},
+				{name: "one shard", shardIDs: []uint64{2}, exp: []tsdb.FieldKeys{{Measurement: "cpu", Keys: []tsdb.FieldKey{
+					{Key: "desc", Types: []cnosql.DataType{cnosql.String}},
+					{Key: "value", Types: []cnosql.DataType{cnosql.Integer}},
+				}}}},
+				{name: "no shards"},
+			} {
+				var cond cnosql.Expr
+				if tt.cond != "" {
+					cond = cnosql.MustParseExpr(tt.cond)
+				}
+				keys, err := s.FieldKeys(query.OpenAuthorizer, tt.shardIDs, cond)
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				} else if !reflect.DeepEqual(keys, tt.exp) {
+					t.Fatalf("%s: unexpected keys: got %v, exp %v", tt.name, keys, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+// Ensure the field keys of a measurement are counted once whatever the
+// shards holding them, both exactly and estimated.
+func TestStore_FieldKeyCardinality(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA a=1,b=1 0`,
+				`mem,host=serverA free=1,used=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`cpu,host=serverA b=1,c=1 10`,
+				`cpu,host=serverB d=1 10`,
+				`disk,host=serverA used=1 10`,
+			)
+
+			// The user only holds a grant on cpu.
+			user := &meta.UserInfo{
+				Name: "reader",
+				MeasurementPrivileges: []meta.MeasurementPrivilege{
+					{Database: "db0", Measurement: "cpu", Privilege: cnosql.ReadPrivilege},
+				},
+			}
+
+			for _, exact := range []bool{true, false} {
+				counts, err := s.FieldKeyCardinality(query.OpenAuthorizer, []uint64{1, 2}, nil, exact)
+				if err != nil {
+					t.Fatal(err)
+				} else if exp := []tsdb.MeasurementFieldKeyN{{Measurement: "cpu", N: 4}, {Measurement: "disk", N: 1}, {Measurement: "mem", N: 2}}; !reflect.DeepEqual(counts, exp) {
+					t.Fatalf("exact=%v: unexpected counts: got %v, exp %v", exact, counts, exp)
+				}
+
+				counts, err = s.FieldKeyCardinality(query.OpenAuthorizer, []uint64{1, 2}, cnosql.MustParseExpr(`_name =~ /^(cpu|mem)$/`), exact)
+				if err != nil {
+					t.Fatal(err)
+				} else if exp := []tsdb.MeasurementFieldKeyN{{Measurement: "cpu", N: 4}, {Measurement: "mem", N: 2}}; !reflect.DeepEqual(counts, exp) {
+					t.Fatalf("exact=%v: unexpected counts: got %v, exp %v", exact, counts, exp)
+				}
+
+				counts, err = s.FieldKeyCardinality(user, []uint64{1, 2}, nil, exact)
+				if err != nil {
+					t.Fatal(err)
+				} else if exp := []tsdb.MeasurementFieldKeyN{{Measurement: "cpu", N: 4}}; !reflect.DeepEqual(counts, exp) {
+					t.Fatalf("exact=%v: unexpected counts: got %v, exp %v", exact, counts, exp)
+				}
+			}
+		})
+	}
+}
+
+// Ensure the tag values of a measurement are counted once whatever the shards
+// and tag keys holding them.
+func TestStore_TagValuesCardinality(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA,region=east value=1 0`,
+				`cpu,host=serverB,region=serverA value=1 0`,
+				`mem,host=serverA value=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`cpu,host=serverA,region=west value=1 10`,
+				`cpu,host=serverC,region=west value=1 10`,
+			)
+
+			for _, tt := range []struct {
+				name     string
+				shardIDs []uint64
+				key      string
+				cond     string
+				exp      []tsdb.MeasurementTagValueN
+			}{
+				{name: "all", shardIDs: []uint64{1, 2}, key: `_tagKey = 'host'`, exp: []tsdb.MeasurementTagValueN{{Measurement: "cpu", N: 3}, {Measurement: "mem", N: 1}}},
+				// serverA is a value of both keys.
+				{name: "keys", shardIDs: []uint64{1, 2}, key: `_tagKey = 'host' OR _tagKey = 'region'`, exp: []tsdb.MeasurementTagValueN{{Measurement: "cpu", N: 5}, {Measurement: "mem", N: 1}}},
+				{name: "condition", shardIDs: []uint64{1, 2}, key: `_tagKey = 'host'`, cond: `_name = 'cpu' AND region = 'west'`, exp: []tsdb.MeasurementTagValueN{{Measurement: "cpu", N: 2}}},
+			} {
+				var cond cnosql.Expr
+				if tt.cond != "" {
+					cond = cnosql.MustParseExpr(tt.cond)
+				}
+				counts, err := s.TagValuesCardinality(query.OpenAuthorizer, tt.shardIDs, cnosql.MustParseExpr(tt.key), cond)
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				} else if !reflect.DeepEqual(counts, tt.exp) {
+					t.Fatalf("%s: unexpected counts: got %v, exp %v", tt.name, counts, tt.exp)
+				}
+			}
+
+			if _, err := s.TagValuesCardinality(query.OpenAuthorizer, []uint64{1, 2}, nil, nil); err == nil {
+				t.Fatal("expected an error without a tag key condition")
+			}
+		})
+	}
+}
+
+// Ensure the measurements counted exactly are those matching the condition,
+// and that conditions the index cannot evaluate are rejected.
+func TestStore_MeasurementsCardinalityByExpr(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA,region=east value=1 0`,
+				`mem,host=serverB,region=west value=1 0`,
+				`disk,host=serverA value=1 0`,
+			)
+
+			for _, tt := range []struct {
+				cond string
+				exp  int64
+				err  string
+			}{
+				{exp: 3},
+				{cond: `_name = 'cpu' OR _name = 'mem'`, exp: 2},
+				{cond: `_name =~ /^(cpu|disk)$/ AND host = 'serverA'`, exp: 2},
+				{cond: `region != 'east'`, exp: 1},
+				{cond: `host = 'serverC'`, exp: 0},
+				{cond: `value::field = 'a'`, err: "fields are not indexed and cannot be used in this condition: value"},
+				{cond: `host = 1`, err: "condition cannot be evaluated against the index: host = 1"},
+			} {
+				var cond cnosql.Expr
+				if tt.cond != "" {
+					cond = cnosql.MustParseExpr(tt.cond)
+				}
+				n, err := s.MeasurementsCardinalityByExpr(query.OpenAuthorizer, "db0", "", cond)
+				if tt.err != "" {
+					if err == nil || err.Error() != tt.err {
+						t.Fatalf("%q: unexpected error: %v", tt.cond, err)
+					}
+				} else if err != nil {
+					t.Fatalf("%q: %v", tt.cond, err)
+				} else if n != tt.exp {
+					t.Fatalf("%q: unexpected count: got %d, exp %d", tt.cond, n, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+// Ensure the measurements of a retention policy are read from its shards only.
+func TestStore_MeasurementNames_RetentionPolicy(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA value=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp1", 2,
+				`mem,host=serverA value=1 0`,
+			)
+
+			for _, tt := range []struct {
+				rp  string
+				exp []string
+			}{
+				{exp: []string{"cpu", "mem"}},
+				{rp: "rp0", exp: []string{"cpu"}},
+				{rp: "rp1", exp: []string{"mem"}},
+				{rp: "rp2"},
+			} {
+				// The inmem shards of a database share one index, so
+				// their measurements can't be told apart.
+				if index == tsdb.InmemIndexName && tt.rp != "" && tt.exp != nil {
+					continue
+				}
+				a, err := s.MeasurementNames(query.OpenAuthorizer, "db0", tt.rp, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var names []string
+				for _, name := range a {
+					names = append(names, string(name))
+				}
+				if !reflect.DeepEqual(names, tt.exp) {
+					t.Fatalf("%q: unexpected names: got %v, exp %v", tt.rp, names, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+// Ensure a shard is hot while its WAL holds writes, cold once they are
+// snapshotted, and remote if it isn't open on this node.
+func TestStore_ShardState(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA value=1 0`,
+			)
+
+			if state := s.ShardState(1); state != tsdb.ShardStateHot {
+				t.Fatalf("unexpected state: %s", state)
+			} else if state := s.ShardState(2); state != tsdb.ShardStateRemote {
+				t.Fatalf("unexpected state: %s", state)
+			}
+
+			e, err := s.Shard(1).Engine()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := e.(interface{ WriteSnapshot() error }).WriteSnapshot(); err != nil {
+				t.Fatal(err)
+			}
+			if state := s.ShardState(1); state != tsdb.ShardStateCold {
+				t.Fatalf("unexpected state: %s", state)
+			}
+		})
+	}
+}
+
+// Ensure measurements are selected by the readable, undeleted series holding
+// a tag value.
+func TestStore_MeasurementNames_TagValue(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			s := MustOpenStore(t, index)
+			s.MustCreateShardWithData(t, "db0", "rp0", 1,
+				`cpu,host=serverA,service=api value=1 0`,
+				`cpu,host=serverB,service=db value=1 0`,
+				`mem,host=serverA,service=db value=1 0`,
+			)
+			s.MustCreateShardWithData(t, "db0", "rp0", 2,
+				`disk,host=serverA,service=api value=1 10`,
+				`net,host=serverA value=1 10`,
+			)
+
+			// The user only holds a grant on mem and disk.
+			user := &meta.UserInfo{
+				Name: "reader",
+				MeasurementPrivileges: []meta.MeasurementPrivilege{
+					{Database: "db0", Measurement: "mem", Privilege: cnosql.ReadPrivilege},
+					{Database: "db0", Measurement: "disk", Privilege: cnosql.ReadPrivilege},
+				},
+			}
+
+			names := func(auth query.FineAuthorizer, cond string) []string {
+				t.Helper()
+				a, err := s.MeasurementNames(auth, "db0", "", cnosql.MustParseExpr(cond))
+				if err != nil {
+					t.Fatalf("%s: %v", cond, err)
+				}
+				var names []string
+				for _, name := range a {
+					names = append(names, string(name))
+				}
+				return names
+			}
+
+			for _, tt := range []struct {
+				cond string
+				auth query.FineAuthorizer
+				exp  []string
+			}{
+				{cond: `service = 'api'`, exp: []string{"cpu", "disk"}},
+				{cond: `service = 'db'`, exp: []string{"cpu", "mem"}},
+				{cond: `service = 'web'`},
+				{cond: `region = 'us'`},
+				{cond: `service = 'api' AND _name = 'cpu'`, exp: []string{"cpu"}},
+				{cond: `service = 'api' OR service = 'db'`, exp: []string{"cpu", "disk", "mem"}},
+				{cond: `service = 'api'`, auth: user, exp: []string{"disk"}},
+				{cond: `service = 'db'`, auth: user, exp: []string{"mem"}},
+			} {
+				auth := tt.auth
+				if auth == nil {
+					auth = query.OpenAuthorizer
+				}
+				if got := names(auth, tt.cond); !reflect.DeepEqual(got, tt.exp) {
+					t.Fatalf("%s: unexpected names: got %v, exp %v", tt.cond, got, tt.exp)
+				}
+			}
+
+			// Measurements whose series with the value are dropped are not
+			// selected anymore.
+			if err := s.DeleteSeries("db0", nil, cnosql.MustParseExpr(`service = 'api' AND host = 'serverA'`)); err != nil {
+				t.Fatal(err)
+			} else if got := names(query.OpenAuthorizer, `service = 'api'`); len(got) != 0 {
+				t.Fatalf("unexpected names: %v", got)
+			} else if got, exp := names(query.OpenAuthorizer, `service = 'db'`), []string{"cpu", "mem"}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("unexpected names: got %v, exp %v", got, exp)
+			}
+		})
+	}
+}
+
+// Store is a test wrapper for tsdb.Store.
+type Store struct {
+	*tsdb.Store
+}
+
+// MustOpenStore returns a new, open store using the given index type in a
+// temporary directory. It is closed when the test finishes.
+func MustOpenStore(tb testing.TB, index string) *Store {
+	dir := tb.TempDir()
+	s := tsdb.NewStore(filepath.Join(dir, "data"))
+	s.EngineOptions.IndexVersion = index
+	s.EngineOptions.Config.WALDir = filepath.Join(dir, "wal")
+	if err := s.Open(); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { s.Close() })
+	return &Store{Store: s}
+}
+
+// MustCreateShardWithData creates a shard and writes the line protocol data
+// to it. The points stay in the cache of the shard.
+func (s *Store) MustCreateShardWithData(tb testing.TB, db, rp string, shardID uint64, data ...string) {
+	tb.Helper()
+	if err := s.CreateShard(db, rp, shardID, true); err != nil {
+		tb.Fatal(err)
+	}
+	s.MustWriteToShardString(tb, shardID, data...)
+}
+
+// MustWriteToShardString writes the line protocol data to a shard.
+func (s *Store) MustWriteToShardString(tb testing.TB, shardID uint64, data ...string) {
+	tb.Helper()
+	points, err := models.ParsePointsString(strings.Join(data, "\n"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := s.WriteToShard(shardID, points); err != nil {
+		tb.Fatal(err)
+	}
+}